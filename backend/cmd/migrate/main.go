@@ -0,0 +1,82 @@
+// Command migrate applies and inspects the database migrations embedded in the
+// binary, so deployments don't need the goose CLI or the migrations directory.
+//
+// Usage:
+//
+//	migrate [-db-url URL] <command> [version]
+//
+// Commands are up, up-by-one, up-to, down, down-to, redo, reset, status and
+// version. The database URL defaults to DB_URL; DB_MIN_SSLMODE, DB_SSL_PINS,
+// DB_IAM_REGION and DB_PASSWORD_FILE apply as they do to the server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/lordaris/resume_generator/pkg/database"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	// A missing .env file is fine; the environment may already be set
+	_ = godotenv.Load()
+
+	dbURL := flag.String("db-url", os.Getenv("DB_URL"), "PostgreSQL connection URL")
+	iamRegion := flag.String("iam-region", os.Getenv("DB_IAM_REGION"), "AWS region to authenticate with RDS IAM tokens in")
+	passwordFile := flag.String("password-file", os.Getenv("DB_PASSWORD_FILE"), "File to read the database password from")
+	timeout := flag.Duration("timeout", 10*time.Minute, "Time allowed for the command")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <%s> [version]\n", os.Args[0], strings.Join(database.MigrationCommands, "|"))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *dbURL == "" {
+		log.Fatal().Msg("DB_URL or -db-url is required")
+	}
+
+	db, err := database.NewPostgres(database.PostgresConfig{
+		URL:          *dbURL,
+		MinSSLMode:   os.Getenv("DB_MIN_SSLMODE"),
+		SSLPins:      splitList(os.Getenv("DB_SSL_PINS")),
+		IAMRegion:    *iamRegion,
+		PasswordFile: *passwordFile,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := database.Migrate(ctx, db.DB, flag.Arg(0), flag.Args()[1:]...); err != nil {
+		log.Error().Err(err).Msg("Migration failed")
+		os.Exit(1)
+	}
+}
+
+// splitList splits a comma-separated variable, dropping empty entries
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
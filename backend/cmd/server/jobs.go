@@ -0,0 +1,40 @@
+package main
+
+import (
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/service"
+)
+
+// setupJobs registers background job handlers and periodic maintenance jobs
+func setupJobs(worker *jobs.Worker, authService *service.AuthService, mailService *service.MailService, smsService *service.SMSService, dataExportService *service.DataExportService, userImportService *service.UserImportService, statusService *service.StatusService, digestService *service.DigestService, settingsService *service.SettingsService, cspReportService *service.CSPReportService, apiKeyService *service.APIKeyService) {
+	// Job handlers
+	worker.Register(service.JobTypeSendMail, mailService.HandleSendJob)
+	worker.Register(service.JobTypeSendSMS, smsService.HandleSendJob)
+	worker.Register(service.JobTypeGenerateDataExport, dataExportService.HandleGenerateJob)
+	worker.Register(service.JobTypeExpireDataExport, dataExportService.HandleExpireJob)
+	worker.Register(service.JobTypeImportUsers, userImportService.HandleImportJob)
+	worker.Register(service.JobTypeCleanupPasswordResets, authService.HandleCleanupPasswordResetsJob)
+	worker.Register(service.JobTypePurgeExpiredSessions, authService.HandlePurgeExpiredSessionsJob)
+	worker.Register(service.JobTypeCleanupEmailChanges, authService.HandleCleanupEmailChangesJob)
+	worker.Register(service.JobTypeStatusProbe, statusService.HandleProbeJob)
+	worker.Register(service.JobTypeSendWeeklyDigests, digestService.HandleSendDigestsJob)
+	worker.Register(service.JobTypeReloadSettings, settingsService.HandleReloadJob)
+	worker.Register(service.JobTypePruneCSPViolations, cspReportService.HandlePruneJob)
+	worker.Register(service.JobTypeFlushAPIKeyUsage, apiKeyService.HandleFlushUsageJob)
+
+	// Periodic maintenance
+	worker.Every(time.Hour, service.JobTypeCleanupPasswordResets)
+	worker.Every(time.Hour, service.JobTypePurgeExpiredSessions)
+	worker.Every(time.Hour, service.JobTypeCleanupEmailChanges)
+	worker.Every(24*time.Hour, service.JobTypePruneCSPViolations)
+	worker.Every(time.Minute, service.JobTypeStatusProbe)
+	worker.Every(time.Hour, service.JobTypeFlushAPIKeyUsage)
+
+	// Settings saved on another instance apply here within a minute
+	worker.Every(time.Minute, service.JobTypeReloadSettings)
+
+	// Digests are checked hourly and each user's is sent once it is a week old
+	worker.Every(time.Hour, service.JobTypeSendWeeklyDigests)
+}
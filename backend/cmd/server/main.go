@@ -2,24 +2,66 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Embed the time zone database so user time zones resolve on hosts without one
+	_ "time/tzdata"
 
+	"github.com/lordaris/resume_generator/internal/ai"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/handler"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/render"
+	"github.com/lordaris/resume_generator/internal/repository"
 	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/buildinfo"
+	"github.com/lordaris/resume_generator/pkg/cache"
 	"github.com/lordaris/resume_generator/pkg/config"
 	"github.com/lordaris/resume_generator/pkg/database"
-	"github.com/redis/go-redis/v9"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/lordaris/resume_generator/pkg/sms"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/lordaris/resume_generator/pkg/telemetry"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	migrate := flag.Bool("migrate", false, "Apply pending database migrations before serving")
+	selfcheck := flag.Bool("selfcheck", false, "Render a sample resume in every export format and exit")
+	flag.Parse()
+
 	// Configure zerolog
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr}).Hook(buildHook{})
+	// Code logging through log.Ctx outside a request falls back to the global logger
+	zerolog.DefaultContextLogger = &log.Logger
+
+	build := buildinfo.Get()
+	log.Info().
+		Str("version", build.Version).
+		Str("commit", build.Commit).
+		Str("build_time", build.BuildTime).
+		Str("go_version", build.GoVersion).
+		Msg("Starting resume generator")
+
+	// Check the renderers before anything else, so a broken export format
+	// stops a deploy rather than failing users' exports. -selfcheck runs
+	// only this, without configuration, for CI.
+	if err := render.SelfCheck(); err != nil {
+		log.Fatal().Err(err).Msg("Renderer self-check failed")
+	}
+	if *selfcheck {
+		log.Info().Int("formats", len(render.Formats)).Msg("Renderer self-check passed")
+		return
+	}
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -27,48 +69,260 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 
+	// Tracing, set up first so the database and Redis clients are traced
+	shutdownTracing, err := telemetry.Setup(context.Background(), telemetry.Config{
+		Endpoint:    cfg.TracingEndpoint,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+	if cfg.TracingEndpoint != "" {
+		log.Info().Str("endpoint", cfg.TracingEndpoint).Float64("sample_ratio", cfg.TracingSampleRatio).Msg("Exporting traces")
+	}
+
 	// Connect to database
-	db, err := database.NewPostgres(cfg.DBUrl)
+	db, err := database.NewPostgres(database.PostgresConfig{
+		URL:          cfg.DBUrl,
+		MinSSLMode:   cfg.DBMinSSLMode,
+		SSLPins:      cfg.DBSSLPins,
+		IAMRegion:    cfg.DBIAMRegion,
+		PasswordFile: cfg.DBPasswordFile,
+
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to database")
 	}
 	defer db.Close()
 
+	if *migrate {
+		if err := database.Migrate(context.Background(), db.DB, "up"); err != nil {
+			log.Fatal().Err(err).Msg("Failed to apply database migrations")
+		}
+	}
+
+	// Prepare queries once migrations have created what they refer to
+	if cfg.DBPreparedStatements {
+		if err := repository.PrepareQueries(context.Background(), db); err != nil {
+			log.Fatal().Err(err).Msg("Failed to prepare database queries")
+		}
+	}
+
 	// Connect to Redis
-	redisOptions, err := redis.ParseURL(cfg.RedisUrl)
+	redisConfig := database.RedisConfig{
+		URL:              cfg.RedisUrl,
+		Username:         cfg.RedisUsername,
+		Password:         cfg.RedisPassword,
+		SentinelPassword: cfg.RedisSentinelPassword,
+		TLS:              cfg.RedisTLS,
+		TLSCAFile:        cfg.RedisTLSCAFile,
+	}
+	switch cfg.RedisMode {
+	case config.RedisModeSentinel:
+		redisConfig.Addrs = cfg.RedisAddrs
+		redisConfig.SentinelMaster = cfg.RedisMasterName
+	case config.RedisModeCluster:
+		redisConfig.Addrs = cfg.RedisAddrs
+		redisConfig.Cluster = true
+	}
+	redisClient, err := database.NewRedisClient(redisConfig)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to parse Redis URL")
+		log.Fatal().Err(err).Msg("Failed to configure Redis client")
 	}
-	redisClient := redis.NewClient(redisOptions)
 	defer redisClient.Close()
+	telemetry.InstrumentRedis(redisClient, redisConfig.Address())
+	log.Info().Str("mode", cfg.RedisMode).Str("address", redisConfig.Address()).Msg("Using Redis")
 
-	// Ping Redis to check connection
+	// Ping Redis to check connection. When Redis is optional a monitor keeps
+	// pinging it, so the server starts without it and picks it up once it is
+	// reachable.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err := redisClient.Ping(ctx).Err(); err != nil {
+	var redisMonitor *database.RedisMonitor
+	if cfg.RedisOptional {
+		redisMonitor = database.NewRedisMonitor(redisClient, database.RedisMonitorConfig{})
+		if err := redisMonitor.Check(ctx); err != nil {
+			log.Warn().Msg("Starting without Redis, it will be retried in the background")
+		}
+		redisMonitor.Start(context.Background())
+		defer redisMonitor.Stop()
+	} else if err := redisClient.Ping(ctx).Err(); err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
 
 	// JWT configuration
+	jwtKeys, err := jwtSigningKeys(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load JWT keys")
+	}
 	jwtConfig := auth.JWTConfig{
-		Secret:             cfg.JWTSecret,
-		AccessTokenExpiry:  15 * time.Minute,
-		RefreshTokenExpiry: 7 * 24 * time.Hour, // 7 days
-		ResetTokenExpiry:   1 * time.Hour,
-		Issuer:             "resume_generator",
-		Audience:           "resume_generator_users",
+		Keys:                   jwtKeys,
+		AccessTokenExpiry:      cfg.JWTAccessTokenExpiry,
+		RefreshTokenExpiry:     cfg.JWTRefreshTokenExpiry,
+		ResetTokenExpiry:       cfg.JWTResetTokenExpiry,
+		EmailChangeTokenExpiry: cfg.JWTEmailChangeTokenExpiry,
+		MagicLinkTokenExpiry:   cfg.JWTMagicLinkTokenExpiry,
+		Issuer:                 "resume_generator",
+		Audience:               "resume_generator_users",
+		Leeway:                 cfg.JWTLeeway,
+		MinimalClaims:          cfg.JWTClaimsMode == config.JWTClaimsMinimal,
 	}
 
+	// Mail delivery
+	var mailSender mail.Sender
+	if cfg.SMTPHost != "" {
+		mailSender = mail.NewSMTPSender(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		})
+	} else {
+		log.Warn().Msg("SMTP_HOST not set, emails will be logged instead of sent")
+		mailSender = mail.NewLogSender()
+	}
+
+	// Text message delivery
+	var smsSender sms.Sender
+	if cfg.TwilioAccountSID != "" {
+		smsSender = sms.NewTwilioSender(sms.TwilioConfig{
+			AccountSID: cfg.TwilioAccountSID,
+			AuthToken:  cfg.TwilioAuthToken,
+			From:       cfg.TwilioFrom,
+		})
+	} else {
+		log.Warn().Msg("TWILIO_ACCOUNT_SID not set, text messages will be logged instead of sent")
+		smsSender = sms.NewLogSender()
+	}
+
+	// File storage for uploads such as profile photos, data exports and
+	// import rosters
+	var fileStore storage.Storage
+	// Local files are downloaded from this API with signed URLs
+	var fileSigner *storage.URLSigner
+	if cfg.StorageBackend == config.StorageBackendS3 {
+		s3Store, err := storage.NewS3(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3ForcePathStyle,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to configure S3 storage")
+		}
+		fileStore = s3Store
+	} else {
+		localStore, err := storage.NewLocal(cfg.StorageLocalDir)
+		if err != nil {
+			log.Fatal().Err(err).Str("dir", cfg.StorageLocalDir).Msg("Failed to create local storage")
+		}
+		secret := []byte(cfg.StorageURLSecret)
+		if len(secret) == 0 {
+			log.Warn().Msg("STORAGE_URL_SECRET not set, file download links only work on this instance until it restarts")
+			secret = make([]byte, 32)
+			rand.Read(secret)
+		}
+		fileSigner = storage.NewURLSigner(cfg.PublicBaseURL+"/api/v1/files", secret)
+		localStore.SetURLSigner(fileSigner)
+		fileStore = localStore
+	}
+
+	// Background job worker
+	jobQueue := jobs.NewQueue(jobs.QueueConfig{Redis: redisClient})
+	worker := jobs.NewWorker(jobQueue, jobs.WorkerConfig{})
+	if redisMonitor != nil {
+		worker.SetAvailability(redisMonitor.Available)
+	}
+
+	// Cache backend. appCache holds copies of data that can be loaded again,
+	// so in memory it evicts them to make room; stateStore holds codes,
+	// ceremonies and counters that must last until they expire.
+	var appCache, stateStore cache.Cache = cache.NewRedis(redisClient), cache.NewRedis(redisClient)
+	if cfg.CacheDriver == config.CacheDriverMemory {
+		evictingCache, err := cache.NewRistretto(cache.RistrettoConfig{})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create in-memory cache")
+		}
+		defer evictingCache.Close()
+		appCache = evictingCache
+
+		memoryStore, err := cache.NewMemory(cache.MemoryConfig{})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create in-memory state store")
+		}
+		defer memoryStore.Close()
+		stateStore = memoryStore
+	}
+
+	// OAuth providers users can sign in with
+	var oauthProviders []*auth.OAuthProvider
+	if cfg.GoogleClientID != "" {
+		oauthProviders = append(oauthProviders, auth.NewGoogleProvider(auth.OAuthConfig{
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			RedirectURL:  cfg.OAuthRedirectBaseURL + handler.OAuthCallbackPath(auth.ProviderGoogle),
+		}))
+	}
+	if cfg.GitHubClientID != "" {
+		oauthProviders = append(oauthProviders, auth.NewGitHubProvider(auth.OAuthConfig{
+			ClientID:     cfg.GitHubClientID,
+			ClientSecret: cfg.GitHubClientSecret,
+			RedirectURL:  cfg.OAuthRedirectBaseURL + handler.OAuthCallbackPath(auth.ProviderGitHub),
+		}))
+	}
+
+	// Language model for AI-assisted suggestions; they are off without one
+	var aiProvider ai.Provider
+	if cfg.AIProvider == config.AIProviderOpenAI {
+		aiProvider = ai.NewOpenAIProvider(ai.OpenAIConfig{
+			BaseURL:    cfg.AIBaseURL,
+			APIKey:     cfg.AIAPIKey,
+			Model:      cfg.AIModel,
+			HTTPClient: &http.Client{Timeout: cfg.AITimeout},
+		})
+		log.Info().Str("model", cfg.AIModel).Msg("AI suggestions enabled")
+	}
+
+	log.Info().Str("storage", cfg.ResumeStorage).Msg("Using resume storage mode")
+	log.Info().Str("driver", cfg.CacheDriver).Msg("Using cache driver")
+	log.Info().Str("backend", cfg.StorageBackend).Msg("Using file storage backend")
+
+	// Client addresses come from the forwarding headers of trusted proxies only
+	if len(cfg.TrustedProxies) > 0 {
+		proxies, err := security.ParseTrustedProxies(cfg.TrustedProxies)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to parse trusted proxies")
+		}
+		security.SetTrustedProxies(proxies)
+	} else {
+		log.Warn().Msg("TRUSTED_PROXIES not set, client addresses are taken from X-Forwarded-For as sent")
+	}
+
+	domain.SetEmailPlusTagFolding(cfg.EmailFoldPlusTags)
+
+	handler.DefaultJSONLimits.MaxBytes = cfg.MaxRequestBodyBytes
+
 	// Setup router
-	router := setupRoutes(db, redisClient, jwtConfig)
+	router := setupRoutes(db, redisClient, redisMonitor, appCache, stateStore, jwtConfig, worker, mailSender, smsSender, fileStore, fileSigner, cfg, oauthProviders, aiProvider)
+
+	// Start the worker after all job handlers are registered
+	worker.Start(context.Background())
 
 	// Create server
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  cfg.ServerReadTimeout,
+		WriteTimeout: cfg.ServerWriteTimeout,
+		IdleTimeout:  cfg.ServerIdleTimeout,
 	}
 
 	// Start server in a goroutine
@@ -93,5 +347,59 @@ func main() {
 		log.Fatal().Err(err).Msg("Server forced to shutdown")
 	}
 
+	// Let in-flight jobs finish
+	worker.Stop()
+
+	// Flush the spans of the last requests
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to flush traces")
+	}
+
 	log.Info().Msg("Server exited properly")
 }
+
+// buildHook tags error logs with the build so they can be matched to a release
+type buildHook struct{}
+
+func (buildHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level >= zerolog.ErrorLevel && level <= zerolog.PanicLevel {
+		e.Str("build", buildinfo.ShortCommit())
+	}
+}
+
+// jwtSigningKeys loads the configured JWT keys, the signing key first
+func jwtSigningKeys(cfg *config.Config) ([]auth.SigningKey, error) {
+	var keys []auth.SigningKey
+	if cfg.JWTPrivateKeyFile != "" {
+		data, err := os.ReadFile(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := auth.ParsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.JWTPrivateKeyFile, err)
+		}
+		keys = append(keys, key)
+	}
+	// Without a private key the secret signs; with one it only verifies
+	// tokens from before the switch
+	if cfg.JWTSecret != "" {
+		keys = append(keys, auth.NewHMACKey([]byte(cfg.JWTSecret)))
+	}
+	for _, secret := range cfg.JWTPreviousSecrets {
+		keys = append(keys, auth.NewHMACKey([]byte(secret)))
+	}
+	for _, file := range cfg.JWTPublicKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		key, err := auth.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -1,94 +1,1441 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/ai"
+	"github.com/lordaris/resume_generator/internal/analysis"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/connector"
+	"github.com/lordaris/resume_generator/internal/domain"
 	"github.com/lordaris/resume_generator/internal/handler"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/parser"
+	"github.com/lordaris/resume_generator/internal/proofread"
 	"github.com/lordaris/resume_generator/internal/repository"
 	"github.com/lordaris/resume_generator/internal/service"
 	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/buildinfo"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/config"
+	"github.com/lordaris/resume_generator/pkg/database"
+	"github.com/lordaris/resume_generator/pkg/health"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/lordaris/resume_generator/pkg/openapi"
 	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/lordaris/resume_generator/pkg/sms"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/lordaris/resume_generator/pkg/telemetry"
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 )
 
-// setupRoutes configures and returns the router with all routes
-func setupRoutes(db *sqlx.DB, redisClient *redis.Client, jwtConfig auth.JWTConfig) http.Handler {
-	corsMiddleware := security.CORSMiddleware(security.DefaultCORSConfig())
-	// Create router
+// requestTimeout bounds the database work done for a single request. It stays
+// below the server's WriteTimeout so the client still receives the error.
+const requestTimeout = 10 * time.Second
+
+// apiTitle names the API in its OpenAPI document
+const apiTitle = "Resume Generator API"
+
+// pageQuery documents the query parameters accepted by paginated list
+// endpoints, which sort by created_at or one of sortFields
+func pageQuery(sortFields ...string) []openapi.Param {
+	sorts := append([]string{domain.SortByCreatedAt}, sortFields...)
+	return []openapi.Param{
+		{Name: "page", Type: "integer", Description: "Page number, starting at 1"},
+		{Name: "limit", Type: "integer", Description: "Page size, at most 100"},
+		{Name: "sort", Description: "Sort field: " + strings.Join(sorts, ", ")},
+		{Name: "order", Description: "asc or desc, defaulting to desc"},
+		{Name: "created_after", Format: "date-time"},
+		{Name: "created_before", Format: "date-time"},
+		{Name: "q", Description: "Search text"},
+	}
+}
+
+// rateLimitPolicy returns the policy with its limit replaced by the configured
+// requests per minute, or unchanged when none is configured
+func rateLimitPolicy(policy security.RateLimitPolicy, perMinute int) security.RateLimitPolicy {
+	if perMinute > 0 {
+		policy.Limit = perMinute
+		policy.Interval = time.Minute
+	}
+	return policy
+}
+
+// setupRoutes configures and returns the router with all routes, registering background jobs on the worker
+func setupRoutes(db *sqlx.DB, redisClient redis.UniversalClient, redisMonitor *database.RedisMonitor, appCache, stateStore cache.Cache, jwtConfig auth.JWTConfig, worker *jobs.Worker, mailSender mail.Sender, smsSender sms.Sender, fileStore storage.Storage, fileSigner *storage.URLSigner, cfg *config.Config, oauthProviders []*auth.OAuthProvider, aiProvider ai.Provider) http.Handler {
+	corsConfig := security.DefaultCORSConfig()
+	corsConfig.AllowedOrigins = cfg.CORSAllowedOrigins
+	corsMiddleware := security.CORSMiddleware(corsConfig)
+	// Create router; routes are registered through the registry so every one
+	// of them is described in the OpenAPI document
 	mux := http.NewServeMux()
+	api := openapi.NewRegistry(mux, openapi.Config{
+		Title:            apiTitle,
+		Version:          buildinfo.Get().Version,
+		ErrorResponse:    apperror.Problem{},
+		ErrorContentType: apperror.ContentType,
+		DefaultErrors:    []int{http.StatusInternalServerError},
+	})
 
 	// Create repositories
 	userRepo := repository.NewPostgresUserRepository(db)
-	resumeRepo := repository.NewPostgresResumeRepository(db)
+	resumeEventRepo := repository.NewPostgresResumeEventRepository(db)
+	incidentRepo := repository.NewPostgresIncidentRepository(db)
+	shareRepo := repository.NewPostgresResumeShareRepository(db)
+	settingRepo := repository.NewPostgresInstanceSettingRepository(db)
+	cspViolationRepo := repository.NewPostgresCSPViolationRepository(db)
+	identityRepo := repository.NewPostgresUserIdentityRepository(db)
+	noteRepo := repository.NewPostgresResumeNoteRepository(db)
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(db)
+	roleProfileRepo := repository.NewPostgresRoleProfileRepository(db)
+	userRoleRepo := repository.NewPostgresUserRoleRepository(db)
+	provenanceRepo := repository.NewPostgresProvenanceRepository(db)
+	connectorRepo := repository.NewPostgresConnectorRepository(db)
+	publicationRepo := repository.NewPostgresResumePublicationRepository(db)
+	serviceAccountRepo := repository.NewPostgresServiceAccountRepository(db)
+	activityRepo := repository.NewPostgresResumeActivityRepository(db)
+	phoneRepo := repository.NewPostgresUserPhoneRepository(db)
+	passkeyRepo := repository.NewPostgresPasskeyRepository(db)
+	emailConflictRepo := repository.NewPostgresUserEmailConflictRepository(db)
+	// Repository calls made inside txManager.WithinTx share its transaction
+	txManager := repository.NewTxManager(db)
+	var resumeRepo domain.ResumeRepository = repository.NewPostgresResumeRepository(db)
+	if cfg.ResumeStorage == config.ResumeStorageDocument {
+		resumeRepo = repository.NewPostgresResumeDocumentRepository(db)
+	}
+	resumeRepo = repository.NewCachedResumeRepository(resumeRepo, appCache, repository.CachedResumeRepositoryConfig{})
+	resumeRepo = service.NewLimitedResumeRepository(resumeRepo, service.ResumeLimitsConfig{
+		MaxEducation:           cfg.ResumeLimits.MaxEducation,
+		MaxExperience:          cfg.ResumeLimits.MaxExperience,
+		MaxSkills:              cfg.ResumeLimits.MaxSkills,
+		MaxProjects:            cfg.ResumeLimits.MaxProjects,
+		MaxCertifications:      cfg.ResumeLimits.MaxCertifications,
+		MaxProjectTechnologies: cfg.ResumeLimits.MaxProjectTechnologies,
+	})
+
+	// Dependency health checks, with short timeouts so readiness probes answer
+	// well within orchestrator probe deadlines
+	healthChecker := health.NewChecker(health.Config{Timeout: time.Second})
+	healthChecker.Register("database", db.PingContext)
+	pingRedis := func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	}
+	if redisMonitor != nil {
+		// The server keeps serving without Redis, so losing it only degrades
+		healthChecker.RegisterOptional("redis", pingRedis)
+	} else {
+		healthChecker.Register("redis", pingRedis)
+	}
 
 	// Create JWT handler
 	jwtHandler := auth.NewJWT(jwtConfig)
 
 	// Create services
 	authServiceConfig := service.AuthServiceConfig{
-		AccessTokenExpiry:  jwtConfig.AccessTokenExpiry,
-		RefreshTokenExpiry: jwtConfig.RefreshTokenExpiry,
-		ResetTokenExpiry:   jwtConfig.ResetTokenExpiry,
+		AccessTokenExpiry:      jwtConfig.AccessTokenExpiry,
+		RefreshTokenExpiry:     jwtConfig.RefreshTokenExpiry,
+		ResetTokenExpiry:       jwtConfig.ResetTokenExpiry,
+		EmailChangeTokenExpiry: jwtConfig.EmailChangeTokenExpiry,
+		SessionIdleTimeout:     cfg.SessionIdleTimeout,
+		SessionMaxLifetime:     cfg.SessionMaxLifetime,
 	}
+	mailService := service.NewMailService(worker.Queue(), mailSender)
 	authService := service.NewAuthService(userRepo, jwtHandler, authServiceConfig)
+	authService.SetMailService(mailService)
+	authService.SetClaimsCache(appCache)
+	smsService := service.NewSMSService(worker.Queue(), smsSender)
+	phoneService := service.NewPhoneService(phoneRepo, smsService, stateStore, service.PhoneServiceConfig{})
+	emailConflictService := service.NewEmailConflictService(emailConflictRepo, userRepo)
+	authService.SetPhoneService(phoneService)
+	// Passkeys are off unless the domain they are bound to is configured
+	var passkeyService *service.PasskeyService
+	if cfg.WebAuthnRPID != "" {
+		var err error
+		passkeyService, err = service.NewPasskeyService(passkeyRepo, userRepo, authService, stateStore, service.PasskeyServiceConfig{
+			RPID:          cfg.WebAuthnRPID,
+			RPDisplayName: cfg.WebAuthnRPName,
+			RPOrigins:     cfg.WebAuthnRPOrigins,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to set up passkeys, passkey sign-in is disabled")
+		} else {
+			passkeyService.SetTransactor(txManager)
+			authService.SetPasskeyService(passkeyService)
+		}
+	}
+	denylist := auth.NewDenylist(redisClient, jwtHandler.Leeway())
+	if redisMonitor != nil {
+		denylist.SetAvailability(redisMonitor.Available)
+	}
+	authService.SetDenylist(denylist)
+	oauthService := service.NewOAuthService(oauthProviders, identityRepo, userRepo, authService, stateStore, service.OAuthServiceConfig{})
+	resumeEventService := service.NewResumeEventService(resumeEventRepo, service.ResumeEventServiceConfig{})
+	dataExportService := service.NewDataExportService(userRepo, resumeRepo, stateStore, fileStore, worker.Queue(), mailService, service.DataExportServiceConfig{})
+	dataExportService.SetRepositories(service.DataExportRepositories{
+		Events:     resumeEventRepo,
+		Activity:   activityRepo,
+		Notes:      noteRepo,
+		Identities: identityRepo,
+		Phones:     phoneRepo,
+		Passkeys:   passkeyRepo,
+		APIKeys:    apiKeyRepo,
+	})
+	userImportService := service.NewUserImportService(userRepo, resumeRepo, stateStore, fileStore, worker.Queue(), mailService, service.UserImportServiceConfig{})
+	userImportService.SetTransactor(txManager)
+	statusService := service.NewStatusService(healthChecker, incidentRepo, redisClient, service.StatusServiceConfig{})
+	digestService := service.NewDigestService(userRepo, resumeRepo, resumeEventRepo, mailService, service.DigestServiceConfig{})
+	cspReportService := service.NewCSPReportService(cspViolationRepo, service.CSPReportServiceConfig{})
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, service.APIKeyServiceConfig{})
+	apiKeyService.SetUsageStore(apiKeyRepo, stateStore)
+	serviceAccountService := service.NewServiceAccountService(serviceAccountRepo, service.ServiceAccountServiceConfig{})
+	permissionService := service.NewPermissionService(userRoleRepo, userRepo, appCache, service.PermissionServiceConfig{})
+	serviceAccountService.SetTransactor(txManager)
+
+	// Security headers start strict and switch to the stored profile once it loads
+	securityHeaders := security.NewHeadersSwitch(security.DefaultHeadersSettings().Config(handler.CSPReportPath))
+	settingsService := service.NewSettingsService(settingRepo, securityHeaders, service.SettingsServiceConfig{CSPReportURI: handler.CSPReportPath})
+	if err := settingsService.Reload(context.Background()); err != nil {
+		log.Error().Err(err).Msg("Failed to load instance settings, using defaults")
+	}
+
+	// Register background jobs
+	setupJobs(worker, authService, mailService, smsService, dataExportService, userImportService, statusService, digestService, settingsService, cspReportService, apiKeyService)
 
 	// Create middleware
-	authMiddleware := handler.NewAuthMiddleware(authService)
-	sessionLogger := handler.NewSessionLogger()
+	authMiddleware := handler.NewAuthMiddleware(authService, apiKeyService)
+	authMiddleware.SetServiceAccountService(serviceAccountService)
+	authMiddleware.SetPermissionService(permissionService)
+	strictPolicy := rateLimitPolicy(security.StrictRateLimit, cfg.RateLimits.Strict)
+	strictPolicy.FailClosed = cfg.RateLimitFailClosed
+	defaultPolicy := rateLimitPolicy(security.DefaultRateLimitPolicy, cfg.RateLimits.Default)
+	lenientPolicy := rateLimitPolicy(security.LenientRateLimit, cfg.RateLimits.Lenient)
+	authMiddleware.SetRateLimiters(
+		handler.NewUserRateLimiter(lenientPolicy, redisClient),
+		handler.NewUserRateLimiter(defaultPolicy, redisClient),
+	)
+	sessionLogger := handler.NewSessionLogger(handler.SessionLoggerConfig{
+		BodySampleRate:       cfg.LogBodySampleRate,
+		SlowRequestThreshold: cfg.SlowRequestThreshold,
+	})
 
 	// Create handlers
 	authHandler := handler.NewAuthHandler(authService, redisClient)
+	authHandler.SetRateLimiter(handler.NewUserRateLimiter(defaultPolicy, redisClient))
+	authHandler.SetStrictLimiter(handler.NewUserRateLimiter(strictPolicy, redisClient))
+	// Reset deliveries are limited per channel; texts cost money, so their
+	// limit fails closed like the strict one
+	smsPolicy := handler.ResetSMSRateLimit
+	smsPolicy.FailClosed = cfg.RateLimitFailClosed
+	authHandler.SetChannelLimiter(service.ResetChannelSMS, handler.NewUserRateLimiter(smsPolicy, redisClient))
+	phoneHandler := handler.NewPhoneHandler(phoneService, redisClient)
+	phoneHandler.SetSMSLimiter(handler.NewUserRateLimiter(smsPolicy, redisClient))
+	emailConflictHandler := handler.NewEmailConflictHandler(emailConflictService, redisClient)
+	emailConflictHandler.SetLimiter(handler.NewUserRateLimiter(strictPolicy, redisClient))
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	oauthHandler.SetSecureCookies(cfg.CookieSecure)
 	userHandler := handler.NewUserHandler(userRepo, resumeRepo)
-	resumeHandler := handler.NewResumeHandler(resumeRepo)
-	adminHandler := handler.NewAdminHandler(userRepo)
+	// Handlers record what happens to resumes in their activity feeds
+	activityService := service.NewResumeActivityService(activityRepo, resumeEventRepo, service.ResumeActivityServiceConfig{})
+	activityHandler := handler.NewResumeActivityHandler(activityService)
+	feedHandler := handler.NewResumeFeedHandler(service.NewResumeFeedService(resumeEventRepo, resumeRepo, service.ResumeFeedServiceConfig{}))
+	resumeHandler := handler.NewResumeHandler(resumeRepo, resumeEventService)
+	resumeHandler.SetActivityService(activityService)
+	resumeHandler.SetUserRepository(userRepo)
+	resumeHandler.SetPermissionService(permissionService)
+	resumeHandler.SetExportService(service.NewResumeExportService(fileStore, appCache, service.ResumeExportServiceConfig{}))
+	resumeDeletionService := service.NewResumeDeletionService(resumeRepo, stateStore, service.ResumeDataRepositories{
+		Shares:       shareRepo,
+		Notes:        noteRepo,
+		Publications: publicationRepo,
+		Activity:     activityRepo,
+		Provenance:   provenanceRepo,
+		Connectors:   connectorRepo,
+	})
+	resumeDeletionService.SetTransactor(txManager)
+	resumeHandler.SetDeletionService(resumeDeletionService)
+	translationService := service.NewResumeTranslationService(resumeRepo)
+	translationService.SetTransactor(txManager)
+	translationHandler := handler.NewResumeTranslationHandler(translationService, resumeEventService)
+	shareHandler := handler.NewResumeShareHandler(service.NewResumeShareService(shareRepo, resumeRepo, stateStore, service.ResumeShareServiceConfig{}), redisClient)
+	shareHandler.SetSecureCookies(cfg.CookieSecure)
+	shareHandler.SetActivityService(activityService)
+	publicationHandler := handler.NewResumePublicationHandler(service.NewResumePublicationService(publicationRepo, resumeRepo), cfg.PublicBaseURL)
+	publicationHandler.SetActivityService(activityService)
+	photoHandler := handler.NewPhotoHandler(service.NewPhotoService(resumeRepo, fileStore, service.PhotoServiceConfig{BaseURL: cfg.PublicBaseURL}), resumeEventService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	serviceAccountHandler := handler.NewServiceAccountHandler(serviceAccountService)
+	noteHandler := handler.NewResumeNoteHandler(service.NewResumeNoteService(noteRepo, service.ResumeNoteServiceConfig{}))
+	noteHandler.SetActivityService(activityService)
+	adminHandler := handler.NewAdminHandler(userRepo, authService)
+	userRoleHandler := handler.NewUserRoleHandler(permissionService, authService)
+	statsRepo := repository.NewPostgresStatsRepository(db, cfg.ResumeStorage == config.ResumeStorageDocument)
+	statsHandler := handler.NewStatsHandler(service.NewStatsService(statsRepo, service.StatsServiceConfig{}))
+	dataExportHandler := handler.NewDataExportHandler(dataExportService)
+	userImportHandler := handler.NewUserImportHandler(userImportService)
+	statusHandler := handler.NewStatusHandler(statusService, incidentRepo)
+	healthHandler := handler.NewHealthHandler(healthChecker)
+	settingsHandler := handler.NewSettingsHandler(settingsService)
+	cspReportHandler := handler.NewCSPReportHandler(cspReportService, redisClient)
+	eventsHandler := handler.NewEventsHandler(worker.Queue())
+	jobHandler := handler.NewJobHandler(worker.Queue())
+	roleProfileHandler := handler.NewRoleProfileHandler(service.NewRoleProfileService(roleProfileRepo), resumeRepo)
+	entryImportService := service.NewEntryImportService(resumeRepo, provenanceRepo)
+	entryImportService.SetTransactor(txManager)
+	entryImportHandler := handler.NewEntryImportHandler(entryImportService, resumeEventService)
+	connectorService := service.NewConnectorService(connectorRepo, resumeRepo, entryImportService, map[string]connector.Source{
+		domain.ConnectorGitHub: connector.NewGitHub(connector.GitHubConfig{Token: cfg.ConnectorGitHubToken}),
+		domain.ConnectorCredly: connector.NewCredly(connector.CredlyConfig{}),
+	})
+	connectorHandler := handler.NewConnectorHandler(connectorService, resumeEventService)
+	connectorHandler.SetPermissionService(permissionService)
+	var checker proofread.Checker = proofread.NewBasic()
+	if cfg.LanguageToolURL != "" {
+		checker = proofread.NewLanguageTool(proofread.LanguageToolConfig{
+			BaseURL:  cfg.LanguageToolURL,
+			Username: cfg.LanguageToolUsername,
+			APIKey:   cfg.LanguageToolAPIKey,
+		})
+	}
+	proofreadHandler := handler.NewProofreadHandler(resumeRepo, checker)
+	resumeSendService := service.NewResumeSendService(mailService, stateStore, service.ResumeSendServiceConfig{DailyLimit: cfg.ResumeSendDailyLimit})
+	resumeSendHandler := handler.NewResumeSendHandler(resumeRepo, resumeSendService)
+	resumeSendHandler.SetActivityService(activityService)
 
 	// Public routes
-	mux.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
-		handler.RespondWithJSON(w, http.StatusOK, map[string]any{
-			"status": "healthy",
-			"time":   time.Now().Format(time.RFC3339),
+	api.HandleFunc("GET /healthz", healthHandler.LivenessHandler, openapi.Route{
+		Summary:  "Report that the process is alive",
+		Tags:     []string{"health"},
+		Response: handler.LivenessResponse{},
+	})
+	api.HandleFunc("GET /readyz", healthHandler.ReadinessHandler, openapi.Route{
+		Summary:      "Report whether Postgres and Redis are reachable",
+		Tags:         []string{"health"},
+		Response:     health.Report{},
+		Alternatives: []openapi.Result{{Status: http.StatusServiceUnavailable, Description: "A dependency is down", Body: health.Report{}}},
+	})
+	api.Handle("GET /status", handler.HandlerFunc(statusHandler.GetStatusHandler), openapi.Route{
+		Summary:  "Get service status, uptime history and incidents",
+		Tags:     []string{"status"},
+		Response: service.StatusPage{},
+		Errors:   []int{http.StatusServiceUnavailable},
+	})
+	// The metrics token is checked by the handler, so other requests pass through
+	metricsHandler := handler.NewMetricsHandler(cfg.MetricsToken, db.Stats)
+	api.Handle("GET /metrics", authMiddleware.ServiceAccountOr(domain.ScopeMetricsRead, func(next http.Handler) http.Handler { return next })(handler.HandlerFunc(metricsHandler.GetMetricsHandler)), openapi.Route{
+		Summary:     "Get database pool metrics in the Prometheus text format; needs METRICS_TOKEN or a service account key with the metrics:read scope as a bearer token",
+		Tags:        []string{"health"},
+		ContentType: handler.MetricsContentType,
+		Errors:      []int{http.StatusUnauthorized, http.StatusForbidden},
+	})
+	api.Handle("POST "+handler.CSPReportPath, handler.HandlerFunc(cspReportHandler.CreateReportHandler), openapi.Route{
+		Summary: "Report a Content-Security-Policy violation; sent by browsers",
+		Tags:    []string{"meta"},
+		Status:  http.StatusNoContent,
+		Errors:  []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusTooManyRequests},
+	})
+	api.HandleFunc("GET /api/v1/meta/version", handler.GetVersionHandler, openapi.Route{
+		Summary:  "Get the running build version",
+		Tags:     []string{"meta"},
+		Response: buildinfo.Info{},
+	})
+	api.HandleFunc("GET /.well-known/jwks.json", handler.JWKSHandler(jwtHandler), openapi.Route{
+		Summary:  "Get the public keys access tokens are signed with",
+		Tags:     []string{"meta"},
+		Response: auth.JSONWebKeySet{},
+	})
+	api.Handle("GET /api/v1/openapi.json", api.SpecHandler(), openapi.Route{
+		Summary:  "Get this OpenAPI document",
+		Tags:     []string{"meta"},
+		Response: openapi.Document{},
+	})
+	api.Handle("GET /api/v1/docs", openapi.SwaggerUIHandler(apiTitle, "/api/v1/openapi.json"), openapi.Route{
+		Summary:     "Browse this document in Swagger UI",
+		Tags:        []string{"meta"},
+		ContentType: "text/html",
+	})
+	api.Handle("POST /api/v1/register", handler.HandlerFunc(authHandler.RegisterHandler), openapi.Route{
+		Summary:  "Register a new user",
+		Tags:     []string{"auth"},
+		Request:  handler.RegisterRequest{},
+		Status:   http.StatusCreated,
+		Response: handler.RegisterResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict},
+	})
+	api.Handle("POST /api/v1/login", handler.HandlerFunc(authHandler.LoginHandler), openapi.Route{
+		Summary:     "Log in with email and password",
+		Description: "Accounts that require a passkey answer 202 with a passkey challenge instead of tokens; the login is finished at /api/v1/passkeys/login/finish.",
+		Tags:        []string{"auth"},
+		Request:     handler.LoginRequest{},
+		Response:    service.TokenPair{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusServiceUnavailable},
+	})
+	// Magic links are off unless the page they open is configured
+	if cfg.MagicLinkURL != "" {
+		magicLinkService := service.NewMagicLinkService(userRepo, authService, mailService, stateStore, service.MagicLinkServiceConfig{
+			LinkURL: cfg.MagicLinkURL,
+			LinkTTL: jwtConfig.MagicLinkTokenExpiry,
+		})
+		magicLinkHandler := handler.NewMagicLinkHandler(magicLinkService, redisClient)
+		magicLinkHandler.SetLoginLimiter(handler.NewUserRateLimiter(strictPolicy, redisClient))
+		magicLinkHandler.SetSecureCookies(cfg.CookieSecure)
+		api.Handle("POST /api/v1/login/magic-link", handler.HandlerFunc(magicLinkHandler.RequestMagicLinkHandler), openapi.Route{
+			Summary:     "Email a sign-in link",
+			Description: "Answers 202 whether or not the email has an account. The link works once, until it expires, from the browser that asked for it, which a cookie identifies.",
+			Tags:        []string{"auth"},
+			Request:     handler.MagicLinkRequest{},
+			Status:      http.StatusAccepted,
+			Response:    handler.MessageResponse{},
+			Errors:      []int{http.StatusBadRequest},
+		})
+		api.Handle("POST /api/v1/login/magic-link/verify", handler.HandlerFunc(magicLinkHandler.VerifyMagicLinkHandler), openapi.Route{
+			Summary:     "Sign in with the token of an emailed link",
+			Description: "Accounts that require a passkey answer 202 with a passkey challenge instead of tokens; the login is finished at /api/v1/passkeys/login/finish.",
+			Tags:        []string{"auth"},
+			Request:     handler.VerifyMagicLinkRequest{},
+			Response:    service.TokenPair{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable},
+		})
+	}
+	if passkeyService != nil {
+		passkeyHandler := handler.NewPasskeyHandler(passkeyService, redisClient)
+		passkeyHandler.SetLoginLimiter(handler.NewUserRateLimiter(strictPolicy, redisClient))
+		api.Handle("POST /api/v1/passkeys/login/begin", handler.HandlerFunc(passkeyHandler.BeginLoginHandler), openapi.Route{
+			Summary:     "Start signing in with a passkey",
+			Description: "The options are passed to navigator.credentials.get; any passkey registered for the site can answer.",
+			Tags:        []string{"auth"},
+			Response:    service.PasskeyChallenge{},
+			Errors:      []int{http.StatusTooManyRequests},
+		})
+		api.Handle("POST /api/v1/passkeys/login/finish", handler.HandlerFunc(passkeyHandler.FinishLoginHandler), openapi.Route{
+			Summary:     "Finish signing in with a passkey",
+			Description: "Answers a challenge from /api/v1/passkeys/login/begin, or the one a password login returns when the account requires a passkey.",
+			Tags:        []string{"auth"},
+			Request:     handler.FinishPasskeyRequest{},
+			Response:    service.TokenPair{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests},
+		})
+		api.Handle("GET /api/v1/user/passkeys", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(passkeyHandler.GetPasskeysHandler))), openapi.Route{
+			Summary:  "List the passkeys registered for the account",
+			Tags:     []string{"user"},
+			Auth:     true,
+			Response: handler.PasskeysResponse{},
+		})
+		api.Handle("POST /api/v1/user/passkeys/register/begin", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(passkeyHandler.BeginRegistrationHandler))), openapi.Route{
+			Summary:     "Start registering a passkey",
+			Description: "The options are passed to navigator.credentials.create.",
+			Tags:        []string{"user"},
+			Auth:        true,
+			Request:     handler.BeginPasskeyRegistrationRequest{},
+			Response:    service.PasskeyChallenge{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
 		})
+		api.Handle("POST /api/v1/user/passkeys/register/finish", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(passkeyHandler.FinishRegistrationHandler))), openapi.Route{
+			Summary:  "Finish registering a passkey with the credential the browser created",
+			Tags:     []string{"user"},
+			Auth:     true,
+			Request:  handler.FinishPasskeyRequest{},
+			Status:   http.StatusCreated,
+			Response: domain.Passkey{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusConflict},
+		})
+		api.Handle("DELETE /api/v1/user/passkeys/{passkeyId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(passkeyHandler.DeletePasskeyHandler))), openapi.Route{
+			Summary:     "Remove a passkey",
+			Description: "Removing the last passkey also stops requiring one for password logins.",
+			Tags:        []string{"user"},
+			Auth:        true,
+			Response:    handler.MessageResponse{},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		})
+		api.Handle("PUT /api/v1/user/passkeys/second-factor", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(passkeyHandler.SetSecondFactorHandler))), openapi.Route{
+			Summary:  "Set whether password logins must be confirmed with a passkey",
+			Tags:     []string{"user"},
+			Auth:     true,
+			Request:  handler.PasskeySecondFactorRequest{},
+			Response: handler.MessageResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+		})
+	}
+	api.HandleFunc("GET /api/v1/auth/providers", oauthHandler.GetProvidersHandler, openapi.Route{
+		Summary:  "List the OAuth providers users can sign in with",
+		Tags:     []string{"auth"},
+		Response: handler.OAuthProvidersResponse{},
+	})
+	api.Handle("GET /api/v1/auth/{provider}/login", handler.HandlerFunc(oauthHandler.LoginHandler), openapi.Route{
+		Summary: "Start signing in with an OAuth provider; redirects to the provider",
+		Tags:    []string{"auth"},
+		Status:  http.StatusFound,
+		Errors:  []int{http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/auth/{provider}/callback", handler.HandlerFunc(oauthHandler.CallbackHandler), openapi.Route{
+		Summary:     "Finish signing in with an OAuth provider; the provider redirects here",
+		Tags:        []string{"auth"},
+		Query:       []openapi.Param{{Name: "code", Description: "Authorization code from the provider"}, {Name: "state", Description: "State from the login redirect"}},
+		Response:    service.TokenPair{},
+		Description: "A link started at /api/v1/user/identities/{provider} answers with the linked identity instead of tokens. Accounts that require a passkey answer 202 with a passkey challenge instead of tokens; the login is finished at /api/v1/passkeys/login/finish.",
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusBadGateway, http.StatusServiceUnavailable},
+	})
+	api.Handle("POST /api/v1/user/identities/{provider}", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(oauthHandler.LinkHandler))), openapi.Route{
+		Summary:     "Start linking an OAuth provider account to the account",
+		Tags:        []string{"auth"},
+		Description: "Send the user to the returned URL; the provider redirects back to /api/v1/auth/{provider}/callback, which links the account.",
+		Response:    handler.OAuthLinkResponse{},
+		Errors:      []int{http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/refresh-token", handler.HandlerFunc(authHandler.RefreshTokenHandler), openapi.Route{
+		Summary:  "Exchange a refresh token for a new token pair",
+		Tags:     []string{"auth"},
+		Request:  handler.RefreshTokenRequest{},
+		Response: service.TokenPair{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+	})
+	api.Handle("POST /api/v1/logout", handler.HandlerFunc(authHandler.LogoutHandler), openapi.Route{
+		Summary:     "Revoke a refresh token",
+		Description: "Send the access token in the Authorization header to revoke it as well.",
+		Tags:        []string{"auth"},
+		Request:     handler.RefreshTokenRequest{},
+		Response:    handler.MessageResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	api.Handle("POST /api/v1/request-password-reset", handler.HandlerFunc(authHandler.RequestPasswordResetHandler), openapi.Route{
+		Summary:     "Request a password reset by email or SMS",
+		Description: "With channel sms, a six-digit code is texted to the account's verified phone number instead of emailing a token. The token is never returned, and the response is the same whether or not the account exists or has a verified number.",
+		Tags:        []string{"auth"},
+		Request:     handler.PasswordResetRequestRequest{},
+		Response:    handler.MessageResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	api.Handle("POST /api/v1/reset-password", handler.HandlerFunc(authHandler.ResetPasswordHandler), openapi.Route{
+		Summary:  "Reset a password with a reset token, or an email address and SMS code",
+		Tags:     []string{"auth"},
+		Request:  handler.PasswordResetRequest{},
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	api.Handle("POST /api/v1/confirm-email-change", handler.HandlerFunc(authHandler.ConfirmEmailChangeHandler), openapi.Route{
+		Summary:     "Confirm an email change and start a new session",
+		Description: "Accounts that require a passkey answer 202 with a passkey challenge instead of tokens; the login is finished at /api/v1/passkeys/login/finish.",
+		Tags:        []string{"auth"},
+		Request:     handler.ConfirmEmailChangeRequest{},
+		Response:    service.TokenPair{},
+		Errors:      []int{http.StatusBadRequest, http.StatusConflict, http.StatusServiceUnavailable},
+	})
+	api.Handle("GET /api/v1/shared/{token}", handler.HandlerFunc(shareHandler.GetSharedResumeHandler), openapi.Route{
+		Summary:  "View a resume through a share link",
+		Tags:     []string{"sharing"},
+		Response: domain.Resume{},
+		Errors:   []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusGone},
+	})
+	api.Handle("GET /api/v1/shared/{token}/export/markdown", handler.HandlerFunc(shareHandler.ExportSharedMarkdownHandler), openapi.Route{
+		Summary:     "Export a resume shared through a link as Markdown",
+		Tags:        []string{"sharing"},
+		ContentType: "text/markdown",
+		Errors:      []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusGone},
+	})
+	api.Handle("GET /api/v1/shared/{token}/export/pdf", handler.HandlerFunc(shareHandler.ExportSharedPDFHandler), openapi.Route{
+		Summary:     "Export a resume shared through a link as PDF",
+		Tags:        []string{"sharing"},
+		ContentType: "application/pdf",
+		Errors:      []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusGone},
+	})
+	api.Handle("GET /r/{slug}", handler.HandlerFunc(publicationHandler.PublishedResumeHandler), openapi.Route{
+		Summary:     "View the public page of a published resume",
+		Description: "Renders the resume published at the slug as an HTML page with meta tags for search engines and link previews. Slugs are matched case-insensitively.",
+		Tags:        []string{"publishing"},
+		ContentType: "text/html",
+		Errors:      []int{http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/photos/{name}", handler.HandlerFunc(photoHandler.GetPhotoHandler), openapi.Route{
+		Summary:     "Get a profile photo",
+		Description: "Serves a profile photo uploaded to a resume, at the URL in its personal info. Photo URLs change with every upload, so photos may be cached indefinitely.",
+		Tags:        []string{"personal info"},
+		ContentType: "image/jpeg",
+		Errors:      []int{http.StatusNotFound},
+	})
+	// Files in S3 are downloaded from the bucket with presigned URLs instead
+	if fileSigner != nil {
+		fileHandler := handler.NewFileHandler(fileStore, fileSigner)
+		api.Handle("GET /api/v1/files/{key...}", handler.HandlerFunc(fileHandler.GetFileHandler), openapi.Route{
+			Summary:     "Download a stored file",
+			Description: "Serves a file, such as a resume export, at a signed URL the API handed out. The URL carries its own expiry and signature, so no credentials are needed.",
+			Tags:        []string{"files"},
+			ContentType: "application/octet-stream",
+			Errors:      []int{http.StatusForbidden, http.StatusNotFound},
+		})
+	}
+	api.Handle("POST /api/v1/shared/{token}/unlock", handler.HandlerFunc(shareHandler.UnlockShareHandler), openapi.Route{
+		Summary:  "Enter the passphrase of a protected share link to view it for a while",
+		Tags:     []string{"sharing"},
+		Request:  handler.UnlockShareRequest{},
+		Response: handler.UnlockShareResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusGone, http.StatusTooManyRequests},
 	})
-	mux.HandleFunc("POST /api/v1/register", authHandler.RegisterHandler)
-	mux.HandleFunc("POST /api/v1/login", authHandler.LoginHandler)
-	mux.HandleFunc("POST /api/v1/refresh-token", authHandler.RefreshTokenHandler)
-	mux.HandleFunc("POST /api/v1/logout", authHandler.LogoutHandler)
-	mux.HandleFunc("POST /api/v1/request-password-reset", authHandler.RequestPasswordResetHandler)
-	mux.HandleFunc("POST /api/v1/reset-password", authHandler.ResetPasswordHandler)
 
 	// User profile route
-	mux.Handle("GET /api/v1/user/profile", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(userHandler.GetProfileHandler))))
+	api.Handle("GET /api/v1/user/profile", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(userHandler.GetProfileHandler))), openapi.Route{
+		Summary:  "Get the authenticated user's profile",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Response: handler.ProfileResponse{},
+		Errors:   []int{http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/user/preferences", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(userHandler.UpdatePreferencesHandler))), openapi.Route{
+		Summary:  "Update the authenticated user's preferences, such as their time zone, weekly digest and locale",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Request:  handler.PreferencesRequest{},
+		Response: handler.PreferencesResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/user/change-email", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(authHandler.ChangeEmailHandler))), openapi.Route{
+		Summary:  "Request an email change confirmed from the new address",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Request:  handler.ChangeEmailRequest{},
+		Status:   http.StatusAccepted,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict},
+	})
+	api.Handle("GET /api/v1/user/phone", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(phoneHandler.GetPhoneHandler))), openapi.Route{
+		Summary:  "Get the phone number password reset codes can be texted to",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Response: handler.PhoneResponse{},
+		Errors:   []int{http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/user/phone", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(phoneHandler.SetPhoneHandler))), openapi.Route{
+		Summary:     "Set the phone number and text it a verification code",
+		Description: "The number is unverified, and can't receive password reset codes, until the code is confirmed. National numbers are read in the given region.",
+		Tags:        []string{"user"},
+		Auth:        true,
+		Request:     handler.SetPhoneRequest{},
+		Status:      http.StatusAccepted,
+		Response:    handler.PhoneResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusTooManyRequests},
+	})
+	api.Handle("POST /api/v1/user/phone/verify", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(phoneHandler.VerifyPhoneHandler))), openapi.Route{
+		Summary:  "Verify the phone number with the code texted to it",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Request:  handler.VerifyPhoneRequest{},
+		Response: handler.PhoneResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	api.Handle("DELETE /api/v1/user/phone", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(phoneHandler.DeletePhoneHandler))), openapi.Route{
+		Summary:  "Remove the phone number",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/user/email-conflicts/merge", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(emailConflictHandler.MergeEmailConflictHandler))), openapi.Route{
+		Summary:     "Merge an account set aside for your email address",
+		Description: "When emails became case-insensitive, accounts whose address differed only in case from an older account's were moved to a placeholder address. Giving the password of such an account moves its resumes to the current user and deletes it, with its API keys, passkeys and linked providers.",
+		Tags:        []string{"user"},
+		Auth:        true,
+		Request:     handler.MergeEmailConflictRequest{},
+		Response:    handler.MessageResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusTooManyRequests},
+	})
+	api.Handle("GET /api/v1/user/sessions", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(userHandler.GetSessionsHandler))), openapi.Route{
+		Summary:  "List active sessions",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Response: handler.SessionsResponse{},
+	})
+	api.Handle("DELETE /api/v1/user/sessions/{sessionId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(userHandler.RevokeSessionHandler))), openapi.Route{
+		Summary:  "Revoke a session",
+		Tags:     []string{"user"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/user/api-keys", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(apiKeyHandler.GetAPIKeysHandler))), openapi.Route{
+		Summary:  "List the current user's API keys",
+		Tags:     []string{"api-keys"},
+		Auth:     true,
+		Response: handler.APIKeysResponse{},
+	})
+	api.Handle("POST /api/v1/user/api-keys", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(apiKeyHandler.CreateAPIKeyHandler))), openapi.Route{
+		Summary:     "Create a scoped API key for programmatic access",
+		Description: "The key is only returned in this response. Send it as a bearer token to the resume endpoints its scopes allow.",
+		Tags:        []string{"api-keys"},
+		Auth:        true,
+		Request:     handler.CreateAPIKeyRequest{},
+		Status:      http.StatusCreated,
+		Response:    handler.CreateAPIKeyResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	})
+	api.Handle("GET /api/v1/user/api-keys/{keyId}/usage", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(apiKeyHandler.GetAPIKeyUsageHandler))), openapi.Route{
+		Summary:     "Get the hourly request counts of an API key",
+		Description: "Hours without requests are left out.",
+		Tags:        []string{"api-keys"},
+		Auth:        true,
+		Query:       []openapi.Param{{Name: "days", Description: "How many days back to cover, 7 by default and at most 90"}},
+		Response:    handler.APIKeyUsageResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	api.Handle("DELETE /api/v1/user/api-keys/{keyId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(apiKeyHandler.RevokeAPIKeyHandler))), openapi.Route{
+		Summary:  "Revoke an API key",
+		Tags:     []string{"api-keys"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/user/data-export", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(dataExportHandler.GetDataExportHandler))), openapi.Route{
+		Summary:      "Download a data export, starting one if none is ready",
+		Tags:         []string{"user"},
+		Auth:         true,
+		ContentType:  "application/zip",
+		Alternatives: []openapi.Result{{Status: http.StatusAccepted, Body: handler.DataExportResponse{}}},
+	})
 
-	// Admin route
-	mux.Handle("GET /api/v1/admin/users", sessionLogger.LogActivity(authMiddleware.AuthRequired(authMiddleware.RequireRole("admin")(http.HandlerFunc(adminHandler.GetUsersHandler)))))
+	api.Handle("GET "+handler.EventsPath, sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(eventsHandler.StreamEventsHandler))), openapi.Route{
+		Summary:     "Stream the status of your background jobs as server-sent events",
+		Tags:        []string{"user"},
+		Auth:        true,
+		ContentType: "text/event-stream",
+	})
+
+	// Admin routes
+	api.Handle("GET /api/v1/admin/users", sessionLogger.LogActivity(authMiddleware.ServiceAccountOr(domain.ScopeUsersRead, authMiddleware.PermissionRequired(domain.PermissionUsersRead))(handler.HandlerFunc(adminHandler.GetUsersHandler))), openapi.Route{
+		Summary:  "List users",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Query:    pageQuery(domain.SortByEmail),
+		Response: handler.UserPage{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("GET /api/v1/admin/stats", sessionLogger.LogActivity(authMiddleware.ServiceAccountOr(domain.ScopeMetricsRead, authMiddleware.PermissionRequired(domain.PermissionStatsRead))(handler.HandlerFunc(statsHandler.GetStatsHandler))), openapi.Route{
+		Summary:     "Get instance statistics",
+		Description: "Counts users, resumes and active sessions, users by how many resumes they have, exports per day over the last 30 days and signups per week over the last 12 weeks, for an operations dashboard. Days and weeks are in UTC; weeks start on Monday.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    domain.AdminStats{},
+		Errors:      []int{http.StatusForbidden},
+	})
+	api.Handle("POST /api/v1/admin/users/{id}/revoke-tokens", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionUsersManage)(handler.HandlerFunc(adminHandler.RevokeUserTokensHandler))), openapi.Route{
+		Summary:     "Sign a user out everywhere",
+		Description: "Revokes the user's sessions and every access token issued to them so far.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    handler.MessageResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/admin/users/{id}/role", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionUsersManage)(handler.HandlerFunc(userRoleHandler.SetUserRoleHandler))), openapi.Route{
+		Summary:     "Change a user's role",
+		Description: "Gives the user one of the user roles and signs them out everywhere, so their next tokens carry it.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Request:     handler.SetUserRoleRequest{},
+		Response:    domain.User{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/admin/user-roles", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionRolesManage)(handler.HandlerFunc(userRoleHandler.ListUserRolesHandler))), openapi.Route{
+		Summary:     "List user roles",
+		Description: "Lists the roles users can have with the permissions each grants, and every permission a role can grant. The admin role grants every permission.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    handler.UserRolesResponse{},
+		Errors:      []int{http.StatusForbidden},
+	})
+	api.Handle("PUT /api/v1/admin/user-roles/{name}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionRolesManage)(handler.HandlerFunc(userRoleHandler.SaveUserRoleHandler))), openapi.Route{
+		Summary:     "Save a user role",
+		Description: "Creates the role or replaces its description and permissions; users with it are affected on their next request. The admin role can't be changed.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Request:     domain.UserRole{},
+		Response:    domain.UserRole{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("DELETE /api/v1/admin/user-roles/{name}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionRolesManage)(handler.HandlerFunc(userRoleHandler.DeleteUserRoleHandler))), openapi.Route{
+		Summary:     "Delete a user role",
+		Description: "Fails while users have the role. The user and admin roles can't be deleted.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    handler.MessageResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+	})
+	api.Handle("GET /api/v1/admin/settings/security-headers", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionSettingsManage)(handler.HandlerFunc(settingsHandler.GetSecurityHeadersHandler))), openapi.Route{
+		Summary:  "Get the security header profile",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Response: handler.SecurityHeadersResponse{},
+		Errors:   []int{http.StatusForbidden},
+	})
+	api.Handle("PUT /api/v1/admin/settings/security-headers", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionSettingsManage)(handler.HandlerFunc(settingsHandler.UpdateSecurityHeadersHandler))), openapi.Route{
+		Summary:  "Choose the security header profile and adjust its Content-Security-Policy",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Request:  security.HeadersSettings{},
+		Response: handler.SecurityHeadersResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("GET /api/v1/admin/csp-reports", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionSettingsManage)(handler.HandlerFunc(cspReportHandler.ListReportsHandler))), openapi.Route{
+		Summary:  "List reported Content-Security-Policy violations, most frequent first",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Query:    append(pageQuery(domain.SortByCount, domain.SortByLastSeenAt), openapi.Param{Name: "directive", Description: "Only violations of this directive, such as script-src"}),
+		Response: handler.CSPViolationPage{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("POST /api/v1/admin/user-imports", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionUsersManage)(handler.HandlerFunc(userImportHandler.CreateImportHandler))), openapi.Route{
+		Summary: "Import accounts from a CSV roster",
+		Description: "The body is a CSV roster with a header row naming its columns: email (required), first_name, last_name, resume_title and target_job_title. " +
+			"Each row creates an account with a skeleton resume and emails the user an invitation. Rows are imported in the background; poll the import for per-row results.",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Status:   http.StatusAccepted,
+		Response: service.UserImport{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusRequestEntityTooLarge},
+	})
+	api.Handle("GET /api/v1/admin/user-imports/{id}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionUsersManage)(handler.HandlerFunc(userImportHandler.GetImportHandler))), openapi.Route{
+		Summary:  "Get an account import and its per-row results",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Response: service.UserImport{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/admin/user-imports/{id}/errors", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionUsersManage)(handler.HandlerFunc(userImportHandler.GetImportErrorsHandler))), openapi.Route{
+		Summary:     "Download the rows of an account import that failed, as CSV",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		ContentType: "text/csv",
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/admin/jobs/stats", sessionLogger.LogActivity(authMiddleware.ServiceAccountOr(domain.ScopeJobsRead, authMiddleware.PermissionRequired(domain.PermissionStatsRead))(handler.HandlerFunc(jobHandler.GetQueueStatsHandler))), openapi.Route{
+		Summary:     "Get background job queue stats",
+		Description: "Jobs are claimed by priority: exports users are waiting for run before normal jobs, and scheduled or batch work runs last. Each user has a cap on jobs running at once.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    jobs.QueueStats{},
+		Errors:      []int{http.StatusForbidden},
+	})
+	api.Handle("POST /api/v1/admin/service-accounts", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionServiceAccountsManage)(handler.HandlerFunc(serviceAccountHandler.CreateServiceAccountHandler))), openapi.Route{
+		Summary:     "Create a service account for an integration",
+		Description: "Service accounts call the admin API within their scopes without acting as a user: metrics:read for GET /metrics and GET /api/v1/admin/stats, users:read for GET /api/v1/admin/users and jobs:read for GET /api/v1/admin/jobs/stats. The key is only returned here.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Status:      http.StatusCreated,
+		Request:     handler.CreateServiceAccountRequest{},
+		Response:    handler.ServiceAccountKeyResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("GET /api/v1/admin/service-accounts", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionServiceAccountsManage)(handler.HandlerFunc(serviceAccountHandler.GetServiceAccountsHandler))), openapi.Route{
+		Summary:  "List service accounts",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Response: handler.ServiceAccountsResponse{},
+		Errors:   []int{http.StatusForbidden},
+	})
+	api.Handle("POST /api/v1/admin/service-accounts/{id}/rotate", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionServiceAccountsManage)(handler.HandlerFunc(serviceAccountHandler.RotateServiceAccountKeyHandler))), openapi.Route{
+		Summary:     "Replace the key of a service account",
+		Description: "The old key stops working at once. The new key is only returned here.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    handler.ServiceAccountKeyResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("DELETE /api/v1/admin/service-accounts/{id}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionServiceAccountsManage)(handler.HandlerFunc(serviceAccountHandler.DeleteServiceAccountHandler))), openapi.Route{
+		Summary:  "Delete a service account",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/admin/service-accounts/{id}/audit", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionServiceAccountsManage)(handler.HandlerFunc(serviceAccountHandler.GetServiceAccountAuditHandler))), openapi.Route{
+		Summary:     "List what was done to and by a service account",
+		Description: "Returns the account's creation, key rotations, deletion and every request it made, newest first. The trail is kept after the account is deleted.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    handler.ServiceAccountAuditResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("GET /api/v1/admin/incidents", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionSettingsManage)(handler.HandlerFunc(statusHandler.GetIncidentsHandler))), openapi.Route{
+		Summary:  "List all incidents",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Response: handler.IncidentsResponse{},
+		Errors:   []int{http.StatusForbidden},
+	})
+	api.Handle("POST /api/v1/admin/incidents", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionSettingsManage)(handler.HandlerFunc(statusHandler.CreateIncidentHandler))), openapi.Route{
+		Summary:  "Open an incident",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Request:  domain.Incident{},
+		Status:   http.StatusCreated,
+		Response: domain.Incident{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("PATCH /api/v1/admin/incidents/{id}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionSettingsManage)(handler.HandlerFunc(statusHandler.UpdateIncidentHandler))), openapi.Route{
+		Summary:  "Update or resolve an incident",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Request:  handler.UpdateIncidentRequest{},
+		Response: domain.Incident{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("DELETE /api/v1/admin/incidents/{id}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionSettingsManage)(handler.HandlerFunc(statusHandler.DeleteIncidentHandler))), openapi.Route{
+		Summary:  "Delete an incident",
+		Tags:     []string{"admin"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/admin/roles/{slug}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionRolesManage)(handler.HandlerFunc(roleProfileHandler.SaveRoleHandler))), openapi.Route{
+		Summary:     "Save a role profile",
+		Description: "Replaces the built-in role profile with the slug, or adds a role when none has it.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Request:     domain.RoleProfile{},
+		Response:    domain.RoleProfile{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden},
+	})
+	api.Handle("DELETE /api/v1/admin/roles/{slug}", sessionLogger.LogActivity(authMiddleware.PermissionRequired(domain.PermissionRolesManage)(handler.HandlerFunc(roleProfileHandler.DeleteRoleHandler))), openapi.Route{
+		Summary:     "Delete a saved role profile",
+		Description: "Restores the built-in role profile with the slug, if there is one.",
+		Tags:        []string{"admin"},
+		Auth:        true,
+		Response:    handler.MessageResponse{},
+		Errors:      []int{http.StatusForbidden, http.StatusNotFound},
+	})
+
+	// Role profiles
+	api.Handle("GET /api/v1/roles", sessionLogger.LogActivity(authMiddleware.AuthRequired(handler.HandlerFunc(roleProfileHandler.ListRolesHandler))), openapi.Route{
+		Summary:  "List the role profiles resumes can be compared against",
+		Tags:     []string{"roles"},
+		Auth:     true,
+		Response: handler.RoleProfilesResponse{},
+	})
 
 	// Resume routes
-	mux.Handle("GET /api/v1/resumes", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetResumeListHandler))))
-	mux.Handle("GET /api/v1/resumes/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetResumeHandler))))
-	mux.Handle("POST /api/v1/resumes", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.CreateResumeHandler))))
-	mux.Handle("DELETE /api/v1/resumes/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteResumeHandler))))
-	mux.Handle("GET /api/v1/resumes/{id}/personal-info", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetPersonalInfoHandler))))
-	mux.Handle("PUT /api/v1/resumes/{id}/personal-info", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.SavePersonalInfoHandler))))
-	mux.Handle("GET /api/v1/resumes/{id}/education", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetEducationHandler))))
-	mux.Handle("POST /api/v1/resumes/{id}/education", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddEducationHandler))))
-	mux.Handle("DELETE /api/v1/resumes/{id}/education/{educationId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteEducationHandler))))
-	mux.Handle("GET /api/v1/resumes/{id}/experience", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetExperienceHandler))))
-	mux.Handle("POST /api/v1/resumes/{id}/experience", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddExperienceHandler))))
-	mux.Handle("DELETE /api/v1/resumes/{id}/experience/{experienceId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteExperienceHandler))))
-	mux.Handle("GET /api/v1/resumes/{id}/skills", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetSkillsHandler))))
-	mux.Handle("POST /api/v1/resumes/{id}/skills", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddSkillHandler))))
-	mux.Handle("DELETE /api/v1/resumes/{id}/skills/{skillId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteSkillHandler))))
-	mux.Handle("GET /api/v1/resumes/{id}/projects", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetProjectsHandler))))
-	mux.Handle("POST /api/v1/resumes/{id}/projects", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddProjectHandler))))
-	mux.Handle("DELETE /api/v1/resumes/{id}/projects/{projectId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteProjectHandler))))
-	mux.Handle("GET /api/v1/resumes/{id}/certifications", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetCertificationsHandler))))
-	mux.Handle("POST /api/v1/resumes/{id}/certifications", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddCertificationHandler))))
-	mux.Handle("DELETE /api/v1/resumes/{id}/certifications/{certificationId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteCertificationHandler))))
-
-	// Wrap the entire router with CORS middleware
-	handlerWithCORS := corsMiddleware(mux)
-
-	return handlerWithCORS
+	api.Handle("GET /api/v1/resumes", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(handler.HandlerFunc(resumeHandler.GetResumeListHandler))), openapi.Route{
+		Summary:  "List the authenticated user's resumes",
+		Tags:     []string{"resumes"},
+		Auth:     true,
+		Query:    append(pageQuery(domain.SortByTitle), openapi.Param{Name: "tag", Description: "Only resumes with this tag"}),
+		Response: handler.ResumePage{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	api.Handle("GET /api/v1/resumes/updated-since", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(handler.HandlerFunc(feedHandler.UpdatedSinceHandler))), openapi.Route{
+		Summary:     "Poll for resumes changed since a time",
+		Description: "Lists the resumes changed after ts, oldest change first, for automation platforms that poll instead of receiving webhooks. Pass next_cursor back as cursor to continue; it only moves forward, so each change is seen once. Changes show up a few seconds after they are made. Each item's id is unique to the change.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Query: []openapi.Param{
+			{Name: "ts", Description: "RFC 3339 time or Unix seconds to list changes after; required without cursor"},
+			{Name: "cursor", Description: "next_cursor of the previous response"},
+			{Name: "limit", Description: "Maximum number of items, at most 100"},
+		},
+		Response: service.ChangedResumesPage{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	api.Handle("GET /api/v1/resumes/events", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(handler.HandlerFunc(feedHandler.EventsHandler))), openapi.Route{
+		Summary:     "Poll for edits across all resumes",
+		Description: "Lists the edits of all the user's resumes in the order they were made, including deletes. Pass next_cursor back as cursor to continue; it only moves forward, so each event is seen once. Events show up a few seconds after they are recorded.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Query: []openapi.Param{
+			{Name: "ts", Description: "RFC 3339 time or Unix seconds to list events after"},
+			{Name: "cursor", Description: "next_cursor of the previous response"},
+			{Name: "limit", Description: "Maximum number of events, at most 100"},
+		},
+		Response: service.ResumeEventsPage{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	api.Handle("GET /api/v1/resumes/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetResumeHandler)))), openapi.Route{
+		Summary:  "Get a complete resume",
+		Tags:     []string{"resumes"},
+		Auth:     true,
+		Response: domain.Resume{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(handler.HandlerFunc(resumeHandler.CreateResumeHandler))), openapi.Route{
+		Summary:         "Create a resume",
+		Description:     "Formatting settings left unset are filled in for the user's locale preference, if they have one: long dates, national phone numbers and the paper size of the locale's region.",
+		Tags:            []string{"resumes"},
+		Auth:            true,
+		Request:         domain.ResumeMetadata{},
+		RequestOptional: true,
+		Status:          http.StatusCreated,
+		Response:        domain.Resume{},
+		Errors:          []int{http.StatusBadRequest},
+	})
+	api.Handle("POST /api/v1/resumes/import/text", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(handler.HandlerFunc(resumeHandler.ImportTextHandler))), openapi.Route{
+		Summary: "Propose resume sections for a resume pasted as plain text",
+		Description: "Headings, date ranges and bullets are used to split the text into personal information, experience, education, skills, projects and certifications. " +
+			"Each entry has a confidence between 0 and 1; nothing is saved, so review the entries and create the ones to keep. Lines that fit no entry are returned as unrecognized.",
+		Tags:     []string{"resumes"},
+		Auth:     true,
+		Request:  handler.ImportTextRequest{},
+		Response: parser.Proposal{},
+		Errors:   []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge},
+	})
+	api.Handle("PUT /api/v1/resumes/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.UpdateResumeHandler)))), openapi.Route{
+		Summary:     "Update a resume's title, target job title, tags, language and formatting settings",
+		Description: "The layout settings hold page-break hints PDF exports follow: a section, or one of its entries by position, can start on a new page (\"before\") or be kept on one page (\"keep_together\"). They also hold entry priorities from 1 to 5 (3 by default), by section and position; one-page PDF exports omit entries with lower priorities first and never omit those with 5.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Request:     domain.ResumeMetadata{},
+		Response:    domain.Resume{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.DeleteResumeHandler)))), openapi.Route{
+		Summary:  "Delete a resume",
+		Tags:     []string{"resumes"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/events", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetResumeEventsHandler)))), openapi.Route{
+		Summary: "List changes made to a resume",
+		Tags:    []string{"resumes"},
+		Auth:    true,
+		Query: []openapi.Param{
+			{Name: "after", Type: "integer", Description: "Only events with a greater version"},
+			{Name: "limit", Type: "integer", Description: "Maximum number of events"},
+		},
+		Response: handler.ResumeEventsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/activity", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(activityHandler.GetActivityHandler)))), openapi.Route{
+		Summary:     "List what happened to a resume",
+		Description: "Returns the resume's edits, exports, emails, share links and their views, publishing, page views and note saves, newest first. Pass next_cursor as cursor to fetch older activity. Comments are not part of the feed, as resumes have none.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Query: []openapi.Param{
+			{Name: "cursor", Type: "string", Description: "next_cursor of the previous page"},
+			{Name: "limit", Type: "integer", Description: "Maximum number of items, at most 200"},
+		},
+		Response: service.ActivityPage{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/versions/{version}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetResumeVersionHandler)))), openapi.Route{
+		Summary:    "Get a resume as it was at a version",
+		Tags:       []string{"resumes"},
+		Auth:       true,
+		PathParams: []openapi.Param{{Name: "version", Type: "integer"}},
+		Response:   handler.ResumeVersionResponse{},
+		Errors:     []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/translations", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(translationHandler.GetTranslationsHandler)))), openapi.Route{
+		Summary:  "List a resume's original and its translations",
+		Tags:     []string{"resumes"},
+		Auth:     true,
+		Response: handler.TranslationsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/translations", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(translationHandler.CreateTranslationHandler)))), openapi.Route{
+		Summary:  "Copy a resume into a new resume in another language",
+		Tags:     []string{"resumes"},
+		Auth:     true,
+		Request:  handler.CreateTranslationRequest{},
+		Status:   http.StatusCreated,
+		Response: domain.Resume{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/share", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(shareHandler.GetSharesHandler)))), openapi.Route{
+		Summary:  "List a resume's share links",
+		Tags:     []string{"sharing"},
+		Auth:     true,
+		Response: handler.SharesResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/share", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(shareHandler.CreateShareHandler)))), openapi.Route{
+		Summary:  "Create a share link, optionally protected by a passphrase and limited in views or time",
+		Tags:     []string{"sharing"},
+		Auth:     true,
+		Request:  handler.CreateShareRequest{},
+		Status:   http.StatusCreated,
+		Response: handler.ShareResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("PATCH /api/v1/resumes/{id}/share/{shareId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(shareHandler.UpdateShareHandler)))), openapi.Route{
+		Summary:  "Adjust or remove the view limit and expiry of a share link",
+		Tags:     []string{"sharing"},
+		Auth:     true,
+		Request:  handler.UpdateShareRequest{},
+		Response: handler.ShareResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/share/{shareId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(shareHandler.RevokeShareHandler)))), openapi.Route{
+		Summary:  "Revoke a share link",
+		Tags:     []string{"sharing"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/publication", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(publicationHandler.GetPublicationHandler)))), openapi.Route{
+		Summary:  "Get where a resume is published",
+		Tags:     []string{"publishing"},
+		Auth:     true,
+		Response: handler.PublicationResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/resumes/{id}/publication", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(publicationHandler.PublishResumeHandler)))), openapi.Route{
+		Summary:     "Publish a resume as a public page at a vanity slug",
+		Description: "Publishes the resume at /r/{slug}, or moves its page to the new slug and frees the old one. Slugs are 3 to 50 lowercase letters, digits and hyphens. A slug another resume is published at is rejected with 409, listing free alternatives in suggestions.",
+		Tags:        []string{"publishing"},
+		Auth:        true,
+		Request:     handler.PublishResumeRequest{},
+		Response:    handler.PublicationResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/publication", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(publicationHandler.UnpublishResumeHandler)))), openapi.Route{
+		Summary:  "Unpublish a resume, freeing its slug",
+		Tags:     []string{"publishing"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/notes", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(noteHandler.GetNoteHandler)))), openapi.Route{
+		Summary:  "Get the latest version of a resume's encrypted private note",
+		Tags:     []string{"notes"},
+		Auth:     true,
+		Response: domain.ResumeNote{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/resumes/{id}/notes", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(noteHandler.SaveNoteHandler)))), openapi.Route{
+		Summary:     "Save a new version of a resume's private note, encrypted by the client",
+		Description: "The save fails with 409 when the note has another version than base_version.",
+		Tags:        []string{"notes"},
+		Auth:        true,
+		Request:     handler.SaveNoteRequest{},
+		Response:    domain.ResumeNote{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusRequestEntityTooLarge},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/notes", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(noteHandler.DeleteNoteHandler)))), openapi.Route{
+		Summary:  "Delete a resume's private note with all its versions",
+		Tags:     []string{"notes"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/notes/versions", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(noteHandler.GetNoteVersionsHandler)))), openapi.Route{
+		Summary:  "List the kept versions of a resume's private note",
+		Tags:     []string{"notes"},
+		Auth:     true,
+		Response: handler.NoteVersionsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/notes/versions/{version}", sessionLogger.LogActivity(authMiddleware.AuthRequired(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(noteHandler.GetNoteVersionHandler)))), openapi.Route{
+		Summary:  "Get a kept version of a resume's private note",
+		Tags:     []string{"notes"},
+		Auth:     true,
+		Response: domain.ResumeNote{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/export/markdown", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.ExportMarkdownHandler)))), openapi.Route{
+		Summary:     "Export a resume as Markdown in its language",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		ContentType: "text/markdown",
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/export/pdf", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.ExportPDFHandler)))), openapi.Route{
+		Summary:     "Export a resume as PDF in its language",
+		Description: "Returns a signed, time-limited URL to download the PDF from. The PDF is kept in file storage and only rendered again once the resume changes. With one_page, margins narrow and fonts shrink within limits to fit one page, then entries are omitted lowest priority first (settings.layout.priorities); the response's condensed field reports what was omitted. Page-break hints don't apply.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Query:       []openapi.Param{{Name: "one_page", Description: "Condense the resume to one page", Type: "boolean"}},
+		Response:    service.ExportURL{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/match", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.MatchHandler)))), openapi.Route{
+		Summary:     "Match a resume against a job description",
+		Description: "Extracts the keywords of the job description and reports those the resume mentions and those it misses, with a coverage score from 0 to 100 weighted by how often the job description repeats each keyword.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Request:     handler.MatchRequest{},
+		Response:    analysis.Match{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/compare", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(roleProfileHandler.CompareHandler)))), openapi.Route{
+		Summary:     "Compare a resume against a role profile",
+		Description: "Reports the role's expected skills and sections the resume lacks, its years of experience against the role's, and recommendations.",
+		Tags:        []string{"roles"},
+		Auth:        true,
+		Query:       []openapi.Param{{Name: "role", Description: "Slug of the role profile, such as backend-mid"}},
+		Response:    analysis.Comparison{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/imports", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(entryImportHandler.ImportEntriesHandler)))), openapi.Route{
+		Summary:     "Import resume entries",
+		Description: "Adds experience, education, skill, project and certification entries from a LinkedIn, GitHub, file or text import, all or none, recording the source and imported value of each.",
+		Tags:        []string{"imports"},
+		Auth:        true,
+		Request:     handler.ImportEntriesRequest{},
+		Response:    service.ImportResult{},
+		Status:      http.StatusCreated,
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/provenance", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(entryImportHandler.ListProvenanceHandler)))), openapi.Route{
+		Summary:     "List imported entries",
+		Description: "Reports where each imported entry of the resume came from, with the value it was imported or last re-synced with.",
+		Tags:        []string{"imports"},
+		Auth:        true,
+		Response:    handler.ProvenanceResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/provenance/{entity}/{entityId}/revert", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(entryImportHandler.RevertEntryHandler)))), openapi.Route{
+		Summary:     "Revert an imported entry",
+		Description: "Discards edits to an imported entry, restoring the value it was imported or last re-synced with.",
+		Tags:        []string{"imports"},
+		Auth:        true,
+		Response:    domain.Provenance{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/resumes/{id}/provenance/{entity}/{entityId}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(entryImportHandler.ResyncEntryHandler)))), openapi.Route{
+		Summary:     "Re-sync an imported entry",
+		Description: "Replaces an imported entry with the value its source has now, which later reverts restore.",
+		Tags:        []string{"imports"},
+		Auth:        true,
+		Request:     handler.ResyncEntryRequest{},
+		Response:    domain.Provenance{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/connectors", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(connectorHandler.CreateConnectorHandler)))), openapi.Route{
+		Summary:     "Connect an account",
+		Description: "Connects a GitHub or Credly account to the resume. Syncing it imports its public repositories as projects, or its badges as certifications.",
+		Tags:        []string{"connectors"},
+		Auth:        true,
+		Request:     handler.CreateConnectorRequest{},
+		Response:    domain.Connector{},
+		Status:      http.StatusCreated,
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/connectors", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(connectorHandler.ListConnectorsHandler)))), openapi.Route{
+		Summary:  "List connected accounts",
+		Tags:     []string{"connectors"},
+		Auth:     true,
+		Response: handler.ConnectorsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/connectors/{id}/sync", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(handler.HandlerFunc(connectorHandler.SyncConnectorHandler))), openapi.Route{
+		Summary:     "Sync a connected account",
+		Description: "Re-imports the account's entries. New entries are added and entries edited only at the source take its value; entries edited on both sides are left as they are and reported as conflicts with their base, local and upstream values.",
+		Tags:        []string{"connectors"},
+		Auth:        true,
+		Response:    service.SyncResult{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusBadGateway},
+	})
+	api.Handle("POST /api/v1/connectors/{id}/conflicts/{entityId}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(handler.HandlerFunc(connectorHandler.ResolveConflictHandler))), openapi.Route{
+		Summary:     "Resolve a sync conflict",
+		Description: "Keeps the local edits, takes the source's value, or sets a merged value for an entry the last sync reported in conflict.",
+		Tags:        []string{"connectors"},
+		Auth:        true,
+		Request:     handler.ResolveConflictRequest{},
+		Response:    domain.Provenance{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+	})
+	api.Handle("DELETE /api/v1/connectors/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(handler.HandlerFunc(connectorHandler.DeleteConnectorHandler))), openapi.Route{
+		Summary:     "Disconnect an account",
+		Description: "The entries the connector synced stay on the resume.",
+		Tags:        []string{"connectors"},
+		Auth:        true,
+		Response:    handler.MessageResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/proofread", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(proofreadHandler.ProofreadResumeHandler)))), openapi.Route{
+		Summary:     "Proofread a resume",
+		Description: "Checks the spelling and grammar of the resume's titles and descriptions in its language, or the one in the body. Issue offsets count UTF-16 code units into the field, as JavaScript strings do.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Request:     handler.ProofreadRequest{},
+		Response:    handler.ProofreadResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusBadGateway},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/send", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeSendHandler.SendResumeHandler)))), openapi.Route{
+		Summary:     "Email a resume as an attachment",
+		Description: "Renders the resume as PDF or Markdown and emails it to the address given, with replies going to the user. Nothing is sent unless confirm is true; otherwise the response previews the email. Each user can send a limited number of resumes per UTC day.",
+		Tags:        []string{"resumes"},
+		Auth:        true,
+		Request:     handler.SendResumeRequest{},
+		Response:    handler.SendResumeResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/personal-info", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetPersonalInfoHandler)))), openapi.Route{
+		Summary:  "Get personal info",
+		Tags:     []string{"personal info"},
+		Auth:     true,
+		Response: domain.PersonalInfo{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("PUT /api/v1/resumes/{id}/personal-info", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.SavePersonalInfoHandler)))), openapi.Route{
+		Summary:  "Save personal info",
+		Tags:     []string{"personal info"},
+		Auth:     true,
+		Request:  domain.PersonalInfo{},
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/photo", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(photoHandler.UploadPhotoHandler)))), openapi.Route{
+		Summary:     "Upload a profile photo",
+		Description: "Takes a JPEG, PNG, GIF or WebP image of up to 5 MB in the photo field of a multipart/form-data body. The photo is scaled down to fit 512×512 pixels, stored as JPEG without its metadata and linked from the personal info's photo_url, replacing any previous one. The resume needs personal info first.",
+		Tags:        []string{"personal info"},
+		Auth:        true,
+		Response:    handler.PhotoResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/photo", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(photoHandler.DeletePhotoHandler)))), openapi.Route{
+		Summary:  "Delete the profile photo",
+		Tags:     []string{"personal info"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/education", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetEducationHandler)))), openapi.Route{
+		Summary:  "List education entries",
+		Tags:     []string{"education"},
+		Auth:     true,
+		Response: []domain.Education{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/education", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.AddEducationHandler)))), openapi.Route{
+		Summary:  "Add an education entry",
+		Tags:     []string{"education"},
+		Auth:     true,
+		Request:  domain.Education{},
+		Status:   http.StatusCreated,
+		Response: handler.CreatedResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/education/{educationId}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.DeleteEducationHandler)))), openapi.Route{
+		Summary:  "Delete an education entry",
+		Tags:     []string{"education"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/experience", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetExperienceHandler)))), openapi.Route{
+		Summary:  "List experience entries",
+		Tags:     []string{"experience"},
+		Auth:     true,
+		Response: []domain.Experience{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/experience", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.AddExperienceHandler)))), openapi.Route{
+		Summary:  "Add an experience entry",
+		Tags:     []string{"experience"},
+		Auth:     true,
+		Request:  domain.Experience{},
+		Status:   http.StatusCreated,
+		Response: handler.CreatedResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/experience/{experienceId}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.DeleteExperienceHandler)))), openapi.Route{
+		Summary:  "Delete an experience entry",
+		Tags:     []string{"experience"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	if aiProvider != nil {
+		suggestionHandler := handler.NewSuggestionHandler(resumeRepo, aiProvider)
+		api.Handle("POST /api/v1/resumes/{id}/experience/{experienceId}/suggest", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(suggestionHandler.SuggestBulletsHandler)))), openapi.Route{
+			Summary:     "Suggest achievement bullets for an experience entry",
+			Description: "Asks the configured language model for improved achievement bullets written from the entry's description, in the resume's language. Nothing is stored. Only available when AI_PROVIDER is set.",
+			Tags:        []string{"experience"},
+			Auth:        true,
+			Response:    handler.BulletSuggestionsResponse{},
+			Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusBadGateway},
+		})
+	}
+	api.Handle("GET /api/v1/resumes/{id}/skills", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetSkillsHandler)))), openapi.Route{
+		Summary:  "List skills",
+		Tags:     []string{"skills"},
+		Auth:     true,
+		Response: []domain.Skill{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/skills/grouped", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetGroupedSkillsHandler)))), openapi.Route{
+		Summary:  "List skills grouped by category, with proficiency labels",
+		Tags:     []string{"skills"},
+		Auth:     true,
+		Query:    []openapi.Param{{Name: "labels", Description: "Names for proficiency levels 1 to 5, separated by commas; defaults to the resume's language"}},
+		Response: handler.GroupedSkillsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/skills", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.AddSkillHandler)))), openapi.Route{
+		Summary:  "Add a skill",
+		Tags:     []string{"skills"},
+		Auth:     true,
+		Request:  domain.Skill{},
+		Status:   http.StatusCreated,
+		Response: handler.CreatedResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/skills/bulk", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.AddSkillsHandler)))), openapi.Route{
+		Summary:  "Add several skills at once",
+		Tags:     []string{"skills"},
+		Auth:     true,
+		Request:  handler.BulkSkillsRequest{},
+		Status:   http.StatusCreated,
+		Response: handler.BulkSkillsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/skills/{skillId}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.DeleteSkillHandler)))), openapi.Route{
+		Summary:  "Delete a skill",
+		Tags:     []string{"skills"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/projects", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetProjectsHandler)))), openapi.Route{
+		Summary:  "List projects",
+		Tags:     []string{"projects"},
+		Auth:     true,
+		Response: []domain.Project{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/projects", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.AddProjectHandler)))), openapi.Route{
+		Summary:  "Add a project",
+		Tags:     []string{"projects"},
+		Auth:     true,
+		Request:  domain.Project{},
+		Status:   http.StatusCreated,
+		Response: handler.CreatedResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/projects/{projectId}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.DeleteProjectHandler)))), openapi.Route{
+		Summary:  "Delete a project",
+		Tags:     []string{"projects"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("GET /api/v1/resumes/{id}/certifications", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesRead)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesReadAny)(handler.HandlerFunc(resumeHandler.GetCertificationsHandler)))), openapi.Route{
+		Summary:  "List certifications",
+		Tags:     []string{"certifications"},
+		Auth:     true,
+		Response: []domain.Certification{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+	api.Handle("POST /api/v1/resumes/{id}/certifications", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.AddCertificationHandler)))), openapi.Route{
+		Summary:  "Add a certification",
+		Tags:     []string{"certifications"},
+		Auth:     true,
+		Request:  domain.Certification{},
+		Status:   http.StatusCreated,
+		Response: handler.CreatedResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	api.Handle("DELETE /api/v1/resumes/{id}/certifications/{certificationId}", sessionLogger.LogActivity(authMiddleware.AuthRequiredWithScope(domain.ScopeResumesWrite)(resumeHandler.RequireResumeOwnership(domain.PermissionResumesWriteAny)(handler.HandlerFunc(resumeHandler.DeleteCertificationHandler)))), openapi.Route{
+		Summary:  "Delete a certification",
+		Tags:     []string{"certifications"},
+		Auth:     true,
+		Response: handler.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	})
+
+	// Wrap the entire router with CORS middleware and the request deadline,
+	// assigning the request ID first so every response and log entry carries it
+	handlerWithCORS := handler.RequestID(corsMiddleware(securityHeaders.Middleware(handler.RequestTimeout(requestTimeout, handler.EventsPath)(telemetry.NameSpansByRoute(mux)))))
+
+	// Trace every request; spans are renamed after their route once matched
+	return telemetry.Middleware(handlerWithCORS)
 }
@@ -0,0 +1,253 @@
+// Command smoketest runs an end-to-end check against a deployed instance. It
+// registers a temporary user, creates a resume, adds a section, downloads the
+// data export and cleans up after itself. It exits non-zero on any failure so it
+// can gate a deployment.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	baseURL := flag.String("base-url", envOr("SMOKETEST_BASE_URL", "http://localhost:8080"), "Base URL of the deployed instance")
+	timeout := flag.Duration("timeout", 2*time.Minute, "Overall time allowed for the smoke test")
+	exportWait := flag.Duration("export-wait", 5*time.Second, "Delay between polls while the data export is generated")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client := &client{
+		baseURL: strings.TrimRight(*baseURL, "/"),
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if err := run(ctx, client, *exportWait); err != nil {
+		log.Error().Err(err).Str("base_url", client.baseURL).Msg("Smoke test failed")
+		os.Exit(1)
+	}
+
+	log.Info().Str("base_url", client.baseURL).Msg("Smoke test passed")
+}
+
+// run performs each step in order, stopping at the first failure. Cleanup runs
+// regardless of the outcome so failed runs do not leave resumes behind.
+func run(ctx context.Context, c *client, exportWait time.Duration) (err error) {
+	email := fmt.Sprintf("smoketest+%s@example.com", uuid.NewString())
+	password := uuid.NewString()
+
+	step := func(name string, fn func() error) error {
+		start := time.Now()
+		if err := fn(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		log.Info().Str("step", name).Dur("duration", time.Since(start)).Msg("Step passed")
+		return nil
+	}
+
+	if err := step("ready", func() error {
+		return c.do(ctx, http.MethodGet, "/readyz", nil, http.StatusOK, nil)
+	}); err != nil {
+		return err
+	}
+
+	if err := step("register", func() error {
+		return c.do(ctx, http.MethodPost, "/api/v1/register", map[string]string{
+			"email":    email,
+			"password": password,
+		}, http.StatusCreated, nil)
+	}); err != nil {
+		return err
+	}
+
+	var tokens struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := step("login", func() error {
+		if err := c.do(ctx, http.MethodPost, "/api/v1/login", map[string]string{
+			"email":    email,
+			"password": password,
+		}, http.StatusOK, &tokens); err != nil {
+			return err
+		}
+		if tokens.AccessToken == "" {
+			return errors.New("no access token in response")
+		}
+		c.accessToken = tokens.AccessToken
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// The API has no account deletion yet, so the temporary user remains with
+	// its sessions revoked
+	defer func() {
+		if logoutErr := step("logout", func() error {
+			return c.do(context.Background(), http.MethodPost, "/api/v1/logout", map[string]string{
+				"refresh_token": tokens.RefreshToken,
+			}, http.StatusOK, nil)
+		}); logoutErr != nil && err == nil {
+			err = logoutErr
+		}
+	}()
+
+	var resume struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := step("create resume", func() error {
+		return c.do(ctx, http.MethodPost, "/api/v1/resumes", map[string]any{
+			"title": "Smoke test",
+			"tags":  []string{"smoketest"},
+		}, http.StatusCreated, &resume)
+	}); err != nil {
+		return err
+	}
+
+	defer func() {
+		if deleteErr := step("delete resume", func() error {
+			return c.do(context.Background(), http.MethodDelete, "/api/v1/resumes/"+resume.ID.String(), nil, http.StatusOK, nil)
+		}); deleteErr != nil && err == nil {
+			err = deleteErr
+		}
+	}()
+
+	if err := step("add skill", func() error {
+		return c.do(ctx, http.MethodPost, "/api/v1/resumes/"+resume.ID.String()+"/skills", map[string]any{
+			"name":        "Go",
+			"category":    "language",
+			"proficiency": 5,
+		}, http.StatusCreated, nil)
+	}); err != nil {
+		return err
+	}
+
+	if err := step("read resume", func() error {
+		var complete struct {
+			Skills []struct {
+				Name string `json:"name"`
+			} `json:"skills"`
+		}
+		if err := c.do(ctx, http.MethodGet, "/api/v1/resumes/"+resume.ID.String(), nil, http.StatusOK, &complete); err != nil {
+			return err
+		}
+		if len(complete.Skills) != 1 || complete.Skills[0].Name != "Go" {
+			return errors.New("added skill missing from resume")
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return step("data export", func() error {
+		return c.waitForExport(ctx, exportWait)
+	})
+}
+
+// client is a minimal API client that carries the access token between steps
+type client struct {
+	baseURL     string
+	http        *http.Client
+	accessToken string
+}
+
+// do sends a JSON request and decodes the response into out when it is non-nil
+func (c *client) do(ctx context.Context, method, path string, body any, wantStatus int, out any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: expected status %d, got %d: %s", method, path, wantStatus, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+		}
+	}
+
+	return nil
+}
+
+// waitForExport polls the data export endpoint until the archive is served
+func (c *client) waitForExport(ctx context.Context, interval time.Duration) error {
+	for {
+		resp, err := c.send(ctx, http.MethodGet, "/api/v1/user/data-export", nil)
+		if err != nil {
+			return err
+		}
+		archive, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			if resp.Header.Get("Content-Type") != "application/zip" || len(archive) == 0 {
+				return errors.New("data export did not return a ZIP archive")
+			}
+			return nil
+		case http.StatusAccepted:
+			// Still being generated
+		default:
+			return fmt.Errorf("unexpected data export status %d", resp.StatusCode)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("data export not ready: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	return c.http.Do(req)
+}
+
+// envOr returns the environment variable's value, or fallback when it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,30 @@
+// Package ai drafts resume content with a large language model. Providers
+// are pluggable; the server only enables the feature when one is configured.
+package ai
+
+import (
+	"context"
+	"errors"
+)
+
+// Message roles
+const (
+	RoleSystem = "system"
+	RoleUser   = "user"
+)
+
+// ErrProvider is returned when the model can't be reached or its reply
+// can't be used
+var ErrProvider = errors.New("ai provider request failed")
+
+// Message is one turn of a conversation with the model
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Provider completes conversations with a language model
+type Provider interface {
+	// Complete returns the model's reply to the messages
+	Complete(ctx context.Context, messages []Message) (string, error)
+}
@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/sanitize"
+)
+
+// MaxBullets caps the achievement bullets suggested for an experience entry
+const MaxBullets = 5
+
+// bulletsPrompt instructs the model. The entry is sent as a separate message
+// so its text is treated as material, not instructions.
+const bulletsPrompt = `You improve resumes. From the work experience entry the user sends, write up to %d achievement bullets for it.
+Start each bullet with a strong action verb, keep it to one sentence, and quantify impact only with figures the entry gives; never invent employers, tools or numbers.
+Write in the language with the tag %q. Reply with the bullets only, one per line, without numbering or any other text.`
+
+// markerRe matches the list markers models put before bullets anyway
+var markerRe = regexp.MustCompile(`^(?:[-*•–—]|\d{1,2}[.)])\s*`)
+
+// SuggestBullets asks the provider for achievement bullets improving an
+// experience entry, written from its description and current achievements
+// in language
+func SuggestBullets(ctx context.Context, provider Provider, experience *domain.Experience, language string) ([]string, error) {
+	var entry strings.Builder
+	fmt.Fprintf(&entry, "Title: %s\nEmployer: %s\n", experience.JobTitle, experience.Employer)
+	if experience.Description != "" {
+		fmt.Fprintf(&entry, "Description: %s\n", experience.Description)
+	}
+	for _, achievement := range experience.Achievements {
+		fmt.Fprintf(&entry, "- %s\n", achievement)
+	}
+
+	reply, err := provider.Complete(ctx, []Message{
+		{Role: RoleSystem, Content: fmt.Sprintf(bulletsPrompt, MaxBullets, language)},
+		{Role: RoleUser, Content: entry.String()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bullets := parseBullets(reply)
+	if len(bullets) == 0 {
+		return nil, fmt.Errorf("%w: no bullets in the reply", ErrProvider)
+	}
+	return bullets, nil
+}
+
+// parseBullets reads one bullet per line of a reply, dropping list markers,
+// surrounding quotes and lines introducing the list
+func parseBullets(reply string) []string {
+	var bullets []string
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(markerRe.ReplaceAllString(strings.TrimSpace(line), ""))
+		line = sanitize.Line(strings.Trim(line, `"*`))
+		if line == "" || strings.HasSuffix(line, ":") {
+			continue
+		}
+		bullets = append(bullets, line)
+		if len(bullets) == MaxBullets {
+			break
+		}
+	}
+	return bullets
+}
@@ -0,0 +1,41 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider replies with a fixed text and keeps the messages it was sent
+type stubProvider struct {
+	reply    string
+	messages []Message
+}
+
+func (p *stubProvider) Complete(ctx context.Context, messages []Message) (string, error) {
+	p.messages = messages
+	return p.reply, nil
+}
+
+func TestSuggestBullets(t *testing.T) {
+	p := &stubProvider{reply: "Here are the bullets:\n\n1. Cut build times by 40% by caching dependencies\n- \"Led the migration to Go\"\n• **Mentored two engineers**\n"}
+	experience := &domain.Experience{JobTitle: "Engineer", Employer: "Initech", Description: "Worked on the build system."}
+
+	bullets, err := SuggestBullets(context.Background(), p, experience, "es")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cut build times by 40% by caching dependencies", "Led the migration to Go", "Mentored two engineers"}, bullets)
+	require.Len(t, p.messages, 2)
+	assert.Contains(t, p.messages[0].Content, `"es"`)
+	assert.Contains(t, p.messages[1].Content, "Description: Worked on the build system.")
+
+	p.reply = "Sorry, I can't help with that:"
+	_, err = SuggestBullets(context.Background(), p, experience, "en")
+	assert.ErrorIs(t, err, ErrProvider)
+}
+
+func TestParseBulletsCapsCount(t *testing.T) {
+	assert.Len(t, parseBullets("a\nb\nc\nd\ne\nf\ng"), MaxBullets)
+}
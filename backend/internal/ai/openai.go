@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxOpenAIResponseSize caps the completion responses read
+const maxOpenAIResponseSize = 1 << 20
+
+// OpenAIConfig contains the configuration for an OpenAI-compatible chat
+// completions API, such as OpenAI's own, Azure OpenAI, Ollama or vLLM
+type OpenAIConfig struct {
+	// BaseURL is the API root the /chat/completions path is appended to;
+	// OpenAI's when empty
+	BaseURL string
+	// APIKey is sent as a bearer token when set
+	APIKey string
+	Model  string
+	// Temperature is the sampling temperature; the API default when zero
+	Temperature float64
+	// HTTPClient makes the requests; a client with a 30s timeout is used
+	// when nil
+	HTTPClient *http.Client
+}
+
+// OpenAIProvider completes conversations with an OpenAI-compatible chat
+// completions API
+type OpenAIProvider struct {
+	config OpenAIConfig
+}
+
+// NewOpenAIProvider creates a new OpenAI-compatible provider
+func NewOpenAIProvider(config OpenAIConfig) *OpenAIProvider {
+	// Set defaults if not provided
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.openai.com/v1"
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &OpenAIProvider{
+		config: config,
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete sends the messages to the chat completions endpoint and returns
+// the first choice
+func (p *OpenAIProvider) Complete(ctx context.Context, messages []Message) (string, error) {
+	body := chatRequest{Model: p.config.Model, Temperature: p.config.Temperature}
+	for _, m := range messages {
+		body.Messages = append(body.Messages, chatMessage{Role: m.Role, Content: m.Content})
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	resp, err := p.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOpenAIResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	var reply chatResponse
+	decodeErr := json.Unmarshal(data, &reply)
+	if resp.StatusCode != http.StatusOK {
+		if reply.Error != nil && reply.Error.Message != "" {
+			return "", fmt.Errorf("%w: %s returned %d: %s", ErrProvider, req.URL.Host, resp.StatusCode, reply.Error.Message)
+		}
+		return "", fmt.Errorf("%w: %s returned %d", ErrProvider, req.URL.Host, resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("%w: %v", ErrProvider, decodeErr)
+	}
+	if len(reply.Choices) == 0 {
+		return "", fmt.Errorf("%w: no choices", ErrProvider)
+	}
+	return reply.Choices[0].Message.Content, nil
+}
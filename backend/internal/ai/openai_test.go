@@ -0,0 +1,41 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProviderComplete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "test-model", req.Model)
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, RoleSystem, req.Messages[0].Role)
+
+		if req.Messages[1].Content == "fail" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"message": "Rate limit reached"}}`))
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "Hello"}}]}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider(OpenAIConfig{BaseURL: server.URL + "/v1/", APIKey: "key", Model: "test-model"})
+	reply, err := p.Complete(context.Background(), []Message{{Role: RoleSystem, Content: "Be brief"}, {Role: RoleUser, Content: "Hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", reply)
+
+	_, err = p.Complete(context.Background(), []Message{{Role: RoleSystem, Content: "Be brief"}, {Role: RoleUser, Content: "fail"}})
+	assert.ErrorIs(t, err, ErrProvider)
+	assert.ErrorContains(t, err, "returned 429: Rate limit reached")
+}
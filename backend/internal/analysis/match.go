@@ -0,0 +1,282 @@
+// Package analysis compares resumes against job descriptions the way
+// applicant tracking systems screen them: by the keywords they share.
+package analysis
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// MaxKeywords caps the keywords taken from a job description, most
+// frequent first
+const MaxKeywords = 30
+
+// Match is how well a resume covers the keywords of a job description
+type Match struct {
+	// Score is the share of the job description's keywords the resume
+	// mentions, from 0 to 100. Keywords count for how often the job
+	// description repeats them, up to three times.
+	Score   int       `json:"score"`
+	Matched []Keyword `json:"matched"`
+	Missing []Keyword `json:"missing"`
+}
+
+// Keyword is a word or phrase of a job description
+type Keyword struct {
+	Term string `json:"term"`
+	// Count is how often the job description mentions the term
+	Count int `json:"count"`
+}
+
+var (
+	// segmentRe splits text where phrases end, leaving dots inside words
+	// such as "node.js" alone
+	segmentRe = regexp.MustCompile(`[,;:!?()\[\]{}"\n•|/]|\.(?:\s|$)|\s[-–—]\s`)
+	wordRe    = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}+#.]*`)
+)
+
+// keyword is a keyword being counted
+type keyword struct {
+	Keyword
+	key   string
+	first int
+}
+
+// MatchJobDescription compares a complete resume with a job description.
+// Keywords are the words of the job description that aren't stop words or
+// recruiting boilerplate, the two-word phrases it repeats, and the resume's
+// own skills when the job description names them. Plurals match their
+// singular.
+func MatchJobDescription(resume *domain.Resume, jobDescription string) *Match {
+	keywords := jobKeywords(jobDescription, resumeSkills(resume))
+	mentioned := termKeys(resumeText(resume))
+	for _, skill := range resumeSkills(resume) {
+		mentioned[phraseKey(strings.Join(words(skill), " "))] = true
+	}
+
+	m := &Match{Matched: []Keyword{}, Missing: []Keyword{}}
+	var total, found float64
+	for _, k := range keywords {
+		weight := float64(min(k.Count, 3))
+		total += weight
+		if mentioned[k.key] {
+			found += weight
+			m.Matched = append(m.Matched, k.Keyword)
+		} else {
+			m.Missing = append(m.Missing, k.Keyword)
+		}
+	}
+	if total > 0 {
+		m.Score = int(math.Round(found / total * 100))
+	}
+	return m
+}
+
+// jobKeywords returns the keywords of a job description, most frequent first
+func jobKeywords(text string, skills []string) []keyword {
+	counts := make(map[string]*keyword)
+	add := func(key, term string, position int) {
+		if k, ok := counts[key]; ok {
+			k.Count++
+			return
+		}
+		counts[key] = &keyword{Keyword: Keyword{Term: term, Count: 1}, key: key, first: position}
+	}
+
+	// Words, and the pairs of adjacent words within a phrase
+	position := 0
+	bigrams := make(map[string]int)
+	for _, segment := range segmentRe.Split(text, -1) {
+		var previous, previousTerm string
+		for _, word := range words(segment) {
+			position++
+			key := stem(word)
+			if isStopWord(word) {
+				previous = ""
+				continue
+			}
+			add(key, word, position)
+			if previous != "" {
+				pair := previous + " " + key
+				add(pair, previousTerm+" "+word, position)
+				bigrams[pair]++
+			}
+			previous, previousTerm = key, word
+		}
+	}
+
+	// Pairs are keywords when repeated
+	phrases := make(map[string]int)
+	for key, n := range bigrams {
+		if n == 1 {
+			delete(counts, key)
+		} else {
+			phrases[key] = n
+		}
+	}
+
+	// and so are the multi-word skills of the resume the job description
+	// names
+	text = " " + strings.Join(words(text), " ") + " "
+	for _, skill := range skills {
+		term := strings.Join(words(skill), " ")
+		if !strings.Contains(term, " ") {
+			continue
+		}
+		if n := strings.Count(text, " "+term+" "); n > 0 {
+			key := phraseKey(term)
+			first := strings.Count(text[:strings.Index(text, " "+term+" ")], " ")
+			counts[key] = &keyword{Keyword: Keyword{Term: term, Count: n}, key: key, first: first}
+			phrases[key] = n
+		}
+	}
+
+	// The words and pairs within a phrase are not keywords when every use
+	// is within the phrase
+	for key, n := range phrases {
+		fields := strings.Fields(key)
+		for i := range fields {
+			for _, part := range []string{fields[i], strings.Join(fields[i:min(i+2, len(fields))], " ")} {
+				if k, ok := counts[part]; ok && part != key && k.Count <= n {
+					delete(counts, part)
+				}
+			}
+		}
+	}
+	var keywords []keyword
+	for _, k := range counts {
+		keywords = append(keywords, *k)
+	}
+	slices.SortFunc(keywords, func(a, b keyword) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return a.first - b.first
+	})
+	if len(keywords) > MaxKeywords {
+		keywords = keywords[:MaxKeywords]
+	}
+	return keywords
+}
+
+// termKeys returns the keys of the words of text and of every pair of
+// adjacent words, whatever the words
+func termKeys(text string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, segment := range segmentRe.Split(text, -1) {
+		previous := ""
+		for _, word := range words(segment) {
+			key := stem(word)
+			keys[key] = true
+			if previous != "" {
+				keys[previous+" "+key] = true
+			}
+			previous = key
+		}
+	}
+	return keys
+}
+
+// resumeText joins the text of every section of a resume
+func resumeText(r *domain.Resume) string {
+	var parts []string
+	add := func(s ...string) {
+		parts = append(parts, s...)
+	}
+	add(r.Title, r.TargetJobTitle)
+	if r.PersonalInfo != nil {
+		add(r.PersonalInfo.JobTitle)
+	}
+	for _, e := range r.Experience {
+		add(e.JobTitle, e.Employer, e.Description)
+		add(e.Achievements...)
+	}
+	for _, e := range r.Education {
+		add(e.Degree, e.Field, e.Institution, e.Description)
+	}
+	add(resumeSkills(r)...)
+	for _, p := range r.Projects {
+		add(p.Name, p.Description)
+		add(p.Technologies...)
+	}
+	for _, c := range r.Certifications {
+		add(c.Name, c.Issuer)
+	}
+	// Each part is a phrase of its own
+	return strings.Join(parts, "\n")
+}
+
+func resumeSkills(r *domain.Resume) []string {
+	skills := make([]string, 0, len(r.Skills))
+	for _, s := range r.Skills {
+		skills = append(skills, s.Name)
+	}
+	return skills
+}
+
+// words returns the lowercase words of text, without trailing dots
+func words(text string) []string {
+	var result []string
+	for _, word := range wordRe.FindAllString(strings.ToLower(text), -1) {
+		if word = strings.TrimRight(word, "."); word != "" {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+// phraseKey returns the key of a phrase, stemming each word
+func phraseKey(phrase string) string {
+	fields := strings.Fields(phrase)
+	for i, word := range fields {
+		fields[i] = stem(word)
+	}
+	return strings.Join(fields, " ")
+}
+
+// stem reduces a lowercase word to its singular
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !(len(word) > 4 && strings.HasSuffix(word, "is")):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// isStopWord reports whether a word carries no meaning for matching:
+// function words, numbers, single letters and the boilerplate of job ads
+func isStopWord(word string) bool {
+	if len(word) < 2 || strings.Trim(word, "0123456789+.") == "" {
+		return true
+	}
+	return stopWords[word] || stopWords[stem(word)]
+}
+
+var stopWords = make(map[string]bool)
+
+func init() {
+	for _, word := range strings.Fields(`
+		a about above across after again against all also am an and any are as at be because been before being
+		below between both but by can could did do does doing down during each either etc even ever every few for
+		from further had has have having he her here hers herself him himself his how i if in into is it its itself
+		just like many may me might more most much must my myself no nor not now of off on once only or other our
+		ours ourselves out over own per same shall she should so some such than that the their theirs them
+		themselves then there these they this those through to too under until up upon us very via was we well
+		were what when where which while who whom whose why will with within without would yet you your yours
+		yourself yourselves
+
+		ability able apply applicant benefit bonus candidate company compensation culture day degree desired
+		environment equal employer excellent experience familiarity first good great help highly ideal including
+		join job key knowledge looking make new nice offer opportunity paid plus position preferred proficiency
+		proven related relevant required requirement responsibility responsible role salary skill strong team
+		understanding use using want work working year years
+	`) {
+		stopWords[word] = true
+	}
+}
@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jobDescription = `Senior Backend Engineer
+
+We are looking for a backend engineer with strong experience in Go and PostgreSQL.
+You will design REST APIs, run services on Kubernetes and own our CI/CD pipelines.
+
+Requirements:
+- 5+ years of experience with Go
+- Experience with PostgreSQL and Redis
+- Familiarity with Kubernetes and Google Cloud Platform
+- Machine learning experience is a plus; machine learning pipelines run on Kubernetes`
+
+func testResume() *domain.Resume {
+	return &domain.Resume{
+		ResumeMetadata: domain.ResumeMetadata{Title: "Backend engineer"},
+		Experience: []*domain.Experience{{
+			JobTitle:     "Software Engineer",
+			Employer:     "Initech",
+			Description:  "Built REST APIs in Go backed by PostgreSQL.",
+			Achievements: []string{"Moved our services to Kubernetes"},
+		}},
+		Skills: []*domain.Skill{{Name: "Go"}, {Name: "Google Cloud Platform"}},
+	}
+}
+
+func terms(keywords []Keyword) []string {
+	result := make([]string, len(keywords))
+	for i, k := range keywords {
+		result[i] = k.Term
+	}
+	return result
+}
+
+func TestMatchJobDescription(t *testing.T) {
+	m := MatchJobDescription(testResume(), jobDescription)
+
+	matched := terms(m.Matched)
+	assert.Equal(t, []string{"kubernetes", "backend engineer", "go", "postgresql", "rest", "apis", "services", "google cloud platform"}, matched)
+	missing := terms(m.Missing)
+	assert.Contains(t, missing, "machine learning")
+	assert.Contains(t, missing, "redis")
+	assert.Contains(t, missing, "ci")
+	assert.NotContains(t, missing, "machine", "words within a repeated phrase are part of it")
+	assert.NotContains(t, matched, "backend")
+	assert.NotContains(t, missing, "experience")
+	assert.NotContains(t, missing, "5+")
+
+	require.NotEmpty(t, m.Matched)
+	assert.Equal(t, Keyword{Term: "kubernetes", Count: 3}, m.Matched[0])
+	assert.Greater(t, m.Score, 50)
+	assert.Less(t, m.Score, 100)
+}
+
+func TestMatchJobDescriptionScore(t *testing.T) {
+	assert.Equal(t, 100, MatchJobDescription(testResume(), "Go, Kubernetes and PostgreSQL").Score)
+	assert.Equal(t, 0, MatchJobDescription(testResume(), "Haskell and Erlang").Score)
+
+	// Repeated keywords weigh more, up to three mentions
+	m := MatchJobDescription(testResume(), "Go. Go. Go. Go. Go. Rust.")
+	assert.Equal(t, 75, m.Score)
+	assert.Equal(t, []Keyword{{Term: "go", Count: 5}}, m.Matched)
+
+	m = MatchJobDescription(testResume(), "The and of with")
+	assert.Equal(t, 0, m.Score)
+	assert.Empty(t, m.Matched)
+	assert.Empty(t, m.Missing)
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t, []string{"c++", "c#", "node.js", "and", "go"}, words("C++, C#, Node.js and Go."))
+	assert.Equal(t, "api", stem("apis"))
+	assert.Equal(t, "technology", stem("technologies"))
+	assert.Equal(t, "status", stem("status"))
+}
@@ -0,0 +1,211 @@
+package analysis
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// builtinRoles are the role profiles that ship with the service
+//
+//go:embed roles.json
+var builtinRoles []byte
+
+// BuiltinRoles returns the role profiles that ship with the service. Each
+// call returns new copies the caller may change.
+func BuiltinRoles() []*domain.RoleProfile {
+	var profiles []*domain.RoleProfile
+	if err := json.Unmarshal(builtinRoles, &profiles); err != nil {
+		panic(fmt.Sprintf("analysis: invalid roles.json: %v", err))
+	}
+	for _, p := range profiles {
+		p.Source = domain.RoleSourceBuiltin
+	}
+	return profiles
+}
+
+// Comparison is how a resume measures up to a role profile
+type Comparison struct {
+	Role     string `json:"role"`
+	RoleName string `json:"role_name"`
+	// Score is the share of the role's expected skills, sections and
+	// experience the resume shows, from 0 to 100
+	Score             int      `json:"score"`
+	MatchedSkills     []string `json:"matched_skills"`
+	MissingSkills     []string `json:"missing_skills"`
+	MissingNiceToHave []string `json:"missing_nice_to_have"`
+	MissingSections   []string `json:"missing_sections"`
+	// ExperienceYears is the time covered by the resume's experience
+	// entries, overlapping entries counted once
+	ExperienceYears    float64  `json:"experience_years"`
+	MinExperienceYears float64  `json:"min_experience_years"`
+	Recommendations    []string `json:"recommendations"`
+}
+
+// sectionLabels name sections in recommendations
+var sectionLabels = map[string]string{
+	domain.RoleSectionPersonalInfo:   "personal info",
+	domain.RoleSectionExperience:     "experience",
+	domain.RoleSectionEducation:      "education",
+	domain.RoleSectionSkills:         "skills",
+	domain.RoleSectionProjects:       "projects",
+	domain.RoleSectionCertifications: "certifications",
+}
+
+// CompareRole compares a complete resume with a role profile. A skill counts
+// when the resume mentions its name or an alias anywhere, not only among its
+// skills. Ongoing experience runs until now.
+func CompareRole(resume *domain.Resume, role *domain.RoleProfile, now time.Time) *Comparison {
+	c := &Comparison{
+		Role:               role.Slug,
+		RoleName:           role.Name,
+		MatchedSkills:      []string{},
+		MissingSkills:      []string{},
+		MissingNiceToHave:  []string{},
+		MissingSections:    []string{},
+		ExperienceYears:    experienceYears(resume.Experience, now),
+		MinExperienceYears: role.MinExperienceYears,
+		Recommendations:    []string{},
+	}
+
+	text := " " + strings.Join(stemAll(words(resumeText(resume))), " ") + " "
+	for _, skill := range role.Skills {
+		if mentionsSkill(text, skill) {
+			c.MatchedSkills = append(c.MatchedSkills, skill.Name)
+		} else {
+			c.MissingSkills = append(c.MissingSkills, skill.Name)
+		}
+	}
+	for _, skill := range role.NiceToHave {
+		if !mentionsSkill(text, skill) {
+			c.MissingNiceToHave = append(c.MissingNiceToHave, skill.Name)
+		}
+	}
+	for _, section := range role.Sections {
+		if !hasSection(resume, section) {
+			c.MissingSections = append(c.MissingSections, section)
+		}
+	}
+
+	total := len(role.Skills) + len(role.Sections)
+	found := len(c.MatchedSkills) + len(role.Sections) - len(c.MissingSections)
+	enoughExperience := c.ExperienceYears >= role.MinExperienceYears
+	if role.MinExperienceYears > 0 {
+		total++
+		if enoughExperience {
+			found++
+		}
+	}
+	if total > 0 {
+		c.Score = int(math.Round(float64(found) / float64(total) * 100))
+	}
+
+	for _, section := range c.MissingSections {
+		c.Recommendations = append(c.Recommendations, fmt.Sprintf("Add entries to the %s section.", sectionLabels[section]))
+	}
+	if len(c.MissingSkills) > 0 {
+		c.Recommendations = append(c.Recommendations, fmt.Sprintf("Mention %s if you have worked with them, in your skills or in the experience and projects that used them.", joinList(c.MissingSkills)))
+	}
+	if !enoughExperience {
+		c.Recommendations = append(c.Recommendations, fmt.Sprintf("The role expects %g years of experience and the resume shows %g; include internships, freelance and open-source work.", role.MinExperienceYears, c.ExperienceYears))
+	}
+	if len(c.MissingNiceToHave) > 0 {
+		c.Recommendations = append(c.Recommendations, fmt.Sprintf("Experience with %s would strengthen the resume for this role.", joinList(c.MissingNiceToHave)))
+	}
+	return c
+}
+
+// mentionsSkill reports whether text, stemmed words between spaces, has the
+// name or an alias of a skill
+func mentionsSkill(text string, skill domain.RoleSkill) bool {
+	for _, name := range append([]string{skill.Name}, skill.Aliases...) {
+		if term := strings.Join(stemAll(words(name)), " "); term != "" && strings.Contains(text, " "+term+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSection reports whether a resume has entries in a section
+func hasSection(r *domain.Resume, section string) bool {
+	switch section {
+	case domain.RoleSectionPersonalInfo:
+		return r.PersonalInfo != nil
+	case domain.RoleSectionExperience:
+		return len(r.Experience) > 0
+	case domain.RoleSectionEducation:
+		return len(r.Education) > 0
+	case domain.RoleSectionSkills:
+		return len(r.Skills) > 0
+	case domain.RoleSectionProjects:
+		return len(r.Projects) > 0
+	case domain.RoleSectionCertifications:
+		return len(r.Certifications) > 0
+	}
+	return false
+}
+
+// experienceYears returns the years covered by experience entries to one
+// decimal, merging overlaps. Entries with unreadable dates are skipped.
+func experienceYears(entries []*domain.Experience, now time.Time) float64 {
+	type span struct{ start, end time.Time }
+	var spans []span
+	for _, e := range entries {
+		start, err := time.Parse("2006-01-02", e.StartDate)
+		if err != nil {
+			continue
+		}
+		end := now
+		if e.EndDate != "" && e.EndDate != "Present" {
+			if end, err = time.Parse("2006-01-02", e.EndDate); err != nil {
+				continue
+			}
+		}
+		if end.After(start) {
+			spans = append(spans, span{start, end})
+		}
+	}
+	slices.SortFunc(spans, func(a, b span) int { return a.start.Compare(b.start) })
+
+	var total time.Duration
+	var current span
+	for i, s := range spans {
+		switch {
+		case i == 0:
+			current = s
+		case !s.start.After(current.end):
+			if s.end.After(current.end) {
+				current.end = s.end
+			}
+		default:
+			total += current.end.Sub(current.start)
+			current = s
+		}
+	}
+	if len(spans) > 0 {
+		total += current.end.Sub(current.start)
+	}
+	return math.Round(total.Hours()/24/365.25*10) / 10
+}
+
+// stemAll stems every word
+func stemAll(words []string) []string {
+	for i, word := range words {
+		words[i] = stem(word)
+	}
+	return words
+}
+
+// joinList joins items as "a, b and c"
+func joinList(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinRoles(t *testing.T) {
+	roles := BuiltinRoles()
+	require.NotEmpty(t, roles)
+	slugs := make(map[string]bool)
+	for _, role := range roles {
+		assert.NoError(t, role.Validate(), role.Slug)
+		assert.False(t, slugs[role.Slug], "duplicate slug %s", role.Slug)
+		slugs[role.Slug] = true
+		assert.Equal(t, domain.RoleSourceBuiltin, role.Source)
+	}
+	assert.True(t, slugs["backend-mid"])
+
+	// Callers get copies
+	roles[0].Name = "Changed"
+	assert.NotEqual(t, "Changed", BuiltinRoles()[0].Name)
+}
+
+func TestCompareRole(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	role := &domain.RoleProfile{
+		Slug: "backend-mid",
+		Name: "Backend Engineer — Mid",
+		Skills: []domain.RoleSkill{
+			{Name: "SQL", Aliases: []string{"PostgreSQL"}},
+			{Name: "CI/CD", Aliases: []string{"GitHub Actions"}},
+			{Name: "Docker"},
+		},
+		NiceToHave:         []domain.RoleSkill{{Name: "Kubernetes"}},
+		Sections:           []string{domain.RoleSectionExperience, domain.RoleSectionProjects},
+		MinExperienceYears: 3,
+	}
+	resume := &domain.Resume{
+		Experience: []*domain.Experience{
+			{StartDate: "2022-01-01", EndDate: "2023-01-01", Description: "Ran PostgreSQL and set up GitHub Actions."},
+			// Overlaps the first entry by six months
+			{StartDate: "2022-07-01", EndDate: "Present"},
+		},
+	}
+
+	c := CompareRole(resume, role, now)
+	assert.Equal(t, []string{"SQL", "CI/CD"}, c.MatchedSkills)
+	assert.Equal(t, []string{"Docker"}, c.MissingSkills)
+	assert.Equal(t, []string{"Kubernetes"}, c.MissingNiceToHave)
+	assert.Equal(t, []string{domain.RoleSectionProjects}, c.MissingSections)
+	assert.Equal(t, 3.0, c.ExperienceYears)
+	// Two of three skills, one of two sections and the experience
+	assert.Equal(t, 67, c.Score)
+	assert.Equal(t, []string{
+		"Add entries to the projects section.",
+		"Mention Docker if you have worked with them, in your skills or in the experience and projects that used them.",
+		"Experience with Kubernetes would strengthen the resume for this role.",
+	}, c.Recommendations)
+
+	c = CompareRole(&domain.Resume{}, role, now)
+	assert.Equal(t, 0, c.Score)
+	assert.Equal(t, 0.0, c.ExperienceYears)
+	assert.Contains(t, c.Recommendations, "The role expects 3 years of experience and the resume shows 0; include internships, freelance and open-source work.")
+}
@@ -0,0 +1,177 @@
+// Package apperror defines the errors HTTP handlers return and writes them as
+// RFC 9457 problem details (application/problem+json). Handlers return an
+// *Error carrying the status, a stable code clients can switch on and a
+// message safe to show; anything else they return is logged and reported as
+// an internal server error, so causes never leak into responses.
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/buildinfo"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// RequestIDHeader is the response header the request ID middleware sets; its
+// value is repeated in problem responses
+const RequestIDHeader = "X-Request-ID"
+
+// Codes used across many endpoints
+const (
+	CodeInvalidRequest   = "INVALID_REQUEST"
+	CodeValidation       = "VALIDATION_ERROR"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeLimitExceeded    = "LIMIT_EXCEEDED"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeNotFound         = "NOT_FOUND"
+	CodeInternal         = "INTERNAL_SERVER_ERROR"
+)
+
+// Error is an error with the HTTP status and code it is reported with
+type Error struct {
+	Status int
+	Code   string
+	// Message is shown to the client
+	Message string
+	// Details are extra members of the problem, such as the invalid fields
+	Details map[string]any
+	// Err is the cause, logged for server errors but never shown
+	Err error
+}
+
+// New creates an error reported with the given status, code and message
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Wrap creates an error reported with the given status, code and message
+// that keeps err as its cause
+func Wrap(err error, status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message, Err: err}
+}
+
+// Internal creates a 500 error with the given message that keeps err as
+// its cause
+func Internal(err error, message string) *Error {
+	return Wrap(err, http.StatusInternalServerError, CodeInternal, message)
+}
+
+// WithDetails returns a copy of the error with details added to the problem
+func (e *Error) WithDetails(details map[string]any) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// Error returns the message, followed by the cause if there is one
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// From returns err as an *Error. Domain validation and limit errors keep
+// their message, as it describes the client's input; anything else becomes
+// an internal error.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return Wrap(err, http.StatusBadRequest, CodeValidation, validationErr.Error())
+	}
+
+	var limitErr *domain.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return Wrap(err, http.StatusUnprocessableEntity, CodeLimitExceeded, "Resume "+limitErr.Error()).WithDetails(map[string]any{
+			"section": limitErr.Section,
+			"limit":   limitErr.Limit,
+		})
+	}
+
+	return Internal(err, "Internal server error")
+}
+
+// Problem is the RFC 9457 problem details body of an error response
+type Problem struct {
+	// Type is "about:blank": the status and code identify the problem
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	// Instance is the path of the request
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	// Error repeats Detail for clients written against the earlier error body
+	Error   string         `json:"error"`
+	Details map[string]any `json:"details,omitempty"`
+	// Build identifies the server build in server error responses so bug reports can reference it
+	Build string `json:"build,omitempty"`
+	// RequestID identifies the request in the server logs
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Problem returns the problem details of the error
+func (e *Error) Problem() Problem {
+	problem := Problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(e.Status),
+		Status:  e.Status,
+		Detail:  e.Message,
+		Code:    e.Code,
+		Error:   e.Message,
+		Details: e.Details,
+	}
+	if e.Status >= http.StatusInternalServerError {
+		problem.Build = buildinfo.ShortCommit()
+	}
+	return problem
+}
+
+// Write writes err as a problem response. Server errors are logged with
+// their cause, through the request's logger when r is not nil.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	appErr := From(err)
+
+	problem := appErr.Problem()
+	problem.RequestID = w.Header().Get(RequestIDHeader)
+
+	logger := &log.Logger
+	if r != nil {
+		problem.Instance = r.URL.Path
+		logger = zerolog.Ctx(r.Context())
+	}
+	if appErr.Status >= http.StatusInternalServerError && appErr.Err != nil {
+		logger.Error().Err(appErr.Err).Str("code", appErr.Code).Msg(appErr.Message)
+	}
+
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		logger.Error().Err(marshalErr).Msg("Failed to marshal problem response")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(appErr.Status)
+	if _, err := w.Write(body); err != nil {
+		logger.Error().Err(err).Msg("Failed to write problem response")
+	}
+}
@@ -0,0 +1,72 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrom(t *testing.T) {
+	notFound := New(http.StatusNotFound, CodeNotFound, "Resume not found")
+	assert.Same(t, notFound, From(joined(notFound)))
+
+	validation := From(domain.NewValidationError("email", "Email is required", domain.ErrInvalidField))
+	assert.Equal(t, http.StatusBadRequest, validation.Status)
+	assert.Equal(t, CodeValidation, validation.Code)
+	assert.Equal(t, "email: Email is required", validation.Message)
+
+	limit := From(&domain.LimitExceededError{Section: "skills", Limit: 100})
+	assert.Equal(t, http.StatusUnprocessableEntity, limit.Status)
+	assert.Equal(t, CodeLimitExceeded, limit.Code)
+	assert.Equal(t, map[string]any{"section": "skills", "limit": 100}, limit.Details)
+
+	internal := From(errors.New("connection refused"))
+	assert.Equal(t, http.StatusInternalServerError, internal.Status)
+	assert.Equal(t, CodeInternal, internal.Code)
+	assert.NotContains(t, internal.Message, "connection refused")
+}
+
+func joined(err error) error {
+	return errors.Join(errors.New("context"), err)
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set(RequestIDHeader, "req-1")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resumes/42", nil)
+
+	Write(rec, req, New(http.StatusNotFound, CodeNotFound, "Resume not found"))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, Problem{
+		Type:      "about:blank",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "Resume not found",
+		Instance:  "/api/v1/resumes/42",
+		Code:      CodeNotFound,
+		Error:     "Resume not found",
+		RequestID: "req-1",
+	}, problem)
+}
+
+func TestWriteHidesCause(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Write(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.New("pq: password authentication failed"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "password")
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, CodeInternal, problem.Code)
+	assert.NotEmpty(t, problem.Build)
+}
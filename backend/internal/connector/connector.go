@@ -0,0 +1,80 @@
+// Package connector fetches resume entries from accounts at external
+// sources, such as a user's GitHub repositories or Credly badges, for
+// syncing into a resume.
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// maxResponseSize caps the API responses read
+const maxResponseSize = 4 << 20
+
+var (
+	// ErrAccountNotFound is returned when the source has no such account
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrUnavailable wraps failures to reach the source or read its reply
+	ErrUnavailable = errors.New("source unavailable")
+)
+
+// accountRe matches the account names the sources accept, which go into
+// request paths
+var accountRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+
+// ValidAccount reports whether an account name is well-formed
+func ValidAccount(account string) bool {
+	return accountRe.MatchString(account)
+}
+
+// Entry is a resume entry as its source has it
+type Entry struct {
+	// Entity is the section of the entry, with the entities of the event log
+	Entity string
+	// SourceRef identifies the entry at the source across syncs
+	SourceRef string
+	Value     any
+}
+
+// Source fetches the entries of an account
+type Source interface {
+	Fetch(ctx context.Context, account string) ([]Entry, error)
+}
+
+// getJSON fetches url and decodes its JSON body into v. A 404 is
+// ErrAccountNotFound.
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrAccountNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w: %s returned %d", ErrUnavailable, req.URL.Host, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return nil
+}
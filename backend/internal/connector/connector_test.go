@@ -0,0 +1,87 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/users/octocat/repos":
+			w.Write([]byte(`[
+				{"id": 1, "name": "hello", "description": "Says hello", "html_url": "https://github.com/octocat/hello", "language": "Go", "topics": ["cli", "go"], "created_at": "2021-03-04T05:06:07Z"},
+				{"id": 2, "name": "forked", "fork": true}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	g := NewGitHub(GitHubConfig{BaseURL: srv.URL, Token: "token"})
+
+	entries, err := g.Fetch(context.Background(), "octocat")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.EventEntityProject, entries[0].Entity)
+	assert.Equal(t, "repo/1", entries[0].SourceRef)
+	assert.Equal(t, &domain.Project{
+		Name:         "hello",
+		Description:  "Says hello",
+		Technologies: []string{"cli", "go"},
+		RepoURL:      "https://github.com/octocat/hello",
+		StartDate:    "2021-03-04",
+	}, entries[0].Value)
+
+	_, err = g.Fetch(context.Background(), "nobody")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestCredlyFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/jane/badges.json" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data": [{
+			"id": "abc-123",
+			"issued_at_date": "2023-01-02",
+			"expires_at_date": null,
+			"badge_template": {"name": "Certified Kubernetes Administrator"},
+			"issuer": {"entities": [{"entity": {"name": "The Linux Foundation"}}]}
+		}]}`))
+	}))
+	defer srv.Close()
+	c := NewCredly(CredlyConfig{BaseURL: srv.URL})
+
+	entries, err := c.Fetch(context.Background(), "jane")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "badge/abc-123", entries[0].SourceRef)
+	assert.Equal(t, &domain.Certification{
+		Name:         "Certified Kubernetes Administrator",
+		Issuer:       "The Linux Foundation",
+		IssueDate:    "2023-01-02",
+		CredentialID: "abc-123",
+		URL:          srv.URL + "/badges/abc-123",
+	}, entries[0].Value)
+
+	_, err = c.Fetch(context.Background(), "down")
+	assert.True(t, errors.Is(err, ErrUnavailable))
+}
+
+func TestValidAccount(t *testing.T) {
+	assert.True(t, ValidAccount("octocat"))
+	assert.True(t, ValidAccount("jane-doe.2"))
+	assert.False(t, ValidAccount(""))
+	assert.False(t, ValidAccount("../admin"))
+	assert.False(t, ValidAccount("a/b"))
+}
@@ -0,0 +1,90 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// CredlyConfig contains the configuration for the Credly connector
+type CredlyConfig struct {
+	// BaseURL is the site root; Credly's when empty
+	BaseURL string
+	// HTTPClient makes the requests; a client with a 30s timeout is used
+	// when nil
+	HTTPClient *http.Client
+}
+
+// Credly syncs a user's public badges as certifications
+type Credly struct {
+	config CredlyConfig
+}
+
+// NewCredly creates a new Credly connector
+func NewCredly(config CredlyConfig) *Credly {
+	// Set defaults if not provided
+	if config.BaseURL == "" {
+		config.BaseURL = "https://www.credly.com"
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &Credly{
+		config: config,
+	}
+}
+
+type credlyBadges struct {
+	Data []struct {
+		ID            string `json:"id"`
+		IssuedAtDate  string `json:"issued_at_date"`
+		ExpiresAtDate string `json:"expires_at_date"`
+		BadgeTemplate struct {
+			Name string `json:"name"`
+		} `json:"badge_template"`
+		Issuer struct {
+			Entities []struct {
+				Entity struct {
+					Name string `json:"name"`
+				} `json:"entity"`
+			} `json:"entities"`
+		} `json:"issuer"`
+	} `json:"data"`
+}
+
+// Fetch returns the account's public badges as certifications
+func (c *Credly) Fetch(ctx context.Context, account string) ([]Entry, error) {
+	var badges credlyBadges
+	endpoint := fmt.Sprintf("%s/users/%s/badges.json", c.config.BaseURL, url.PathEscape(account))
+	if err := getJSON(ctx, c.config.HTTPClient, endpoint, http.Header{}, &badges); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(badges.Data))
+	for _, badge := range badges.Data {
+		issuers := make([]string, 0, len(badge.Issuer.Entities))
+		for _, e := range badge.Issuer.Entities {
+			issuers = append(issuers, e.Entity.Name)
+		}
+		entries = append(entries, Entry{
+			Entity:    domain.EventEntityCertification,
+			SourceRef: "badge/" + badge.ID,
+			Value: &domain.Certification{
+				Name:         badge.BadgeTemplate.Name,
+				Issuer:       strings.Join(issuers, ", "),
+				IssueDate:    badge.IssuedAtDate,
+				ExpiryDate:   badge.ExpiresAtDate,
+				CredentialID: badge.ID,
+				URL:          fmt.Sprintf("%s/badges/%s", c.config.BaseURL, url.PathEscape(badge.ID)),
+			},
+		})
+	}
+	return entries, nil
+}
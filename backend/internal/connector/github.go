@@ -0,0 +1,102 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// GitHubConfig contains the configuration for the GitHub connector
+type GitHubConfig struct {
+	// BaseURL is the API root; GitHub's when empty
+	BaseURL string
+	// Token is sent as a bearer token when set, raising the rate limit
+	Token string
+	// HTTPClient makes the requests; a client with a 30s timeout is used
+	// when nil
+	HTTPClient *http.Client
+}
+
+// GitHub syncs a user's public repositories as projects
+type GitHub struct {
+	config GitHubConfig
+}
+
+// NewGitHub creates a new GitHub connector
+func NewGitHub(config GitHubConfig) *GitHub {
+	// Set defaults if not provided
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.github.com"
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &GitHub{
+		config: config,
+	}
+}
+
+type gitHubRepo struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	HTMLURL     string    `json:"html_url"`
+	Homepage    string    `json:"homepage"`
+	Language    string    `json:"language"`
+	Topics      []string  `json:"topics"`
+	Fork        bool      `json:"fork"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Fetch returns the account's own public repositories, forks excluded, as
+// projects, most recently pushed first
+func (g *GitHub) Fetch(ctx context.Context, account string) ([]Entry, error) {
+	header := http.Header{}
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if g.config.Token != "" {
+		header.Set("Authorization", "Bearer "+g.config.Token)
+	}
+
+	var repos []gitHubRepo
+	endpoint := fmt.Sprintf("%s/users/%s/repos?type=owner&sort=pushed&per_page=100", g.config.BaseURL, url.PathEscape(account))
+	if err := getJSON(ctx, g.config.HTTPClient, endpoint, header, &repos); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(repos))
+	for _, repo := range repos {
+		if repo.Fork {
+			continue
+		}
+
+		technologies := slices.Clone(repo.Topics)
+		if repo.Language != "" && !slices.ContainsFunc(technologies, func(t string) bool { return strings.EqualFold(t, repo.Language) }) {
+			technologies = append([]string{repo.Language}, technologies...)
+		}
+		project := &domain.Project{
+			Name:         repo.Name,
+			Description:  repo.Description,
+			Technologies: technologies,
+			RepoURL:      repo.HTMLURL,
+			DemoURL:      repo.Homepage,
+		}
+		if !repo.CreatedAt.IsZero() {
+			project.StartDate = repo.CreatedAt.Format("2006-01-02")
+		}
+
+		entries = append(entries, Entry{
+			Entity:    domain.EventEntityProject,
+			SourceRef: fmt.Sprintf("repo/%d", repo.ID),
+			Value:     project,
+		})
+	}
+	return entries, nil
+}
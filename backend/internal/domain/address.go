@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/lordaris/resume_generator/pkg/sanitize"
+)
+
+// Address is a postal address. Structured fields cover most countries;
+// FreeForm holds addresses that don't fit them and replaces them when set.
+type Address struct {
+	Street string `json:"street"`
+	// Line2 holds apartment, suite, floor or building details
+	Line2 string `json:"line2,omitempty"`
+	City  string `json:"city"`
+	// Region is the state, province or prefecture
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	// Country is an ISO 3166-1 alpha-2 code or a country name as the user wrote it
+	Country  string `json:"country"`
+	FreeForm string `json:"free_form,omitempty"`
+}
+
+// addressFormat holds the validation rules and layout of one country's addresses
+type addressFormat struct {
+	// names are lowercase names and alpha-3 codes the country is also written as
+	names      []string
+	postalCode *regexp.Regexp
+	layout     func(a *Address) []string
+}
+
+// addressFormats are keyed by ISO 3166-1 alpha-2 code. Countries without an
+// entry use the default layout and only the generic rules.
+var addressFormats = map[string]addressFormat{
+	"US": {names: []string{"usa", "united states", "united states of america"}, postalCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`), layout: cityRegionPostalLayout},
+	"CA": {names: []string{"can", "canada"}, postalCode: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`), layout: cityRegionPostalLayout},
+	"AU": {names: []string{"aus", "australia"}, postalCode: regexp.MustCompile(`^\d{4}$`), layout: cityRegionPostalLayout},
+	"MX": {names: []string{"mex", "mexico", "méxico"}, postalCode: regexp.MustCompile(`^\d{5}$`), layout: postalCityLayout},
+	"BR": {names: []string{"bra", "brazil", "brasil"}, postalCode: regexp.MustCompile(`^\d{5}-?\d{3}$`), layout: cityRegionPostalLayout},
+	"GB": {names: []string{"gbr", "uk", "united kingdom", "great britain"}, postalCode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`), layout: postalLastLayout},
+	"IE": {names: []string{"irl", "ireland"}, postalCode: regexp.MustCompile(`^[A-Z]\d[\dW] ?[A-Z\d]{4}$`), layout: postalLastLayout},
+	"DE": {names: []string{"deu", "germany", "deutschland"}, postalCode: regexp.MustCompile(`^\d{5}$`), layout: postalCityLayout},
+	"AT": {names: []string{"aut", "austria", "österreich"}, postalCode: regexp.MustCompile(`^\d{4}$`), layout: postalCityLayout},
+	"CH": {names: []string{"che", "switzerland", "schweiz", "suisse"}, postalCode: regexp.MustCompile(`^\d{4}$`), layout: postalCityLayout},
+	"FR": {names: []string{"fra", "france"}, postalCode: regexp.MustCompile(`^\d{5}$`), layout: postalCityLayout},
+	"ES": {names: []string{"esp", "spain", "españa"}, postalCode: regexp.MustCompile(`^\d{5}$`), layout: postalCityLayout},
+	"IT": {names: []string{"ita", "italy", "italia"}, postalCode: regexp.MustCompile(`^\d{5}$`), layout: postalCityLayout},
+	"NL": {names: []string{"nld", "netherlands", "the netherlands", "nederland"}, postalCode: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`), layout: postalCityLayout},
+	"PL": {names: []string{"pol", "poland", "polska"}, postalCode: regexp.MustCompile(`^\d{2}-\d{3}$`), layout: postalCityLayout},
+	"IN": {names: []string{"ind", "india"}, postalCode: regexp.MustCompile(`^\d{6}$`), layout: cityRegionPostalLayout},
+	"JP": {names: []string{"jpn", "japan", "日本"}, postalCode: regexp.MustCompile(`^\d{3}-?\d{4}$`), layout: japanLayout},
+	"CN": {names: []string{"chn", "china", "中国"}, postalCode: regexp.MustCompile(`^\d{6}$`), layout: largestFirstLayout},
+	"KR": {names: []string{"kor", "south korea", "korea", "대한민국"}, postalCode: regexp.MustCompile(`^\d{5}$`), layout: largestFirstLayout},
+}
+
+// countryAliases maps lowercase names and alpha-3 codes to alpha-2 codes
+var countryAliases = func() map[string]string {
+	aliases := make(map[string]string)
+	for code, format := range addressFormats {
+		for _, name := range format.names {
+			aliases[name] = code
+		}
+	}
+	return aliases
+}()
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of the address's country
+// when it is written as a code or a known name, and "" otherwise
+func (a *Address) CountryCode() string {
+	country := strings.TrimSpace(a.Country)
+	if code, ok := countryAliases[strings.ToLower(country)]; ok {
+		return code
+	}
+	if len(country) == 2 {
+		return strings.ToUpper(country)
+	}
+	return ""
+}
+
+// IsZero reports whether no address was given
+func (a *Address) IsZero() bool {
+	return *a == Address{}
+}
+
+// Validate validates the address against the generic and per-country rules
+func (a *Address) Validate() error {
+	if strings.TrimSpace(a.Country) == "" {
+		return NewValidationError("address.country", "Country is required if address is provided", ErrInvalidField)
+	}
+
+	// Free-form addresses are taken as written
+	if strings.TrimSpace(a.FreeForm) != "" {
+		return nil
+	}
+
+	if strings.TrimSpace(a.Street) == "" {
+		return NewValidationError("address.street", "Street is required if address is provided", ErrInvalidField)
+	}
+	if strings.TrimSpace(a.City) == "" {
+		return NewValidationError("address.city", "City is required if address is provided", ErrInvalidField)
+	}
+
+	format, ok := addressFormats[a.CountryCode()]
+	if !ok {
+		return nil
+	}
+	if a.PostalCode != "" && !format.postalCode.MatchString(strings.ToUpper(a.PostalCode)) {
+		return NewValidationError("address.postal_code", "Invalid postal code for this country", ErrInvalidField)
+	}
+
+	return nil
+}
+
+// BeforeSave sanitizes the address before saving
+func (a *Address) BeforeSave() {
+	a.Street = sanitize.Line(a.Street)
+	a.Line2 = sanitize.Line(a.Line2)
+	a.City = sanitize.Line(a.City)
+	a.Region = sanitize.Line(a.Region)
+	a.PostalCode = strings.ToUpper(sanitize.Line(a.PostalCode))
+	a.Country = sanitize.Line(a.Country)
+	a.FreeForm = sanitize.Text(a.FreeForm)
+}
+
+// Lines returns the address as display lines in the order and layout used in
+// its country, ending with the country. Empty parts are left out.
+func (a *Address) Lines() []string {
+	var lines []string
+	if strings.TrimSpace(a.FreeForm) != "" {
+		lines = strings.Split(a.FreeForm, "\n")
+	} else {
+		layout := defaultAddressLayout
+		if format, ok := addressFormats[a.CountryCode()]; ok {
+			layout = format.layout
+		}
+		lines = layout(a)
+	}
+	lines = append(lines, a.Country)
+
+	nonEmpty := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			nonEmpty = append(nonEmpty, line)
+		}
+	}
+	return nonEmpty
+}
+
+// joinParts joins the non-empty parts with sep
+func joinParts(sep string, parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
+
+// defaultAddressLayout writes the city, region and postal code on one line
+func defaultAddressLayout(a *Address) []string {
+	return []string{a.Street, a.Line2, joinParts(", ", a.City, a.Region, a.PostalCode)}
+}
+
+// cityRegionPostalLayout is the North American style "City, Region Postal"
+func cityRegionPostalLayout(a *Address) []string {
+	return []string{a.Street, a.Line2, joinParts(", ", a.City, joinParts(" ", a.Region, a.PostalCode))}
+}
+
+// postalCityLayout is the continental European style "Postal City"
+func postalCityLayout(a *Address) []string {
+	return []string{a.Street, a.Line2, joinParts(" ", a.PostalCode, a.City, a.Region)}
+}
+
+// postalLastLayout gives the city, region and postal code their own lines
+func postalLastLayout(a *Address) []string {
+	return []string{a.Street, a.Line2, a.City, a.Region, a.PostalCode}
+}
+
+// largestFirstLayout orders East Asian addresses from the largest area to the
+// smallest: postal code, region and city, then street and building
+func largestFirstLayout(a *Address) []string {
+	return []string{a.PostalCode, joinParts(" ", a.Region, a.City), joinParts(" ", a.Street, a.Line2)}
+}
+
+// japanLayout is the largest-first layout with the postal mark before the postal code
+func japanLayout(a *Address) []string {
+	lines := largestFirstLayout(a)
+	if a.PostalCode != "" {
+		lines[0] = "〒" + a.PostalCode
+	}
+	return lines
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressLines(t *testing.T) {
+	tests := []struct {
+		address Address
+		want    []string
+	}{
+		{
+			Address{Street: "1600 Amphitheatre Pkwy", City: "Mountain View", Region: "CA", PostalCode: "94043", Country: "USA"},
+			[]string{"1600 Amphitheatre Pkwy", "Mountain View, CA 94043", "USA"},
+		},
+		{
+			Address{Street: "Unter den Linden 1", Line2: "3. OG", City: "Berlin", PostalCode: "10117", Country: "DE"},
+			[]string{"Unter den Linden 1", "3. OG", "10117 Berlin", "DE"},
+		},
+		{
+			Address{Street: "10 Downing Street", City: "London", PostalCode: "SW1A 2AA", Country: "United Kingdom"},
+			[]string{"10 Downing Street", "London", "SW1A 2AA", "United Kingdom"},
+		},
+		{
+			Address{Street: "1-1 Chiyoda", City: "Chiyoda-ku", Region: "Tokyo", PostalCode: "100-8111", Country: "Japan"},
+			[]string{"〒100-8111", "Tokyo Chiyoda-ku", "1-1 Chiyoda", "Japan"},
+		},
+		{
+			Address{Street: "Calle 1", City: "Lima", Country: "Peru"},
+			[]string{"Calle 1", "Lima", "Peru"},
+		},
+		{
+			Address{FreeForm: "Finca El Sol\nKm 12 Carretera Norte", Country: "NI"},
+			[]string{"Finca El Sol", "Km 12 Carretera Norte", "NI"},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.address.Lines())
+	}
+}
+
+func TestAddressValidate(t *testing.T) {
+	valid := []Address{
+		{Street: "1 Main St", City: "Springfield", PostalCode: "12345-6789", Country: "us"},
+		{Street: "1 Rue de Rivoli", City: "Paris", PostalCode: "75001", Country: "France"},
+		{Street: "1 Main St", City: "Springfield", Country: "USA"},
+		{FreeForm: "Somewhere", Country: "Atlantis"},
+	}
+	for _, address := range valid {
+		assert.NoError(t, address.Validate(), "address %+v", address)
+	}
+
+	invalid := map[string]Address{
+		"address.country":     {Street: "1 Main St", City: "Springfield"},
+		"address.street":      {City: "Springfield", Country: "US"},
+		"address.postal_code": {Street: "1 Rue de Rivoli", City: "Paris", PostalCode: "7500", Country: "FR"},
+	}
+	for field, address := range invalid {
+		err := address.Validate()
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr), "field %s", field)
+		assert.Equal(t, field, validationErr.Field)
+	}
+}
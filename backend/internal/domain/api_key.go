@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// API key scopes
+const (
+	ScopeResumesRead  = "resumes:read"
+	ScopeResumesWrite = "resumes:write"
+)
+
+// APIKeyScopes lists the scopes an API key can be granted
+var APIKeyScopes = []string{
+	ScopeResumesRead,
+	ScopeResumesWrite,
+}
+
+// MaxAPIKeyNameLength caps the name users give a key
+const MaxAPIKeyNameLength = 100
+
+// APIKey is a personal access token letting scripts call the API as its user
+// within its scopes
+type APIKey struct {
+	ID     uuid.UUID `json:"id" db:"id"`
+	UserID uuid.UUID `json:"-" db:"user_id"`
+	Name   string    `json:"name" db:"name"`
+	// Prefix is the start of the key, shown to tell keys apart
+	Prefix string `json:"prefix" db:"prefix"`
+	// KeyHash is the hex SHA-256 hash of the key
+	KeyHash string   `json:"-" db:"key_hash"`
+	Scopes  []string `json:"scopes" db:"scopes"`
+	// ExpiresAt stops the key working at that time when set
+	ExpiresAt  time.Time `json:"expires_at,omitzero" db:"expires_at"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero" db:"last_used_at"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Expired reports whether the key has passed its expiry at now
+func (k *APIKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// HasScope reports whether the key was granted a scope
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// Validate validates the key's name and expiry
+func (k *APIKey) Validate() error {
+	name := strings.TrimSpace(k.Name)
+	if name == "" {
+		return NewValidationError("name", "Name is required", ErrInvalidField)
+	}
+	if utf8.RuneCountInString(name) > MaxAPIKeyNameLength {
+		return NewValidationError("name", "Name cannot be longer than 100 characters", ErrInvalidField)
+	}
+	if !k.ExpiresAt.IsZero() && !k.ExpiresAt.After(time.Now()) {
+		return NewValidationError("expires_at", "Expiry must be in the future", ErrInvalidField)
+	}
+	return nil
+}
+
+// NormalizeAPIKeyScopes checks that every entry names a known scope and
+// returns them sorted without duplicates. At least one scope is required.
+func NormalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	return normalizeScopes(scopes, APIKeyScopes)
+}
+
+// normalizeScopes checks that every entry is among allowed and returns them
+// sorted without duplicates. At least one scope is required.
+func normalizeScopes(scopes, allowed []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(allowed, scope) {
+			return nil, NewValidationError("scopes", fmt.Sprintf("Scopes must be among: %s", strings.Join(allowed, ", ")), ErrInvalidField)
+		}
+		normalized = append(normalized, scope)
+	}
+	if len(normalized) == 0 {
+		return nil, NewValidationError("scopes", "At least one scope is required", ErrInvalidField)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+// APIKeyRepository defines the interface for API key data operations
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*APIKey, error)
+	CountAPIKeysByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	// TouchAPIKey records when a key was last used
+	TouchAPIKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	DeleteAPIKey(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// APIKeyUsage counts the requests made with an API key in an hour
+type APIKeyUsage struct {
+	APIKeyID uuid.UUID `json:"-" db:"api_key_id"`
+	// Hour is the start of the hour, UTC
+	Hour     time.Time `json:"hour" db:"hour"`
+	Requests int64     `json:"requests" db:"requests"`
+}
+
+// APIKeyUsageRepository defines the interface for API key usage operations
+type APIKeyUsageRepository interface {
+	// GetAPIKeyIDsUsedSince returns the keys last used at or after since
+	GetAPIKeyIDsUsedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error)
+	// SaveAPIKeyUsage stores the count of an hour, replacing any stored one
+	SaveAPIKeyUsage(ctx context.Context, usage *APIKeyUsage) error
+	// GetAPIKeyUsage returns the hourly counts of a key from since on, oldest first
+	GetAPIKeyUsage(ctx context.Context, keyID uuid.UUID, since time.Time) ([]*APIKeyUsage, error)
+	// DeleteAPIKeyUsageBefore deletes the counts of hours before a time
+	DeleteAPIKeyUsageBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -5,6 +5,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/lordaris/resume_generator/pkg/sanitize"
 )
 
 // Certification represents a certification entry in a resume
@@ -63,12 +65,12 @@ func (c *Certification) Validate() error {
 
 // BeforeSave sanitizes the data before saving
 func (c *Certification) BeforeSave() {
-	c.Name = strings.TrimSpace(c.Name)
-	c.Issuer = strings.TrimSpace(c.Issuer)
-	c.IssueDate = strings.TrimSpace(c.IssueDate)
-	c.ExpiryDate = strings.TrimSpace(c.ExpiryDate)
-	c.CredentialID = strings.TrimSpace(c.CredentialID)
-	c.URL = strings.TrimSpace(c.URL)
+	c.Name = sanitize.Line(c.Name)
+	c.Issuer = sanitize.Line(c.Issuer)
+	c.IssueDate = sanitize.Line(c.IssueDate)
+	c.ExpiryDate = sanitize.Line(c.ExpiryDate)
+	c.CredentialID = sanitize.Line(c.CredentialID)
+	c.URL = sanitize.Line(c.URL)
 }
 
 // ToJSON converts the certification entry to JSON
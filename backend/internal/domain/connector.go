@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Connector providers
+const (
+	ConnectorGitHub = "github"
+	ConnectorCredly = "credly"
+)
+
+// Connector links an account at a source, such as a GitHub user, to a resume
+// whose entries are synced from it
+type Connector struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	ResumeID uuid.UUID `json:"resume_id" db:"resume_id"`
+	// Provider is the source, which is also the import source of the
+	// entries it syncs
+	Provider string `json:"provider" db:"provider"`
+	// Account is the user's name at the provider
+	Account      string     `json:"account" db:"account"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty" db:"last_synced_at"`
+	// Conflicts are the entries the last sync found changed both locally and
+	// upstream, awaiting resolution
+	Conflicts []*ConnectorConflict `json:"conflicts" db:"conflicts"`
+	CreatedAt time.Time            `json:"created_at" db:"created_at"`
+}
+
+// ConnectorConflict is an entry edited locally since it was last synced that
+// also changed at its source. Base is the value both sides started from.
+type ConnectorConflict struct {
+	Entity    string          `json:"entity"`
+	EntityID  uuid.UUID       `json:"entity_id"`
+	SourceRef string          `json:"source_ref"`
+	Base      json.RawMessage `json:"base"`
+	Local     json.RawMessage `json:"local"`
+	Upstream  json.RawMessage `json:"upstream"`
+	// Fields lists the fields where local and upstream differ
+	Fields []string `json:"fields"`
+}
+
+// ConnectorRepository defines the interface for connector operations
+type ConnectorRepository interface {
+	CreateConnector(ctx context.Context, connector *Connector) error
+	GetConnector(ctx context.Context, id uuid.UUID) (*Connector, error)
+	GetConnectorsByResume(ctx context.Context, resumeID uuid.UUID) ([]*Connector, error)
+	// UpdateConnectorSync stores the time and conflicts of a connector's sync
+	UpdateConnectorSync(ctx context.Context, connector *Connector) error
+	DeleteConnector(ctx context.Context, id uuid.UUID) error
+	DeleteConnectorsByResume(ctx context.Context, resumeID uuid.UUID) error
+}
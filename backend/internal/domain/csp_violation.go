@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sort fields accepted by the CSP violation list
+const (
+	SortByCount      = "count"
+	SortByLastSeenAt = "last_seen_at"
+)
+
+// CSPViolation counts the Content-Security-Policy violation reports received
+// for one page, directive, blocked source and disposition
+type CSPViolation struct {
+	ID                uuid.UUID `json:"id" db:"id"`
+	DocumentURI       string    `json:"document_uri" db:"document_uri"`
+	ViolatedDirective string    `json:"violated_directive" db:"violated_directive"`
+	BlockedURI        string    `json:"blocked_uri" db:"blocked_uri"`
+	Disposition       string    `json:"disposition" db:"disposition"`
+	// SourceFile, LineNumber and ColumnNumber locate the code behind the
+	// latest report, when the browser sent them
+	SourceFile   string    `json:"source_file,omitempty" db:"source_file"`
+	LineNumber   int       `json:"line_number,omitempty" db:"line_number"`
+	ColumnNumber int       `json:"column_number,omitempty" db:"column_number"`
+	Count        int64     `json:"count" db:"count"`
+	FirstSeenAt  time.Time `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt   time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// CSPViolationRepository defines the interface for CSP violation data operations
+type CSPViolationRepository interface {
+	// RecordViolation counts a report, adding it to the matching violation
+	// or creating one
+	RecordViolation(ctx context.Context, violation *CSPViolation) error
+	// ListViolations returns a page of violations and the total matching the
+	// filters. CreatedAfter and CreatedBefore select violations seen in that
+	// period; directive limits the list to one directive unless empty.
+	ListViolations(ctx context.Context, directive string, opts ListOptions) ([]*CSPViolation, int64, error)
+	// DeleteViolationsSeenBefore removes violations not reported since before
+	DeleteViolationsSeenBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"time"
+
+	"github.com/lordaris/resume_generator/pkg/sanitize"
 )
 
 // Education represents an education entry in a resume
@@ -56,13 +58,13 @@ func (e *Education) Validate() error {
 
 // BeforeSave sanitizes the data before saving
 func (e *Education) BeforeSave() {
-	e.Institution = strings.TrimSpace(e.Institution)
-	e.Location = strings.TrimSpace(e.Location)
-	e.Degree = strings.TrimSpace(e.Degree)
-	e.Field = strings.TrimSpace(e.Field)
-	e.StartDate = strings.TrimSpace(e.StartDate)
-	e.EndDate = strings.TrimSpace(e.EndDate)
-	e.Description = strings.TrimSpace(e.Description)
+	e.Institution = sanitize.Line(e.Institution)
+	e.Location = sanitize.Line(e.Location)
+	e.Degree = sanitize.Line(e.Degree)
+	e.Field = sanitize.Line(e.Field)
+	e.StartDate = sanitize.Line(e.StartDate)
+	e.EndDate = sanitize.Line(e.EndDate)
+	e.Description = sanitize.Text(e.Description)
 }
 
 // ToJSON converts the education entry to JSON
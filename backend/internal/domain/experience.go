@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"time"
+
+	"github.com/lordaris/resume_generator/pkg/sanitize"
 )
 
 // Experience represents a work experience entry in a resume
@@ -56,16 +58,16 @@ func (e *Experience) Validate() error {
 
 // BeforeSave sanitizes the data before saving
 func (e *Experience) BeforeSave() {
-	e.Employer = strings.TrimSpace(e.Employer)
-	e.JobTitle = strings.TrimSpace(e.JobTitle)
-	e.Location = strings.TrimSpace(e.Location)
-	e.StartDate = strings.TrimSpace(e.StartDate)
-	e.EndDate = strings.TrimSpace(e.EndDate)
-	e.Description = strings.TrimSpace(e.Description)
+	e.Employer = sanitize.Line(e.Employer)
+	e.JobTitle = sanitize.Line(e.JobTitle)
+	e.Location = sanitize.Line(e.Location)
+	e.StartDate = sanitize.Line(e.StartDate)
+	e.EndDate = sanitize.Line(e.EndDate)
+	e.Description = sanitize.Text(e.Description)
 
-	// Trim achievements
+	// Sanitize achievements
 	for i, achievement := range e.Achievements {
-		e.Achievements[i] = strings.TrimSpace(achievement)
+		e.Achievements[i] = sanitize.Line(achievement)
 	}
 
 	// Remove empty achievements
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Incident statuses
+const (
+	IncidentStatusInvestigating = "investigating"
+	IncidentStatusIdentified    = "identified"
+	IncidentStatusMonitoring    = "monitoring"
+	IncidentStatusResolved      = "resolved"
+)
+
+// Incident impact levels
+const (
+	IncidentImpactMinor    = "minor"
+	IncidentImpactMajor    = "major"
+	IncidentImpactCritical = "critical"
+)
+
+// ValidIncidentStatuses defines valid incident statuses
+var ValidIncidentStatuses = map[string]bool{
+	IncidentStatusInvestigating: true,
+	IncidentStatusIdentified:    true,
+	IncidentStatusMonitoring:    true,
+	IncidentStatusResolved:      true,
+}
+
+// ValidIncidentImpacts defines valid incident impact levels
+var ValidIncidentImpacts = map[string]bool{
+	IncidentImpactMinor:    true,
+	IncidentImpactMajor:    true,
+	IncidentImpactCritical: true,
+}
+
+// Incident represents a service incident shown on the status page
+type Incident struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	Status      string    `json:"status" db:"status"`
+	Impact      string    `json:"impact" db:"impact"`
+	StartedAt   time.Time `json:"started_at" db:"started_at"`
+	ResolvedAt  time.Time `json:"resolved_at,omitzero" db:"resolved_at"`
+	CreatedBy   uuid.UUID `json:"-" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate validates the incident
+func (i *Incident) Validate() error {
+	if strings.TrimSpace(i.Title) == "" {
+		return NewValidationError("title", "Title is required", ErrInvalidField)
+	}
+	if len(i.Title) > 200 {
+		return NewValidationError("title", "Title must be at most 200 characters", ErrInvalidField)
+	}
+	if !ValidIncidentStatuses[i.Status] {
+		return NewValidationError("status", "Status must be one of: investigating, identified, monitoring, resolved", ErrInvalidField)
+	}
+	if !ValidIncidentImpacts[i.Impact] {
+		return NewValidationError("impact", "Impact must be one of: minor, major, critical", ErrInvalidField)
+	}
+
+	return nil
+}
+
+// BeforeSave sanitizes the data before saving and keeps the resolution time in step with the status
+func (i *Incident) BeforeSave() {
+	i.Title = strings.TrimSpace(i.Title)
+	i.Description = strings.TrimSpace(i.Description)
+
+	if i.Status == "" {
+		i.Status = IncidentStatusInvestigating
+	}
+	if i.Impact == "" {
+		i.Impact = IncidentImpactMinor
+	}
+
+	if i.Status == IncidentStatusResolved {
+		if i.ResolvedAt.IsZero() {
+			i.ResolvedAt = time.Now().UTC()
+		}
+	} else {
+		i.ResolvedAt = time.Time{}
+	}
+}
+
+// IncidentRepository defines the interface for incident data operations
+type IncidentRepository interface {
+	CreateIncident(ctx context.Context, incident *Incident) error
+	GetIncidentByID(ctx context.Context, id uuid.UUID) (*Incident, error)
+	UpdateIncident(ctx context.Context, incident *Incident) error
+	DeleteIncident(ctx context.Context, id uuid.UUID) error
+	// GetIncidentsSince returns unresolved incidents and those resolved after since, newest first
+	GetIncidentsSince(ctx context.Context, since time.Time) ([]*Incident, error)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Instance setting keys
+const (
+	// SettingSecurityHeaders holds the security header profile
+	SettingSecurityHeaders = "security_headers"
+)
+
+// InstanceSetting is a runtime setting of this deployment stored as JSON
+type InstanceSetting struct {
+	Key       string          `json:"key" db:"key"`
+	Value     json.RawMessage `json:"value" db:"value"`
+	UpdatedBy uuid.UUID       `json:"updated_by,omitzero" db:"updated_by"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// InstanceSettingRepository defines the interface for instance setting data operations
+type InstanceSettingRepository interface {
+	GetSetting(ctx context.Context, key string) (*InstanceSetting, error)
+	SaveSetting(ctx context.Context, setting *InstanceSetting) error
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Sort fields accepted by list queries
+const (
+	SortByCreatedAt = "created_at"
+	SortByTitle     = "title"
+	SortByEmail     = "email"
+)
+
+// ListOptions controls paging, ordering and filtering of list queries
+type ListOptions struct {
+	Limit  int
+	Offset int
+
+	// SortBy is one of the SortBy* fields supported by the list; created_at is used when empty
+	SortBy   string
+	SortDesc bool
+
+	// CreatedAfter and CreatedBefore bound the creation time; zero values are ignored
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// Search is a case-insensitive substring match on the list's text fields
+	Search string
+
+	// Tag limits the list to entries carrying the tag; ignored when empty
+	Tag string
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxPasskeyNameLength is the longest passkey name allowed, in characters
+const MaxPasskeyNameLength = 100
+
+// Passkey is a WebAuthn credential a user signs in with instead of a
+// password, or confirms password logins with
+type Passkey struct {
+	ID     uuid.UUID `json:"id" db:"id"`
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	// Name tells the user's passkeys apart, such as the device each is on
+	Name string `json:"name" db:"name"`
+	// CredentialID is the ID the authenticator chose for the credential
+	CredentialID []byte `json:"-" db:"credential_id"`
+	// Credential is the public key, sign count and flags of the credential,
+	// JSON-encoded as the WebAuthn library stores them
+	Credential json.RawMessage `json:"-" db:"credential"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time       `json:"last_used_at,omitzero" db:"last_used_at"`
+}
+
+// Validate checks the user-provided fields of a passkey
+func (p *Passkey) Validate() error {
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		return NewValidationError("name", "Name is required", ErrInvalidField)
+	}
+	if utf8.RuneCountInString(name) > MaxPasskeyNameLength {
+		return NewValidationError("name", "Name cannot be longer than 100 characters", ErrInvalidField)
+	}
+	return nil
+}
+
+// PasskeyRepository defines the interface for passkey operations
+type PasskeyRepository interface {
+	// CreatePasskey stores a passkey. It fails with a conflict when the
+	// credential is already registered.
+	CreatePasskey(ctx context.Context, passkey *Passkey) error
+	CountPasskeysByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]*Passkey, error)
+	// UpdatePasskeyCredential stores a credential's new sign count and
+	// flags after it was used at usedAt
+	UpdatePasskeyCredential(ctx context.Context, id uuid.UUID, credential json.RawMessage, usedAt time.Time) error
+	DeletePasskey(ctx context.Context, userID, id uuid.UUID) error
+	// SetPasskeyRequired sets whether the user's password logins must be
+	// confirmed with a passkey
+	SetPasskeyRequired(ctx context.Context, userID uuid.UUID, required bool) error
+}
@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"regexp"
 	"strings"
+
+	"github.com/lordaris/resume_generator/pkg/phone"
+	"github.com/lordaris/resume_generator/pkg/sanitize"
 )
 
 // EmailRegex is the regular expression for validating email addresses (RFC 5322)
 var EmailRegex = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
 
-// PhoneRegex is the regular expression for validating phone numbers (E.164 format)
+// PhoneRegex matches numbers already in E.164 format. They are accepted as-is
+// so numbers stored before national formats were parsed stay valid even when
+// they fall outside the ranges known to the phone number metadata.
 var PhoneRegex = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
 
 // PersonalInfo represents the personal information section of a resume
@@ -18,12 +23,17 @@ type PersonalInfo struct {
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
 	Phone     string `json:"phone"`
-	Address   struct {
-		Street  string `json:"street"`
-		City    string `json:"city"`
-		Country string `json:"country"`
-	} `json:"address"`
-	JobTitle string `json:"job_title"`
+	// PhoneCountry is the ISO 3166-1 alpha-2 code national phone numbers are
+	// read in and displayed for
+	PhoneCountry string `json:"phone_country,omitempty"`
+	// PhoneDisplay is the phone number formatted for display. It is derived
+	// from Phone and PhoneCountry, and ignored on input.
+	PhoneDisplay string  `json:"phone_display,omitempty"`
+	Address      Address `json:"address"`
+	JobTitle     string  `json:"job_title"`
+	// PhotoURL links the uploaded profile photo. It is set by the photo
+	// upload and ignored on input.
+	PhotoURL string `json:"photo_url,omitempty"`
 }
 
 // Validate validates the personal information
@@ -44,21 +54,20 @@ func (p *PersonalInfo) Validate() error {
 		return NewValidationError("email", "Invalid email format", ErrInvalidField)
 	}
 
-	// Validate phone format if provided
+	// Validate phone number if provided, reading national numbers in the phone country
+	if p.PhoneCountry != "" && !phone.ValidRegion(p.PhoneCountry) {
+		return NewValidationError("phone_country", "Invalid phone country (must be a two-letter country code, e.g., US)", ErrInvalidField)
+	}
 	if p.Phone != "" && !PhoneRegex.MatchString(p.Phone) {
-		return NewValidationError("phone", "Invalid phone format (must be E.164 format, e.g., +1234567890)", ErrInvalidField)
+		if _, err := phone.Normalize(p.Phone, p.PhoneCountry); err != nil {
+			return NewValidationError("phone", "Invalid phone number (include the country code, e.g., +1 202 555 0143, or set phone_country)", ErrInvalidField)
+		}
 	}
 
 	// Validate address if provided
-	if p.Address.Street != "" || p.Address.City != "" || p.Address.Country != "" {
-		if strings.TrimSpace(p.Address.Street) == "" {
-			return NewValidationError("address.street", "Street is required if address is provided", ErrInvalidField)
-		}
-		if strings.TrimSpace(p.Address.City) == "" {
-			return NewValidationError("address.city", "City is required if address is provided", ErrInvalidField)
-		}
-		if strings.TrimSpace(p.Address.Country) == "" {
-			return NewValidationError("address.country", "Country is required if address is provided", ErrInvalidField)
+	if !p.Address.IsZero() {
+		if err := p.Address.Validate(); err != nil {
+			return err
 		}
 	}
 
@@ -67,14 +76,22 @@ func (p *PersonalInfo) Validate() error {
 
 // BeforeSave sanitizes the data before saving
 func (p *PersonalInfo) BeforeSave() {
-	p.FirstName = strings.TrimSpace(p.FirstName)
-	p.LastName = strings.TrimSpace(p.LastName)
-	p.Email = strings.TrimSpace(p.Email)
-	p.Phone = strings.TrimSpace(p.Phone)
-	p.Address.Street = strings.TrimSpace(p.Address.Street)
-	p.Address.City = strings.TrimSpace(p.Address.City)
-	p.Address.Country = strings.TrimSpace(p.Address.Country)
-	p.JobTitle = strings.TrimSpace(p.JobTitle)
+	p.FirstName = sanitize.Line(p.FirstName)
+	p.LastName = sanitize.Line(p.LastName)
+	p.Email = sanitize.Line(p.Email)
+	p.Phone = sanitize.Line(p.Phone)
+	p.PhoneCountry = strings.ToUpper(sanitize.Line(p.PhoneCountry))
+	p.Address.BeforeSave()
+	p.JobTitle = sanitize.Line(p.JobTitle)
+
+	// Store numbers in E.164; invalid numbers are left for Validate to reject
+	if normalized, err := phone.Normalize(p.Phone, p.PhoneCountry); err == nil {
+		p.Phone = normalized
+	}
+	p.PhoneDisplay = ""
+	if p.Phone != "" {
+		p.PhoneDisplay = phone.Display(p.Phone, p.PhoneCountry)
+	}
 }
 
 // ToJSON converts the personal information to JSON
@@ -5,6 +5,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/lordaris/resume_generator/pkg/sanitize"
 )
 
 // Project represents a project entry in a resume
@@ -65,16 +67,16 @@ func (p *Project) Validate() error {
 
 // BeforeSave sanitizes the data before saving
 func (p *Project) BeforeSave() {
-	p.Name = strings.TrimSpace(p.Name)
-	p.Description = strings.TrimSpace(p.Description)
-	p.RepoURL = strings.TrimSpace(p.RepoURL)
-	p.DemoURL = strings.TrimSpace(p.DemoURL)
-	p.StartDate = strings.TrimSpace(p.StartDate)
-	p.EndDate = strings.TrimSpace(p.EndDate)
+	p.Name = sanitize.Line(p.Name)
+	p.Description = sanitize.Text(p.Description)
+	p.RepoURL = sanitize.Line(p.RepoURL)
+	p.DemoURL = sanitize.Line(p.DemoURL)
+	p.StartDate = sanitize.Line(p.StartDate)
+	p.EndDate = sanitize.Line(p.EndDate)
 
-	// Trim technologies
+	// Sanitize technologies
 	for i, tech := range p.Technologies {
-		p.Technologies[i] = strings.TrimSpace(tech)
+		p.Technologies[i] = sanitize.Line(tech)
 	}
 
 	// Remove empty technologies
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Import sources
+const (
+	ImportSourceText     = "text"
+	ImportSourceFile     = "file"
+	ImportSourceLinkedIn = "linkedin"
+	ImportSourceGitHub   = "github"
+	ImportSourceCredly   = "credly"
+)
+
+// ImportSources lists the sources resume entries can be imported from
+var ImportSources = []string{
+	ImportSourceText,
+	ImportSourceFile,
+	ImportSourceLinkedIn,
+	ImportSourceGitHub,
+	ImportSourceCredly,
+}
+
+// Provenance records where an imported resume entry came from, keeping the
+// entry as the source had it so it can be reverted to
+type Provenance struct {
+	ResumeID uuid.UUID `json:"resume_id" db:"resume_id"`
+	// Entity and EntityID name the entry, with the entities of the event log
+	Entity   string    `json:"entity" db:"entity"`
+	EntityID uuid.UUID `json:"entity_id" db:"entity_id"`
+	Source   string    `json:"source" db:"source"`
+	// ImportID is shared by the entries imported together
+	ImportID uuid.UUID `json:"import_id" db:"import_id"`
+	// SourceRef identifies the entry at its source, such as a GitHub
+	// repository, so a later import can re-sync it
+	SourceRef string `json:"source_ref,omitempty" db:"source_ref"`
+	// Original is the entry as imported, or as last re-synced
+	Original   json.RawMessage `json:"original" db:"original"`
+	ImportedAt time.Time       `json:"imported_at" db:"imported_at"`
+	SyncedAt   time.Time       `json:"synced_at" db:"synced_at"`
+}
+
+// ProvenanceRepository defines the interface for the provenance of imported entries
+type ProvenanceRepository interface {
+	// SaveProvenance creates or replaces the provenance of an entry
+	SaveProvenance(ctx context.Context, provenance *Provenance) error
+	GetProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) (*Provenance, error)
+	ListProvenance(ctx context.Context, resumeID uuid.UUID) ([]*Provenance, error)
+	DeleteProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) error
+	// DeleteResumeProvenance deletes the provenance of every entry of a resume
+	DeleteResumeProvenance(ctx context.Context, resumeID uuid.UUID) error
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ type Resume struct {
 	UserID    uuid.UUID `json:"user_id" db:"user_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ResumeMetadata
 
 	// Optional fields not stored in the resume table
 	PersonalInfo   *PersonalInfo    `json:"personal_info,omitempty" db:"-"`
@@ -25,55 +27,62 @@ type Resume struct {
 // ResumeRepository defines the interface for resume data operations
 type ResumeRepository interface {
 	// Resume operations
-	CreateResume(userID uuid.UUID) (*Resume, error)
-	GetResumeByID(id uuid.UUID) (*Resume, error)
-	GetResumesByUserID(userID uuid.UUID) ([]*Resume, error)
-	DeleteResume(id uuid.UUID) error
+	CreateResume(ctx context.Context, userID uuid.UUID, metadata *ResumeMetadata) (*Resume, error)
+	GetResumeByID(ctx context.Context, id uuid.UUID) (*Resume, error)
+	GetResumesByUserID(ctx context.Context, userID uuid.UUID) ([]*Resume, error)
+	ListResumesByUserID(ctx context.Context, userID uuid.UUID, opts ListOptions) ([]*Resume, int64, error)
+	UpdateResumeMetadata(ctx context.Context, id uuid.UUID, metadata *ResumeMetadata) error
+	DeleteResume(ctx context.Context, id uuid.UUID) error
 
 	// Personal info operations
-	SavePersonalInfo(resumeID uuid.UUID, info *PersonalInfo) error
-	GetPersonalInfo(resumeID uuid.UUID) (*PersonalInfo, error)
+	SavePersonalInfo(ctx context.Context, resumeID uuid.UUID, info *PersonalInfo) error
+	GetPersonalInfo(ctx context.Context, resumeID uuid.UUID) (*PersonalInfo, error)
+	// SetPhotoURL sets the personal info's photo, empty to remove it. It
+	// returns ErrNotFound when the resume has no personal info.
+	SetPhotoURL(ctx context.Context, resumeID uuid.UUID, photoURL string) error
 
 	// Education operations
-	AddEducation(resumeID uuid.UUID, education *Education) (uuid.UUID, error)
-	UpdateEducation(id uuid.UUID, education *Education) error
-	DeleteEducation(id uuid.UUID) error
-	GetEducation(id uuid.UUID) (*Education, error)
-	GetEducationByResume(resumeID uuid.UUID) ([]*Education, error)
+	AddEducation(ctx context.Context, resumeID uuid.UUID, education *Education) (uuid.UUID, error)
+	UpdateEducation(ctx context.Context, resumeID, id uuid.UUID, education *Education) error
+	DeleteEducation(ctx context.Context, resumeID, id uuid.UUID) error
+	GetEducation(ctx context.Context, id uuid.UUID) (*Education, error)
+	GetEducationByResume(ctx context.Context, resumeID uuid.UUID) ([]*Education, error)
 
 	// Experience operations
-	AddExperience(resumeID uuid.UUID, experience *Experience) (uuid.UUID, error)
-	UpdateExperience(id uuid.UUID, experience *Experience) error
-	DeleteExperience(id uuid.UUID) error
-	GetExperience(id uuid.UUID) (*Experience, error)
-	GetExperienceByResume(resumeID uuid.UUID) ([]*Experience, error)
+	AddExperience(ctx context.Context, resumeID uuid.UUID, experience *Experience) (uuid.UUID, error)
+	UpdateExperience(ctx context.Context, resumeID, id uuid.UUID, experience *Experience) error
+	DeleteExperience(ctx context.Context, resumeID, id uuid.UUID) error
+	GetExperience(ctx context.Context, resumeID, id uuid.UUID) (*Experience, error)
+	GetExperienceByResume(ctx context.Context, resumeID uuid.UUID) ([]*Experience, error)
 
 	// Skill operations
-	AddSkill(resumeID uuid.UUID, skill *Skill) (uuid.UUID, error)
-	UpdateSkill(id uuid.UUID, skill *Skill) error
-	DeleteSkill(id uuid.UUID) error
-	GetSkill(id uuid.UUID) (*Skill, error)
-	GetSkillsByResume(resumeID uuid.UUID) ([]*Skill, error)
+	AddSkill(ctx context.Context, resumeID uuid.UUID, skill *Skill) (uuid.UUID, error)
+	// AddSkills adds several skills at once; either all are added or none
+	AddSkills(ctx context.Context, resumeID uuid.UUID, skills []*Skill) ([]uuid.UUID, error)
+	UpdateSkill(ctx context.Context, resumeID, id uuid.UUID, skill *Skill) error
+	DeleteSkill(ctx context.Context, resumeID, id uuid.UUID) error
+	GetSkill(ctx context.Context, id uuid.UUID) (*Skill, error)
+	GetSkillsByResume(ctx context.Context, resumeID uuid.UUID) ([]*Skill, error)
 
 	// Project operations
-	AddProject(resumeID uuid.UUID, project *Project) (uuid.UUID, error)
-	UpdateProject(id uuid.UUID, project *Project) error
-	DeleteProject(id uuid.UUID) error
-	GetProject(id uuid.UUID) (*Project, error)
-	GetProjectsByResume(resumeID uuid.UUID) ([]*Project, error)
+	AddProject(ctx context.Context, resumeID uuid.UUID, project *Project) (uuid.UUID, error)
+	UpdateProject(ctx context.Context, resumeID, id uuid.UUID, project *Project) error
+	DeleteProject(ctx context.Context, resumeID, id uuid.UUID) error
+	GetProject(ctx context.Context, id uuid.UUID) (*Project, error)
+	GetProjectsByResume(ctx context.Context, resumeID uuid.UUID) ([]*Project, error)
 
 	// Project technologies operations
-	AddProjectTechnology(projectID uuid.UUID, technology string) error
-	DeleteProjectTechnology(projectID uuid.UUID, technology string) error
-	GetProjectTechnologies(projectID uuid.UUID) ([]string, error)
+	AddProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error
+	DeleteProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error
+	GetProjectTechnologies(ctx context.Context, projectID uuid.UUID) ([]string, error)
 
 	// Certification operations
-	AddCertification(resumeID uuid.UUID, certification *Certification) (uuid.UUID, error)
-	UpdateCertification(id uuid.UUID, certification *Certification) error
-	DeleteCertification(id uuid.UUID) error
-	GetCertification(id uuid.UUID) (*Certification, error)
-	GetCertificationsByResume(resumeID uuid.UUID) ([]*Certification, error)
+	AddCertification(ctx context.Context, resumeID uuid.UUID, certification *Certification) (uuid.UUID, error)
+	UpdateCertification(ctx context.Context, resumeID, id uuid.UUID, certification *Certification) error
+	DeleteCertification(ctx context.Context, resumeID, id uuid.UUID) error
+	GetCertification(ctx context.Context, id uuid.UUID) (*Certification, error)
+	GetCertificationsByResume(ctx context.Context, resumeID uuid.UUID) ([]*Certification, error)
 
 	// Complete resume operations
-	GetCompleteResume(resumeID uuid.UUID) (*Resume, error)
+	GetCompleteResume(ctx context.Context, resumeID uuid.UUID) (*Resume, error)
 }
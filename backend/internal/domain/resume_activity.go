@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resume activity kinds
+const (
+	// ActivityEdit is a change to the resume, from its event log
+	ActivityEdit        = "edit"
+	ActivityExport      = "export"
+	ActivityEmail       = "email"
+	ActivityShareCreate = "share_create"
+	ActivityShareRevoke = "share_revoke"
+	ActivityShareView   = "share_view"
+	ActivityPublish     = "publish"
+	ActivityUnpublish   = "unpublish"
+	ActivityPageView    = "page_view"
+	ActivityNote        = "note"
+)
+
+// ResumeActivity is something that happened to a resume, such as an export
+// or a view through a share link. Edits are kept in the event log and appear
+// in activity feeds with the edit kind.
+type ResumeActivity struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	ResumeID uuid.UUID `json:"resume_id" db:"resume_id"`
+	Kind     string    `json:"kind" db:"kind"`
+	// ActorID is the user responsible, nil for anonymous viewers
+	ActorID uuid.UUID `json:"actor_id,omitempty" db:"actor_id"`
+	// Details describe the activity, such as the format of an export
+	Details   json.RawMessage `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ResumeActivityRepository defines the interface for resume activity operations
+type ResumeActivityRepository interface {
+	AddActivity(ctx context.Context, activity *ResumeActivity) error
+	// GetActivityBefore returns up to limit activities older than the one
+	// created at before with ID beforeID, newest first; a zero before starts
+	// from the newest
+	GetActivityBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*ResumeActivity, error)
+	DeleteActivity(ctx context.Context, resumeID uuid.UUID) error
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resume event entities
+const (
+	EventEntityResume        = "resume"
+	EventEntityPersonalInfo  = "personal_info"
+	EventEntityEducation     = "education"
+	EventEntityExperience    = "experience"
+	EventEntitySkill         = "skill"
+	EventEntityProject       = "project"
+	EventEntityCertification = "certification"
+)
+
+// Resume event operations
+const (
+	EventOpCreate = "create"
+	EventOpUpdate = "update"
+	EventOpDelete = "delete"
+)
+
+// ResumeEvent is an immutable record of a single resume mutation
+type ResumeEvent struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	ResumeID  uuid.UUID       `json:"resume_id" db:"resume_id"`
+	Version   int64           `json:"version" db:"version"`
+	Entity    string          `json:"entity" db:"entity"`
+	EntityID  uuid.UUID       `json:"entity_id,omitempty" db:"entity_id"`
+	Op        string          `json:"op" db:"op"`
+	Payload   json.RawMessage `json:"payload,omitempty" db:"payload"`
+	ActorID   uuid.UUID       `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	// Seq is the event's position in the log of all resumes, which feeds
+	// across a user's resumes page by
+	Seq int64 `json:"-" db:"seq"`
+}
+
+// ResumeChange is a resume that changed, as of its latest event
+type ResumeChange struct {
+	ResumeID  uuid.UUID
+	Seq       int64
+	ChangedAt time.Time
+}
+
+// ResumeEventRepository defines the interface for the resume event log
+type ResumeEventRepository interface {
+	// AppendEvent stores an event, assigning it the next version for its resume
+	AppendEvent(ctx context.Context, event *ResumeEvent) error
+	// GetEventsAfter returns up to limit events with a version greater than afterVersion
+	GetEventsAfter(ctx context.Context, resumeID uuid.UUID, afterVersion int64, limit int) ([]*ResumeEvent, error)
+	// GetEventsBefore returns up to limit events older than the one created
+	// at before with ID beforeID, newest first; a zero before starts from the
+	// newest
+	GetEventsBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*ResumeEvent, error)
+	// GetEventsUpTo returns all events up to and including version
+	GetEventsUpTo(ctx context.Context, resumeID uuid.UUID, version int64) ([]*ResumeEvent, error)
+	// CountEventsSince returns the number of events recorded for each resume
+	// since the given time; resumes without events are left out
+	CountEventsSince(ctx context.Context, resumeIDs []uuid.UUID, since time.Time) (map[uuid.UUID]int64, error)
+	// GetOwnerEventsAfter returns up to limit events of the owner's resumes
+	// after afterSeq that were recorded after since and no later than until,
+	// in the order they were recorded
+	GetOwnerEventsAfter(ctx context.Context, ownerID uuid.UUID, afterSeq int64, since, until time.Time, limit int) ([]*ResumeEvent, error)
+	// GetOwnerChangedResumes returns up to limit of the owner's resumes with
+	// events in the same range, ordered by their latest one
+	GetOwnerChangedResumes(ctx context.Context, ownerID uuid.UUID, afterSeq int64, since, until time.Time, limit int) ([]*ResumeChange, error)
+}
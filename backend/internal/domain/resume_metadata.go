@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/pkg/locale"
+	"github.com/lordaris/resume_generator/pkg/sanitize"
+)
+
+// Resume metadata limits
+const (
+	MaxResumeTitleLength = 200
+	MaxResumeTags        = 20
+	MaxResumeTagLength   = 50
+)
+
+// ResumeMetadata names and labels a resume so a user can tell their resumes apart
+type ResumeMetadata struct {
+	Title          string   `json:"title"`
+	TargetJobTitle string   `json:"target_job_title"`
+	Tags           []string `json:"tags"`
+	// Language is the BCP 47 tag of the language the resume is written in. It
+	// picks the section headings and date format of exports.
+	Language string `json:"language"`
+	// TranslationOf is the resume this one was translated from. It is set when
+	// a translation is created and can't be changed afterwards.
+	TranslationOf *uuid.UUID `json:"translation_of,omitempty"`
+	// Settings are the formatting preferences of the resume's exports
+	Settings ResumeSettings `json:"settings"`
+}
+
+// Validate validates the resume metadata
+func (m *ResumeMetadata) Validate() error {
+	if utf8.RuneCountInString(m.Title) > MaxResumeTitleLength {
+		return NewValidationError("title", fmt.Sprintf("Title must be at most %d characters", MaxResumeTitleLength), ErrInvalidField)
+	}
+	if utf8.RuneCountInString(m.TargetJobTitle) > MaxResumeTitleLength {
+		return NewValidationError("target_job_title", fmt.Sprintf("Target job title must be at most %d characters", MaxResumeTitleLength), ErrInvalidField)
+	}
+	if len(m.Tags) > MaxResumeTags {
+		return NewValidationError("tags", fmt.Sprintf("A resume can have at most %d tags", MaxResumeTags), ErrInvalidField)
+	}
+	for _, tag := range m.Tags {
+		if utf8.RuneCountInString(tag) > MaxResumeTagLength {
+			return NewValidationError("tags", fmt.Sprintf("Tags must be at most %d characters", MaxResumeTagLength), ErrInvalidField)
+		}
+	}
+	if m.Language != "" {
+		if _, err := locale.Normalize(m.Language); err != nil {
+			return NewValidationError("language", "Language must be a language tag such as \"en\" or \"es-MX\"", ErrInvalidField)
+		}
+	}
+
+	return m.Settings.Validate()
+}
+
+// BeforeSave sanitizes the data before saving. Tags are lowercased, and empty
+// and duplicate tags are removed so filtering by tag is exact.
+func (m *ResumeMetadata) BeforeSave() {
+	m.Title = sanitize.Line(m.Title)
+	m.TargetJobTitle = sanitize.Line(m.TargetJobTitle)
+	if language, err := locale.Normalize(m.Language); err == nil {
+		m.Language = language
+	} else if m.Language == "" {
+		m.Language = locale.Default
+	}
+
+	tags := make([]string, 0, len(m.Tags))
+	for _, tag := range m.Tags {
+		tag = NormalizeTag(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	m.Tags = tags
+	m.Settings.BeforeSave()
+}
+
+// NormalizeTag returns the stored form of a tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(sanitize.Line(tag))
+}
@@ -6,8 +6,9 @@ import (
 
 // Common resume model errors
 var (
-	ErrInvalidField = fmt.Errorf("invalid field value")
-	ErrDateRange    = fmt.Errorf("invalid date range")
+	ErrInvalidField  = fmt.Errorf("invalid field value")
+	ErrDateRange     = fmt.Errorf("invalid date range")
+	ErrLimitExceeded = fmt.Errorf("limit exceeded")
 )
 
 // ValidationError represents a validation error with a field name and message
@@ -35,3 +36,19 @@ func NewValidationError(field, message string, err error) *ValidationError {
 		Err:     err,
 	}
 }
+
+// LimitExceededError reports that a resume section already holds its maximum number of entries
+type LimitExceededError struct {
+	Section string
+	Limit   int
+}
+
+// Error returns the error message
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s cannot have more than %d entries", e.Section, e.Limit)
+}
+
+// Unwrap returns ErrLimitExceeded so callers can match any cap violation
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
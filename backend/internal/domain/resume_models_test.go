@@ -11,11 +11,7 @@ func BenchmarkPersonalInfoValidation(b *testing.B) {
 		LastName:  "Doe",
 		Email:     "john.doe@example.com",
 		Phone:     "+1234567890",
-		Address: struct {
-			Street  string `json:"street"`
-			City    string `json:"city"`
-			Country string `json:"country"`
-		}{
+		Address: Address{
 			Street:  "123 Main St",
 			City:    "New York",
 			Country: "USA",
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Private note size limits
+const (
+	// MaxNoteCiphertextSize caps the encrypted note of a resume
+	MaxNoteCiphertextSize = 64 << 10
+	// MaxNoteNonceSize caps the nonce or IV stored with a note
+	MaxNoteNonceSize       = 64
+	MaxNoteAlgorithmLength = 50
+	MaxNoteKeyIDLength     = 100
+)
+
+// ResumeNote is a version of the private note on a resume. Notes are
+// encrypted by the client with a key the server never sees; the server
+// stores the ciphertext with what the client needs to decrypt it.
+type ResumeNote struct {
+	ResumeID uuid.UUID `json:"resume_id" db:"resume_id"`
+	// Version counts the saves of the note, starting at 1
+	Version int       `json:"version" db:"version"`
+	UserID  uuid.UUID `json:"-" db:"user_id"`
+	// Algorithm names the cipher, such as "AES-256-GCM"; it isn't checked
+	Algorithm string `json:"algorithm" db:"algorithm"`
+	// KeyID lets the client find the key, such as a key derivation salt
+	KeyID      string    `json:"key_id,omitempty" db:"key_id"`
+	Nonce      []byte    `json:"nonce" db:"nonce"`
+	Ciphertext []byte    `json:"ciphertext,omitempty" db:"ciphertext"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ResumeNoteVersion describes a stored version without its ciphertext
+type ResumeNoteVersion struct {
+	Version   int    `json:"version" db:"version"`
+	Algorithm string `json:"algorithm" db:"algorithm"`
+	KeyID     string `json:"key_id,omitempty" db:"key_id"`
+	// Size is the length of the ciphertext in bytes
+	Size      int       `json:"size" db:"size"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Validate validates the note metadata and sizes
+func (n *ResumeNote) Validate() error {
+	if len(n.Ciphertext) == 0 {
+		return NewValidationError("ciphertext", "Ciphertext is required", ErrInvalidField)
+	}
+	if len(n.Ciphertext) > MaxNoteCiphertextSize {
+		return NewValidationError("ciphertext", "Ciphertext cannot be larger than 64 KiB", ErrInvalidField)
+	}
+	if len(n.Nonce) > MaxNoteNonceSize {
+		return NewValidationError("nonce", "Nonce cannot be longer than 64 bytes", ErrInvalidField)
+	}
+	if n.Algorithm == "" {
+		return NewValidationError("algorithm", "Algorithm is required", ErrInvalidField)
+	}
+	if utf8.RuneCountInString(n.Algorithm) > MaxNoteAlgorithmLength {
+		return NewValidationError("algorithm", "Algorithm cannot be longer than 50 characters", ErrInvalidField)
+	}
+	if utf8.RuneCountInString(n.KeyID) > MaxNoteKeyIDLength {
+		return NewValidationError("key_id", "Key ID cannot be longer than 100 characters", ErrInvalidField)
+	}
+	return nil
+}
+
+// ResumeNoteRepository defines the interface for private note data operations
+type ResumeNoteRepository interface {
+	// CreateNote stores a new version of a note, returning ErrConflict when
+	// the version exists
+	CreateNote(ctx context.Context, note *ResumeNote) error
+	GetLatestNote(ctx context.Context, resumeID uuid.UUID) (*ResumeNote, error)
+	GetNoteVersion(ctx context.Context, resumeID uuid.UUID, version int) (*ResumeNote, error)
+	GetNoteVersions(ctx context.Context, resumeID uuid.UUID) ([]*ResumeNoteVersion, error)
+	// DeleteNoteVersionsBefore deletes the versions older than version
+	DeleteNoteVersionsBefore(ctx context.Context, resumeID uuid.UUID, version int) error
+	DeleteNotes(ctx context.Context, resumeID uuid.UUID) error
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Slug length limits of published resumes
+const (
+	MinSlugLength = 3
+	MaxSlugLength = 50
+)
+
+// slugPattern matches lowercase words of letters and digits joined by single hyphens
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// reservedSlugs can't be published at, so they stay free for pages of the app
+// and can't be used to impersonate it
+var reservedSlugs = []string{"about", "admin", "api", "app", "help", "login", "privacy", "resume", "resumes", "settings", "support", "terms"}
+
+// ResumePublication publishes a resume as a public page at a vanity slug,
+// without the fields and sections it hides
+type ResumePublication struct {
+	ResumeID uuid.UUID `json:"resume_id" db:"resume_id"`
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Slug     string    `json:"slug" db:"slug"`
+	// Hidden lists the fields and sections the page doesn't show, among
+	// HideableShareFields
+	Hidden      []string  `json:"hidden,omitempty" db:"hidden"`
+	PublishedAt time.Time `json:"published_at" db:"published_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Redact returns a copy of resume without the fields and sections the page
+// hides. resume itself is left unchanged.
+func (p *ResumePublication) Redact(resume *Resume) *Resume {
+	return (&ResumeShare{Hidden: p.Hidden}).Redact(resume)
+}
+
+// NormalizeSlug lowercases a slug and checks that it is free to publish at
+func NormalizeSlug(slug string) (string, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if len(slug) < MinSlugLength || len(slug) > MaxSlugLength {
+		return "", NewValidationError("slug", "Slug must be between 3 and 50 characters", ErrInvalidField)
+	}
+	if !slugPattern.MatchString(slug) {
+		return "", NewValidationError("slug", "Slug may only contain letters, digits and single hyphens between them", ErrInvalidField)
+	}
+	if slices.Contains(reservedSlugs, slug) {
+		return "", NewValidationError("slug", "Slug is reserved", ErrInvalidField)
+	}
+	return slug, nil
+}
+
+// ResumePublicationRepository defines the interface for published resume operations
+type ResumePublicationRepository interface {
+	// SavePublication publishes a resume, or changes its slug and hidden
+	// fields when it is already published. It fails with a conflict when
+	// another resume is published at the slug.
+	SavePublication(ctx context.Context, publication *ResumePublication) error
+	GetPublication(ctx context.Context, resumeID uuid.UUID) (*ResumePublication, error)
+	GetPublicationBySlug(ctx context.Context, slug string) (*ResumePublication, error)
+	// SlugsTaken returns which of the slugs resumes are published at
+	SlugsTaken(ctx context.Context, slugs []string) ([]string, error)
+	DeletePublication(ctx context.Context, resumeID uuid.UUID) error
+}
@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lordaris/resume_generator/pkg/locale"
+)
+
+// Phone display styles of a rendered resume
+const (
+	// PhoneNational writes phone numbers the way they're dialed in their own
+	// country, as chosen by the personal info's phone country
+	PhoneNational = "national"
+	// PhoneInternational writes phone numbers with their country code
+	PhoneInternational = "international"
+)
+
+// PhoneDisplays are the supported phone display styles
+var PhoneDisplays = []string{PhoneNational, PhoneInternational}
+
+// Page-break hints of a rendered resume
+const (
+	// PageBreakKeepTogether keeps a section or entry on one page, moving it
+	// to the next page if it would be split and fits on one
+	PageBreakKeepTogether = "keep_together"
+	// PageBreakBefore starts a section or entry on a new page
+	PageBreakBefore = "before"
+)
+
+// PageBreaks are the supported page-break hints
+var PageBreaks = []string{PageBreakKeepTogether, PageBreakBefore}
+
+// Sections page-break hints can apply to
+const (
+	LayoutSectionExperience     = "experience"
+	LayoutSectionEducation      = "education"
+	LayoutSectionSkills         = "skills"
+	LayoutSectionProjects       = "projects"
+	LayoutSectionCertifications = "certifications"
+)
+
+// LayoutSections lists the sections page-break hints can apply to
+var LayoutSections = []string{
+	LayoutSectionExperience,
+	LayoutSectionEducation,
+	LayoutSectionSkills,
+	LayoutSectionProjects,
+	LayoutSectionCertifications,
+}
+
+// MaxPageBreakHints is how many page-break hints a resume can have
+const MaxPageBreakHints = 100
+
+// PageBreakHint asks paged exports to break pages around a section or one
+// of its entries
+type PageBreakHint struct {
+	// Section is one of LayoutSections
+	Section string `json:"section"`
+	// Entry narrows the hint to one entry of the section, counting from 0 in
+	// the order the section lists them. Skills are grouped when rendered, so
+	// their hints apply to the whole section.
+	Entry *int `json:"entry,omitempty"`
+	// Break is one of PageBreaks
+	Break string `json:"break"`
+}
+
+// Priorities of entries, ranking which a one-page export omits first
+const (
+	MinEntryPriority = 1
+	// DefaultEntryPriority is the priority of entries without one
+	DefaultEntryPriority = 3
+	// MaxEntryPriority entries are never omitted
+	MaxEntryPriority = 5
+)
+
+// MaxEntryPriorities is how many entry priorities a resume can have
+const MaxEntryPriorities = 100
+
+// EntryPriority ranks an entry of a section for one-page exports, which
+// omit entries with lower priorities first
+type EntryPriority struct {
+	// Section is one of LayoutSections except skills
+	Section string `json:"section"`
+	// Entry is the entry's position in the section, counting from 0
+	Entry int `json:"entry"`
+	// Priority is from MinEntryPriority to MaxEntryPriority
+	Priority int `json:"priority"`
+}
+
+// ResumeLayout is how a resume's content is laid out on pages
+type ResumeLayout struct {
+	PageBreaks []PageBreakHint `json:"page_breaks,omitempty"`
+	Priorities []EntryPriority `json:"priorities,omitempty"`
+}
+
+// Priority returns the priority of an entry of a section
+func (l ResumeLayout) Priority(section string, entry int) int {
+	for _, p := range l.Priorities {
+		if p.Section == section && p.Entry == entry {
+			return p.Priority
+		}
+	}
+	return DefaultEntryPriority
+}
+
+// PageBreak reports the hints of a section, or of one of its entries when
+// entry isn't negative
+func (l ResumeLayout) PageBreak(section string, entry int) (before, keepTogether bool) {
+	for _, hint := range l.PageBreaks {
+		if hint.Section != section {
+			continue
+		}
+		if (entry < 0 && hint.Entry != nil) || (entry >= 0 && (hint.Entry == nil || *hint.Entry != entry)) {
+			continue
+		}
+		switch hint.Break {
+		case PageBreakBefore:
+			before = true
+		case PageBreakKeepTogether:
+			keepTogether = true
+		}
+	}
+	return before, keepTogether
+}
+
+// ResumeSettings are the formatting preferences every export of a resume
+// honors. Empty preferences follow the locale of the resume's language.
+type ResumeSettings struct {
+	// DateStyle is how dates are written, one of locale.DateStyles
+	DateStyle string `json:"date_style,omitempty"`
+	// PhoneDisplay is how phone numbers are written, one of PhoneDisplays
+	PhoneDisplay string `json:"phone_display,omitempty"`
+	// PaperSize is the page size of PDF and printed exports, one of
+	// locale.PaperSizes
+	PaperSize string `json:"paper_size,omitempty"`
+	// Layout holds the page-break hints of paged exports and the entry
+	// priorities of one-page exports
+	Layout ResumeLayout `json:"layout"`
+}
+
+// Validate validates the resume settings, ignoring case
+func (s *ResumeSettings) Validate() error {
+	n := *s
+	n.BeforeSave()
+	if n.DateStyle != "" && !slices.Contains(locale.DateStyles, n.DateStyle) {
+		return NewValidationError("settings.date_style", "Date style must be one of "+strings.Join(locale.DateStyles, ", "), ErrInvalidField)
+	}
+	if n.PhoneDisplay != "" && !slices.Contains(PhoneDisplays, n.PhoneDisplay) {
+		return NewValidationError("settings.phone_display", "Phone display must be one of "+strings.Join(PhoneDisplays, ", "), ErrInvalidField)
+	}
+	if n.PaperSize != "" && !slices.Contains(locale.PaperSizes, n.PaperSize) {
+		return NewValidationError("settings.paper_size", "Paper size must be one of "+strings.Join(locale.PaperSizes, ", "), ErrInvalidField)
+	}
+
+	if len(n.Layout.PageBreaks) > MaxPageBreakHints {
+		return NewValidationError("settings.layout.page_breaks", fmt.Sprintf("A resume can have at most %d page-break hints", MaxPageBreakHints), ErrInvalidField)
+	}
+	for _, hint := range n.Layout.PageBreaks {
+		if !slices.Contains(LayoutSections, hint.Section) {
+			return NewValidationError("settings.layout.page_breaks", "Page-break sections must be one of "+strings.Join(LayoutSections, ", "), ErrInvalidField)
+		}
+		if !slices.Contains(PageBreaks, hint.Break) {
+			return NewValidationError("settings.layout.page_breaks", "Page breaks must be one of "+strings.Join(PageBreaks, ", "), ErrInvalidField)
+		}
+		if hint.Entry != nil && (*hint.Entry < 0 || hint.Section == LayoutSectionSkills) {
+			return NewValidationError("settings.layout.page_breaks", "Page-break entries must be positions from 0, and skills can only have section hints", ErrInvalidField)
+		}
+	}
+
+	if len(n.Layout.Priorities) > MaxEntryPriorities {
+		return NewValidationError("settings.layout.priorities", fmt.Sprintf("A resume can have at most %d entry priorities", MaxEntryPriorities), ErrInvalidField)
+	}
+	for _, p := range n.Layout.Priorities {
+		if !slices.Contains(LayoutSections, p.Section) || p.Section == LayoutSectionSkills {
+			return NewValidationError("settings.layout.priorities", "Priority sections must be one of experience, education, projects, certifications", ErrInvalidField)
+		}
+		if p.Entry < 0 {
+			return NewValidationError("settings.layout.priorities", "Priority entries must be positions from 0", ErrInvalidField)
+		}
+		if p.Priority < MinEntryPriority || p.Priority > MaxEntryPriority {
+			return NewValidationError("settings.layout.priorities", fmt.Sprintf("Priorities must be from %d to %d", MinEntryPriority, MaxEntryPriority), ErrInvalidField)
+		}
+	}
+	return nil
+}
+
+// BeforeSave sanitizes the data before saving
+func (s *ResumeSettings) BeforeSave() {
+	s.DateStyle = strings.ToLower(strings.TrimSpace(s.DateStyle))
+	s.PhoneDisplay = strings.ToLower(strings.TrimSpace(s.PhoneDisplay))
+	s.PaperSize = strings.ToLower(strings.TrimSpace(s.PaperSize))
+
+	var hints []PageBreakHint
+	for _, hint := range s.Layout.PageBreaks {
+		hint.Section = strings.ToLower(strings.TrimSpace(hint.Section))
+		hint.Break = strings.ToLower(strings.TrimSpace(hint.Break))
+		if !slices.ContainsFunc(hints, hint.equal) {
+			hints = append(hints, hint)
+		}
+	}
+	s.Layout.PageBreaks = hints
+
+	// A later priority of the same entry replaces an earlier one
+	var priorities []EntryPriority
+	for _, p := range s.Layout.Priorities {
+		p.Section = strings.ToLower(strings.TrimSpace(p.Section))
+		i := slices.IndexFunc(priorities, func(other EntryPriority) bool {
+			return other.Section == p.Section && other.Entry == p.Entry
+		})
+		if i >= 0 {
+			priorities[i] = p
+		} else {
+			priorities = append(priorities, p)
+		}
+	}
+	s.Layout.Priorities = priorities
+}
+
+// equal reports whether two hints are the same
+func (h PageBreakHint) equal(other PageBreakHint) bool {
+	return h.Section == other.Section && h.Break == other.Break &&
+		(h.Entry == nil) == (other.Entry == nil) && (h.Entry == nil || *h.Entry == *other.Entry)
+}
+
+// Resolve returns the settings with empty preferences filled in for the
+// locale tag: long dates, national phone numbers and the paper size of the
+// tag's region
+func (s ResumeSettings) Resolve(tag string) ResumeSettings {
+	if s.DateStyle == "" {
+		s.DateStyle = locale.DateStyleLong
+	}
+	if s.PhoneDisplay == "" {
+		s.PhoneDisplay = PhoneNational
+	}
+	if s.PaperSize == "" {
+		s.PaperSize = locale.PaperSize(tag)
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/lordaris/resume_generator/pkg/locale"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeSettingsValidate(t *testing.T) {
+	valid := ResumeSettings{DateStyle: "ISO", PhoneDisplay: " international ", PaperSize: "Letter"}
+	assert.NoError(t, valid.Validate())
+	valid.BeforeSave()
+	assert.Equal(t, ResumeSettings{DateStyle: locale.DateStyleISO, PhoneDisplay: PhoneInternational, PaperSize: locale.PaperLetter}, valid)
+
+	for _, settings := range []ResumeSettings{{DateStyle: "short"}, {PhoneDisplay: "e164"}, {PaperSize: "a5"}} {
+		assert.ErrorIs(t, settings.Validate(), ErrInvalidField, "settings %+v", settings)
+	}
+}
+
+func TestResumeSettingsResolve(t *testing.T) {
+	assert.Equal(t, ResumeSettings{DateStyle: locale.DateStyleLong, PhoneDisplay: PhoneNational, PaperSize: locale.PaperLetter}, ResumeSettings{}.Resolve("es-MX"))
+	assert.Equal(t, locale.PaperA4, ResumeSettings{}.Resolve("es").PaperSize)
+
+	// Chosen settings are kept
+	chosen := ResumeSettings{DateStyle: locale.DateStyleNumeric, PhoneDisplay: PhoneInternational, PaperSize: locale.PaperA4}
+	assert.Equal(t, chosen, chosen.Resolve("en-US"))
+}
+
+func TestResumeLayoutPageBreak(t *testing.T) {
+	second := 1
+	settings := ResumeSettings{Layout: ResumeLayout{PageBreaks: []PageBreakHint{
+		{Section: "Experience", Entry: &second, Break: "keep_together"},
+		{Section: "experience", Entry: &second, Break: "keep_together"},
+		{Section: "education", Break: "before"},
+	}}}
+	assert.NoError(t, settings.Validate())
+	settings.BeforeSave()
+	assert.Len(t, settings.Layout.PageBreaks, 2)
+
+	before, keep := settings.Layout.PageBreak(LayoutSectionExperience, 1)
+	assert.False(t, before)
+	assert.True(t, keep)
+	before, keep = settings.Layout.PageBreak(LayoutSectionExperience, -1)
+	assert.False(t, before || keep)
+	before, _ = settings.Layout.PageBreak(LayoutSectionEducation, -1)
+	assert.True(t, before)
+	before, _ = settings.Layout.PageBreak(LayoutSectionEducation, 0)
+	assert.False(t, before)
+
+	negative := -1
+	for _, hint := range []PageBreakHint{
+		{Section: "summary", Break: "before"},
+		{Section: "education", Break: "after"},
+		{Section: "education", Entry: &negative, Break: "before"},
+		{Section: "skills", Entry: &second, Break: "before"},
+	} {
+		settings := ResumeSettings{Layout: ResumeLayout{PageBreaks: []PageBreakHint{hint}}}
+		assert.ErrorIs(t, settings.Validate(), ErrInvalidField, "hint %+v", hint)
+	}
+}
+
+func TestResumeLayoutPriority(t *testing.T) {
+	settings := ResumeSettings{Layout: ResumeLayout{Priorities: []EntryPriority{
+		{Section: "Experience", Entry: 2, Priority: 1},
+		{Section: "projects", Entry: 0, Priority: 5},
+		{Section: "experience", Entry: 2, Priority: 4},
+	}}}
+	assert.NoError(t, settings.Validate())
+	settings.BeforeSave()
+	assert.Len(t, settings.Layout.Priorities, 2)
+
+	// The later priority of an entry wins; other entries have the default
+	assert.Equal(t, 4, settings.Layout.Priority(LayoutSectionExperience, 2))
+	assert.Equal(t, MaxEntryPriority, settings.Layout.Priority(LayoutSectionProjects, 0))
+	assert.Equal(t, DefaultEntryPriority, settings.Layout.Priority(LayoutSectionProjects, 1))
+
+	for _, p := range []EntryPriority{
+		{Section: "skills", Entry: 0, Priority: 3},
+		{Section: "summary", Entry: 0, Priority: 3},
+		{Section: "education", Entry: -1, Priority: 3},
+		{Section: "education", Entry: 0, Priority: 0},
+		{Section: "education", Entry: 0, Priority: 6},
+	} {
+		settings := ResumeSettings{Layout: ResumeLayout{Priorities: []EntryPriority{p}}}
+		assert.ErrorIs(t, settings.Validate(), ErrInvalidField, "priority %+v", p)
+	}
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Share link passphrase length limits
+const (
+	MinSharePassphraseLength = 8
+	MaxSharePassphraseLength = 100
+)
+
+// Parts of a resume a share link can hide
+const (
+	ShareFieldEmail          = "email"
+	ShareFieldPhone          = "phone"
+	ShareFieldAddress        = "address"
+	ShareFieldPhoto          = "photo"
+	ShareFieldExperience     = "experience"
+	ShareFieldEducation      = "education"
+	ShareFieldSkills         = "skills"
+	ShareFieldProjects       = "projects"
+	ShareFieldCertifications = "certifications"
+)
+
+// HideableShareFields lists the contact details and sections a share link can hide
+var HideableShareFields = []string{
+	ShareFieldEmail,
+	ShareFieldPhone,
+	ShareFieldAddress,
+	ShareFieldPhoto,
+	ShareFieldExperience,
+	ShareFieldEducation,
+	ShareFieldSkills,
+	ShareFieldProjects,
+	ShareFieldCertifications,
+}
+
+// ResumeShare is a link giving read access to a resume to anyone holding its
+// token and, when the link is protected, its passphrase
+type ResumeShare struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	ResumeID uuid.UUID `json:"resume_id" db:"resume_id"`
+	Token    string    `json:"token" db:"token"`
+	// PassphraseHash is the argon2id hash of the passphrase, empty for open links
+	PassphraseHash string `json:"-" db:"passphrase_hash"`
+	ShareLimits
+	// Hidden lists the fields and sections viewers of the link don't see
+	Hidden    []string  `json:"hidden,omitempty" db:"hidden"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ShareLimits stop a share link from working after a number of views or at a
+// set time; zero values mean no limit
+type ShareLimits struct {
+	MaxViews  int       `json:"max_views,omitempty" db:"max_views"`
+	ExpiresAt time.Time `json:"expires_at,omitzero" db:"expires_at"`
+}
+
+// Validate validates the share limits
+func (l *ShareLimits) Validate() error {
+	if l.MaxViews < 0 {
+		return NewValidationError("max_views", "Maximum views must be positive", ErrInvalidField)
+	}
+	if !l.ExpiresAt.IsZero() && !l.ExpiresAt.After(time.Now()) {
+		return NewValidationError("expires_at", "Expiry must be in the future", ErrInvalidField)
+	}
+	return nil
+}
+
+// Expired reports whether the link has passed its expiry at now
+func (l *ShareLimits) Expired(now time.Time) bool {
+	return !l.ExpiresAt.IsZero() && !now.Before(l.ExpiresAt)
+}
+
+// Protected reports whether viewers must enter a passphrase
+func (s *ResumeShare) Protected() bool {
+	return s.PassphraseHash != ""
+}
+
+// Hides reports whether the link hides a field or section
+func (s *ResumeShare) Hides(field string) bool {
+	return slices.Contains(s.Hidden, field)
+}
+
+// Redact returns a copy of resume without the fields and sections the link
+// hides. resume itself is left unchanged.
+func (s *ResumeShare) Redact(resume *Resume) *Resume {
+	redacted := *resume
+
+	if info := resume.PersonalInfo; info != nil {
+		copied := *info
+		if s.Hides(ShareFieldEmail) {
+			copied.Email = ""
+		}
+		if s.Hides(ShareFieldPhone) {
+			copied.Phone = ""
+			copied.PhoneCountry = ""
+			copied.PhoneDisplay = ""
+		}
+		if s.Hides(ShareFieldAddress) {
+			copied.Address = Address{}
+		}
+		if s.Hides(ShareFieldPhoto) {
+			copied.PhotoURL = ""
+		}
+		redacted.PersonalInfo = &copied
+	}
+	if s.Hides(ShareFieldExperience) {
+		redacted.Experience = nil
+	}
+	if s.Hides(ShareFieldEducation) {
+		redacted.Education = nil
+	}
+	if s.Hides(ShareFieldSkills) {
+		redacted.Skills = nil
+	}
+	if s.Hides(ShareFieldProjects) {
+		redacted.Projects = nil
+	}
+	if s.Hides(ShareFieldCertifications) {
+		redacted.Certifications = nil
+	}
+
+	return &redacted
+}
+
+// NormalizeShareHidden checks that every entry names a hideable field or
+// section and returns them sorted without duplicates
+func NormalizeShareHidden(hidden []string) ([]string, error) {
+	normalized := make([]string, 0, len(hidden))
+	for _, field := range hidden {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !slices.Contains(HideableShareFields, field) {
+			return nil, NewValidationError("hidden", fmt.Sprintf("Hidden fields must be among: %s", strings.Join(HideableShareFields, ", ")), ErrInvalidField)
+		}
+		normalized = append(normalized, field)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+// ValidateSharePassphrase checks the length of a share link passphrase
+func ValidateSharePassphrase(passphrase string) error {
+	length := utf8.RuneCountInString(passphrase)
+	if length < MinSharePassphraseLength || length > MaxSharePassphraseLength {
+		return NewValidationError("passphrase", "Passphrase must be between 8 and 100 characters", ErrInvalidField)
+	}
+	return nil
+}
+
+// ResumeShareRepository defines the interface for share link data operations
+type ResumeShareRepository interface {
+	CreateShare(ctx context.Context, share *ResumeShare) error
+	GetShareByID(ctx context.Context, resumeID, id uuid.UUID) (*ResumeShare, error)
+	GetShareByToken(ctx context.Context, token string) (*ResumeShare, error)
+	GetSharesByResumeID(ctx context.Context, resumeID uuid.UUID) ([]*ResumeShare, error)
+	UpdateShare(ctx context.Context, share *ResumeShare) error
+	DeleteShare(ctx context.Context, resumeID, id uuid.UUID) error
+	// DeleteSharesByResumeID deletes every share link of a resume, returning
+	// their IDs
+	DeleteSharesByResumeID(ctx context.Context, resumeID uuid.UUID) ([]uuid.UUID, error)
+}
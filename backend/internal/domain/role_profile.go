@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resume sections a role profile can expect
+const (
+	RoleSectionPersonalInfo   = "personal_info"
+	RoleSectionExperience     = "experience"
+	RoleSectionEducation      = "education"
+	RoleSectionSkills         = "skills"
+	RoleSectionProjects       = "projects"
+	RoleSectionCertifications = "certifications"
+)
+
+// RoleSections lists the sections a role profile can expect
+var RoleSections = []string{
+	RoleSectionPersonalInfo,
+	RoleSectionExperience,
+	RoleSectionEducation,
+	RoleSectionSkills,
+	RoleSectionProjects,
+	RoleSectionCertifications,
+}
+
+// Role profile sources
+const (
+	// RoleSourceBuiltin profiles ship with the service
+	RoleSourceBuiltin = "builtin"
+	// RoleSourceOverride profiles were saved by an admin, replacing the
+	// built-in profile with the same slug or adding a role
+	RoleSourceOverride = "override"
+)
+
+// roleSlugRe matches role slugs such as "backend-mid"
+var roleSlugRe = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// RoleProfile describes what resumes for a role are expected to show
+type RoleProfile struct {
+	// Slug identifies the profile in URLs, such as "backend-mid"
+	Slug string `json:"slug"`
+	// Name is shown to users, such as "Backend Engineer — Mid"
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Skills are expected of the role; resumes without them have gaps
+	Skills []RoleSkill `json:"skills"`
+	// NiceToHave skills are recommended without counting as gaps
+	NiceToHave []RoleSkill `json:"nice_to_have,omitempty"`
+	// Sections are the resume sections expected to have entries
+	Sections []string `json:"sections,omitempty"`
+	// MinExperienceYears is the work experience expected, in years
+	MinExperienceYears float64 `json:"min_experience_years,omitempty"`
+
+	// Source tells built-in profiles from admin overrides
+	Source    string    `json:"source,omitempty"`
+	UpdatedBy uuid.UUID `json:"updated_by,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// RoleSkill is a skill a role expects
+type RoleSkill struct {
+	Name string `json:"name"`
+	// Aliases are other names of the skill, such as "Postgres" for
+	// PostgreSQL
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Validate validates the role profile
+func (p *RoleProfile) Validate() error {
+	if !roleSlugRe.MatchString(p.Slug) || len(p.Slug) > 100 {
+		return NewValidationError("slug", "Slug must be lowercase letters and digits separated by hyphens, such as \"backend-mid\"", ErrInvalidField)
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return NewValidationError("name", "Name is required", ErrInvalidField)
+	}
+	if len(p.Skills) == 0 {
+		return NewValidationError("skills", "At least one skill is required", ErrInvalidField)
+	}
+	for _, skill := range slices.Concat(p.Skills, p.NiceToHave) {
+		if strings.TrimSpace(skill.Name) == "" {
+			return NewValidationError("skills", "Skill names are required", ErrInvalidField)
+		}
+	}
+	for _, section := range p.Sections {
+		if !slices.Contains(RoleSections, section) {
+			return NewValidationError("sections", fmt.Sprintf("Sections must be among: %s", strings.Join(RoleSections, ", ")), ErrInvalidField)
+		}
+	}
+	if p.MinExperienceYears < 0 || p.MinExperienceYears > 50 {
+		return NewValidationError("min_experience_years", "Minimum experience must be from 0 to 50 years", ErrInvalidField)
+	}
+	return nil
+}
+
+// RoleProfileRepository defines the interface for the role profiles admins
+// save over the built-in ones
+type RoleProfileRepository interface {
+	ListRoleProfiles(ctx context.Context) ([]*RoleProfile, error)
+	SaveRoleProfile(ctx context.Context, profile *RoleProfile) error
+	DeleteRoleProfile(ctx context.Context, slug string) error
+}
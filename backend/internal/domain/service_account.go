@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Service account scopes
+const (
+	ScopeMetricsRead = "metrics:read"
+	ScopeUsersRead   = "users:read"
+	ScopeJobsRead    = "jobs:read"
+)
+
+// ServiceAccountScopes lists the scopes a service account can be granted
+var ServiceAccountScopes = []string{
+	ScopeJobsRead,
+	ScopeMetricsRead,
+	ScopeUsersRead,
+}
+
+// RoleServiceAccount is the role in the claims of requests made by service
+// accounts, which no user has
+const RoleServiceAccount = "service"
+
+// MaxServiceAccountDescriptionLength caps the description of a service account
+const MaxServiceAccountDescriptionLength = 500
+
+// Service account audit actions
+const (
+	ServiceAccountActionCreate    = "create"
+	ServiceAccountActionRotateKey = "rotate_key"
+	ServiceAccountActionDelete    = "delete"
+	// ServiceAccountActionRequest is a request the account made
+	ServiceAccountActionRequest = "request"
+)
+
+// ServiceAccount lets an integration, such as a dashboard or backup tooling,
+// call the admin API within its scopes. Unlike API keys it acts as no user,
+// and only admins manage it.
+type ServiceAccount struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description,omitempty" db:"description"`
+	// Prefix is the start of the account's key, shown to tell keys apart
+	Prefix string `json:"prefix" db:"prefix"`
+	// KeyHash is the hex SHA-256 hash of the account's key
+	KeyHash string   `json:"-" db:"key_hash"`
+	Scopes  []string `json:"scopes" db:"scopes"`
+	// ExpiresAt stops the key working at that time when set
+	ExpiresAt  time.Time `json:"expires_at,omitzero" db:"expires_at"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero" db:"last_used_at"`
+	// CreatedBy is the admin who created the account, nil once deleted
+	CreatedBy uuid.UUID `json:"created_by,omitzero" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Expired reports whether the account's key has passed its expiry at now
+func (a *ServiceAccount) Expired(now time.Time) bool {
+	return !a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt)
+}
+
+// HasScope reports whether the account was granted a scope
+func (a *ServiceAccount) HasScope(scope string) bool {
+	return slices.Contains(a.Scopes, scope)
+}
+
+// Validate validates the account's name, description and expiry
+func (a *ServiceAccount) Validate() error {
+	name := strings.TrimSpace(a.Name)
+	if name == "" {
+		return NewValidationError("name", "Name is required", ErrInvalidField)
+	}
+	if utf8.RuneCountInString(name) > MaxAPIKeyNameLength {
+		return NewValidationError("name", "Name cannot be longer than 100 characters", ErrInvalidField)
+	}
+	if utf8.RuneCountInString(a.Description) > MaxServiceAccountDescriptionLength {
+		return NewValidationError("description", "Description cannot be longer than 500 characters", ErrInvalidField)
+	}
+	if !a.ExpiresAt.IsZero() && !a.ExpiresAt.After(time.Now()) {
+		return NewValidationError("expires_at", "Expiry must be in the future", ErrInvalidField)
+	}
+	return nil
+}
+
+// NormalizeServiceAccountScopes checks that every entry names a service
+// account scope and returns them sorted without duplicates. At least one
+// scope is required.
+func NormalizeServiceAccountScopes(scopes []string) ([]string, error) {
+	return normalizeScopes(scopes, ServiceAccountScopes)
+}
+
+// ServiceAccountAuditEntry records something done to or by a service account.
+// Entries outlive the account.
+type ServiceAccountAuditEntry struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	ServiceAccountID uuid.UUID `json:"service_account_id" db:"service_account_id"`
+	// ActorID is the admin who acted, nil for the account's own requests
+	ActorID uuid.UUID `json:"actor_id,omitzero" db:"actor_id"`
+	Action  string    `json:"action" db:"action"`
+	// Details describe the action, such as the path of a request
+	Details   json.RawMessage `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ServiceAccountRepository defines the interface for service account data operations
+type ServiceAccountRepository interface {
+	CreateServiceAccount(ctx context.Context, account *ServiceAccount) error
+	GetServiceAccount(ctx context.Context, id uuid.UUID) (*ServiceAccount, error)
+	GetServiceAccountByHash(ctx context.Context, keyHash string) (*ServiceAccount, error)
+	ListServiceAccounts(ctx context.Context) ([]*ServiceAccount, error)
+	// UpdateServiceAccountKey replaces the key of an account
+	UpdateServiceAccountKey(ctx context.Context, id uuid.UUID, prefix, keyHash string) error
+	// TouchServiceAccount records when an account was last used
+	TouchServiceAccount(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	DeleteServiceAccount(ctx context.Context, id uuid.UUID) error
+	AddServiceAccountAuditEntry(ctx context.Context, entry *ServiceAccountAuditEntry) error
+	// GetServiceAccountAuditEntries returns up to limit entries of an
+	// account, newest first
+	GetServiceAccountAuditEntries(ctx context.Context, serviceAccountID uuid.UUID, limit int) ([]*ServiceAccountAuditEntry, error)
+}
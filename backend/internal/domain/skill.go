@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/lordaris/resume_generator/pkg/sanitize"
 )
 
 // Skill categories
@@ -31,6 +33,29 @@ type Skill struct {
 	Proficiency int    `json:"proficiency,omitempty"` // 1-5 scale
 }
 
+// SkillGroup holds the skills of one category
+type SkillGroup struct {
+	Category string
+	Skills   []*Skill
+}
+
+// GroupSkills groups skills by category, keeping categories in order of first
+// use and skills in their original order
+func GroupSkills(skills []*Skill) []SkillGroup {
+	var groups []SkillGroup
+	index := make(map[string]int)
+	for _, s := range skills {
+		i, seen := index[s.Category]
+		if !seen {
+			i = len(groups)
+			index[s.Category] = i
+			groups = append(groups, SkillGroup{Category: s.Category})
+		}
+		groups[i].Skills = append(groups[i].Skills, s)
+	}
+	return groups
+}
+
 // Validate validates the skill entry
 func (s *Skill) Validate() error {
 	// Validate required fields
@@ -57,8 +82,8 @@ func (s *Skill) Validate() error {
 
 // BeforeSave sanitizes the data before saving
 func (s *Skill) BeforeSave() {
-	s.Name = strings.TrimSpace(s.Name)
-	s.Category = strings.TrimSpace(s.Category)
+	s.Name = sanitize.Line(s.Name)
+	s.Category = sanitize.Line(s.Category)
 
 	// Set default category if not provided
 	if s.Category == "" {
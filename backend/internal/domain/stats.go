@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AdminStats are aggregate counts of the instance, for an operations dashboard
+type AdminStats struct {
+	Users          int64 `json:"users"`
+	Resumes        int64 `json:"resumes"`
+	ActiveSessions int64 `json:"active_sessions"`
+	// ResumesPerUser counts users by how many resumes they have, fewest first
+	ResumesPerUser []ResumeCount `json:"resumes_per_user"`
+	// ExportsPerDay counts exports per UTC day, oldest first, including
+	// days without any
+	ExportsPerDay []PeriodCount `json:"exports_per_day"`
+	// SignupsPerWeek counts new users per week starting on Monday, oldest
+	// first, including weeks without any
+	SignupsPerWeek []PeriodCount `json:"signups_per_week"`
+	GeneratedAt    time.Time     `json:"generated_at"`
+}
+
+// ResumeCount is how many users have a number of resumes
+type ResumeCount struct {
+	Resumes int64 `json:"resumes"`
+	Users   int64 `json:"users"`
+}
+
+// PeriodCount is a count over the period starting at Start
+type PeriodCount struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+// StatsRepository defines the interface for the aggregate counts of admin
+// statistics
+type StatsRepository interface {
+	CountActiveSessions(ctx context.Context, now time.Time) (int64, error)
+	// GetResumesPerUser counts users by how many resumes they have,
+	// including users without any
+	GetResumesPerUser(ctx context.Context) ([]ResumeCount, error)
+	// GetExportsPerDay counts exports per UTC day since a time, leaving out
+	// days without any
+	GetExportsPerDay(ctx context.Context, since time.Time) ([]PeriodCount, error)
+	// GetSignupsPerWeek counts new users per week since a time, leaving out
+	// weeks without any
+	GetSignupsPerWeek(ctx context.Context, since time.Time) ([]PeriodCount, error)
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// Transactor runs a unit of work in a database transaction: the repository
+// calls fn makes with the context it is given commit or roll back together
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
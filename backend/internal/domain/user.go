@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"context"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,11 +15,75 @@ type User struct {
 	Email        string    `json:"email" db:"email"`
 	PasswordHash string    `json:"-" db:"password_hash"`
 	Role         string    `json:"role" db:"role"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	// Timezone is the IANA time zone times are shown to the user in
+	Timezone string `json:"timezone" db:"timezone"`
+	// WeeklyDigest is whether the user receives the weekly activity digest
+	WeeklyDigest bool `json:"weekly_digest" db:"weekly_digest"`
+	// Locale is the BCP 47 tag, such as "en-US", new resumes take their
+	// formatting settings from; empty if the user hasn't chosen one
+	Locale string `json:"locale" db:"locale"`
+	// DigestSentAt is when the last weekly digest was sent, zero if never
+	DigestSentAt time.Time `json:"-" db:"digest_sent_at"`
+	// PasskeyRequired is whether password logins must be confirmed with one
+	// of the user's passkeys
+	PasskeyRequired bool      `json:"passkey_required" db:"passkey_required"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// foldEmailPlusTags is whether NormalizeEmail drops "+tag" suffixes
+var foldEmailPlusTags atomic.Bool
+
+// SetEmailPlusTagFolding sets whether NormalizeEmail drops the "+tag" suffix
+// of local parts, making user+jobs@example.com the same account as
+// user@example.com
+func SetEmailPlusTagFolding(fold bool) {
+	foldEmailPlusTags.Store(fold)
 }
 
-// Session represents a user session
+// NormalizeEmail returns the form account emails are stored and looked up
+// in: trimmed and lowercased, and without a "+tag" suffix when folding is on
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !foldEmailPlusTags.Load() {
+		return email
+	}
+	at := strings.LastIndexByte(email, '@')
+	if plus := strings.IndexByte(email, '+'); plus > 0 && plus < at {
+		return email[:plus] + email[at:]
+	}
+	return email
+}
+
+// DefaultTimezone is the time zone of users who haven't chosen one
+const DefaultTimezone = "UTC"
+
+// ValidateTimezone checks that name is an IANA time zone such as "Europe/Berlin"
+func ValidateTimezone(name string) error {
+	// "Local" would be the server's zone, which means nothing to the user
+	if name == "" || name == "Local" {
+		return NewValidationError("timezone", "Time zone is required", ErrInvalidField)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return NewValidationError("timezone", "Unknown time zone", ErrInvalidField)
+	}
+	return nil
+}
+
+// Location returns the user's time zone, or UTC when it is unset or unknown
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" || u.Timezone == "Local" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Session represents a user session. Refreshing it rotates its token in
+// place, so CreatedAt is the time of the login.
 type Session struct {
 	ID           uuid.UUID `json:"id" db:"id"`
 	UserID       uuid.UUID `json:"user_id" db:"user_id"`
@@ -25,6 +92,7 @@ type Session struct {
 	ClientIP     string    `json:"client_ip" db:"client_ip"`
 	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	LastUsedAt   time.Time `json:"last_used_at" db:"last_used_at"`
 }
 
 // PasswordReset represents a password reset request
@@ -37,25 +105,50 @@ type PasswordReset struct {
 	UsedAt    time.Time `json:"used_at,omitempty" db:"used_at"`
 }
 
+// EmailChange represents a pending change of a user's email address
+type EmailChange struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	NewEmail    string    `json:"new_email" db:"new_email"`
+	Token       string    `json:"token" db:"token"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ConfirmedAt time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+}
+
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
 	// User operations
-	CreateUser(user *User) error
-	GetUserByID(id uuid.UUID) (*User, error)
-	GetUserByEmail(email string) (*User, error)
-	UpdateUser(user *User) error
-	DeleteUser(id uuid.UUID) error
+	CreateUser(ctx context.Context, user *User) error
+	GetUserByID(ctx context.Context, id uuid.UUID) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	UpdateUser(ctx context.Context, user *User) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	ListUsers(ctx context.Context, opts ListOptions) ([]*User, int64, error)
+
+	// Weekly digest operations
+	ListDigestRecipients(ctx context.Context, sentBefore time.Time, afterID uuid.UUID, limit int) ([]*User, error)
+	MarkDigestSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
 
 	// Session operations
-	CreateSession(session *Session) error
-	GetSessionByID(id uuid.UUID) (*Session, error)
-	GetSessionByToken(token string) (*Session, error)
-	DeleteSession(id uuid.UUID) error
-	DeleteUserSessions(userID uuid.UUID) error
+	CreateSession(ctx context.Context, session *Session) error
+	GetSessionByID(ctx context.Context, id uuid.UUID) (*Session, error)
+	GetSessionByToken(ctx context.Context, token string) (*Session, error)
+	GetSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]*Session, error)
+	RotateSession(ctx context.Context, session *Session, previousToken string) error
+	DeleteSession(ctx context.Context, id uuid.UUID) error
+	DeleteUserSessions(ctx context.Context, userID uuid.UUID) error
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
 
 	// Password reset operations
-	CreatePasswordReset(reset *PasswordReset) error
-	GetPasswordResetByToken(token string) (*PasswordReset, error)
-	MarkPasswordResetUsed(id uuid.UUID) error
-	DeleteExpiredPasswordResets() error
+	CreatePasswordReset(ctx context.Context, reset *PasswordReset) error
+	GetPasswordResetByToken(ctx context.Context, token string) (*PasswordReset, error)
+	MarkPasswordResetUsed(ctx context.Context, id uuid.UUID) error
+	DeleteExpiredPasswordResets(ctx context.Context) error
+
+	// Email change operations
+	CreateEmailChange(ctx context.Context, change *EmailChange) error
+	GetEmailChangeByToken(ctx context.Context, token string) (*EmailChange, error)
+	MarkEmailChangeConfirmed(ctx context.Context, id uuid.UUID) error
+	DeleteExpiredEmailChanges(ctx context.Context) error
 }
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserEmailConflict is an account whose address collided with an older
+// account's when emails became case-insensitive. Addresses were never
+// verified, so the accounts may belong to different people: the account was
+// moved to a placeholder address and keeps its data until its owner merges
+// it into the account holding the address.
+type UserEmailConflict struct {
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	// Email is the address the account had before it moved to a placeholder
+	Email     string    `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// UserEmailConflictRepository defines the interface for accounts set aside
+// by the email normalization
+type UserEmailConflictRepository interface {
+	// GetConflictsByEmail returns the accounts set aside for an address,
+	// compared case-insensitively
+	GetConflictsByEmail(ctx context.Context, email string) ([]*UserEmailConflict, error)
+	// MergeUser moves the resumes of fromID, with their notes, documents,
+	// public pages, connectors and events, to intoID and deletes fromID in one
+	// transaction. Credentials such as API keys, passkeys and provider
+	// identities go with the deleted account.
+	MergeUser(ctx context.Context, fromID, intoID uuid.UUID) error
+}
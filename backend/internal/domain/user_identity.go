@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an OAuth provider
+type UserIdentity struct {
+	ID     uuid.UUID `json:"id" db:"id"`
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	// Provider is the name of the OAuth provider, such as "google"
+	Provider string `json:"provider" db:"provider"`
+	// Subject is the provider's stable ID of the account
+	Subject     string    `json:"-" db:"subject"`
+	Email       string    `json:"email" db:"email"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at" db:"last_login_at"`
+}
+
+// UserIdentityRepository defines the interface for user identity data operations
+type UserIdentityRepository interface {
+	GetIdentity(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	// GetIdentitiesByUserID returns the provider accounts linked to a user
+	GetIdentitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *UserIdentity) error
+	// CreateUserWithIdentity creates a user and links the identity to it in
+	// one transaction
+	CreateUserWithIdentity(ctx context.Context, user *User, identity *UserIdentity) error
+	// TouchIdentity records a sign-in, storing the account's current email
+	TouchIdentity(ctx context.Context, identity *UserIdentity) error
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserPhone is the phone number a user added. Once verified, it can receive
+// password reset codes by SMS.
+type UserPhone struct {
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	// Phone is the number in E.164 format
+	Phone string `json:"phone" db:"phone"`
+	// VerifiedAt is when the user confirmed a code sent to the number, zero
+	// until then
+	VerifiedAt time.Time `json:"verified_at,omitzero" db:"verified_at"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Verified reports whether the user confirmed the number
+func (p *UserPhone) Verified() bool {
+	return !p.VerifiedAt.IsZero()
+}
+
+// UserPhoneRepository defines the interface for user phone number operations
+type UserPhoneRepository interface {
+	// SavePhone sets a user's phone number, which is unverified until
+	// VerifyPhone is called for it
+	SavePhone(ctx context.Context, phone *UserPhone) error
+	GetPhone(ctx context.Context, userID uuid.UUID) (*UserPhone, error)
+	// VerifyPhone marks a user's number verified, as long as it hasn't
+	// changed from number since the code was sent. It fails with not found
+	// otherwise.
+	VerifyPhone(ctx context.Context, userID uuid.UUID, number string) error
+	DeletePhone(ctx context.Context, userID uuid.UUID) error
+}
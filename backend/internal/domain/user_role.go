@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Permissions a user role can grant. Users manage their own resumes and
+// account whatever their role; permissions reach beyond that.
+const (
+	// PermissionResumesReadAny views and exports any user's resumes
+	PermissionResumesReadAny = "resumes:read:any"
+	// PermissionResumesWriteAny changes and deletes any user's resumes and
+	// their connectors
+	PermissionResumesWriteAny = "resumes:write:any"
+	// PermissionUsersRead lists users
+	PermissionUsersRead = "users:read"
+	// PermissionUsersManage signs users out, imports users and sets their role
+	PermissionUsersManage = "users:manage"
+	// PermissionRolesManage edits user roles and role profiles
+	PermissionRolesManage = "roles:manage"
+	// PermissionSettingsManage edits instance settings and incidents and
+	// reads CSP reports
+	PermissionSettingsManage = "settings:manage"
+	// PermissionServiceAccountsManage creates, rotates and deletes service accounts
+	PermissionServiceAccountsManage = "service_accounts:manage"
+	// PermissionStatsRead reads instance and job queue statistics
+	PermissionStatsRead = "stats:read"
+)
+
+// Permissions lists the permissions a user role can grant
+var Permissions = []string{
+	PermissionResumesReadAny,
+	PermissionResumesWriteAny,
+	PermissionRolesManage,
+	PermissionServiceAccountsManage,
+	PermissionSettingsManage,
+	PermissionStatsRead,
+	PermissionUsersManage,
+	PermissionUsersRead,
+}
+
+// Built-in user roles. They can't be deleted, and the admin role always
+// grants every permission, including ones added later.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// RoleSupport is the role the instance starts with for support staff, who
+// view users and resumes without changing them
+const RoleSupport = "support"
+
+// builtinRolePermissions are the permissions of the roles the instance
+// starts with, used until roles are loaded from the database
+var builtinRolePermissions = map[string][]string{
+	RoleUser:    {},
+	RoleAdmin:   Permissions,
+	RoleSupport: {PermissionResumesReadAny, PermissionUsersRead},
+}
+
+// BuiltinRoleHasPermission reports whether one of the roles the instance
+// starts with grants a permission
+func BuiltinRoleHasPermission(role, permission string) bool {
+	return role == RoleAdmin || slices.Contains(builtinRolePermissions[role], permission)
+}
+
+// MaxUserRoleDescriptionLength caps the description of a user role
+const MaxUserRoleDescriptionLength = 500
+
+// userRoleNameRe matches role names such as "support" or "billing_admin"
+var userRoleNameRe = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
+// UserRole is a role users are given, granting permissions beyond their own
+// resumes and account
+type UserRole struct {
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Permissions []string  `json:"permissions" db:"permissions"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// HasPermission reports whether the role grants a permission
+func (r *UserRole) HasPermission(permission string) bool {
+	return r.Name == RoleAdmin || slices.Contains(r.Permissions, permission)
+}
+
+// Validate validates the role's name, description and permissions
+func (r *UserRole) Validate() error {
+	if !userRoleNameRe.MatchString(r.Name) {
+		return NewValidationError("name", "Name must be up to 50 lowercase letters, digits, hyphens and underscores, starting with a letter", ErrInvalidField)
+	}
+	if r.Name == RoleServiceAccount {
+		return NewValidationError("name", "Name is reserved for service accounts", ErrInvalidField)
+	}
+	if utf8.RuneCountInString(r.Description) > MaxUserRoleDescriptionLength {
+		return NewValidationError("description", fmt.Sprintf("Description cannot be longer than %d characters", MaxUserRoleDescriptionLength), ErrInvalidField)
+	}
+	for _, permission := range r.Permissions {
+		if !slices.Contains(Permissions, permission) {
+			return NewValidationError("permissions", fmt.Sprintf("Permissions must be among: %s", strings.Join(Permissions, ", ")), ErrInvalidField)
+		}
+	}
+	return nil
+}
+
+// BeforeSave sanitizes the data before saving. Permissions are lowercased
+// and sorted without duplicates.
+func (r *UserRole) BeforeSave() {
+	r.Name = strings.ToLower(strings.TrimSpace(r.Name))
+	r.Description = strings.TrimSpace(r.Description)
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		permissions = append(permissions, strings.ToLower(strings.TrimSpace(permission)))
+	}
+	slices.Sort(permissions)
+	r.Permissions = slices.Compact(permissions)
+}
+
+// UserRoleRepository defines the interface for user role data operations
+type UserRoleRepository interface {
+	// ListUserRoles retrieves every role, by name
+	ListUserRoles(ctx context.Context) ([]*UserRole, error)
+	// SaveUserRole creates a role or replaces the one with its name
+	SaveUserRole(ctx context.Context, role *UserRole) error
+	// DeleteUserRole deletes a role, which fails while users still have it
+	DeleteUserRole(ctx context.Context, name string) error
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "jane@example.com", NormalizeEmail("  Jane@Example.COM\n"))
+	assert.Equal(t, "jane+jobs@example.com", NormalizeEmail("Jane+Jobs@example.com"))
+
+	SetEmailPlusTagFolding(true)
+	t.Cleanup(func() { SetEmailPlusTagFolding(false) })
+	assert.Equal(t, "jane@example.com", NormalizeEmail("Jane+Jobs@example.com"))
+	assert.Equal(t, "jane@example.com", NormalizeEmail("jane+@example.com"))
+	// Only the local part has a tag
+	assert.Equal(t, "jane@mail+relay.example.com", NormalizeEmail("jane@mail+relay.example.com"))
+	assert.Equal(t, "+jane@example.com", NormalizeEmail("+jane@example.com"))
+}
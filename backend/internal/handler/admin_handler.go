@@ -1,34 +1,68 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
 	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
 )
 
 // AdminHandler handles admin-related requests
 type AdminHandler struct {
-	userRepo domain.UserRepository
+	userRepo    domain.UserRepository
+	authService *service.AuthService
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(userRepo domain.UserRepository) *AdminHandler {
+func NewAdminHandler(userRepo domain.UserRepository, authService *service.AuthService) *AdminHandler {
 	return &AdminHandler{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		authService: authService,
 	}
 }
 
-// GetUsersHandler handles fetching all users (admin only)
-func (h *AdminHandler) GetUsersHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+// GetUsersHandler handles listing users (admin only)
+func (h *AdminHandler) GetUsersHandler(w http.ResponseWriter, r *http.Request) error {
+	page, err := parsePageRequest(r, domain.SortByEmail)
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid query: "+err.Error())
 	}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message":  "Admin route accessed successfully",
-		"admin_id": claims.UserID,
-		"email":    claims.Email,
-	})
+	users, total, err := h.userRepo.ListUsers(r.Context(), page.Options)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get users")
+	}
+
+	RespondWithPage(w, page, users, total)
+	return nil
+}
+
+// RevokeUserTokensHandler signs a user out everywhere, revoking their
+// sessions and access tokens (admin only)
+func (h *AdminHandler) RevokeUserTokensHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid user ID")
+	}
+
+	if _, err := h.userRepo.GetUserByID(r.Context(), userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "User not found")
+		}
+		return apperror.Internal(err, "Failed to get user")
+	}
+
+	if err := h.authService.LogoutAll(r.Context(), userID); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to revoke user tokens")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to revoke tokens")
+	}
+
+	log.Ctx(r.Context()).Info().Str("user_id", userID.String()).Msg("Admin revoked user tokens")
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Tokens revoked successfully"})
+	return nil
 }
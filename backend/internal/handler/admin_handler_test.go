@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUsersHandler(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	handler := NewAdminHandler(mockRepo, nil)
+
+	users := []*domain.User{
+		{ID: uuid.New(), Email: "a@example.com", PasswordHash: "hash", Role: "user"},
+		{ID: uuid.New(), Email: "b@example.com", PasswordHash: "hash", Role: "admin"},
+	}
+	mockRepo.On("ListUsers", domain.ListOptions{
+		Limit:        2,
+		Offset:       2,
+		SortBy:       domain.SortByEmail,
+		CreatedAfter: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Search:       "example",
+	}).Return(users, int64(5), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users?page=2&limit=2&sort=email&order=asc&created_after=2025-01-01&q=example", nil)
+	rr := httptest.NewRecorder()
+	HandlerFunc(handler.GetUsersHandler).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "hash")
+
+	var body struct {
+		Data       []domain.User  `json:"data"`
+		Pagination PaginationMeta `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Len(t, body.Data, 2)
+	assert.Equal(t, PaginationMeta{Page: 2, Limit: 2, Total: 5, TotalPages: 3}, body.Pagination)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUsersHandlerInvalidQuery(t *testing.T) {
+	handler := NewAdminHandler(new(MockUserRepository), nil)
+
+	for _, query := range []string{"page=0", "limit=1000", "sort=title", "order=sideways", "created_before=yesterday"} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users?"+query, nil)
+			rr := httptest.NewRecorder()
+			HandlerFunc(handler.GetUsersHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
+
+func TestRevokeUserTokensHandler(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	jwtHandler := auth.NewJWT(auth.JWTConfig{Secret: "test-secret"})
+	authService := service.NewAuthService(mockRepo, jwtHandler, service.AuthServiceConfig{})
+	mr := miniredis.RunT(t)
+	authService.SetDenylist(auth.NewDenylist(redis.NewClient(&redis.Options{Addr: mr.Addr()}), auth.DefaultLeeway))
+	handler := NewAdminHandler(mockRepo, authService)
+
+	user := &domain.User{ID: uuid.New(), Email: "user@example.com", Role: "user"}
+	mockRepo.On("GetUserByID", user.ID).Return(user, nil)
+	mockRepo.On("DeleteUserSessions", user.ID).Return(nil)
+
+	// Tokens issued before the revocation stop working
+	token, err := jwtHandler.GenerateAccessToken(user.ID.String(), user.Email, user.Role)
+	require.NoError(t, err)
+	_, err = authService.ValidateAccessToken(context.Background(), token)
+	require.NoError(t, err)
+	// Tokens issued in the second of the revocation are kept, so move past it
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/"+user.ID.String()+"/revoke-tokens", nil)
+	req.SetPathValue("id", user.ID.String())
+	rr := httptest.NewRecorder()
+	HandlerFunc(handler.RevokeUserTokensHandler).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	_, err = authService.ValidateAccessToken(context.Background(), token)
+	assert.ErrorIs(t, err, service.ErrTokenRevoked)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRevokeUserTokensHandlerUnknownUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	handler := NewAdminHandler(mockRepo, nil)
+
+	userID := uuid.New()
+	mockRepo.On("GetUserByID", userID).Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/"+userID.String()+"/revoke-tokens", nil)
+	req.SetPathValue("id", userID.String())
+	rr := httptest.NewRecorder()
+	HandlerFunc(handler.RevokeUserTokensHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
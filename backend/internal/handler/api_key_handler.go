@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// APIKeyHandler handles users' personal API keys
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKeyRequest creates an API key
+type CreateAPIKeyRequest struct {
+	Name string `json:"name"`
+	// Scopes lists what the key may do: resumes:read, resumes:write
+	Scopes []string `json:"scopes"`
+	// ExpiresAt stops the key working at that time when set
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// CreateAPIKeyResponse returns a new key. The key itself is only shown here.
+type CreateAPIKeyResponse struct {
+	*domain.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeysResponse lists a user's API keys
+type APIKeysResponse struct {
+	Keys []*domain.APIKey `json:"keys"`
+}
+
+// maxAPIKeyUsageDays bounds how far back API key usage can be requested
+const maxAPIKeyUsageDays = 90
+
+// APIKeyUsageResponse returns the hourly request counts of an API key
+type APIKeyUsageResponse struct {
+	KeyID      uuid.UUID `json:"key_id"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero"`
+	// Requests is the total of the hours returned
+	Requests int64 `json:"requests"`
+	// Hours lists the hours the key was used in, oldest first
+	Hours []*domain.APIKeyUsage `json:"hours"`
+}
+
+// CreateAPIKeyHandler creates an API key for the current user
+func (h *APIKeyHandler) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req CreateAPIKeyRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	key, secret, err := h.apiKeyService.CreateKey(r.Context(), userID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrAPIKeyLimit):
+			return apperror.New(http.StatusUnprocessableEntity, apperror.CodeLimitExceeded, "Too many API keys, revoke one first")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to create API key")
+			return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to create API key")
+		}
+	}
+
+	RespondWithJSON(w, http.StatusCreated, CreateAPIKeyResponse{APIKey: key, Key: secret})
+	return nil
+}
+
+// GetAPIKeysHandler lists the current user's API keys
+func (h *APIKeyHandler) GetAPIKeysHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	keys, err := h.apiKeyService.Keys(r.Context(), userID)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get API keys")
+	}
+
+	RespondWithJSON(w, http.StatusOK, APIKeysResponse{Keys: keys})
+	return nil
+}
+
+// RevokeAPIKeyHandler deletes one of the current user's API keys
+func (h *APIKeyHandler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	keyID, err := uuid.Parse(r.PathValue("keyId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid API key ID")
+	}
+
+	if err := h.apiKeyService.RevokeKey(r.Context(), userID, keyID); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "API key not found")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("api_key_id", keyID.String()).Msg("Failed to revoke API key")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to revoke API key")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "API key revoked successfully"})
+	return nil
+}
+
+// GetAPIKeyUsageHandler returns the hourly request counts of one of the
+// current user's API keys over the last days, 7 unless the days query
+// parameter asks for up to 90
+func (h *APIKeyHandler) GetAPIKeyUsageHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	keyID, err := uuid.Parse(r.PathValue("keyId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid API key ID")
+	}
+
+	days := 7
+	if d := r.URL.Query().Get("days"); d != "" {
+		days, err = strconv.Atoi(d)
+		if err != nil || days < 1 || days > maxAPIKeyUsageDays {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid days")
+		}
+	}
+
+	since := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	key, usage, err := h.apiKeyService.Usage(r.Context(), userID, keyID, since)
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "API key not found")
+		}
+		return apperror.Internal(err, "Failed to get API key usage")
+	}
+
+	resp := APIKeyUsageResponse{KeyID: key.ID, LastUsedAt: key.LastUsedAt, Hours: usage}
+	for _, u := range usage {
+		resp.Requests += u.Requests
+	}
+	RespondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAPIKeyRepository holds API keys in memory
+type stubAPIKeyRepository struct {
+	domain.APIKeyRepository
+	keys []*domain.APIKey
+}
+
+func (s *stubAPIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	key.ID = uuid.New()
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *stubAPIKeyRepository) CountAPIKeysByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	return len(s.keys), nil
+}
+
+func (s *stubAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	for _, key := range s.keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (s *stubAPIKeyRepository) TouchAPIKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return nil
+}
+
+func TestAuthRequiredWithScope(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "ci@example.com", Role: "user"}
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetUserByID", user.ID).Return(user, nil)
+
+	jwtHandler := auth.NewJWT(auth.JWTConfig{Secret: "test-secret", AccessTokenExpiry: 15 * time.Minute})
+	authService := service.NewAuthService(userRepo, jwtHandler, service.AuthServiceConfig{})
+	apiKeyService := service.NewAPIKeyService(&stubAPIKeyRepository{}, userRepo, service.APIKeyServiceConfig{})
+	m := NewAuthMiddleware(authService, apiKeyService)
+
+	_, readKey, err := apiKeyService.CreateKey(context.Background(), user.ID, "CI", []string{domain.ScopeResumesRead}, time.Time{})
+	require.NoError(t, err)
+
+	var caller *auth.JWTClaims
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ = GetClaimsFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	call := func(h http.Handler, token string) int {
+		req := httptest.NewRequest("GET", "/api/v1/resumes", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// A key acts as its user on routes accepting its scope
+	assert.Equal(t, http.StatusNoContent, call(m.AuthRequiredWithScope(domain.ScopeResumesRead)(next), readKey))
+	require.NotNil(t, caller)
+	assert.Equal(t, user.ID.String(), caller.UserID)
+	assert.Equal(t, auth.TokenTypeAPIKey, caller.TokenType)
+
+	assert.Equal(t, http.StatusForbidden, call(m.AuthRequiredWithScope(domain.ScopeResumesWrite)(next), readKey))
+	assert.Equal(t, http.StatusUnauthorized, call(m.AuthRequiredWithScope(domain.ScopeResumesRead)(next), "rg_revoked"))
+
+	// Routes that don't name a scope only take access tokens
+	assert.Equal(t, http.StatusForbidden, call(m.AuthRequired(next), readKey))
+}
@@ -1,13 +1,13 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
 	"github.com/lordaris/resume_generator/internal/service"
 	"github.com/lordaris/resume_generator/pkg/security"
 	"github.com/redis/go-redis/v9"
@@ -18,25 +18,54 @@ import (
 type AuthHandler struct {
 	authService *service.AuthService
 	validator   *validator.Validate
-	rateLimiter *security.RateLimiter
+	rateLimiter security.Limiter
+	// strictLimiter guards the endpoints that check passwords or send email
+	strictLimiter security.Limiter
+	// channelLimiters additionally limit password reset requests per
+	// delivery channel
+	channelLimiters map[string]security.Limiter
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *service.AuthService, redisClient *redis.Client) *AuthHandler {
-	// Create rate limiter for auth endpoints
-	rateLimiterConfig := security.RateLimiterConfig{
-		Redis:    redisClient,
-		Limit:    100,         // 100 requests
-		Interval: time.Minute, // per minute
-	}
+// Reset delivery policies limit password reset requests per channel, on top
+// of the strict limit. Text messages cost money, so fewer are allowed.
+var (
+	ResetEmailRateLimit = security.RateLimitPolicy{Name: "reset_email", Limit: 5, Interval: time.Hour}
+	ResetSMSRateLimit   = security.RateLimitPolicy{Name: "reset_sms", Limit: 3, Interval: time.Hour}
+)
 
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authService *service.AuthService, redisClient redis.UniversalClient) *AuthHandler {
+	// Most endpoints here are public and count per client IP; changing the
+	// email counts per user
 	return &AuthHandler{
-		authService: authService,
-		validator:   validator.New(),
-		rateLimiter: security.NewRateLimiter(rateLimiterConfig),
+		authService:   authService,
+		validator:     validator.New(),
+		rateLimiter:   NewUserRateLimiter(security.DefaultRateLimitPolicy, redisClient),
+		strictLimiter: NewUserRateLimiter(security.StrictRateLimit, redisClient),
+		channelLimiters: map[string]security.Limiter{
+			service.ResetChannelEmail: NewUserRateLimiter(ResetEmailRateLimit, redisClient),
+			service.ResetChannelSMS:   NewUserRateLimiter(ResetSMSRateLimit, redisClient),
+		},
 	}
 }
 
+// SetRateLimiter replaces the limiter guarding the other endpoints
+func (h *AuthHandler) SetRateLimiter(limiter security.Limiter) {
+	h.rateLimiter = limiter
+}
+
+// SetStrictLimiter replaces the limiter guarding the endpoints that check
+// passwords or send email, such as one that fails closed
+func (h *AuthHandler) SetStrictLimiter(limiter security.Limiter) {
+	h.strictLimiter = limiter
+}
+
+// SetChannelLimiter replaces the limiter of password reset requests
+// delivered over channel
+func (h *AuthHandler) SetChannelLimiter(channel string, limiter security.Limiter) {
+	h.channelLimiters[channel] = limiter
+}
+
 // RegisterRequest represents a user registration request
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -58,33 +87,48 @@ type RefreshTokenRequest struct {
 // PasswordResetRequestRequest represents a password reset request request
 type PasswordResetRequestRequest struct {
 	Email string `json:"email" validate:"required,email"`
+	// Channel is how the reset is delivered: email (the default) or sms,
+	// which texts a code to the user's verified phone number
+	Channel string `json:"channel,omitempty" validate:"omitempty,oneof=email sms"`
 }
 
-// PasswordResetRequest represents a password reset request
+// PasswordResetRequest represents a password reset request, with either the
+// token sent by email or the email address and the code sent by SMS
 type PasswordResetRequest struct {
-	Token       string `json:"token" validate:"required"`
+	Token       string `json:"token,omitempty" validate:"required_without=Code"`
+	Email       string `json:"email,omitempty" validate:"required_with=Code,omitempty,email"`
+	Code        string `json:"code,omitempty" validate:"required_without=Token,omitempty,len=6,numeric"`
 	NewPassword string `json:"new_password" validate:"required,min=8,max=100"`
 }
 
+// ChangeEmailRequest represents an email change request
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ConfirmEmailChangeRequest represents an email change confirmation request
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // RegisterHandler handles user registration
-func (h *AuthHandler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+func (h *AuthHandler) RegisterHandler(w http.ResponseWriter, r *http.Request) error {
 	// Apply rate limiting
-	if ok := h.applyRateLimit(w, r); !ok {
-		return
+	if err := h.applyStrictRateLimit(w, r); err != nil {
+		return err
 	}
 
 	// Parse request body
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		RespondWithValidationError(w, validationErrors)
-		return
+		return validationError(validationErrors)
 	}
 
 	// Set default role if not provided
@@ -93,43 +137,40 @@ func (h *AuthHandler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Register user
-	user, err := h.authService.Register(req.Email, req.Password, req.Role)
+	user, err := h.authService.Register(r.Context(), req.Email, req.Password, req.Role)
 	if err != nil {
 		if errors.Is(err, service.ErrUserAlreadyExists) {
-			RespondWithError(w, http.StatusConflict, "User with this email already exists", "USER_EXISTS")
-			return
+			return apperror.New(http.StatusConflict, "USER_EXISTS", "User with this email already exists")
 		}
-		log.Error().Err(err).Msg("Failed to register user")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to register user", "REGISTRATION_FAILED")
-		return
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to register user")
+		return apperror.New(http.StatusInternalServerError, "REGISTRATION_FAILED", "Failed to register user")
 	}
 
 	// Return success response
-	RespondWithJSON(w, http.StatusCreated, map[string]any{
-		"message": "User registered successfully",
-		"user_id": user.ID,
+	RespondWithJSON(w, http.StatusCreated, RegisterResponse{
+		Message: "User registered successfully",
+		UserID:  user.ID,
 	})
+	return nil
 }
 
 // LoginHandler handles user login
-func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
+func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) error {
 	// Apply rate limiting
-	if ok := h.applyRateLimit(w, r); !ok {
-		return
+	if err := h.applyStrictRateLimit(w, r); err != nil {
+		return err
 	}
 
 	// Parse request body
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		RespondWithValidationError(w, validationErrors)
-		return
+		return validationError(validationErrors)
 	}
 
 	// Get client info
@@ -137,41 +178,41 @@ func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	clientIP := getClientIP(r)
 
 	// Login user
-	tokens, err := h.authService.Login(req.Email, req.Password, userAgent, clientIP)
+	tokens, err := h.authService.Login(r.Context(), req.Email, req.Password, userAgent, clientIP)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			// Return same error for invalid email or password to prevent user enumeration
-			RespondWithError(w, http.StatusUnauthorized, "Invalid email or password", "INVALID_CREDENTIALS")
-			return
+			return apperror.New(http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid email or password")
+		}
+		if handled, err := respondSecondFactor(w, err); handled {
+			return err
 		}
-		log.Error().Err(err).Msg("Failed to login user")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to login user", "LOGIN_FAILED")
-		return
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to login user")
+		return apperror.New(http.StatusInternalServerError, "LOGIN_FAILED", "Failed to login user")
 	}
 
 	// Return tokens
 	RespondWithJSON(w, http.StatusOK, tokens)
+	return nil
 }
 
 // RefreshTokenHandler handles token refresh
-func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) error {
 	// Apply rate limiting
-	if ok := h.applyRateLimit(w, r); !ok {
-		return
+	if err := h.applyRateLimit(w, r); err != nil {
+		return err
 	}
 
 	// Parse request body
 	var req RefreshTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		RespondWithValidationError(w, validationErrors)
-		return
+		return validationError(validationErrors)
 	}
 
 	// Get client info
@@ -179,7 +220,7 @@ func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request
 	clientIP := getClientIP(r)
 
 	// Refresh token
-	tokens, err := h.authService.RefreshToken(req.RefreshToken, userAgent, clientIP)
+	tokens, err := h.authService.RefreshToken(r.Context(), req.RefreshToken, userAgent, clientIP)
 	if err != nil {
 		status := http.StatusUnauthorized
 		code := "INVALID_TOKEN"
@@ -193,118 +234,139 @@ func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request
 			message = "Invalid session"
 		}
 
-		RespondWithError(w, status, message, code)
-		return
+		return apperror.New(status, code, message)
 	}
 
 	// Return tokens
 	RespondWithJSON(w, http.StatusOK, tokens)
+	return nil
 }
 
 // LogoutHandler handles user logout
-func (h *AuthHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+func (h *AuthHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) error {
 	// Apply rate limiting
-	if ok := h.applyRateLimit(w, r); !ok {
-		return
+	if err := h.applyRateLimit(w, r); err != nil {
+		return err
 	}
 
 	// Parse request body
 	var req RefreshTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		RespondWithValidationError(w, validationErrors)
-		return
+		return validationError(validationErrors)
 	}
 
+	// Revoke the access token too when the client sends it
+	accessToken, _ := extractTokenFromHeader(r)
+
 	// Logout user
-	if err := h.authService.Logout(req.RefreshToken); err != nil {
-		log.Error().Err(err).Msg("Failed to logout user")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to logout user", "LOGOUT_FAILED")
-		return
+	if err := h.authService.Logout(r.Context(), req.RefreshToken, accessToken); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to logout user")
+		return apperror.New(http.StatusInternalServerError, "LOGOUT_FAILED", "Failed to logout user")
 	}
 
 	// Return success response
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "User logged out successfully",
-	})
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "User logged out successfully"})
+	return nil
 }
 
 // RequestPasswordResetHandler handles password reset requests
-func (h *AuthHandler) RequestPasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+func (h *AuthHandler) RequestPasswordResetHandler(w http.ResponseWriter, r *http.Request) error {
 	// Apply rate limiting
-	if ok := h.applyRateLimit(w, r); !ok {
-		return
+	if err := h.applyStrictRateLimit(w, r); err != nil {
+		return err
 	}
 
 	// Parse request body
 	var req PasswordResetRequestRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		RespondWithValidationError(w, validationErrors)
-		return
+		return validationError(validationErrors)
+	}
+
+	channel := req.Channel
+	if channel == "" {
+		channel = service.ResetChannelEmail
+	}
+	if limiter, ok := h.channelLimiters[channel]; ok {
+		if err := applyRateLimit(w, r, limiter); err != nil {
+			return err
+		}
+	}
+
+	// Always return the same response whether or not the user exists or can
+	// receive texts, to prevent user enumeration. The token itself is only
+	// ever delivered to the account's email address or phone.
+	sent := MessageResponse{Message: "Password reset instructions sent if email exists"}
+	if channel == service.ResetChannelSMS {
+		sent.Message = "Password reset code sent if the account has a verified phone number"
 	}
 
 	// Request password reset
-	resetToken, err := h.authService.RequestPasswordReset(req.Email)
-	if err != nil {
-		if errors.Is(err, service.ErrUserNotFound) {
-			// Always return success even if user doesn't exist to prevent user enumeration
-			RespondWithJSON(w, http.StatusOK, map[string]any{
-				"message": "Password reset instructions sent if email exists",
-			})
-			return
+	if err := h.authService.RequestPasswordReset(r.Context(), req.Email, channel); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrPhoneNotVerified), errors.Is(err, service.ErrCodeLimitReached):
+			RespondWithJSON(w, http.StatusOK, sent)
+			return nil
+		case errors.Is(err, service.ErrChannelUnavailable):
+			return apperror.New(http.StatusBadRequest, "CHANNEL_UNAVAILABLE", "Password reset by SMS is not available")
 		}
-		log.Error().Err(err).Msg("Failed to request password reset")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to request password reset", "PASSWORD_RESET_FAILED")
-		return
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to request password reset")
+		return apperror.New(http.StatusInternalServerError, "PASSWORD_RESET_FAILED", "Failed to request password reset")
 	}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Password reset instructions sent",
-		"token":   resetToken,
-	})
+	RespondWithJSON(w, http.StatusOK, sent)
+	return nil
 }
 
 // ResetPasswordHandler handles password reset
-func (h *AuthHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+func (h *AuthHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) error {
 	// Apply rate limiting
-	if ok := h.applyRateLimit(w, r); !ok {
-		return
+	if err := h.applyStrictRateLimit(w, r); err != nil {
+		return err
 	}
 
 	// Parse request body
 	var req PasswordResetRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		RespondWithValidationError(w, validationErrors)
-		return
+		return validationError(validationErrors)
 	}
 
-	// Reset password
-	if err := h.authService.ResetPassword(req.Token, req.NewPassword); err != nil {
+	// Reset password, with the code texted to the user when one is given
+	var err error
+	if req.Code != "" {
+		err = h.authService.ResetPasswordWithCode(r.Context(), req.Email, req.Code, req.NewPassword)
+	} else {
+		err = h.authService.ResetPassword(r.Context(), req.Token, req.NewPassword)
+	}
+	if err != nil {
 		status := http.StatusBadRequest
 		code := "PASSWORD_RESET_FAILED"
 		message := "Failed to reset password"
 
-		if errors.Is(err, service.ErrInvalidToken) {
+		if errors.Is(err, service.ErrInvalidCode) {
+			code = "INVALID_CODE"
+			message = "Invalid or expired reset code"
+		} else if errors.Is(err, service.ErrChannelUnavailable) {
+			code = "CHANNEL_UNAVAILABLE"
+			message = "Password reset by SMS is not available"
+		} else if errors.Is(err, service.ErrInvalidToken) {
 			code = "INVALID_TOKEN"
 			message = "Invalid reset token"
 		} else if errors.Is(err, service.ErrExpiredToken) {
@@ -315,34 +377,118 @@ func (h *AuthHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Reques
 			message = "Reset token already used"
 		}
 
-		RespondWithError(w, status, message, code)
-		return
+		return apperror.New(status, code, message)
 	}
 
 	// Return success response
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Password reset successfully",
-	})
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Password reset successfully"})
+	return nil
 }
 
-// Helper functions
+// ChangeEmailHandler handles requests to change the authenticated user's email
+func (h *AuthHandler) ChangeEmailHandler(w http.ResponseWriter, r *http.Request) error {
+	// Apply rate limiting
+	if err := h.applyStrictRateLimit(w, r); err != nil {
+		return err
+	}
+
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
 
-// applyRateLimit applies rate limiting to a request
-func (h *AuthHandler) applyRateLimit(w http.ResponseWriter, r *http.Request) bool {
-	count, err := h.rateLimiter.CheckRateLimit(r.Context(), r)
+	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		if errors.Is(err, security.ErrRateLimitExceeded) {
-			RespondWithError(w, http.StatusTooManyRequests, "Rate limit exceeded", "RATE_LIMIT_EXCEEDED")
-			return false
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	// Parse request body
+	var req ChangeEmailRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		return validationError(validationErrors)
+	}
+
+	// Request email change
+	if err := h.authService.RequestEmailChange(r.Context(), userID, req.NewEmail, req.Password); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			return apperror.New(http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid password")
+		case errors.Is(err, service.ErrEmailUnchanged):
+			return apperror.New(http.StatusBadRequest, "EMAIL_UNCHANGED", "New email matches current email")
+		case errors.Is(err, service.ErrUserAlreadyExists):
+			return apperror.New(http.StatusConflict, "USER_EXISTS", "User with this email already exists")
+		case errors.Is(err, service.ErrUserNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "User not found")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Msg("Failed to request email change")
+			return apperror.New(http.StatusInternalServerError, "EMAIL_CHANGE_FAILED", "Failed to request email change")
+		}
+	}
+
+	RespondWithJSON(w, http.StatusAccepted, MessageResponse{Message: "Confirmation sent to the new email address"})
+	return nil
+}
+
+// ConfirmEmailChangeHandler handles confirmation of an email change
+func (h *AuthHandler) ConfirmEmailChangeHandler(w http.ResponseWriter, r *http.Request) error {
+	// Apply rate limiting
+	if err := h.applyRateLimit(w, r); err != nil {
+		return err
+	}
+
+	// Parse request body
+	var req ConfirmEmailChangeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		return validationError(validationErrors)
+	}
+
+	// Confirm the change and issue tokens carrying the new email
+	tokens, err := h.authService.ConfirmEmailChange(r.Context(), req.Token, r.UserAgent(), getClientIP(r))
+	if err != nil {
+		if handled, err := respondSecondFactor(w, err); handled {
+			return err
+		}
+		switch {
+		case errors.Is(err, service.ErrInvalidToken):
+			return apperror.New(http.StatusBadRequest, "INVALID_TOKEN", "Invalid confirmation token")
+		case errors.Is(err, service.ErrExpiredToken), errors.Is(err, service.ErrEmailChangeExpired):
+			return apperror.New(http.StatusBadRequest, "TOKEN_EXPIRED", "Confirmation token expired")
+		case errors.Is(err, service.ErrEmailChangeUsed):
+			return apperror.New(http.StatusBadRequest, "TOKEN_USED", "Confirmation token already used")
+		case errors.Is(err, service.ErrUserAlreadyExists):
+			return apperror.New(http.StatusConflict, "USER_EXISTS", "User with this email already exists")
+		case errors.Is(err, service.ErrUserNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "User not found")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Msg("Failed to confirm email change")
+			return apperror.New(http.StatusInternalServerError, "EMAIL_CHANGE_FAILED", "Failed to confirm email change")
 		}
-		log.Error().Err(err).Msg("Rate limiting error")
 	}
 
-	// Add rate limit headers with correct calculation
-	w.Header().Set("X-RateLimit-Limit", "100")
-	remaining := max(100-count, 0)
-	w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-	w.Header().Set("X-RateLimit-Reset", "60")
+	RespondWithJSON(w, http.StatusOK, tokens)
+	return nil
+}
+
+// Helper functions
+
+// applyRateLimit applies the default rate limit to a request
+func (h *AuthHandler) applyRateLimit(w http.ResponseWriter, r *http.Request) error {
+	return applyRateLimit(w, r, h.rateLimiter)
+}
 
-	return true
+// applyStrictRateLimit applies the strict rate limit to a request
+func (h *AuthHandler) applyStrictRateLimit(w http.ResponseWriter, r *http.Request) error {
+	return applyRateLimit(w, r, h.strictLimiter)
 }
@@ -2,8 +2,8 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,11 +12,14 @@ import (
 	"github.com/alicebob/miniredis/v2"
 	"github.com/google/uuid"
 	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
 	"github.com/lordaris/resume_generator/internal/service"
 	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/security"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock user repository
@@ -24,12 +27,12 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) CreateUser(user *domain.User) error {
+func (m *MockUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetUserByID(id uuid.UUID) (*domain.User, error) {
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -37,7 +40,7 @@ func (m *MockUserRepository) GetUserByID(id uuid.UUID) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetUserByEmail(email string) (*domain.User, error) {
+func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -45,22 +48,43 @@ func (m *MockUserRepository) GetUserByEmail(email string) (*domain.User, error)
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) UpdateUser(user *domain.User) error {
+func (m *MockUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) DeleteUser(id uuid.UUID) error {
+func (m *MockUserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) CreateSession(session *domain.Session) error {
+func (m *MockUserRepository) ListUsers(ctx context.Context, opts domain.ListOptions) ([]*domain.User, int64, error) {
+	args := m.Called(opts)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) ListDigestRecipients(ctx context.Context, sentBefore time.Time, afterID uuid.UUID, limit int) ([]*domain.User, error) {
+	args := m.Called(sentBefore, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkDigestSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	args := m.Called(id, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CreateSession(ctx context.Context, session *domain.Session) error {
 	args := m.Called(session)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetSessionByID(id uuid.UUID) (*domain.Session, error) {
+func (m *MockUserRepository) GetSessionByID(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -68,7 +92,7 @@ func (m *MockUserRepository) GetSessionByID(id uuid.UUID) (*domain.Session, erro
 	return args.Get(0).(*domain.Session), args.Error(1)
 }
 
-func (m *MockUserRepository) GetSessionByToken(token string) (*domain.Session, error) {
+func (m *MockUserRepository) GetSessionByToken(ctx context.Context, token string) (*domain.Session, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -76,22 +100,40 @@ func (m *MockUserRepository) GetSessionByToken(token string) (*domain.Session, e
 	return args.Get(0).(*domain.Session), args.Error(1)
 }
 
-func (m *MockUserRepository) DeleteSession(id uuid.UUID) error {
+func (m *MockUserRepository) GetSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Session), args.Error(1)
+}
+
+func (m *MockUserRepository) RotateSession(ctx context.Context, session *domain.Session, previousToken string) error {
+	args := m.Called(session, previousToken)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteSession(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) DeleteUserSessions(userID uuid.UUID) error {
+func (m *MockUserRepository) DeleteUserSessions(ctx context.Context, userID uuid.UUID) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) CreatePasswordReset(reset *domain.PasswordReset) error {
+func (m *MockUserRepository) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) error {
 	args := m.Called(reset)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetPasswordResetByToken(token string) (*domain.PasswordReset, error) {
+func (m *MockUserRepository) GetPasswordResetByToken(ctx context.Context, token string) (*domain.PasswordReset, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -99,12 +141,35 @@ func (m *MockUserRepository) GetPasswordResetByToken(token string) (*domain.Pass
 	return args.Get(0).(*domain.PasswordReset), args.Error(1)
 }
 
-func (m *MockUserRepository) MarkPasswordResetUsed(id uuid.UUID) error {
+func (m *MockUserRepository) MarkPasswordResetUsed(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) DeleteExpiredPasswordResets() error {
+func (m *MockUserRepository) DeleteExpiredPasswordResets(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CreateEmailChange(ctx context.Context, change *domain.EmailChange) error {
+	args := m.Called(change)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetEmailChangeByToken(ctx context.Context, token string) (*domain.EmailChange, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.EmailChange), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkEmailChangeConfirmed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteExpiredEmailChanges(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
@@ -168,7 +233,7 @@ func TestRegisterHandler(t *testing.T) {
 				"password": "password123",
 			},
 			setupMock: func() {
-				mockRepo.On("GetUserByEmail", "test@example.com").Return(nil, errors.New("not found"))
+				mockRepo.On("GetUserByEmail", "test@example.com").Return(nil, repository.ErrNotFound)
 				mockRepo.On("CreateUser", mock.AnythingOfType("*domain.User")).Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
@@ -188,7 +253,7 @@ func TestRegisterHandler(t *testing.T) {
 				mockRepo.On("GetUserByEmail", "existing@example.com").Return(user, nil)
 			},
 			expectedStatus: http.StatusConflict,
-			expectedBody:   `{"error":"User with this email already exists","code":"USER_EXISTS"}`,
+			expectedBody:   `"status":409,"detail":"User with this email already exists","instance":"/api/v1/register","code":"USER_EXISTS"`,
 		},
 		{
 			name: "Invalid email",
@@ -198,7 +263,7 @@ func TestRegisterHandler(t *testing.T) {
 			},
 			setupMock:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Validation failed","code":"VALIDATION_FAILED","details":{"fields":{"email":"Must be a valid email address"}}}`,
+			expectedBody:   `"status":400,"detail":"Validation failed","instance":"/api/v1/register","code":"VALIDATION_FAILED","error":"Validation failed","details":{"fields":{"email":"Must be a valid email address"}}`,
 		},
 		{
 			name: "Password too short",
@@ -208,7 +273,7 @@ func TestRegisterHandler(t *testing.T) {
 			},
 			setupMock:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Validation failed","code":"VALIDATION_FAILED","details":{"fields":{"password":"Must be at least 8 characters long"}}}`,
+			expectedBody:   `"status":400,"detail":"Validation failed","instance":"/api/v1/register","code":"VALIDATION_FAILED","error":"Validation failed","details":{"fields":{"password":"Must be at least 8 characters long"}}`,
 		},
 	}
 
@@ -226,7 +291,7 @@ func TestRegisterHandler(t *testing.T) {
 			rr := httptest.NewRecorder()
 
 			// Call handler
-			handler.RegisterHandler(rr, req)
+			HandlerFunc(handler.RegisterHandler).ServeHTTP(rr, req)
 
 			// Check response
 			assert.Equal(t, tc.expectedStatus, rr.Code)
@@ -244,11 +309,13 @@ func TestLoginHandler(t *testing.T) {
 	defer mr.Close()
 
 	// Create test user
+	passwordHash, err := security.HashPassword("password123", nil)
+	require.NoError(t, err)
 	testUserID := uuid.New()
 	testUser := &domain.User{
 		ID:           testUserID,
 		Email:        "test@example.com",
-		PasswordHash: "$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$NkOaJEF81zhyjzEgPpiFUzdmVL8/TxHIxc1qQB/qKj4", // "password123"
+		PasswordHash: passwordHash,
 		Role:         "user",
 	}
 
@@ -280,10 +347,10 @@ func TestLoginHandler(t *testing.T) {
 				"password": "password123",
 			},
 			setupMock: func() {
-				mockRepo.On("GetUserByEmail", "nonexistent@example.com").Return(nil, errors.New("not found"))
+				mockRepo.On("GetUserByEmail", "nonexistent@example.com").Return(nil, repository.ErrNotFound)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"Invalid email or password","code":"INVALID_CREDENTIALS"}`,
+			expectedBody:   `"status":401,"detail":"Invalid email or password","instance":"/api/v1/login","code":"INVALID_CREDENTIALS"`,
 		},
 		{
 			name: "Invalid password",
@@ -295,7 +362,7 @@ func TestLoginHandler(t *testing.T) {
 				mockRepo.On("GetUserByEmail", "test@example.com").Return(testUser, nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"Invalid email or password","code":"INVALID_CREDENTIALS"}`,
+			expectedBody:   `"status":401,"detail":"Invalid email or password","instance":"/api/v1/login","code":"INVALID_CREDENTIALS"`,
 		},
 	}
 
@@ -313,7 +380,7 @@ func TestLoginHandler(t *testing.T) {
 			rr := httptest.NewRecorder()
 
 			// Call handler
-			handler.LoginHandler(rr, req)
+			HandlerFunc(handler.LoginHandler).ServeHTTP(rr, req)
 
 			// Check response
 			assert.Equal(t, tc.expectedStatus, rr.Code)
@@ -324,3 +391,33 @@ func TestLoginHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestPasswordResetHandler(t *testing.T) {
+	handler, mockRepo, mr := setupTest(t)
+	defer mr.Close()
+
+	testUser := &domain.User{ID: uuid.New(), Email: "test@example.com", Role: "user"}
+	mockRepo.On("GetUserByEmail", "test@example.com").Return(testUser, nil)
+	mockRepo.On("GetUserByEmail", "nonexistent@example.com").Return(nil, repository.ErrNotFound)
+	mockRepo.On("CreatePasswordReset", mock.AnythingOfType("*domain.PasswordReset")).Return(nil)
+
+	request := func(email string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(map[string]any{"email": email})
+		req, _ := http.NewRequest("POST", "/api/v1/request-password-reset", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		HandlerFunc(handler.RequestPasswordResetHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	// The token is only emailed, and the response doesn't tell whether the
+	// account exists
+	existing := request("test@example.com")
+	unknown := request("nonexistent@example.com")
+	assert.Equal(t, http.StatusOK, existing.Code)
+	assert.Equal(t, http.StatusOK, unknown.Code)
+	assert.JSONEq(t, `{"message":"Password reset instructions sent if email exists"}`, existing.Body.String())
+	assert.Equal(t, unknown.Body.String(), existing.Body.String())
+	assert.NotContains(t, existing.Body.String(), "token")
+	mockRepo.AssertCalled(t, "CreatePasswordReset", mock.AnythingOfType("*domain.PasswordReset"))
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/connector"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+)
+
+// ConnectorHandler handles connectors that sync resume entries from external sources
+type ConnectorHandler struct {
+	connectorService  *service.ConnectorService
+	eventService      *service.ResumeEventService
+	permissionService *service.PermissionService
+}
+
+// NewConnectorHandler creates a new connector handler
+func NewConnectorHandler(connectorService *service.ConnectorService, eventService *service.ResumeEventService) *ConnectorHandler {
+	return &ConnectorHandler{
+		connectorService: connectorService,
+		eventService:     eventService,
+	}
+}
+
+// SetPermissionService checks access to other users' connectors against the
+// user roles stored in the database rather than the roles the instance
+// starts with
+func (h *ConnectorHandler) SetPermissionService(permissionService *service.PermissionService) {
+	h.permissionService = permissionService
+}
+
+// CreateConnectorRequest represents a request to connect an account to a resume
+type CreateConnectorRequest struct {
+	Provider string `json:"provider"`
+	Account  string `json:"account"`
+}
+
+// ResolveConflictRequest settles a sync conflict over an entry
+type ResolveConflictRequest struct {
+	// Resolution is local, upstream or merged
+	Resolution string `json:"resolution"`
+	// Value is the merged entry, for the merged resolution
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ConnectorsResponse lists the connectors of a resume
+type ConnectorsResponse struct {
+	Connectors []*domain.Connector `json:"connectors"`
+}
+
+// CreateConnectorHandler connects an account at a provider to a resume
+func (h *ConnectorHandler) CreateConnectorHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var req CreateConnectorRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	c, err := h.connectorService.Create(r.Context(), resume.UserID, resume.ID, req.Provider, req.Account)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrConnectorExists):
+			return apperror.New(http.StatusConflict, "CONNECTOR_EXISTS", "The account is already connected to this resume")
+		default:
+			return apperror.Internal(err, "Failed to create connector")
+		}
+	}
+
+	RespondWithJSON(w, http.StatusCreated, c)
+	return nil
+}
+
+// ListConnectorsHandler lists the connectors of a resume
+func (h *ConnectorHandler) ListConnectorsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	connectors, err := h.connectorService.Connectors(r.Context(), resume.ID)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get connectors")
+	}
+
+	RespondWithJSON(w, http.StatusOK, ConnectorsResponse{Connectors: connectors})
+	return nil
+}
+
+// SyncConnectorHandler re-imports a connector's entries from its source,
+// reporting entries changed both locally and upstream as conflicts rather
+// than overwriting the user's edits
+func (h *ConnectorHandler) SyncConnectorHandler(w http.ResponseWriter, r *http.Request) error {
+	c, err := h.ownedConnector(r)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.connectorService.Sync(r.Context(), c)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrConnectorNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Connector not found")
+		case errors.Is(err, connector.ErrAccountNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "The account was not found at the provider")
+		case errors.Is(err, connector.ErrUnavailable):
+			return apperror.Wrap(err, http.StatusBadGateway, "CONNECTOR_UNAVAILABLE", "The provider could not be reached, try again later")
+		case errors.Is(err, domain.ErrLimitExceeded):
+			return err
+		default:
+			return apperror.Internal(err, "Failed to sync connector")
+		}
+	}
+
+	for _, p := range result.Added {
+		recordImportEvent(r, h.eventService, p, domain.EventOpCreate)
+	}
+	for _, p := range result.Updated {
+		recordImportEvent(r, h.eventService, p, domain.EventOpUpdate)
+	}
+
+	RespondWithJSON(w, http.StatusOK, result)
+	return nil
+}
+
+// ResolveConflictHandler settles a connector's sync conflict over an entry
+func (h *ConnectorHandler) ResolveConflictHandler(w http.ResponseWriter, r *http.Request) error {
+	c, err := h.ownedConnector(r)
+	if err != nil {
+		return err
+	}
+
+	entityID, err := uuid.Parse(r.PathValue("entityId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid entity ID")
+	}
+
+	var req ResolveConflictRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	provenance, err := h.connectorService.Resolve(r.Context(), c, entityID, req.Resolution, req.Value)
+	if err != nil {
+		if errors.Is(err, service.ErrConflictNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "No pending conflict for this entry")
+		}
+		return provenanceError(err, "Failed to resolve conflict")
+	}
+
+	recordImportEvent(r, h.eventService, provenance, domain.EventOpUpdate)
+
+	RespondWithJSON(w, http.StatusOK, provenance)
+	return nil
+}
+
+// DeleteConnectorHandler disconnects a connector, keeping the entries it synced
+func (h *ConnectorHandler) DeleteConnectorHandler(w http.ResponseWriter, r *http.Request) error {
+	c, err := h.ownedConnector(r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.connectorService.Delete(r.Context(), c.ID); err != nil {
+		if errors.Is(err, service.ErrConnectorNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Connector not found")
+		}
+		return apperror.Internal(err, "Failed to delete connector")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Connector deleted successfully"})
+	return nil
+}
+
+// ownedConnector returns the connector named by the {id} path parameter,
+// checking that the authenticated user owns it (or that their role grants
+// changing any resume)
+func (h *ConnectorHandler) ownedConnector(r *http.Request) (*domain.Connector, error) {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return nil, apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return nil, apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid connector ID")
+	}
+
+	c, err := h.connectorService.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrConnectorNotFound) {
+			return nil, apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Connector not found")
+		}
+		return nil, apperror.Internal(err, "Failed to get connector")
+	}
+
+	if c.UserID.String() != claims.UserID {
+		allowed, err := hasPermission(r.Context(), h.permissionService, claims.Role, domain.PermissionResumesWriteAny)
+		if err != nil {
+			return nil, apperror.Internal(err, "Failed to check permissions")
+		}
+		if !allowed {
+			return nil, apperror.New(http.StatusForbidden, apperror.CodeForbidden, "You don't have permission to update this connector")
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// CSPReportPath is where browsers send Content-Security-Policy violation reports
+const CSPReportPath = "/api/v1/csp-report"
+
+// Bounds on the reports a client may send
+const (
+	// maxCSPReportSize caps the size of a violation report body
+	maxCSPReportSize = 64 << 10
+	// maxCSPReportsPerRequest caps the violations stored from one Reporting API batch
+	maxCSPReportsPerRequest = 20
+	// cspReportLimit is the number of report requests accepted per client and interval
+	cspReportLimit    = 60
+	cspReportInterval = time.Minute
+)
+
+// CSPReportHandler collects Content-Security-Policy violation reports and
+// lets admins review them
+type CSPReportHandler struct {
+	reportService *service.CSPReportService
+	rateLimiter   security.Limiter
+}
+
+// NewCSPReportHandler creates a new CSP report handler
+func NewCSPReportHandler(reportService *service.CSPReportService, redisClient redis.UniversalClient) *CSPReportHandler {
+	// A page with a broken policy reports on every load, so reports are
+	// limited per client IP to keep one visitor from flooding the table
+	rateLimiterConfig := security.RateLimiterConfig{
+		Redis:    redisClient,
+		Name:     "csp_report",
+		Limit:    cspReportLimit,
+		Interval: cspReportInterval,
+	}
+
+	return &CSPReportHandler{
+		reportService: reportService,
+		rateLimiter:   security.NewLimiter(rateLimiterConfig),
+	}
+}
+
+// CreateReportHandler receives Content-Security-Policy violation reports from
+// browsers. Browsers don't read the response, so it is always empty.
+func (h *CSPReportHandler) CreateReportHandler(w http.ResponseWriter, r *http.Request) error {
+	if _, err := h.rateLimiter.CheckRateLimit(r.Context(), r); err != nil {
+		if errors.Is(err, security.ErrRateLimitExceeded) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return nil
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Rate limiting error")
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCSPReportSize))
+	if err != nil {
+		return apperror.New(http.StatusRequestEntityTooLarge, apperror.CodeInvalidRequest, "Report too large")
+	}
+
+	violations, err := security.ParseCSPReports(body)
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid report")
+	}
+	if len(violations) > maxCSPReportsPerRequest {
+		violations = violations[:maxCSPReportsPerRequest]
+	}
+
+	if err := h.reportService.Record(r.Context(), violations); err != nil {
+		// The browser won't retry, so the report is only logged
+		log.Ctx(r.Context()).Error().Err(err).Int("violations", len(violations)).Msg("Failed to record CSP violations")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListReportsHandler returns the aggregated violations, most frequent first
+// unless sorted otherwise (admin only)
+func (h *CSPReportHandler) ListReportsHandler(w http.ResponseWriter, r *http.Request) error {
+	page, err := parsePageRequest(r, domain.SortByCount, domain.SortByLastSeenAt)
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid query: "+err.Error())
+	}
+	if r.URL.Query().Get("sort") == "" {
+		page.Options.SortBy = domain.SortByCount
+	}
+
+	violations, total, err := h.reportService.Violations(r.Context(), r.URL.Query().Get("directive"), page.Options)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get CSP reports")
+	}
+
+	RespondWithPage(w, page, violations, total)
+	return nil
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// violationRepository keeps CSP violations in memory
+type violationRepository struct {
+	domain.CSPViolationRepository
+	recorded  []*domain.CSPViolation
+	directive string
+	opts      domain.ListOptions
+}
+
+func (r *violationRepository) RecordViolation(ctx context.Context, violation *domain.CSPViolation) error {
+	r.recorded = append(r.recorded, violation)
+	return nil
+}
+
+func (r *violationRepository) ListViolations(ctx context.Context, directive string, opts domain.ListOptions) ([]*domain.CSPViolation, int64, error) {
+	r.directive, r.opts = directive, opts
+	return r.recorded, int64(len(r.recorded)), nil
+}
+
+func TestCreateCSPReportHandler(t *testing.T) {
+	repo := &violationRepository{}
+	h := NewCSPReportHandler(service.NewCSPReportService(repo, service.CSPReportServiceConfig{}), nil)
+
+	report := `{"csp-report":{"document-uri":"https://app.example.com/","violated-directive":"script-src","blocked-uri":"inline"}}`
+	for range cspReportLimit {
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.CreateReportHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, CSPReportPath, strings.NewReader(report)))
+		require.Equal(t, http.StatusNoContent, rr.Code)
+	}
+	assert.Len(t, repo.recorded, cspReportLimit)
+
+	// Clients that keep reporting are turned away
+	rr := httptest.NewRecorder()
+	HandlerFunc(h.CreateReportHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, CSPReportPath, strings.NewReader(report)))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Len(t, repo.recorded, cspReportLimit)
+
+	// Malformed reports are rejected
+	req := httptest.NewRequest(http.MethodPost, CSPReportPath, strings.NewReader("{"))
+	req.RemoteAddr = "192.0.2.2:1234"
+	rr = httptest.NewRecorder()
+	HandlerFunc(h.CreateReportHandler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestListCSPReportsHandler(t *testing.T) {
+	repo := &violationRepository{recorded: []*domain.CSPViolation{{ViolatedDirective: "script-src", BlockedURI: "inline", Count: 3}}}
+	h := NewCSPReportHandler(service.NewCSPReportService(repo, service.CSPReportServiceConfig{}), nil)
+
+	rr := httptest.NewRecorder()
+	HandlerFunc(h.ListReportsHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/admin/csp-reports?directive=Script-Src", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	// The most frequent violations come first by default
+	assert.Equal(t, "script-src", repo.directive)
+	assert.Equal(t, domain.SortByCount, repo.opts.SortBy)
+	assert.True(t, repo.opts.SortDesc)
+
+	var page CSPViolationPage
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, int64(3), page.Data[0].Count)
+	assert.Equal(t, int64(1), page.Pagination.Total)
+
+	rr = httptest.NewRecorder()
+	HandlerFunc(h.ListReportsHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/admin/csp-reports?sort=blocked_uri", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// DataExportHandler handles personal data export (takeout) requests
+type DataExportHandler struct {
+	exportService *service.DataExportService
+}
+
+// NewDataExportHandler creates a new data export handler
+func NewDataExportHandler(exportService *service.DataExportService) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+	}
+}
+
+// GetDataExportHandler returns the user's data archive when ready, otherwise starts or reports generation
+func (h *DataExportHandler) GetDataExportHandler(w http.ResponseWriter, r *http.Request) error {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	// Get user ID from claims
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	// Serve the archive if it has already been generated
+	archive, err := h.exportService.GetArchive(r.Context(), userID)
+	if err == nil {
+		defer archive.Body.Close()
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="resume-generator-data-export.zip"`)
+		if archive.Size > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(archive.Size, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, archive.Body); err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("Failed to write data export archive")
+		}
+		return nil
+	}
+	if !errors.Is(err, service.ErrDataExportNotFound) && !errors.Is(err, service.ErrDataExportNotReady) {
+		log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get data export")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to get data export")
+	}
+
+	// Start a new export (or report the one in progress)
+	status, err := h.exportService.RequestExport(r.Context(), userID)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to request data export")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to request data export")
+	}
+
+	w.Header().Set("Retry-After", "10")
+	RespondWithJSON(w, http.StatusAccepted, DataExportResponse{
+		Message: "Data export is being generated, retry this request to download it once ready",
+		Export:  status,
+	})
+	return nil
+}
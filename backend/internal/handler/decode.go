@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+)
+
+// JSONLimits bounds the request bodies a handler decodes. A small body can
+// still be expensive to decode when it nests deeply or packs huge arrays, so
+// the shape is checked before the body is decoded into the request type.
+type JSONLimits struct {
+	// MaxBytes caps the body size
+	MaxBytes int64
+	// MaxDepth caps how deeply objects and arrays nest
+	MaxDepth int
+	// MaxArrayLength caps the elements of any one array
+	MaxArrayLength int
+	// MaxStringLength caps the bytes of any one string, object keys included
+	MaxStringLength int
+}
+
+// DefaultJSONLimits apply to request bodies unless a route sets its own
+var DefaultJSONLimits = JSONLimits{
+	MaxBytes:        1 << 20,
+	MaxDepth:        32,
+	MaxArrayLength:  1000,
+	MaxStringLength: 64 << 10,
+}
+
+// JSONLimitError reports a request body over one of its limits
+type JSONLimitError struct {
+	Limit string
+	Max   int
+}
+
+func (e *JSONLimitError) Error() string {
+	return fmt.Sprintf("request body exceeds the maximum %s of %d", e.Limit, e.Max)
+}
+
+// decodeJSON decodes a request body within the default limits
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	return decodeJSONWithLimits(w, r, dst, DefaultJSONLimits)
+}
+
+// decodeJSONWithLimits decodes a request body into dst after checking it
+// against limits. An empty body returns io.EOF.
+func decodeJSONWithLimits(w http.ResponseWriter, r *http.Request, dst any, limits JSONLimits) error {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limits.MaxBytes))
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return io.EOF
+	}
+	if err := checkJSONShape(body, limits); err != nil {
+		return err
+	}
+	return json.Unmarshal(body, dst)
+}
+
+// checkJSONShape walks the tokens of a JSON document, failing on the first
+// that breaks a limit. Nothing is allocated per value beyond the token
+// itself, so an adversarial body costs no more than its size.
+func checkJSONShape(body []byte, limits JSONLimits) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	// arrays holds the element count of each open container; objects hold -1
+	var arrays []int
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == ']' || delim == '}') {
+			arrays = arrays[:len(arrays)-1]
+			continue
+		}
+
+		// Every other token in an array starts one of its elements
+		if n := len(arrays); n > 0 && arrays[n-1] >= 0 {
+			arrays[n-1]++
+			if arrays[n-1] > limits.MaxArrayLength {
+				return &JSONLimitError{Limit: "array length", Max: limits.MaxArrayLength}
+			}
+		}
+
+		switch value := token.(type) {
+		case json.Delim:
+			if len(arrays) == limits.MaxDepth {
+				return &JSONLimitError{Limit: "depth", Max: limits.MaxDepth}
+			}
+			if value == '[' {
+				arrays = append(arrays, 0)
+			} else {
+				arrays = append(arrays, -1)
+			}
+		case string:
+			if len(value) > limits.MaxStringLength {
+				return &JSONLimitError{Limit: "string length", Max: limits.MaxStringLength}
+			}
+		}
+	}
+}
+
+// decodeError reports a request body that could not be decoded
+func decodeError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	var limitErr *JSONLimitError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return apperror.Wrap(err, http.StatusRequestEntityTooLarge, apperror.CodeInvalidRequest, "Request body too large")
+	case errors.As(err, &limitErr):
+		return apperror.Wrap(err, http.StatusBadRequest, apperror.CodeInvalidRequest, limitErr.Error())
+	default:
+		return apperror.Wrap(err, http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid request body")
+	}
+}
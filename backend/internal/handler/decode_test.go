@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSONRejectsAdversarialBodies(t *testing.T) {
+	limits := JSONLimits{MaxBytes: 1 << 20, MaxDepth: 4, MaxArrayLength: 3, MaxStringLength: 8}
+
+	tests := []struct {
+		name  string
+		body  string
+		limit string
+	}{
+		{"deep arrays", strings.Repeat("[", 5) + strings.Repeat("]", 5), "depth"},
+		{"deep objects", strings.Repeat(`{"a":`, 5) + "1" + strings.Repeat("}", 5), "depth"},
+		{"unterminated nesting", strings.Repeat("[", 100000), "depth"},
+		{"long array", `[1,2,3,4]`, "array length"},
+		{"long nested array", `{"skills":[{"name":"a"},{"name":"b"},{"name":"c"},{"name":"d"}]}`, "array length"},
+		{"long string", `{"name":"123456789"}`, "string length"},
+		{"long key", `{"123456789":1}`, "string length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			var dst any
+			err := decodeJSONWithLimits(httptest.NewRecorder(), req, &dst, limits)
+
+			var limitErr *JSONLimitError
+			require.ErrorAs(t, err, &limitErr)
+			assert.Equal(t, tt.limit, limitErr.Limit)
+		})
+	}
+}
+
+func TestDecodeJSONWithinLimits(t *testing.T) {
+	limits := JSONLimits{MaxBytes: 1 << 20, MaxDepth: 4, MaxArrayLength: 3, MaxStringLength: 8}
+	body := `{"skills":[{"name":"go","tags":["a","b","c"]},{"name":"sql"}],"count":2}`
+
+	var dst struct {
+		Skills []struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		} `json:"skills"`
+		Count int `json:"count"`
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	require.NoError(t, decodeJSONWithLimits(httptest.NewRecorder(), req, &dst, limits))
+	assert.Len(t, dst.Skills, 2)
+	assert.Equal(t, []string{"a", "b", "c"}, dst.Skills[0].Tags)
+	assert.Equal(t, 2, dst.Count)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("  "))
+	assert.ErrorIs(t, decodeJSONWithLimits(httptest.NewRecorder(), req, &dst, limits), io.EOF)
+}
+
+func TestDecodeError(t *testing.T) {
+	limits := JSONLimits{MaxBytes: 16, MaxDepth: 4, MaxArrayLength: 3, MaxStringLength: 8}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"too large", `{"name":"` + strings.Repeat("a", 32) + `"}`, http.StatusRequestEntityTooLarge},
+		{"over a limit", `[[[[[]]]]]`, http.StatusBadRequest},
+		{"malformed", `{"name":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			var dst any
+			err := decodeJSONWithLimits(rr, req, &dst, limits)
+			require.Error(t, err)
+
+			WriteError(rr, req, decodeError(err))
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
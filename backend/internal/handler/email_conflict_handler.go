@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
+)
+
+// EmailConflictHandler handles merging accounts set aside when emails became
+// case-insensitive
+type EmailConflictHandler struct {
+	emailConflictService *service.EmailConflictService
+	// limiter guards the endpoint, which checks passwords
+	limiter security.Limiter
+}
+
+// NewEmailConflictHandler creates a new email conflict handler
+func NewEmailConflictHandler(emailConflictService *service.EmailConflictService, redisClient redis.UniversalClient) *EmailConflictHandler {
+	return &EmailConflictHandler{
+		emailConflictService: emailConflictService,
+		limiter:              NewUserRateLimiter(security.StrictRateLimit, redisClient),
+	}
+}
+
+// SetLimiter replaces the limiter guarding the merge endpoint
+func (h *EmailConflictHandler) SetLimiter(limiter security.Limiter) {
+	h.limiter = limiter
+}
+
+// MergeEmailConflictRequest confirms owning an account set aside for the
+// user's address
+type MergeEmailConflictRequest struct {
+	// Password is the password of the account set aside
+	Password string `json:"password"`
+}
+
+// MergeEmailConflictHandler merges the account set aside for the current
+// user's address whose password is given into the current user
+func (h *EmailConflictHandler) MergeEmailConflictHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := applyRateLimit(w, r, h.limiter); err != nil {
+		return err
+	}
+
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req MergeEmailConflictRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+	if req.Password == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "password is required")
+	}
+
+	if _, err := h.emailConflictService.MergeConflictingAccount(r.Context(), userID, req.Password); err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailConflictNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "No account was set aside for your email address")
+		case errors.Is(err, service.ErrInvalidCredentials):
+			return apperror.New(http.StatusUnauthorized, "INVALID_CREDENTIALS", "The password doesn't match an account set aside for your email address")
+		}
+		return apperror.Internal(err, "Failed to merge accounts")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Accounts merged"})
+	return nil
+}
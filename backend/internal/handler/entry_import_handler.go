@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// EntryImportHandler handles importing resume entries and their provenance
+type EntryImportHandler struct {
+	importService *service.EntryImportService
+	eventService  *service.ResumeEventService
+}
+
+// NewEntryImportHandler creates a new entry import handler
+func NewEntryImportHandler(importService *service.EntryImportService, eventService *service.ResumeEventService) *EntryImportHandler {
+	return &EntryImportHandler{
+		importService: importService,
+		eventService:  eventService,
+	}
+}
+
+// ImportEntriesRequest represents a request to import resume entries
+type ImportEntriesRequest struct {
+	Source  string                `json:"source"`
+	Entries []service.ImportEntry `json:"entries"`
+}
+
+// ResyncEntryRequest carries the value an imported entry has at its source now
+type ResyncEntryRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// ProvenanceResponse lists the provenance of a resume's imported entries
+type ProvenanceResponse struct {
+	Entries []*domain.Provenance `json:"entries"`
+}
+
+// ImportEntriesHandler adds entries from an import to a resume, recording
+// where each came from
+func (h *EntryImportHandler) ImportEntriesHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var req ImportEntriesRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	result, err := h.importService.Import(r.Context(), resume.ID, req.Source, req.Entries)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return err
+		}
+		return apperror.Internal(err, "Failed to import entries")
+	}
+
+	for _, entry := range result.Entries {
+		recordImportEvent(r, h.eventService, entry, domain.EventOpCreate)
+	}
+
+	RespondWithJSON(w, http.StatusCreated, result)
+	return nil
+}
+
+// ListProvenanceHandler lists where the imported entries of a resume came from
+func (h *EntryImportHandler) ListProvenanceHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	provenance, err := h.importService.Provenance(r.Context(), resume.ID)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get provenance")
+	}
+
+	RespondWithJSON(w, http.StatusOK, ProvenanceResponse{Entries: provenance})
+	return nil
+}
+
+// RevertEntryHandler restores an imported entry to the value it was imported,
+// or last re-synced, with
+func (h *EntryImportHandler) RevertEntryHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	entity, entityID, err := entryFromPath(r)
+	if err != nil {
+		return err
+	}
+
+	provenance, err := h.importService.Revert(r.Context(), resume.ID, entity, entityID)
+	if err != nil {
+		return provenanceError(err, "Failed to revert entry")
+	}
+
+	recordImportEvent(r, h.eventService, provenance, domain.EventOpUpdate)
+
+	RespondWithJSON(w, http.StatusOK, provenance)
+	return nil
+}
+
+// ResyncEntryHandler replaces an imported entry with the value its source
+// has now
+func (h *EntryImportHandler) ResyncEntryHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	entity, entityID, err := entryFromPath(r)
+	if err != nil {
+		return err
+	}
+
+	var req ResyncEntryRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	provenance, err := h.importService.Resync(r.Context(), resume.ID, entity, entityID, req.Value)
+	if err != nil {
+		return provenanceError(err, "Failed to re-sync entry")
+	}
+
+	recordImportEvent(r, h.eventService, provenance, domain.EventOpUpdate)
+
+	RespondWithJSON(w, http.StatusOK, provenance)
+	return nil
+}
+
+// recordImportEvent appends a change to an imported entry to the resume's
+// event log. The change itself has already been stored, so a failure is
+// logged rather than returned.
+func recordImportEvent(r *http.Request, eventService *service.ResumeEventService, provenance *domain.Provenance, op string) {
+	actorID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", provenance.ResumeID.String()).Msg("Failed to resolve actor for resume event")
+		return
+	}
+
+	if _, err := eventService.Record(r.Context(), provenance.ResumeID, actorID, provenance.Entity, provenance.EntityID, op, provenance.Original); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", provenance.ResumeID.String()).Str("entity", provenance.Entity).Str("op", op).Msg("Failed to record resume event")
+	}
+}
+
+// entryFromPath reads the entity and entity ID of an imported entry from the path
+func entryFromPath(r *http.Request) (string, uuid.UUID, error) {
+	entityID, err := uuid.Parse(r.PathValue("entityId"))
+	if err != nil {
+		return "", uuid.Nil, apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid entity ID")
+	}
+	return r.PathValue("entity"), entityID, nil
+}
+
+// provenanceError maps an error of reverting or re-syncing an imported entry
+func provenanceError(err error, message string) error {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+	case errors.Is(err, service.ErrProvenanceNotFound):
+		return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Imported entry not found")
+	default:
+		return apperror.Internal(err, message)
+	}
+}
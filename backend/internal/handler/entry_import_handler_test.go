@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// noProvenanceRepository has no imported entries
+type noProvenanceRepository struct {
+	domain.ProvenanceRepository
+}
+
+func (noProvenanceRepository) GetProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) (*domain.Provenance, error) {
+	return nil, repository.ErrNotFound
+}
+
+func TestEntryImportHandlerErrors(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New()}
+	h := NewEntryImportHandler(service.NewEntryImportService(nil, noProvenanceRepository{}), nil)
+	serve := func(fn HandlerFunc, method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		mux := http.NewServeMux()
+		mux.Handle(method+" /api/v1/resumes/{id}/provenance/{entity}/{entityId}/revert", fn)
+		mux.Handle(method+" /api/v1/resumes/{id}/imports", fn)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	prefix := "/api/v1/resumes/" + resume.ID.String()
+
+	assert.Equal(t, http.StatusBadRequest, serve(h.ImportEntriesHandler, http.MethodPost, prefix+"/imports", `{"source":"myspace","entries":[]}`))
+	assert.Equal(t, http.StatusBadRequest, serve(h.ImportEntriesHandler, http.MethodPost, prefix+"/imports", `{"source":"github","entries":[{"entity":"skill","value":{"name":""}}]}`))
+	assert.Equal(t, http.StatusBadRequest, serve(h.RevertEntryHandler, http.MethodPost, prefix+"/provenance/skill/not-a-uuid/revert", ""))
+	assert.Equal(t, http.StatusNotFound, serve(h.RevertEntryHandler, http.MethodPost, prefix+"/provenance/skill/"+uuid.NewString()+"/revert", ""))
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/rs/zerolog/log"
+)
+
+// EventsPath is where clients stream the status of their background jobs
+const EventsPath = "/api/v1/events"
+
+// eventsKeepAlive is the time between comments sent to keep idle streams open
+// through proxies
+const eventsKeepAlive = 15 * time.Second
+
+// EventsHandler streams job events to their users as server-sent events
+type EventsHandler struct {
+	queue     *jobs.Queue
+	keepAlive time.Duration
+}
+
+// NewEventsHandler creates a new events handler
+func NewEventsHandler(queue *jobs.Queue) *EventsHandler {
+	return &EventsHandler{
+		queue:     queue,
+		keepAlive: eventsKeepAlive,
+	}
+}
+
+// StreamEventsHandler streams the status of the user's background jobs, such
+// as data exports, until the client disconnects or the access token expires.
+// Each event is sent as a "job" event whose data is the JSON job event.
+func (h *EventsHandler) StreamEventsHandler(w http.ResponseWriter, r *http.Request) error {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to clear write deadline for event stream")
+	}
+
+	sub, err := h.queue.SubscribeEvents(r.Context(), userID)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to subscribe to job events")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to subscribe to events")
+	}
+	defer sub.Close()
+
+	// Stop when the access token expires, so a revoked user can't keep listening
+	var expired <-chan time.Time
+	if claims.ExpiresAt != nil {
+		timer := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// Tell the client the stream is open before the first event
+	fmt.Fprint(w, ": connected\n\n")
+	if err := rc.Flush(); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to flush event stream")
+		return nil
+	}
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-expired:
+			fmt.Fprint(w, "event: expired\ndata: {}\n\n")
+			rc.Flush()
+			return nil
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Ctx(r.Context()).Error().Err(err).Msg("Failed to encode job event")
+				continue
+			}
+			fmt.Fprintf(w, "event: job\ndata: %s\n\n", data)
+		}
+
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEventsHandler(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: client})
+
+	userID := uuid.New()
+	h := NewEventsHandler(queue)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		HandlerFunc(h.StreamEventsHandler).ServeHTTP(w, withClaims(r, userID))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The stream opens with a comment, after which the subscription is live
+	lines := bufio.NewReader(resp.Body)
+	line, err := lines.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, ": connected\n", line)
+
+	require.NoError(t, queue.PublishEvent(ctx, uuid.New(), jobs.Event{JobID: "other", JobType: "test.job", Status: jobs.EventStatusQueued}))
+	require.NoError(t, queue.PublishEvent(ctx, userID, jobs.Event{JobID: "mine", JobType: "test.job", Status: jobs.EventStatusSucceeded}))
+
+	var received []string
+	for len(received) < 2 {
+		line, err := lines.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, "event:") || strings.HasPrefix(line, "data:") {
+			received = append(received, strings.TrimSpace(line))
+		}
+	}
+	assert.Equal(t, "event: job", received[0])
+	assert.Contains(t, received[1], `"job_id":"mine"`)
+	assert.Contains(t, received[1], `"status":"succeeded"`)
+}
+
+func TestStreamEventsHandlerUnauthorized(t *testing.T) {
+	h := NewEventsHandler(nil)
+	rr := httptest.NewRecorder()
+	HandlerFunc(h.StreamEventsHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, EventsPath, nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
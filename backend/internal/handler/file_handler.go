@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// FileHandler serves stored files at signed URLs, for storages without URLs
+// of their own
+type FileHandler struct {
+	store  storage.Storage
+	signer *storage.URLSigner
+}
+
+// NewFileHandler creates a new file handler
+func NewFileHandler(store storage.Storage, signer *storage.URLSigner) *FileHandler {
+	return &FileHandler{
+		store:  store,
+		signer: signer,
+	}
+}
+
+// GetFileHandler serves the file of a signed URL as a download
+func (h *FileHandler) GetFileHandler(w http.ResponseWriter, r *http.Request) error {
+	key := r.PathValue("key")
+	query := r.URL.Query()
+	if err := h.signer.Verify(key, query.Get("expires"), query.Get("signature")); err != nil {
+		return apperror.New(http.StatusForbidden, "INVALID_SIGNATURE", "Download link invalid or expired")
+	}
+
+	object, err := h.store.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "File not found")
+		}
+		return apperror.Internal(err, "Failed to get file")
+	}
+	defer object.Body.Close()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	if object.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	}
+	// Served as attachments, so stored files never run as pages of the API
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(key)}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, object.Body); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("key", key).Msg("Failed to write file")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFileHandler(t *testing.T) {
+	ctx := context.Background()
+	signer := storage.NewURLSigner("/api/v1/files", []byte("secret"))
+	store := storage.NewMemory()
+	store.SetURLSigner(signer)
+	require.NoError(t, store.Put(ctx, "exports/resume.pdf", strings.NewReader("%PDF"), "application/pdf"))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/files/{key...}", HandlerFunc(NewFileHandler(store, signer).GetFileHandler))
+	get := func(target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		return rr
+	}
+
+	signed, err := store.SignedURL(ctx, "exports/resume.pdf", time.Minute)
+	require.NoError(t, err)
+	rr := get(signed)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "%PDF", rr.Body.String())
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=resume.pdf`, rr.Header().Get("Content-Disposition"))
+
+	// Signatures don't carry over to other files
+	rr = get(strings.Replace(signed, "resume.pdf", "other.pdf", 1))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = get("/api/v1/files/exports/resume.pdf")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	signed, err = store.SignedURL(ctx, "exports/missing.pdf", time.Minute)
+	require.NoError(t, err)
+	rr = get(signed)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/lordaris/resume_generator/pkg/health"
+	"github.com/rs/zerolog/log"
+)
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// LivenessResponse reports that the process is serving requests
+type LivenessResponse struct {
+	Status string `json:"status"`
+	Time   string `json:"time"`
+}
+
+// LivenessHandler reports whether the process is alive. It never touches
+// dependencies, so an outage elsewhere doesn't get the server restarted.
+func (h *HealthHandler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	RespondWithJSON(w, http.StatusOK, LivenessResponse{
+		Status: health.StatusUp,
+		Time:   time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// ReadinessHandler reports whether the server can handle traffic by checking
+// every dependency, responding 503 with per-dependency status when a required
+// one is down. With only optional dependencies down the server stays ready.
+func (h *HealthHandler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Run(r.Context())
+
+	status := http.StatusOK
+	switch report.Status {
+	case health.StatusUp:
+	case health.StatusDegraded:
+		log.Ctx(r.Context()).Warn().Interface("components", report.Components).Msg("Readiness check degraded")
+	default:
+		status = http.StatusServiceUnavailable
+		log.Ctx(r.Context()).Warn().Interface("components", report.Components).Msg("Readiness check failed")
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	RespondWithJSON(w, status, report)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lordaris/resume_generator/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	checker := health.NewChecker(health.Config{})
+	checker.Register("database", func(ctx context.Context) error { return nil })
+	h := NewHealthHandler(checker)
+
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	checker.Register("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	rec = httptest.NewRecorder()
+	h.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, health.StatusUp, report.Components["database"].Status)
+	assert.Equal(t, "connection refused", report.Components["redis"].Error)
+
+	// Liveness ignores dependencies
+	rec = httptest.NewRecorder()
+	h.LivenessHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/rs/zerolog/log"
+)
+
+// JobHandler reports on the background job queue (admin only)
+type JobHandler struct {
+	queue *jobs.Queue
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(queue *jobs.Queue) *JobHandler {
+	return &JobHandler{
+		queue: queue,
+	}
+}
+
+// GetQueueStatsHandler returns the depth of each priority and how long due
+// jobs have been waiting
+func (h *JobHandler) GetQueueStatsHandler(w http.ResponseWriter, r *http.Request) error {
+	stats, err := h.queue.Stats(r.Context())
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to get job queue stats")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to get job queue stats")
+	}
+
+	RespondWithJSON(w, http.StatusOK, stats)
+	return nil
+}
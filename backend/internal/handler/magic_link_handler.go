@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// magicLinkDeviceCookie binds a sign-in link to the browser that asked for
+// it, so the link alone doesn't sign anyone in
+const magicLinkDeviceCookie = "magic_link_device"
+
+// magicLinkPath is the path of the magic link endpoints, the only ones the
+// device cookie is sent to
+const magicLinkPath = "/api/v1/login/magic-link"
+
+// MagicLinkHandler handles signing in with links sent by email
+type MagicLinkHandler struct {
+	magicLinkService *service.MagicLinkService
+	validator        *validator.Validate
+	// loginLimiter guards both endpoints, which send email or sign in
+	loginLimiter security.Limiter
+	// secureCookies marks the device cookie Secure
+	secureCookies bool
+}
+
+// NewMagicLinkHandler creates a new magic link handler
+func NewMagicLinkHandler(magicLinkService *service.MagicLinkService, redisClient redis.UniversalClient) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		magicLinkService: magicLinkService,
+		validator:        validator.New(),
+		loginLimiter:     NewUserRateLimiter(security.StrictRateLimit, redisClient),
+		secureCookies:    true,
+	}
+}
+
+// SetLoginLimiter replaces the limiter guarding the endpoints, such as one
+// that fails closed
+func (h *MagicLinkHandler) SetLoginLimiter(limiter security.Limiter) {
+	h.loginLimiter = limiter
+}
+
+// SetSecureCookies sets whether the device cookie is only sent over HTTPS.
+// Turn it off only for local development over plain HTTP.
+func (h *MagicLinkHandler) SetSecureCookies(secure bool) {
+	h.secureCookies = secure
+}
+
+// MagicLinkRequest asks for a sign-in link
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// VerifyMagicLinkRequest signs in with the token of a sign-in link
+type VerifyMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// RequestMagicLinkHandler emails a sign-in link. The response is the same
+// whether the address has an account or not, to prevent user enumeration.
+func (h *MagicLinkHandler) RequestMagicLinkHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := applyRateLimit(w, r, h.loginLimiter); err != nil {
+		return err
+	}
+
+	var req MagicLinkRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		return validationError(validationErrors)
+	}
+
+	// Links asked for again from the same browser share its secret
+	var device string
+	if cookie, err := r.Cookie(magicLinkDeviceCookie); err == nil {
+		device = cookie.Value
+	}
+	device, err := h.magicLinkService.Request(r.Context(), req.Email, device)
+	if err != nil {
+		return apperror.Internal(err, "Failed to send sign-in link")
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicLinkDeviceCookie,
+		Value:    device,
+		Path:     magicLinkPath,
+		MaxAge:   int(h.magicLinkService.LinkTTL().Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+	RespondWithJSON(w, http.StatusAccepted, MessageResponse{
+		Message: "If the email has an account, a sign-in link has been sent to it",
+	})
+	return nil
+}
+
+// VerifyMagicLinkHandler exchanges the token of a sign-in link for a token
+// pair, from the browser that asked for the link
+func (h *MagicLinkHandler) VerifyMagicLinkHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := applyRateLimit(w, r, h.loginLimiter); err != nil {
+		return err
+	}
+
+	var req VerifyMagicLinkRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		return validationError(validationErrors)
+	}
+
+	var device string
+	if cookie, err := r.Cookie(magicLinkDeviceCookie); err == nil {
+		device = cookie.Value
+	}
+
+	tokens, err := h.magicLinkService.Exchange(r.Context(), req.Token, device, r.UserAgent(), getClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMagicLinkInvalid):
+			return apperror.New(http.StatusUnauthorized, "INVALID_TOKEN", "Sign-in link invalid, expired or already used")
+		case errors.Is(err, service.ErrMagicLinkDevice):
+			return apperror.New(http.StatusForbidden, "WRONG_DEVICE", "Open the sign-in link on the device you requested it from")
+		}
+		// The link is used up either way
+		h.clearDeviceCookie(w)
+		if handled, err := respondSecondFactor(w, err); handled {
+			return err
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to login with sign-in link")
+		return apperror.New(http.StatusInternalServerError, "LOGIN_FAILED", "Failed to login user")
+	}
+
+	h.clearDeviceCookie(w)
+	RespondWithJSON(w, http.StatusOK, tokens)
+	return nil
+}
+
+// clearDeviceCookie removes the device cookie once its link is used
+func (h *MagicLinkHandler) clearDeviceCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicLinkDeviceCookie,
+		Path:     magicLinkPath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
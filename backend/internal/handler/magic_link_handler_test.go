@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMagicLinkHandlers(t *testing.T) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	mr := miniredis.RunT(t)
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetUserByEmail", "nobody@example.com").Return(nil, repository.ErrNotFound)
+	authService := service.NewAuthService(userRepo, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), service.AuthServiceConfig{})
+	magicLinkService := service.NewMagicLinkService(userRepo, authService, nil, store, service.MagicLinkServiceConfig{LinkURL: "https://app.example.com/login/magic"})
+	h := NewMagicLinkHandler(magicLinkService, redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	// Unknown addresses are answered like known ones, binding the browser
+	rr := httptest.NewRecorder()
+	HandlerFunc(h.RequestMagicLinkHandler).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/login/magic-link", bytes.NewBufferString(`{"email":"nobody@example.com"}`)))
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, magicLinkDeviceCookie, cookies[0].Name)
+	assert.Equal(t, magicLinkPath, cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+	assert.NotEmpty(t, cookies[0].Value)
+
+	rr = httptest.NewRecorder()
+	HandlerFunc(h.RequestMagicLinkHandler).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/login/magic-link", bytes.NewBufferString(`{"email":"not an email"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req := httptest.NewRequest("POST", "/api/v1/login/magic-link/verify", bytes.NewBufferString(`{"token":"not-a-token"}`))
+	req.AddCookie(cookies[0])
+	rr = httptest.NewRecorder()
+	HandlerFunc(h.VerifyMagicLinkHandler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"INVALID_TOKEN"`)
+
+	rr = httptest.NewRecorder()
+	HandlerFunc(h.VerifyMagicLinkHandler).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/login/magic-link/verify", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
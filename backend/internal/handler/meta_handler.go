@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/buildinfo"
+)
+
+// GetVersionHandler handles fetching the running build's version information
+func GetVersionHandler(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, buildinfo.Get())
+}
+
+// JWKSHandler publishes the public keys access tokens can be verified with.
+// The set is empty while tokens are signed with shared secrets.
+func JWKSHandler(jwt *auth.JWT) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Verifiers refetch after a rotation, so a short cache is enough
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		RespondWithJSON(w, http.StatusOK, jwt.JWKS())
+	}
+}
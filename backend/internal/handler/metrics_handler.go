@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+)
+
+// MetricsContentType is the Prometheus text exposition format
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsHandler serves metrics in the Prometheus text format to scrapers
+// that present the metrics token
+type MetricsHandler struct {
+	token   string
+	dbStats func() sql.DBStats
+}
+
+// NewMetricsHandler creates a new metrics handler reporting the database
+// pool statistics dbStats returns
+func NewMetricsHandler(token string, dbStats func() sql.DBStats) *MetricsHandler {
+	return &MetricsHandler{
+		token:   token,
+		dbStats: dbStats,
+	}
+}
+
+// GetMetricsHandler writes the metrics. The database pool metrics use the
+// names of the Prometheus Go client's DB stats collector, so existing
+// dashboards work with them. Service accounts let through by
+// ServiceAccountOr need no metrics token; without one configured, only they
+// are served.
+func (h *MetricsHandler) GetMetricsHandler(w http.ResponseWriter, r *http.Request) error {
+	if _, ok := GetServiceAccountFromContext(r.Context()); !ok {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || h.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Invalid metrics token")
+		}
+	}
+
+	var b bytes.Buffer
+	stats := h.dbStats()
+	writeMetric(&b, "go_sql_max_open_connections", "gauge", "Maximum number of open connections to the database.", float64(stats.MaxOpenConnections))
+	writeMetric(&b, "go_sql_open_connections", "gauge", "The number of established connections both in use and idle.", float64(stats.OpenConnections))
+	writeMetric(&b, "go_sql_in_use_connections", "gauge", "The number of connections currently in use.", float64(stats.InUse))
+	writeMetric(&b, "go_sql_idle_connections", "gauge", "The number of idle connections.", float64(stats.Idle))
+	writeMetric(&b, "go_sql_wait_count_total", "counter", "The total number of connections waited for.", float64(stats.WaitCount))
+	writeMetric(&b, "go_sql_wait_duration_seconds_total", "counter", "The total time blocked waiting for a new connection.", stats.WaitDuration.Seconds())
+	writeMetric(&b, "go_sql_max_idle_closed_total", "counter", "The total number of connections closed due to SetMaxIdleConns.", float64(stats.MaxIdleClosed))
+	writeMetric(&b, "go_sql_max_idle_time_closed_total", "counter", "The total number of connections closed due to SetConnMaxIdleTime.", float64(stats.MaxIdleTimeClosed))
+	writeMetric(&b, "go_sql_max_lifetime_closed_total", "counter", "The total number of connections closed due to SetConnMaxLifetime.", float64(stats.MaxLifetimeClosed))
+
+	w.Header().Set("Content-Type", MetricsContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b.Bytes())
+	return nil
+}
+
+// writeMetric writes one unlabelled sample with its help and type lines
+func writeMetric(b *bytes.Buffer, name, kind, help string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", name, help, name, kind, name, value)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	h := NewMetricsHandler("scrape-token", func() sql.DBStats {
+		return sql.DBStats{MaxOpenConnections: 25, OpenConnections: 7, InUse: 5, Idle: 2, WaitCount: 3, WaitDuration: 1500 * time.Millisecond}
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	HandlerFunc(h.GetMetricsHandler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req.Header.Set("Authorization", "Bearer scrape-token")
+	HandlerFunc(h.GetMetricsHandler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, MetricsContentType, rr.Header().Get("Content-Type"))
+	body := rr.Body.String()
+	assert.Contains(t, body, "# TYPE go_sql_max_open_connections gauge\ngo_sql_max_open_connections 25\n")
+	assert.Contains(t, body, "go_sql_in_use_connections 5\n")
+	assert.Contains(t, body, "go_sql_wait_count_total 3\n")
+	assert.Contains(t, body, "go_sql_wait_duration_seconds_total 1.5\n")
+}
@@ -4,13 +4,21 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
 	"github.com/lordaris/resume_generator/internal/service"
 	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // contextKey is a type for context keys
@@ -21,63 +29,285 @@ const (
 	userContextKey contextKey = iota
 	// claimsContextKey is the key for the JWT claims in the context
 	claimsContextKey
+	// resumeContextKey is the key for the resume resolved by RequireResumeOwnership
+	resumeContextKey
+	// requestIDContextKey is the key for the request ID
+	requestIDContextKey
+	// serviceAccountContextKey is the key for the service account making the request
+	serviceAccountContextKey
 )
 
-// AuthMiddleware extracts and validates JWT tokens from requests
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = apperror.RequestIDHeader
+
+// maxRequestIDLength bounds incoming request IDs so clients can't bloat logs
+const maxRequestIDLength = 128
+
+// AuthMiddleware extracts and validates JWT tokens and API keys from requests
 type AuthMiddleware struct {
-	authService *service.AuthService
+	authService           *service.AuthService
+	apiKeyService         *service.APIKeyService
+	serviceAccountService *service.ServiceAccountService
+	permissionService     *service.PermissionService
+	// readLimiter and writeLimiter limit authenticated requests per user
+	readLimiter  security.Limiter
+	writeLimiter security.Limiter
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(authService *service.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService *service.AuthService, apiKeyService *service.APIKeyService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:   authService,
+		apiKeyService: apiKeyService,
 	}
 }
 
+// SetRateLimiters limits authenticated requests per user, with readLimiter
+// applying to GET and HEAD requests and writeLimiter to the rest
+func (m *AuthMiddleware) SetRateLimiters(readLimiter, writeLimiter security.Limiter) {
+	m.readLimiter = readLimiter
+	m.writeLimiter = writeLimiter
+}
+
+// SetServiceAccountService lets the routes wrapped in ServiceAccountOr accept
+// service account keys
+func (m *AuthMiddleware) SetServiceAccountService(serviceAccountService *service.ServiceAccountService) {
+	m.serviceAccountService = serviceAccountService
+}
+
+// SetPermissionService checks permissions against the user roles stored in
+// the database rather than the roles the instance starts with
+func (m *AuthMiddleware) SetPermissionService(permissionService *service.PermissionService) {
+	m.permissionService = permissionService
+}
+
 // AuthRequired middleware checks for a valid JWT token and injects user info into the context
 func (m *AuthMiddleware) AuthRequired(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Extract token from Authorization header
 		token, err := extractTokenFromHeader(r)
 		if err != nil {
-			RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "INVALID_TOKEN")
+			WriteError(w, r, apperror.New(http.StatusUnauthorized, "INVALID_TOKEN", "Unauthorized"))
+			return
+		}
+
+		// API keys only work on the routes that accept their scopes
+		if service.IsAPIKey(token) || service.IsServiceAccountKey(token) {
+			WriteError(w, r, apperror.New(http.StatusForbidden, "INSUFFICIENT_SCOPE", "API keys can't be used for this endpoint"))
 			return
 		}
 
 		// Validate token
-		claims, err := m.authService.ValidateAccessToken(token)
+		claims, err := m.authService.ValidateAccessToken(r.Context(), token)
 		if err != nil {
-			if errors.Is(err, service.ErrExpiredToken) {
-				RespondWithError(w, http.StatusUnauthorized, "Token expired", "TOKEN_EXPIRED")
-				return
+			switch {
+			case errors.Is(err, service.ErrExpiredToken):
+				WriteError(w, r, apperror.New(http.StatusUnauthorized, "TOKEN_EXPIRED", "Token expired"))
+			case errors.Is(err, service.ErrTokenRevoked):
+				WriteError(w, r, apperror.New(http.StatusUnauthorized, "TOKEN_REVOKED", "Token revoked"))
+			case errors.Is(err, service.ErrInvalidToken):
+				WriteError(w, r, apperror.New(http.StatusUnauthorized, "INVALID_TOKEN", "Invalid token"))
+			default:
+				log.Ctx(r.Context()).Error().Err(err).Msg("Failed to validate access token")
+				WriteError(w, r, apperror.New(http.StatusServiceUnavailable, "AUTH_UNAVAILABLE", "Authentication unavailable"))
 			}
-			RespondWithError(w, http.StatusUnauthorized, "Invalid token", "INVALID_TOKEN")
 			return
 		}
 
 		// Add claims to context
-		ctx := context.WithValue(r.Context(), claimsContextKey, claims)
+		r = r.WithContext(context.WithValue(r.Context(), claimsContextKey, claims))
+		if err := m.applyRateLimit(w, r); err != nil {
+			WriteError(w, r, err)
+			return
+		}
 
 		// Continue with the next handler
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r)
 	})
 }
 
-// RequireRole middleware checks if the user has the required role
-func (m *AuthMiddleware) RequireRole(role string) func(http.Handler) http.Handler {
+// AuthRequiredWithScope works like AuthRequired but also accepts API keys
+// granted the scope, acting as the key's user
+func (m *AuthMiddleware) AuthRequiredWithScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withToken := m.AuthRequired(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := extractTokenFromHeader(r)
+			if err != nil || !service.IsAPIKey(token) {
+				withToken.ServeHTTP(w, r)
+				return
+			}
+
+			key, user, err := m.apiKeyService.Authenticate(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidAPIKey) {
+					WriteError(w, r, apperror.New(http.StatusUnauthorized, "INVALID_TOKEN", "Invalid API key"))
+					return
+				}
+				log.Ctx(r.Context()).Error().Err(err).Msg("Failed to authenticate API key")
+				WriteError(w, r, apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to authenticate"))
+				return
+			}
+			if !key.HasScope(scope) {
+				WriteError(w, r, apperror.New(http.StatusForbidden, "INSUFFICIENT_SCOPE", "API key lacks the "+scope+" scope"))
+				return
+			}
+
+			// Handlers read the caller from the claims whichever way it signed in
+			claims := &auth.JWTClaims{
+				UserID:    user.ID.String(),
+				Email:     user.Email,
+				Role:      user.Role,
+				TokenType: auth.TokenTypeAPIKey,
+			}
+			r = r.WithContext(context.WithValue(r.Context(), claimsContextKey, claims))
+			if err := m.applyRateLimit(w, r); err != nil {
+				WriteError(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PermissionRequired returns middleware only letting users signed in with an
+// access token whose role grants the permission through
+func (m *AuthMiddleware) PermissionRequired(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return m.AuthRequired(m.RequirePermission(permission)(next))
+	}
+}
+
+// ServiceAccountOr accepts requests made with the key of a service account
+// granted the scope, recording each in the account's audit trail; other
+// requests go through fallback, such as PermissionRequired
+func (m *AuthMiddleware) ServiceAccountOr(scope string, fallback func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		otherwise := fallback(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := extractTokenFromHeader(r)
+			if err != nil || !service.IsServiceAccountKey(token) || m.serviceAccountService == nil {
+				otherwise.ServeHTTP(w, r)
+				return
+			}
+
+			account, err := m.serviceAccountService.Authenticate(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidServiceAccountKey) {
+					WriteError(w, r, apperror.New(http.StatusUnauthorized, "INVALID_TOKEN", "Invalid service account key"))
+					return
+				}
+				log.Ctx(r.Context()).Error().Err(err).Msg("Failed to authenticate service account")
+				WriteError(w, r, apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to authenticate"))
+				return
+			}
+			if !account.HasScope(scope) {
+				WriteError(w, r, apperror.New(http.StatusForbidden, "INSUFFICIENT_SCOPE", "Service account lacks the "+scope+" scope"))
+				return
+			}
+
+			// Service accounts act as no user, so handlers that need one refuse them
+			claims := &auth.JWTClaims{
+				Role:             domain.RoleServiceAccount,
+				TokenType:        auth.TokenTypeServiceAccount,
+				RegisteredClaims: jwt.RegisteredClaims{Subject: account.ID.String()},
+			}
+			ctx := context.WithValue(r.Context(), claimsContextKey, claims)
+			ctx = context.WithValue(ctx, serviceAccountContextKey, account)
+			r = r.WithContext(ctx)
+			if err := m.applyRateLimit(w, r); err != nil {
+				WriteError(w, r, err)
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			// The request has been served, so a failure only loses the entry
+			if err := m.serviceAccountService.RecordRequest(r.Context(), account.ID, r.Method, r.URL.Path, rw.statusCode); err != nil {
+				log.Ctx(r.Context()).Error().Err(err).Str("service_account_id", account.ID.String()).Msg("Failed to audit service account request")
+			}
+		})
+	}
+}
+
+// applyRateLimit limits an authenticated request by its method
+func (m *AuthMiddleware) applyRateLimit(w http.ResponseWriter, r *http.Request) error {
+	limiter := m.writeLimiter
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		limiter = m.readLimiter
+	}
+	if limiter == nil {
+		return nil
+	}
+	return applyRateLimit(w, r, limiter)
+}
+
+// applyRateLimit counts a request against a limiter, returning a 429 Too Many
+// Requests error once the limit is reached. The limit is reported in headers.
+func applyRateLimit(w http.ResponseWriter, r *http.Request, limiter security.Limiter) error {
+	policy := limiter.Policy()
+	count, err := limiter.CheckRateLimit(r.Context(), r)
+	if err != nil {
+		if errors.Is(err, security.ErrRateLimitExceeded) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(policy.Interval.Seconds())))
+			return apperror.New(http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Rate limit exceeded")
+		}
+		if errors.Is(err, security.ErrRateLimitUnavailable) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(policy.Interval.Seconds())))
+			return apperror.Wrap(err, http.StatusServiceUnavailable, "RATE_LIMIT_UNAVAILABLE", "Service temporarily unavailable")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Rate limiting error")
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(policy.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(policy.Limit-count, 0)))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(policy.Interval.Seconds())))
+	return nil
+}
+
+// NewUserRateLimiter creates a limiter enforcing a policy per user on
+// authenticated routes
+func NewUserRateLimiter(policy security.RateLimitPolicy, redisClient redis.UniversalClient) security.Limiter {
+	config := policy.Config(redisClient)
+	config.Identify = rateLimitIdentity
+	return security.NewLimiter(config)
+}
+
+// rateLimitIdentity counts authenticated requests against the user and
+// others against the client IP
+func rateLimitIdentity(r *http.Request) string {
+	if claims, ok := r.Context().Value(claimsContextKey).(*auth.JWTClaims); ok {
+		if claims.UserID != "" {
+			return "user:" + claims.UserID
+		}
+		if claims.TokenType == auth.TokenTypeServiceAccount {
+			return "service:" + claims.Subject
+		}
+	}
+	return security.IPIdentity(r)
+}
+
+// RequirePermission middleware checks if the user's role grants the permission
+func (m *AuthMiddleware) RequirePermission(permission string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get claims from context
 			claims, ok := r.Context().Value(claimsContextKey).(*auth.JWTClaims)
 			if !ok {
-				RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+				WriteError(w, r, apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized"))
 				return
 			}
 
-			// Check role
-			if claims.Role != role {
-				RespondWithError(w, http.StatusForbidden, "Forbidden", "FORBIDDEN")
+			// Check permission
+			allowed, err := hasPermission(r.Context(), m.permissionService, claims.Role, permission)
+			if err != nil {
+				WriteError(w, r, apperror.Internal(err, "Failed to check permissions"))
+				return
+			}
+			if !allowed {
+				WriteError(w, r, apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Forbidden"))
 				return
 			}
 
@@ -87,50 +317,73 @@ func (m *AuthMiddleware) RequireRole(role string) func(http.Handler) http.Handle
 	}
 }
 
-type SessionLogger struct{}
-
-// NewSessionLogger creates a new session logger
-func NewSessionLogger() *SessionLogger {
-	return &SessionLogger{}
+// hasPermission reports whether a role grants a permission, going by the
+// roles the instance starts with when permissions is nil
+func hasPermission(ctx context.Context, permissions *service.PermissionService, role, permission string) (bool, error) {
+	if permissions == nil {
+		return domain.BuiltinRoleHasPermission(role, permission), nil
+	}
+	return permissions.HasPermission(ctx, role, permission)
 }
 
-// LogActivity middleware logs user activity
-func (l *SessionLogger) LogActivity(next http.Handler) http.Handler {
+// RequestID middleware assigns each request an ID, reusing a well-formed
+// incoming X-Request-ID so calls can be traced across services. The ID is
+// returned in the response header and attached to the context's logger, so
+// every entry logged with log.Ctx carries it.
+func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		startTime := time.Now()
-
-		// Get claims from context if available
-		var userID, email, role string
-		if claims, ok := r.Context().Value(claimsContextKey).(*auth.JWTClaims); ok {
-			userID = claims.UserID
-			email = claims.Email
-			role = claims.Role
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
 		}
 
-		// Create a custom response writer to capture the status code
-		rw := &responseWriter{w, http.StatusOK}
-
-		// Process the request
-		next.ServeHTTP(rw, r)
-
-		// Log the activity
-		duration := time.Since(startTime)
-		log.Info().
-			Str("method", r.Method).
-			Str("path", r.URL.Path).
-			Int("status", rw.statusCode).
-			Str("user_id", userID).
-			Str("email", email).
-			Str("role", role).
-			Str("ip", getClientIP(r)).
-			Str("user_agent", r.UserAgent()).
-			Dur("duration", duration).
-			Msg("API request")
+		w.Header().Set(RequestIDHeader, requestID)
+
+		logger := log.Ctx(r.Context()).With().Str("request_id", requestID).Logger()
+		// Tie log entries to the request's trace when it is recorded
+		if span := trace.SpanContextFromContext(r.Context()); span.IsSampled() {
+			logger = logger.With().Str("trace_id", span.TraceID().String()).Logger()
+		}
+		ctx := context.WithValue(logger.WithContext(r.Context()), requestIDContextKey, requestID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// validRequestID reports whether an incoming request ID is safe to reuse
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)) {
+			return false
+		}
+	}
+	return true
+}
+
 // Helper functions
 
+// RequestTimeout middleware bounds the request context so that repository calls
+// are cancelled once a response could no longer be delivered in time. Requests
+// to the streaming paths are left unbounded; they end when the client leaves.
+func RequestTimeout(timeout time.Duration, streamingPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(streamingPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // extractTokenFromHeader extracts the token from the Authorization header
 func extractTokenFromHeader(r *http.Request) (string, error) {
 	// Get Authorization header
@@ -148,29 +401,10 @@ func extractTokenFromHeader(r *http.Request) (string, error) {
 	return parts[1], nil
 }
 
-// getClientIP gets the client IP address
+// getClientIP gets the client IP address, believing forwarding headers only
+// from the trusted proxies
 func getClientIP(r *http.Request) string {
-	// Check for X-Forwarded-For header first
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// X-Forwarded-For can contain multiple IPs, we want the first one
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	// Check for X-Real-IP header
-	if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
-		return xrip
-	}
-
-	// Get IP from remote address
-	ip := r.RemoteAddr
-	// Remove port if present
-	if index := strings.IndexByte(ip, ':'); index != -1 {
-		ip = ip[:index]
-	}
-	return ip
+	return security.ClientIP(r)
 }
 
 // GetUserIDFromContext gets the user ID from the context
@@ -198,10 +432,35 @@ func GetClaimsFromContext(ctx context.Context) (*auth.JWTClaims, error) {
 	return claims, nil
 }
 
-// responseWriter is a wrapper for http.ResponseWriter that captures the status code
+// GetRequestIDFromContext gets the request ID assigned by RequestID, or "" outside a request
+func GetRequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// GetResumeFromContext gets the resume resolved by RequireResumeOwnership from the context
+func GetResumeFromContext(ctx context.Context) (*domain.Resume, error) {
+	resume, ok := ctx.Value(resumeContextKey).(*domain.Resume)
+	if !ok {
+		return nil, errors.New("no resume in context")
+	}
+
+	return resume, nil
+}
+
+// GetServiceAccountFromContext gets the service account authenticated by
+// ServiceAccountOr from the context
+func GetServiceAccountFromContext(ctx context.Context) (*domain.ServiceAccount, bool) {
+	account, ok := ctx.Value(serviceAccountContextKey).(*domain.ServiceAccount)
+	return account, ok
+}
+
+// responseWriter is a wrapper for http.ResponseWriter that captures the status
+// code and counts the bytes written
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	bytes      int64
 }
 
 // WriteHeader captures the status code
@@ -209,3 +468,15 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Write counts the bytes of the response body
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap returns the wrapped writer so http.ResponseController can flush it
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
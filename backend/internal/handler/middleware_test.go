@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	logger := zerolog.New(&logs)
+
+	var contextID string
+	h := RequestID(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		contextID = GetRequestIDFromContext(r.Context())
+		log.Ctx(r.Context()).Info().Msg("handled")
+		return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Not found")
+	}))
+
+	serve := func(incoming string) *httptest.ResponseRecorder {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(logger.WithContext(req.Context()))
+		if incoming != "" {
+			req.Header.Set(RequestIDHeader, incoming)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A well-formed incoming ID is reused everywhere
+	rec := serve("edge-42.abc")
+	assert.Equal(t, "edge-42.abc", rec.Header().Get(RequestIDHeader))
+	assert.Equal(t, "edge-42.abc", contextID)
+	assert.Contains(t, logs.String(), `"request_id":"edge-42.abc"`)
+
+	var body apperror.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "edge-42.abc", body.RequestID)
+
+	// Missing and unsafe IDs are replaced
+	for _, incoming := range []string{"", "bad id\n", strings.Repeat("a", maxRequestIDLength+1)} {
+		rec := serve(incoming)
+		generated := rec.Header().Get(RequestIDHeader)
+		_, err := uuid.Parse(generated)
+		assert.NoError(t, err, "incoming %q", incoming)
+		assert.Equal(t, generated, contextID)
+	}
+}
+
+func TestRequestTimeoutStreamingPaths(t *testing.T) {
+	var hasDeadline bool
+	h := RequestTimeout(time.Second, EventsPath)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/resumes", nil))
+	assert.True(t, hasDeadline)
+
+	// Streams end when the client leaves, not after the timeout
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, EventsPath, nil))
+	assert.False(t, hasDeadline)
+}
+
+func TestAuthRequiredRateLimitsPerUser(t *testing.T) {
+	jwtHandler := auth.NewJWT(auth.JWTConfig{Secret: "test-secret"})
+	m := NewAuthMiddleware(service.NewAuthService(new(MockUserRepository), jwtHandler, service.AuthServiceConfig{}), nil)
+	m.SetRateLimiters(
+		NewUserRateLimiter(security.RateLimitPolicy{Name: "read", Limit: 2, Interval: time.Minute}, nil),
+		NewUserRateLimiter(security.RateLimitPolicy{Name: "write", Limit: 1, Interval: time.Minute}, nil),
+	)
+	h := m.AuthRequired(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	call := func(method, userID string) *httptest.ResponseRecorder {
+		token, err := jwtHandler.GenerateAccessToken(userID, "user@example.com", "user")
+		require.NoError(t, err)
+		req := httptest.NewRequest(method, "/api/v1/resumes", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	alice, bob := uuid.NewString(), uuid.NewString()
+	rr := call(http.MethodGet, alice)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, http.StatusNoContent, call(http.MethodGet, alice).Code)
+	assert.Equal(t, http.StatusTooManyRequests, call(http.MethodGet, alice).Code)
+
+	// Writes are counted apart from reads, and users apart from each other
+	assert.Equal(t, http.StatusNoContent, call(http.MethodPost, alice).Code)
+	assert.Equal(t, http.StatusTooManyRequests, call(http.MethodPost, alice).Code)
+	assert.Equal(t, http.StatusNoContent, call(http.MethodGet, bob).Code)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/rs/zerolog/log"
+)
+
+// oauthStateCookie binds a sign-in to the browser that started it, so a
+// callback URL sent to someone else can't sign them in to the sender's account
+const oauthStateCookie = "oauth_state"
+
+// OAuthHandler handles sign-in with OAuth providers
+type OAuthHandler struct {
+	oauthService *service.OAuthService
+	// secureCookies marks the state cookie Secure
+	secureCookies bool
+}
+
+// NewOAuthHandler creates a new OAuth handler
+func NewOAuthHandler(oauthService *service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService:  oauthService,
+		secureCookies: true,
+	}
+}
+
+// SetSecureCookies sets whether the state cookie is only sent over HTTPS.
+// Turn it off only for local development over plain HTTP.
+func (h *OAuthHandler) SetSecureCookies(secure bool) {
+	h.secureCookies = secure
+}
+
+// OAuthProvidersResponse lists the providers users can sign in with
+type OAuthProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// GetProvidersHandler lists the configured providers
+func (h *OAuthHandler) GetProvidersHandler(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, OAuthProvidersResponse{Providers: h.oauthService.Providers()})
+}
+
+// LoginHandler redirects the user to the provider to sign in
+func (h *OAuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) error {
+	provider := r.PathValue("provider")
+
+	redirectURL, state, err := h.oauthService.Begin(r.Context(), provider)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownProvider) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Unknown provider")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("provider", provider).Msg("Failed to start OAuth sign-in")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to start sign-in")
+	}
+
+	h.setStateCookie(w, provider, state)
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+	return nil
+}
+
+// OAuthLinkResponse is where to send the user to link a provider account
+type OAuthLinkResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+// LinkHandler starts linking a provider account to the current user. The
+// redirect URL is returned rather than followed, as the request carries the
+// user's token; the provider redirects back to the callback, which links the
+// account.
+func (h *OAuthHandler) LinkHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+	provider := r.PathValue("provider")
+
+	redirectURL, state, err := h.oauthService.BeginLink(r.Context(), provider, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownProvider) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Unknown provider")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("provider", provider).Msg("Failed to start OAuth link")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to start linking")
+	}
+
+	h.setStateCookie(w, provider, state)
+	RespondWithJSON(w, http.StatusOK, OAuthLinkResponse{RedirectURL: redirectURL})
+	return nil
+}
+
+// setStateCookie binds the state of a redirect to the browser
+func (h *OAuthHandler) setStateCookie(w http.ResponseWriter, provider, state string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     OAuthCallbackPath(provider),
+		MaxAge:   int(h.oauthService.StateTTL().Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		// Lax cookies are sent on the provider's top-level redirect back
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// CallbackHandler completes a sign-in when the provider redirects the user
+// back, returning a token pair like the password login. A link started with
+// LinkHandler returns the linked identity instead.
+func (h *OAuthHandler) CallbackHandler(w http.ResponseWriter, r *http.Request) error {
+	provider := r.PathValue("provider")
+	query := r.URL.Query()
+
+	// The sign-in is over either way
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Path:     OAuthCallbackPath(provider),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if query.Get("error") != "" {
+		return apperror.New(http.StatusBadRequest, "OAUTH_DENIED", "Sign-in was cancelled at the provider")
+	}
+
+	state, code := query.Get("state"), query.Get("code")
+	cookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || state == "" || code == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		return apperror.New(http.StatusBadRequest, "INVALID_STATE", "Invalid or expired sign-in, start again")
+	}
+
+	result, err := h.oauthService.Complete(r.Context(), provider, state, code, r.UserAgent(), getClientIP(r))
+	if err != nil {
+		if handled, err := respondSecondFactor(w, err); handled {
+			return err
+		}
+		switch {
+		case errors.Is(err, service.ErrUnknownProvider):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Unknown provider")
+		case errors.Is(err, service.ErrInvalidOAuthState):
+			return apperror.New(http.StatusBadRequest, "INVALID_STATE", "Invalid or expired sign-in, start again")
+		case errors.Is(err, auth.ErrOAuthNoVerifiedEmail):
+			return apperror.New(http.StatusForbidden, "EMAIL_NOT_VERIFIED", "The provider account has no verified email address")
+		case errors.Is(err, auth.ErrOAuthExchange):
+			log.Ctx(r.Context()).Warn().Err(err).Str("provider", provider).Msg("OAuth code exchange failed")
+			return apperror.New(http.StatusBadRequest, "INVALID_STATE", "Invalid or expired sign-in, start again")
+		case errors.Is(err, auth.ErrOAuthProfile):
+			log.Ctx(r.Context()).Error().Err(err).Str("provider", provider).Msg("Failed to read OAuth profile")
+			return apperror.New(http.StatusBadGateway, "OAUTH_FAILED", "The provider could not be reached")
+		case errors.Is(err, service.ErrOAuthAccountExists):
+			return apperror.New(http.StatusConflict, "USER_EXISTS", "An account with this email already exists, sign in with its password and link the provider from your account")
+		case errors.Is(err, service.ErrIdentityLinked):
+			return apperror.New(http.StatusConflict, "IDENTITY_LINKED", "The provider account is linked to another user")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Str("provider", provider).Msg("Failed to complete OAuth sign-in")
+			return apperror.New(http.StatusInternalServerError, "LOGIN_FAILED", "Failed to sign in")
+		}
+	}
+
+	if result.Linked != nil {
+		RespondWithJSON(w, http.StatusOK, result.Linked)
+		return nil
+	}
+	RespondWithJSON(w, http.StatusOK, result.Tokens)
+	return nil
+}
+
+// OAuthCallbackPath is the path a provider redirects back to, registered as
+// the redirect URL of the OAuth client
+func OAuthCallbackPath(provider string) string {
+	return "/api/v1/auth/" + provider + "/callback"
+}
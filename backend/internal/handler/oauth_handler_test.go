@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthLoginAndCallbackState(t *testing.T) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	provider := auth.NewGitHubProvider(auth.OAuthConfig{ClientID: "client", AuthURL: "https://github.example.com/authorize"})
+	h := NewOAuthHandler(service.NewOAuthService([]*auth.OAuthProvider{provider}, nil, nil, nil, store, service.OAuthServiceConfig{}))
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/auth/{provider}/login", HandlerFunc(h.LoginHandler))
+	mux.Handle("GET /api/v1/auth/{provider}/callback", HandlerFunc(h.CallbackHandler))
+	mux.Handle("POST /api/v1/user/identities/{provider}", HandlerFunc(h.LinkHandler))
+
+	// Login redirects to the provider and binds the state to the browser
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/auth/github/login", nil))
+	require.Equal(t, http.StatusFound, rr.Code)
+	assert.True(t, strings.HasPrefix(rr.Header().Get("Location"), "https://github.example.com/authorize?"))
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, oauthStateCookie, cookies[0].Name)
+	assert.Equal(t, OAuthCallbackPath(auth.ProviderGitHub), cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+
+	// A callback from another browser, without the cookie, is refused
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/auth/github/callback?code=c&state="+cookies[0].Value, nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "INVALID_STATE")
+
+	// So is one whose state doesn't match the cookie
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/github/callback?code=c&state=other", nil)
+	req.AddCookie(cookies[0])
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Users who cancel at the provider are told so
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/auth/github/callback?error=access_denied", nil))
+	assert.Contains(t, rr.Body.String(), "OAUTH_DENIED")
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/auth/myspace/login", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Linking returns the redirect for the signed-in user to follow, with the
+	// state bound to the browser the same way
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, withClaims(httptest.NewRequest(http.MethodPost, "/api/v1/user/identities/github", nil), uuid.New()))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var link OAuthLinkResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &link))
+	assert.True(t, strings.HasPrefix(link.RedirectURL, "https://github.example.com/authorize?"))
+	cookies = rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, OAuthCallbackPath(auth.ProviderGitHub), cookies[0].Path)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/user/identities/github", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
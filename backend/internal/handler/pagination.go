@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// Page size bounds for list endpoints
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// PaginationMeta describes the page returned by a list endpoint
+type PaginationMeta struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+// PaginatedResponse is the envelope shared by all paginated list endpoints
+type PaginatedResponse struct {
+	Data       any            `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// ResumePage is a page of resumes
+type ResumePage struct {
+	Data       []*domain.Resume `json:"data"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
+
+// UserPage is a page of users
+type UserPage struct {
+	Data       []*domain.User `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// CSPViolationPage is a page of CSP violations
+type CSPViolationPage struct {
+	Data       []*domain.CSPViolation `json:"data"`
+	Pagination PaginationMeta         `json:"pagination"`
+}
+
+// pageRequest holds the parsed list query parameters
+type pageRequest struct {
+	Page    int
+	Options domain.ListOptions
+}
+
+// parsePageRequest reads the page, limit, sort, order, created_after,
+// created_before and q query parameters. Sort must be one of sortFields;
+// created_at is always accepted and results default to newest first.
+func parsePageRequest(r *http.Request, sortFields ...string) (pageRequest, error) {
+	query := r.URL.Query()
+	req := pageRequest{
+		Page: 1,
+		Options: domain.ListOptions{
+			Limit:    defaultPageLimit,
+			SortBy:   domain.SortByCreatedAt,
+			SortDesc: true,
+			Search:   query.Get("q"),
+		},
+	}
+
+	if p := query.Get("page"); p != "" {
+		page, err := strconv.Atoi(p)
+		if err != nil || page < 1 || page > math.MaxInt32/maxPageLimit {
+			return req, errors.New("invalid page")
+		}
+		req.Page = page
+	}
+
+	if l := query.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return req, errors.New("limit must be between 1 and " + strconv.Itoa(maxPageLimit))
+		}
+		req.Options.Limit = limit
+	}
+	req.Options.Offset = (req.Page - 1) * req.Options.Limit
+
+	if sort := query.Get("sort"); sort != "" {
+		if sort != domain.SortByCreatedAt && !slices.Contains(sortFields, sort) {
+			return req, errors.New("invalid sort field")
+		}
+		req.Options.SortBy = sort
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		req.Options.SortDesc = false
+	default:
+		return req, errors.New("order must be asc or desc")
+	}
+
+	var err error
+	if req.Options.CreatedAfter, err = parseTimeParam(query.Get("created_after")); err != nil {
+		return req, errors.New("invalid created_after date")
+	}
+	if req.Options.CreatedBefore, err = parseTimeParam(query.Get("created_before")); err != nil {
+		return req, errors.New("invalid created_before date")
+	}
+
+	return req, nil
+}
+
+// parseTimeParam accepts an RFC 3339 timestamp or a plain date; empty means unset
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// RespondWithPage writes a page of results in the shared pagination envelope
+func RespondWithPage(w http.ResponseWriter, req pageRequest, data any, total int64) {
+	limit := int64(req.Options.Limit)
+	RespondWithJSON(w, http.StatusOK, PaginatedResponse{
+		Data: data,
+		Pagination: PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Options.Limit,
+			Total:      total,
+			TotalPages: (total + limit - 1) / limit,
+		},
+	})
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// PasskeyHandler handles registering passkeys and signing in with them
+type PasskeyHandler struct {
+	passkeyService *service.PasskeyService
+	// loginLimiter guards the public login endpoints
+	loginLimiter security.Limiter
+}
+
+// NewPasskeyHandler creates a new passkey handler
+func NewPasskeyHandler(passkeyService *service.PasskeyService, redisClient redis.UniversalClient) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+		loginLimiter:   NewUserRateLimiter(security.StrictRateLimit, redisClient),
+	}
+}
+
+// SetLoginLimiter replaces the limiter guarding the login endpoints, such as
+// one that fails closed
+func (h *PasskeyHandler) SetLoginLimiter(limiter security.Limiter) {
+	h.loginLimiter = limiter
+}
+
+// BeginPasskeyRegistrationRequest starts registering a passkey
+type BeginPasskeyRegistrationRequest struct {
+	// Name tells the user's passkeys apart, such as the device it is on
+	Name string `json:"name"`
+}
+
+// FinishPasskeyRequest answers a passkey challenge
+type FinishPasskeyRequest struct {
+	ChallengeID string `json:"challenge_id"`
+	// Credential is the PublicKeyCredential the browser returned, as JSON
+	Credential json.RawMessage `json:"credential"`
+}
+
+// PasskeySecondFactorRequest sets whether password logins need a passkey
+type PasskeySecondFactorRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// PasskeysResponse lists a user's passkeys
+type PasskeysResponse struct {
+	Passkeys []*domain.Passkey `json:"passkeys"`
+}
+
+// PasskeyChallengeResponse is returned when a login must be confirmed with a
+// passkey before tokens are issued
+type PasskeyChallengeResponse struct {
+	Message string `json:"message"`
+	*service.PasskeyChallenge
+}
+
+// respondSecondFactor answers a login of a user who requires a passkey,
+// reporting whether err was one. The login is finished with a passkey at
+// /api/v1/passkeys/login/finish.
+func respondSecondFactor(w http.ResponseWriter, err error) (bool, error) {
+	var secondFactor *service.SecondFactorRequiredError
+	if errors.As(err, &secondFactor) {
+		RespondWithJSON(w, http.StatusAccepted, PasskeyChallengeResponse{
+			Message:          "Confirm the login with a passkey",
+			PasskeyChallenge: secondFactor.Challenge,
+		})
+		return true, nil
+	}
+	if errors.Is(err, service.ErrSecondFactorUnavailable) {
+		return true, apperror.New(http.StatusServiceUnavailable, "PASSKEYS_UNAVAILABLE", "This account requires a passkey, but passkeys are not enabled")
+	}
+	return false, nil
+}
+
+// BeginRegistrationHandler starts registering a passkey for the current user
+func (h *PasskeyHandler) BeginRegistrationHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req BeginPasskeyRegistrationRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	challenge, err := h.passkeyService.BeginRegistration(r.Context(), userID, req.Name)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrPasskeyLimit):
+			return apperror.New(http.StatusUnprocessableEntity, apperror.CodeLimitExceeded, "Too many passkeys, remove one first")
+		default:
+			return apperror.Internal(err, "Failed to start passkey registration")
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, challenge)
+	return nil
+}
+
+// FinishRegistrationHandler stores the passkey the browser created for a
+// registration challenge
+func (h *PasskeyHandler) FinishRegistrationHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req FinishPasskeyRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(r.Context(), userID, req.ChallengeID, req.Credential)
+	if err != nil {
+		if appErr := passkeyError(err); appErr != nil {
+			return appErr
+		}
+		if errors.Is(err, service.ErrPasskeyExists) {
+			return apperror.New(http.StatusConflict, "PASSKEY_EXISTS", "This passkey is already registered")
+		}
+		return apperror.Internal(err, "Failed to register passkey")
+	}
+
+	RespondWithJSON(w, http.StatusCreated, passkey)
+	return nil
+}
+
+// GetPasskeysHandler lists the current user's passkeys
+func (h *PasskeyHandler) GetPasskeysHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	passkeys, err := h.passkeyService.Passkeys(r.Context(), userID)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get passkeys")
+	}
+
+	RespondWithJSON(w, http.StatusOK, PasskeysResponse{Passkeys: passkeys})
+	return nil
+}
+
+// DeletePasskeyHandler removes one of the current user's passkeys
+func (h *PasskeyHandler) DeletePasskeyHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	passkeyID, err := uuid.Parse(r.PathValue("passkeyId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid passkey ID")
+	}
+
+	if err := h.passkeyService.DeletePasskey(r.Context(), userID, passkeyID); err != nil {
+		if errors.Is(err, service.ErrPasskeyNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Passkey not found")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("passkey_id", passkeyID.String()).Msg("Failed to delete passkey")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to delete passkey")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Passkey removed successfully"})
+	return nil
+}
+
+// SetSecondFactorHandler sets whether the current user's password logins
+// must be confirmed with a passkey
+func (h *PasskeyHandler) SetSecondFactorHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req PasskeySecondFactorRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	if err := h.passkeyService.SetSecondFactor(r.Context(), userID, req.Enabled); err != nil {
+		if errors.Is(err, service.ErrNoPasskeys) {
+			return apperror.New(http.StatusUnprocessableEntity, "NO_PASSKEYS", "Register a passkey first")
+		}
+		return apperror.Internal(err, "Failed to update passkey requirement")
+	}
+
+	message := "Password logins no longer need a passkey"
+	if req.Enabled {
+		message = "Password logins now need a passkey"
+	}
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: message})
+	return nil
+}
+
+// BeginLoginHandler starts a passwordless login with a passkey
+func (h *PasskeyHandler) BeginLoginHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := applyRateLimit(w, r, h.loginLimiter); err != nil {
+		return err
+	}
+
+	challenge, err := h.passkeyService.BeginLogin(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to start passkey login")
+	}
+
+	RespondWithJSON(w, http.StatusOK, challenge)
+	return nil
+}
+
+// FinishLoginHandler signs in with the passkey assertion the browser
+// returned for a login challenge, whether a passwordless login or the
+// second factor of a password login
+func (h *PasskeyHandler) FinishLoginHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := applyRateLimit(w, r, h.loginLimiter); err != nil {
+		return err
+	}
+
+	var req FinishPasskeyRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	tokens, err := h.passkeyService.FinishLogin(r.Context(), req.ChallengeID, req.Credential, r.UserAgent(), getClientIP(r))
+	if err != nil {
+		if appErr := passkeyError(err); appErr != nil {
+			return appErr
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to login with passkey")
+		return apperror.New(http.StatusInternalServerError, "LOGIN_FAILED", "Failed to login user")
+	}
+
+	RespondWithJSON(w, http.StatusOK, tokens)
+	return nil
+}
+
+// passkeyError maps the errors of answering a passkey challenge, or returns
+// nil for other errors
+func passkeyError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrCeremonyNotFound):
+		return apperror.New(http.StatusBadRequest, "INVALID_CHALLENGE", "Passkey challenge not found or expired")
+	case errors.Is(err, service.ErrPasskeyInvalid), errors.Is(err, service.ErrUserNotFound):
+		return apperror.New(http.StatusUnauthorized, "INVALID_PASSKEY", "Passkey could not be verified")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPasskeyRepository has no passkeys
+type stubPasskeyRepository struct {
+	domain.PasskeyRepository
+}
+
+func (s *stubPasskeyRepository) CountPasskeysByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func newTestPasskeyHandler(t *testing.T) *PasskeyHandler {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	mr := miniredis.RunT(t)
+	userRepo := new(MockUserRepository)
+	authService := service.NewAuthService(userRepo, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), service.AuthServiceConfig{})
+	passkeyService, err := service.NewPasskeyService(&stubPasskeyRepository{}, userRepo, authService, store, service.PasskeyServiceConfig{
+		RPID:      "example.com",
+		RPOrigins: []string{"https://app.example.com"},
+	})
+	require.NoError(t, err)
+	return NewPasskeyHandler(passkeyService, redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+}
+
+func TestPasskeyLoginHandlers(t *testing.T) {
+	h := newTestPasskeyHandler(t)
+
+	rr := httptest.NewRecorder()
+	HandlerFunc(h.BeginLoginHandler).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/passkeys/login/begin", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var challenge service.PasskeyChallenge
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &challenge))
+	assert.NotEmpty(t, challenge.ID)
+	assert.Contains(t, rr.Body.String(), `"rpId":"example.com"`)
+
+	rr = httptest.NewRecorder()
+	body := `{"challenge_id":"unknown","credential":{}}`
+	HandlerFunc(h.FinishLoginHandler).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/passkeys/login/finish", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"INVALID_CHALLENGE"`)
+}
+
+func TestSetSecondFactorHandlerWithoutPasskeys(t *testing.T) {
+	h := newTestPasskeyHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := withClaims(httptest.NewRequest("PUT", "/api/v1/user/passkeys/second-factor", bytes.NewBufferString(`{"enabled":true}`)), uuid.New())
+	HandlerFunc(h.SetSecondFactorHandler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"NO_PASSKEYS"`)
+}
+
+func TestLoginHandlerPasskeyUnavailable(t *testing.T) {
+	h, mockRepo, mr := setupTest(t)
+	defer mr.Close()
+
+	hash, err := security.HashPassword("password123", nil)
+	require.NoError(t, err)
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hash, Role: "user", PasskeyRequired: true}
+	mockRepo.On("GetUserByEmail", "test@example.com").Return(user, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(`{"email":"test@example.com","password":"password123"}`))
+	HandlerFunc(h.LoginHandler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"PASSKEYS_UNAVAILABLE"`)
+}
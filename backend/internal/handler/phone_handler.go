@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// PhoneHandler handles the phone number users receive SMS codes at
+type PhoneHandler struct {
+	phoneService *service.PhoneService
+	// smsLimiter guards the endpoint that texts codes
+	smsLimiter security.Limiter
+}
+
+// NewPhoneHandler creates a new phone handler
+func NewPhoneHandler(phoneService *service.PhoneService, redisClient redis.UniversalClient) *PhoneHandler {
+	return &PhoneHandler{
+		phoneService: phoneService,
+		smsLimiter:   NewUserRateLimiter(ResetSMSRateLimit, redisClient),
+	}
+}
+
+// SetSMSLimiter replaces the limiter guarding the endpoint that texts codes
+func (h *PhoneHandler) SetSMSLimiter(limiter security.Limiter) {
+	h.smsLimiter = limiter
+}
+
+// SetPhoneRequest sets the user's phone number
+type SetPhoneRequest struct {
+	Phone string `json:"phone"`
+	// Region is the ISO 3166-1 alpha-2 country national numbers are read in;
+	// it may be left out for international numbers
+	Region string `json:"region,omitempty"`
+}
+
+// VerifyPhoneRequest verifies the user's phone number
+type VerifyPhoneRequest struct {
+	Code string `json:"code"`
+}
+
+// PhoneResponse describes the user's phone number
+type PhoneResponse struct {
+	*domain.UserPhone
+	Verified bool `json:"verified"`
+}
+
+// GetPhoneHandler returns the current user's phone number
+func (h *PhoneHandler) GetPhoneHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	userPhone, err := h.phoneService.Phone(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrPhoneNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "No phone number added")
+		}
+		return apperror.Internal(err, "Failed to get phone number")
+	}
+
+	RespondWithJSON(w, http.StatusOK, phoneResponse(userPhone))
+	return nil
+}
+
+// SetPhoneHandler sets the current user's phone number and texts it a code
+// to verify it with. The number can't receive reset codes until verified.
+func (h *PhoneHandler) SetPhoneHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := applyRateLimit(w, r, h.smsLimiter); err != nil {
+		return err
+	}
+
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req SetPhoneRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	userPhone, err := h.phoneService.SetPhone(r.Context(), userID, req.Phone, req.Region)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrCodeLimitReached):
+			return apperror.New(http.StatusTooManyRequests, apperror.CodeLimitExceeded, "Too many codes sent, try again later")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to set phone number")
+			return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to set phone number")
+		}
+	}
+
+	RespondWithJSON(w, http.StatusAccepted, phoneResponse(userPhone))
+	return nil
+}
+
+// VerifyPhoneHandler verifies the current user's phone number with the code
+// texted to it
+func (h *PhoneHandler) VerifyPhoneHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req VerifyPhoneRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	userPhone, err := h.phoneService.VerifyPhone(r.Context(), userID, req.Code)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCode) {
+			return apperror.New(http.StatusBadRequest, "INVALID_CODE", "Invalid or expired verification code")
+		}
+		return apperror.Internal(err, "Failed to verify phone number")
+	}
+
+	RespondWithJSON(w, http.StatusOK, phoneResponse(userPhone))
+	return nil
+}
+
+// DeletePhoneHandler removes the current user's phone number
+func (h *PhoneHandler) DeletePhoneHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	if err := h.phoneService.RemovePhone(r.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrPhoneNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "No phone number added")
+		}
+		return apperror.Internal(err, "Failed to remove phone number")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Phone number removed successfully"})
+	return nil
+}
+
+// phoneResponse adds whether the number is verified
+func phoneResponse(userPhone *domain.UserPhone) PhoneResponse {
+	return PhoneResponse{UserPhone: userPhone, Verified: userPhone.Verified()}
+}
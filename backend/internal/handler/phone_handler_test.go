@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/sms"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPhoneRepository holds phone numbers in memory
+type stubPhoneRepository struct {
+	domain.UserPhoneRepository
+	phones map[uuid.UUID]*domain.UserPhone
+}
+
+func (s *stubPhoneRepository) SavePhone(ctx context.Context, phone *domain.UserPhone) error {
+	s.phones[phone.UserID] = phone
+	return nil
+}
+
+func (s *stubPhoneRepository) GetPhone(ctx context.Context, userID uuid.UUID) (*domain.UserPhone, error) {
+	phone, ok := s.phones[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return phone, nil
+}
+
+func newTestPhoneService(t *testing.T) (*service.PhoneService, redis.UniversalClient) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	smsService := service.NewSMSService(jobs.NewQueue(jobs.QueueConfig{Redis: redisClient}), sms.NewLogSender())
+	repo := &stubPhoneRepository{phones: map[uuid.UUID]*domain.UserPhone{}}
+	return service.NewPhoneService(repo, smsService, store, service.PhoneServiceConfig{}), redisClient
+}
+
+func TestSetPhoneHandler(t *testing.T) {
+	phoneService, redisClient := newTestPhoneService(t)
+	h := NewPhoneHandler(phoneService, redisClient)
+	userID := uuid.New()
+	send := func(handler HandlerFunc, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, withClaims(httptest.NewRequest("POST", "/api/v1/user/phone", bytes.NewBufferString(body)), userID))
+		return rr
+	}
+
+	assert.Equal(t, http.StatusBadRequest, send(h.SetPhoneHandler, `{"phone":"12345","region":"US"}`).Code)
+
+	rr := send(h.SetPhoneHandler, `{"phone":"(415) 555-2671","region":"US"}`)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"phone":"+14155552671"`)
+	assert.Contains(t, rr.Body.String(), `"verified":false`)
+
+	// The number isn't verified by a wrong code
+	rr = send(h.VerifyPhoneHandler, `{"code":"000000"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "INVALID_CODE")
+}
+
+func TestRequestPasswordResetBySMSHandler(t *testing.T) {
+	h, mockRepo, mr := setupTest(t)
+	defer mr.Close()
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com"}
+	mockRepo.On("GetUserByEmail", user.Email).Return(user, nil)
+	request := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.RequestPasswordResetHandler).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/request-password-reset", bytes.NewBufferString(body)))
+		return rr
+	}
+
+	// The SMS channel is only offered when a phone service is set
+	rr := request(`{"email":"jane@example.com","channel":"sms"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "CHANNEL_UNAVAILABLE")
+
+	// Accounts without a verified number get the same answer as others, and
+	// no token
+	phoneService, _ := newTestPhoneService(t)
+	h.authService.SetPhoneService(phoneService)
+	rr = request(`{"email":"jane@example.com","channel":"sms"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"message":"Password reset code sent if the account has a verified phone number"}`, rr.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, request(`{"email":"jane@example.com","channel":"pigeon"}`).Code)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// maxPhotoSize caps the size of an uploaded profile photo
+const maxPhotoSize = 5 << 20
+
+// PhotoHandler handles the profile photos of resumes
+type PhotoHandler struct {
+	photoService *service.PhotoService
+	eventService *service.ResumeEventService
+}
+
+// NewPhotoHandler creates a new photo handler
+func NewPhotoHandler(photoService *service.PhotoService, eventService *service.ResumeEventService) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+		eventService: eventService,
+	}
+}
+
+// PhotoResponse is the URL of a resume's profile photo
+type PhotoResponse struct {
+	PhotoURL string `json:"photo_url"`
+}
+
+// UploadPhotoHandler stores the image in the "photo" field of a multipart
+// form as the resume's profile photo
+func (h *PhotoHandler) UploadPhotoHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	// Leave room for the form around the photo
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhotoSize+64<<10)
+	if err := r.ParseMultipartForm(maxPhotoSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return errPhotoTooLarge()
+		}
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Request must be a multipart form with a photo field")
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Request must be a multipart form with a photo field")
+	}
+	defer file.Close()
+	if header.Size > maxPhotoSize {
+		return errPhotoTooLarge()
+	}
+
+	// The declared size can't be trusted, so read one byte past the limit to
+	// tell a photo at the limit from a larger one
+	data, err := io.ReadAll(io.LimitReader(file, maxPhotoSize+1))
+	if err != nil {
+		return apperror.Internal(err, "Failed to read photo")
+	}
+	if len(data) > maxPhotoSize {
+		return errPhotoTooLarge()
+	}
+
+	photoURL, err := h.photoService.Upload(r.Context(), resume.ID, bytes.NewReader(data))
+	if err != nil {
+		return photoError(err, "Failed to upload photo")
+	}
+
+	h.recordEvent(r, resume.ID, photoURL)
+
+	RespondWithJSON(w, http.StatusOK, PhotoResponse{PhotoURL: photoURL})
+	return nil
+}
+
+// errPhotoTooLarge is returned for photos over maxPhotoSize
+func errPhotoTooLarge() error {
+	return apperror.New(http.StatusRequestEntityTooLarge, apperror.CodeInvalidRequest, "Photo too large, the limit is "+strconv.Itoa(maxPhotoSize>>20)+" MB")
+}
+
+// DeletePhotoHandler removes the resume's profile photo
+func (h *PhotoHandler) DeletePhotoHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	if err := h.photoService.Remove(r.Context(), resume.ID); err != nil {
+		return photoError(err, "Failed to delete photo")
+	}
+
+	h.recordEvent(r, resume.ID, "")
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Photo deleted successfully"})
+	return nil
+}
+
+// GetPhotoHandler serves a stored profile photo. Photo names are random and
+// change with every upload, so photos are cached for long.
+func (h *PhotoHandler) GetPhotoHandler(w http.ResponseWriter, r *http.Request) error {
+	object, err := h.photoService.Get(r.Context(), r.PathValue("name"))
+	if err != nil {
+		return photoError(err, "Failed to get photo")
+	}
+	defer object.Body.Close()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	if object.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	// Resume pages served from other origins show the photo too
+	w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, object.Body); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to write photo")
+	}
+	return nil
+}
+
+// recordEvent records the change of a resume's photo in its event log. The
+// change has already been stored, so a failure is logged rather than returned.
+func (h *PhotoHandler) recordEvent(r *http.Request, resumeID uuid.UUID, photoURL string) {
+	actorID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return
+	}
+	if _, err := h.eventService.Record(r.Context(), resumeID, actorID, domain.EventEntityPersonalInfo, uuid.Nil, domain.EventOpUpdate, PhotoResponse{PhotoURL: photoURL}); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to record resume event")
+	}
+}
+
+// photoError maps the errors of the photo service
+func photoError(err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrUnsupportedPhoto):
+		return apperror.New(http.StatusUnsupportedMediaType, "UNSUPPORTED_PHOTO", err.Error())
+	case errors.Is(err, service.ErrPhotoTooLarge):
+		return apperror.New(http.StatusUnprocessableEntity, "PHOTO_TOO_LARGE", "Photo dimensions are too large")
+	case errors.Is(err, service.ErrNoPersonalInfo):
+		return apperror.New(http.StatusConflict, "NO_PERSONAL_INFO", "Save personal info before uploading a photo")
+	case errors.Is(err, service.ErrPhotoNotFound):
+		return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Photo not found")
+	default:
+		return apperror.Internal(err, message)
+	}
+}
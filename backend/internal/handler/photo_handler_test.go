@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPhotoResumeRepository has the personal info of a single resume
+type stubPhotoResumeRepository struct {
+	domain.ResumeRepository
+	info *domain.PersonalInfo
+}
+
+func (s *stubPhotoResumeRepository) GetPersonalInfo(ctx context.Context, resumeID uuid.UUID) (*domain.PersonalInfo, error) {
+	copied := *s.info
+	return &copied, nil
+}
+
+func (s *stubPhotoResumeRepository) SetPhotoURL(ctx context.Context, resumeID uuid.UUID, photoURL string) error {
+	s.info.PhotoURL = photoURL
+	return nil
+}
+
+// photoForm builds a multipart form with a file in the given field
+func photoForm(t *testing.T, field string, data []byte) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "photo.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestPhotoHandlers(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New()}
+	repo := &stubPhotoResumeRepository{info: &domain.PersonalInfo{FirstName: "Ada"}}
+	h := NewPhotoHandler(service.NewPhotoService(repo, storage.NewMemory(), service.PhotoServiceConfig{}), nil)
+	upload := func(body *bytes.Buffer, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/resumes/"+resume.ID.String()+"/photo", body)
+		req.Header.Set("Content-Type", contentType)
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.UploadPhotoHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 40, 40))))
+	rr := upload(photoForm(t, "photo", img.Bytes()))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp PhotoResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.True(t, strings.HasPrefix(resp.PhotoURL, "/api/v1/photos/"), resp.PhotoURL)
+	assert.Equal(t, resp.PhotoURL, repo.info.PhotoURL)
+
+	// The photo is served publicly
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/photos/{name}", HandlerFunc(h.GetPhotoHandler))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", resp.PhotoURL, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/jpeg", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Cache-Control"), "immutable")
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/photos/"+path.Base(resp.PhotoURL)+"x", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = upload(photoForm(t, "photo", []byte("not an image")))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+
+	rr = upload(photoForm(t, "avatar", img.Bytes()))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = upload(bytes.NewBufferString(`{"photo":"x"}`), "application/json")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = upload(photoForm(t, "photo", make([]byte, maxPhotoSize+128<<10)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	// Photos just over the limit fit in the form's slack but are refused too
+	rr = upload(photoForm(t, "photo", make([]byte, maxPhotoSize+1)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/proofread"
+	"github.com/lordaris/resume_generator/pkg/locale"
+)
+
+// ProofreadHandler handles spell and grammar checks of resumes
+type ProofreadHandler struct {
+	resumeRepo domain.ResumeRepository
+	checker    proofread.Checker
+}
+
+// NewProofreadHandler creates a new proofread handler
+func NewProofreadHandler(resumeRepo domain.ResumeRepository, checker proofread.Checker) *ProofreadHandler {
+	return &ProofreadHandler{
+		resumeRepo: resumeRepo,
+		checker:    checker,
+	}
+}
+
+// ProofreadRequest optionally overrides the language a resume is checked in
+type ProofreadRequest struct {
+	// Language is a BCP 47 tag; the resume's language when empty
+	Language string `json:"language,omitempty"`
+}
+
+// ProofreadResponse lists the issues found in a resume's text
+type ProofreadResponse struct {
+	Checker  string            `json:"checker"`
+	Language string            `json:"language"`
+	Issues   []proofread.Issue `json:"issues"`
+}
+
+// ProofreadResumeHandler checks the text of a resume's sections, reporting
+// each issue with its offset in the field it was found in
+func (h *ProofreadHandler) ProofreadResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	var req ProofreadRequest
+	if err := decodeJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		return decodeError(err)
+	}
+
+	complete, err := loadCompleteResume(r, h.resumeRepo)
+	if err != nil {
+		return err
+	}
+
+	language := complete.Language
+	if req.Language != "" {
+		if language, err = locale.Normalize(req.Language); err != nil {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Language must be a language tag such as \"en\" or \"es-MX\"")
+		}
+	}
+
+	issues, err := proofread.Proofread(r.Context(), h.checker, language, resumeTexts(complete))
+	if err != nil {
+		if errors.Is(err, proofread.ErrUnavailable) {
+			return apperror.Wrap(err, http.StatusBadGateway, "PROOFREAD_UNAVAILABLE", "Proofreading is unavailable, try again later")
+		}
+		return apperror.Internal(err, "Failed to proofread resume")
+	}
+
+	RespondWithJSON(w, http.StatusOK, ProofreadResponse{Checker: h.checker.Name(), Language: language, Issues: issues})
+	return nil
+}
+
+// resumeTexts returns the prose fields of a resume, leaving out names,
+// dates and other fields a checker would flag wrongly
+func resumeTexts(resume *domain.Resume) []proofread.Text {
+	var texts []proofread.Text
+	if resume.PersonalInfo != nil {
+		texts = append(texts, proofread.Text{Section: "personal_info", Field: "job_title", Text: resume.PersonalInfo.JobTitle})
+	}
+	for i, e := range resume.Experience {
+		texts = append(texts,
+			proofread.Text{Section: "experience", Index: i, Field: "title", Text: e.JobTitle},
+			proofread.Text{Section: "experience", Index: i, Field: "description", Text: e.Description},
+		)
+	}
+	for i, e := range resume.Education {
+		texts = append(texts,
+			proofread.Text{Section: "education", Index: i, Field: "degree", Text: e.Degree},
+			proofread.Text{Section: "education", Index: i, Field: "field", Text: e.Field},
+			proofread.Text{Section: "education", Index: i, Field: "description", Text: e.Description},
+		)
+	}
+	for i, p := range resume.Projects {
+		texts = append(texts, proofread.Text{Section: "projects", Index: i, Field: "description", Text: p.Description})
+	}
+	return texts
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/proofread"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofreadResumeHandler(t *testing.T) {
+	resume := &domain.Resume{
+		ID:             uuid.New(),
+		ResumeMetadata: domain.ResumeMetadata{Language: "en"},
+		Experience:     []*domain.Experience{{JobTitle: "Engineer", Description: "Shipped teh app"}},
+	}
+	h := NewProofreadHandler(&stubCompleteResumeRepository{resume: resume}, proofread.NewBasic())
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/resumes/"+resume.ID.String()+"/proofread", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.ProofreadResumeHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post("")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body ProofreadResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "basic", body.Checker)
+	assert.Equal(t, "en", body.Language)
+	require.Len(t, body.Issues, 1)
+	assert.Equal(t, "experience", body.Issues[0].Section)
+	assert.Equal(t, "description", body.Issues[0].Field)
+	assert.Equal(t, 8, body.Issues[0].Offset)
+
+	// English spelling rules don't apply to other languages
+	rr = post(`{"language": "de"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Empty(t, body.Issues)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"language": "not a language"}`).Code)
+}
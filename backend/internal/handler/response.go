@@ -6,15 +6,29 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
 	"github.com/rs/zerolog/log"
 )
 
-// ErrorResponse represents a standardized error response
-type ErrorResponse struct {
-	Status  int            `json:"-"`
-	Error   string         `json:"error"`
-	Code    string         `json:"code"`
-	Details map[string]any `json:"details,omitempty"`
+// HandlerFunc is an HTTP handler that returns its errors instead of writing
+// them. ServeHTTP writes a returned error as a problem+json response, so
+// handlers report failures with a return rather than writing them ad hoc.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f and writes the error it returns, if any
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// WriteError writes err as a problem+json response. Middleware uses it
+// directly; handlers return their errors instead.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	apperror.Write(w, r, err)
 }
 
 // RespondWithJSON writes a JSON response
@@ -37,20 +51,8 @@ func RespondWithJSON(w http.ResponseWriter, status int, data any) {
 	}
 }
 
-// RespondWithError writes a JSON error response
-func RespondWithError(w http.ResponseWriter, status int, message, code string) {
-	response := ErrorResponse{
-		Status: status,
-		Error:  message,
-		Code:   code,
-	}
-	RespondWithJSON(w, status, response)
-}
-
-// RespondWithValidationError writes a validation error response
-func RespondWithValidationError(w http.ResponseWriter, validationErrors any) {
-	// Create error details
-	details := make(map[string]any)
+// validationError reports the fields a request failed validation on
+func validationError(validationErrors any) error {
 	fieldErrors := make(map[string]string)
 
 	// Check if it's the expected type
@@ -65,17 +67,9 @@ func RespondWithValidationError(w http.ResponseWriter, validationErrors any) {
 		fieldErrors["general"] = "Validation failed"
 	}
 
-	details["fields"] = fieldErrors
-
-	// Create error response
-	response := ErrorResponse{
-		Status:  http.StatusBadRequest,
-		Error:   "Validation failed",
-		Code:    "VALIDATION_FAILED",
-		Details: details,
-	}
-
-	RespondWithJSON(w, http.StatusBadRequest, response)
+	return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, "Validation failed").WithDetails(map[string]any{
+		"fields": fieldErrors,
+	})
 }
 
 // GetValidationErrorMessage returns a human-readable validation error message
@@ -96,17 +90,78 @@ func GetValidationErrorMessage(err validator.FieldError) string {
 	}
 }
 
-// SuccessResponse represents a standardized success response
-type SuccessResponse struct {
+// MessageResponse is returned by actions that have no resource to return
+type MessageResponse struct {
 	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
 }
 
-// // respondWithSuccess writes a JSON success response
-// func respondWithSuccess(w http.ResponseWriter, message string, data any) {
-// 	response := SuccessResponse{
-// 		Message: message,
-// 		Data:    data,
-// 	}
-// 	RespondWithJSON(w, http.StatusOK, response)
-// }
+// CreatedResponse is returned when a resume entry is created
+type CreatedResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Message string    `json:"message"`
+}
+
+// RegisterResponse is returned when a user registers
+type RegisterResponse struct {
+	Message string    `json:"message"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+// DataExportResponse is returned while a data export is being generated
+type DataExportResponse struct {
+	Message string                    `json:"message"`
+	Export  *service.DataExportStatus `json:"export"`
+}
+
+// ResumeEventsResponse lists resume events in version order
+type ResumeEventsResponse struct {
+	Events []*domain.ResumeEvent `json:"events"`
+}
+
+// ResumeVersionResponse is a resume rebuilt as it was at a given version
+type ResumeVersionResponse struct {
+	Version int64          `json:"version"`
+	Resume  *domain.Resume `json:"resume"`
+}
+
+// BulkSkillResult reports the outcome for one entry of a bulk skill add
+type BulkSkillResult struct {
+	Index int       `json:"index"`
+	Name  string    `json:"name"`
+	ID    uuid.UUID `json:"id,omitzero"`
+	Error string    `json:"error,omitempty"`
+}
+
+// BulkSkillsResponse is returned when several skills are added at once
+type BulkSkillsResponse struct {
+	Message string            `json:"message"`
+	Results []BulkSkillResult `json:"results"`
+}
+
+// GroupedSkill is a skill with its proficiency level named
+type GroupedSkill struct {
+	Name             string `json:"name"`
+	Proficiency      int    `json:"proficiency,omitempty"`
+	ProficiencyLabel string `json:"proficiency_label,omitempty"`
+}
+
+// SkillGroupResponse lists the skills of one category
+type SkillGroupResponse struct {
+	Category string         `json:"category"`
+	Skills   []GroupedSkill `json:"skills"`
+}
+
+// GroupedSkillsResponse lists a resume's skills grouped by category, in order of first use
+type GroupedSkillsResponse struct {
+	Groups []SkillGroupResponse `json:"groups"`
+}
+
+// IncidentsResponse lists incidents
+type IncidentsResponse struct {
+	Incidents []*domain.Incident `json:"incidents"`
+}
+
+// SessionsResponse lists the user's active sessions
+type SessionsResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// ResumeActivityHandler handles resume activity feeds
+type ResumeActivityHandler struct {
+	activityService *service.ResumeActivityService
+}
+
+// NewResumeActivityHandler creates a new resume activity handler
+func NewResumeActivityHandler(activityService *service.ResumeActivityService) *ResumeActivityHandler {
+	return &ResumeActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// GetActivityHandler returns a page of a resume's activity, newest first:
+// its edits, exports, emails, share links and their views, publishing and
+// page views, and note saves
+func (h *ResumeActivityHandler) GetActivityHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var limit int
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid limit")
+		}
+	}
+
+	page, err := h.activityService.Feed(r.Context(), resume.ID, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid cursor")
+		}
+		return apperror.Internal(err, "Failed to get resume activity")
+	}
+
+	RespondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// recordActivity records an activity of a resume on behalf of the
+// authenticated user, or of an anonymous viewer on public routes. What it
+// records has already happened, so a failure is logged rather than returned.
+// Nothing is recorded when activityService is nil.
+func recordActivity(r *http.Request, activityService *service.ResumeActivityService, resumeID uuid.UUID, kind string, details any) {
+	if activityService == nil {
+		return
+	}
+
+	// Public routes have no user
+	actorID, _ := GetUserIDFromContext(r.Context())
+	if err := activityService.Record(r.Context(), resumeID, actorID, kind, details); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resumeID.String()).Str("kind", kind).Msg("Failed to record resume activity")
+	}
+}
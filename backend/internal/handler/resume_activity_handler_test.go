@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activityRepository keeps activity in memory, oldest first
+type activityRepository struct {
+	activities []*domain.ResumeActivity
+}
+
+func (r *activityRepository) AddActivity(ctx context.Context, activity *domain.ResumeActivity) error {
+	activity.ID = uuid.New()
+	// Keep activity in order even when recorded within the clock's resolution
+	activity.CreatedAt = time.Now().UTC().Add(time.Duration(len(r.activities)) * time.Millisecond)
+	r.activities = append(r.activities, activity)
+	return nil
+}
+
+func (r *activityRepository) GetActivityBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*domain.ResumeActivity, error) {
+	var page []*domain.ResumeActivity
+	for i := len(r.activities) - 1; i >= 0 && len(page) < limit; i-- {
+		activity := r.activities[i]
+		if activity.ResumeID == resumeID && (before.IsZero() || activity.CreatedAt.Before(before)) {
+			page = append(page, activity)
+		}
+	}
+	return page, nil
+}
+
+func (r *activityRepository) DeleteActivity(ctx context.Context, resumeID uuid.UUID) error {
+	r.activities = slices.DeleteFunc(r.activities, func(a *domain.ResumeActivity) bool { return a.ResumeID == resumeID })
+	return nil
+}
+
+// noEventRepository is an empty event log
+type noEventRepository struct {
+	domain.ResumeEventRepository
+}
+
+func (noEventRepository) GetEventsBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*domain.ResumeEvent, error) {
+	return nil, nil
+}
+
+func TestResumeActivityHandler(t *testing.T) {
+	userID := uuid.New()
+	resume := &domain.Resume{
+		ID:             uuid.New(),
+		UserID:         userID,
+		ResumeMetadata: domain.ResumeMetadata{Language: "en"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Jane", LastName: "Doe"},
+	}
+	activityService := service.NewResumeActivityService(&activityRepository{}, noEventRepository{}, service.ResumeActivityServiceConfig{})
+	publicationHandler := NewResumePublicationHandler(
+		service.NewResumePublicationService(&publicationRepository{publications: map[uuid.UUID]*domain.ResumePublication{}}, &stubCompleteResumeRepository{resume: resume}),
+		"",
+	)
+	publicationHandler.SetActivityService(activityService)
+	h := NewResumeActivityHandler(activityService)
+
+	mux := http.NewServeMux()
+	mux.Handle("PUT /api/v1/resumes/{id}/publication", HandlerFunc(publicationHandler.PublishResumeHandler))
+	mux.Handle("GET /r/{slug}", HandlerFunc(publicationHandler.PublishedResumeHandler))
+	mux.Handle("GET /api/v1/resumes/{id}/activity", HandlerFunc(h.GetActivityHandler))
+	serve := func(method, path, body string, owner bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if owner {
+			req = withClaims(req, userID)
+			req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+	activityPath := "/api/v1/resumes/" + resume.ID.String() + "/activity"
+
+	require.Equal(t, http.StatusOK, serve(http.MethodPut, "/api/v1/resumes/"+resume.ID.String()+"/publication", `{"slug": "jane-doe"}`, true).Code)
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/r/jane-doe", "", false).Code)
+
+	rr := serve(http.MethodGet, activityPath+"?limit=1", "", true)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var page service.ActivityPage
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	require.Len(t, page.Activity, 1)
+	// Page views are anonymous
+	assert.Equal(t, domain.ActivityPageView, page.Activity[0].Kind)
+	assert.Equal(t, uuid.Nil, page.Activity[0].ActorID)
+	require.NotEmpty(t, page.NextCursor)
+
+	rr = serve(http.MethodGet, activityPath+"?limit=1&cursor="+page.NextCursor, "", true)
+	require.Equal(t, http.StatusOK, rr.Code)
+	page = service.ActivityPage{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	require.Len(t, page.Activity, 1)
+	assert.Equal(t, domain.ActivityPublish, page.Activity[0].Kind)
+	assert.Equal(t, userID, page.Activity[0].ActorID)
+	assert.JSONEq(t, `{"slug":"jane-doe"}`, string(page.Activity[0].Details))
+	assert.Empty(t, page.NextCursor)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, activityPath+"?cursor=nope", "", true).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, activityPath+"?limit=0", "", true).Code)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+)
+
+// ResumeFeedHandler handles the feeds automation platforms poll for changes
+// to a user's resumes
+type ResumeFeedHandler struct {
+	feedService *service.ResumeFeedService
+}
+
+// NewResumeFeedHandler creates a new resume feed handler
+func NewResumeFeedHandler(feedService *service.ResumeFeedService) *ResumeFeedHandler {
+	return &ResumeFeedHandler{
+		feedService: feedService,
+	}
+}
+
+// UpdatedSinceHandler returns the current user's resumes changed after the
+// ts query parameter, or after the cursor of a previous page. One of them is
+// required.
+func (h *ResumeFeedHandler) UpdatedSinceHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	query, err := parseFeedQuery(r)
+	if err != nil {
+		return err
+	}
+	if query.since.IsZero() && query.cursor == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "ts or cursor is required")
+	}
+
+	page, err := h.feedService.ChangedResumes(r.Context(), userID, query.since, query.cursor, query.limit)
+	if err != nil {
+		return feedError(err, "Failed to get changed resumes")
+	}
+
+	RespondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// EventsHandler returns the edits of all the current user's resumes, oldest
+// first, after the ts query parameter or the cursor of a previous page
+func (h *ResumeFeedHandler) EventsHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	query, err := parseFeedQuery(r)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.feedService.Events(r.Context(), userID, query.since, query.cursor, query.limit)
+	if err != nil {
+		return feedError(err, "Failed to get resume events")
+	}
+
+	RespondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// feedQuery holds the parsed query parameters of a polling feed
+type feedQuery struct {
+	since  time.Time
+	cursor string
+	limit  int
+}
+
+// parseFeedQuery reads the ts, cursor and limit query parameters. ts is an
+// RFC 3339 time or Unix seconds, as automation platforms send either.
+func parseFeedQuery(r *http.Request) (feedQuery, error) {
+	q := r.URL.Query()
+	query := feedQuery{cursor: q.Get("cursor")}
+
+	if ts := q.Get("ts"); ts != "" {
+		if seconds, err := strconv.ParseInt(ts, 10, 64); err == nil && seconds > 0 {
+			query.since = time.Unix(seconds, 0).UTC()
+		} else if since, err := time.Parse(time.RFC3339, ts); err == nil {
+			query.since = since
+		} else {
+			return query, apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid ts, use an RFC 3339 time or Unix seconds")
+		}
+	}
+
+	if l := q.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 1 {
+			return query, apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid limit")
+		}
+		query.limit = limit
+	}
+	return query, nil
+}
+
+// feedError maps the errors of reading a feed
+func feedError(err error, message string) error {
+	if errors.Is(err, service.ErrInvalidFeedCursor) {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid cursor")
+	}
+	return apperror.Internal(err, message)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// sinceEventRepository records the time feeds are read from
+type sinceEventRepository struct {
+	domain.ResumeEventRepository
+	since time.Time
+}
+
+func (r *sinceEventRepository) GetOwnerChangedResumes(ctx context.Context, ownerID uuid.UUID, afterSeq int64, since, until time.Time, limit int) ([]*domain.ResumeChange, error) {
+	r.since = since
+	return nil, nil
+}
+
+func TestUpdatedSinceHandler(t *testing.T) {
+	events := &sinceEventRepository{}
+	h := NewResumeFeedHandler(service.NewResumeFeedService(events, nil, service.ResumeFeedServiceConfig{}))
+	get := func(query string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.UpdatedSinceHandler).ServeHTTP(rr, withClaims(httptest.NewRequest("GET", "/api/v1/resumes/updated-since"+query, nil), uuid.New()))
+		return rr
+	}
+
+	for _, query := range []string{"", "?ts=yesterday", "?ts=2025-04-01T12:00:00Z&limit=0", "?cursor=abc"} {
+		assert.Equal(t, http.StatusBadRequest, get(query).Code, query)
+	}
+
+	rr := get("?ts=2025-04-01T12:00:00Z")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"data":[],"has_more":false}`, rr.Body.String())
+	assert.Equal(t, time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC), events.since.UTC())
+
+	// Unix seconds work too
+	assert.Equal(t, http.StatusOK, get("?ts=1743508800").Code)
+	assert.Equal(t, time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC), events.since)
+}
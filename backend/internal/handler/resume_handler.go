@@ -1,1205 +1,1087 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/analysis"
+	"github.com/lordaris/resume_generator/internal/apperror"
 	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/parser"
+	"github.com/lordaris/resume_generator/internal/render"
 	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/locale"
+	"github.com/rs/zerolog/log"
 )
 
 // ResumeHandler handles resume-related requests
 type ResumeHandler struct {
-	resumeRepo domain.ResumeRepository
+	resumeRepo        domain.ResumeRepository
+	eventService      *service.ResumeEventService
+	activityService   *service.ResumeActivityService
+	exportService     *service.ResumeExportService
+	deletionService   *service.ResumeDeletionService
+	userRepo          domain.UserRepository
+	permissionService *service.PermissionService
 }
 
 // NewResumeHandler creates a new resume handler
-func NewResumeHandler(resumeRepo domain.ResumeRepository) *ResumeHandler {
+func NewResumeHandler(resumeRepo domain.ResumeRepository, eventService *service.ResumeEventService) *ResumeHandler {
 	return &ResumeHandler{
-		resumeRepo: resumeRepo,
+		resumeRepo:   resumeRepo,
+		eventService: eventService,
 	}
 }
 
-// GetResumeHandler handles fetching a single resume
-func (h *ResumeHandler) GetResumeHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+// SetActivityService sets where exports are recorded in the resume's
+// activity feed. Nothing is recorded until it is set.
+func (h *ResumeHandler) SetActivityService(activityService *service.ResumeActivityService) {
+	h.activityService = activityService
+}
+
+// SetExportService makes PDF exports return a download URL of a stored
+// export instead of the document. Until it is set, exports are rendered on
+// every request.
+func (h *ResumeHandler) SetExportService(exportService *service.ResumeExportService) {
+	h.exportService = exportService
+}
+
+// SetDeletionService makes deleting a resume delete what other features
+// store about it. Until it is set, only the resume is deleted.
+func (h *ResumeHandler) SetDeletionService(deletionService *service.ResumeDeletionService) {
+	h.deletionService = deletionService
+}
+
+// SetUserRepository makes new resumes take the formatting settings they
+// leave unset from their owner's locale preference. Until it is set, unset
+// settings follow the resume's language.
+func (h *ResumeHandler) SetUserRepository(userRepo domain.UserRepository) {
+	h.userRepo = userRepo
+}
+
+// SetPermissionService checks access to other users' resumes against the
+// user roles stored in the database rather than the roles the instance
+// starts with
+func (h *ResumeHandler) SetPermissionService(permissionService *service.PermissionService) {
+	h.permissionService = permissionService
+}
+
+// recordEvent appends a mutation to the resume's event log on behalf of the
+// authenticated user. The change itself has already been stored, so a failure
+// is logged rather than returned.
+func (h *ResumeHandler) recordEvent(r *http.Request, resumeID uuid.UUID, entity string, entityID uuid.UUID, op string, payload any) {
+	actorID, err := GetUserIDFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to resolve actor for resume event")
 		return
 	}
 
-	// Get user ID from claims
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+	if _, err := h.eventService.Record(r.Context(), resumeID, actorID, entity, entityID, op, payload); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resumeID.String()).Str("entity", entity).Str("op", op).Msg("Failed to record resume event")
 	}
+}
 
-	// Get resume ID from path parameter
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+// RequireResumeOwnership returns middleware resolving the resume named by
+// the {id} path parameter and injecting it into the request context. Users
+// other than the owner need their role to grant permission, which each route
+// sets to reading or changing any resume. It must run after AuthRequired.
+func (h *ResumeHandler) RequireResumeOwnership(permission string) func(http.Handler) http.Handler {
+	action := "update"
+	if permission == domain.PermissionResumesReadAny {
+		action = "access"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := GetClaimsFromContext(r.Context())
+			if err != nil {
+				WriteError(w, r, apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized"))
+				return
+			}
+
+			userID, err := uuid.Parse(claims.UserID)
+			if err != nil {
+				WriteError(w, r, apperror.Internal(err, "Invalid user ID"))
+				return
+			}
+
+			resumeID := r.PathValue("id")
+			if resumeID == "" {
+				WriteError(w, r, apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Resume ID is required"))
+				return
+			}
+
+			resumeUUID, err := uuid.Parse(resumeID)
+			if err != nil {
+				WriteError(w, r, apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid resume ID"))
+				return
+			}
+
+			resume, err := h.resumeRepo.GetResumeByID(r.Context(), resumeUUID)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					WriteError(w, r, apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Resume not found"))
+					return
+				}
+				WriteError(w, r, apperror.Internal(err, "Failed to get resume"))
+				return
+			}
+
+			if resume.UserID != userID {
+				allowed, err := hasPermission(r.Context(), h.permissionService, claims.Role, permission)
+				if err != nil {
+					WriteError(w, r, apperror.Internal(err, "Failed to check permissions"))
+					return
+				}
+				if !allowed {
+					WriteError(w, r, apperror.New(http.StatusForbidden, apperror.CodeForbidden, "You don't have permission to "+action+" this resume"))
+					return
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), resumeContextKey, resume)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
 	}
+}
 
-	// Parse resume ID
-	resumeUUID, err := uuid.Parse(resumeID)
+// GetResumeHandler handles fetching a single resume
+func (h *ResumeHandler) GetResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	// Get resume from repository
-	resume, err := h.resumeRepo.GetCompleteResume(resumeUUID)
+	// Load the resume together with all of its sections
+	complete, err := h.resumeRepo.GetCompleteResume(r.Context(), resume.ID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Resume not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	// Check if the resume belongs to the user
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	RespondWithJSON(w, http.StatusOK, resume)
+	RespondWithJSON(w, http.StatusOK, complete)
+	return nil
 }
 
 // CreateResumeHandler handles creating a new resume
-func (h *ResumeHandler) CreateResumeHandler(w http.ResponseWriter, r *http.Request) {
+func (h *ResumeHandler) CreateResumeHandler(w http.ResponseWriter, r *http.Request) error {
 	claims, err := GetClaimsFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
 	}
 
 	// Get user ID from claims
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	// Metadata is optional, so an empty body creates an untitled resume
+	var metadata domain.ResumeMetadata
+	if err := decodeJSON(w, r, &metadata); err != nil && !errors.Is(err, io.EOF) {
+		return decodeError(err)
+	}
+
+	if err := metadata.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
+	metadata.BeforeSave()
+	// Translations are only created from their original, where ownership is checked
+	metadata.TranslationOf = nil
+	h.applyLocaleSettings(r, userID, &metadata.Settings)
+
 	// Create a new resume
-	resume, err := h.resumeRepo.CreateResume(userID)
+	resume, err := h.resumeRepo.CreateResume(r.Context(), userID, &metadata)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to create resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to create resume")
 	}
 
+	h.recordEvent(r, resume.ID, domain.EventEntityResume, uuid.Nil, domain.EventOpCreate, resume)
+
 	RespondWithJSON(w, http.StatusCreated, resume)
+	return nil
 }
 
-// DeleteResumeHandler handles deleting a resume
-func (h *ResumeHandler) DeleteResumeHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+// applyLocaleSettings fills in the settings a new resume leaves unset for
+// its owner's locale, if they have chosen one. The resume is created without
+// them if the owner can't be looked up.
+func (h *ResumeHandler) applyLocaleSettings(r *http.Request, userID uuid.UUID, settings *domain.ResumeSettings) {
+	if h.userRepo == nil {
 		return
 	}
-
-	// Get user ID from claims
-	userID, err := uuid.Parse(claims.UserID)
+	user, err := h.userRepo.GetUserByID(r.Context(), userID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		log.Ctx(r.Context()).Warn().Err(err).Str("user_id", userID.String()).Msg("Failed to get user locale for resume settings")
 		return
 	}
+	if user.Locale != "" {
+		*settings = settings.Resolve(user.Locale)
+	}
+}
 
-	// Get resume ID from path parameter
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+// ImportTextRequest holds a resume pasted as plain text
+type ImportTextRequest struct {
+	Text string `json:"text" validate:"required"`
+}
+
+// ImportTextHandler proposes resume sections for a resume pasted as plain
+// text. Nothing is stored: the client reviews the proposal, guided by the
+// confidence of each entry, and saves the entries it keeps.
+func (h *ResumeHandler) ImportTextHandler(w http.ResponseWriter, r *http.Request) error {
+	var req ImportTextRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
 	}
+	if strings.TrimSpace(req.Text) == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Text is required")
+	}
+
+	RespondWithJSON(w, http.StatusOK, parser.ParseText(req.Text))
+	return nil
+}
 
-	// Parse resume ID
-	resumeUUID, err := uuid.Parse(resumeID)
+// UpdateResumeHandler handles replacing a resume's title, target job title,
+// tags, language and settings
+func (h *ResumeHandler) UpdateResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	// Get resume to check ownership
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+	var metadata domain.ResumeMetadata
+	if err := decodeJSON(w, r, &metadata); err != nil {
+		return decodeError(err)
 	}
 
-	// Check if the resume belongs to the user
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to delete this resume", "FORBIDDEN")
-		return
+	if err := metadata.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
-	// Delete the resume
-	if err := h.resumeRepo.DeleteResume(resumeUUID); err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to delete resume", "INTERNAL_SERVER_ERROR")
-		return
+	metadata.BeforeSave()
+	// The original of a translation is fixed when the translation is created
+	metadata.TranslationOf = resume.TranslationOf
+
+	if err := h.resumeRepo.UpdateResumeMetadata(r.Context(), resume.ID, &metadata); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Resume not found")
+		}
+		return apperror.Internal(err, "Failed to update resume")
 	}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Resume deleted successfully",
-	})
+	resume.ResumeMetadata = metadata
+	h.recordEvent(r, resume.ID, domain.EventEntityResume, uuid.Nil, domain.EventOpUpdate, resume)
+
+	RespondWithJSON(w, http.StatusOK, resume)
+	return nil
 }
 
-// GetResumeListHandler handles fetching all resumes for a user
-func (h *ResumeHandler) GetResumeListHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+// DeleteResumeHandler handles deleting a resume
+func (h *ResumeHandler) DeleteResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	// Get user ID from claims
-	userID, err := uuid.Parse(claims.UserID)
+	// Delete the resume
+	if h.deletionService != nil {
+		err = h.deletionService.DeleteResume(r.Context(), resume.ID)
+	} else {
+		err = h.resumeRepo.DeleteResume(r.Context(), resume.ID)
+	}
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to delete resume")
 	}
 
-	// Get resumes from repository
-	resumes, err := h.resumeRepo.GetResumesByUserID(userID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resumes", "INTERNAL_SERVER_ERROR")
-		return
+	h.recordEvent(r, resume.ID, domain.EventEntityResume, uuid.Nil, domain.EventOpDelete, nil)
+	if h.exportService != nil {
+		h.exportService.DeleteExports(r.Context(), resume.ID)
 	}
 
-	RespondWithJSON(w, http.StatusOK, resumes)
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Resume deleted successfully"})
+	return nil
 }
 
-// SavePersonalInfoHandler stores personal information
-func (h *ResumeHandler) SavePersonalInfoHandler(w http.ResponseWriter, r *http.Request) {
+// GetResumeListHandler handles listing a user's resumes a page at a time
+func (h *ResumeHandler) GetResumeListHandler(w http.ResponseWriter, r *http.Request) error {
 	claims, err := GetClaimsFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
 	}
 
-	// Obtain UserID through claims
+	// Get user ID from claims
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	// Get id from path
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Invalid user ID")
 	}
 
-	// transform resumeID string to uuid
-	resumeUUID, err := uuid.Parse(resumeID)
+	page, err := parsePageRequest(r, domain.SortByTitle)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid query: "+err.Error())
 	}
+	page.Options.Tag = r.URL.Query().Get("tag")
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	// Get resumes from repository
+	resumes, total, err := h.resumeRepo.ListResumesByUserID(r.Context(), userID, page.Options)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get resumes")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
+	RespondWithPage(w, page, resumes, total)
+	return nil
+}
+
+// SavePersonalInfoHandler stores personal information
+func (h *ResumeHandler) SavePersonalInfoHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
 	var personalInfo domain.PersonalInfo
-	if err := json.NewDecoder(r.Body).Decode(&personalInfo); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
+	if err := decodeJSON(w, r, &personalInfo); err != nil {
+		return decodeError(err)
 	}
 
 	if err := personalInfo.Validate(); err != nil {
-		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
-		return
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
 	personalInfo.BeforeSave()
 
-	if err := h.resumeRepo.SavePersonalInfo(resumeUUID, &personalInfo); err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to save personal info", "INTERNAL_SERVER_ERROR")
-		return
+	if err := h.resumeRepo.SavePersonalInfo(r.Context(), resume.ID, &personalInfo); err != nil {
+		return apperror.Internal(err, "Failed to save personal info")
 	}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Personal info saved successfully",
-	})
+	h.recordEvent(r, resume.ID, domain.EventEntityPersonalInfo, uuid.Nil, domain.EventOpUpdate, &personalInfo)
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Personal info saved successfully"})
+	return nil
 }
 
-func (h *ResumeHandler) AddEducationHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+func (h *ResumeHandler) AddEducationHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+	var education domain.Education
+	if err := decodeJSON(w, r, &education); err != nil {
+		return decodeError(err)
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	if err := education.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
-	}
+	education.BeforeSave()
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	educationID, err := h.resumeRepo.AddEducation(r.Context(), resume.ID, &education)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return err
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to add education")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
-	}
+	h.recordEvent(r, resume.ID, domain.EventEntityEducation, educationID, domain.EventOpCreate, &education)
 
-	var education domain.Education
-	if err := json.NewDecoder(r.Body).Decode(&education); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
-	}
+	RespondWithJSON(w, http.StatusCreated, CreatedResponse{ID: educationID, Message: "Education added successfully"})
+	return nil
+}
 
-	if err := education.Validate(); err != nil {
-		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
-		return
+func (h *ResumeHandler) GetEducationHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	education.BeforeSave()
-
-	educationID, err := h.resumeRepo.AddEducation(resumeUUID, &education)
+	education, err := h.resumeRepo.GetEducationByResume(r.Context(), resume.ID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to add education", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get education entries")
 	}
 
-	RespondWithJSON(w, http.StatusCreated, map[string]any{
-		"id":      educationID,
-		"message": "Education added successfully",
-	})
+	RespondWithJSON(w, http.StatusOK, education)
+	return nil
 }
 
-func (h *ResumeHandler) GetEducationHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
-	}
-
-	userID, err := uuid.Parse(claims.UserID)
+func (h *ResumeHandler) DeleteEducationHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	educationID := r.PathValue("educationId")
+	if educationID == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Education ID is required")
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
+	educationUUID, err := uuid.Parse(educationID)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid education ID")
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
+	if err := h.resumeRepo.DeleteEducation(r.Context(), resume.ID, educationUUID); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Education entry not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to delete education entry")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
-		return
-	}
+	h.recordEvent(r, resume.ID, domain.EventEntityEducation, educationUUID, domain.EventOpDelete, nil)
 
-	education, err := h.resumeRepo.GetEducationByResume(resumeUUID)
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Education entry deleted successfully"})
+	return nil
+}
+
+func (h *ResumeHandler) AddExperienceHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get education entries", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	RespondWithJSON(w, http.StatusOK, education)
-}
+	var experience domain.Experience
+	if err := decodeJSON(w, r, &experience); err != nil {
+		return decodeError(err)
+	}
 
-func (h *ResumeHandler) DeleteEducationHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+	if err := experience.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
+	experience.BeforeSave()
+
+	experienceID, err := h.resumeRepo.AddExperience(r.Context(), resume.ID, &experience)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return err
+		}
+		return apperror.Internal(err, "Failed to add experience")
 	}
 
-	resumeID := r.PathValue("id")
-	educationID := r.PathValue("educationId")
+	h.recordEvent(r, resume.ID, domain.EventEntityExperience, experienceID, domain.EventOpCreate, &experience)
 
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
-	}
+	RespondWithJSON(w, http.StatusCreated, CreatedResponse{ID: experienceID, Message: "Experience added successfully"})
+	return nil
+}
 
-	if educationID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Education ID is required", "INVALID_REQUEST")
-		return
+func (h *ResumeHandler) GetExperienceHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
+	experience, err := h.resumeRepo.GetExperienceByResume(r.Context(), resume.ID)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get experience entries")
 	}
 
-	educationUUID, err := uuid.Parse(educationID)
+	RespondWithJSON(w, http.StatusOK, experience)
+	return nil
+}
+
+func (h *ResumeHandler) DeleteExperienceHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid education ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+	experienceID := r.PathValue("experienceId")
+	if experienceID == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Experience ID is required")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
+	experienceUUID, err := uuid.Parse(experienceID)
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid experience ID")
 	}
 
-	if err := h.resumeRepo.DeleteEducation(educationUUID); err != nil {
+	if err := h.resumeRepo.DeleteExperience(r.Context(), resume.ID, experienceUUID); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Education entry not found", "NOT_FOUND")
-			return
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Experience entry not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to delete education entry", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to delete experience entry")
 	}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Education entry deleted successfully",
-	})
+	h.recordEvent(r, resume.ID, domain.EventEntityExperience, experienceUUID, domain.EventOpDelete, nil)
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Experience entry deleted successfully"})
+	return nil
 }
 
-func (h *ResumeHandler) AddExperienceHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+func (h *ResumeHandler) AddSkillHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+	var skill domain.Skill
+	if err := decodeJSON(w, r, &skill); err != nil {
+		return decodeError(err)
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	if err := skill.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
-	}
+	skill.BeforeSave()
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	skillID, err := h.resumeRepo.AddSkill(r.Context(), resume.ID, &skill)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return err
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to add skill")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
-	}
+	h.recordEvent(r, resume.ID, domain.EventEntitySkill, skillID, domain.EventOpCreate, &skill)
 
-	var experience domain.Experience
-	if err := json.NewDecoder(r.Body).Decode(&experience); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
-	}
+	RespondWithJSON(w, http.StatusCreated, CreatedResponse{ID: skillID, Message: "Skill added successfully"})
+	return nil
+}
 
-	if err := experience.Validate(); err != nil {
-		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
-		return
-	}
+// SkillList is a list of skills that can also be written as a comma-separated
+// string of skill names
+type SkillList []domain.Skill
 
-	experience.BeforeSave()
+// UnmarshalJSON accepts either a list of skills or a string of names
+func (l *SkillList) UnmarshalJSON(data []byte) error {
+	var names string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return json.Unmarshal(data, (*[]domain.Skill)(l))
+	}
 
-	experienceID, err := h.resumeRepo.AddExperience(resumeUUID, &experience)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to add experience", "INTERNAL_SERVER_ERROR")
-		return
+	*l = SkillList{}
+	for name := range strings.SplitSeq(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			*l = append(*l, domain.Skill{Name: name})
+		}
 	}
+	return nil
+}
 
-	RespondWithJSON(w, http.StatusCreated, map[string]any{
-		"id":      experienceID,
-		"message": "Experience added successfully",
-	})
+// BulkSkillsRequest adds several skills at once
+type BulkSkillsRequest struct {
+	Skills SkillList `json:"skills"`
 }
 
-func (h *ResumeHandler) GetExperienceHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+// AddSkillsHandler adds several skills in one transaction. Every entry is
+// validated first; if any is invalid none are added and the response reports
+// the problem with each entry.
+func (h *ResumeHandler) AddSkillsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+	var req BulkSkillsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+	if len(req.Skills) == 0 {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "At least one skill is required")
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	skills := make([]*domain.Skill, len(req.Skills))
+	results := make([]BulkSkillResult, len(req.Skills))
+	valid := true
+	for i := range req.Skills {
+		skills[i] = &req.Skills[i]
+		results[i] = BulkSkillResult{Index: i, Name: skills[i].Name}
+		if err := skills[i].Validate(); err != nil {
+			results[i].Error = err.Error()
+			valid = false
+		}
+	}
+	if !valid {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "No skills were added because some are invalid").WithDetails(map[string]any{
+			"results": results,
+		})
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+	for _, skill := range skills {
+		skill.BeforeSave()
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	ids, err := h.resumeRepo.AddSkills(r.Context(), resume.ID, skills)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return err
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to add skills")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
-		return
-	}
-
-	experience, err := h.resumeRepo.GetExperienceByResume(resumeUUID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get experience entries", "INTERNAL_SERVER_ERROR")
-		return
+	for i, id := range ids {
+		results[i].ID = id
+		h.recordEvent(r, resume.ID, domain.EventEntitySkill, id, domain.EventOpCreate, skills[i])
 	}
 
-	RespondWithJSON(w, http.StatusOK, experience)
+	RespondWithJSON(w, http.StatusCreated, BulkSkillsResponse{
+		Message: "Skills added successfully",
+		Results: results,
+	})
+	return nil
 }
 
-func (h *ResumeHandler) DeleteExperienceHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+func (h *ResumeHandler) GetSkillsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
+	skills, err := h.resumeRepo.GetSkillsByResume(r.Context(), resume.ID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get skills")
 	}
 
-	resumeID := r.PathValue("id")
-	experienceID := r.PathValue("experienceId")
+	RespondWithJSON(w, http.StatusOK, skills)
+	return nil
+}
 
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+// GetGroupedSkillsHandler lists a resume's skills grouped by category. Proficiency
+// levels are named in the resume's language unless the labels query parameter
+// gives five comma-separated names for levels 1 to 5.
+func (h *ResumeHandler) GetGroupedSkillsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	if experienceID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Experience ID is required", "INVALID_REQUEST")
-		return
+	labels := locale.For(resume.Language).Proficiency
+	if param := r.URL.Query().Get("labels"); param != "" {
+		names := strings.Split(param, ",")
+		if len(names) != len(labels) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Labels must name the 5 proficiency levels, separated by commas")
+		}
+		for i, name := range names {
+			labels[i] = strings.TrimSpace(name)
+		}
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
+	skills, err := h.resumeRepo.GetSkillsByResume(r.Context(), resume.ID)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get skills")
 	}
 
-	experienceUUID, err := uuid.Parse(experienceID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid experience ID", "INVALID_REQUEST")
-		return
+	response := GroupedSkillsResponse{Groups: []SkillGroupResponse{}}
+	for _, group := range domain.GroupSkills(skills) {
+		entries := make([]GroupedSkill, len(group.Skills))
+		for i, s := range group.Skills {
+			entries[i] = GroupedSkill{Name: s.Name, Proficiency: s.Proficiency}
+			if s.Proficiency >= 1 && s.Proficiency <= len(labels) {
+				entries[i].ProficiencyLabel = labels[s.Proficiency-1]
+			}
+		}
+		response.Groups = append(response.Groups, SkillGroupResponse{Category: group.Category, Skills: entries})
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	RespondWithJSON(w, http.StatusOK, response)
+	return nil
+}
+
+func (h *ResumeHandler) DeleteSkillHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
-	}
-
-	if err := h.resumeRepo.DeleteExperience(experienceUUID); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Experience entry not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to delete experience entry", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Experience entry deleted successfully",
-	})
-}
-
-func (h *ResumeHandler) AddSkillHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+	skillID := r.PathValue("skillId")
+	if skillID == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Skill ID is required")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
+	skillUUID, err := uuid.Parse(skillID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
-	}
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid skill ID")
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
-	}
-
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
+	if err := h.resumeRepo.DeleteSkill(r.Context(), resume.ID, skillUUID); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Skill not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
+		return apperror.Internal(err, "Failed to delete skill")
 	}
 
-	var skill domain.Skill
-	if err := json.NewDecoder(r.Body).Decode(&skill); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
-	}
-
-	if err := skill.Validate(); err != nil {
-		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
-		return
-	}
-
-	skill.BeforeSave()
+	h.recordEvent(r, resume.ID, domain.EventEntitySkill, skillUUID, domain.EventOpDelete, nil)
 
-	skillID, err := h.resumeRepo.AddSkill(resumeUUID, &skill)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to add skill", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	RespondWithJSON(w, http.StatusCreated, map[string]any{
-		"id":      skillID,
-		"message": "Skill added successfully",
-	})
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Skill deleted successfully"})
+	return nil
 }
 
-func (h *ResumeHandler) GetSkillsHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+func (h *ResumeHandler) AddProjectHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+	var project domain.Project
+	if err := decodeJSON(w, r, &project); err != nil {
+		return decodeError(err)
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	if err := project.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
-	}
+	project.BeforeSave()
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	projectID, err := h.resumeRepo.AddProject(r.Context(), resume.ID, &project)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return err
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to add project")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
-		return
-	}
+	h.recordEvent(r, resume.ID, domain.EventEntityProject, projectID, domain.EventOpCreate, &project)
 
-	skills, err := h.resumeRepo.GetSkillsByResume(resumeUUID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get skills", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	RespondWithJSON(w, http.StatusOK, skills)
+	RespondWithJSON(w, http.StatusCreated, CreatedResponse{ID: projectID, Message: "Project added successfully"})
+	return nil
 }
 
-func (h *ResumeHandler) DeleteSkillHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+func (h *ResumeHandler) GetProjectsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
+	projects, err := h.resumeRepo.GetProjectsByResume(r.Context(), resume.ID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get projects")
 	}
 
-	resumeID := r.PathValue("id")
-	skillID := r.PathValue("skillId")
-
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
-	}
-
-	if skillID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Skill ID is required", "INVALID_REQUEST")
-		return
-	}
+	RespondWithJSON(w, http.StatusOK, projects)
+	return nil
+}
 
-	resumeUUID, err := uuid.Parse(resumeID)
+func (h *ResumeHandler) DeleteProjectHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	skillUUID, err := uuid.Parse(skillID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid skill ID", "INVALID_REQUEST")
-
-		return
-
+	projectID := r.PathValue("projectId")
+	if projectID == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Project ID is required")
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	projectUUID, err := uuid.Parse(projectID)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid project ID")
 	}
 
-	if err := h.resumeRepo.DeleteSkill(skillUUID); err != nil {
+	if err := h.resumeRepo.DeleteProject(r.Context(), resume.ID, projectUUID); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Skill not found", "NOT_FOUND")
-			return
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Project not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to delete skill", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to delete project")
 	}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Skill deleted successfully",
-	})
+	h.recordEvent(r, resume.ID, domain.EventEntityProject, projectUUID, domain.EventOpDelete, nil)
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Project deleted successfully"})
+	return nil
 }
 
-func (h *ResumeHandler) AddProjectHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+func (h *ResumeHandler) AddCertificationHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+	var certification domain.Certification
+	if err := decodeJSON(w, r, &certification); err != nil {
+		return decodeError(err)
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	if err := certification.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
-	}
+	certification.BeforeSave()
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	certificationID, err := h.resumeRepo.AddCertification(r.Context(), resume.ID, &certification)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return err
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to add certification")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
-	}
+	h.recordEvent(r, resume.ID, domain.EventEntityCertification, certificationID, domain.EventOpCreate, &certification)
 
-	var project domain.Project
-	if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
-	}
+	RespondWithJSON(w, http.StatusCreated, CreatedResponse{ID: certificationID, Message: "Certification added successfully"})
+	return nil
+}
 
-	if err := project.Validate(); err != nil {
-		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
-		return
+func (h *ResumeHandler) GetCertificationsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	project.BeforeSave()
-
-	projectID, err := h.resumeRepo.AddProject(resumeUUID, &project)
+	certifications, err := h.resumeRepo.GetCertificationsByResume(r.Context(), resume.ID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to add project", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get certifications")
 	}
 
-	RespondWithJSON(w, http.StatusCreated, map[string]any{
-		"id":      projectID,
-		"message": "Project added successfully",
-	})
+	RespondWithJSON(w, http.StatusOK, certifications)
+	return nil
 }
 
-func (h *ResumeHandler) GetProjectsHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
-	}
-
-	userID, err := uuid.Parse(claims.UserID)
+func (h *ResumeHandler) DeleteCertificationHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	certificationID := r.PathValue("certificationId")
+	if certificationID == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Certification ID is required")
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
+	certificationUUID, err := uuid.Parse(certificationID)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid certification ID")
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
+	if err := h.resumeRepo.DeleteCertification(r.Context(), resume.ID, certificationUUID); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Certification not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to delete certification")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
-		return
-	}
+	h.recordEvent(r, resume.ID, domain.EventEntityCertification, certificationUUID, domain.EventOpDelete, nil)
 
-	projects, err := h.resumeRepo.GetProjectsByResume(resumeUUID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get projects", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	RespondWithJSON(w, http.StatusOK, projects)
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Certification deleted successfully"})
+	return nil
 }
 
-func (h *ResumeHandler) DeleteProjectHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
+func (h *ResumeHandler) GetPersonalInfoHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
-	}
-
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	resumeID := r.PathValue("id")
-	projectID := r.PathValue("projectId")
-
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
-	}
-
-	if projectID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Project ID is required", "INVALID_REQUEST")
-		return
-	}
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	projectUUID, err := uuid.Parse(projectID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid project ID", "INVALID_REQUEST")
-		return
-	}
-
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	personalInfo, err := h.resumeRepo.GetPersonalInfo(r.Context(), resume.ID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
-	}
-
-	if err := h.resumeRepo.DeleteProject(projectUUID); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Project not found", "NOT_FOUND")
-			return
+			RespondWithJSON(w, http.StatusOK, nil)
+			return nil
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to delete project", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get personal info")
 	}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Project deleted successfully",
-	})
+	RespondWithJSON(w, http.StatusOK, personalInfo)
+	return nil
 }
 
-func (h *ResumeHandler) AddCertificationHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
-	}
-
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
-	}
-
-	resumeUUID, err := uuid.Parse(resumeID)
+// GetResumeEventsHandler handles listing a resume's change events newer than a version
+func (h *ResumeHandler) GetResumeEventsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+	// Clients pass the last version they have seen to receive only newer events
+	var afterVersion int64
+	if after := r.URL.Query().Get("after"); after != "" {
+		afterVersion, err = strconv.ParseInt(after, 10, 64)
+		if err != nil || afterVersion < 0 {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid after version")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
-	}
-
-	var certification domain.Certification
-	if err := json.NewDecoder(r.Body).Decode(&certification); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
-		return
 	}
 
-	if err := certification.Validate(); err != nil {
-		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
-		return
+	var limit int
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid limit")
+		}
 	}
 
-	certification.BeforeSave()
-
-	certificationID, err := h.resumeRepo.AddCertification(resumeUUID, &certification)
+	events, err := h.eventService.EventsAfter(r.Context(), resume.ID, afterVersion, limit)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to add certification", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get resume events")
 	}
 
-	RespondWithJSON(w, http.StatusCreated, map[string]any{
-		"id":      certificationID,
-		"message": "Certification added successfully",
-	})
+	RespondWithJSON(w, http.StatusOK, ResumeEventsResponse{Events: events})
+	return nil
 }
 
-func (h *ResumeHandler) GetCertificationsHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
-	}
-
-	userID, err := uuid.Parse(claims.UserID)
+// GetResumeVersionHandler handles rebuilding a resume as it was at a given version
+func (h *ResumeHandler) GetResumeVersionHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get resume")
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+	version, err := strconv.ParseInt(r.PathValue("version"), 10, 64)
+	if err != nil || version < 1 {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid version")
 	}
 
-	resumeUUID, err := uuid.Parse(resumeID)
+	snapshot, err := h.eventService.StateAt(r.Context(), resume.ID, version)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
-	}
-
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+		if errors.Is(err, service.ErrVersionNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Version not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
-		return
+		return apperror.Internal(err, "Failed to rebuild resume version")
 	}
 
-	certifications, err := h.resumeRepo.GetCertificationsByResume(resumeUUID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get certifications", "INTERNAL_SERVER_ERROR")
-		return
+	// Resumes created before event recording have no create event to project from
+	if snapshot == nil {
+		return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Version not found")
 	}
 
-	RespondWithJSON(w, http.StatusOK, certifications)
+	RespondWithJSON(w, http.StatusOK, ResumeVersionResponse{
+		Version: version,
+		Resume:  snapshot,
+	})
+	return nil
 }
 
-func (h *ResumeHandler) DeleteCertificationHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
-	}
-
-	userID, err := uuid.Parse(claims.UserID)
+// ExportMarkdownHandler renders a resume as Markdown, with headings and dates in the resume's language
+func (h *ResumeHandler) ExportMarkdownHandler(w http.ResponseWriter, r *http.Request) error {
+	complete, err := h.completeResume(r)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	resumeID := r.PathValue("id")
-	certificationID := r.PathValue("certificationId")
-
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
+		return err
 	}
 
-	if certificationID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Certification ID is required", "INVALID_REQUEST")
-		return
-	}
+	writeDocument(w, r, render.Markdown(complete), "text/markdown; charset=utf-8", complete.Language, "resume-"+complete.ID.String()+".md")
+	recordActivity(r, h.activityService, complete.ID, domain.ActivityExport, map[string]string{"format": "markdown"})
+	return nil
+}
 
-	resumeUUID, err := uuid.Parse(resumeID)
-	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+// ExportPDFHandler renders a resume as PDF, with headings and dates in the
+// resume's language. With an export service it responds with a signed URL
+// of the stored PDF, rendered only when the resume has changed. With the
+// one_page query parameter the resume is condensed to one page, reported
+// in the response or, without an export service, in X-Condensed headers.
+func (h *ResumeHandler) ExportPDFHandler(w http.ResponseWriter, r *http.Request) error {
+	var onePage bool
+	if param := r.URL.Query().Get("one_page"); param != "" {
+		var err error
+		if onePage, err = strconv.ParseBool(param); err != nil {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "one_page must be true or false")
+		}
 	}
 
-	certificationUUID, err := uuid.Parse(certificationID)
+	complete, err := h.completeResume(r)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid certification ID", "INVALID_REQUEST")
-		return
+		return err
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+	if h.exportService != nil {
+		var exportURL *service.ExportURL
+		if onePage {
+			exportURL, err = h.exportService.OnePagePDFURL(r.Context(), complete)
+		} else {
+			exportURL, err = h.exportService.PDFURL(r.Context(), complete)
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
-	}
+		if err != nil {
+			return apperror.Internal(err, "Failed to export resume")
+		}
+		w.Header().Set("Cache-Control", "private, no-store")
+		RespondWithJSON(w, http.StatusOK, exportURL)
+	} else if onePage {
+		condensed := render.CondensePDF(complete)
+		setCondensedHeaders(w, condensed)
+		writeDocument(w, r, condensed.PDF(complete), "application/pdf", complete.Language, "resume-"+complete.ID.String()+".pdf")
+	} else {
+		writeDocument(w, r, render.PDF(complete), "application/pdf", complete.Language, "resume-"+complete.ID.String()+".pdf")
+	}
+
+	details := map[string]string{"format": "pdf"}
+	if onePage {
+		details["one_page"] = "true"
+	}
+	recordActivity(r, h.activityService, complete.ID, domain.ActivityExport, details)
+	return nil
+}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
-		return
+// setCondensedHeaders reports how a streamed one-page export was condensed:
+// X-Condensed-Fits says whether it fits on one page and X-Condensed-Omitted
+// lists the omitted entries as section:position
+func setCondensedHeaders(w http.ResponseWriter, condensed *render.Condensed) {
+	omitted := make([]string, len(condensed.Omitted))
+	for i, entry := range condensed.Omitted {
+		omitted[i] = entry.Section + ":" + strconv.Itoa(entry.Entry)
 	}
-
-	if err := h.resumeRepo.DeleteCertification(certificationUUID); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Certification not found", "NOT_FOUND")
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to delete certification", "INTERNAL_SERVER_ERROR")
-		return
+	w.Header().Set("X-Condensed-Fits", strconv.FormatBool(condensed.Fits))
+	if len(omitted) > 0 {
+		w.Header().Set("X-Condensed-Omitted", strings.Join(omitted, ", "))
 	}
+}
 
-	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Certification deleted successfully",
-	})
+// MatchRequest holds the job description a resume is matched against
+type MatchRequest struct {
+	JobDescription string `json:"job_description" validate:"required"`
 }
 
-func (h *ResumeHandler) GetPersonalInfoHandler(w http.ResponseWriter, r *http.Request) {
-	claims, err := GetClaimsFromContext(r.Context())
-	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+// MatchHandler compares a resume with a job description, reporting the
+// job description's keywords the resume mentions and those it misses
+func (h *ResumeHandler) MatchHandler(w http.ResponseWriter, r *http.Request) error {
+	var req MatchRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+	if strings.TrimSpace(req.JobDescription) == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Job description is required")
 	}
 
-	userID, err := uuid.Parse(claims.UserID)
+	complete, err := h.completeResume(r)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return err
 	}
 
-	resumeID := r.PathValue("id")
-	if resumeID == "" {
-		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
-		return
-	}
+	RespondWithJSON(w, http.StatusOK, analysis.MatchJobDescription(complete, req.JobDescription))
+	return nil
+}
 
-	resumeUUID, err := uuid.Parse(resumeID)
+// completeResume loads every section of the resume in the request context
+func (h *ResumeHandler) completeResume(r *http.Request) (*domain.Resume, error) {
+	return loadCompleteResume(r, h.resumeRepo)
+}
+
+// loadCompleteResume loads every section of the resume in the request
+// context from resumeRepo
+func loadCompleteResume(r *http.Request, resumeRepo domain.ResumeRepository) (*domain.Resume, error) {
+	resume, err := GetResumeFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
-		return
+		return nil, apperror.Internal(err, "Failed to get resume")
 	}
 
-	resume, err := h.resumeRepo.GetResumeByID(resumeUUID)
+	complete, err := resumeRepo.GetCompleteResume(r.Context(), resume.ID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
-			return
+			return nil, apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Resume not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
-		return
+		return nil, apperror.Internal(err, "Failed to get resume")
 	}
 
-	if resume.UserID != userID && claims.Role != "admin" {
-		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
-		return
-	}
+	return complete, nil
+}
 
-	personalInfo, err := h.resumeRepo.GetPersonalInfo(resumeUUID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithJSON(w, http.StatusOK, nil)
-			return
-		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get personal info", "INTERNAL_SERVER_ERROR")
-		return
+// writeDocument writes a rendered resume as a download
+func writeDocument(w http.ResponseWriter, r *http.Request, document []byte, contentType, language, filename string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Language", language)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(document)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(document); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("filename", filename).Msg("Failed to write resume export")
 	}
-
-	RespondWithJSON(w, http.StatusOK, personalInfo)
 }
@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/analysis"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubResumeRepository serves resumes from memory; unused methods panic
+type stubResumeRepository struct {
+	domain.ResumeRepository
+	resumes  map[uuid.UUID]*domain.Resume
+	listOpts domain.ListOptions
+}
+
+func (s *stubResumeRepository) ListResumesByUserID(ctx context.Context, userID uuid.UUID, opts domain.ListOptions) ([]*domain.Resume, int64, error) {
+	s.listOpts = opts
+	var resumes []*domain.Resume
+	for _, resume := range s.resumes {
+		if resume.UserID == userID && (opts.Tag == "" || slices.Contains(resume.Tags, opts.Tag)) {
+			resumes = append(resumes, resume)
+		}
+	}
+	return resumes, int64(len(resumes)), nil
+}
+
+func (s *stubResumeRepository) GetResumeByID(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	resume, ok := s.resumes[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return resume, nil
+}
+
+func TestRequireResumeOwnership(t *testing.T) {
+	ownerID := uuid.New()
+	resume := &domain.Resume{ID: uuid.New(), UserID: ownerID}
+	handler := NewResumeHandler(&stubResumeRepository{resumes: map[uuid.UUID]*domain.Resume{resume.ID: resume}}, nil)
+
+	var resolved *domain.Resume
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		resolved, err = GetResumeFromContext(r.Context())
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	read, write := domain.PermissionResumesReadAny, domain.PermissionResumesWriteAny
+
+	testCases := []struct {
+		name           string
+		method         string
+		permission     string
+		resumeID       string
+		userID         uuid.UUID
+		role           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"Owner", "GET", read, resume.ID.String(), ownerID, domain.RoleUser, http.StatusNoContent, ""},
+		{"Invalid ID", "GET", read, "not-a-uuid", ownerID, domain.RoleUser, http.StatusBadRequest, "Invalid resume ID"},
+		{"Unknown resume", "GET", read, uuid.NewString(), ownerID, domain.RoleUser, http.StatusNotFound, "Resume not found"},
+		{"Other user reading", "GET", read, resume.ID.String(), uuid.New(), domain.RoleUser, http.StatusForbidden, "permission to access"},
+		{"Other user writing", "POST", write, resume.ID.String(), uuid.New(), domain.RoleUser, http.StatusForbidden, "permission to update"},
+		{"Support reading", "GET", read, resume.ID.String(), uuid.New(), domain.RoleSupport, http.StatusNoContent, ""},
+		// Routes such as matching post a request but only read the resume
+		{"Support reading by POST", "POST", read, resume.ID.String(), uuid.New(), domain.RoleSupport, http.StatusNoContent, ""},
+		{"Support writing", "POST", write, resume.ID.String(), uuid.New(), domain.RoleSupport, http.StatusForbidden, "permission to update"},
+		{"Admin writing", "POST", write, resume.ID.String(), uuid.New(), domain.RoleAdmin, http.StatusNoContent, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved = nil
+			req := httptest.NewRequest(tc.method, "/api/v1/resumes/"+tc.resumeID, nil)
+			req.SetPathValue("id", tc.resumeID)
+			req = withClaims(req, tc.userID)
+			claims, err := GetClaimsFromContext(req.Context())
+			require.NoError(t, err)
+			claims.Role = tc.role
+			rr := httptest.NewRecorder()
+			handler.RequireResumeOwnership(tc.permission)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tc.expectedBody)
+			if tc.expectedStatus == http.StatusNoContent {
+				assert.Equal(t, resume, resolved)
+			} else {
+				assert.Nil(t, resolved)
+			}
+		})
+	}
+}
+
+func TestGetResumeListHandler(t *testing.T) {
+	userID := uuid.New()
+	tagged := &domain.Resume{ID: uuid.New(), UserID: userID, ResumeMetadata: domain.ResumeMetadata{Title: "Backend", Tags: []string{"go"}}}
+	untagged := &domain.Resume{ID: uuid.New(), UserID: userID, ResumeMetadata: domain.ResumeMetadata{Title: "Frontend"}}
+	repo := &stubResumeRepository{resumes: map[uuid.UUID]*domain.Resume{tagged.ID: tagged, untagged.ID: untagged}}
+	handler := NewResumeHandler(repo, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resumes?tag=go&sort=title&order=asc&q=back", nil)
+	rr := httptest.NewRecorder()
+	HandlerFunc(handler.GetResumeListHandler).ServeHTTP(rr, withClaims(req, userID))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, domain.ListOptions{Limit: defaultPageLimit, SortBy: domain.SortByTitle, Search: "back", Tag: "go"}, repo.listOpts)
+
+	var body struct {
+		Data       []domain.Resume `json:"data"`
+		Pagination PaginationMeta  `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "Backend", body.Data[0].Title)
+	assert.Equal(t, []string{"go"}, body.Data[0].Tags)
+	assert.Equal(t, PaginationMeta{Page: 1, Limit: defaultPageLimit, Total: 1, TotalPages: 1}, body.Pagination)
+}
+
+// skillsRepository serves a fixed list of skills
+type skillsRepository struct {
+	domain.ResumeRepository
+	skills []*domain.Skill
+}
+
+func (s *skillsRepository) GetSkillsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Skill, error) {
+	return s.skills, nil
+}
+
+func (s *skillsRepository) AddSkills(ctx context.Context, resumeID uuid.UUID, skills []*domain.Skill) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(skills))
+	for i := range skills {
+		ids[i] = uuid.New()
+	}
+	s.skills = append(s.skills, skills...)
+	return ids, nil
+}
+
+func TestGetGroupedSkillsHandler(t *testing.T) {
+	repo := &skillsRepository{skills: []*domain.Skill{
+		{Name: "Go", Category: domain.SkillCategoryLanguage, Proficiency: 5},
+		{Name: "PostgreSQL", Category: domain.SkillCategoryDatabase},
+		{Name: "SQL", Category: domain.SkillCategoryLanguage, Proficiency: 3},
+	}}
+	handler := NewResumeHandler(repo, nil)
+	resume := &domain.Resume{ID: uuid.New(), ResumeMetadata: domain.ResumeMetadata{Language: "es"}}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/resumes/"+resume.ID.String()+"/skills/grouped"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(handler.GetGroupedSkillsHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get("")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body GroupedSkillsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, GroupedSkillsResponse{Groups: []SkillGroupResponse{
+		{Category: "language", Skills: []GroupedSkill{{Name: "Go", Proficiency: 5, ProficiencyLabel: "Experto"}, {Name: "SQL", Proficiency: 3, ProficiencyLabel: "Intermedio"}}},
+		{Category: "database", Skills: []GroupedSkill{{Name: "PostgreSQL"}}},
+	}}, body)
+
+	// Labels can be overridden
+	rr = get("?labels=1,2,3,4,5")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "5", body.Groups[0].Skills[0].ProficiencyLabel)
+
+	assert.Equal(t, http.StatusBadRequest, get("?labels=low,high").Code)
+}
+
+func TestAddSkillsHandler(t *testing.T) {
+	repo := &skillsRepository{}
+	handler := NewResumeHandler(repo, nil)
+	resume := &domain.Resume{ID: uuid.New()}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/resumes/"+resume.ID.String()+"/skills/bulk", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(handler.AddSkillsHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	// One invalid entry rejects the whole batch
+	rr := post(`{"skills": [{"name": "Go", "category": "language"}, {"name": "Rust", "proficiency": 9}]}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	var errBody struct {
+		Details struct {
+			Results []BulkSkillResult `json:"results"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errBody))
+	require.Len(t, errBody.Details.Results, 2)
+	assert.Empty(t, errBody.Details.Results[0].Error)
+	assert.Contains(t, errBody.Details.Results[1].Error, "Proficiency")
+	assert.Empty(t, repo.skills)
+
+	// Skills can be given as a comma-separated string
+	rr = post(`{"skills": "Go, SQL, ,Docker"}`)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var body BulkSkillsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	require.Len(t, body.Results, 3)
+	assert.Equal(t, "Docker", body.Results[2].Name)
+	assert.NotEqual(t, uuid.Nil, body.Results[2].ID)
+	require.Len(t, repo.skills, 3)
+	assert.Equal(t, domain.SkillCategoryOther, repo.skills[0].Category)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"skills": ""}`).Code)
+}
+
+func TestImportTextHandler(t *testing.T) {
+	handler := NewResumeHandler(nil, nil)
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/resumes/import/text", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		HandlerFunc(handler.ImportTextHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post(`{"text": "Ana Lima\nana@example.com\n\nExperience\nEngineer at Initech, 2019 - 2022\n- Shipped things"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body struct {
+		PersonalInfo struct {
+			Email      string  `json:"email"`
+			Confidence float64 `json:"confidence"`
+		} `json:"personal_info"`
+		Experience []struct {
+			Employer   string  `json:"employer"`
+			StartDate  string  `json:"start_date"`
+			Confidence float64 `json:"confidence"`
+		} `json:"experience"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "ana@example.com", body.PersonalInfo.Email)
+	require.Len(t, body.Experience, 1)
+	assert.Equal(t, "Initech", body.Experience[0].Employer)
+	assert.Equal(t, "2019-01-01", body.Experience[0].StartDate)
+	assert.Equal(t, 1.0, body.Experience[0].Confidence)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"text": "  "}`).Code)
+}
+
+func TestMatchHandler(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New(), Skills: []*domain.Skill{{Name: "Go"}, {Name: "PostgreSQL"}}}
+	handler := NewResumeHandler(&stubCompleteResumeRepository{resume: resume}, nil)
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/resumes/"+resume.ID.String()+"/match", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(handler.MatchHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post(`{"job_description": "Go and Kubernetes developer. Go and PostgreSQL."}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body analysis.Match
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, []analysis.Keyword{{Term: "go", Count: 2}, {Term: "postgresql", Count: 1}}, body.Matched)
+	assert.Equal(t, []analysis.Keyword{{Term: "kubernetes", Count: 1}, {Term: "developer", Count: 1}}, body.Missing)
+	assert.Equal(t, 60, body.Score)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"job_description": " "}`).Code)
+}
+
+func TestExportPDFHandler(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New(), ResumeMetadata: domain.ResumeMetadata{Title: "Backend", Language: "en"}}
+	handler := NewResumeHandler(&stubCompleteResumeRepository{resume: resume}, nil)
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/resumes/"+resume.ID.String()+"/export/pdf"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(handler.ExportPDFHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Without an export service the PDF is rendered in the response
+	rr := get("")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "%PDF"))
+	assert.Empty(t, rr.Header().Get("X-Condensed-Fits"))
+
+	// One-page exports report how they were condensed in headers
+	rr = get("?one_page=true")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "%PDF"))
+	assert.Equal(t, "true", rr.Header().Get("X-Condensed-Fits"))
+	assert.Empty(t, rr.Header().Get("X-Condensed-Omitted"))
+	assert.Equal(t, http.StatusBadRequest, get("?one_page=maybe").Code)
+
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	files := storage.NewMemory()
+	files.SetURLSigner(storage.NewURLSigner("/api/v1/files", []byte("secret")))
+	handler.SetExportService(service.NewResumeExportService(files, store, service.ResumeExportServiceConfig{}))
+
+	rr = get("")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "private, no-store", rr.Header().Get("Cache-Control"))
+	var body service.ExportURL
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.True(t, strings.HasPrefix(body.URL, "/api/v1/files/exports/resumes/"+resume.ID.String()+"/"))
+	assert.Contains(t, body.URL, "signature=")
+	assert.False(t, body.ExpiresAt.IsZero())
+	assert.Nil(t, body.Condensed)
+
+	// One-page exports are stored apart, reporting how they were condensed
+	rr = get("?one_page=1")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var onePage service.ExportURL
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &onePage))
+	assert.Contains(t, onePage.URL, "/resume-"+resume.ID.String()+".one-page.pdf?")
+	require.NotNil(t, onePage.Condensed)
+	assert.True(t, onePage.Condensed.Fits)
+	assert.Empty(t, onePage.Condensed.Omitted)
+}
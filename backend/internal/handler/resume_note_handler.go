@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// maxNoteRequestSize caps a note save, leaving room for the base64 encoding
+// of the largest ciphertext
+const maxNoteRequestSize = 128 << 10
+
+// noteJSONLimits let the ciphertext string take up the whole request
+var noteJSONLimits = JSONLimits{
+	MaxBytes:        maxNoteRequestSize,
+	MaxDepth:        DefaultJSONLimits.MaxDepth,
+	MaxArrayLength:  DefaultJSONLimits.MaxArrayLength,
+	MaxStringLength: maxNoteRequestSize,
+}
+
+// ResumeNoteHandler handles the private notes on resumes
+type ResumeNoteHandler struct {
+	noteService     *service.ResumeNoteService
+	activityService *service.ResumeActivityService
+}
+
+// NewResumeNoteHandler creates a new resume note handler
+func NewResumeNoteHandler(noteService *service.ResumeNoteService) *ResumeNoteHandler {
+	return &ResumeNoteHandler{
+		noteService: noteService,
+	}
+}
+
+// SetActivityService sets where note saves are recorded in the resume's
+// activity feed. Nothing is recorded until it is set.
+func (h *ResumeNoteHandler) SetActivityService(activityService *service.ResumeActivityService) {
+	h.activityService = activityService
+}
+
+// SaveNoteRequest saves a new version of a resume's note, encrypted by the
+// client. Binary fields are base64 encoded.
+type SaveNoteRequest struct {
+	// BaseVersion is the version the client last read, 0 for a new note
+	BaseVersion int    `json:"base_version"`
+	Algorithm   string `json:"algorithm"`
+	KeyID       string `json:"key_id,omitempty"`
+	Nonce       []byte `json:"nonce"`
+	Ciphertext  []byte `json:"ciphertext"`
+}
+
+// NoteVersionsResponse lists the kept versions of a note
+type NoteVersionsResponse struct {
+	Versions []*domain.ResumeNoteVersion `json:"versions"`
+}
+
+// GetNoteHandler returns the latest version of a resume's note
+func (h *ResumeNoteHandler) GetNoteHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	note, err := h.noteService.Note(r.Context(), resume.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrNoteNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Note not found")
+		}
+		return apperror.Internal(err, "Failed to get note")
+	}
+
+	RespondWithJSON(w, http.StatusOK, note)
+	return nil
+}
+
+// SaveNoteHandler stores a new version of a resume's note
+func (h *ResumeNoteHandler) SaveNoteHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var req SaveNoteRequest
+	if err := decodeJSONWithLimits(w, r, &req, noteJSONLimits); err != nil {
+		return decodeError(err)
+	}
+
+	note := &domain.ResumeNote{
+		ResumeID:   resume.ID,
+		UserID:     resume.UserID,
+		Algorithm:  req.Algorithm,
+		KeyID:      req.KeyID,
+		Nonce:      req.Nonce,
+		Ciphertext: req.Ciphertext,
+	}
+	if err := h.noteService.SaveNote(r.Context(), note, req.BaseVersion); err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrNoteVersionConflict):
+			return apperror.New(http.StatusConflict, "VERSION_CONFLICT", "The note was changed since the base version, reload it first")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to save note")
+			return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to save note")
+		}
+	}
+
+	// Notes are encrypted, so only the version is recorded
+	recordActivity(r, h.activityService, resume.ID, domain.ActivityNote, map[string]int{"version": note.Version})
+	RespondWithJSON(w, http.StatusOK, note)
+	return nil
+}
+
+// GetNoteVersionsHandler lists the kept versions of a resume's note
+func (h *ResumeNoteHandler) GetNoteVersionsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	versions, err := h.noteService.Versions(r.Context(), resume.ID)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get note versions")
+	}
+
+	RespondWithJSON(w, http.StatusOK, NoteVersionsResponse{Versions: versions})
+	return nil
+}
+
+// GetNoteVersionHandler returns a kept version of a resume's note
+func (h *ResumeNoteHandler) GetNoteVersionHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version < 1 {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid version")
+	}
+
+	note, err := h.noteService.NoteVersion(r.Context(), resume.ID, version)
+	if err != nil {
+		if errors.Is(err, service.ErrNoteNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Version not found")
+		}
+		return apperror.Internal(err, "Failed to get note version")
+	}
+
+	RespondWithJSON(w, http.StatusOK, note)
+	return nil
+}
+
+// DeleteNoteHandler deletes a resume's note with all its versions
+func (h *ResumeNoteHandler) DeleteNoteHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	if err := h.noteService.DeleteNote(r.Context(), resume.ID); err != nil {
+		if errors.Is(err, service.ErrNoteNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Note not found")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to delete note")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to delete note")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Note deleted successfully"})
+	return nil
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubNoteRepository holds the latest version of a single note
+type stubNoteRepository struct {
+	domain.ResumeNoteRepository
+	note *domain.ResumeNote
+}
+
+func (s *stubNoteRepository) CreateNote(ctx context.Context, note *domain.ResumeNote) error {
+	s.note = note
+	return nil
+}
+
+func (s *stubNoteRepository) GetLatestNote(ctx context.Context, resumeID uuid.UUID) (*domain.ResumeNote, error) {
+	if s.note == nil {
+		return nil, repository.ErrNotFound
+	}
+	return s.note, nil
+}
+
+func TestSaveNoteHandler(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New(), UserID: uuid.New()}
+	repo := &stubNoteRepository{}
+	h := NewResumeNoteHandler(service.NewResumeNoteService(repo, service.ResumeNoteServiceConfig{}))
+
+	save := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/api/v1/resumes/"+resume.ID.String()+"/notes", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.SaveNoteHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	ciphertext := base64.StdEncoding.EncodeToString([]byte("sealed"))
+	rr := save(`{"base_version":0,"algorithm":"AES-256-GCM","nonce":"AAAAAAAAAAAAAAAA","ciphertext":"` + ciphertext + `"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NotNil(t, repo.note)
+	assert.Equal(t, []byte("sealed"), repo.note.Ciphertext)
+	assert.Equal(t, resume.UserID, repo.note.UserID)
+
+	// The ciphertext round-trips as base64 and the owner isn't exposed
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, ciphertext, response["ciphertext"])
+	assert.EqualValues(t, 1, response["version"])
+	assert.NotContains(t, response, "user_id")
+
+	// Saving over a version the client hasn't seen conflicts
+	rr = save(`{"base_version":0,"algorithm":"AES-256-GCM","ciphertext":"` + ciphertext + `"}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = save(`{"base_version":1,"algorithm":"AES-256-GCM","ciphertext":"not base64!"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	large := base64.StdEncoding.EncodeToString(make([]byte, maxNoteRequestSize))
+	rr = save(`{"base_version":1,"algorithm":"AES-256-GCM","ciphertext":"` + large + `"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+}
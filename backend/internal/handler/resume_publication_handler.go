@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/render"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// publishedPagePolicy is the Content-Security-Policy of published resume
+// pages, which only use inline styles. It replaces the policy set for the
+// rest of the API.
+const publishedPagePolicy = "default-src 'none'; style-src 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'; form-action 'none'; base-uri 'none'"
+
+// ResumePublicationHandler handles publishing resumes as public pages
+type ResumePublicationHandler struct {
+	publicationService *service.ResumePublicationService
+	activityService    *service.ResumeActivityService
+	// baseURL is the public URL pages are linked at, such as
+	// "https://resumes.example.com"; links are relative when empty
+	baseURL string
+}
+
+// NewResumePublicationHandler creates a new resume publication handler
+func NewResumePublicationHandler(publicationService *service.ResumePublicationService, baseURL string) *ResumePublicationHandler {
+	return &ResumePublicationHandler{
+		publicationService: publicationService,
+		baseURL:            baseURL,
+	}
+}
+
+// SetActivityService sets where publishing and page views are recorded in
+// the resume's activity feed. Nothing is recorded until it is set.
+func (h *ResumePublicationHandler) SetActivityService(activityService *service.ResumeActivityService) {
+	h.activityService = activityService
+}
+
+// PublishResumeRequest represents a request to publish a resume
+type PublishResumeRequest struct {
+	// Slug is the vanity name the page is served at, under /r/
+	Slug string `json:"slug"`
+	// Hidden lists the fields and sections the page leaves out
+	Hidden []string `json:"hidden,omitempty"`
+}
+
+// PublicationResponse describes a published resume and where its page is
+type PublicationResponse struct {
+	*domain.ResumePublication
+	URL string `json:"url"`
+}
+
+// PublishResumeHandler publishes a resume at a slug, or moves its page to a
+// new one. A taken slug is reported with free alternatives.
+func (h *ResumePublicationHandler) PublishResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var req PublishResumeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	publication, err := h.publicationService.Publish(r.Context(), resume, req.Slug, req.Hidden)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrSlugTaken):
+			conflict := apperror.New(http.StatusConflict, "SLUG_TAKEN", "Another resume is published at this slug")
+			suggestions, err := h.publicationService.SuggestSlugs(r.Context(), req.Slug)
+			if err != nil {
+				log.Ctx(r.Context()).Error().Err(err).Msg("Failed to suggest slugs")
+				return conflict
+			}
+			return conflict.WithDetails(map[string]any{"suggestions": suggestions})
+		default:
+			return apperror.Internal(err, "Failed to publish resume")
+		}
+	}
+
+	recordActivity(r, h.activityService, resume.ID, domain.ActivityPublish, map[string]string{"slug": publication.Slug})
+	RespondWithJSON(w, http.StatusOK, h.publicationResponse(publication))
+	return nil
+}
+
+// GetPublicationHandler returns where a resume is published
+func (h *ResumePublicationHandler) GetPublicationHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	publication, err := h.publicationService.Publication(r.Context(), resume.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrPublicationNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Resume is not published")
+		}
+		return apperror.Internal(err, "Failed to get publication")
+	}
+
+	RespondWithJSON(w, http.StatusOK, h.publicationResponse(publication))
+	return nil
+}
+
+// UnpublishResumeHandler takes a resume's page down, freeing its slug
+func (h *ResumePublicationHandler) UnpublishResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	if err := h.publicationService.Unpublish(r.Context(), resume.ID); err != nil {
+		if errors.Is(err, service.ErrPublicationNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Resume is not published")
+		}
+		return apperror.Internal(err, "Failed to unpublish resume")
+	}
+
+	recordActivity(r, h.activityService, resume.ID, domain.ActivityUnpublish, nil)
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Resume unpublished successfully"})
+	return nil
+}
+
+// PublishedResumeHandler serves the public page of the resume published at a
+// slug. No authentication is required.
+func (h *ResumePublicationHandler) PublishedResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	slug := strings.ToLower(r.PathValue("slug"))
+	resume, publication, err := h.publicationService.View(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, service.ErrPublicationNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Resume not found")
+		}
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var canonicalURL string
+	if h.baseURL != "" {
+		canonicalURL = h.pageURL(publication.Slug)
+	}
+	page := render.HTMLPage(resume, canonicalURL)
+	recordActivity(r, h.activityService, resume.ID, domain.ActivityPageView, map[string]string{"slug": publication.Slug})
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Language", resume.Language)
+	w.Header().Del("Content-Security-Policy-Report-Only")
+	w.Header().Set("Content-Security-Policy", publishedPagePolicy)
+	// Pages may be cached briefly; unpublishing takes effect once they expire
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(page); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("slug", slug).Msg("Failed to write published resume")
+	}
+	return nil
+}
+
+// publicationResponse adds the page URL to a publication
+func (h *ResumePublicationHandler) publicationResponse(publication *domain.ResumePublication) PublicationResponse {
+	return PublicationResponse{ResumePublication: publication, URL: h.pageURL(publication.Slug)}
+}
+
+// pageURL is where the page published at slug is served
+func (h *ResumePublicationHandler) pageURL(slug string) string {
+	return h.baseURL + "/r/" + slug
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publicationRepository keeps publications in memory, by resume ID
+type publicationRepository struct {
+	publications map[uuid.UUID]*domain.ResumePublication
+}
+
+func (r *publicationRepository) SavePublication(ctx context.Context, publication *domain.ResumePublication) error {
+	for id, p := range r.publications {
+		if p.Slug == publication.Slug && id != publication.ResumeID {
+			return repository.ErrConflict
+		}
+	}
+	copied := *publication
+	r.publications[publication.ResumeID] = &copied
+	return nil
+}
+
+func (r *publicationRepository) GetPublication(ctx context.Context, resumeID uuid.UUID) (*domain.ResumePublication, error) {
+	p, ok := r.publications[resumeID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *publicationRepository) GetPublicationBySlug(ctx context.Context, slug string) (*domain.ResumePublication, error) {
+	for _, p := range r.publications {
+		if p.Slug == slug {
+			return p, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *publicationRepository) SlugsTaken(ctx context.Context, slugs []string) ([]string, error) {
+	var taken []string
+	for _, p := range r.publications {
+		if slices.Contains(slugs, p.Slug) {
+			taken = append(taken, p.Slug)
+		}
+	}
+	return taken, nil
+}
+
+func (r *publicationRepository) DeletePublication(ctx context.Context, resumeID uuid.UUID) error {
+	if _, ok := r.publications[resumeID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.publications, resumeID)
+	return nil
+}
+
+func TestResumePublicationHandlers(t *testing.T) {
+	resume := &domain.Resume{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		ResumeMetadata: domain.ResumeMetadata{Language: "en"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", JobTitle: "Engineer"},
+	}
+	repo := &publicationRepository{publications: map[uuid.UUID]*domain.ResumePublication{}}
+	svc := service.NewResumePublicationService(repo, &stubCompleteResumeRepository{resume: resume})
+	h := NewResumePublicationHandler(svc, "https://resumes.example.com")
+
+	mux := http.NewServeMux()
+	mux.Handle("PUT /api/v1/resumes/{id}/publication", HandlerFunc(h.PublishResumeHandler))
+	mux.Handle("DELETE /api/v1/resumes/{id}/publication", HandlerFunc(h.UnpublishResumeHandler))
+	mux.Handle("GET /r/{slug}", HandlerFunc(h.PublishedResumeHandler))
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+	publicationPath := "/api/v1/resumes/" + resume.ID.String() + "/publication"
+
+	rr := serve(http.MethodPut, publicationPath, `{"slug": "Jane-Doe", "hidden": ["email"]}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body PublicationResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "jane-doe", body.Slug)
+	assert.Equal(t, "https://resumes.example.com/r/jane-doe", body.URL)
+
+	// The page is public, leaves out hidden fields and links to itself
+	rr = serve(http.MethodGet, "/r/Jane-Doe", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, publishedPagePolicy, rr.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, rr.Body.String(), "<title>Jane Doe — Engineer</title>")
+	assert.Contains(t, rr.Body.String(), `<link rel="canonical" href="https://resumes.example.com/r/jane-doe">`)
+	assert.NotContains(t, rr.Body.String(), "jane@example.com")
+
+	// Another resume's slug is refused with free alternatives
+	repo.publications[uuid.New()] = &domain.ResumePublication{Slug: "john-doe"}
+	repo.publications[uuid.New()] = &domain.ResumePublication{Slug: "john-doe-2"}
+	rr = serve(http.MethodPut, publicationPath, `{"slug": "john-doe"}`)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	var problem struct {
+		Code    string `json:"code"`
+		Details struct {
+			Suggestions []string `json:"suggestions"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	assert.Equal(t, "SLUG_TAKEN", problem.Code)
+	assert.Equal(t, []string{"john-doe-3", "john-doe-4", "john-doe-5"}, problem.Details.Suggestions)
+
+	for _, slug := range []string{"ab", "admin", "jane--doe", "jane_doe", "-jane"} {
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, publicationPath, `{"slug": "`+slug+`"}`).Code, slug)
+	}
+
+	// Unpublishing takes the page down
+	require.Equal(t, http.StatusOK, serve(http.MethodDelete, publicationPath, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/r/jane-doe", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, publicationPath, "").Code)
+}
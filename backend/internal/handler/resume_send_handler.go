@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	netmail "net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/render"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/rs/zerolog/log"
+)
+
+// Formats a resume can be emailed in
+const (
+	SendFormatPDF      = "pdf"
+	SendFormatMarkdown = "markdown"
+)
+
+// Length limits of the subject and message of an emailed resume, in characters
+const (
+	maxSendSubjectLength = 200
+	maxSendMessageLength = 5000
+)
+
+// ResumeSendHandler handles emailing resumes to addresses users give
+type ResumeSendHandler struct {
+	resumeRepo      domain.ResumeRepository
+	sendService     *service.ResumeSendService
+	activityService *service.ResumeActivityService
+}
+
+// NewResumeSendHandler creates a new resume send handler
+func NewResumeSendHandler(resumeRepo domain.ResumeRepository, sendService *service.ResumeSendService) *ResumeSendHandler {
+	return &ResumeSendHandler{
+		resumeRepo:  resumeRepo,
+		sendService: sendService,
+	}
+}
+
+// SetActivityService sets where emailed resumes are recorded in the resume's
+// activity feed. Nothing is recorded until it is set.
+func (h *ResumeSendHandler) SetActivityService(activityService *service.ResumeActivityService) {
+	h.activityService = activityService
+}
+
+// SendResumeRequest represents a request to email a resume
+type SendResumeRequest struct {
+	// To is the address the resume is sent to
+	To string `json:"to"`
+	// Format is pdf (the default) or markdown
+	Format string `json:"format,omitempty"`
+	// Subject defaults to "Resume of" and the name on the resume
+	Subject string `json:"subject,omitempty"`
+	// Message is the body of the email, such as a cover note
+	Message string `json:"message,omitempty"`
+	// Confirm sends the email; without it the response previews what would be sent
+	Confirm bool `json:"confirm"`
+}
+
+// SendResumeResponse describes an emailed resume, or one that would be
+// emailed once confirmed
+type SendResumeResponse struct {
+	Sent     bool   `json:"sent"`
+	To       string `json:"to"`
+	Subject  string `json:"subject"`
+	Filename string `json:"filename"`
+	Size     int    `json:"size"`
+	// Remaining is how many more resumes the user can email today
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// SendResumeHandler emails a resume as an attachment. Unless the request
+// confirms it, nothing is sent and the response previews the email so the
+// user can check the address first.
+func (h *ResumeSendHandler) SendResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req SendResumeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	to, err := netmail.ParseAddress(strings.TrimSpace(req.To))
+	if err != nil || to.Name != "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "To must be an email address")
+	}
+	if req.Format == "" {
+		req.Format = SendFormatPDF
+	}
+	if req.Format != SendFormatPDF && req.Format != SendFormatMarkdown {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Format must be \""+SendFormatPDF+"\" or \""+SendFormatMarkdown+"\"")
+	}
+	req.Subject = strings.TrimSpace(req.Subject)
+	if strings.ContainsAny(req.Subject, "\r\n") || utf8.RuneCountInString(req.Subject) > maxSendSubjectLength {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Subject must be a single line of at most "+strconv.Itoa(maxSendSubjectLength)+" characters")
+	}
+	if utf8.RuneCountInString(req.Message) > maxSendMessageLength {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Message must be at most "+strconv.Itoa(maxSendMessageLength)+" characters")
+	}
+
+	complete, err := loadCompleteResume(r, h.resumeRepo)
+	if err != nil {
+		return err
+	}
+
+	attachment := resumeAttachment(complete, req.Format)
+	msg := &mail.Message{
+		To:          to.Address,
+		Subject:     req.Subject,
+		Body:        req.Message,
+		ReplyTo:     claims.Email,
+		Attachments: []mail.Attachment{attachment},
+	}
+	if msg.Subject == "" {
+		msg.Subject = defaultSendSubject(complete)
+	}
+	response := SendResumeResponse{
+		To:       msg.To,
+		Subject:  msg.Subject,
+		Filename: attachment.Filename,
+		Size:     len(attachment.Data),
+		ResetAt:  h.sendService.ResetAt(),
+	}
+
+	if !req.Confirm {
+		if response.Remaining, err = h.sendService.Remaining(r.Context(), userID); err != nil {
+			return apperror.Internal(err, "Failed to get daily send allowance")
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+		return nil
+	}
+
+	response.Remaining, err = h.sendService.Send(r.Context(), userID, msg)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSendLimitReached):
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(response.ResetAt).Seconds())+1))
+			return apperror.New(http.StatusTooManyRequests, "SEND_LIMIT_REACHED", "You can email "+strconv.Itoa(h.sendService.DailyLimit())+" resumes a day, try again tomorrow")
+		case errors.Is(err, mail.ErrInvalidMessage):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "The email can't be sent to this address")
+		default:
+			return apperror.Internal(err, "Failed to send resume")
+		}
+	}
+
+	log.Ctx(r.Context()).Info().Str("resume_id", complete.ID.String()).Str("format", req.Format).Msg("Resume emailed")
+	recordActivity(r, h.activityService, complete.ID, domain.ActivityEmail, map[string]string{"to": msg.To, "format": req.Format})
+
+	response.Sent = true
+	RespondWithJSON(w, http.StatusAccepted, response)
+	return nil
+}
+
+// resumeAttachment renders a resume in the format as an email attachment,
+// named as it is when downloaded
+func resumeAttachment(resume *domain.Resume, format string) mail.Attachment {
+	if format == SendFormatMarkdown {
+		return mail.Attachment{Filename: "resume-" + resume.ID.String() + ".md", ContentType: "text/markdown; charset=utf-8", Data: render.Markdown(resume)}
+	}
+	return mail.Attachment{Filename: "resume-" + resume.ID.String() + ".pdf", ContentType: "application/pdf", Data: render.PDF(resume)}
+}
+
+// defaultSendSubject names the person a resume is for, or just says it is a
+// resume when it has no name
+func defaultSendSubject(resume *domain.Resume) string {
+	if resume.PersonalInfo != nil {
+		if name := strings.TrimSpace(resume.PersonalInfo.FirstName + " " + resume.PersonalInfo.LastName); name != "" {
+			return "Resume of " + name
+		}
+	}
+	return "Resume"
+}
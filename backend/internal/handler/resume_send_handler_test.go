@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendResumeHandler(t *testing.T) {
+	resume := &domain.Resume{
+		ID:             uuid.New(),
+		ResumeMetadata: domain.ResumeMetadata{Language: "en"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Jane", LastName: "Doe"},
+	}
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	mr := miniredis.RunT(t)
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})})
+	sendService := service.NewResumeSendService(service.NewMailService(queue, mail.NewLogSender()), store, service.ResumeSendServiceConfig{DailyLimit: 1})
+	h := NewResumeSendHandler(&stubCompleteResumeRepository{resume: resume}, sendService)
+	userID := uuid.New()
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/resumes/"+resume.ID.String()+"/send", strings.NewReader(body))
+		req = withClaims(req, userID)
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.SendResumeHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Without confirmation the email is only previewed
+	rr := post(`{"to": "jobs@example.com"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body SendResumeResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.False(t, body.Sent)
+	assert.Equal(t, "Resume of Jane Doe", body.Subject)
+	assert.Equal(t, "resume-"+resume.ID.String()+".pdf", body.Filename)
+	assert.Positive(t, body.Size)
+	assert.Equal(t, 1, body.Remaining)
+
+	rr = post(`{"to": "jobs@example.com", "format": "markdown", "subject": "Application", "confirm": true}`)
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.True(t, body.Sent)
+	assert.Equal(t, "Application", body.Subject)
+	assert.Equal(t, "resume-"+resume.ID.String()+".md", body.Filename)
+	assert.Equal(t, 0, body.Remaining)
+
+	// The daily limit is reached
+	rr = post(`{"to": "jobs@example.com", "confirm": true}`)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no address", `{"confirm": true}`},
+		{"named address", `{"to": "Jobs <jobs@example.com>", "confirm": true}`},
+		{"unknown format", `{"to": "jobs@example.com", "format": "docx", "confirm": true}`},
+		{"multiline subject", `{"to": "jobs@example.com", "subject": "Hi\r\nBcc: eve@example.com", "confirm": true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, post(tt.body).Code)
+		})
+	}
+}
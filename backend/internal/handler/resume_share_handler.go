@@ -0,0 +1,415 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/render"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// ShareViewerCookie holds the viewer token of an unlocked share link. It is
+// scoped to the link's path, so each link is unlocked separately.
+const ShareViewerCookie = "share_viewer"
+
+// Passphrase guess limits per client and link
+const (
+	shareUnlockLimit    = 5
+	shareUnlockInterval = 15 * time.Minute
+)
+
+// ResumeShareHandler handles share links and viewing shared resumes
+type ResumeShareHandler struct {
+	shareService    *service.ResumeShareService
+	activityService *service.ResumeActivityService
+	rateLimiter     security.Limiter
+	// secureCookies marks the viewer cookie Secure
+	secureCookies bool
+}
+
+// NewResumeShareHandler creates a new resume share handler
+func NewResumeShareHandler(shareService *service.ResumeShareService, redisClient redis.UniversalClient) *ResumeShareHandler {
+	// Unlock attempts are counted per client IP and link, so passphrases
+	// can't be guessed quickly
+	rateLimiterConfig := security.RateLimiterConfig{
+		Redis:    redisClient,
+		Name:     "share_unlock",
+		Limit:    shareUnlockLimit,
+		Interval: shareUnlockInterval,
+	}
+
+	return &ResumeShareHandler{
+		shareService:  shareService,
+		rateLimiter:   security.NewLimiter(rateLimiterConfig),
+		secureCookies: true,
+	}
+}
+
+// SetSecureCookies sets whether the viewer cookie is only sent over HTTPS.
+// Turn it off only for local development over plain HTTP.
+func (h *ResumeShareHandler) SetSecureCookies(secure bool) {
+	h.secureCookies = secure
+}
+
+// SetActivityService sets where share links and their views are recorded in
+// the resume's activity feed. Nothing is recorded until it is set.
+func (h *ResumeShareHandler) SetActivityService(activityService *service.ResumeActivityService) {
+	h.activityService = activityService
+}
+
+// CreateShareRequest creates a share link
+type CreateShareRequest struct {
+	// Passphrase protects the link when set
+	Passphrase string `json:"passphrase,omitempty"`
+	// MaxViews stops the link working after that many views when set
+	MaxViews int `json:"max_views,omitempty"`
+	// ExpiresAt stops the link working at that time when set
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+	// Hidden lists contact details and sections viewers don't see
+	Hidden []string `json:"hidden,omitempty"`
+}
+
+// UpdateShareRequest adjusts the limits and hidden fields of a share link;
+// omitted settings are left unchanged
+type UpdateShareRequest struct {
+	// MaxViews replaces the view limit; 0 removes it
+	MaxViews *int `json:"max_views,omitempty"`
+	// ExpiresAt replaces the expiry as an RFC 3339 time; "" removes it
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	// Hidden replaces the hidden fields and sections; [] shows everything
+	Hidden *[]string `json:"hidden,omitempty"`
+}
+
+// UnlockShareRequest unlocks a protected share link
+type UnlockShareRequest struct {
+	Passphrase string `json:"passphrase" validate:"required"`
+}
+
+// ShareResponse describes a share link
+type ShareResponse struct {
+	ID        string `json:"id"`
+	Token     string `json:"token"`
+	Protected bool   `json:"protected"`
+	MaxViews  int    `json:"max_views,omitempty"`
+	// Views is only counted for links with a view limit
+	Views     int64    `json:"views,omitempty"`
+	ExpiresAt string   `json:"expires_at,omitempty"`
+	Hidden    []string `json:"hidden"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// SharesResponse lists a resume's share links
+type SharesResponse struct {
+	Shares []ShareResponse `json:"shares"`
+}
+
+// UnlockShareResponse tells viewers how long the link stays unlocked
+type UnlockShareResponse struct {
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// CreateShareHandler creates a share link for a resume
+func (h *ResumeShareHandler) CreateShareHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var req CreateShareRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	limits := domain.ShareLimits{MaxViews: req.MaxViews, ExpiresAt: req.ExpiresAt}
+	share, err := h.shareService.CreateShare(r.Context(), resume.ID, req.Passphrase, limits, req.Hidden)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to create share link")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to create share link")
+	}
+
+	recordActivity(r, h.activityService, resume.ID, domain.ActivityShareCreate, map[string]any{"share_id": share.ID, "protected": share.Protected()})
+	RespondWithJSON(w, http.StatusCreated, h.shareResponse(r, share))
+	return nil
+}
+
+// GetSharesHandler lists a resume's share links
+func (h *ResumeShareHandler) GetSharesHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	shares, err := h.shareService.Shares(r.Context(), resume.ID)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to list share links")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to get share links")
+	}
+
+	response := SharesResponse{Shares: make([]ShareResponse, len(shares))}
+	for i, share := range shares {
+		response.Shares[i] = h.shareResponse(r, share)
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+	return nil
+}
+
+// UpdateShareHandler adjusts the view limit, expiry and hidden fields of a share link
+func (h *ResumeShareHandler) UpdateShareHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	shareID, err := uuid.Parse(r.PathValue("shareId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid share link ID")
+	}
+
+	var req UpdateShareRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	share, err := h.shareService.Share(r.Context(), resume.ID, shareID)
+	if err != nil {
+		if errors.Is(err, service.ErrShareNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Share link not found")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("share_id", shareID.String()).Msg("Failed to get share link")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to get share link")
+	}
+
+	limits := share.ShareLimits
+	if req.MaxViews != nil {
+		limits.MaxViews = *req.MaxViews
+	}
+	if req.ExpiresAt != nil {
+		limits.ExpiresAt = time.Time{}
+		if *req.ExpiresAt != "" {
+			expiresAt, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+			if err != nil {
+				return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Expiry must be an RFC 3339 time")
+			}
+			limits.ExpiresAt = expiresAt
+		}
+	}
+
+	hidden := share.Hidden
+	if req.Hidden != nil {
+		hidden = *req.Hidden
+	}
+
+	if err := h.shareService.UpdateShare(r.Context(), share, limits, hidden); err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrShareNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Share link not found")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Str("share_id", shareID.String()).Msg("Failed to update share link")
+			return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to update share link")
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, h.shareResponse(r, share))
+	return nil
+}
+
+// RevokeShareHandler deletes a share link
+func (h *ResumeShareHandler) RevokeShareHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	shareID, err := uuid.Parse(r.PathValue("shareId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid share link ID")
+	}
+
+	if err := h.shareService.RevokeShare(r.Context(), resume.ID, shareID); err != nil {
+		if errors.Is(err, service.ErrShareNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Share link not found")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("share_id", shareID.String()).Msg("Failed to revoke share link")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to revoke share link")
+	}
+
+	recordActivity(r, h.activityService, resume.ID, domain.ActivityShareRevoke, map[string]any{"share_id": shareID})
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Share link revoked successfully"})
+	return nil
+}
+
+// UnlockShareHandler checks the passphrase of a protected share link and sets
+// a short-lived viewer cookie that unlocks it
+func (h *ResumeShareHandler) UnlockShareHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := h.applyRateLimit(w, r); err != nil {
+		return err
+	}
+
+	var req UnlockShareRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+	if req.Passphrase == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Passphrase is required")
+	}
+
+	token := r.PathValue("token")
+	viewerToken, expiresAt, err := h.shareService.Unlock(r.Context(), token, req.Passphrase)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrShareNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Share link not found")
+		case errors.Is(err, service.ErrWrongPassphrase):
+			return apperror.New(http.StatusUnauthorized, "WRONG_PASSPHRASE", "Wrong passphrase")
+		case errors.Is(err, service.ErrShareExpired):
+			return apperror.New(http.StatusGone, "SHARE_EXPIRED", "Share link has expired")
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Msg("Failed to unlock share link")
+			return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to unlock share link")
+		}
+	}
+
+	// Open links need no cookie
+	if viewerToken == "" {
+		RespondWithJSON(w, http.StatusOK, UnlockShareResponse{})
+		return nil
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ShareViewerCookie,
+		Value:    viewerToken,
+		Path:     sharedResumePath(token),
+		Expires:  expiresAt,
+		MaxAge:   int(h.shareService.ViewerTTL().Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+	RespondWithJSON(w, http.StatusOK, UnlockShareResponse{ExpiresAt: expiresAt.Format(time.RFC3339)})
+	return nil
+}
+
+// GetSharedResumeHandler returns the resume behind a share link
+func (h *ResumeShareHandler) GetSharedResumeHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := h.viewSharedResume(r, "json")
+	if err != nil {
+		return err
+	}
+
+	// Shared resumes may be protected or revoked at any time
+	w.Header().Set("Cache-Control", "private, no-store")
+	RespondWithJSON(w, http.StatusOK, resume)
+	return nil
+}
+
+// ExportSharedMarkdownHandler renders the resume behind a share link as
+// Markdown, leaving out what the link hides. The export counts as a view.
+func (h *ResumeShareHandler) ExportSharedMarkdownHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := h.viewSharedResume(r, "markdown")
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeDocument(w, r, render.Markdown(resume), "text/markdown; charset=utf-8", resume.Language, "resume.md")
+	return nil
+}
+
+// ExportSharedPDFHandler renders the resume behind a share link as PDF,
+// leaving out what the link hides. The export counts as a view.
+func (h *ResumeShareHandler) ExportSharedPDFHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := h.viewSharedResume(r, "pdf")
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeDocument(w, r, render.PDF(resume), "application/pdf", resume.Language, "resume.pdf")
+	return nil
+}
+
+// viewSharedResume resolves the resume behind the share link in the request
+// and records the view in the resume's activity feed; format is what the
+// viewer receives it as
+func (h *ResumeShareHandler) viewSharedResume(r *http.Request, format string) (*domain.Resume, error) {
+	var viewerToken string
+	if cookie, err := r.Cookie(ShareViewerCookie); err == nil {
+		viewerToken = cookie.Value
+	}
+
+	resume, err := h.shareService.View(r.Context(), r.PathValue("token"), viewerToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrShareNotFound):
+			return nil, apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Share link not found")
+		case errors.Is(err, service.ErrPassphraseRequired):
+			return nil, apperror.New(http.StatusUnauthorized, "PASSPHRASE_REQUIRED", "This resume is protected by a passphrase")
+		case errors.Is(err, service.ErrShareExpired):
+			return nil, apperror.New(http.StatusGone, "SHARE_EXPIRED", "Share link has expired")
+		case errors.Is(err, service.ErrShareExhausted):
+			return nil, apperror.New(http.StatusGone, "SHARE_VIEW_LIMIT_REACHED", "Share link has reached its view limit")
+		default:
+			return nil, apperror.Internal(err, "Failed to get resume")
+		}
+	}
+
+	recordActivity(r, h.activityService, resume.ID, domain.ActivityShareView, map[string]string{"format": format})
+	return resume, nil
+}
+
+// applyRateLimit limits passphrase guesses per client and link
+func (h *ResumeShareHandler) applyRateLimit(w http.ResponseWriter, r *http.Request) error {
+	if _, err := h.rateLimiter.CheckRateLimit(r.Context(), r); err != nil {
+		if errors.Is(err, security.ErrRateLimitExceeded) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(shareUnlockInterval.Seconds())))
+			return apperror.New(http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Too many passphrase attempts, try again later")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Rate limiting error")
+	}
+	return nil
+}
+
+// sharedResumePath is the path a share link is viewed at
+func sharedResumePath(token string) string {
+	return "/api/v1/shared/" + token
+}
+
+// shareResponse converts a share link to its API representation
+func (h *ResumeShareHandler) shareResponse(r *http.Request, share *domain.ResumeShare) ShareResponse {
+	response := ShareResponse{
+		ID:        share.ID.String(),
+		Token:     share.Token,
+		Protected: share.Protected(),
+		MaxViews:  share.MaxViews,
+		Hidden:    share.Hidden,
+		CreatedAt: share.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if !share.ExpiresAt.IsZero() {
+		response.ExpiresAt = share.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if share.MaxViews > 0 {
+		views, err := h.shareService.Views(r.Context(), share)
+		if err != nil {
+			// The link is still described, just without its view count
+			log.Ctx(r.Context()).Error().Err(err).Str("share_id", share.ID.String()).Msg("Failed to get share link views")
+		}
+		response.Views = views
+	}
+	return response
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubShareRepository holds a single share link
+type stubShareRepository struct {
+	domain.ResumeShareRepository
+	share *domain.ResumeShare
+}
+
+func (s *stubShareRepository) CreateShare(ctx context.Context, share *domain.ResumeShare) error {
+	share.ID = uuid.New()
+	s.share = share
+	return nil
+}
+
+func (s *stubShareRepository) GetShareByToken(ctx context.Context, token string) (*domain.ResumeShare, error) {
+	if s.share == nil || s.share.Token != token {
+		return nil, repository.ErrNotFound
+	}
+	return s.share, nil
+}
+
+func TestSharePassphraseFlow(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New(), ResumeMetadata: domain.ResumeMetadata{Title: "Backend"}}
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	shares := &stubShareRepository{}
+	shareService := service.NewResumeShareService(shares, &stubCompleteResumeRepository{resume: resume}, store, service.ResumeShareServiceConfig{})
+	share, err := shareService.CreateShare(context.Background(), resume.ID, "correct horse", domain.ShareLimits{}, nil)
+	require.NoError(t, err)
+	h := NewResumeShareHandler(shareService, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/shared/{token}", HandlerFunc(h.GetSharedResumeHandler))
+	mux.Handle("POST /api/v1/shared/{token}/unlock", HandlerFunc(h.UnlockShareHandler))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+	unlock := func(passphrase string) *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"passphrase":"` + passphrase + `"}`)
+		return serve(httptest.NewRequest("POST", "/api/v1/shared/"+share.Token+"/unlock", body))
+	}
+
+	rr := serve(httptest.NewRequest("GET", "/api/v1/shared/"+share.Token, nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "PASSPHRASE_REQUIRED")
+
+	rr = unlock("wrong horse")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = unlock("correct horse")
+	require.Equal(t, http.StatusOK, rr.Code)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, ShareViewerCookie, cookies[0].Name)
+	assert.Equal(t, "/api/v1/shared/"+share.Token, cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+
+	req := httptest.NewRequest("GET", "/api/v1/shared/"+share.Token, nil)
+	req.AddCookie(cookies[0])
+	rr = serve(req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"title":"Backend"`)
+
+	// Guesses are limited per client and link
+	for range shareUnlockLimit - 2 {
+		assert.Equal(t, http.StatusUnauthorized, unlock("wrong horse").Code)
+	}
+	rr = unlock("correct horse")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+}
+
+// stubCompleteResumeRepository serves one complete resume
+type stubCompleteResumeRepository struct {
+	domain.ResumeRepository
+	resume *domain.Resume
+}
+
+func (s *stubCompleteResumeRepository) GetCompleteResume(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	if id != s.resume.ID {
+		return nil, repository.ErrNotFound
+	}
+	return s.resume, nil
+}
+
+func (s *stubShareRepository) GetShareByID(ctx context.Context, resumeID, id uuid.UUID) (*domain.ResumeShare, error) {
+	if s.share == nil || s.share.ID != id || s.share.ResumeID != resumeID {
+		return nil, repository.ErrNotFound
+	}
+	return s.share, nil
+}
+
+func (s *stubShareRepository) UpdateShare(ctx context.Context, share *domain.ResumeShare) error {
+	return nil
+}
+
+func TestUpdateShareHandler(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New()}
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	shareService := service.NewResumeShareService(&stubShareRepository{}, &stubCompleteResumeRepository{resume: resume}, store, service.ResumeShareServiceConfig{})
+	share, err := shareService.CreateShare(context.Background(), resume.ID, "", domain.ShareLimits{MaxViews: 3, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	require.NoError(t, err)
+	h := NewResumeShareHandler(shareService, nil)
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", "/api/v1/resumes/"+resume.ID.String()+"/share/"+share.ID.String(), strings.NewReader(body))
+		req.SetPathValue("shareId", share.ID.String())
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.UpdateShareHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Omitted limits are kept
+	rr := patch(`{"max_views":10}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 10, share.MaxViews)
+	assert.False(t, share.ExpiresAt.IsZero())
+
+	// An empty expiry removes it
+	rr = patch(`{"expires_at":""}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, share.ExpiresAt.IsZero())
+	assert.NotContains(t, rr.Body.String(), "expires_at")
+
+	// Hidden fields are replaced as a whole
+	rr = patch(`{"hidden":["email","projects"]}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{"email", "projects"}, share.Hidden)
+	assert.Equal(t, 10, share.MaxViews)
+
+	for _, body := range []string{`{"expires_at":"tomorrow"}`, `{"expires_at":"2000-01-01T00:00:00Z"}`, `{"max_views":-1}`, `{"hidden":["salary"]}`} {
+		rr := patch(body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, "body %s", body)
+	}
+	assert.Equal(t, 10, share.MaxViews)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// ResumeTranslationHandler handles localized variants of resumes
+type ResumeTranslationHandler struct {
+	translationService *service.ResumeTranslationService
+	eventService       *service.ResumeEventService
+}
+
+// NewResumeTranslationHandler creates a new resume translation handler
+func NewResumeTranslationHandler(translationService *service.ResumeTranslationService, eventService *service.ResumeEventService) *ResumeTranslationHandler {
+	return &ResumeTranslationHandler{
+		translationService: translationService,
+		eventService:       eventService,
+	}
+}
+
+// CreateTranslationRequest names the language of a new translation
+type CreateTranslationRequest struct {
+	// Language is a BCP 47 tag such as "es" or "pt-BR"
+	Language string `json:"language" validate:"required"`
+}
+
+// TranslationsResponse lists a resume's original and its translations
+type TranslationsResponse struct {
+	Translations []*domain.Resume `json:"translations"`
+}
+
+// CreateTranslationHandler copies a resume and its sections into a new resume in another language
+func (h *ResumeTranslationHandler) CreateTranslationHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	var req CreateTranslationRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	translation, err := h.translationService.CreateTranslation(r.Context(), resume, req.Language)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		case errors.Is(err, service.ErrTranslationExists):
+			return apperror.New(http.StatusConflict, "TRANSLATION_EXISTS", "A translation in this language already exists")
+		default:
+			if errors.Is(err, domain.ErrLimitExceeded) {
+				return err
+			}
+			log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to create resume translation")
+			return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to create translation")
+		}
+	}
+
+	if actorID, err := GetUserIDFromContext(r.Context()); err == nil {
+		if _, err := h.eventService.Record(r.Context(), translation.ID, actorID, domain.EventEntityResume, uuid.Nil, domain.EventOpCreate, translation); err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Str("resume_id", translation.ID.String()).Msg("Failed to record resume event")
+		}
+	}
+
+	RespondWithJSON(w, http.StatusCreated, translation)
+	return nil
+}
+
+// GetTranslationsHandler lists the original of a resume and all of its translations
+func (h *ResumeTranslationHandler) GetTranslationsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	translations, err := h.translationService.Translations(r.Context(), resume)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to list resume translations")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to get translations")
+	}
+
+	RespondWithJSON(w, http.StatusOK, TranslationsResponse{Translations: translations})
+	return nil
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/analysis"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+)
+
+// RoleProfileHandler handles role profiles and comparing resumes against them
+type RoleProfileHandler struct {
+	roleService *service.RoleProfileService
+	resumeRepo  domain.ResumeRepository
+}
+
+// NewRoleProfileHandler creates a new role profile handler
+func NewRoleProfileHandler(roleService *service.RoleProfileService, resumeRepo domain.ResumeRepository) *RoleProfileHandler {
+	return &RoleProfileHandler{
+		roleService: roleService,
+		resumeRepo:  resumeRepo,
+	}
+}
+
+// RoleProfilesResponse lists the role profiles resumes can be compared against
+type RoleProfilesResponse struct {
+	Roles []*domain.RoleProfile `json:"roles"`
+}
+
+// ListRolesHandler lists the role profiles
+func (h *RoleProfileHandler) ListRolesHandler(w http.ResponseWriter, r *http.Request) error {
+	profiles, err := h.roleService.Profiles(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get role profiles")
+	}
+
+	RespondWithJSON(w, http.StatusOK, RoleProfilesResponse{Roles: profiles})
+	return nil
+}
+
+// CompareHandler compares a resume with the role profile named by the role
+// query parameter, reporting its gaps with recommendations
+func (h *RoleProfileHandler) CompareHandler(w http.ResponseWriter, r *http.Request) error {
+	slug := r.URL.Query().Get("role")
+	if slug == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Role is required")
+	}
+
+	profile, err := h.roleService.Profile(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, service.ErrRoleNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Role not found")
+		}
+		return apperror.Internal(err, "Failed to get role profile")
+	}
+
+	complete, err := loadCompleteResume(r, h.resumeRepo)
+	if err != nil {
+		return err
+	}
+
+	RespondWithJSON(w, http.StatusOK, analysis.CompareRole(complete, profile, time.Now()))
+	return nil
+}
+
+// SaveRoleHandler saves a role profile over the built-in profile with the
+// slug in the path, or adds a role (admin only)
+func (h *RoleProfileHandler) SaveRoleHandler(w http.ResponseWriter, r *http.Request) error {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var profile domain.RoleProfile
+	if err := decodeJSON(w, r, &profile); err != nil {
+		return decodeError(err)
+	}
+	profile.Slug = r.PathValue("slug")
+
+	if err := h.roleService.SaveProfile(r.Context(), &profile, adminID); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+		return apperror.Internal(err, "Failed to save role profile")
+	}
+
+	RespondWithJSON(w, http.StatusOK, &profile)
+	return nil
+}
+
+// DeleteRoleHandler deletes a saved role profile, restoring the built-in
+// profile with its slug if there is one (admin only)
+func (h *RoleProfileHandler) DeleteRoleHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := h.roleService.DeleteProfile(r.Context(), r.PathValue("slug")); err != nil {
+		if errors.Is(err, service.ErrRoleNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "No saved role profile with this slug")
+		}
+		return apperror.Internal(err, "Failed to delete role profile")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Role profile deleted successfully"})
+	return nil
+}
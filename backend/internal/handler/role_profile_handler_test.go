@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/analysis"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noRoleProfileRepository has no saved role profiles
+type noRoleProfileRepository struct {
+	domain.RoleProfileRepository
+}
+
+func (noRoleProfileRepository) ListRoleProfiles(ctx context.Context) ([]*domain.RoleProfile, error) {
+	return nil, nil
+}
+
+func TestCompareHandler(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New(), Skills: []*domain.Skill{{Name: "PostgreSQL"}, {Name: "Docker"}}}
+	h := NewRoleProfileHandler(service.NewRoleProfileService(noRoleProfileRepository{}), &stubCompleteResumeRepository{resume: resume})
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/resumes/"+resume.ID.String()+"/compare"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		HandlerFunc(h.CompareHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get("?role=backend-mid")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body analysis.Comparison
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "backend-mid", body.Role)
+	assert.Contains(t, body.MatchedSkills, "SQL")
+	assert.Contains(t, body.MatchedSkills, "Docker")
+	assert.Contains(t, body.MissingSections, domain.RoleSectionExperience)
+	assert.NotEmpty(t, body.Recommendations)
+
+	assert.Equal(t, http.StatusBadRequest, get("").Code)
+	assert.Equal(t, http.StatusNotFound, get("?role=astronaut").Code)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// ServiceAccountHandler handles the service accounts admins create for integrations
+type ServiceAccountHandler struct {
+	serviceAccountService *service.ServiceAccountService
+}
+
+// NewServiceAccountHandler creates a new service account handler
+func NewServiceAccountHandler(serviceAccountService *service.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// CreateServiceAccountRequest creates a service account
+type CreateServiceAccountRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Scopes lists what the account may do: jobs:read, metrics:read, users:read
+	Scopes []string `json:"scopes"`
+	// ExpiresAt stops the key working at that time when set
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// ServiceAccountKeyResponse returns a service account with its new key. The
+// key itself is only shown here.
+type ServiceAccountKeyResponse struct {
+	*domain.ServiceAccount
+	Key string `json:"key"`
+}
+
+// ServiceAccountsResponse lists the service accounts
+type ServiceAccountsResponse struct {
+	ServiceAccounts []*domain.ServiceAccount `json:"service_accounts"`
+}
+
+// ServiceAccountAuditResponse lists the most recent audit entries of a service account
+type ServiceAccountAuditResponse struct {
+	Entries []*domain.ServiceAccountAuditEntry `json:"entries"`
+}
+
+// CreateServiceAccountHandler creates a service account (admin only)
+func (h *ServiceAccountHandler) CreateServiceAccountHandler(w http.ResponseWriter, r *http.Request) error {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var req CreateServiceAccountRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	account, secret, err := h.serviceAccountService.CreateAccount(r.Context(), adminID, req.Name, req.Description, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to create service account")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to create service account")
+	}
+
+	RespondWithJSON(w, http.StatusCreated, ServiceAccountKeyResponse{ServiceAccount: account, Key: secret})
+	return nil
+}
+
+// GetServiceAccountsHandler lists the service accounts (admin only)
+func (h *ServiceAccountHandler) GetServiceAccountsHandler(w http.ResponseWriter, r *http.Request) error {
+	accounts, err := h.serviceAccountService.Accounts(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get service accounts")
+	}
+
+	RespondWithJSON(w, http.StatusOK, ServiceAccountsResponse{ServiceAccounts: accounts})
+	return nil
+}
+
+// RotateServiceAccountKeyHandler replaces the key of a service account (admin only)
+func (h *ServiceAccountHandler) RotateServiceAccountKeyHandler(w http.ResponseWriter, r *http.Request) error {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	accountID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid service account ID")
+	}
+
+	account, secret, err := h.serviceAccountService.RotateKey(r.Context(), adminID, accountID)
+	if err != nil {
+		if errors.Is(err, service.ErrServiceAccountNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Service account not found")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("service_account_id", accountID.String()).Msg("Failed to rotate service account key")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to rotate service account key")
+	}
+
+	RespondWithJSON(w, http.StatusOK, ServiceAccountKeyResponse{ServiceAccount: account, Key: secret})
+	return nil
+}
+
+// DeleteServiceAccountHandler deletes a service account (admin only)
+func (h *ServiceAccountHandler) DeleteServiceAccountHandler(w http.ResponseWriter, r *http.Request) error {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	accountID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid service account ID")
+	}
+
+	if err := h.serviceAccountService.DeleteAccount(r.Context(), adminID, accountID); err != nil {
+		if errors.Is(err, service.ErrServiceAccountNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Service account not found")
+		}
+		log.Ctx(r.Context()).Error().Err(err).Str("service_account_id", accountID.String()).Msg("Failed to delete service account")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to delete service account")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Service account deleted successfully"})
+	return nil
+}
+
+// GetServiceAccountAuditHandler lists what admins did to a service account
+// and the requests it made, newest first (admin only)
+func (h *ServiceAccountHandler) GetServiceAccountAuditHandler(w http.ResponseWriter, r *http.Request) error {
+	accountID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid service account ID")
+	}
+
+	entries, err := h.serviceAccountService.AuditLog(r.Context(), accountID)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get service account audit trail")
+	}
+
+	RespondWithJSON(w, http.StatusOK, ServiceAccountAuditResponse{Entries: entries})
+	return nil
+}
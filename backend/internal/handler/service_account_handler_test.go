@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serviceAccountRepository keeps service accounts and their audit trails in memory
+type serviceAccountRepository struct {
+	domain.ServiceAccountRepository
+	accounts []*domain.ServiceAccount
+	audit    []*domain.ServiceAccountAuditEntry
+}
+
+func (r *serviceAccountRepository) CreateServiceAccount(ctx context.Context, account *domain.ServiceAccount) error {
+	account.ID = uuid.New()
+	r.accounts = append(r.accounts, account)
+	return nil
+}
+
+func (r *serviceAccountRepository) GetServiceAccountByHash(ctx context.Context, keyHash string) (*domain.ServiceAccount, error) {
+	for _, account := range r.accounts {
+		if account.KeyHash == keyHash {
+			return account, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *serviceAccountRepository) TouchServiceAccount(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return nil
+}
+
+func (r *serviceAccountRepository) AddServiceAccountAuditEntry(ctx context.Context, entry *domain.ServiceAccountAuditEntry) error {
+	r.audit = append(r.audit, entry)
+	return nil
+}
+
+func TestServiceAccountOr(t *testing.T) {
+	jwtHandler := auth.NewJWT(auth.JWTConfig{Secret: "test-secret"})
+	repo := &serviceAccountRepository{}
+	accounts := service.NewServiceAccountService(repo, service.ServiceAccountServiceConfig{})
+	m := NewAuthMiddleware(service.NewAuthService(new(MockUserRepository), jwtHandler, service.AuthServiceConfig{}), nil)
+	m.SetServiceAccountService(accounts)
+
+	h := m.ServiceAccountOr(domain.ScopeUsersRead, m.PermissionRequired(domain.PermissionUsersRead))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Service accounts act as no user
+		_, err := GetUserIDFromContext(r.Context())
+		_, isAccount := GetServiceAccountFromContext(r.Context())
+		assert.Equal(t, isAccount, err != nil)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	call := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	ctx := context.Background()
+	adminID := uuid.New()
+	_, usersKey, err := accounts.CreateAccount(ctx, adminID, "Backup", "", []string{domain.ScopeUsersRead}, time.Time{})
+	require.NoError(t, err)
+	_, metricsKey, err := accounts.CreateAccount(ctx, adminID, "Grafana", "", []string{domain.ScopeMetricsRead}, time.Time{})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, call(usersKey))
+	assert.Equal(t, http.StatusForbidden, call(metricsKey))
+	assert.Equal(t, http.StatusUnauthorized, call(service.ServiceAccountKeyPrefix+"unknown"))
+
+	// Each request made by the account is audited
+	var requests []*domain.ServiceAccountAuditEntry
+	for _, entry := range repo.audit {
+		if entry.Action == domain.ServiceAccountActionRequest {
+			requests = append(requests, entry)
+		}
+	}
+	require.Len(t, requests, 1)
+	assert.Equal(t, repo.accounts[0].ID, requests[0].ServiceAccountID)
+	var details map[string]any
+	require.NoError(t, json.Unmarshal(requests[0].Details, &details))
+	assert.Equal(t, "/api/v1/admin/users", details["path"])
+	assert.EqualValues(t, http.StatusNoContent, details["status"])
+
+	// Other requests need a role granting the permission
+	adminToken, err := jwtHandler.GenerateAccessToken(adminID.String(), "admin@example.com", "admin")
+	require.NoError(t, err)
+	supportToken, err := jwtHandler.GenerateAccessToken(uuid.NewString(), "support@example.com", "support")
+	require.NoError(t, err)
+	userToken, err := jwtHandler.GenerateAccessToken(uuid.NewString(), "user@example.com", "user")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, call(adminToken))
+	assert.Equal(t, http.StatusNoContent, call(supportToken))
+	assert.Equal(t, http.StatusForbidden, call(userToken))
+
+	// Service account keys only work where they are accepted
+	user := m.AuthRequired(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "Bearer "+usersKey)
+	rr := httptest.NewRecorder()
+	user.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "INSUFFICIENT_SCOPE")
+}
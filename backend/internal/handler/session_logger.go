@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/rs/zerolog/log"
+)
+
+// redactedValue replaces sensitive values in logged request bodies
+const redactedValue = "[REDACTED]"
+
+// SessionLoggerConfig configures request logging
+type SessionLoggerConfig struct {
+	// BodySampleRate is the fraction of JSON request bodies logged, from 0
+	// (none) to 1 (all)
+	BodySampleRate float64
+	// MaxBodyBytes caps the size of a logged body. Larger bodies are left out
+	// rather than cut, since a cut body can't be parsed for redaction.
+	MaxBodyBytes int
+	// SlowRequestThreshold logs requests that take longer as warnings
+	SlowRequestThreshold time.Duration
+	// RedactFields are the body fields whose values are never logged. Any
+	// field whose name contains "password" is redacted as well.
+	RedactFields []string
+}
+
+// SessionLogger logs the requests to the API
+type SessionLogger struct {
+	config SessionLoggerConfig
+	redact map[string]bool
+	sample func() float64
+}
+
+// NewSessionLogger creates a new session logger
+func NewSessionLogger(config SessionLoggerConfig) *SessionLogger {
+	// Set default values if not provided
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = 4 << 10
+	}
+	if config.SlowRequestThreshold <= 0 {
+		config.SlowRequestThreshold = time.Second
+	}
+	if config.RedactFields == nil {
+		config.RedactFields = []string{"token", "refresh_token", "secret", "passphrase", "ciphertext", "code"}
+	}
+
+	redact := make(map[string]bool, len(config.RedactFields))
+	for _, field := range config.RedactFields {
+		redact[strings.ToLower(field)] = true
+	}
+
+	return &SessionLogger{
+		config: config,
+		redact: redact,
+		sample: rand.Float64,
+	}
+}
+
+// LogActivity middleware logs user activity: the request, the response status
+// and size, and a redacted sample of the request bodies. Requests slower than
+// the threshold are logged as warnings.
+func (l *SessionLogger) LogActivity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// Get claims from context if available
+		var userID, email, role string
+		if claims, ok := r.Context().Value(claimsContextKey).(*auth.JWTClaims); ok {
+			userID = claims.UserID
+			email = claims.Email
+			role = claims.Role
+		}
+
+		body := l.sampleBody(r)
+
+		// Create a custom response writer to capture the status code
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		// Process the request
+		next.ServeHTTP(rw, r)
+
+		// Log the activity
+		duration := time.Since(startTime)
+		event := log.Ctx(r.Context()).Info()
+		msg := "API request"
+		if duration > l.config.SlowRequestThreshold {
+			event = log.Ctx(r.Context()).Warn()
+			msg = "Slow API request"
+		}
+		event = event.
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Int("status", rw.statusCode).
+			Int64("request_bytes", r.ContentLength).
+			Int64("response_bytes", rw.bytes).
+			Str("user_id", userID).
+			Str("email", email).
+			Str("role", role).
+			Str("ip", getClientIP(r)).
+			Str("user_agent", r.UserAgent()).
+			Dur("duration", duration)
+		if body != nil {
+			event = event.RawJSON("request_body", body)
+		}
+		event.Msg(msg)
+	})
+}
+
+// sampleBody returns the redacted body of a sampled JSON request, or nil. The
+// body is read up to the size limit and put back for the handler.
+func (l *SessionLogger) sampleBody(r *http.Request) []byte {
+	if l.config.BodySampleRate <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil
+	}
+	if l.sample() >= l.config.BodySampleRate {
+		return nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(r.Body, int64(l.config.MaxBodyBytes)+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	if err != nil || len(head) > l.config.MaxBodyBytes {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(head, &value); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(l.redactValue(value))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// redactValue replaces the values of sensitive fields, at any depth
+func (l *SessionLogger) redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if l.sensitive(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = l.redactValue(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = l.redactValue(item)
+		}
+	}
+	return value
+}
+
+// sensitive reports whether a body field must not be logged
+func (l *SessionLogger) sensitive(field string) bool {
+	field = strings.ToLower(field)
+	return strings.Contains(field, "password") || l.redact[field]
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveLogged runs a request through the session logger and returns the
+// logged entry and the body the handler read
+func serveLogged(t *testing.T, l *SessionLogger, body string, handle func(w http.ResponseWriter)) (map[string]any, string) {
+	t.Helper()
+	var logs bytes.Buffer
+	logger := zerolog.New(&logs)
+
+	var received string
+	h := l.LogActivity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = string(data)
+		handle(w)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(logger.WithContext(req.Context()))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	return entry, received
+}
+
+func TestLogActivitySamplesRedactedBodies(t *testing.T) {
+	l := NewSessionLogger(SessionLoggerConfig{BodySampleRate: 1})
+	body := `{"email":"ada@example.com","password":"hunter22","nested":{"new_password":"x","token":"t","tags":["a"]}}`
+
+	entry, received := serveLogged(t, l, body, func(w http.ResponseWriter) {
+		RespondWithJSON(w, http.StatusCreated, MessageResponse{Message: "ok"})
+	})
+
+	// The handler still gets the whole body
+	assert.Equal(t, body, received)
+
+	assert.Equal(t, "info", entry["level"])
+	assert.EqualValues(t, http.StatusCreated, entry["status"])
+	assert.EqualValues(t, len(`{"message":"ok"}`), entry["response_bytes"])
+	assert.EqualValues(t, len(body), entry["request_bytes"])
+	assert.Equal(t, map[string]any{
+		"email":    "ada@example.com",
+		"password": redactedValue,
+		"nested":   map[string]any{"new_password": redactedValue, "token": redactedValue, "tags": []any{"a"}},
+	}, entry["request_body"])
+}
+
+func TestLogActivitySkipsUnsampledAndLargeBodies(t *testing.T) {
+	l := NewSessionLogger(SessionLoggerConfig{BodySampleRate: 0.5, MaxBodyBytes: 32})
+	l.sample = func() float64 { return 0.7 }
+
+	entry, _ := serveLogged(t, l, `{"email":"ada@example.com"}`, func(w http.ResponseWriter) {})
+	assert.NotContains(t, entry, "request_body")
+
+	l.sample = func() float64 { return 0.2 }
+	large := `{"email":"` + strings.Repeat("a", 64) + `"}`
+	entry, received := serveLogged(t, l, large, func(w http.ResponseWriter) {})
+	assert.NotContains(t, entry, "request_body")
+	assert.Equal(t, large, received)
+}
+
+func TestLogActivityWarnsOnSlowRequests(t *testing.T) {
+	l := NewSessionLogger(SessionLoggerConfig{SlowRequestThreshold: time.Millisecond})
+
+	entry, _ := serveLogged(t, l, `{}`, func(w http.ResponseWriter) {
+		time.Sleep(5 * time.Millisecond)
+	})
+	assert.Equal(t, "warn", entry["level"])
+	assert.Equal(t, "Slow API request", entry["message"])
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/rs/zerolog/log"
+)
+
+// SettingsHandler handles instance settings
+type SettingsHandler struct {
+	settingsService *service.SettingsService
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(settingsService *service.SettingsService) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: settingsService,
+	}
+}
+
+// SecurityHeadersResponse describes the security header settings and the
+// Content-Security-Policy they produce
+type SecurityHeadersResponse struct {
+	Settings security.HeadersSettings `json:"settings"`
+	// Policy is the Content-Security-Policy sent with responses
+	Policy string `json:"policy"`
+	// ReportOnly is set when the policy only reports violations
+	ReportOnly bool `json:"report_only"`
+}
+
+// GetSecurityHeadersHandler returns the security header settings (admin only)
+func (h *SettingsHandler) GetSecurityHeadersHandler(w http.ResponseWriter, r *http.Request) error {
+	settings, err := h.settingsService.SecurityHeaders(r.Context())
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to get security header settings")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to get security header settings")
+	}
+
+	RespondWithJSON(w, http.StatusOK, h.securityHeadersResponse(settings))
+	return nil
+}
+
+// UpdateSecurityHeadersHandler replaces the security header settings (admin only)
+func (h *SettingsHandler) UpdateSecurityHeadersHandler(w http.ResponseWriter, r *http.Request) error {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	var settings security.HeadersSettings
+	if err := decodeJSON(w, r, &settings); err != nil {
+		return decodeError(err)
+	}
+
+	if err := h.settingsService.UpdateSecurityHeaders(r.Context(), settings, adminID); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to update security header settings")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to update security header settings")
+	}
+
+	RespondWithJSON(w, http.StatusOK, h.securityHeadersResponse(settings))
+	return nil
+}
+
+// securityHeadersResponse describes settings with the policy they produce
+func (h *SettingsHandler) securityHeadersResponse(settings security.HeadersSettings) SecurityHeadersResponse {
+	config := h.settingsService.HeadersConfig(settings)
+	return SecurityHeadersResponse{
+		Settings:   settings,
+		Policy:     config.ContentSecurityPolicy,
+		ReportOnly: config.CSPReportOnly,
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// StatsHandler reports aggregate counts of the instance (admin only)
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetStatsHandler returns the counts of users, resumes, sessions, exports
+// and signups for an operations dashboard
+func (h *StatsHandler) GetStatsHandler(w http.ResponseWriter, r *http.Request) error {
+	stats, err := h.statsService.GetStats(r.Context())
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to get admin stats")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to get stats")
+	}
+
+	RespondWithJSON(w, http.StatusOK, stats)
+	return nil
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// StatusHandler handles the public status page and incident management
+type StatusHandler struct {
+	statusService *service.StatusService
+	incidentRepo  domain.IncidentRepository
+}
+
+// NewStatusHandler creates a new status handler
+func NewStatusHandler(statusService *service.StatusService, incidentRepo domain.IncidentRepository) *StatusHandler {
+	return &StatusHandler{
+		statusService: statusService,
+		incidentRepo:  incidentRepo,
+	}
+}
+
+// GetStatusHandler serves aggregate uptime and incident information
+func (h *StatusHandler) GetStatusHandler(w http.ResponseWriter, r *http.Request) error {
+	page, err := h.statusService.Status(r.Context())
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to build status page")
+		return apperror.New(http.StatusServiceUnavailable, "STATUS_UNAVAILABLE", "Status unavailable")
+	}
+
+	// Allow short-lived caching since the page is public and probes run every minute
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	RespondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// GetIncidentsHandler handles listing all incidents (admin only)
+func (h *StatusHandler) GetIncidentsHandler(w http.ResponseWriter, r *http.Request) error {
+	incidents, err := h.incidentRepo.GetIncidentsSince(r.Context(), time.Time{})
+	if err != nil {
+		return apperror.Internal(err, "Failed to get incidents")
+	}
+
+	RespondWithJSON(w, http.StatusOK, IncidentsResponse{Incidents: incidents})
+	return nil
+}
+
+// CreateIncidentHandler handles opening a new incident (admin only)
+func (h *StatusHandler) CreateIncidentHandler(w http.ResponseWriter, r *http.Request) error {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	var incident domain.Incident
+	if err := decodeJSON(w, r, &incident); err != nil {
+		return decodeError(err)
+	}
+
+	incident.BeforeSave()
+
+	if err := incident.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+	}
+
+	incident.ID = uuid.Nil
+	incident.CreatedBy = userID
+
+	if err := h.incidentRepo.CreateIncident(r.Context(), &incident); err != nil {
+		return apperror.Internal(err, "Failed to create incident")
+	}
+
+	RespondWithJSON(w, http.StatusCreated, incident)
+	return nil
+}
+
+// UpdateIncidentRequest represents a partial incident update; nil fields are left unchanged
+type UpdateIncidentRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Status      *string `json:"status"`
+	Impact      *string `json:"impact"`
+}
+
+// UpdateIncidentHandler handles updating an incident, including resolving it (admin only)
+func (h *StatusHandler) UpdateIncidentHandler(w http.ResponseWriter, r *http.Request) error {
+	incidentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid incident ID")
+	}
+
+	incident, err := h.incidentRepo.GetIncidentByID(r.Context(), incidentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Incident not found")
+		}
+		return apperror.Internal(err, "Failed to get incident")
+	}
+
+	// Fields missing from the body keep their current values
+	var update UpdateIncidentRequest
+	if err := decodeJSON(w, r, &update); err != nil {
+		return decodeError(err)
+	}
+
+	if update.Title != nil {
+		incident.Title = *update.Title
+	}
+	if update.Description != nil {
+		incident.Description = *update.Description
+	}
+	if update.Status != nil {
+		incident.Status = *update.Status
+	}
+	if update.Impact != nil {
+		incident.Impact = *update.Impact
+	}
+
+	incident.BeforeSave()
+
+	if err := incident.Validate(); err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+	}
+
+	if err := h.incidentRepo.UpdateIncident(r.Context(), incident); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Incident not found")
+		}
+		return apperror.Internal(err, "Failed to update incident")
+	}
+
+	RespondWithJSON(w, http.StatusOK, incident)
+	return nil
+}
+
+// DeleteIncidentHandler handles deleting an incident (admin only)
+func (h *StatusHandler) DeleteIncidentHandler(w http.ResponseWriter, r *http.Request) error {
+	incidentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid incident ID")
+	}
+
+	if err := h.incidentRepo.DeleteIncident(r.Context(), incidentID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Incident not found")
+		}
+		return apperror.Internal(err, "Failed to delete incident")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Incident deleted successfully"})
+	return nil
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/ai"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+)
+
+// SuggestionHandler drafts resume content with a language model
+type SuggestionHandler struct {
+	resumeRepo domain.ResumeRepository
+	provider   ai.Provider
+}
+
+// NewSuggestionHandler creates a new suggestion handler
+func NewSuggestionHandler(resumeRepo domain.ResumeRepository, provider ai.Provider) *SuggestionHandler {
+	return &SuggestionHandler{
+		resumeRepo: resumeRepo,
+		provider:   provider,
+	}
+}
+
+// BulletSuggestionsResponse holds achievement bullets proposed for an
+// experience entry
+type BulletSuggestionsResponse struct {
+	Bullets []string `json:"bullets"`
+}
+
+// SuggestBulletsHandler proposes achievement bullets for an experience entry,
+// written from its description in the resume's language. Nothing is stored:
+// the client saves the bullets it keeps.
+func (h *SuggestionHandler) SuggestBulletsHandler(w http.ResponseWriter, r *http.Request) error {
+	resume, err := GetResumeFromContext(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get resume")
+	}
+
+	experienceID, err := uuid.Parse(r.PathValue("experienceId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid experience ID")
+	}
+
+	experience, err := h.resumeRepo.GetExperience(r.Context(), resume.ID, experienceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Experience entry not found")
+		}
+		return apperror.Internal(err, "Failed to get experience entry")
+	}
+	if strings.TrimSpace(experience.Description) == "" && len(experience.Achievements) == 0 {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Add a description to the experience entry first")
+	}
+
+	language := resume.Language
+	if language == "" {
+		language = "en"
+	}
+	bullets, err := ai.SuggestBullets(r.Context(), h.provider, experience, language)
+	if err != nil {
+		return apperror.Wrap(err, http.StatusBadGateway, "AI_UNAVAILABLE", "Suggestions are unavailable, try again later")
+	}
+
+	RespondWithJSON(w, http.StatusOK, BulletSuggestionsResponse{Bullets: bullets})
+	return nil
+}
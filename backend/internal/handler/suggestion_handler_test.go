@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/ai"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// experienceRepository serves the experience entries of one resume
+type experienceRepository struct {
+	domain.ResumeRepository
+	resumeID   uuid.UUID
+	experience map[uuid.UUID]*domain.Experience
+}
+
+func (r *experienceRepository) GetExperience(ctx context.Context, resumeID, id uuid.UUID) (*domain.Experience, error) {
+	if e, ok := r.experience[id]; ok && resumeID == r.resumeID {
+		return e, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+// replyProvider replies with a fixed text, or fails with err
+type replyProvider struct {
+	reply string
+	err   error
+}
+
+func (p *replyProvider) Complete(ctx context.Context, messages []ai.Message) (string, error) {
+	return p.reply, p.err
+}
+
+func TestSuggestBulletsHandler(t *testing.T) {
+	resume := &domain.Resume{ID: uuid.New()}
+	described, empty := uuid.New(), uuid.New()
+	repo := &experienceRepository{resumeID: resume.ID, experience: map[uuid.UUID]*domain.Experience{
+		described: {JobTitle: "Engineer", Employer: "Initech", Description: "Maintained the billing service."},
+		empty:     {JobTitle: "Intern", Employer: "Initech"},
+	}}
+	provider := &replyProvider{reply: "- Cut billing errors by half\n- Automated invoice runs"}
+	h := NewSuggestionHandler(repo, provider)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /api/v1/resumes/{id}/experience/{experienceId}/suggest", HandlerFunc(h.SuggestBulletsHandler))
+	post := func(experienceID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/resumes/"+resume.ID.String()+"/experience/"+experienceID+"/suggest", nil)
+		req = req.WithContext(context.WithValue(req.Context(), resumeContextKey, resume))
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post(described.String())
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body BulletSuggestionsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, []string{"Cut billing errors by half", "Automated invoice runs"}, body.Bullets)
+
+	assert.Equal(t, http.StatusBadRequest, post(empty.String()).Code)
+	assert.Equal(t, http.StatusBadRequest, post("not-a-uuid").Code)
+	assert.Equal(t, http.StatusNotFound, post(uuid.NewString()).Code)
+
+	provider.err = errors.New("connection refused")
+	assert.Equal(t, http.StatusBadGateway, post(described.String()).Code)
+}
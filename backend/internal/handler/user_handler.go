@@ -3,10 +3,13 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
 	"github.com/lordaris/resume_generator/internal/domain"
 	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/locale"
 	"github.com/rs/zerolog/log"
 )
 
@@ -24,70 +27,242 @@ func NewUserHandler(userRepo domain.UserRepository, resumeRepo domain.ResumeRepo
 	}
 }
 
+// ProfileResponse is the authenticated user's profile with a summary of their resumes
+type ProfileResponse struct {
+	UserID    string          `json:"user_id"`
+	Email     string          `json:"email"`
+	Role      string          `json:"role"`
+	Timezone  string          `json:"timezone"`
+	CreatedAt string          `json:"created_at"`
+	Resumes   []ProfileResume `json:"resumes,omitempty"`
+}
+
+// ProfileResume summarizes one resume in a profile
+type ProfileResume struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SessionInfo describes an active session. Refresh tokens are never exposed,
+// only session metadata.
+type SessionInfo struct {
+	ID        string `json:"id"`
+	UserAgent string `json:"user_agent"`
+	ClientIP  string `json:"client_ip"`
+	CreatedAt string `json:"created_at"`
+	// LastUsedAt is when the session was last refreshed
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+// PreferencesRequest updates the user's preferences; omitted preferences are left unchanged
+type PreferencesRequest struct {
+	// Timezone is an IANA time zone such as "Europe/Berlin"
+	Timezone string `json:"timezone,omitempty"`
+	// WeeklyDigest turns the weekly activity digest email on or off
+	WeeklyDigest *bool `json:"weekly_digest,omitempty"`
+	// Locale is a BCP 47 tag such as "en-US" new resumes are formatted for:
+	// its region picks their paper size
+	Locale string `json:"locale,omitempty"`
+}
+
+// PreferencesResponse holds the user's preferences
+type PreferencesResponse struct {
+	Timezone     string `json:"timezone"`
+	WeeklyDigest bool   `json:"weekly_digest"`
+	Locale       string `json:"locale"`
+}
+
 // GetProfileHandler handles fetching the user profile
-func (h *UserHandler) GetProfileHandler(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) GetProfileHandler(w http.ResponseWriter, r *http.Request) error {
 	claims, err := GetClaimsFromContext(r.Context())
 	if err != nil {
-		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-		return
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
 	}
 
 	// Get user ID from claims
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Invalid user ID")
 	}
 
 	// Get user from repository
-	user, err := h.userRepo.GetUserByID(userID)
+	user, err := h.userRepo.GetUserByID(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			RespondWithError(w, http.StatusNotFound, "User not found", "NOT_FOUND")
-			return
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "User not found")
 		}
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get user profile", "INTERNAL_SERVER_ERROR")
-		return
+		return apperror.Internal(err, "Failed to get user profile")
 	}
 
 	// Get user's resumes
-	resumes, err := h.resumeRepo.GetResumesByUserID(userID)
+	resumes, err := h.resumeRepo.GetResumesByUserID(r.Context(), userID)
 	if err != nil {
-		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get user resumes")
+		log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get user resumes")
 		// Continue without resumes
 	}
 
 	// Create response with user profile and resume list
-	type resumeInfo struct {
-		ID        string `json:"id"`
-		CreatedAt string `json:"created_at"`
-	}
-
-	type profileResponse struct {
-		UserID    string       `json:"user_id"`
-		Email     string       `json:"email"`
-		Role      string       `json:"role"`
-		CreatedAt string       `json:"created_at"`
-		Resumes   []resumeInfo `json:"resumes,omitempty"`
-	}
-
-	response := profileResponse{
+	response := ProfileResponse{
 		UserID:    user.ID.String(),
 		Email:     user.Email,
 		Role:      user.Role,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		Timezone:  user.Timezone,
+		CreatedAt: user.CreatedAt.UTC().Format(time.RFC3339),
 	}
 
 	if resumes != nil {
-		resumeList := make([]resumeInfo, len(resumes))
+		resumeList := make([]ProfileResume, len(resumes))
 		for i, resume := range resumes {
-			resumeList[i] = resumeInfo{
+			resumeList[i] = ProfileResume{
 				ID:        resume.ID.String(),
-				CreatedAt: resume.CreatedAt.Format("2006-01-02T15:04:05Z"),
+				CreatedAt: resume.CreatedAt.UTC().Format(time.RFC3339),
 			}
 		}
 		response.Resumes = resumeList
 	}
 
 	RespondWithJSON(w, http.StatusOK, response)
+	return nil
+}
+
+// GetSessionsHandler handles listing the user's active sessions
+func (h *UserHandler) GetSessionsHandler(w http.ResponseWriter, r *http.Request) error {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	// Get user ID from claims
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	sessions, err := h.userRepo.GetSessionsByUserID(r.Context(), userID)
+	if err != nil {
+		return apperror.Internal(err, "Failed to get sessions")
+	}
+
+	now := time.Now().UTC()
+	sessionList := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		// Skip sessions that have expired but not yet been purged
+		if session.ExpiresAt.Before(now) {
+			continue
+		}
+		sessionList = append(sessionList, SessionInfo{
+			ID:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			ClientIP:   session.ClientIP,
+			CreatedAt:  session.CreatedAt.UTC().Format(time.RFC3339),
+			LastUsedAt: session.LastUsedAt.UTC().Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	RespondWithJSON(w, http.StatusOK, SessionsResponse{Sessions: sessionList})
+	return nil
+}
+
+// RevokeSessionHandler handles revoking one of the user's sessions
+func (h *UserHandler) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) error {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	// Get user ID from claims
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	// Get session ID from path
+	sessionID, err := uuid.Parse(r.PathValue("sessionId"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid session ID")
+	}
+
+	session, err := h.userRepo.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Session not found")
+		}
+		return apperror.Internal(err, "Failed to get session")
+	}
+
+	// Other users' sessions are reported as missing rather than forbidden
+	if session.UserID != userID {
+		return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Session not found")
+	}
+
+	if err := h.userRepo.DeleteSession(r.Context(), sessionID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Session not found")
+		}
+		return apperror.Internal(err, "Failed to revoke session")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "Session revoked successfully"})
+	return nil
+}
+
+// UpdatePreferencesHandler handles updating the user's preferences
+func (h *UserHandler) UpdatePreferencesHandler(w http.ResponseWriter, r *http.Request) error {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	// Get user ID from claims
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return apperror.Internal(err, "Invalid user ID")
+	}
+
+	var req PreferencesRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+	if req.Timezone == "" && req.WeeklyDigest == nil && req.Locale == "" {
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "No preferences given")
+	}
+	if req.Timezone != "" {
+		if err := domain.ValidateTimezone(req.Timezone); err != nil {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+	}
+	if req.Locale != "" {
+		normalized, err := locale.Normalize(req.Locale)
+		if err != nil {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "Locale must be a language tag such as \"en-US\"")
+		}
+		req.Locale = normalized
+	}
+
+	user, err := h.userRepo.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "User not found")
+		}
+		return apperror.Internal(err, "Failed to get user")
+	}
+
+	if req.Timezone != "" {
+		user.Timezone = req.Timezone
+	}
+	if req.WeeklyDigest != nil {
+		user.WeeklyDigest = *req.WeeklyDigest
+	}
+	if req.Locale != "" {
+		user.Locale = req.Locale
+	}
+	if err := h.userRepo.UpdateUser(r.Context(), user); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to update preferences")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to update preferences")
+	}
+
+	RespondWithJSON(w, http.StatusOK, PreferencesResponse{Timezone: user.Timezone, WeeklyDigest: user.WeeklyDigest, Locale: user.Locale})
+	return nil
 }
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withClaims attaches authentication claims to a request as AuthRequired would
+func withClaims(req *http.Request, userID uuid.UUID) *http.Request {
+	claims := &auth.JWTClaims{UserID: userID.String(), Role: "user"}
+	return req.WithContext(context.WithValue(req.Context(), claimsContextKey, claims))
+}
+
+func TestGetSessionsHandler(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	handler := NewUserHandler(mockRepo, nil)
+
+	userID := uuid.New()
+	active := &domain.Session{
+		ID:           uuid.New(),
+		UserID:       userID,
+		RefreshToken: "secret-refresh-token",
+		UserAgent:    "Mozilla/5.0",
+		ClientIP:     "203.0.113.7",
+		CreatedAt:    time.Now().Add(-time.Hour),
+		LastUsedAt:   time.Now().Add(-time.Minute),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	expired := &domain.Session{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: time.Now().Add(-48 * time.Hour),
+		ExpiresAt: time.Now().Add(-24 * time.Hour),
+	}
+	mockRepo.On("GetSessionsByUserID", userID).Return([]*domain.Session{active, expired}, nil)
+
+	req := withClaims(httptest.NewRequest("GET", "/api/v1/user/sessions", nil), userID)
+	rr := httptest.NewRecorder()
+	HandlerFunc(handler.GetSessionsHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "secret-refresh-token")
+
+	var body struct {
+		Sessions []map[string]string `json:"sessions"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	require.Len(t, body.Sessions, 1)
+	assert.Equal(t, active.ID.String(), body.Sessions[0]["id"])
+	assert.Equal(t, "203.0.113.7", body.Sessions[0]["client_ip"])
+	assert.Equal(t, active.LastUsedAt.Format(time.RFC3339), body.Sessions[0]["last_used_at"])
+}
+
+func TestRevokeSessionHandler(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	testCases := []struct {
+		name           string
+		owner          uuid.UUID
+		expectedStatus int
+	}{
+		{name: "Own session", owner: userID, expectedStatus: http.StatusOK},
+		{name: "Another user's session", owner: uuid.New(), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			handler := NewUserHandler(mockRepo, nil)
+
+			mockRepo.On("GetSessionByID", sessionID).Return(&domain.Session{ID: sessionID, UserID: tc.owner}, nil)
+			mockRepo.On("DeleteSession", sessionID).Return(nil)
+
+			req := httptest.NewRequest("DELETE", "/api/v1/user/sessions/"+sessionID.String(), nil)
+			req.SetPathValue("sessionId", sessionID.String())
+			rr := httptest.NewRecorder()
+			HandlerFunc(handler.RevokeSessionHandler).ServeHTTP(rr, withClaims(req, userID))
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedStatus == http.StatusOK {
+				mockRepo.AssertCalled(t, "DeleteSession", sessionID)
+			} else {
+				mockRepo.AssertNotCalled(t, "DeleteSession", sessionID)
+			}
+		})
+	}
+}
+
+func TestUpdatePreferencesHandler(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	handler := NewUserHandler(mockRepo, nil)
+
+	userID := uuid.New()
+	user := &domain.User{ID: userID, Email: "user@example.com", Timezone: domain.DefaultTimezone}
+	mockRepo.On("GetUserByID", userID).Return(user, nil)
+	mockRepo.On("UpdateUser", user).Return(nil)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := withClaims(httptest.NewRequest("PUT", "/api/v1/user/preferences", strings.NewReader(body)), userID)
+		rr := httptest.NewRecorder()
+		HandlerFunc(handler.UpdatePreferencesHandler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(`{"timezone":"America/Argentina/Buenos_Aires"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "America/Argentina/Buenos_Aires", user.Timezone)
+
+	// Preferences that aren't given are left unchanged
+	rr = serve(`{"weekly_digest":true}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, user.WeeklyDigest)
+	assert.Equal(t, "America/Argentina/Buenos_Aires", user.Timezone)
+	assert.JSONEq(t, `{"timezone":"America/Argentina/Buenos_Aires","weekly_digest":true,"locale":""}`, rr.Body.String())
+
+	rr = serve(`{"locale":"es_mx"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "es-MX", user.Locale)
+
+	for _, body := range []string{`{"timezone":"Mars/Olympus_Mons"}`, `{"timezone":"Local"}`, `{"locale":"not a locale"}`, `{}`} {
+		rr := serve(body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, "body %s", body)
+	}
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 3)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// maxRosterSize caps the size of an uploaded CSV roster
+const maxRosterSize = 1 << 20
+
+// UserImportHandler handles bulk account imports (admin only)
+type UserImportHandler struct {
+	importService *service.UserImportService
+}
+
+// NewUserImportHandler creates a new user import handler
+func NewUserImportHandler(importService *service.UserImportService) *UserImportHandler {
+	return &UserImportHandler{
+		importService: importService,
+	}
+}
+
+// CreateImportHandler starts importing the CSV roster in the request body
+func (h *UserImportHandler) CreateImportHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "Unauthorized")
+	}
+
+	roster, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRosterSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return apperror.New(http.StatusRequestEntityTooLarge, apperror.CodeInvalidRequest, "Roster too large")
+		}
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid request body")
+	}
+
+	imp, err := h.importService.StartImport(r.Context(), userID, bytes.NewReader(roster))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRoster) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to start user import")
+		return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "Failed to start import")
+	}
+
+	RespondWithJSON(w, http.StatusAccepted, imp)
+	return nil
+}
+
+// GetImportHandler returns an import and the results of its rows so far
+func (h *UserImportHandler) GetImportHandler(w http.ResponseWriter, r *http.Request) error {
+	importID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid import ID")
+	}
+
+	imp, err := h.importService.GetImport(r.Context(), importID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserImportNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Import not found")
+		}
+		return apperror.Internal(err, "Failed to get import")
+	}
+
+	RespondWithJSON(w, http.StatusOK, imp)
+	return nil
+}
+
+// GetImportErrorsHandler downloads the failed rows of an import as CSV
+func (h *UserImportHandler) GetImportErrorsHandler(w http.ResponseWriter, r *http.Request) error {
+	importID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid import ID")
+	}
+
+	report, err := h.importService.ErrorReport(r.Context(), importID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserImportNotFound) {
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "Import not found")
+		}
+		return apperror.Internal(err, "Failed to get import errors")
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="import-`+importID.String()+`-errors.csv"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(report)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(report); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to write import error report")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserImportHandlers(t *testing.T) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	mr := miniredis.RunT(t)
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})})
+	h := NewUserImportHandler(service.NewUserImportService(new(MockUserRepository), nil, store, storage.NewMemory(), queue, nil, service.UserImportServiceConfig{}))
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /api/v1/admin/user-imports", HandlerFunc(h.CreateImportHandler))
+	mux.Handle("GET /api/v1/admin/user-imports/{id}", HandlerFunc(h.GetImportHandler))
+	mux.Handle("GET /api/v1/admin/user-imports/{id}/errors", HandlerFunc(h.GetImportErrorsHandler))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, withClaims(req, uuid.New()))
+		return rr
+	}
+
+	// Rosters without an email column are refused outright
+	rr := serve(httptest.NewRequest(http.MethodPost, "/api/v1/admin/user-imports", strings.NewReader("name\nAda\n")))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serve(httptest.NewRequest(http.MethodPost, "/api/v1/admin/user-imports", strings.NewReader(strings.Repeat("x", maxRosterSize+1))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	rr = serve(httptest.NewRequest(http.MethodPost, "/api/v1/admin/user-imports", strings.NewReader("email\nada@example.com\n")))
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	var imp service.UserImport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &imp))
+	assert.Equal(t, service.UserImportStatusPending, imp.Status)
+	assert.Equal(t, 1, imp.Total)
+
+	rr = serve(httptest.NewRequest(http.MethodGet, "/api/v1/admin/user-imports/"+imp.ID.String(), nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(httptest.NewRequest(http.MethodGet, "/api/v1/admin/user-imports/"+imp.ID.String()+"/errors", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "line,email,error\n", rr.Body.String())
+
+	rr = serve(httptest.NewRequest(http.MethodGet, "/api/v1/admin/user-imports/"+uuid.NewString(), nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/apperror"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// UserRoleHandler handles user roles and the permissions they grant
+type UserRoleHandler struct {
+	permissionService *service.PermissionService
+	authService       *service.AuthService
+}
+
+// NewUserRoleHandler creates a new user role handler
+func NewUserRoleHandler(permissionService *service.PermissionService, authService *service.AuthService) *UserRoleHandler {
+	return &UserRoleHandler{
+		permissionService: permissionService,
+		authService:       authService,
+	}
+}
+
+// UserRolesResponse lists the user roles and the permissions roles can grant
+type UserRolesResponse struct {
+	Roles       []*domain.UserRole `json:"roles"`
+	Permissions []string           `json:"permissions"`
+}
+
+// SetUserRoleRequest is the request body for changing a user's role
+type SetUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// ListUserRolesHandler lists the user roles
+func (h *UserRoleHandler) ListUserRolesHandler(w http.ResponseWriter, r *http.Request) error {
+	roles, err := h.permissionService.Roles(r.Context())
+	if err != nil {
+		return apperror.Internal(err, "Failed to get user roles")
+	}
+
+	RespondWithJSON(w, http.StatusOK, UserRolesResponse{Roles: roles, Permissions: domain.Permissions})
+	return nil
+}
+
+// SaveUserRoleHandler creates the role named in the path or replaces its
+// description and permissions. Users with the role gain or lose permissions
+// on their next request.
+func (h *UserRoleHandler) SaveUserRoleHandler(w http.ResponseWriter, r *http.Request) error {
+	var role domain.UserRole
+	if err := decodeJSON(w, r, &role); err != nil {
+		return decodeError(err)
+	}
+	role.Name = r.PathValue("name")
+
+	if err := h.permissionService.SaveRole(r.Context(), &role); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, err.Error())
+		}
+		if errors.Is(err, service.ErrBuiltinRole) {
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "The admin role always grants every permission")
+		}
+		return apperror.Internal(err, "Failed to save user role")
+	}
+
+	RespondWithJSON(w, http.StatusOK, &role)
+	return nil
+}
+
+// DeleteUserRoleHandler deletes a role no user has
+func (h *UserRoleHandler) DeleteUserRoleHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := h.permissionService.DeleteRole(r.Context(), r.PathValue("name")); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserRoleNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "User role not found")
+		case errors.Is(err, service.ErrBuiltinRole):
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "The user and admin roles can't be deleted")
+		case errors.Is(err, repository.ErrRoleInUse):
+			return apperror.New(http.StatusConflict, "ROLE_IN_USE", "Users still have this role")
+		}
+		return apperror.Internal(err, "Failed to delete user role")
+	}
+
+	RespondWithJSON(w, http.StatusOK, MessageResponse{Message: "User role deleted successfully"})
+	return nil
+}
+
+// SetUserRoleHandler changes a user's role and signs them out everywhere, so
+// their new tokens carry the role
+func (h *UserRoleHandler) SetUserRoleHandler(w http.ResponseWriter, r *http.Request) error {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidRequest, "Invalid user ID")
+	}
+
+	var req SetUserRoleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		return decodeError(err)
+	}
+
+	user, err := h.permissionService.SetUserRole(r.Context(), userID, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserRoleNotFound):
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidation, "User role not found")
+		case errors.Is(err, repository.ErrNotFound):
+			return apperror.New(http.StatusNotFound, apperror.CodeNotFound, "User not found")
+		}
+		return apperror.Internal(err, "Failed to update user role")
+	}
+
+	// Minimal tokens resolve the role through the claims cache
+	h.authService.ForgetClaimsUser(r.Context(), userID)
+	if err := h.authService.LogoutAll(r.Context(), userID); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to revoke tokens after role change")
+	}
+
+	log.Ctx(r.Context()).Info().Str("user_id", userID.String()).Str("role", user.Role).Msg("Admin changed user role")
+	RespondWithJSON(w, http.StatusOK, user)
+	return nil
+}
@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Job event statuses
+const (
+	EventStatusQueued    = "queued"
+	EventStatusRunning   = "running"
+	EventStatusSucceeded = "succeeded"
+	EventStatusFailed    = "failed"
+)
+
+// Event is a status update about a job started on behalf of a user
+type Event struct {
+	JobID   string `json:"job_id,omitempty"`
+	JobType string `json:"job_type"`
+	Status  string `json:"status"`
+	// Progress is the share of the work done, in percent, while the job runs
+	Progress int    `json:"progress,omitempty"`
+	Message  string `json:"message,omitempty"`
+	// Attempt counts from 1 and grows when a failed job is retried
+	Attempt int       `json:"attempt,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// Subscription receives the job events of one user until it is closed
+type Subscription struct {
+	pubsub *redis.PubSub
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription is.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return s.pubsub.Close()
+}
+
+// PublishEvent sends a job event to the user's subscribers. Events are not
+// stored: users who aren't listening miss them and should poll the job's
+// status endpoint instead.
+func (q *Queue) PublishEvent(ctx context.Context, userID uuid.UUID, event Event) error {
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return q.redis.Publish(ctx, q.eventsChannel(userID), data).Err()
+}
+
+// SubscribeEvents subscribes to the job events of a user. The subscription is
+// established when SubscribeEvents returns, so no event published afterwards
+// is missed.
+func (q *Queue) SubscribeEvents(ctx context.Context, userID uuid.UUID) (*Subscription, error) {
+	pubsub := q.redis.Subscribe(ctx, q.eventsChannel(userID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	sub := &Subscription{pubsub: pubsub, events: make(chan Event), done: make(chan struct{})}
+	go func() {
+		defer close(sub.events)
+		for msg := range pubsub.Channel() {
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Ctx(ctx).Error().Err(err).Str("channel", msg.Channel).Msg("Failed to decode job event")
+				continue
+			}
+			select {
+			case sub.events <- event:
+			case <-sub.done:
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// eventsChannel returns the pub/sub channel carrying a user's job events
+func (q *Queue) eventsChannel(userID uuid.UUID) string {
+	return q.key("events:" + userID.String())
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueEvents(t *testing.T) {
+	queue, _ := setupQueue(t)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	sub, err := queue.SubscribeEvents(ctx, userID)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	// Events of other users are not delivered
+	require.NoError(t, queue.PublishEvent(ctx, uuid.New(), Event{JobType: "test.job", Status: EventStatusQueued}))
+	require.NoError(t, queue.PublishEvent(ctx, userID, Event{JobID: "1", JobType: "test.job", Status: EventStatusRunning, Progress: 50}))
+
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, "1", event.JobID)
+		assert.Equal(t, EventStatusRunning, event.Status)
+		assert.Equal(t, 50, event.Progress)
+		assert.False(t, event.At.IsZero())
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	// Closing the subscription closes its channel
+	require.NoError(t, sub.Close())
+	select {
+	case _, ok := <-sub.Events():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("events channel not closed")
+	}
+}
@@ -0,0 +1,525 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Default queue parameters
+const (
+	DefaultMaxAttempts        = 5
+	DefaultVisibilityTimeout  = 5 * time.Minute
+	DefaultMaxRunningPerOwner = 2
+	// defaultKeyPrefix is a hash tag, so Redis Cluster keeps every queue key
+	// in one slot and the scripts may touch several at once
+	defaultKeyPrefix = "{jobs}"
+	// claimScanLimit bounds how many due jobs of each priority a dequeue looks
+	// at for one whose owner is under the running cap
+	claimScanLimit = 100
+)
+
+// Priority orders due jobs; higher priorities are claimed first
+type Priority int
+
+// Job priorities
+const (
+	// PriorityLow is for scheduled and batch work nobody is waiting on
+	PriorityLow Priority = -1
+	// PriorityNormal is the default
+	PriorityNormal Priority = 0
+	// PriorityHigh is for work a user started and is waiting for
+	PriorityHigh Priority = 1
+)
+
+// priorities lists the priorities in the order jobs are claimed
+var priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// String returns the name of the priority
+func (p Priority) String() string {
+	switch {
+	case p > PriorityNormal:
+		return "high"
+	case p < PriorityNormal:
+		return "low"
+	default:
+		return "normal"
+	}
+}
+
+// ErrJobNotFound is returned when a job's data is missing from the queue
+var ErrJobNotFound = errors.New("job not found")
+
+// abandonedJobError is the last error of jobs whose worker stopped before they finished
+const abandonedJobError = "worker stopped before the job finished"
+
+// Job is a unit of background work
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	EnqueuedAt  time.Time       `json:"enqueued_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	FailedAt    time.Time       `json:"failed_at,omitzero"`
+	Priority    Priority        `json:"priority,omitempty"`
+	// Owner is who the job runs for, usually a user ID. Each owner has at
+	// most the queue's running cap of jobs in flight at once.
+	Owner string `json:"owner,omitempty"`
+}
+
+// DecodePayload unmarshals the job payload into v
+func (j *Job) DecodePayload(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// EnqueueOption customizes a job when it is enqueued
+type EnqueueOption func(*Job)
+
+// WithDelay schedules the job to run after the given delay
+func WithDelay(delay time.Duration) EnqueueOption {
+	return func(j *Job) {
+		j.RunAt = j.EnqueuedAt.Add(delay)
+	}
+}
+
+// WithRunAt schedules the job to run at the given time
+func WithRunAt(runAt time.Time) EnqueueOption {
+	return func(j *Job) {
+		j.RunAt = runAt
+	}
+}
+
+// WithMaxAttempts overrides the number of attempts before the job is dead-lettered
+func WithMaxAttempts(attempts int) EnqueueOption {
+	return func(j *Job) {
+		j.MaxAttempts = attempts
+	}
+}
+
+// WithPriority sets the priority of the job
+func WithPriority(priority Priority) EnqueueOption {
+	return func(j *Job) {
+		j.Priority = priority
+	}
+}
+
+// WithOwner counts the job against an owner's running cap
+func WithOwner(owner string) EnqueueOption {
+	return func(j *Job) {
+		j.Owner = owner
+	}
+}
+
+// QueueConfig contains configuration options for the job queue
+type QueueConfig struct {
+	// Redis is the Redis client backing the queue
+	Redis redis.UniversalClient
+	// KeyPrefix namespaces all queue keys. On Redis Cluster it must be a hash
+	// tag such as "{jobs}", as the queue's scripts touch several keys at once.
+	KeyPrefix string
+	// VisibilityTimeout is how long a dequeued job may run before it is handed to another worker
+	VisibilityTimeout time.Duration
+	// MaxRunningPerOwner caps the jobs in flight for one owner, so one account
+	// can't take up every worker. Jobs over the cap wait while others run.
+	MaxRunningPerOwner int
+}
+
+// Queue is a Redis-backed scheduled job queue.
+//
+// Job bodies live in a hash, pending jobs in a sorted set per priority scored
+// by run time, in-flight jobs in a sorted set scored by their visibility
+// deadline, and jobs that exhausted their attempts in a dead-letter list. The
+// owners of jobs and the number each has in flight are kept in two more hashes.
+type Queue struct {
+	redis              redis.UniversalClient
+	prefix             string
+	visibilityTimeout  time.Duration
+	maxRunningPerOwner int
+}
+
+// NewQueue creates a new job queue
+func NewQueue(config QueueConfig) *Queue {
+	if config.Redis == nil {
+		panic("Redis client is required for the job queue")
+	}
+
+	// Set defaults if not provided
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaultKeyPrefix
+	}
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	if config.MaxRunningPerOwner <= 0 {
+		config.MaxRunningPerOwner = DefaultMaxRunningPerOwner
+	}
+
+	return &Queue{
+		redis:              config.Redis,
+		prefix:             config.KeyPrefix,
+		visibilityTimeout:  config.VisibilityTimeout,
+		maxRunningPerOwner: config.MaxRunningPerOwner,
+	}
+}
+
+// Enqueue adds a job of the given type to the queue
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, opts ...EnqueueOption) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: DefaultMaxAttempts,
+		RunAt:       now,
+		EnqueuedAt:  now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	if err := q.schedule(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// DeadLetters returns the most recent jobs that exhausted their attempts
+func (q *Queue) DeadLetters(ctx context.Context, limit int64) ([]*Job, error) {
+	items, err := q.redis.LRange(ctx, q.key("dead"), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(items))
+	for _, item := range items {
+		var job Job
+		if err := json.Unmarshal([]byte(item), &job); err != nil {
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
+
+// QueueStats describes the backlog of the queue
+type QueueStats struct {
+	Priorities []PriorityStats `json:"priorities"`
+	// Processing is the number of jobs in flight
+	Processing int64 `json:"processing"`
+	// BusyOwners is the number of owners with jobs in flight
+	BusyOwners  int64 `json:"busy_owners"`
+	DeadLetters int64 `json:"dead_letters"`
+}
+
+// PriorityStats describes the jobs waiting at one priority
+type PriorityStats struct {
+	Priority string `json:"priority"`
+	// Depth counts the waiting jobs, including those scheduled for later
+	Depth int64 `json:"depth"`
+	// Due counts the jobs that could run now
+	Due int64 `json:"due"`
+	// OldestWaitSeconds is how long the longest-waiting due job has been due
+	OldestWaitSeconds float64 `json:"oldest_wait_seconds"`
+}
+
+// Stats returns the depth of each priority and how long due jobs have waited
+func (q *Queue) Stats(ctx context.Context) (*QueueStats, error) {
+	now := time.Now()
+	dueBy := strconv.FormatInt(now.UnixMilli(), 10)
+
+	pipe := q.redis.Pipeline()
+	depths := make([]*redis.IntCmd, len(priorities))
+	dues := make([]*redis.IntCmd, len(priorities))
+	oldest := make([]*redis.ZSliceCmd, len(priorities))
+	for i, priority := range priorities {
+		key := q.scheduleKey(priority)
+		depths[i] = pipe.ZCard(ctx, key)
+		dues[i] = pipe.ZCount(ctx, key, "-inf", dueBy)
+		oldest[i] = pipe.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: "-inf", Max: dueBy, Count: 1})
+	}
+	processing := pipe.ZCard(ctx, q.key("processing"))
+	busy := pipe.HLen(ctx, q.key("running"))
+	dead := pipe.LLen(ctx, q.key("dead"))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	stats := &QueueStats{
+		Processing:  processing.Val(),
+		BusyOwners:  busy.Val(),
+		DeadLetters: dead.Val(),
+	}
+	for i, priority := range priorities {
+		ps := PriorityStats{Priority: priority.String(), Depth: depths[i].Val(), Due: dues[i].Val()}
+		if first := oldest[i].Val(); len(first) > 0 {
+			ps.OldestWaitSeconds = now.Sub(time.UnixMilli(int64(first[0].Score))).Seconds()
+		}
+		stats.Priorities = append(stats.Priorities, ps)
+	}
+	return stats, nil
+}
+
+// dequeueScript atomically moves the next due job into the processing set.
+// The schedules are searched in priority order, skipping jobs whose owner
+// already has the maximum number running.
+//
+// KEYS: the schedules by priority, then processing, owners and running
+// ARGV: now, visibility deadline, running cap, scan limit
+var dequeueScript = redis.NewScript(`
+local n = #KEYS - 3
+local processing, owners, running = KEYS[n + 1], KEYS[n + 2], KEYS[n + 3]
+local cap = tonumber(ARGV[3])
+for i = 1, n do
+	local ids = redis.call('ZRANGEBYSCORE', KEYS[i], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[4]))
+	for _, id in ipairs(ids) do
+		local owner = redis.call('HGET', owners, id)
+		if not owner or tonumber(redis.call('HGET', running, owner) or '0') < cap then
+			redis.call('ZREM', KEYS[i], id)
+			redis.call('ZADD', processing, ARGV[2], id)
+			if owner then
+				redis.call('HINCRBY', running, owner, 1)
+			end
+			return id
+		end
+	end
+end
+return false
+`)
+
+// claimExpiredScript claims in-flight jobs whose visibility deadline passed
+// for requeueing by pushing their deadline back, so another maintenance run
+// leaves them alone and a crash midway returns them later. Jobs whose data
+// is gone are dropped, releasing their owners' running slots.
+//
+// KEYS: processing, data, owners, running
+// ARGV: now, claim deadline
+var claimExpiredScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+local claimed = {}
+for _, id in ipairs(ids) do
+	if redis.call('HEXISTS', KEYS[2], id) == 1 then
+		redis.call('ZADD', KEYS[1], ARGV[2], id)
+		table.insert(claimed, id)
+	else
+		redis.call('ZREM', KEYS[1], id)
+		local owner = redis.call('HGET', KEYS[3], id)
+		if owner then
+			redis.call('HDEL', KEYS[3], id)
+			if redis.call('HINCRBY', KEYS[4], owner, -1) <= 0 then
+				redis.call('HDEL', KEYS[4], owner)
+			end
+		end
+	end
+end
+return claimed
+`)
+
+// releaseScript frees one of an owner's running slots
+var releaseScript = redis.NewScript(`
+if redis.call('HINCRBY', KEYS[1], ARGV[1], -1) <= 0 then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// dequeue claims the next job that is due, returning nil if there is none
+func (q *Queue) dequeue(ctx context.Context) (*Job, error) {
+	now := time.Now()
+	deadline := now.Add(q.visibilityTimeout)
+
+	keys := make([]string, 0, len(priorities)+3)
+	for _, priority := range priorities {
+		keys = append(keys, q.scheduleKey(priority))
+	}
+	keys = append(keys, q.key("processing"), q.key("owners"), q.key("running"))
+
+	result, err := dequeueScript.Run(ctx, q.redis, keys,
+		strconv.FormatInt(now.UnixMilli(), 10),
+		strconv.FormatInt(deadline.UnixMilli(), 10),
+		q.maxRunningPerOwner,
+		claimScanLimit,
+	).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data, err := q.redis.HGet(ctx, q.key("data"), result).Bytes()
+	if err != nil {
+		// Drop the orphaned id so it doesn't block the queue
+		q.redis.ZRem(ctx, q.key("processing"), result)
+		q.redis.HDel(ctx, q.key("owners"), result)
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		q.redis.ZRem(ctx, q.key("processing"), result)
+		q.redis.HDel(ctx, q.key("data"), result)
+		q.redis.HDel(ctx, q.key("owners"), result)
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// complete removes a successfully processed job
+func (q *Queue) complete(ctx context.Context, job *Job) error {
+	pipe := q.redis.TxPipeline()
+	pipe.ZRem(ctx, q.key("processing"), job.ID)
+	pipe.HDel(ctx, q.key("data"), job.ID)
+	q.release(ctx, pipe, job, true)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// retry reschedules a failed job after the given delay
+func (q *Queue) retry(ctx context.Context, job *Job, delay time.Duration) error {
+	job.RunAt = time.Now().Add(delay)
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redis.TxPipeline()
+	pipe.ZRem(ctx, q.key("processing"), job.ID)
+	pipe.HSet(ctx, q.key("data"), job.ID, data)
+	pipe.ZAdd(ctx, q.scheduleKey(job.Priority), redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+	q.release(ctx, pipe, job, false)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// deadLetter moves a job that exhausted its attempts to the dead-letter list
+func (q *Queue) deadLetter(ctx context.Context, job *Job) error {
+	job.FailedAt = time.Now()
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redis.TxPipeline()
+	pipe.ZRem(ctx, q.key("processing"), job.ID)
+	pipe.HDel(ctx, q.key("data"), job.ID)
+	pipe.LPush(ctx, q.key("dead"), data)
+	q.release(ctx, pipe, job, true)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// requeueExpired returns jobs abandoned by crashed workers to the schedule.
+// An abandoned run counts as an attempt, so a job that keeps crashing its
+// worker is dead-lettered once it reaches its maximum attempts.
+func (q *Queue) requeueExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	ids, err := claimExpiredScript.Run(ctx, q.redis,
+		[]string{q.key("processing"), q.key("data"), q.key("owners"), q.key("running")},
+		strconv.FormatInt(now.UnixMilli(), 10),
+		strconv.FormatInt(now.Add(q.visibilityTimeout).UnixMilli(), 10),
+	).StringSlice()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		data, err := q.redis.HGet(ctx, q.key("data"), id).Bytes()
+		if err != nil {
+			// The job finished since it was claimed
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return count, err
+		}
+
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			// The next run drops the job now that its data is gone
+			q.redis.HDel(ctx, q.key("data"), id)
+			continue
+		}
+
+		job.Attempts++
+		job.LastError = abandonedJobError
+		if job.Attempts >= job.MaxAttempts {
+			err = q.deadLetter(ctx, &job)
+		} else {
+			err = q.retry(ctx, &job, 0)
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// release frees the owner's running slot of a claimed job in a pipeline,
+// forgetting the owner too when the job is done
+func (q *Queue) release(ctx context.Context, pipe redis.Pipeliner, job *Job, done bool) {
+	if job.Owner == "" {
+		return
+	}
+	releaseScript.Eval(ctx, pipe, []string{q.key("running")}, job.Owner)
+	if done {
+		pipe.HDel(ctx, q.key("owners"), job.ID)
+	}
+}
+
+// schedule stores the job and adds it to the schedule
+func (q *Queue) schedule(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redis.TxPipeline()
+	pipe.HSet(ctx, q.key("data"), job.ID, data)
+	if job.Owner != "" {
+		pipe.HSet(ctx, q.key("owners"), job.ID, job.Owner)
+	}
+	pipe.ZAdd(ctx, q.scheduleKey(job.Priority), redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// scheduleKey returns the key of the schedule of a priority. Normal jobs keep
+// the key used before there were priorities.
+func (q *Queue) scheduleKey(priority Priority) string {
+	switch {
+	case priority > PriorityNormal:
+		return q.key("scheduled:high")
+	case priority < PriorityNormal:
+		return q.key("scheduled:low")
+	default:
+		return q.key("scheduled")
+	}
+}
+
+// key returns a namespaced Redis key
+func (q *Queue) key(name string) string {
+	return q.prefix + ":" + name
+}
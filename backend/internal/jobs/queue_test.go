@@ -0,0 +1,336 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupQueue(t *testing.T) (*Queue, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to create miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewQueue(QueueConfig{Redis: client, VisibilityTimeout: time.Minute}), mr
+}
+
+func TestQueueEnqueueDequeue(t *testing.T) {
+	queue, _ := setupQueue(t)
+	ctx := context.Background()
+
+	enqueued, err := queue.Enqueue(ctx, "test.job", map[string]string{"key": "value"})
+	require.NoError(t, err)
+
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, enqueued.ID, job.ID)
+	assert.Equal(t, "test.job", job.Type)
+
+	var payload map[string]string
+	require.NoError(t, job.DecodePayload(&payload))
+	assert.Equal(t, "value", payload["key"])
+
+	// Claimed jobs are not handed out twice
+	next, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+
+	require.NoError(t, queue.complete(ctx, job))
+}
+
+// hashTag returns the part of a key Redis Cluster hashes to pick its slot
+func hashTag(key string) string {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+	return key
+}
+
+func TestQueueKeysShareASlot(t *testing.T) {
+	queue, mr := setupQueue(t)
+	ctx := context.Background()
+
+	// Use every key: the schedules, an owner's running count, retries and the
+	// dead letters
+	for _, priority := range priorities {
+		_, err := queue.Enqueue(ctx, "test.job", nil, WithPriority(priority), WithOwner("owner"))
+		require.NoError(t, err)
+	}
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, queue.retry(ctx, job, time.Hour))
+	job, err = queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, queue.deadLetter(ctx, job))
+	_, err = queue.dequeue(ctx)
+	require.NoError(t, err)
+	_, err = queue.requeueExpired(ctx)
+	require.NoError(t, err)
+
+	// Redis Cluster refuses scripts whose keys are in different slots.
+	// Emptied schedules are gone from Redis, so they are added here.
+	keys := mr.Keys()
+	require.NotEmpty(t, keys)
+	for _, priority := range priorities {
+		keys = append(keys, queue.scheduleKey(priority))
+	}
+	for _, key := range keys {
+		assert.Equal(t, "jobs", hashTag(key), key)
+	}
+}
+
+func TestQueueDelayedJob(t *testing.T) {
+	queue, _ := setupQueue(t)
+	ctx := context.Background()
+
+	_, err := queue.Enqueue(ctx, "test.job", nil, WithDelay(time.Hour))
+	require.NoError(t, err)
+
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestQueueRequeueExpired(t *testing.T) {
+	queue, _ := setupQueue(t)
+	queue.visibilityTimeout = -time.Second
+	ctx := context.Background()
+
+	_, err := queue.Enqueue(ctx, "test.job", nil)
+	require.NoError(t, err)
+
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	count, err := queue.requeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	again, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, again)
+	assert.Equal(t, job.ID, again.ID)
+	assert.Equal(t, 1, again.Attempts)
+	assert.Equal(t, abandonedJobError, again.LastError)
+}
+
+func TestQueueRequeueExpiredDeadLettersAtMaxAttempts(t *testing.T) {
+	queue, _ := setupQueue(t)
+	queue.visibilityTimeout = -time.Second
+	ctx := context.Background()
+
+	enqueued, err := queue.Enqueue(ctx, "test.job", nil, WithMaxAttempts(2), WithOwner("user-a"))
+	require.NoError(t, err)
+
+	// The job crashes its worker on every attempt
+	for range 2 {
+		job, err := queue.dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		count, err := queue.requeueExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	}
+
+	next, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+
+	dead, err := queue.DeadLetters(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, enqueued.ID, dead[0].ID)
+	assert.Equal(t, 2, dead[0].Attempts)
+	assert.False(t, dead[0].FailedAt.IsZero())
+
+	stats, err := queue.Stats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Processing)
+	assert.Zero(t, stats.BusyOwners)
+}
+
+func TestQueueClaimsByPriority(t *testing.T) {
+	queue, _ := setupQueue(t)
+	ctx := context.Background()
+
+	low, err := queue.Enqueue(ctx, "test.batch", nil, WithPriority(PriorityLow))
+	require.NoError(t, err)
+	normal, err := queue.Enqueue(ctx, "test.job", nil)
+	require.NoError(t, err)
+	high, err := queue.Enqueue(ctx, "test.export", nil, WithPriority(PriorityHigh))
+	require.NoError(t, err)
+
+	for _, want := range []*Job{high, normal, low} {
+		job, err := queue.dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, want.ID, job.ID)
+	}
+}
+
+func TestQueueCapsRunningJobsPerOwner(t *testing.T) {
+	queue, _ := setupQueue(t)
+	queue.maxRunningPerOwner = 1
+	ctx := context.Background()
+
+	// Distinct run times keep the order from depending on how Redis breaks ties
+	start := time.Now().Add(-time.Minute)
+	first, err := queue.Enqueue(ctx, "test.export", nil, WithOwner("user-a"), WithRunAt(start))
+	require.NoError(t, err)
+	second, err := queue.Enqueue(ctx, "test.export", nil, WithOwner("user-a"), WithRunAt(start.Add(time.Second)))
+	require.NoError(t, err)
+	other, err := queue.Enqueue(ctx, "test.export", nil, WithOwner("user-b"), WithRunAt(start.Add(2*time.Second)))
+	require.NoError(t, err)
+
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, job.ID)
+
+	// user-a is at the cap, so user-b's job goes ahead of theirs
+	job, err = queue.dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, job.ID)
+
+	next, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+
+	// Finishing a job frees the slot
+	require.NoError(t, queue.complete(ctx, first))
+	job, err = queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, second.ID, job.ID)
+}
+
+func TestQueueRequeueExpiredKeepsPriorityAndReleasesOwner(t *testing.T) {
+	queue, _ := setupQueue(t)
+	queue.visibilityTimeout = -time.Second
+	queue.maxRunningPerOwner = 1
+	ctx := context.Background()
+
+	enqueued, err := queue.Enqueue(ctx, "test.export", nil, WithPriority(PriorityHigh), WithOwner("user-a"))
+	require.NoError(t, err)
+	_, err = queue.dequeue(ctx)
+	require.NoError(t, err)
+
+	count, err := queue.requeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	stats, err := queue.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "high", stats.Priorities[0].Priority)
+	assert.EqualValues(t, 1, stats.Priorities[0].Due)
+	assert.Zero(t, stats.BusyOwners)
+
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, enqueued.ID, job.ID)
+}
+
+func TestQueueStats(t *testing.T) {
+	queue, _ := setupQueue(t)
+	ctx := context.Background()
+
+	_, err := queue.Enqueue(ctx, "test.job", nil, WithRunAt(time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	_, err = queue.Enqueue(ctx, "test.job", nil, WithDelay(time.Hour))
+	require.NoError(t, err)
+	_, err = queue.Enqueue(ctx, "test.export", nil, WithPriority(PriorityHigh), WithOwner("user-a"))
+	require.NoError(t, err)
+	_, err = queue.dequeue(ctx)
+	require.NoError(t, err)
+
+	stats, err := queue.Stats(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, stats.Processing)
+	assert.EqualValues(t, 1, stats.BusyOwners)
+	require.Len(t, stats.Priorities, 3)
+
+	normal := stats.Priorities[1]
+	assert.Equal(t, "normal", normal.Priority)
+	assert.EqualValues(t, 2, normal.Depth)
+	assert.EqualValues(t, 1, normal.Due)
+	assert.InDelta(t, 60, normal.OldestWaitSeconds, 5)
+	assert.Zero(t, stats.Priorities[2].Depth)
+}
+
+func TestWorkerRetriesThenDeadLetters(t *testing.T) {
+	queue, _ := setupQueue(t)
+	ctx := context.Background()
+
+	worker := NewWorker(queue, WorkerConfig{Concurrency: 1, RetryBackoff: time.Millisecond})
+	attempts := 0
+	worker.Register("test.fail", func(ctx context.Context, job *Job) error {
+		attempts++
+		return errors.New("boom")
+	})
+
+	_, err := queue.Enqueue(ctx, "test.fail", nil, WithMaxAttempts(2))
+	require.NoError(t, err)
+
+	// First attempt schedules a retry
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	worker.process(ctx, job)
+
+	time.Sleep(5 * time.Millisecond)
+
+	// Second attempt exhausts the job
+	job, err = queue.dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	worker.process(ctx, job)
+
+	assert.Equal(t, 2, attempts)
+
+	dead, err := queue.DeadLetters(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, "boom", dead[0].LastError)
+	assert.Equal(t, 2, dead[0].Attempts)
+
+	next, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+}
+
+func TestWorkerRecoversPanics(t *testing.T) {
+	queue, _ := setupQueue(t)
+	ctx := context.Background()
+
+	worker := NewWorker(queue, WorkerConfig{Concurrency: 1})
+	worker.Register("test.panic", func(ctx context.Context, job *Job) error {
+		panic("unexpected")
+	})
+
+	_, err := queue.Enqueue(ctx, "test.panic", nil, WithMaxAttempts(1))
+	require.NoError(t, err)
+
+	job, err := queue.dequeue(ctx)
+	require.NoError(t, err)
+	worker.process(ctx, job)
+
+	dead, err := queue.DeadLetters(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Contains(t, dead[0].LastError, "panicked")
+}
@@ -0,0 +1,308 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Default worker parameters
+const (
+	DefaultConcurrency  = 4
+	DefaultPollInterval = time.Second
+	DefaultRetryBackoff = 10 * time.Second
+	maxRetryBackoff     = time.Hour
+)
+
+// Handler processes a single job; returning an error schedules a retry
+type Handler func(ctx context.Context, job *Job) error
+
+// WorkerConfig contains configuration options for the job worker
+type WorkerConfig struct {
+	// Concurrency is the number of jobs processed in parallel
+	Concurrency int
+	// PollInterval is how often idle workers check for due jobs
+	PollInterval time.Duration
+	// RetryBackoff is the base delay before a failed job is retried; it doubles per attempt
+	RetryBackoff time.Duration
+	// JobTimeout bounds the runtime of a single job
+	JobTimeout time.Duration
+}
+
+// periodicJob is a job type enqueued on a fixed interval
+type periodicJob struct {
+	jobType  string
+	interval time.Duration
+}
+
+// Worker pulls jobs from a queue and dispatches them to registered handlers
+type Worker struct {
+	queue    *Queue
+	config   WorkerConfig
+	handlers map[string]Handler
+	periodic []periodicJob
+	// available reports whether the queue's Redis is reachable
+	available func() bool
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewWorker creates a new job worker
+func NewWorker(queue *Queue, config WorkerConfig) *Worker {
+	// Set defaults if not provided
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+	if config.JobTimeout <= 0 {
+		// Leave headroom so a job finishes before another worker may claim it
+		config.JobTimeout = queue.visibilityTimeout - time.Second
+	}
+
+	return &Worker{
+		queue:    queue,
+		config:   config,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Queue returns the queue the worker consumes
+func (w *Worker) Queue() *Queue {
+	return w.queue
+}
+
+// Register sets the handler for a job type
+func (w *Worker) Register(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// SetAvailability sets a function reporting whether the queue's Redis is
+// reachable. While it reports false the worker pauses rather than failing
+// every poll, and picks up again once Redis is back.
+func (w *Worker) SetAvailability(available func() bool) {
+	w.available = available
+}
+
+// paused reports whether the worker is waiting for Redis to come back
+func (w *Worker) paused() bool {
+	return w.available != nil && !w.available()
+}
+
+// Every enqueues a job of the given type once per interval across all running workers
+func (w *Worker) Every(interval time.Duration, jobType string) {
+	w.periodic = append(w.periodic, periodicJob{jobType: jobType, interval: interval})
+}
+
+// Start launches the worker goroutines; call Stop to shut them down
+func (w *Worker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < w.config.Concurrency; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.processLoop(ctx)
+		}()
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.maintenanceLoop(ctx)
+	}()
+
+	for _, p := range w.periodic {
+		w.wg.Add(1)
+		go func(p periodicJob) {
+			defer w.wg.Done()
+			w.periodicLoop(ctx, p)
+		}(p)
+	}
+
+	log.Info().Int("concurrency", w.config.Concurrency).Int("periodic", len(w.periodic)).Msg("Job worker started")
+}
+
+// Stop signals the worker to stop and waits for in-flight jobs to finish
+func (w *Worker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+	log.Info().Msg("Job worker stopped")
+}
+
+// processLoop repeatedly claims and runs due jobs
+func (w *Worker) processLoop(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain all due jobs before sleeping
+		for !w.paused() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			job, err := w.queue.dequeue(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					log.Error().Err(err).Msg("Failed to dequeue job")
+				}
+				break
+			}
+			if job == nil {
+				break
+			}
+
+			w.process(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// process runs a single job and records the outcome
+func (w *Worker) process(ctx context.Context, job *Job) {
+	logger := log.With().Str("job_id", job.ID).Str("job_type", job.Type).Int("attempt", job.Attempts+1).
+		Stringer("priority", job.Priority).Dur("wait", time.Since(job.RunAt)).Logger()
+
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		logger.Error().Msg("No handler registered for job type")
+		job.Attempts = job.MaxAttempts
+		job.LastError = "no handler registered"
+		if err := w.queue.deadLetter(ctx, job); err != nil {
+			logger.Error().Err(err).Msg("Failed to dead-letter job")
+		}
+		return
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, w.config.JobTimeout)
+	err := runHandler(jobCtx, handler, job)
+	cancel()
+
+	// Use a fresh context so results are recorded even during shutdown
+	recordCtx, recordCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer recordCancel()
+
+	if err == nil {
+		if err := w.queue.complete(recordCtx, job); err != nil {
+			logger.Error().Err(err).Msg("Failed to mark job as complete")
+		}
+		logger.Debug().Msg("Job completed")
+		return
+	}
+
+	job.Attempts++
+	job.LastError = err.Error()
+
+	if job.Attempts >= job.MaxAttempts {
+		logger.Error().Err(err).Msg("Job failed permanently, moving to dead-letter queue")
+		if err := w.queue.deadLetter(recordCtx, job); err != nil {
+			logger.Error().Err(err).Msg("Failed to dead-letter job")
+		}
+		return
+	}
+
+	delay := w.backoff(job.Attempts)
+	logger.Warn().Err(err).Dur("retry_in", delay).Msg("Job failed, scheduling retry")
+	if err := w.queue.retry(recordCtx, job, delay); err != nil {
+		logger.Error().Err(err).Msg("Failed to schedule job retry")
+	}
+}
+
+// maintenanceLoop returns abandoned jobs to the schedule
+func (w *Worker) maintenanceLoop(ctx context.Context) {
+	ticker := time.NewTicker(w.queue.visibilityTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.paused() {
+				continue
+			}
+			count, err := w.queue.requeueExpired(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					log.Error().Err(err).Msg("Failed to requeue expired jobs")
+				}
+				continue
+			}
+			if count > 0 {
+				log.Warn().Int("count", count).Msg("Requeued jobs abandoned by workers")
+			}
+		}
+	}
+}
+
+// periodicLoop enqueues a periodic job, using a Redis lock so only one instance enqueues per interval
+func (w *Worker) periodicLoop(ctx context.Context, p periodicJob) {
+	enqueue := func() {
+		if w.paused() {
+			return
+		}
+		acquired, err := w.queue.redis.SetNX(ctx, w.queue.key("periodic:"+p.jobType), time.Now().Unix(), p.interval).Result()
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				log.Error().Err(err).Str("job_type", p.jobType).Msg("Failed to acquire periodic job lock")
+			}
+			return
+		}
+		if !acquired {
+			return
+		}
+
+		if _, err := w.queue.Enqueue(ctx, p.jobType, nil, WithMaxAttempts(1), WithPriority(PriorityLow)); err != nil {
+			log.Error().Err(err).Str("job_type", p.jobType).Msg("Failed to enqueue periodic job")
+		}
+	}
+
+	enqueue()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			enqueue()
+		}
+	}
+}
+
+// backoff returns the exponential retry delay for the given attempt
+func (w *Worker) backoff(attempt int) time.Duration {
+	delay := w.config.RetryBackoff << (attempt - 1)
+	if delay <= 0 || delay > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return delay
+}
+
+// runHandler invokes the handler, converting panics into errors
+func runHandler(ctx context.Context, handler Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
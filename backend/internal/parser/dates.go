@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lordaris/resume_generator/pkg/locale"
+)
+
+// months maps lowercase month names, and their first three letters, in
+// every supported language to their number
+var months = map[string]int{"sept": 9}
+
+// presentWords end the date range of something ongoing
+var presentWords = []string{"present", "current", "now", "today", "ongoing"}
+
+var (
+	digitsRe    *regexp.Regexp
+	dateRe      *regexp.Regexp
+	dateRangeRe *regexp.Regexp
+)
+
+func init() {
+	// Abbreviations shared by two months, such as "jui" for juin and
+	// juillet, stand for neither
+	abbreviations := make(map[string]int)
+	for _, lang := range locale.Languages() {
+		c := locale.For(lang)
+		for i, name := range c.Months {
+			name = strings.ToLower(name)
+			months[name] = i + 1
+			if short := []rune(name); len(short) > 3 {
+				if month, seen := abbreviations[string(short[:3])]; seen && month != i+1 {
+					abbreviations[string(short[:3])] = 0
+				} else {
+					abbreviations[string(short[:3])] = i + 1
+				}
+			}
+		}
+		presentWords = append(presentWords, strings.ToLower(c.Present))
+	}
+	for short, month := range abbreviations {
+		if _, full := months[short]; month != 0 && !full {
+			months[short] = month
+		}
+	}
+
+	names := make([]string, 0, len(months))
+	for name := range months {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	// Longest first, so "june" isn't read as "jun"
+	slices.SortFunc(names, func(a, b string) int { return len(b) - len(a) })
+	present := make([]string, len(presentWords))
+	for i, word := range presentWords {
+		present[i] = regexp.QuoteMeta(word)
+	}
+
+	date := `(?:(?:` + strings.Join(names, "|") + `)\.?\s+(?:de\s+)?)?(?:\d{1,2}[/.])?\d{4}(?:[-/.]\d{1,2}(?:[-/.]\d{1,2})?)?\b`
+	digitsRe = regexp.MustCompile(`\d+`)
+	dateRe = regexp.MustCompile(`(?i)\b` + date)
+	dateRangeRe = regexp.MustCompile(`(?i)\b(` + date + `)\s*(?:-|–|—|to|until|till)\s*(` + date + `|` + strings.Join(present, "|") + `)`)
+}
+
+// takeDateRange finds the first date range in lines and returns its start
+// and end as YYYY-MM-DD, or "Present", with the lines left once it is
+// removed
+func takeDateRange(lines []string) (string, string, []string, bool) {
+	for i, line := range lines {
+		m := dateRangeRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		start, ok := parseDate(line[m[2]:m[3]])
+		if !ok {
+			continue
+		}
+		end, ok := parseDate(line[m[4]:m[5]])
+		if !ok {
+			continue
+		}
+		return start, end, removeMatch(lines, i, m[0], m[1]), true
+	}
+	return "", "", lines, false
+}
+
+// takeDate finds the first date in lines, like takeDateRange
+func takeDate(lines []string) (string, []string, bool) {
+	for i, line := range lines {
+		for _, m := range dateRe.FindAllStringIndex(line, -1) {
+			if date, ok := parseDate(line[m[0]:m[1]]); ok {
+				return date, removeMatch(lines, i, m[0], m[1]), true
+			}
+		}
+	}
+	return "", lines, false
+}
+
+// removeMatch returns lines with line i's text from start to end removed,
+// along with the separators it leaves dangling. A line left empty is
+// dropped.
+func removeMatch(lines []string, i, start, end int) []string {
+	line := strings.TrimSpace(lines[i][:start]) + " " + strings.TrimSpace(lines[i][end:])
+	line = strings.TrimSpace(strings.ReplaceAll(line, "()", ""))
+	line = strings.Trim(line, " ,|–—-·:")
+	rest := slices.Clone(lines)
+	if line == "" {
+		return slices.Delete(rest, i, i+1)
+	}
+	rest[i] = line
+	return rest
+}
+
+// parseDate reads a date such as "Mar 2021", "marzo de 2021", "03/2021",
+// "2021-03", "2021-03-15" or "2021" as YYYY-MM-DD, taking the first of the
+// month or year when the day or month is missing. Ongoing words give
+// "Present".
+func parseDate(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if slices.Contains(presentWords, s) {
+		return "Present", true
+	}
+
+	month := 1
+	if word := strings.TrimRight(strings.Fields(s)[0], "."); !strings.ContainsAny(word[:1], "0123456789") {
+		m, ok := months[word]
+		if !ok {
+			return "", false
+		}
+		month = m
+	}
+
+	nums := digitsRe.FindAllString(s, -1)
+	var year, day int
+	switch {
+	case len(nums) == 1:
+		year, _ = strconv.Atoi(nums[0])
+	case len(nums) == 2 && len(nums[0]) == 4:
+		year, _ = strconv.Atoi(nums[0])
+		month, _ = strconv.Atoi(nums[1])
+	case len(nums) == 2:
+		month, _ = strconv.Atoi(nums[0])
+		year, _ = strconv.Atoi(nums[1])
+	case len(nums) == 3 && len(nums[0]) == 4:
+		year, _ = strconv.Atoi(nums[0])
+		month, _ = strconv.Atoi(nums[1])
+		day, _ = strconv.Atoi(nums[2])
+	default:
+		return "", false
+	}
+	if year < 1950 || year > 2100 || month < 1 || month > 12 {
+		return "", false
+	}
+	if day == 0 {
+		day = 1
+	}
+	date := fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "", false
+	}
+	return date, true
+}
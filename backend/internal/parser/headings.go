@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/lordaris/resume_generator/pkg/locale"
+)
+
+// section is the kind of a resume section
+type section int
+
+const (
+	// sectionHeader is the top of the resume, before the first heading
+	sectionHeader section = iota
+	sectionSummary
+	sectionExperience
+	sectionEducation
+	sectionSkills
+	sectionProjects
+	sectionCertifications
+	// sectionOther is a section without a matching resume section, such as
+	// interests or references
+	sectionOther
+)
+
+// headings maps the lowercase titles of sections to their kind. The
+// headings of every locale catalog are added, so resumes exported by this
+// service in any language are read back.
+var headings = map[string]section{
+	"summary": sectionSummary, "profile": sectionSummary, "professional summary": sectionSummary,
+	"about": sectionSummary, "about me": sectionSummary, "objective": sectionSummary, "career objective": sectionSummary,
+
+	"experience": sectionExperience, "work experience": sectionExperience, "professional experience": sectionExperience,
+	"employment": sectionExperience, "employment history": sectionExperience, "work history": sectionExperience,
+	"career history": sectionExperience, "relevant experience": sectionExperience,
+
+	"education": sectionEducation, "academic background": sectionEducation, "education and training": sectionEducation,
+
+	"skills": sectionSkills, "technical skills": sectionSkills, "core competencies": sectionSkills,
+	"key skills": sectionSkills, "technologies": sectionSkills, "skills and technologies": sectionSkills,
+
+	"projects": sectionProjects, "personal projects": sectionProjects, "side projects": sectionProjects,
+	"selected projects": sectionProjects, "open source": sectionProjects,
+
+	"certifications": sectionCertifications, "certificates": sectionCertifications,
+	"licenses and certifications": sectionCertifications, "licenses & certifications": sectionCertifications,
+	"certifications and licenses": sectionCertifications,
+
+	"interests": sectionOther, "hobbies": sectionOther, "languages": sectionOther, "references": sectionOther,
+	"awards": sectionOther, "honors": sectionOther, "publications": sectionOther, "volunteering": sectionOther,
+	"volunteer experience": sectionOther, "additional information": sectionOther,
+}
+
+func init() {
+	for _, lang := range locale.Languages() {
+		h := locale.For(lang).Headings
+		for title, s := range map[string]section{
+			h.Experience:     sectionExperience,
+			h.Education:      sectionEducation,
+			h.Skills:         sectionSkills,
+			h.Projects:       sectionProjects,
+			h.Certifications: sectionCertifications,
+		} {
+			headings[strings.ToLower(title)] = s
+		}
+	}
+}
+
+// headingSection reports whether line is a section heading, and of which
+// section. Markdown and underline decorations and a trailing colon are
+// ignored. Once a known heading has been seen, any other short line in
+// capitals is taken for the heading of an unknown section.
+func headingSection(line string, seenHeading bool) (section, bool) {
+	if bulletRe.MatchString(line) {
+		return 0, false
+	}
+	title := strings.TrimFunc(line, func(r rune) bool {
+		return strings.ContainsRune("#*_=~:-–— ", r)
+	})
+	if title == "" || len(strings.Fields(title)) > 5 {
+		return 0, false
+	}
+	if s, ok := headings[strings.ToLower(strings.Join(strings.Fields(title), " "))]; ok {
+		return s, true
+	}
+	if seenHeading && isUpper(title) {
+		return sectionOther, true
+	}
+	return 0, false
+}
+
+// isUpper reports whether s has letters, all capitals, and no digits
+func isUpper(s string) bool {
+	letters := 0
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r), unicode.IsLower(r):
+			return false
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	return letters > 1
+}
@@ -0,0 +1,654 @@
+// Package parser proposes structured resume content from unstructured input.
+// Each proposed entry carries a confidence between 0 and 1, so clients can ask
+// the user to review the doubtful ones before saving them.
+package parser
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// Proposal is the structure proposed for a pasted resume. Entries are ready
+// to be sent to the section endpoints once the user has reviewed them.
+type Proposal struct {
+	PersonalInfo   *ProposedPersonalInfo   `json:"personal_info,omitempty"`
+	Experience     []ProposedExperience    `json:"experience"`
+	Education      []ProposedEducation     `json:"education"`
+	Skills         []ProposedSkill         `json:"skills"`
+	Projects       []ProposedProject       `json:"projects"`
+	Certifications []ProposedCertification `json:"certifications"`
+	// Unrecognized are the lines that weren't placed in an entry, such as a
+	// summary, so nothing pasted is silently dropped
+	Unrecognized []string `json:"unrecognized"`
+}
+
+// ProposedPersonalInfo is the personal information found at the top
+type ProposedPersonalInfo struct {
+	domain.PersonalInfo
+	Confidence float64 `json:"confidence"`
+}
+
+// ProposedExperience is a work experience entry
+type ProposedExperience struct {
+	domain.Experience
+	Confidence float64 `json:"confidence"`
+}
+
+// ProposedEducation is an education entry
+type ProposedEducation struct {
+	domain.Education
+	Confidence float64 `json:"confidence"`
+}
+
+// ProposedSkill is a skill
+type ProposedSkill struct {
+	domain.Skill
+	Confidence float64 `json:"confidence"`
+}
+
+// ProposedProject is a project
+type ProposedProject struct {
+	domain.Project
+	Confidence float64 `json:"confidence"`
+}
+
+// ProposedCertification is a certification
+type ProposedCertification struct {
+	domain.Certification
+	Confidence float64 `json:"confidence"`
+}
+
+var (
+	bulletRe    = regexp.MustCompile(`^(?:[-*•·▪◦‣●○■►▸➢✓–—]|\d{1,2}[.)])\s+`)
+	emailRe     = regexp.MustCompile(`[^\s@<>()|,;:]+@[^\s@<>()|,;:]+\.[A-Za-z]{2,}`)
+	phoneRe     = regexp.MustCompile(`\+?\(?\d[\d\s().-]{5,}\d`)
+	urlRe       = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>|,;]+`)
+	separatorRe = regexp.MustCompile(`\s+[|•·—–-]\s+|\s*\|\s*|,\s+|\s+@\s+|\s+(?i:at)\s+`)
+	labelRe     = regexp.MustCompile(`^([\p{L} &/]{2,30}):\s*(.+)$`)
+)
+
+// ParseText proposes resume sections for a resume pasted as plain text. It
+// splits the text at section headings, splits sections into entries at
+// blank lines and where a new heading line follows bullets, and reads dates
+// and date ranges in the month names of every supported language.
+func ParseText(text string) *Proposal {
+	p := &Proposal{
+		Experience:     []ProposedExperience{},
+		Education:      []ProposedEducation{},
+		Skills:         []ProposedSkill{},
+		Projects:       []ProposedProject{},
+		Certifications: []ProposedCertification{},
+		Unrecognized:   []string{},
+	}
+
+	current := sectionHeader
+	var lines []string
+	flush := func() {
+		switch current {
+		case sectionHeader:
+			p.parseHeader(lines)
+		case sectionExperience:
+			for _, b := range splitBlocks(lines) {
+				p.Experience = append(p.Experience, parseExperience(b))
+			}
+		case sectionEducation:
+			for _, b := range splitBlocks(lines) {
+				p.Education = append(p.Education, parseEducation(b))
+			}
+		case sectionProjects:
+			for _, b := range splitBlocks(lines) {
+				p.Projects = append(p.Projects, parseProject(b))
+			}
+		case sectionCertifications:
+			p.parseCertifications(lines)
+		case sectionSkills:
+			p.parseSkills(lines)
+		default:
+			p.unrecognized(lines)
+		}
+		lines = nil
+	}
+
+	seenHeading := false
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if s, ok := headingSection(line, seenHeading); ok {
+			flush()
+			current = s
+			seenHeading = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return p
+}
+
+// parseHeader reads the personal information from the lines before the
+// first heading: a name, the contact details and a job title
+func (p *Proposal) parseHeader(lines []string) {
+	var info domain.PersonalInfo
+	score := 0.0
+	var rest []string
+	afterName := false
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		used := false
+		if info.Email == "" {
+			if email := emailRe.FindString(line); email != "" && domain.EmailRegex.MatchString(email) {
+				info.Email = email
+				score += 0.35
+				used = true
+			}
+		}
+		if info.Phone == "" {
+			for _, candidate := range phoneRe.FindAllString(line, -1) {
+				if digits := countDigits(candidate); digits >= 7 && digits <= 15 && !dateRangeRe.MatchString(candidate) {
+					info.Phone = strings.TrimSpace(candidate)
+					score += 0.1
+					used = true
+					break
+				}
+			}
+		}
+		if used || urlRe.MatchString(line) {
+			continue
+		}
+		switch {
+		case info.FirstName == "" && looksLikeName(line):
+			fields := strings.Fields(line)
+			info.FirstName = fields[0]
+			info.LastName = strings.Join(fields[1:], " ")
+			score += 0.35
+			afterName = true
+			continue
+		case afterName && len(strings.Fields(line)) <= 8 && countDigits(line) == 0 && !strings.ContainsAny(line, ",|") && !isProse(line):
+			// The line under the name is usually the job title
+			info.JobTitle = line
+			score += 0.1
+		default:
+			rest = append(rest, line)
+		}
+		afterName = false
+	}
+	p.unrecognized(rest)
+	if score == 0 {
+		return
+	}
+	if info.Validate() == nil {
+		score += 0.1
+	}
+	p.PersonalInfo = &ProposedPersonalInfo{PersonalInfo: info, Confidence: confidence(score)}
+}
+
+// block is the lines of one entry: its heading lines, its bullets and any
+// prose under it
+type block struct {
+	headers []string
+	bullets []string
+	text    []string
+}
+
+// splitBlocks splits the lines of a section into entries. A heading line
+// after an entry's bullets or prose starts the next entry, and bullets
+// separated from their heading by a blank line stay with it. Labelled lines
+// and links are details of the entry they follow.
+func splitBlocks(lines []string) []block {
+	var blocks []block
+	var current block
+	closeBlock := func() {
+		switch {
+		case len(current.headers) == 0 && len(blocks) > 0:
+			last := &blocks[len(blocks)-1]
+			last.bullets = append(last.bullets, current.bullets...)
+			last.text = append(last.text, current.text...)
+		case len(current.headers)+len(current.bullets)+len(current.text) > 0:
+			blocks = append(blocks, current)
+		}
+		current = block{}
+	}
+
+	for _, line := range lines {
+		switch {
+		case line == "":
+			closeBlock()
+		case bulletRe.MatchString(line):
+			current.bullets = append(current.bullets, strings.TrimSpace(bulletRe.ReplaceAllString(line, "")))
+		case isProse(line) || (len(current.headers) > 0 && isDetail(line)):
+			current.text = append(current.text, line)
+		default:
+			if len(current.bullets)+len(current.text) > 0 {
+				closeBlock()
+			}
+			current.headers = append(current.headers, line)
+		}
+	}
+	closeBlock()
+	return blocks
+}
+
+func parseExperience(b block) ProposedExperience {
+	var e domain.Experience
+	score := 0.2
+	headers := b.headers
+	if start, end, rest, ok := takeDateRange(headers); ok {
+		e.StartDate, e.EndDate = start, end
+		headers = rest
+		score += 0.3
+	}
+
+	var parts []string
+	if len(headers) > 0 {
+		parts = splitParts(headers[0])
+	}
+	if len(parts) < 2 && len(headers) > 1 {
+		parts = append(parts, splitParts(headers[1])...)
+		headers = headers[1:]
+	}
+	if len(parts) > 0 {
+		e.JobTitle = parts[0]
+	}
+	if len(parts) > 1 {
+		e.Employer = parts[1]
+		e.Location = strings.Join(parts[2:], ", ")
+		// "Acme Inc | Senior Engineer" names the employer first
+		if looksLikeRole(e.Employer) && !looksLikeRole(e.JobTitle) {
+			e.JobTitle, e.Employer = e.Employer, e.JobTitle
+		}
+		score += 0.2
+	}
+	if len(headers) > 1 {
+		b.text = slices.Concat(headers[1:], b.text)
+	}
+
+	e.Achievements = b.bullets
+	e.Description = strings.Join(b.text, " ")
+	if len(b.bullets) > 0 {
+		score += 0.1
+	}
+	if e.Validate() == nil {
+		score += 0.2
+	}
+	return ProposedExperience{Experience: e, Confidence: confidence(score)}
+}
+
+func parseEducation(b block) ProposedEducation {
+	var e domain.Education
+	score := 0.2
+	headers := b.headers
+	if start, end, rest, ok := takeDateRange(headers); ok {
+		e.StartDate, e.EndDate = start, end
+		headers = rest
+		score += 0.3
+	} else if date, rest, ok := takeDate(headers); ok {
+		// A single date is usually the graduation
+		e.EndDate = date
+		headers = rest
+		score += 0.1
+	}
+
+	var unplaced []string
+	for _, header := range headers {
+		for _, part := range splitParts(header) {
+			switch {
+			case e.Institution == "" && isInstitution(part):
+				e.Institution = part
+			case e.Degree == "" && isDegree(part):
+				e.Degree, e.Field = splitDegree(part)
+			default:
+				unplaced = append(unplaced, part)
+			}
+		}
+	}
+	for len(unplaced) > 0 && (e.Institution == "" || e.Degree == "") {
+		if e.Degree == "" {
+			e.Degree, e.Field = splitDegree(unplaced[0])
+		} else {
+			e.Institution = unplaced[0]
+		}
+		unplaced = unplaced[1:]
+	}
+	e.Location = strings.Join(unplaced, ", ")
+	if e.Institution != "" && e.Degree != "" {
+		score += 0.2
+	}
+
+	e.Description = strings.Join(slices.Concat(b.text, b.bullets), " ")
+	if e.Validate() == nil {
+		score += 0.3
+	}
+	return ProposedEducation{Education: e, Confidence: confidence(score)}
+}
+
+func parseProject(b block) ProposedProject {
+	var pr domain.Project
+	score := 0.3
+	headers := b.headers
+	if start, end, rest, ok := takeDateRange(headers); ok {
+		pr.StartDate, pr.EndDate = start, end
+		headers = rest
+	}
+
+	var description []string
+	lines := slices.Concat(headers, b.text, b.bullets)
+	for i, line := range lines {
+		for _, link := range urlRe.FindAllString(line, -1) {
+			setProjectURL(&pr, link)
+			line = strings.TrimSpace(strings.Replace(line, link, "", 1))
+		}
+		if label, value, ok := splitLabel(line); ok && isTechnologiesLabel(label) {
+			pr.Technologies = append(pr.Technologies, splitList(value)...)
+			continue
+		}
+		if i == 0 {
+			parts := splitParts(line)
+			if len(parts) > 0 {
+				pr.Name = parts[0]
+				description = append(description, parts[1:]...)
+			}
+			continue
+		}
+		if line != "" {
+			description = append(description, line)
+		}
+	}
+	pr.Description = strings.Join(description, " ")
+
+	if pr.Name != "" {
+		score += 0.2
+	}
+	if pr.Description != "" || len(pr.Technologies) > 0 {
+		score += 0.2
+	}
+	if pr.Validate() == nil {
+		score += 0.3
+	}
+	return ProposedProject{Project: pr, Confidence: confidence(score)}
+}
+
+// parseCertifications reads one certification per line, attaching credential
+// IDs and links on their own lines to the certification above
+func (p *Proposal) parseCertifications(lines []string) {
+	for _, line := range lines {
+		line = strings.TrimSpace(bulletRe.ReplaceAllString(line, ""))
+		if line == "" {
+			continue
+		}
+		last := len(p.Certifications) - 1
+		if label, value, ok := splitLabel(line); ok && last >= 0 && strings.Contains(strings.ToLower(label), "credential") {
+			p.Certifications[last].CredentialID = value
+			continue
+		}
+		if link := urlRe.FindString(line); link == line && last >= 0 {
+			p.Certifications[last].URL = link
+			continue
+		}
+
+		var c domain.Certification
+		score := 0.2
+		if link := urlRe.FindString(line); link != "" {
+			c.URL = link
+			line = strings.TrimSpace(strings.Replace(line, link, "", 1))
+		}
+		rest := []string{line}
+		if date, remaining, ok := takeDate(rest); ok {
+			c.IssueDate = date
+			rest = remaining
+			score += 0.3
+			if expiry, remaining, ok := takeDate(rest); ok {
+				c.ExpiryDate = expiry
+				rest = remaining
+			}
+		}
+		var parts []string
+		if len(rest) > 0 {
+			parts = splitParts(rest[0])
+		}
+		if len(parts) > 0 {
+			c.Name = parts[0]
+		}
+		if len(parts) > 1 {
+			c.Issuer = parts[1]
+			score += 0.2
+		}
+		if c.Validate() == nil {
+			score += 0.3
+		}
+		p.Certifications = append(p.Certifications, ProposedCertification{Certification: c, Confidence: confidence(score)})
+	}
+}
+
+// parseSkills reads comma-separated lists of skills, optionally labelled
+// with a category as in "Languages: Go, Python"
+func (p *Proposal) parseSkills(lines []string) {
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		line = strings.TrimSpace(bulletRe.ReplaceAllString(line, ""))
+		if line == "" {
+			continue
+		}
+		category, score := "", 0.8
+		if label, value, ok := splitLabel(line); ok {
+			category = skillCategory(label)
+			line = value
+			score = 0.9
+		}
+		for _, name := range splitList(line) {
+			key := strings.ToLower(name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			s := score
+			// Long items are more likely a sentence than a skill
+			if len(strings.Fields(name)) > 4 {
+				s = 0.4
+			}
+			p.Skills = append(p.Skills, ProposedSkill{Skill: domain.Skill{Name: name, Category: category}, Confidence: s})
+		}
+	}
+}
+
+func (p *Proposal) unrecognized(lines []string) {
+	for _, line := range lines {
+		if line != "" {
+			p.Unrecognized = append(p.Unrecognized, line)
+		}
+	}
+}
+
+// splitParts splits a heading line at the separators resumes put between
+// a title, an organization and a location, keeping company suffixes such as
+// "Inc." with the name before them
+func splitParts(line string) []string {
+	var parts []string
+	for _, part := range separatorRe.Split(line, -1) {
+		part = strings.Trim(part, " ()[],")
+		if part == "" {
+			continue
+		}
+		if len(parts) > 0 && companySuffixes[strings.ToLower(strings.TrimSuffix(part, "."))] {
+			parts[len(parts)-1] += ", " + part
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// splitList splits a list of items at commas, semicolons, pipes and bullets
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|' || r == '•' || r == '·'
+	}) {
+		if item = strings.Trim(item, " ."); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitLabel splits "Label: value" lines
+func splitLabel(line string) (string, string, bool) {
+	m := labelRe.FindStringSubmatch(line)
+	if m == nil || len(strings.Fields(m[1])) > 3 || strings.HasPrefix(m[2], "//") {
+		return "", "", false
+	}
+	return strings.TrimSpace(m[1]), strings.TrimSpace(m[2]), true
+}
+
+func setProjectURL(p *domain.Project, link string) {
+	if strings.HasPrefix(strings.ToLower(link), "www.") {
+		link = "https://" + link
+	}
+	link = strings.TrimRight(link, ".)")
+	u, err := url.Parse(link)
+	if err != nil {
+		return
+	}
+	host := strings.ToLower(u.Host)
+	if (strings.HasSuffix(host, "github.com") || strings.HasSuffix(host, "gitlab.com") || strings.HasSuffix(host, "bitbucket.org")) && p.RepoURL == "" {
+		p.RepoURL = link
+	} else if p.DemoURL == "" {
+		p.DemoURL = link
+	}
+}
+
+// isProse reports whether a line reads as a sentence rather than a heading
+func isProse(line string) bool {
+	return len(strings.Fields(line)) > 12 || (strings.HasSuffix(line, ".") && len(strings.Fields(line)) > 4)
+}
+
+// isDetail reports whether a line is a "Label: value" line or a bare link
+func isDetail(line string) bool {
+	_, _, labelled := splitLabel(line)
+	return labelled || urlRe.FindString(line) == line
+}
+
+func looksLikeName(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 4 || countDigits(line) > 0 || strings.ContainsAny(line, "@|:/,") {
+		return false
+	}
+	for _, field := range fields {
+		if !startsWithLetter(field) {
+			return false
+		}
+	}
+	return !looksLikeRole(line)
+}
+
+func looksLikeRole(s string) bool {
+	return containsWord(s, roleWords)
+}
+
+func isInstitution(s string) bool {
+	return containsWord(s, institutionWords)
+}
+
+func isDegree(s string) bool {
+	return containsWord(s, degreeWords)
+}
+
+// splitDegree splits "Bachelor of Science in Computer Science" into the
+// degree and the field
+func splitDegree(s string) (string, string) {
+	lower := strings.ToLower(s)
+	for _, sep := range []string{" in ", " en ", " em "} {
+		if i := strings.Index(lower, sep); i > 0 {
+			return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(sep):])
+		}
+	}
+	return s, ""
+}
+
+func isTechnologiesLabel(label string) bool {
+	label = strings.ToLower(label)
+	return strings.Contains(label, "tech") || strings.Contains(label, "stack") || strings.Contains(label, "built with") || strings.Contains(label, "tools")
+}
+
+// skillCategory guesses the category of a labelled list of skills
+func skillCategory(label string) string {
+	label = strings.ToLower(label)
+	switch {
+	case strings.Contains(label, "language"):
+		return domain.SkillCategoryLanguage
+	case strings.Contains(label, "framework"), strings.Contains(label, "librar"):
+		return domain.SkillCategoryFramework
+	case strings.Contains(label, "database"), strings.Contains(label, "data store"):
+		return domain.SkillCategoryDatabase
+	case strings.Contains(label, "tool"), strings.Contains(label, "platform"), strings.Contains(label, "cloud"), strings.Contains(label, "devops"):
+		return domain.SkillCategoryTool
+	default:
+		return domain.SkillCategoryOther
+	}
+}
+
+func containsWord(s string, words map[string]bool) bool {
+	for _, field := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == ' ' || r == ',' || r == '(' || r == ')'
+	}) {
+		if words[strings.Trim(field, ".")] || words[field] {
+			return true
+		}
+	}
+	return false
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+func startsWithLetter(s string) bool {
+	for _, r := range s {
+		return r != '(' && !strings.ContainsRune("0123456789-*•#", r)
+	}
+	return false
+}
+
+// confidence clamps a score to 0-1 with two decimals
+func confidence(score float64) float64 {
+	return math.Round(math.Min(score, 1)*100) / 100
+}
+
+var companySuffixes = map[string]bool{
+	"inc": true, "llc": true, "ltd": true, "gmbh": true, "corp": true, "co": true, "plc": true, "s.a": true, "sa": true, "ag": true,
+}
+
+var roleWords = map[string]bool{
+	"engineer": true, "developer": true, "manager": true, "designer": true, "analyst": true, "intern": true,
+	"consultant": true, "director": true, "lead": true, "specialist": true, "scientist": true, "architect": true,
+	"administrator": true, "officer": true, "coordinator": true, "assistant": true, "programmer": true,
+	"head": true, "vp": true, "cto": true, "ceo": true, "founder": true, "co-founder": true, "associate": true,
+	"technician": true, "researcher": true, "teacher": true, "owner": true, "principal": true, "staff": true,
+}
+
+var institutionWords = map[string]bool{
+	"university": true, "college": true, "institute": true, "school": true, "academy": true, "polytechnic": true,
+	"universidad": true, "universidade": true, "université": true, "universität": true, "università": true,
+	"instituto": true, "escuela": true, "escola": true, "école": true, "hochschule": true, "bootcamp": true,
+}
+
+var degreeWords = map[string]bool{
+	"bachelor": true, "bachelor's": true, "master": true, "master's": true, "phd": true, "ph.d": true, "doctor": true,
+	"doctorate": true, "associate": true, "diploma": true, "certificate": true, "mba": true, "bs": true, "ba": true,
+	"bsc": true, "msc": true, "ms": true, "ma": true, "b.s": true, "b.a": true, "m.s": true, "m.a": true,
+	"b.sc": true, "m.sc": true, "beng": true, "meng": true, "licenciatura": true, "maestría": true, "ingeniería": true,
+	"licence": true, "bachillerato": true, "degree": true, "high": true,
+}
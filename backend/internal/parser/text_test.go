@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pastedResume = `Jane Doe
+Senior Software Engineer
+jane.doe@example.com | +1 202 555 0143
+San Francisco, CA
+
+SUMMARY
+Backend engineer who likes boring technology.
+
+Work Experience
+Senior Software Engineer at Acme Inc., Remote    Jan 2021 – Present
+- Led the migration of billing to Go
+- Cut p99 latency by 40%
+
+Globex Corp | Software Engineer
+06/2017 - 12/2020
+• Built the reporting pipeline
+
+EDUCATION
+Bachelor of Science in Computer Science, Stanford University, 2013 - 2017
+
+Skills:
+Languages: Go, Python, SQL
+Docker, Kubernetes, go
+
+Projects
+Resume Builder
+Generates PDF resumes from structured data.
+Technologies: Go, PostgreSQL
+https://github.com/jane/resume-builder
+
+Certifications
+Certified Kubernetes Administrator, CNCF, March 2022
+Credential ID: CKA-123
+
+INTERESTS
+Climbing
+`
+
+func TestParseText(t *testing.T) {
+	p := ParseText(pastedResume)
+
+	require.NotNil(t, p.PersonalInfo)
+	assert.Equal(t, "Jane", p.PersonalInfo.FirstName)
+	assert.Equal(t, "Doe", p.PersonalInfo.LastName)
+	assert.Equal(t, "Senior Software Engineer", p.PersonalInfo.JobTitle)
+	assert.Equal(t, "jane.doe@example.com", p.PersonalInfo.Email)
+	assert.Equal(t, "+1 202 555 0143", p.PersonalInfo.Phone)
+	assert.Equal(t, 1.0, p.PersonalInfo.Confidence)
+
+	require.Len(t, p.Experience, 2)
+	first := p.Experience[0]
+	assert.Equal(t, "Senior Software Engineer", first.JobTitle)
+	assert.Equal(t, "Acme Inc.", first.Employer)
+	assert.Equal(t, "Remote", first.Location)
+	assert.Equal(t, "2021-01-01", first.StartDate)
+	assert.Equal(t, "Present", first.EndDate)
+	assert.Equal(t, []string{"Led the migration of billing to Go", "Cut p99 latency by 40%"}, first.Achievements)
+	assert.Equal(t, 1.0, first.Confidence)
+
+	// The employer written first is recognized, and the dates on their own line are read
+	second := p.Experience[1]
+	assert.Equal(t, "Software Engineer", second.JobTitle)
+	assert.Equal(t, "Globex Corp", second.Employer)
+	assert.Equal(t, "2017-06-01", second.StartDate)
+	assert.Equal(t, "2020-12-01", second.EndDate)
+	assert.Equal(t, []string{"Built the reporting pipeline"}, second.Achievements)
+
+	require.Len(t, p.Education, 1)
+	assert.Equal(t, "Bachelor of Science", p.Education[0].Degree)
+	assert.Equal(t, "Computer Science", p.Education[0].Field)
+	assert.Equal(t, "Stanford University", p.Education[0].Institution)
+	assert.Equal(t, "2013-01-01", p.Education[0].StartDate)
+	assert.Equal(t, 1.0, p.Education[0].Confidence)
+
+	names := make([]string, len(p.Skills))
+	for i, s := range p.Skills {
+		names[i] = s.Name
+	}
+	// Duplicates are dropped whatever their case
+	assert.Equal(t, []string{"Go", "Python", "SQL", "Docker", "Kubernetes"}, names)
+	assert.Equal(t, "language", p.Skills[0].Category)
+	assert.Greater(t, p.Skills[0].Confidence, p.Skills[3].Confidence)
+
+	require.Len(t, p.Projects, 1)
+	assert.Equal(t, "Resume Builder", p.Projects[0].Name)
+	assert.Equal(t, "Generates PDF resumes from structured data.", p.Projects[0].Description)
+	assert.Equal(t, []string{"Go", "PostgreSQL"}, p.Projects[0].Technologies)
+	assert.Equal(t, "https://github.com/jane/resume-builder", p.Projects[0].RepoURL)
+
+	require.Len(t, p.Certifications, 1)
+	assert.Equal(t, "Certified Kubernetes Administrator", p.Certifications[0].Name)
+	assert.Equal(t, "CNCF", p.Certifications[0].Issuer)
+	assert.Equal(t, "2022-03-01", p.Certifications[0].IssueDate)
+	assert.Equal(t, "CKA-123", p.Certifications[0].CredentialID)
+	assert.Equal(t, 1.0, p.Certifications[0].Confidence)
+
+	assert.Equal(t, []string{"San Francisco, CA", "Backend engineer who likes boring technology.", "Climbing"}, p.Unrecognized)
+}
+
+func TestParseTextIncompleteEntries(t *testing.T) {
+	p := ParseText("Experiencia\nDesarrolladora, Initech\nmarzo de 2019 – Actualidad\n\nSomething I did\n")
+
+	assert.Nil(t, p.PersonalInfo)
+	require.Len(t, p.Experience, 2)
+	assert.Equal(t, "2019-03-01", p.Experience[0].StartDate)
+	assert.Equal(t, "Present", p.Experience[0].EndDate)
+	// A line without dates or an employer is a doubtful entry
+	assert.Less(t, p.Experience[1].Confidence, 0.5)
+}
+
+func TestParseDate(t *testing.T) {
+	tests := map[string]string{
+		"Mar 2021":      "2021-03-01",
+		"Sept. 2019":    "2019-09-01",
+		"juillet 2020":  "2020-07-01",
+		"marzo de 2021": "2021-03-01",
+		"03/2021":       "2021-03-01",
+		"2021-03":       "2021-03-01",
+		"2021-03-15":    "2021-03-15",
+		"2021":          "2021-01-01",
+		"Present":       "Present",
+		"Actualidad":    "Present",
+	}
+	for input, want := range tests {
+		got, ok := parseDate(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"13/2021", "jui 2020", "1850", "2021-02-30"} {
+		_, ok := parseDate(input)
+		assert.False(t, ok, input)
+	}
+}
@@ -0,0 +1,181 @@
+package proofread
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Basic rule IDs
+const (
+	RuleRepeatedWord     = "REPEATED_WORD"
+	RuleDoubleSpace      = "DOUBLE_SPACE"
+	RuleSpaceBeforePunct = "SPACE_BEFORE_PUNCTUATION"
+	RuleSentenceCase     = "SENTENCE_CASE"
+	RuleMisspelling      = "MISSPELLING"
+	RuleLowercaseI       = "LOWERCASE_I"
+)
+
+var (
+	wordRe             = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’][\p{L}]+)*`)
+	doubleSpaceRe      = regexp.MustCompile(` {2,}`)
+	spaceBeforePunctRe = regexp.MustCompile(`( +)[,.;:!?]`)
+	sentenceStartRe    = regexp.MustCompile(`([\p{L}\p{N}]*)[.!?]\s+(\p{Ll})`)
+)
+
+// abbreviations end in a period without ending the sentence
+var abbreviations = map[string]bool{"e.g": true, "eg": true, "i.e": true, "ie": true, "etc": true, "vs": true, "approx": true, "incl": true}
+
+// misspellings maps common English misspellings to their correction
+var misspellings = map[string]string{
+	"accomodate":    "accommodate",
+	"acheive":       "achieve",
+	"acheived":      "achieved",
+	"acheivement":   "achievement",
+	"acheivements":  "achievements",
+	"adress":        "address",
+	"begining":      "beginning",
+	"beleive":       "believe",
+	"buisness":      "business",
+	"calender":      "calendar",
+	"collegue":      "colleague",
+	"collegues":     "colleagues",
+	"comittee":      "committee",
+	"commited":      "committed",
+	"definately":    "definitely",
+	"developement":  "development",
+	"enviroment":    "environment",
+	"enviroments":   "environments",
+	"existance":     "existence",
+	"experiance":    "experience",
+	"goverment":     "government",
+	"implemention":  "implementation",
+	"independant":   "independent",
+	"infrastucture": "infrastructure",
+	"maintainance":  "maintenance",
+	"managment":     "management",
+	"occured":       "occurred",
+	"occurence":     "occurrence",
+	"oppurtunity":   "opportunity",
+	"perfomance":    "performance",
+	"recieve":       "receive",
+	"recieved":      "received",
+	"reponsible":    "responsible",
+	"responsable":   "responsible",
+	"seperate":      "separate",
+	"seperately":    "separately",
+	"succesful":     "successful",
+	"succesfully":   "successfully",
+	"sucessful":     "successful",
+	"teh":           "the",
+	"tommorow":      "tomorrow",
+	"untill":        "until",
+	"wich":          "which",
+}
+
+// Basic is an offline checker with a few language-neutral rules, such as
+// repeated words and stray spaces, and for English a list of common
+// misspellings. It catches far less than a real grammar checker.
+type Basic struct{}
+
+// NewBasic creates a new basic checker
+func NewBasic() *Basic {
+	return &Basic{}
+}
+
+// Name returns the checker's name
+func (b *Basic) Name() string {
+	return "basic"
+}
+
+// Check checks text, applying the English rules when language is English
+// or empty
+func (b *Basic) Check(ctx context.Context, text, language string) ([]Match, error) {
+	english := language == "" || language == "en" || strings.HasPrefix(language, "en-")
+	var matches []Match
+	add := func(start, end int, rule, message string, replacements ...string) {
+		matches = append(matches, Match{
+			Offset:       utf16Len(text[:start]),
+			Length:       utf16Len(text[start:end]),
+			Message:      message,
+			Rule:         rule,
+			Replacements: append([]string{}, replacements...),
+		})
+	}
+
+	words := wordRe.FindAllStringIndex(text, -1)
+	for i, w := range words {
+		word := text[w[0]:w[1]]
+		if i > 0 {
+			prev := words[i-1]
+			if strings.TrimSpace(text[prev[1]:w[0]]) == "" && strings.EqualFold(text[prev[0]:prev[1]], word) && !isNumber(word) {
+				add(prev[0], w[1], RuleRepeatedWord, "Possible typo: you repeated a word", text[prev[0]:prev[1]])
+			}
+		}
+		if !english {
+			continue
+		}
+		if word == "i" {
+			add(w[0], w[1], RuleLowercaseI, "The pronoun \"I\" is capitalized", "I")
+		}
+		if correction, ok := misspellings[strings.ToLower(word)]; ok {
+			add(w[0], w[1], RuleMisspelling, "Possible spelling mistake", matchCase(correction, word))
+		}
+	}
+
+	// Only spaces between words count, not indentation
+	for _, m := range doubleSpaceRe.FindAllStringIndex(text, -1) {
+		if betweenWords(text, m[0], m[1]) {
+			add(m[0], m[1], RuleDoubleSpace, "Possible typo: several spaces in a row", " ")
+		}
+	}
+	for _, m := range spaceBeforePunctRe.FindAllStringSubmatchIndex(text, -1) {
+		if betweenWords(text, m[2], m[3]) {
+			add(m[2], m[3], RuleSpaceBeforePunct, "Don't put a space before punctuation", "")
+		}
+	}
+	for _, m := range sentenceStartRe.FindAllStringSubmatchIndex(text, -1) {
+		// A period after an abbreviation doesn't end the sentence
+		if abbreviations[strings.ToLower(lastWord(text[:m[3]]))] {
+			continue
+		}
+		letter := text[m[4]:m[5]]
+		add(m[4], m[5], RuleSentenceCase, "A sentence should start with a capital letter", strings.ToUpper(letter))
+	}
+
+	slices.SortFunc(matches, func(a, b Match) int { return a.Offset - b.Offset })
+	return matches, nil
+}
+
+// betweenWords reports whether text[start:end] has other text than
+// whitespace on both sides on its line
+func betweenWords(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return start > 0 && end < len(text) && !unicode.IsSpace(before) && !unicode.IsSpace(after)
+}
+
+// lastWord returns the word, dots included, that ends s
+func lastWord(s string) string {
+	i := strings.LastIndexFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '(' })
+	return s[i+1:]
+}
+
+// isNumber reports whether a word is all digits, which may repeat in
+// version numbers and dates
+func isNumber(word string) bool {
+	return strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
+
+// matchCase capitalizes correction when word is capitalized
+func matchCase(correction, word string) string {
+	first, _ := utf8.DecodeRuneInString(word)
+	if unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(correction)
+		return string(unicode.ToUpper(r)) + correction[size:]
+	}
+	return correction
+}
@@ -0,0 +1,123 @@
+package proofread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxLanguageToolResponseSize caps the check responses read
+const maxLanguageToolResponseSize = 4 << 20
+
+// LanguageToolConfig contains the configuration for a LanguageTool server
+type LanguageToolConfig struct {
+	// BaseURL is the server root the /v2/check path is appended to
+	BaseURL string
+	// Username and APIKey authenticate LanguageTool Premium requests
+	Username string
+	APIKey   string
+	// HTTPClient makes the requests; a client with a 30s timeout is used
+	// when nil
+	HTTPClient *http.Client
+}
+
+// LanguageTool checks text with a LanguageTool server's HTTP API
+type LanguageTool struct {
+	config LanguageToolConfig
+}
+
+// NewLanguageTool creates a new LanguageTool checker
+func NewLanguageTool(config LanguageToolConfig) *LanguageTool {
+	// Set defaults if not provided
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &LanguageTool{
+		config: config,
+	}
+}
+
+// Name returns the checker's name
+func (l *LanguageTool) Name() string {
+	return "languagetool"
+}
+
+type languageToolResponse struct {
+	Matches []struct {
+		Message      string `json:"message"`
+		Offset       int    `json:"offset"`
+		Length       int    `json:"length"`
+		Replacements []struct {
+			Value string `json:"value"`
+		} `json:"replacements"`
+		Rule struct {
+			ID string `json:"id"`
+		} `json:"rule"`
+	} `json:"matches"`
+}
+
+// maxReplacements caps the replacements kept for each match
+const maxReplacements = 5
+
+// Check sends text to the server's /v2/check endpoint
+func (l *LanguageTool) Check(ctx context.Context, text, language string) ([]Match, error) {
+	if language == "" {
+		language = "auto"
+	}
+	form := url.Values{"text": {text}, "language": {language}}
+	if l.config.Username != "" && l.config.APIKey != "" {
+		form.Set("username", l.config.Username)
+		form.Set("apiKey", l.config.APIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.config.BaseURL+"/v2/check", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLanguageToolResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %d: %s", ErrUnavailable, req.URL.Host, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	var reply languageToolResponse
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	matches := make([]Match, 0, len(reply.Matches))
+	for _, m := range reply.Matches {
+		match := Match{
+			Offset:       m.Offset,
+			Length:       m.Length,
+			Message:      m.Message,
+			Rule:         m.Rule.ID,
+			Replacements: []string{},
+		}
+		for i, r := range m.Replacements {
+			if i == maxReplacements {
+				break
+			}
+			match.Replacements = append(match.Replacements, r.Value)
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
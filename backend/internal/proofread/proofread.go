@@ -0,0 +1,108 @@
+// Package proofread checks resume text for spelling and grammar issues with
+// a pluggable checker: a LanguageTool server, or a basic offline checker.
+package proofread
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrUnavailable wraps failures to reach a checker's service or read its reply
+var ErrUnavailable = errors.New("checker unavailable")
+
+// Match is an issue a checker found in a text. Offsets and lengths count
+// UTF-16 code units, as JavaScript strings do, so the frontend can highlight
+// matches directly.
+type Match struct {
+	Offset       int      `json:"offset"`
+	Length       int      `json:"length"`
+	Message      string   `json:"message"`
+	Rule         string   `json:"rule"`
+	Replacements []string `json:"replacements"`
+}
+
+// Checker finds issues in text written in language, a BCP 47 tag; an empty
+// language lets the checker detect it
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, text, language string) ([]Match, error)
+}
+
+// Text is a field of a resume to proofread
+type Text struct {
+	// Section is the resume section, such as "experience"
+	Section string
+	// Index is the position of the entry in its section
+	Index int
+	// Field is the JSON name of the field, such as "description"
+	Field string
+	Text  string
+}
+
+// Issue is a match in a field of a resume
+type Issue struct {
+	Section string `json:"section"`
+	Index   int    `json:"index"`
+	Field   string `json:"field"`
+	Match
+}
+
+// separator separates texts checked together, ending any sentence
+const separator = "\n\n"
+
+// Proofread checks texts with a single call to checker, reporting issues by
+// field. Offsets are relative to the field's text.
+func Proofread(ctx context.Context, checker Checker, language string, texts []Text) ([]Issue, error) {
+	issues := []Issue{}
+	var b strings.Builder
+	starts := make([]int, 0, len(texts))
+	offset := 0
+	for _, t := range texts {
+		if strings.TrimSpace(t.Text) == "" {
+			starts = append(starts, -1)
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(separator)
+			offset += len(separator)
+		}
+		starts = append(starts, offset)
+		b.WriteString(t.Text)
+		offset += utf16Len(t.Text)
+	}
+	if b.Len() == 0 {
+		return issues, nil
+	}
+
+	matches, err := checker.Check(ctx, b.String(), language)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range matches {
+		for i, t := range texts {
+			start := starts[i]
+			if start < 0 || m.Offset < start || m.Offset+m.Length > start+utf16Len(t.Text) {
+				continue
+			}
+			m.Offset -= start
+			if m.Replacements == nil {
+				m.Replacements = []string{}
+			}
+			issues = append(issues, Issue{Section: t.Section, Index: t.Index, Field: t.Field, Match: m})
+			break
+		}
+	}
+	return issues, nil
+}
+
+// utf16Len returns the length of s in UTF-16 code units
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
@@ -0,0 +1,87 @@
+package proofread
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rules(matches []Match) []string {
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.Rule)
+	}
+	return ids
+}
+
+func TestBasicCheck(t *testing.T) {
+	b := NewBasic()
+	ctx := context.Background()
+
+	matches, err := b.Check(ctx, "Led the the team .  then i recieved awards, e.g. trophies.", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []string{RuleRepeatedWord, RuleSpaceBeforePunct, RuleDoubleSpace, RuleSentenceCase, RuleLowercaseI, RuleMisspelling}, rules(matches))
+	assert.Equal(t, Match{Offset: 4, Length: 7, Message: "Possible typo: you repeated a word", Rule: RuleRepeatedWord, Replacements: []string{"the"}}, matches[0])
+	assert.Equal(t, []string{"received"}, matches[5].Replacements)
+
+	// Offsets count UTF-16 code units
+	matches, err = b.Check(ctx, "🚀 Teh launch", "en-US")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, 3, matches[0].Offset)
+	assert.Equal(t, []string{"The"}, matches[0].Replacements)
+
+	// English rules only apply to English
+	matches, err = b.Check(ctx, "recieve de de", "es")
+	require.NoError(t, err)
+	assert.Equal(t, []string{RuleRepeatedWord}, rules(matches))
+
+	// Indentation and repeated numbers are fine
+	matches, err = b.Check(ctx, "Version 2 2\n  Indented", "")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestProofread(t *testing.T) {
+	texts := []Text{
+		{Section: "experience", Index: 0, Field: "title", Text: "Engineer"},
+		{Section: "experience", Index: 0, Field: "description", Text: ""},
+		{Section: "experience", Index: 1, Field: "description", Text: "Built teh platform"},
+	}
+
+	issues, err := Proofread(context.Background(), NewBasic(), "en", texts)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "experience", issues[0].Section)
+	assert.Equal(t, 1, issues[0].Index)
+	assert.Equal(t, "description", issues[0].Field)
+	assert.Equal(t, 6, issues[0].Offset)
+
+	issues, err = Proofread(context.Background(), NewBasic(), "en", nil)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestLanguageToolCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/check", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "auto", r.Form.Get("language"))
+		assert.Equal(t, "Built teh platform", r.Form.Get("text"))
+		assert.Empty(t, r.Form.Get("apiKey"))
+		w.Write([]byte(`{"matches": [{"message": "Possible spelling mistake found.", "offset": 6, "length": 3, "replacements": [{"value": "the"}, {"value": "tech"}], "rule": {"id": "MORFOLOGIK_RULE_EN_US"}}]}`))
+	}))
+	defer srv.Close()
+
+	matches, err := NewLanguageTool(LanguageToolConfig{BaseURL: srv.URL + "/"}).Check(context.Background(), "Built teh platform", "")
+	require.NoError(t, err)
+	assert.Equal(t, []Match{{Offset: 6, Length: 3, Message: "Possible spelling mistake found.", Rule: "MORFOLOGIK_RULE_EN_US", Replacements: []string{"the", "tech"}}}, matches)
+
+	srv.Close()
+	_, err = NewLanguageTool(LanguageToolConfig{BaseURL: srv.URL}).Check(context.Background(), "text", "en")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
@@ -0,0 +1,151 @@
+package render
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// condenseSteps are the page margins, in points, and font scales tried in
+// turn to fit a resume on one page. The last step is as far as condensing
+// goes before omitting entries.
+var condenseSteps = []struct{ margin, scale float64 }{
+	{pdfMargin, 1},
+	{48, 0.95},
+	{42, 0.9},
+	{36, 0.85},
+}
+
+// Condensed is how a resume is condensed to fit on one page
+type Condensed struct {
+	// Fits is false when the resume takes more than a page even with every
+	// entry below the top priority omitted; nothing is omitted then
+	Fits bool `json:"fits"`
+	// Margin is the page margin, in points
+	Margin float64 `json:"margin"`
+	// FontScale is the factor every font size is scaled by
+	FontScale float64 `json:"font_scale"`
+	// Omitted lists the entries left out, in the order they were dropped
+	Omitted []OmittedEntry `json:"omitted"`
+}
+
+// OmittedEntry is an entry a condensed resume leaves out
+type OmittedEntry struct {
+	// Section is one of domain.LayoutSections
+	Section string `json:"section"`
+	// Entry is the entry's position in the section, counting from 0
+	Entry    int    `json:"entry"`
+	Title    string `json:"title"`
+	Priority int    `json:"priority"`
+}
+
+// CondensePDF works out how to fit a resume on one page as PDF. Margins
+// narrow and fonts shrink a step at a time; if that isn't enough, entries
+// are omitted lowest priority first and, among equal priorities, last first.
+// Entries with domain.MaxEntryPriority are never omitted. Page-break hints
+// don't apply to one-page exports.
+func CondensePDF(resume *domain.Resume) *Condensed {
+	settings := condensedSettings(resume)
+	for _, step := range condenseSteps {
+		c := &Condensed{Fits: true, Margin: step.margin, FontScale: step.scale, Omitted: []OmittedEntry{}}
+		if c.pages(resume, settings) == 1 {
+			return c
+		}
+	}
+
+	last := condenseSteps[len(condenseSteps)-1]
+	c := &Condensed{Margin: last.margin, FontScale: last.scale, Omitted: omittableEntries(resume, settings.Layout)}
+	if c.pages(resume, settings) > 1 {
+		c.Omitted = []OmittedEntry{}
+		return c
+	}
+
+	c.Fits = true
+	candidates := c.Omitted
+	for n := 1; n <= len(candidates); n++ {
+		c.Omitted = candidates[:n]
+		if c.pages(resume, settings) == 1 {
+			break
+		}
+	}
+	c.Omitted = slices.Clip(c.Omitted)
+	return c
+}
+
+// PDF renders a resume as PDF condensed the way CondensePDF worked out
+func (c *Condensed) PDF(resume *domain.Resume) []byte {
+	l, title := c.layout(resume, condensedSettings(resume))
+	return writePDF(l.finish(), l.page.width, l.page.height, title)
+}
+
+// pages returns how many pages the condensed resume takes
+func (c *Condensed) pages(resume *domain.Resume, settings domain.ResumeSettings) int {
+	l, _ := c.layout(resume, settings)
+	return len(l.finish())
+}
+
+// layout sets the resume without its omitted entries
+func (c *Condensed) layout(resume *domain.Resume, settings domain.ResumeSettings) (*pdfLayout, string) {
+	kept := *resume
+	kept.Experience = keepEntries(resume.Experience, domain.LayoutSectionExperience, c.Omitted)
+	kept.Education = keepEntries(resume.Education, domain.LayoutSectionEducation, c.Omitted)
+	kept.Projects = keepEntries(resume.Projects, domain.LayoutSectionProjects, c.Omitted)
+	kept.Certifications = keepEntries(resume.Certifications, domain.LayoutSectionCertifications, c.Omitted)
+
+	l := newPDFLayout(pdfPageSizes[settings.PaperSize], c.Margin, c.FontScale)
+	title := l.resume(&kept, settings)
+	return l, title
+}
+
+// condensedSettings returns the resolved settings of a resume without its
+// page-break hints
+func condensedSettings(resume *domain.Resume) domain.ResumeSettings {
+	settings := resume.Settings.Resolve(resume.Language)
+	settings.Layout.PageBreaks = nil
+	return settings
+}
+
+// keepEntries returns the entries of a section that aren't omitted
+func keepEntries[T any](entries []T, section string, omitted []OmittedEntry) []T {
+	var kept []T
+	for i, entry := range entries {
+		if !slices.ContainsFunc(omitted, func(o OmittedEntry) bool { return o.Section == section && o.Entry == i }) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// omittableEntries lists the entries condensing may omit, in the order it
+// omits them
+func omittableEntries(resume *domain.Resume, layout domain.ResumeLayout) []OmittedEntry {
+	var entries []OmittedEntry
+	add := func(section string, entry int, title string) {
+		if priority := layout.Priority(section, entry); priority < domain.MaxEntryPriority {
+			entries = append(entries, OmittedEntry{Section: section, Entry: entry, Title: title, Priority: priority})
+		}
+	}
+	for i, e := range resume.Experience {
+		add(domain.LayoutSectionExperience, i, e.JobTitle+" — "+e.Employer)
+	}
+	for i, e := range resume.Education {
+		add(domain.LayoutSectionEducation, i, joinNonEmpty(", ", e.Degree, e.Field)+" — "+e.Institution)
+	}
+	for i, p := range resume.Projects {
+		add(domain.LayoutSectionProjects, i, p.Name)
+	}
+	for i, cert := range resume.Certifications {
+		add(domain.LayoutSectionCertifications, i, cert.Name)
+	}
+
+	// Lowest priority first, then the last entries of the last sections
+	slices.SortStableFunc(entries, func(a, b OmittedEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.Priority, b.Priority),
+			cmp.Compare(b.Entry, a.Entry),
+			cmp.Compare(slices.Index(domain.LayoutSections, b.Section), slices.Index(domain.LayoutSections, a.Section)),
+		)
+	})
+	return entries
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// condenseResume returns a resume with n experience entries
+func condenseResume(n int) *domain.Resume {
+	resume := &domain.Resume{PersonalInfo: &domain.PersonalInfo{FirstName: "Ana"}}
+	for i := 0; i < n; i++ {
+		resume.Experience = append(resume.Experience, &domain.Experience{
+			Employer: "Acme " + strconv.Itoa(i), JobTitle: "Engineer", StartDate: "2020-01-01",
+			Description: strings.Repeat("Built and ran the services behind the product. ", 4),
+		})
+	}
+	resume.Projects = []*domain.Project{{Name: "Compiler", Description: "A small compiler."}}
+	return resume
+}
+
+func TestCondensePDF(t *testing.T) {
+	// A resume that fits isn't condensed
+	c := CondensePDF(condenseResume(2))
+	assert.Equal(t, &Condensed{Fits: true, Margin: pdfMargin, FontScale: 1, Omitted: []OmittedEntry{}}, c)
+
+	// A little over a page shrinks margins and fonts
+	long := condenseResume(9)
+	require.Len(t, pdfPages(t, PDF(long)), 2)
+	c = CondensePDF(long)
+	assert.True(t, c.Fits)
+	assert.Less(t, c.FontScale, 1.0)
+	assert.Empty(t, c.Omitted)
+	assert.Len(t, pdfPages(t, c.PDF(long)), 1)
+
+	// Further over, entries are omitted lowest priority first, then last first
+	longer := condenseResume(16)
+	longer.Settings.Layout.Priorities = []domain.EntryPriority{
+		{Section: domain.LayoutSectionExperience, Entry: 3, Priority: 1},
+		{Section: domain.LayoutSectionExperience, Entry: 15, Priority: domain.MaxEntryPriority},
+		{Section: domain.LayoutSectionProjects, Entry: 0, Priority: domain.MaxEntryPriority},
+	}
+	c = CondensePDF(longer)
+	assert.True(t, c.Fits)
+	assert.Equal(t, condenseSteps[len(condenseSteps)-1].scale, c.FontScale)
+	require.Greater(t, len(c.Omitted), 1)
+	assert.Equal(t, OmittedEntry{Section: domain.LayoutSectionExperience, Entry: 3, Title: "Engineer — Acme 3", Priority: 1}, c.Omitted[0])
+	assert.Equal(t, 14, c.Omitted[1].Entry)
+	for _, omitted := range c.Omitted {
+		assert.NotEqual(t, 15, omitted.Entry)
+		assert.Equal(t, domain.LayoutSectionExperience, omitted.Section)
+	}
+
+	pages := pdfPages(t, c.PDF(longer))
+	require.Len(t, pages, 1)
+	assert.Contains(t, pages[0], "Acme 15)")
+	assert.Contains(t, pages[0], "(Compiler)")
+	assert.NotContains(t, pages[0], "Acme 3)")
+	assert.NotContains(t, pages[0], "Acme 14)")
+}
+
+func TestCondensePDFDoesNotFit(t *testing.T) {
+	resume := condenseResume(40)
+	resume.Settings.Layout.Priorities = []domain.EntryPriority{{Section: domain.LayoutSectionProjects, Entry: 0, Priority: domain.MaxEntryPriority}}
+	for i := range resume.Experience {
+		resume.Settings.Layout.Priorities = append(resume.Settings.Layout.Priorities, domain.EntryPriority{
+			Section: domain.LayoutSectionExperience, Entry: i, Priority: domain.MaxEntryPriority,
+		})
+	}
+
+	// Nothing is omitted when omitting can't make the resume fit
+	c := CondensePDF(resume)
+	assert.False(t, c.Fits)
+	assert.Empty(t, c.Omitted)
+	assert.Greater(t, len(pdfPages(t, c.PDF(resume))), 1)
+}
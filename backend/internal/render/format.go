@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/locale"
+	"github.com/lordaris/resume_generator/pkg/phone"
+)
+
+// Format is a document type resumes can be exported as
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	Render      func(resume *domain.Resume) []byte
+	// check reports what is wrong with a rendered document
+	check func(document []byte) error
+}
+
+// Formats are the export formats
+var Formats = []Format{
+	{
+		Name:        "markdown",
+		ContentType: "text/markdown; charset=utf-8",
+		Extension:   ".md",
+		Render:      Markdown,
+		check: func(document []byte) error {
+			if !utf8.Valid(document) {
+				return errors.New("output is not valid UTF-8")
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "pdf",
+		ContentType: "application/pdf",
+		Extension:   ".pdf",
+		Render:      PDF,
+		check: func(document []byte) error {
+			if !bytes.HasPrefix(document, []byte("%PDF-")) || !bytes.HasSuffix(document, []byte("%%EOF\n")) {
+				return errors.New("output is not a complete PDF file")
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "html",
+		ContentType: "text/html; charset=utf-8",
+		Extension:   ".html",
+		Render:      HTML,
+		check: func(document []byte) error {
+			if !utf8.Valid(document) {
+				return errors.New("output is not valid UTF-8")
+			}
+			if !bytes.HasSuffix(document, []byte("</html>\n")) {
+				return errors.New("output is not a complete HTML page")
+			}
+			return nil
+		},
+	},
+}
+
+// catalog returns the text of a resume's language, writing dates in the
+// style of its settings
+func catalog(resume *domain.Resume, settings domain.ResumeSettings) *locale.Catalog {
+	return locale.For(resume.Language).WithDateStyle(settings.DateStyle)
+}
+
+// displayPhone returns the phone number of personal info as the settings
+// display it
+func displayPhone(info *domain.PersonalInfo, settings domain.ResumeSettings) string {
+	if info.Phone == "" {
+		return ""
+	}
+	if settings.PhoneDisplay == domain.PhoneInternational {
+		return phone.Display(info.Phone, "")
+	}
+	if info.PhoneDisplay != "" {
+		return info.PhoneDisplay
+	}
+	return info.Phone
+}
+
+// SelfCheck renders a sample resume in every format and language, returning
+// the first failure. It runs at startup so a broken renderer stops the
+// server instead of failing a user's export.
+func SelfCheck() error {
+	for _, lang := range locale.Languages() {
+		resume := sampleResume(lang)
+		for _, format := range Formats {
+			if err := checkFormat(format, resume); err != nil {
+				return fmt.Errorf("%s export in %q: %w", format.Name, lang, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkFormat renders a resume, turning a panic into an error
+func checkFormat(format Format, resume *domain.Resume) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	document := format.Render(resume)
+	if len(document) == 0 {
+		return errors.New("output is empty")
+	}
+	if format.check != nil {
+		return format.check(document)
+	}
+	return nil
+}
+
+// sampleResume returns a resume with every section filled in, including
+// text the renderers must escape or replace
+func sampleResume(lang string) *domain.Resume {
+	return &domain.Resume{
+		ResumeMetadata: domain.ResumeMetadata{Title: "Sample resume", Language: lang},
+		PersonalInfo: &domain.PersonalInfo{
+			FirstName: "Ana", LastName: "García", JobTitle: "Software Engineer",
+			Email: "ana@example.com", Phone: "+34 600 000 000",
+			Address: domain.Address{City: "Madrid", Country: "ES"},
+		},
+		Experience: []*domain.Experience{{
+			Employer: "Acme (Madrid)", JobTitle: "Engineer", Location: "Madrid",
+			StartDate: "2021-03-01", EndDate: "Present",
+			Description:  "Built the services *behind* the product [and] ran them.",
+			Achievements: []string{"Cut costs by 20%", "Led a team of 5 — in three time zones"},
+		}},
+		Education: []*domain.Education{{
+			Institution: "Universidad Politécnica", Degree: "BSc", Field: "Computer Science",
+			StartDate: "2015-09-01", EndDate: "2019-06-30",
+		}},
+		Skills: []*domain.Skill{
+			{Name: "Go", Category: "Languages", Proficiency: 5},
+			{Name: "日本語"},
+		},
+		Projects: []*domain.Project{{
+			Name: "resume_generator", Description: "Resume builder",
+			Technologies: []string{"Go", "PostgreSQL"}, RepoURL: "https://example.com/repo",
+			StartDate: "2023-01-01",
+		}},
+		Certifications: []*domain.Certification{{
+			Name: "Cloud Architect", Issuer: "Example", IssueDate: "2022-05-01", ExpiryDate: "2025-05-01",
+		}},
+	}
+}
@@ -0,0 +1,29 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfCheck(t *testing.T) {
+	require.NoError(t, SelfCheck())
+}
+
+func TestSelfCheckReportsBrokenFormat(t *testing.T) {
+	formats := Formats
+	t.Cleanup(func() { Formats = formats })
+
+	Formats = append([]Format{}, formats...)
+	Formats = append(Formats, Format{
+		Name:   "broken",
+		Render: func(resume *domain.Resume) []byte { panic("template not found") },
+	})
+
+	err := SelfCheck()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken export")
+	assert.Contains(t, err.Error(), "template not found")
+}
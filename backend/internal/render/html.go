@@ -0,0 +1,284 @@
+package render
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/locale"
+)
+
+// maxDescriptionLength is the length search engines show of a page's
+// description, in characters
+const maxDescriptionLength = 160
+
+// htmlTemplate renders a resume as a standalone page. Styles are inline so the
+// page needs nothing else from the server.
+var htmlTemplate = template.Must(template.New("resume").Funcs(template.FuncMap{
+	"join": joinNonEmpty,
+	"list": func(items []string) string { return strings.Join(items, ", ") },
+}).Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Title}}</title>
+  {{- with .Description}}
+  <meta name="description" content="{{.}}">
+  {{- end}}
+  <meta property="og:type" content="profile">
+  <meta property="og:title" content="{{.Title}}">
+  {{- with .Description}}
+  <meta property="og:description" content="{{.}}">
+  {{- end}}
+  {{- with .CanonicalURL}}
+  <meta property="og:url" content="{{.}}">
+  <link rel="canonical" href="{{.}}">
+  {{- end}}
+  {{- with .Person.Image}}
+  <meta property="og:image" content="{{.}}">
+  {{- end}}
+  <script type="application/ld+json">{{.Person}}</script>
+  <style>
+    @page { size: {{.PageSize}}; }
+    body { font-family: Helvetica, Arial, sans-serif; line-height: 1.5; color: #222; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; }
+    h1 { margin-bottom: 0; }
+    h2 { border-bottom: 1px solid #ccc; margin-top: 2rem; }
+    h3 { margin-bottom: 0; }
+    .muted { color: #666; }
+    ul.skills { padding-left: 1.25rem; }
+    img.photo { float: right; width: 8rem; height: 8rem; object-fit: cover; border-radius: 50%; }
+  </style>
+</head>
+<body>
+<header>
+  {{- with .Info}}
+  {{- with .PhotoURL}}
+  <img class="photo" src="{{.}}" alt="">
+  {{- end}}
+  <h1>{{join " " .FirstName .LastName}}</h1>
+  {{- with .JobTitle}}
+  <p><strong>{{.}}</strong></p>
+  {{- end}}
+  {{- with $.Contact}}
+  <p class="muted">{{.}}</p>
+  {{- end}}
+  {{- else}}
+  <h1>{{.Resume.Title}}</h1>
+  {{- end}}
+</header>
+{{- with .Resume.Experience}}
+<section>
+  <h2>{{$.Locale.Headings.Experience}}</h2>
+  {{- range .}}
+  <article>
+    <h3>{{join " — " .JobTitle .Employer}}</h3>
+    <p class="muted">{{join " · " ($.Locale.DateRange .StartDate .EndDate) .Location}}</p>
+    {{- with .Description}}
+    <p>{{.}}</p>
+    {{- end}}
+    {{- with .Achievements}}
+    <ul>
+      {{- range .}}
+      <li>{{.}}</li>
+      {{- end}}
+    </ul>
+    {{- end}}
+  </article>
+  {{- end}}
+</section>
+{{- end}}
+{{- with .Resume.Education}}
+<section>
+  <h2>{{$.Locale.Headings.Education}}</h2>
+  {{- range .}}
+  <article>
+    <h3>{{join " — " (join ", " .Degree .Field) .Institution}}</h3>
+    <p class="muted">{{join " · " ($.Locale.DateRange .StartDate .EndDate) .Location}}</p>
+    {{- with .Description}}
+    <p>{{.}}</p>
+    {{- end}}
+  </article>
+  {{- end}}
+</section>
+{{- end}}
+{{- with .Skills}}
+<section>
+  <h2>{{$.Locale.Headings.Skills}}</h2>
+  <ul class="skills">
+    {{- range .}}
+    <li>{{with .Category}}<strong>{{.}}:</strong> {{end}}{{.Names}}</li>
+    {{- end}}
+  </ul>
+</section>
+{{- end}}
+{{- with .Resume.Projects}}
+<section>
+  <h2>{{$.Locale.Headings.Projects}}</h2>
+  {{- range .}}
+  <article>
+    <h3>{{.Name}}</h3>
+    {{- if or .StartDate .EndDate}}
+    <p class="muted">{{$.Locale.DateRange .StartDate .EndDate}}</p>
+    {{- end}}
+    {{- with .Description}}
+    <p>{{.}}</p>
+    {{- end}}
+    {{- with .Technologies}}
+    <p class="muted">{{list .}}</p>
+    {{- end}}
+    {{- if or .RepoURL .DemoURL}}
+    <p>
+      {{- with .RepoURL}}<a href="{{.}}" rel="nofollow noopener">{{.}}</a>{{end}}
+      {{- if and .RepoURL .DemoURL}} · {{end}}
+      {{- with .DemoURL}}<a href="{{.}}" rel="nofollow noopener">{{.}}</a>{{end -}}
+    </p>
+    {{- end}}
+  </article>
+  {{- end}}
+</section>
+{{- end}}
+{{- with .Certifications}}
+<section>
+  <h2>{{$.Locale.Headings.Certifications}}</h2>
+  <ul>
+    {{- range .}}
+    <li>{{.}}</li>
+    {{- end}}
+  </ul>
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlSkillGroup is a line of the skills section
+type htmlSkillGroup struct {
+	Category string
+	Names    string
+}
+
+// htmlPerson is the schema.org description of the person a page is about,
+// which search engines read
+type htmlPerson struct {
+	Context  string `json:"@context"`
+	Type     string `json:"@type"`
+	Name     string `json:"name,omitempty"`
+	JobTitle string `json:"jobTitle,omitempty"`
+	// Image is the person's photo when its URL is absolute
+	Image string `json:"image,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// htmlPageData is what the page template renders
+type htmlPageData struct {
+	Lang  string
+	Title string
+	// PageSize is the CSS page size the page prints on
+	PageSize       string
+	Description    string
+	CanonicalURL   string
+	Person         htmlPerson
+	Resume         *domain.Resume
+	Info           *domain.PersonalInfo
+	Contact        string
+	Locale         *locale.Catalog
+	Skills         []htmlSkillGroup
+	Certifications []string
+}
+
+// htmlPageSizes are the CSS page sizes by paper size
+var htmlPageSizes = map[string]string{
+	locale.PaperA4:     "A4",
+	locale.PaperLetter: "letter",
+}
+
+// HTML renders a complete resume as a standalone HTML page
+func HTML(resume *domain.Resume) []byte {
+	return HTMLPage(resume, "")
+}
+
+// HTMLPage renders a complete resume as a standalone HTML page with the meta
+// tags search engines and link previews read. canonicalURL is where the page
+// is published; it is left out when empty.
+func HTMLPage(resume *domain.Resume, canonicalURL string) []byte {
+	settings := resume.Settings.Resolve(resume.Language)
+	c := catalog(resume, settings)
+	data := htmlPageData{
+		Lang:         resume.Language,
+		Title:        resume.Title,
+		PageSize:     htmlPageSizes[settings.PaperSize],
+		CanonicalURL: canonicalURL,
+		Person:       htmlPerson{Context: "https://schema.org", Type: "Person", URL: canonicalURL},
+		Resume:       resume,
+		Info:         resume.PersonalInfo,
+		Locale:       c,
+	}
+
+	if info := resume.PersonalInfo; info != nil {
+		name := joinNonEmpty(" ", info.FirstName, info.LastName)
+		data.Title = joinNonEmpty(" — ", name, info.JobTitle)
+		data.Person.Name, data.Person.JobTitle = name, info.JobTitle
+		// Link previews and search engines need absolute image URLs
+		if strings.HasPrefix(info.PhotoURL, "https://") || strings.HasPrefix(info.PhotoURL, "http://") {
+			data.Person.Image = info.PhotoURL
+		}
+
+		contact := []string{info.Email, displayPhone(info, settings)}
+		if !info.Address.IsZero() {
+			contact = append(contact, strings.Join(info.Address.Lines(), ", "))
+		}
+		data.Contact = joinNonEmpty(" · ", contact...)
+	}
+	if data.Title == "" {
+		data.Title = "Resume"
+	}
+	data.Description = pageDescription(resume)
+
+	for _, group := range domain.GroupSkills(resume.Skills) {
+		names := make([]string, len(group.Skills))
+		for i, s := range group.Skills {
+			names[i] = s.Name
+		}
+		data.Skills = append(data.Skills, htmlSkillGroup{Category: group.Category, Names: strings.Join(names, ", ")})
+	}
+	for _, cert := range resume.Certifications {
+		line := joinNonEmpty(" — ", cert.Name, cert.Issuer)
+		if cert.IssueDate != "" {
+			line += ", " + c.Date(cert.IssueDate)
+		}
+		if expiry := c.Date(cert.ExpiryDate); expiry != "" && expiry != cert.ExpiryDate {
+			line += " (" + c.Expires + " " + expiry + ")"
+		}
+		data.Certifications = append(data.Certifications, line)
+	}
+
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, data); err != nil {
+		// The template is fixed and its data well-formed, so this is a bug
+		panic("render: HTML template: " + err.Error())
+	}
+	return b.Bytes()
+}
+
+// pageDescription summarizes a resume for search results: the job title
+// followed by the latest role, cut to the length search engines show
+func pageDescription(resume *domain.Resume) string {
+	var parts []string
+	if resume.PersonalInfo != nil {
+		parts = append(parts, resume.PersonalInfo.JobTitle)
+	}
+	if len(resume.Experience) > 0 {
+		latest := resume.Experience[0]
+		parts = append(parts, joinNonEmpty(", ", latest.JobTitle, latest.Employer))
+	}
+	description := joinNonEmpty(" · ", parts...)
+
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		runes := []rune(description)
+		description = strings.TrimSpace(string(runes[:maxDescriptionLength-1])) + "…"
+	}
+	return description
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLPage(t *testing.T) {
+	resume := &domain.Resume{
+		ResumeMetadata: domain.ResumeMetadata{Language: "es"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Ana", LastName: "García", JobTitle: "Ingeniera <Go>"},
+		Experience: []*domain.Experience{{
+			Employer: "Acme", JobTitle: "Ingeniera", StartDate: "2021-03-01", EndDate: "Present",
+			Description: `<script>alert("x")</script>`,
+		}},
+		Projects: []*domain.Project{{Name: "Demo", RepoURL: "javascript:alert(1)"}},
+	}
+
+	got := string(HTMLPage(resume, "https://example.com/r/ana"))
+	assert.Contains(t, got, `<html lang="es">`)
+	assert.Contains(t, got, "<title>Ana García — Ingeniera &lt;Go&gt;</title>")
+	assert.Contains(t, got, `<meta name="description" content="Ingeniera &lt;Go&gt; · Ingeniera, Acme">`)
+	assert.Contains(t, got, `<link rel="canonical" href="https://example.com/r/ana">`)
+	assert.Contains(t, got, `"@type":"Person"`)
+	assert.Contains(t, got, "<h2>Experiencia</h2>")
+	assert.Contains(t, got, "marzo de 2021 – Actualidad")
+	assert.Contains(t, got, "@page { size: A4; }")
+
+	// Resume text can't inject markup or script links
+	assert.NotContains(t, got, "<script>alert")
+	assert.NotContains(t, got, `href="javascript:`)
+
+	// Pages that aren't published have no canonical URL
+	assert.NotContains(t, string(HTML(resume)), "canonical")
+}
+
+func TestHTMLPagePhoto(t *testing.T) {
+	resume := &domain.Resume{PersonalInfo: &domain.PersonalInfo{FirstName: "Ana", PhotoURL: "https://api.example.com/api/v1/photos/abc.jpg"}}
+	got := string(HTML(resume))
+	assert.Contains(t, got, `<img class="photo" src="https://api.example.com/api/v1/photos/abc.jpg" alt="">`)
+	assert.Contains(t, got, `<meta property="og:image" content="https://api.example.com/api/v1/photos/abc.jpg">`)
+	assert.Contains(t, got, `"image":"https://api.example.com/api/v1/photos/abc.jpg"`)
+
+	// Relative photo URLs are shown but not announced to link previews
+	resume.PersonalInfo.PhotoURL = "/api/v1/photos/abc.jpg"
+	got = string(HTML(resume))
+	assert.Contains(t, got, `src="/api/v1/photos/abc.jpg"`)
+	assert.NotContains(t, got, "og:image")
+}
@@ -0,0 +1,135 @@
+// Package render turns complete resumes into documents. Headings and dates are
+// written in the resume's language.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/locale"
+)
+
+// Markdown renders a complete resume as a Markdown document
+func Markdown(resume *domain.Resume) []byte {
+	settings := resume.Settings.Resolve(resume.Language)
+	c := catalog(resume, settings)
+	var b bytes.Buffer
+
+	if info := resume.PersonalInfo; info != nil {
+		fmt.Fprintf(&b, "# %s\n\n", joinNonEmpty(" ", info.FirstName, info.LastName))
+		if info.JobTitle != "" {
+			fmt.Fprintf(&b, "**%s**\n\n", info.JobTitle)
+		}
+		contact := []string{info.Email, displayPhone(info, settings)}
+		if !info.Address.IsZero() {
+			contact = append(contact, strings.Join(info.Address.Lines(), ", "))
+		}
+		if line := joinNonEmpty(" · ", contact...); line != "" {
+			fmt.Fprintf(&b, "%s\n\n", line)
+		}
+	} else if resume.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", resume.Title)
+	}
+
+	if len(resume.Experience) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", c.Headings.Experience)
+		for _, e := range resume.Experience {
+			fmt.Fprintf(&b, "### %s — %s\n\n", e.JobTitle, e.Employer)
+			fmt.Fprintf(&b, "*%s*\n\n", joinNonEmpty(" · ", c.DateRange(e.StartDate, e.EndDate), e.Location))
+			writeParagraph(&b, e.Description)
+			for _, achievement := range e.Achievements {
+				fmt.Fprintf(&b, "- %s\n", achievement)
+			}
+			if len(e.Achievements) > 0 {
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if len(resume.Education) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", c.Headings.Education)
+		for _, e := range resume.Education {
+			fmt.Fprintf(&b, "### %s — %s\n\n", joinNonEmpty(", ", e.Degree, e.Field), e.Institution)
+			fmt.Fprintf(&b, "*%s*\n\n", joinNonEmpty(" · ", c.DateRange(e.StartDate, e.EndDate), e.Location))
+			writeParagraph(&b, e.Description)
+		}
+	}
+
+	if len(resume.Skills) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", c.Headings.Skills)
+		for _, group := range domain.GroupSkills(resume.Skills) {
+			names := make([]string, len(group.Skills))
+			for i, s := range group.Skills {
+				names[i] = s.Name
+			}
+			if group.Category == "" {
+				fmt.Fprintf(&b, "- %s\n", strings.Join(names, ", "))
+			} else {
+				fmt.Fprintf(&b, "- **%s:** %s\n", group.Category, strings.Join(names, ", "))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(resume.Projects) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", c.Headings.Projects)
+		for _, p := range resume.Projects {
+			fmt.Fprintf(&b, "### %s\n\n", p.Name)
+			if dates := dateRangeIfAny(c, p.StartDate, p.EndDate); dates != "" {
+				fmt.Fprintf(&b, "*%s*\n\n", dates)
+			}
+			writeParagraph(&b, p.Description)
+			if len(p.Technologies) > 0 {
+				fmt.Fprintf(&b, "%s\n\n", strings.Join(p.Technologies, ", "))
+			}
+			if links := joinNonEmpty(" · ", p.RepoURL, p.DemoURL); links != "" {
+				fmt.Fprintf(&b, "%s\n\n", links)
+			}
+		}
+	}
+
+	if len(resume.Certifications) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", c.Headings.Certifications)
+		for _, cert := range resume.Certifications {
+			line := joinNonEmpty(" — ", cert.Name, cert.Issuer)
+			if cert.IssueDate != "" {
+				line += ", " + c.Date(cert.IssueDate)
+			}
+			if expiry := c.Date(cert.ExpiryDate); expiry != "" && expiry != cert.ExpiryDate {
+				line += " (" + c.Expires + " " + expiry + ")"
+			}
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n')
+}
+
+// dateRangeIfAny formats a date range only when at least one date is set
+func dateRangeIfAny(c *locale.Catalog, start, end string) string {
+	if strings.TrimSpace(start) == "" && strings.TrimSpace(end) == "" {
+		return ""
+	}
+	return c.DateRange(start, end)
+}
+
+// writeParagraph writes text followed by a blank line, skipping empty text
+func writeParagraph(b *bytes.Buffer, text string) {
+	if text = strings.TrimSpace(text); text != "" {
+		fmt.Fprintf(b, "%s\n\n", text)
+	}
+}
+
+// joinNonEmpty joins the non-empty parts with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
@@ -0,0 +1,53 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownLocalized(t *testing.T) {
+	resume := &domain.Resume{
+		ResumeMetadata: domain.ResumeMetadata{Language: "es"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Ana", LastName: "García", Email: "ana@example.com"},
+		Experience: []*domain.Experience{{
+			Employer: "Acme", JobTitle: "Ingeniera", StartDate: "2021-03-01", EndDate: "Present",
+			Achievements: []string{"Redujo costos un 20%"},
+		}},
+		Skills: []*domain.Skill{{Name: "Go", Category: "Lenguajes"}, {Name: "SQL", Category: "Lenguajes"}},
+	}
+
+	got := string(Markdown(resume))
+	assert.Contains(t, got, "# Ana García\n")
+	assert.Contains(t, got, "## Experiencia\n")
+	assert.Contains(t, got, "*marzo de 2021 – Actualidad*")
+	assert.Contains(t, got, "- Redujo costos un 20%\n")
+	assert.Contains(t, got, "## Habilidades\n\n- **Lenguajes:** Go, SQL\n")
+	assert.NotContains(t, got, "## Educación")
+
+	resume.Language = "en"
+	got = string(Markdown(resume))
+	assert.Contains(t, got, "## Experience\n")
+	assert.Contains(t, got, "*March 2021 – Present*")
+}
+
+func TestMarkdownSettings(t *testing.T) {
+	resume := &domain.Resume{
+		ResumeMetadata: domain.ResumeMetadata{
+			Language: "en-US",
+			Settings: domain.ResumeSettings{DateStyle: "numeric", PhoneDisplay: domain.PhoneInternational},
+		},
+		PersonalInfo: &domain.PersonalInfo{FirstName: "Ana", Phone: "+12015550123", PhoneCountry: "US", PhoneDisplay: "(201) 555-0123"},
+		Experience:   []*domain.Experience{{Employer: "Acme", JobTitle: "Engineer", StartDate: "2021-03-01", EndDate: "Present"}},
+	}
+
+	got := string(Markdown(resume))
+	assert.Contains(t, got, "*03/2021 – Present*")
+	assert.Contains(t, got, "+1 201-555-0123")
+
+	resume.Settings = domain.ResumeSettings{DateStyle: "iso"}
+	got = string(Markdown(resume))
+	assert.Contains(t, got, "*2021-03 – Present*")
+	assert.Contains(t, got, "(201) 555-0123")
+}
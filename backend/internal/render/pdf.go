@@ -0,0 +1,387 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/locale"
+)
+
+// pdfMargin is the page margin, in points, unless the resume is condensed
+const pdfMargin = 56
+
+// pdfPage is the size of a page, in points
+type pdfPage struct {
+	width, height float64
+}
+
+// pdfPageSizes are the page sizes by paper size
+var pdfPageSizes = map[string]pdfPage{
+	locale.PaperA4:     {595.28, 841.89},
+	locale.PaperLetter: {612, 792},
+}
+
+// pdfStyle is the font and size of a run of text
+type pdfStyle struct {
+	font *pdfFont
+	size float64
+}
+
+var (
+	pdfNameStyle    = pdfStyle{fontBold, 20}
+	pdfTitleStyle   = pdfStyle{fontRegular, 12}
+	pdfContactStyle = pdfStyle{fontRegular, 9.5}
+	pdfSectionStyle = pdfStyle{fontBold, 13}
+	pdfEntryStyle   = pdfStyle{fontBold, 11}
+	pdfMetaStyle    = pdfStyle{fontItalic, 9.5}
+	pdfBodyStyle    = pdfStyle{fontRegular, 10}
+	pdfLabelStyle   = pdfStyle{fontBold, 10}
+)
+
+// PDF renders a complete resume as a PDF document, with the same content as
+// the Markdown export. It needs no browser or external tools: text is set in
+// the standard Helvetica fonts every PDF viewer provides, so the layout is
+// plain and characters outside Western European scripts show as "?". Pages
+// are the size of the resume's paper size setting.
+func PDF(resume *domain.Resume) []byte {
+	settings := resume.Settings.Resolve(resume.Language)
+	l := newPDFLayout(pdfPageSizes[settings.PaperSize], pdfMargin, 1)
+	title := l.resume(resume, settings)
+	return writePDF(l.finish(), l.page.width, l.page.height, title)
+}
+
+// resume sets a resume with resolved settings, returning its title
+func (l *pdfLayout) resume(resume *domain.Resume, settings domain.ResumeSettings) string {
+	c := catalog(resume, settings)
+
+	title := resume.Title
+	if info := resume.PersonalInfo; info != nil {
+		name := joinNonEmpty(" ", info.FirstName, info.LastName)
+		if name != "" {
+			title = name
+		}
+		l.paragraph(name, pdfNameStyle, 0)
+		if info.JobTitle != "" {
+			l.paragraph(info.JobTitle, pdfTitleStyle, 0)
+		}
+		contact := []string{info.Email, displayPhone(info, settings)}
+		if !info.Address.IsZero() {
+			contact = append(contact, strings.Join(info.Address.Lines(), ", "))
+		}
+		l.space(2)
+		l.paragraph(joinNonEmpty(" · ", contact...), pdfContactStyle, 0)
+	} else if resume.Title != "" {
+		l.paragraph(resume.Title, pdfNameStyle, 0)
+	}
+
+	layout := settings.Layout
+	if len(resume.Experience) > 0 {
+		l.block(layout, domain.LayoutSectionExperience, -1, func(l *pdfLayout) {
+			l.section(c.Headings.Experience)
+			for i, e := range resume.Experience {
+				l.block(layout, domain.LayoutSectionExperience, i, func(l *pdfLayout) {
+					l.entry(e.JobTitle+" — "+e.Employer, joinNonEmpty(" · ", c.DateRange(e.StartDate, e.EndDate), e.Location))
+					l.paragraph(e.Description, pdfBodyStyle, 0)
+					for _, achievement := range e.Achievements {
+						l.bullet(achievement)
+					}
+				})
+			}
+		})
+	}
+
+	if len(resume.Education) > 0 {
+		l.block(layout, domain.LayoutSectionEducation, -1, func(l *pdfLayout) {
+			l.section(c.Headings.Education)
+			for i, e := range resume.Education {
+				l.block(layout, domain.LayoutSectionEducation, i, func(l *pdfLayout) {
+					l.entry(joinNonEmpty(", ", e.Degree, e.Field)+" — "+e.Institution, joinNonEmpty(" · ", c.DateRange(e.StartDate, e.EndDate), e.Location))
+					l.paragraph(e.Description, pdfBodyStyle, 0)
+				})
+			}
+		})
+	}
+
+	if len(resume.Skills) > 0 {
+		l.block(layout, domain.LayoutSectionSkills, -1, func(l *pdfLayout) {
+			l.section(c.Headings.Skills)
+			for _, group := range domain.GroupSkills(resume.Skills) {
+				names := make([]string, len(group.Skills))
+				for i, s := range group.Skills {
+					names[i] = s.Name
+				}
+				if group.Category == "" {
+					l.bullet(strings.Join(names, ", "))
+				} else {
+					l.labeledBullet(group.Category+":", strings.Join(names, ", "))
+				}
+			}
+		})
+	}
+
+	if len(resume.Projects) > 0 {
+		l.block(layout, domain.LayoutSectionProjects, -1, func(l *pdfLayout) {
+			l.section(c.Headings.Projects)
+			for i, p := range resume.Projects {
+				l.block(layout, domain.LayoutSectionProjects, i, func(l *pdfLayout) {
+					l.entry(p.Name, dateRangeIfAny(c, p.StartDate, p.EndDate))
+					l.paragraph(p.Description, pdfBodyStyle, 0)
+					l.paragraph(strings.Join(p.Technologies, ", "), pdfBodyStyle, 0)
+					l.paragraph(joinNonEmpty(" · ", p.RepoURL, p.DemoURL), pdfBodyStyle, 0)
+				})
+			}
+		})
+	}
+
+	if len(resume.Certifications) > 0 {
+		l.block(layout, domain.LayoutSectionCertifications, -1, func(l *pdfLayout) {
+			l.section(c.Headings.Certifications)
+			for i, cert := range resume.Certifications {
+				line := joinNonEmpty(" — ", cert.Name, cert.Issuer)
+				if cert.IssueDate != "" {
+					line += ", " + c.Date(cert.IssueDate)
+				}
+				if expiry := c.Date(cert.ExpiryDate); expiry != "" && expiry != cert.ExpiryDate {
+					line += " (" + c.Expires + " " + expiry + ")"
+				}
+				l.block(layout, domain.LayoutSectionCertifications, i, func(l *pdfLayout) {
+					l.bullet(line)
+				})
+			}
+		})
+	}
+
+	return title
+}
+
+// pdfLayout sets text top to bottom, starting a new page when one is full
+type pdfLayout struct {
+	page pdfPage
+	// margin is the page margin and scale the factor of every font size
+	margin, scale float64
+	pages         [][]byte
+	content       bytes.Buffer
+	// y is the baseline position of the next line, from the page bottom
+	y float64
+}
+
+func newPDFLayout(page pdfPage, margin, scale float64) *pdfLayout {
+	return &pdfLayout{page: page, margin: margin, scale: scale, y: page.height - margin}
+}
+
+// lineHeight returns the distance between baselines for a style
+func (s pdfStyle) lineHeight() float64 {
+	return s.size * 1.3
+}
+
+// style returns a style scaled by the layout's font scale
+func (l *pdfLayout) style(s pdfStyle) pdfStyle {
+	return pdfStyle{s.font, s.size * l.scale}
+}
+
+// textWidth is the width lines are wrapped to
+func (l *pdfLayout) textWidth() float64 {
+	return l.page.width - 2*l.margin
+}
+
+// ensure starts a new page unless height fits above the bottom margin
+func (l *pdfLayout) ensure(height float64) {
+	if l.y-height >= l.margin {
+		return
+	}
+	l.newPage()
+}
+
+// newPage starts a new page, unless the current one is still empty
+func (l *pdfLayout) newPage() {
+	if l.content.Len() == 0 {
+		return
+	}
+	l.pages = append(l.pages, bytes.Clone(l.content.Bytes()))
+	l.content.Reset()
+	l.y = l.page.height - l.margin
+}
+
+// block sets a section, or one of its entries when entry isn't negative,
+// following the layout's page-break hints for it
+func (l *pdfLayout) block(layout domain.ResumeLayout, section string, entry int, set func(l *pdfLayout)) {
+	before, keepTogether := layout.PageBreak(section, entry)
+	if before {
+		l.newPage()
+	}
+	if keepTogether {
+		// Something taller than a page is split wherever it has to be
+		if height := l.measure(set); height <= l.page.height-2*l.margin {
+			l.ensure(height)
+		}
+	}
+	set(l)
+}
+
+// measure returns the height set takes up, without setting anything.
+// Anything that starts a page of its own is taller than any page.
+func (l *pdfLayout) measure(set func(l *pdfLayout)) float64 {
+	scratch := newPDFLayout(pdfPage{width: l.page.width, height: pdfMeasureHeight}, l.margin, l.scale)
+	set(scratch)
+	if len(scratch.pages) > 0 {
+		return math.Inf(1)
+	}
+	return pdfMeasureHeight - l.margin - scratch.y
+}
+
+// pdfMeasureHeight is the page height blocks are measured on, tall enough
+// for any resume to fit
+const pdfMeasureHeight = 1e9
+
+// space moves down by the given height
+func (l *pdfLayout) space(height float64) {
+	l.y -= height
+}
+
+// text writes encoded text with its left edge at x on the current line
+func (l *pdfLayout) text(x float64, style pdfStyle, text []byte) {
+	fmt.Fprintf(&l.content, "BT /%s %s Tf %s %s Td ", style.font.resource, pdfNumber(style.size), pdfNumber(x), pdfNumber(l.y))
+	pdfString(&l.content, text)
+	l.content.WriteString(" Tj ET\n")
+}
+
+// paragraph wraps text to the page width less indent, skipping empty text
+func (l *pdfLayout) paragraph(text string, style pdfStyle, indent float64) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	style = l.style(style)
+	for _, line := range wrapPDFText(winAnsi(text), style, l.textWidth()-indent) {
+		l.ensure(style.lineHeight())
+		l.y -= style.lineHeight()
+		l.text(l.margin+indent, style, line)
+	}
+	l.space(style.size * 0.4)
+}
+
+// section writes a heading with a rule under it
+func (l *pdfLayout) section(heading string) {
+	style := l.style(pdfSectionStyle)
+	// Keep the heading with the first lines under it
+	l.ensure(style.lineHeight() + 4*l.style(pdfBodyStyle).lineHeight())
+	l.space(style.size * 0.8)
+	l.y -= style.lineHeight()
+	l.text(l.margin, style, winAnsi(heading))
+	l.y -= 4
+	fmt.Fprintf(&l.content, "0.6 G 0.75 w %s %s m %s %s l S 0 G\n",
+		pdfNumber(l.margin), pdfNumber(l.y), pdfNumber(l.page.width-l.margin), pdfNumber(l.y))
+	l.space(4)
+}
+
+// entry writes the title of an experience, degree or project and the line
+// of dates and places under it
+func (l *pdfLayout) entry(title, meta string) {
+	l.ensure(l.style(pdfEntryStyle).lineHeight() + l.style(pdfMetaStyle).lineHeight() + 2*l.style(pdfBodyStyle).lineHeight())
+	l.space(2)
+	l.paragraph(title, pdfEntryStyle, 0)
+	l.paragraph(meta, pdfMetaStyle, 0)
+}
+
+// pdfBulletIndent is where the text of a bullet starts
+const pdfBulletIndent = 14
+
+// bullet writes a list item, wrapping under its first line's text
+func (l *pdfLayout) bullet(text string) {
+	l.labeledBullet("", text)
+}
+
+// labeledBullet writes a list item that starts with a bold label
+func (l *pdfLayout) labeledBullet(label, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" && label == "" {
+		return
+	}
+	style, labelStyle := l.style(pdfBodyStyle), l.style(pdfLabelStyle)
+	width := l.textWidth() - pdfBulletIndent
+
+	var labelWidth float64
+	encodedLabel := winAnsi(label)
+	if label != "" {
+		labelWidth = labelStyle.font.width(encodedLabel, labelStyle.size) + style.font.width([]byte(" "), style.size)
+	}
+
+	// The first line is shorter by the label; later lines use the full width
+	lines := wrapPDFTextFirst(winAnsi(text), style, width-labelWidth, width)
+	if len(lines) == 0 {
+		lines = [][]byte{nil}
+	}
+	for i, line := range lines {
+		l.ensure(style.lineHeight())
+		l.y -= style.lineHeight()
+		x := l.margin + pdfBulletIndent
+		if i == 0 {
+			l.text(l.margin+4, style, winAnsi("•"))
+			if label != "" {
+				l.text(x, labelStyle, encodedLabel)
+				x += labelWidth
+			}
+		}
+		l.text(x, style, line)
+	}
+	l.space(style.size * 0.25)
+}
+
+// finish returns the content streams of the pages
+func (l *pdfLayout) finish() [][]byte {
+	return append(l.pages, l.content.Bytes())
+}
+
+// wrapPDFText breaks text into lines no wider than width
+func wrapPDFText(text []byte, style pdfStyle, width float64) [][]byte {
+	return wrapPDFTextFirst(text, style, width, width)
+}
+
+// wrapPDFTextFirst breaks text into lines, the first no wider than first and
+// the rest no wider than rest. Words too long for a line are split.
+func wrapPDFTextFirst(text []byte, style pdfStyle, first, rest float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+	width := first
+	space := style.font.width([]byte(" "), style.size)
+
+	flush := func() {
+		lines = append(lines, line)
+		line = nil
+		width = rest
+	}
+
+	for _, word := range bytes.Fields(text) {
+		wordWidth := style.font.width(word, style.size)
+		if line != nil && style.font.width(line, style.size)+space+wordWidth > width {
+			flush()
+		}
+		for line == nil && wordWidth > width {
+			// Split a word that doesn't fit on a line of its own
+			n := 1
+			for n < len(word) && style.font.width(word[:n+1], style.size) <= width {
+				n++
+			}
+			line = bytes.Clone(word[:n])
+			flush()
+			word = word[n:]
+			wordWidth = style.font.width(word, style.size)
+		}
+		if len(word) == 0 {
+			continue
+		}
+		// Lines are built in their own buffers, as the words share text's
+		if line == nil {
+			line = bytes.Clone(word)
+		} else {
+			line = append(append(line, ' '), word...)
+		}
+	}
+	if line != nil {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,158 @@
+package render
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pdfPages returns the decompressed content stream of each page
+func pdfPages(t *testing.T, document []byte) []string {
+	t.Helper()
+	var pages []string
+	for _, match := range regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(document, -1) {
+		length, err := strconv.Atoi(string(document[match[2]:match[3]]))
+		require.NoError(t, err)
+		zr, err := zlib.NewReader(bytes.NewReader(document[match[1] : match[1]+length]))
+		require.NoError(t, err)
+		content, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		pages = append(pages, string(content))
+	}
+	return pages
+}
+
+func TestPDF(t *testing.T) {
+	resume := &domain.Resume{
+		ResumeMetadata: domain.ResumeMetadata{Language: "es"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Ana", LastName: "García", Email: "ana@example.com"},
+		Experience: []*domain.Experience{{
+			Employer: "Acme (Madrid)", JobTitle: "Ingeniera", StartDate: "2021-03-01", EndDate: "Present",
+			Achievements: []string{"Redujo costos un 20%"},
+		}},
+		Skills: []*domain.Skill{{Name: "Go", Category: "Lenguajes"}, {Name: "日本語"}},
+	}
+
+	document := PDF(resume)
+	require.True(t, bytes.HasPrefix(document, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(document, []byte("%%EOF\n")))
+	assert.Contains(t, string(document), "/Count 1 ")
+
+	// Every object starts where the cross-reference table says
+	xref := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(document, -1)
+	require.NotEmpty(t, xref)
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(document[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+
+	pages := pdfPages(t, document)
+	require.Len(t, pages, 1)
+	content := pages[0]
+	// Text is WinAnsi-encoded, with parentheses escaped
+	assert.Contains(t, content, "(Ana Garc\xeda)")
+	assert.Contains(t, content, "(Experiencia)")
+	assert.Contains(t, content, "(Ingeniera \x97 Acme \\(Madrid\\))")
+	assert.Contains(t, content, "(marzo de 2021 \x96 Actualidad)")
+	assert.Contains(t, content, "(Lenguajes:)")
+	// Characters the standard fonts lack are replaced
+	assert.Contains(t, content, "(???)")
+
+	// The output is the same every time
+	assert.Equal(t, document, PDF(resume))
+}
+
+func TestPDFStartsNewPages(t *testing.T) {
+	resume := &domain.Resume{PersonalInfo: &domain.PersonalInfo{FirstName: "Ana"}}
+	for i := 0; i < 40; i++ {
+		resume.Experience = append(resume.Experience, &domain.Experience{
+			Employer: "Acme", JobTitle: "Engineer", StartDate: "2020-01-01",
+			Description: strings.Repeat("Built and ran the services behind the product. ", 4),
+		})
+	}
+
+	document := PDF(resume)
+	pages := pdfPages(t, document)
+	require.Greater(t, len(pages), 1)
+	assert.Contains(t, string(document), "/Count "+strconv.Itoa(len(pages))+" ")
+}
+
+func TestPDFPageBreakHints(t *testing.T) {
+	resume := &domain.Resume{PersonalInfo: &domain.PersonalInfo{FirstName: "Ana"}}
+	for i := 0; i < 12; i++ {
+		resume.Experience = append(resume.Experience, &domain.Experience{
+			Employer: "Acme " + strconv.Itoa(i), JobTitle: "Engineer", StartDate: "2020-01-01",
+			Description: strings.Repeat("Built and ran the services behind the product. ", 12) + "end" + strconv.Itoa(i) + ".",
+		})
+	}
+	resume.Education = []*domain.Education{{Institution: "Universidad", Degree: "BSc"}}
+
+	// split reports whether an entry starts on one page and ends on another
+	split := func(pages []string, i int) bool {
+		for _, page := range pages {
+			if strings.Contains(page, "Acme "+strconv.Itoa(i)+")") {
+				return !strings.Contains(page, "end"+strconv.Itoa(i)+".")
+			}
+		}
+		t.Fatalf("entry %d not found", i)
+		return false
+	}
+	splitEntries := func(pages []string) int {
+		n := 0
+		for i := range resume.Experience {
+			if split(pages, i) {
+				n++
+			}
+		}
+		return n
+	}
+	require.Positive(t, splitEntries(pdfPages(t, PDF(resume))))
+
+	for i := range resume.Experience {
+		resume.Settings.Layout.PageBreaks = append(resume.Settings.Layout.PageBreaks, domain.PageBreakHint{
+			Section: domain.LayoutSectionExperience, Entry: &i, Break: domain.PageBreakKeepTogether,
+		})
+	}
+	resume.Settings.Layout.PageBreaks = append(resume.Settings.Layout.PageBreaks, domain.PageBreakHint{
+		Section: domain.LayoutSectionEducation, Break: domain.PageBreakBefore,
+	})
+	pages := pdfPages(t, PDF(resume))
+	assert.Zero(t, splitEntries(pages))
+	last := pages[len(pages)-1]
+	assert.Contains(t, last, "(Education)")
+	assert.NotContains(t, last, "Acme")
+}
+
+func TestWrapPDFText(t *testing.T) {
+	text := []byte("one two three " + strings.Repeat("x", 80))
+	lines := wrapPDFText(text, pdfBodyStyle, 100)
+
+	require.Greater(t, len(lines), 2)
+	assert.Equal(t, "one two three", string(lines[0]))
+	for _, line := range lines {
+		assert.LessOrEqual(t, pdfBodyStyle.font.width(line, pdfBodyStyle.size), 100.0)
+	}
+	// The long word is split across lines without losing characters
+	assert.Equal(t, strings.Repeat("x", 80), string(bytes.Join(lines[1:], nil)))
+	// Wrapping doesn't write into the text
+	assert.Equal(t, "one two three "+strings.Repeat("x", 80), string(text))
+}
+
+func TestPDFPaperSize(t *testing.T) {
+	resume := &domain.Resume{ResumeMetadata: domain.ResumeMetadata{Title: "Resume", Language: "en-GB"}}
+	assert.Contains(t, string(PDF(resume)), "/MediaBox [0 0 595.28 841.89]")
+
+	// Letter follows the language's region unless the settings choose
+	resume.Language = "en-US"
+	assert.Contains(t, string(PDF(resume)), "/MediaBox [0 0 612 792]")
+	resume.Settings.PaperSize = "a4"
+	assert.Contains(t, string(PDF(resume)), "/MediaBox [0 0 595.28 841.89]")
+}
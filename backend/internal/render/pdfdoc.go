@@ -0,0 +1,175 @@
+package render
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// pdfFont is one of the standard Type 1 fonts every PDF viewer provides, so
+// no font files are embedded
+type pdfFont struct {
+	// resource names the font in content streams
+	resource string
+	baseFont string
+	// widths are the advance widths of the ASCII characters from space, in
+	// thousandths of the font size, from the font's published metrics
+	widths [95]int
+}
+
+var (
+	fontRegular = &pdfFont{resource: "F1", baseFont: "Helvetica", widths: helveticaWidths}
+	fontBold    = &pdfFont{resource: "F2", baseFont: "Helvetica-Bold", widths: helveticaBoldWidths}
+	// Oblique shares the regular widths
+	fontItalic = &pdfFont{resource: "F3", baseFont: "Helvetica-Oblique", widths: helveticaWidths}
+)
+
+var pdfFonts = []*pdfFont{fontRegular, fontBold, fontItalic}
+
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611, // 0 to ?
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556, // P to _
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, // ` to o
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, // p to ~
+}
+
+// Widths of the Windows-1252 punctuation resumes use; other characters past
+// ASCII, mostly accented letters, are measured as a typical letter
+var pdfWideCharWidths = map[byte]int{
+	0x95: 350,  // bullet
+	0x96: 556,  // en dash
+	0x97: 1000, // em dash
+	0xB7: 278,  // middle dot
+}
+
+// width returns the width of WinAnsi-encoded text at the given size, in points
+func (f *pdfFont) width(text []byte, size float64) float64 {
+	total := 0
+	for _, c := range text {
+		switch {
+		case c >= 32 && c < 127:
+			total += f.widths[c-32]
+		case pdfWideCharWidths[c] != 0:
+			total += pdfWideCharWidths[c]
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// winAnsi encodes text for the standard fonts, replacing characters they
+// can't show with "?"
+func winAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		encoded = append(encoded, b)
+	}
+	return encoded
+}
+
+// pdfString writes WinAnsi-encoded text as a PDF literal string
+func pdfString(b *bytes.Buffer, text []byte) {
+	b.WriteByte('(')
+	for _, c := range text {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+}
+
+// pdfTextString encodes metadata text as UTF-16, which PDF viewers show
+// whatever the script
+func pdfTextString(text string) string {
+	var b bytes.Buffer
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// pdfNumber formats a coordinate with at most two decimals
+func pdfNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// writePDF assembles a PDF file from the content streams of its pages. The
+// output is deterministic: it carries no creation date or random ID.
+func writePDF(pages [][]byte, width, height float64, title string) []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects: the catalog, the page tree, the fonts, then each page and
+	// its content, then the document information
+	firstPage := 3 + len(pdfFonts)
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	var kids bytes.Buffer
+	for i := range pages {
+		fmt.Fprintf(&kids, "%d 0 R ", firstPage+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids.Bytes()), len(pages)))
+
+	var fonts bytes.Buffer
+	for i, font := range pdfFonts {
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /" + font.baseFont + " /Encoding /WinAnsiEncoding >>")
+		fmt.Fprintf(&fonts, "/%s %d 0 R ", font.resource, 3+i)
+	}
+
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			pdfNumber(width), pdfNumber(height), fonts.String(), firstPage+2*i+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(content)
+		zw.Close()
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	object("<< /Title " + pdfTextString(title) + " /Producer (resume_generator) >>")
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, len(offsets), xref)
+
+	return out.Bytes()
+}
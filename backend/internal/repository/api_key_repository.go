@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresAPIKeyRepository implements the APIKeyRepository interface using PostgreSQL
+type PostgresAPIKeyRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresAPIKeyRepository creates a new PostgreSQL API key repository
+func NewPostgresAPIKeyRepository(db *sqlx.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreateAPIKey creates a new API key
+func (r *PostgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	// Set default values if not provided
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC()
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+
+	err := r.queries.CreateAPIKey(ctx, dbgen.CreateAPIKeyParams{
+		ID:        key.ID,
+		UserID:    key.UserID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		Scopes:    key.Scopes,
+		ExpiresAt: nullTime(key.ExpiresAt),
+		CreatedAt: key.CreatedAt,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Ctx(ctx).Error().Err(err).Str("user_id", key.UserID.String()).Msg("Failed to create API key")
+		return err
+	}
+
+	return nil
+}
+
+// GetAPIKeyByHash retrieves an API key by the hash of the key
+func (r *PostgresAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	row, err := r.queries.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get API key by hash")
+		return nil, err
+	}
+
+	return apiKeyFromRow(row), nil
+}
+
+// GetAPIKeysByUserID retrieves all API keys of a user, newest first
+func (r *PostgresAPIKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	rows, err := r.queries.GetAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get API keys")
+		return nil, err
+	}
+
+	keys := make([]*domain.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = apiKeyFromRow(row)
+	}
+
+	return keys, nil
+}
+
+// CountAPIKeysByUserID counts the API keys of a user
+func (r *PostgresAPIKeyRepository) CountAPIKeysByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := r.queries.CountAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to count API keys")
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// TouchAPIKey records when a key was last used
+func (r *PostgresAPIKeyRepository) TouchAPIKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	err := r.queries.TouchAPIKey(ctx, dbgen.TouchAPIKeyParams{
+		LastUsedAt: nullTime(usedAt),
+		ID:         id,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("api_key_id", id.String()).Msg("Failed to update API key")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAPIKey deletes one of a user's API keys
+func (r *PostgresAPIKeyRepository) DeleteAPIKey(ctx context.Context, userID, id uuid.UUID) error {
+	rowsAffected, err := r.queries.DeleteAPIKey(ctx, dbgen.DeleteAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("api_key_id", id.String()).Msg("Failed to delete API key")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// apiKeyFromRow converts a generated API key row to the domain model
+func apiKeyFromRow(row dbgen.ApiKey) *domain.APIKey {
+	return &domain.APIKey{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		KeyHash:    row.KeyHash,
+		Scopes:     row.Scopes,
+		ExpiresAt:  row.ExpiresAt.Time,
+		LastUsedAt: row.LastUsedAt.Time,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+// GetAPIKeyIDsUsedSince returns the keys last used at or after since
+func (r *PostgresAPIKeyRepository) GetAPIKeyIDsUsedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	ids, err := r.queries.GetAPIKeyIDsUsedSince(ctx, nullTime(since))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get used API keys")
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// SaveAPIKeyUsage stores the request count of an hour, replacing any stored one
+func (r *PostgresAPIKeyRepository) SaveAPIKeyUsage(ctx context.Context, usage *domain.APIKeyUsage) error {
+	err := r.queries.UpsertAPIKeyUsage(ctx, dbgen.UpsertAPIKeyUsageParams{
+		ApiKeyID: usage.APIKeyID,
+		Hour:     usage.Hour.UTC(),
+		Requests: usage.Requests,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("api_key_id", usage.APIKeyID.String()).Msg("Failed to save API key usage")
+		return err
+	}
+
+	return nil
+}
+
+// GetAPIKeyUsage returns the hourly request counts of a key from since on
+func (r *PostgresAPIKeyRepository) GetAPIKeyUsage(ctx context.Context, keyID uuid.UUID, since time.Time) ([]*domain.APIKeyUsage, error) {
+	rows, err := r.queries.GetAPIKeyUsageSince(ctx, dbgen.GetAPIKeyUsageSinceParams{
+		ApiKeyID: keyID,
+		Hour:     since.UTC(),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("api_key_id", keyID.String()).Msg("Failed to get API key usage")
+		return nil, err
+	}
+
+	usage := make([]*domain.APIKeyUsage, len(rows))
+	for i, row := range rows {
+		usage[i] = &domain.APIKeyUsage{
+			APIKeyID: row.ApiKeyID,
+			Hour:     row.Hour.UTC(),
+			Requests: row.Requests,
+		}
+	}
+	return usage, nil
+}
+
+// DeleteAPIKeyUsageBefore deletes the request counts of hours before a time
+func (r *PostgresAPIKeyRepository) DeleteAPIKeyUsageBefore(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := r.queries.DeleteAPIKeyUsageBefore(ctx, before.UTC())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete old API key usage")
+		return 0, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CachedResumeRepositoryConfig contains configuration for the resume cache
+type CachedResumeRepositoryConfig struct {
+	// TTL bounds how long a complete resume is served from the cache
+	TTL time.Duration
+}
+
+// CachedResumeRepository is a cache-aside decorator that keeps serialized
+// complete resumes in a cache and drops them whenever any section is written
+type CachedResumeRepository struct {
+	domain.ResumeRepository
+	cache  cache.Cache
+	config CachedResumeRepositoryConfig
+}
+
+// NewCachedResumeRepository wraps a resume repository with a complete resume cache
+func NewCachedResumeRepository(repo domain.ResumeRepository, store cache.Cache, config CachedResumeRepositoryConfig) *CachedResumeRepository {
+	// Set default values if not provided
+	if config.TTL == 0 {
+		config.TTL = 10 * time.Minute
+	}
+
+	return &CachedResumeRepository{
+		ResumeRepository: repo,
+		cache:            store,
+		config:           config,
+	}
+}
+
+// completeResumeKey returns the cache key for a complete resume
+func completeResumeKey(resumeID uuid.UUID) string {
+	return "resume:complete:" + resumeID.String()
+}
+
+// GetCompleteResume serves the complete resume from the cache, loading and
+// storing it on a miss. Cache failures fall back to the underlying repository.
+// Inside a transaction the cache is bypassed, as it could hold changes that
+// are later rolled back.
+func (r *CachedResumeRepository) GetCompleteResume(ctx context.Context, resumeID uuid.UUID) (*domain.Resume, error) {
+	if InTx(ctx) {
+		return r.ResumeRepository.GetCompleteResume(ctx, resumeID)
+	}
+
+	ctx, span := tracer.Start(ctx, "CachedResumeRepository.GetCompleteResume",
+		trace.WithAttributes(attribute.String("resume.id", resumeID.String())))
+	defer span.End()
+
+	key := completeResumeKey(resumeID)
+
+	data, err := r.cache.Get(ctx, key)
+	if err == nil {
+		var resume domain.Resume
+		if err := json.Unmarshal(data, &resume); err == nil {
+			span.SetAttributes(attribute.Bool("cache.hit", true))
+			return &resume, nil
+		}
+		log.Ctx(ctx).Warn().Str("resume_id", resumeID.String()).Msg("Discarding undecodable cached resume")
+	} else if !errors.Is(err, cache.ErrMiss) {
+		log.Ctx(ctx).Warn().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to read cached resume")
+	}
+
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	resume, err := r.ResumeRepository.GetCompleteResume(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(resume)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to encode resume for cache")
+		return resume, nil
+	}
+	if err := r.cache.Set(ctx, key, data, r.config.TTL); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to cache resume")
+	}
+
+	return resume, nil
+}
+
+// invalidate drops the cached complete resume after a write. A failure only
+// leaves a stale entry until the TTL expires, so it is logged rather than
+// returned. Inside a transaction it is dropped again once the transaction
+// commits, in case a reader cached the resume as it was before.
+func (r *CachedResumeRepository) invalidate(ctx context.Context, resumeID uuid.UUID) {
+	del := func() {
+		if err := r.cache.Del(context.WithoutCancel(ctx), completeResumeKey(resumeID)); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to invalidate cached resume")
+		}
+	}
+	if InTx(ctx) {
+		del()
+	}
+	AfterCommit(ctx, del)
+}
+
+// UpdateResumeMetadata updates a resume's metadata and invalidates the cached resume
+func (r *CachedResumeRepository) UpdateResumeMetadata(ctx context.Context, id uuid.UUID, metadata *domain.ResumeMetadata) error {
+	defer r.invalidate(ctx, id)
+	return r.ResumeRepository.UpdateResumeMetadata(ctx, id, metadata)
+}
+
+// DeleteResume deletes a resume and its cached copy
+func (r *CachedResumeRepository) DeleteResume(ctx context.Context, id uuid.UUID) error {
+	defer r.invalidate(ctx, id)
+	return r.ResumeRepository.DeleteResume(ctx, id)
+}
+
+// SavePersonalInfo saves personal info and invalidates the cached resume
+func (r *CachedResumeRepository) SavePersonalInfo(ctx context.Context, resumeID uuid.UUID, info *domain.PersonalInfo) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.SavePersonalInfo(ctx, resumeID, info)
+}
+
+// SetPhotoURL sets the personal info's photo and invalidates the cached resume
+func (r *CachedResumeRepository) SetPhotoURL(ctx context.Context, resumeID uuid.UUID, photoURL string) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.SetPhotoURL(ctx, resumeID, photoURL)
+}
+
+// AddEducation adds an education entry and invalidates the cached resume
+func (r *CachedResumeRepository) AddEducation(ctx context.Context, resumeID uuid.UUID, education *domain.Education) (uuid.UUID, error) {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.AddEducation(ctx, resumeID, education)
+}
+
+// UpdateEducation updates an education entry and invalidates the cached resume
+func (r *CachedResumeRepository) UpdateEducation(ctx context.Context, resumeID, id uuid.UUID, education *domain.Education) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.UpdateEducation(ctx, resumeID, id, education)
+}
+
+// DeleteEducation deletes an education entry and invalidates the cached resume
+func (r *CachedResumeRepository) DeleteEducation(ctx context.Context, resumeID, id uuid.UUID) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.DeleteEducation(ctx, resumeID, id)
+}
+
+// AddExperience adds an experience entry and invalidates the cached resume
+func (r *CachedResumeRepository) AddExperience(ctx context.Context, resumeID uuid.UUID, experience *domain.Experience) (uuid.UUID, error) {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.AddExperience(ctx, resumeID, experience)
+}
+
+// UpdateExperience updates an experience entry and invalidates the cached resume
+func (r *CachedResumeRepository) UpdateExperience(ctx context.Context, resumeID, id uuid.UUID, experience *domain.Experience) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.UpdateExperience(ctx, resumeID, id, experience)
+}
+
+// DeleteExperience deletes an experience entry and invalidates the cached resume
+func (r *CachedResumeRepository) DeleteExperience(ctx context.Context, resumeID, id uuid.UUID) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.DeleteExperience(ctx, resumeID, id)
+}
+
+// AddSkill adds a skill entry and invalidates the cached resume
+func (r *CachedResumeRepository) AddSkill(ctx context.Context, resumeID uuid.UUID, skill *domain.Skill) (uuid.UUID, error) {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.AddSkill(ctx, resumeID, skill)
+}
+
+// AddSkills adds several skill entries and invalidates the cached resume
+func (r *CachedResumeRepository) AddSkills(ctx context.Context, resumeID uuid.UUID, skills []*domain.Skill) ([]uuid.UUID, error) {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.AddSkills(ctx, resumeID, skills)
+}
+
+// UpdateSkill updates a skill entry and invalidates the cached resume
+func (r *CachedResumeRepository) UpdateSkill(ctx context.Context, resumeID, id uuid.UUID, skill *domain.Skill) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.UpdateSkill(ctx, resumeID, id, skill)
+}
+
+// DeleteSkill deletes a skill entry and invalidates the cached resume
+func (r *CachedResumeRepository) DeleteSkill(ctx context.Context, resumeID, id uuid.UUID) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.DeleteSkill(ctx, resumeID, id)
+}
+
+// AddProject adds a project entry and invalidates the cached resume
+func (r *CachedResumeRepository) AddProject(ctx context.Context, resumeID uuid.UUID, project *domain.Project) (uuid.UUID, error) {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.AddProject(ctx, resumeID, project)
+}
+
+// UpdateProject updates a project entry and invalidates the cached resume
+func (r *CachedResumeRepository) UpdateProject(ctx context.Context, resumeID, id uuid.UUID, project *domain.Project) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.UpdateProject(ctx, resumeID, id, project)
+}
+
+// DeleteProject deletes a project entry and invalidates the cached resume
+func (r *CachedResumeRepository) DeleteProject(ctx context.Context, resumeID, id uuid.UUID) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.DeleteProject(ctx, resumeID, id)
+}
+
+// AddProjectTechnology adds a technology to a project and invalidates the cached resume
+func (r *CachedResumeRepository) AddProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.AddProjectTechnology(ctx, resumeID, projectID, technology)
+}
+
+// DeleteProjectTechnology deletes a technology from a project and invalidates the cached resume
+func (r *CachedResumeRepository) DeleteProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.DeleteProjectTechnology(ctx, resumeID, projectID, technology)
+}
+
+// AddCertification adds a certification entry and invalidates the cached resume
+func (r *CachedResumeRepository) AddCertification(ctx context.Context, resumeID uuid.UUID, certification *domain.Certification) (uuid.UUID, error) {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.AddCertification(ctx, resumeID, certification)
+}
+
+// UpdateCertification updates a certification entry and invalidates the cached resume
+func (r *CachedResumeRepository) UpdateCertification(ctx context.Context, resumeID, id uuid.UUID, certification *domain.Certification) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.UpdateCertification(ctx, resumeID, id, certification)
+}
+
+// DeleteCertification deletes a certification entry and invalidates the cached resume
+func (r *CachedResumeRepository) DeleteCertification(ctx context.Context, resumeID, id uuid.UUID) error {
+	defer r.invalidate(ctx, resumeID)
+	return r.ResumeRepository.DeleteCertification(ctx, resumeID, id)
+}
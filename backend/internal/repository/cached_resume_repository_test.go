@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingResumeRepository serves a fixed resume and counts how often it is loaded
+type countingResumeRepository struct {
+	domain.ResumeRepository
+	resume *domain.Resume
+	loads  int
+}
+
+func (r *countingResumeRepository) GetCompleteResume(ctx context.Context, resumeID uuid.UUID) (*domain.Resume, error) {
+	r.loads++
+	if resumeID != r.resume.ID {
+		return nil, ErrNotFound
+	}
+	return r.resume, nil
+}
+
+func (r *countingResumeRepository) DeleteSkill(ctx context.Context, resumeID, id uuid.UUID) error {
+	r.resume.Skills = nil
+	return nil
+}
+
+func TestCachedResumeRepository(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	t.Cleanup(store.Close)
+
+	inner := &countingResumeRepository{
+		resume: &domain.Resume{
+			ID:     uuid.New(),
+			Skills: []*domain.Skill{{Name: "Go"}},
+		},
+	}
+	repo := NewCachedResumeRepository(inner, store, CachedResumeRepositoryConfig{})
+
+	first, err := repo.GetCompleteResume(ctx, inner.resume.ID)
+	require.NoError(t, err)
+	second, err := repo.GetCompleteResume(ctx, inner.resume.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.loads, "second read should be served from the cache")
+	assert.Equal(t, first.Skills[0].Name, second.Skills[0].Name)
+
+	require.NoError(t, repo.DeleteSkill(ctx, inner.resume.ID, uuid.New()))
+	third, err := repo.GetCompleteResume(ctx, inner.resume.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.loads, "a section write should invalidate the cache")
+	assert.Empty(t, third.Skills)
+
+	_, err = repo.GetCompleteResume(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCachedResumeRepositoryInTransaction(t *testing.T) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	t.Cleanup(store.Close)
+
+	inner := &countingResumeRepository{resume: &domain.Resume{ID: uuid.New()}}
+	repo := NewCachedResumeRepository(inner, store, CachedResumeRepositoryConfig{})
+
+	// Reads inside a transaction skip the cache, so they neither see nor
+	// store uncommitted changes
+	state := &txState{}
+	txCtx := context.WithValue(context.Background(), txKey{}, state)
+	_, err = repo.GetCompleteResume(txCtx, inner.resume.ID)
+	require.NoError(t, err)
+	_, err = repo.GetCompleteResume(context.Background(), inner.resume.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.loads)
+
+	// A write drops the entry at once and again when the transaction commits
+	require.NoError(t, repo.DeleteSkill(txCtx, inner.resume.ID, uuid.New()))
+	_, err = repo.GetCompleteResume(context.Background(), inner.resume.ID)
+	require.NoError(t, err)
+	require.Len(t, state.afterCommit, 1)
+	state.afterCommit[0]()
+	_, err = repo.GetCompleteResume(context.Background(), inner.resume.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, inner.loads)
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresConnectorRepository implements the ConnectorRepository interface using PostgreSQL
+type PostgresConnectorRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresConnectorRepository creates a new PostgreSQL connector repository
+func NewPostgresConnectorRepository(db *sqlx.DB) *PostgresConnectorRepository {
+	return &PostgresConnectorRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreateConnector creates a connector. It returns ErrConflict when the
+// resume already has a connector for the account.
+func (r *PostgresConnectorRepository) CreateConnector(ctx context.Context, connector *domain.Connector) error {
+	// Set default values if not provided
+	if connector.ID == uuid.Nil {
+		connector.ID = uuid.New()
+	}
+	if connector.CreatedAt.IsZero() {
+		connector.CreatedAt = time.Now().UTC()
+	}
+	if connector.Conflicts == nil {
+		connector.Conflicts = []*domain.ConnectorConflict{}
+	}
+
+	conflicts, err := json.Marshal(connector.Conflicts)
+	if err != nil {
+		return err
+	}
+
+	err = queriesFor(ctx, r.queries).CreateConnector(ctx, dbgen.CreateConnectorParams{
+		ID:        connector.ID,
+		UserID:    connector.UserID,
+		ResumeID:  connector.ResumeID,
+		Provider:  connector.Provider,
+		Account:   connector.Account,
+		Conflicts: conflicts,
+		CreatedAt: connector.CreatedAt,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", connector.ResumeID.String()).Msg("Failed to create connector")
+		return err
+	}
+
+	return nil
+}
+
+// GetConnector retrieves a connector by ID
+func (r *PostgresConnectorRepository) GetConnector(ctx context.Context, id uuid.UUID) (*domain.Connector, error) {
+	row, err := queriesFor(ctx, r.queries).GetConnector(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("connector_id", id.String()).Msg("Failed to get connector")
+		return nil, err
+	}
+
+	return connectorFromRow(ctx, row)
+}
+
+// GetConnectorsByResume retrieves the connectors of a resume, oldest first
+func (r *PostgresConnectorRepository) GetConnectorsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Connector, error) {
+	rows, err := queriesFor(ctx, r.queries).GetConnectorsByResume(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get connectors by resume")
+		return nil, err
+	}
+
+	connectors := make([]*domain.Connector, 0, len(rows))
+	for _, row := range rows {
+		connector, err := connectorFromRow(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		connectors = append(connectors, connector)
+	}
+
+	return connectors, nil
+}
+
+// UpdateConnectorSync stores the time and conflicts of a connector's sync
+func (r *PostgresConnectorRepository) UpdateConnectorSync(ctx context.Context, connector *domain.Connector) error {
+	if connector.Conflicts == nil {
+		connector.Conflicts = []*domain.ConnectorConflict{}
+	}
+	conflicts, err := json.Marshal(connector.Conflicts)
+	if err != nil {
+		return err
+	}
+
+	var lastSyncedAt sql.NullTime
+	if connector.LastSyncedAt != nil {
+		lastSyncedAt = nullTime(*connector.LastSyncedAt)
+	}
+
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateConnectorSync(ctx, dbgen.UpdateConnectorSyncParams{
+		ID:           connector.ID,
+		LastSyncedAt: lastSyncedAt,
+		Conflicts:    conflicts,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("connector_id", connector.ID.String()).Msg("Failed to update connector sync")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteConnector deletes a connector. The entries it synced stay.
+func (r *PostgresConnectorRepository) DeleteConnector(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteConnector(ctx, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("connector_id", id.String()).Msg("Failed to delete connector")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteConnectorsByResume deletes every connector of a resume
+func (r *PostgresConnectorRepository) DeleteConnectorsByResume(ctx context.Context, resumeID uuid.UUID) error {
+	if err := queriesFor(ctx, r.queries).DeleteConnectorsByResume(ctx, resumeID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to delete resume connectors")
+		return err
+	}
+
+	return nil
+}
+
+// connectorFromRow converts a generated row to a domain connector
+func connectorFromRow(ctx context.Context, row dbgen.Connector) (*domain.Connector, error) {
+	connector := &domain.Connector{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		ResumeID:  row.ResumeID,
+		Provider:  row.Provider,
+		Account:   row.Account,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.LastSyncedAt.Valid {
+		connector.LastSyncedAt = &row.LastSyncedAt.Time
+	}
+	if err := json.Unmarshal(row.Conflicts, &connector.Conflicts); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("connector_id", row.ID.String()).Msg("Failed to decode connector conflicts")
+		return nil, err
+	}
+
+	return connector, nil
+}
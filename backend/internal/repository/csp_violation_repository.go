@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresCSPViolationRepository implements the CSPViolationRepository interface using PostgreSQL
+type PostgresCSPViolationRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresCSPViolationRepository creates a new PostgreSQL CSP violation repository
+func NewPostgresCSPViolationRepository(db *sqlx.DB) *PostgresCSPViolationRepository {
+	return &PostgresCSPViolationRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// RecordViolation counts a report, adding it to the matching violation or creating one
+func (r *PostgresCSPViolationRepository) RecordViolation(ctx context.Context, violation *domain.CSPViolation) error {
+	// Set default values if not provided
+	if violation.ID == uuid.Nil {
+		violation.ID = uuid.New()
+	}
+	if violation.LastSeenAt.IsZero() {
+		violation.LastSeenAt = time.Now().UTC()
+	}
+	if violation.FirstSeenAt.IsZero() {
+		violation.FirstSeenAt = violation.LastSeenAt
+	}
+
+	err := r.queries.RecordCSPViolation(ctx, dbgen.RecordCSPViolationParams{
+		ID:                violation.ID,
+		DocumentUri:       violation.DocumentURI,
+		ViolatedDirective: violation.ViolatedDirective,
+		BlockedUri:        violation.BlockedURI,
+		Disposition:       violation.Disposition,
+		SourceFile:        violation.SourceFile,
+		LineNumber:        int32(violation.LineNumber),
+		ColumnNumber:      int32(violation.ColumnNumber),
+		FirstSeenAt:       violation.FirstSeenAt,
+		LastSeenAt:        violation.LastSeenAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("directive", violation.ViolatedDirective).Msg("Failed to record CSP violation")
+		return err
+	}
+
+	return nil
+}
+
+// ListViolations retrieves a page of violations and the total matching the filters
+func (r *PostgresCSPViolationRepository) ListViolations(ctx context.Context, directive string, opts domain.ListOptions) ([]*domain.CSPViolation, int64, error) {
+	total, err := r.queries.CountCSPViolations(ctx, dbgen.CountCSPViolationsParams{
+		SeenAfter:  nullTime(opts.CreatedAfter),
+		SeenBefore: nullTime(opts.CreatedBefore),
+		Directive:  directive,
+		Search:     searchPattern(opts.Search),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count CSP violations")
+		return nil, 0, err
+	}
+
+	rows, err := r.queries.ListCSPViolations(ctx, dbgen.ListCSPViolationsParams{
+		SeenAfter:  nullTime(opts.CreatedAfter),
+		SeenBefore: nullTime(opts.CreatedBefore),
+		Directive:  directive,
+		Search:     searchPattern(opts.Search),
+		SortBy:     opts.SortBy,
+		SortDesc:   opts.SortDesc,
+		RowOffset:  int32(opts.Offset),
+		RowLimit:   int32(opts.Limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to list CSP violations")
+		return nil, 0, err
+	}
+
+	violations := make([]*domain.CSPViolation, len(rows))
+	for i, row := range rows {
+		violations[i] = &domain.CSPViolation{
+			ID:                row.ID,
+			DocumentURI:       row.DocumentUri,
+			ViolatedDirective: row.ViolatedDirective,
+			BlockedURI:        row.BlockedUri,
+			Disposition:       row.Disposition,
+			SourceFile:        row.SourceFile,
+			LineNumber:        int(row.LineNumber),
+			ColumnNumber:      int(row.ColumnNumber),
+			Count:             row.Count,
+			FirstSeenAt:       row.FirstSeenAt,
+			LastSeenAt:        row.LastSeenAt,
+		}
+	}
+
+	return violations, total, nil
+}
+
+// DeleteViolationsSeenBefore removes violations not reported since before
+func (r *PostgresCSPViolationRepository) DeleteViolationsSeenBefore(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := r.queries.DeleteCSPViolationsSeenBefore(ctx, before)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete old CSP violations")
+		return 0, err
+	}
+
+	return deleted, nil
+}
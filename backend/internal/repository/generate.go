@@ -0,0 +1,6 @@
+package repository
+
+// Typed query functions in dbgen are generated from the SQL in queries/ and
+// the schema in migrations/; edit those files and regenerate instead of
+// changing the generated code.
+//go:generate sh -c "cd ../.. && sqlc generate"
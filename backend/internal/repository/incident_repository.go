@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresIncidentRepository implements the IncidentRepository interface using PostgreSQL
+type PostgresIncidentRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresIncidentRepository creates a new PostgreSQL incident repository
+func NewPostgresIncidentRepository(db *sqlx.DB) *PostgresIncidentRepository {
+	return &PostgresIncidentRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreateIncident creates a new incident
+func (r *PostgresIncidentRepository) CreateIncident(ctx context.Context, incident *domain.Incident) error {
+	// Set default values if not provided
+	if incident.ID == uuid.Nil {
+		incident.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if incident.StartedAt.IsZero() {
+		incident.StartedAt = now
+	}
+	if incident.CreatedAt.IsZero() {
+		incident.CreatedAt = now
+	}
+	if incident.UpdatedAt.IsZero() {
+		incident.UpdatedAt = now
+	}
+
+	err := r.queries.CreateIncident(ctx, dbgen.CreateIncidentParams{
+		ID:          incident.ID,
+		Title:       incident.Title,
+		Description: incident.Description,
+		Status:      incident.Status,
+		Impact:      incident.Impact,
+		StartedAt:   incident.StartedAt,
+		ResolvedAt:  sql.NullTime{Time: incident.ResolvedAt, Valid: !incident.ResolvedAt.IsZero()},
+		CreatedBy:   uuid.NullUUID{UUID: incident.CreatedBy, Valid: incident.CreatedBy != uuid.Nil},
+		CreatedAt:   incident.CreatedAt,
+		UpdatedAt:   incident.UpdatedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create incident")
+		return err
+	}
+
+	return nil
+}
+
+// GetIncidentByID retrieves an incident by ID
+func (r *PostgresIncidentRepository) GetIncidentByID(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
+	row, err := r.queries.GetIncidentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("incident_id", id.String()).Msg("Failed to get incident by ID")
+		return nil, err
+	}
+
+	return incidentFromRow(row), nil
+}
+
+// UpdateIncident updates an incident
+func (r *PostgresIncidentRepository) UpdateIncident(ctx context.Context, incident *domain.Incident) error {
+	// Update the updated_at timestamp
+	incident.UpdatedAt = time.Now().UTC()
+
+	rowsAffected, err := r.queries.UpdateIncident(ctx, dbgen.UpdateIncidentParams{
+		Title:       incident.Title,
+		Description: incident.Description,
+		Status:      incident.Status,
+		Impact:      incident.Impact,
+		ResolvedAt:  sql.NullTime{Time: incident.ResolvedAt, Valid: !incident.ResolvedAt.IsZero()},
+		UpdatedAt:   incident.UpdatedAt,
+		ID:          incident.ID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("incident_id", incident.ID.String()).Msg("Failed to update incident")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteIncident deletes an incident
+func (r *PostgresIncidentRepository) DeleteIncident(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := r.queries.DeleteIncident(ctx, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("incident_id", id.String()).Msg("Failed to delete incident")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetIncidentsSince returns unresolved incidents and those resolved after since, newest first
+func (r *PostgresIncidentRepository) GetIncidentsSince(ctx context.Context, since time.Time) ([]*domain.Incident, error) {
+	rows, err := r.queries.GetIncidentsSince(ctx, sql.NullTime{Time: since, Valid: true})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get incidents")
+		return nil, err
+	}
+
+	incidents := make([]*domain.Incident, len(rows))
+	for i, row := range rows {
+		incidents[i] = incidentFromRow(row)
+	}
+
+	return incidents, nil
+}
+
+// incidentFromRow converts a generated incident row to the domain model
+func incidentFromRow(row dbgen.Incident) *domain.Incident {
+	return &domain.Incident{
+		ID:          row.ID,
+		Title:       row.Title,
+		Description: row.Description,
+		Status:      row.Status,
+		Impact:      row.Impact,
+		StartedAt:   row.StartedAt,
+		ResolvedAt:  row.ResolvedAt.Time,
+		CreatedBy:   row.CreatedBy.UUID,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresInstanceSettingRepository implements the InstanceSettingRepository interface using PostgreSQL
+type PostgresInstanceSettingRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresInstanceSettingRepository creates a new PostgreSQL instance setting repository
+func NewPostgresInstanceSettingRepository(db *sqlx.DB) *PostgresInstanceSettingRepository {
+	return &PostgresInstanceSettingRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// GetSetting retrieves a setting by key
+func (r *PostgresInstanceSettingRepository) GetSetting(ctx context.Context, key string) (*domain.InstanceSetting, error) {
+	row, err := r.queries.GetInstanceSetting(ctx, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("key", key).Msg("Failed to get instance setting")
+		return nil, err
+	}
+
+	return &domain.InstanceSetting{
+		Key:       row.Key,
+		Value:     row.Value,
+		UpdatedBy: row.UpdatedBy.UUID,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+// SaveSetting creates or replaces a setting
+func (r *PostgresInstanceSettingRepository) SaveSetting(ctx context.Context, setting *domain.InstanceSetting) error {
+	// Set default values if not provided
+	if setting.UpdatedAt.IsZero() {
+		setting.UpdatedAt = time.Now().UTC()
+	}
+
+	err := r.queries.SaveInstanceSetting(ctx, dbgen.SaveInstanceSettingParams{
+		Key:       setting.Key,
+		Value:     setting.Value,
+		UpdatedBy: uuid.NullUUID{UUID: setting.UpdatedBy, Valid: setting.UpdatedBy != uuid.Nil},
+		UpdatedAt: setting.UpdatedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("key", setting.Key).Msg("Failed to save instance setting")
+		return err
+	}
+
+	return nil
+}
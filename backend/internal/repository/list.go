@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// likeEscaper escapes the characters ILIKE treats as wildcards
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// searchPattern turns a free-text search into a substring ILIKE pattern. An
+// empty search yields an empty pattern, which the list queries treat as no filter.
+func searchPattern(search string) string {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return ""
+	}
+	return "%" + likeEscaper.Replace(search) + "%"
+}
+
+// nullTime maps the zero time to NULL so optional bounds can be skipped
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
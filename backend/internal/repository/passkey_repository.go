@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresPasskeyRepository implements the PasskeyRepository interface using PostgreSQL
+type PostgresPasskeyRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresPasskeyRepository creates a new PostgreSQL passkey repository
+func NewPostgresPasskeyRepository(db *sqlx.DB) *PostgresPasskeyRepository {
+	return &PostgresPasskeyRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreatePasskey stores a passkey. It returns ErrConflict when the credential
+// is already registered.
+func (r *PostgresPasskeyRepository) CreatePasskey(ctx context.Context, passkey *domain.Passkey) error {
+	// Set default values if not provided
+	if passkey.ID == uuid.Nil {
+		passkey.ID = uuid.New()
+	}
+	if passkey.CreatedAt.IsZero() {
+		passkey.CreatedAt = time.Now().UTC()
+	}
+
+	err := queriesFor(ctx, r.queries).CreatePasskey(ctx, dbgen.CreatePasskeyParams{
+		ID:           passkey.ID,
+		UserID:       passkey.UserID,
+		Name:         passkey.Name,
+		CredentialID: passkey.CredentialID,
+		Credential:   passkey.Credential,
+		CreatedAt:    passkey.CreatedAt,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Ctx(ctx).Error().Err(err).Str("user_id", passkey.UserID.String()).Msg("Failed to create passkey")
+		return err
+	}
+
+	return nil
+}
+
+// CountPasskeysByUserID counts the passkeys of a user
+func (r *PostgresPasskeyRepository) CountPasskeysByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := queriesFor(ctx, r.queries).CountPasskeysByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to count passkeys")
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetPasskeysByUserID retrieves the passkeys of a user, oldest first
+func (r *PostgresPasskeyRepository) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Passkey, error) {
+	rows, err := queriesFor(ctx, r.queries).GetPasskeysByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get passkeys")
+		return nil, err
+	}
+
+	passkeys := make([]*domain.Passkey, len(rows))
+	for i, row := range rows {
+		passkeys[i] = passkeyFromRow(row)
+	}
+
+	return passkeys, nil
+}
+
+// UpdatePasskeyCredential stores a credential's new sign count and flags
+func (r *PostgresPasskeyRepository) UpdatePasskeyCredential(ctx context.Context, id uuid.UUID, credential json.RawMessage, usedAt time.Time) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdatePasskeyCredential(ctx, dbgen.UpdatePasskeyCredentialParams{
+		ID:         id,
+		Credential: credential,
+		LastUsedAt: nullTime(usedAt),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("passkey_id", id.String()).Msg("Failed to update passkey")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeletePasskey deletes one of a user's passkeys
+func (r *PostgresPasskeyRepository) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeletePasskey(ctx, dbgen.DeletePasskeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("passkey_id", id.String()).Msg("Failed to delete passkey")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetPasskeyRequired sets whether a user's password logins must be
+// confirmed with a passkey
+func (r *PostgresPasskeyRepository) SetPasskeyRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).SetUserPasskeyRequired(ctx, dbgen.SetUserPasskeyRequiredParams{
+		ID:              userID,
+		PasskeyRequired: required,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to set passkey requirement")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// passkeyFromRow converts a passkeys row
+func passkeyFromRow(row dbgen.Passkey) *domain.Passkey {
+	return &domain.Passkey{
+		ID:           row.ID,
+		UserID:       row.UserID,
+		Name:         row.Name,
+		CredentialID: row.CredentialID,
+		Credential:   row.Credential,
+		CreatedAt:    row.CreatedAt,
+		LastUsedAt:   row.LastUsedAt.Time,
+	}
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresProvenanceRepository implements the ProvenanceRepository interface using PostgreSQL
+type PostgresProvenanceRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresProvenanceRepository creates a new PostgreSQL provenance repository
+func NewPostgresProvenanceRepository(db *sqlx.DB) *PostgresProvenanceRepository {
+	return &PostgresProvenanceRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// SaveProvenance creates or replaces the provenance of an entry
+func (r *PostgresProvenanceRepository) SaveProvenance(ctx context.Context, provenance *domain.Provenance) error {
+	// Set default values if not provided
+	now := time.Now().UTC()
+	if provenance.ImportedAt.IsZero() {
+		provenance.ImportedAt = now
+	}
+	if provenance.SyncedAt.IsZero() {
+		provenance.SyncedAt = now
+	}
+
+	err := queriesFor(ctx, r.queries).SaveProvenance(ctx, dbgen.SaveProvenanceParams{
+		ResumeID:   provenance.ResumeID,
+		Entity:     provenance.Entity,
+		EntityID:   provenance.EntityID,
+		Source:     provenance.Source,
+		ImportID:   provenance.ImportID,
+		SourceRef:  nullString(provenance.SourceRef),
+		Original:   provenance.Original,
+		ImportedAt: provenance.ImportedAt,
+		SyncedAt:   provenance.SyncedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("entity", provenance.Entity).Str("entity_id", provenance.EntityID.String()).Msg("Failed to save provenance")
+		return err
+	}
+
+	return nil
+}
+
+// GetProvenance retrieves the provenance of an entry
+func (r *PostgresProvenanceRepository) GetProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) (*domain.Provenance, error) {
+	row, err := queriesFor(ctx, r.queries).GetProvenance(ctx, dbgen.GetProvenanceParams{
+		ResumeID: resumeID,
+		Entity:   entity,
+		EntityID: entityID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("entity", entity).Str("entity_id", entityID.String()).Msg("Failed to get provenance")
+		return nil, err
+	}
+
+	return provenanceFromRow(row), nil
+}
+
+// ListProvenance retrieves the provenance of every imported entry of a resume, oldest import first
+func (r *PostgresProvenanceRepository) ListProvenance(ctx context.Context, resumeID uuid.UUID) ([]*domain.Provenance, error) {
+	rows, err := queriesFor(ctx, r.queries).ListProvenance(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to list provenance")
+		return nil, err
+	}
+
+	provenance := make([]*domain.Provenance, 0, len(rows))
+	for _, row := range rows {
+		provenance = append(provenance, provenanceFromRow(row))
+	}
+
+	return provenance, nil
+}
+
+// DeleteProvenance deletes the provenance of an entry
+func (r *PostgresProvenanceRepository) DeleteProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteProvenance(ctx, dbgen.DeleteProvenanceParams{
+		ResumeID: resumeID,
+		Entity:   entity,
+		EntityID: entityID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("entity", entity).Str("entity_id", entityID.String()).Msg("Failed to delete provenance")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteResumeProvenance deletes the provenance of every entry of a resume
+func (r *PostgresProvenanceRepository) DeleteResumeProvenance(ctx context.Context, resumeID uuid.UUID) error {
+	if err := queriesFor(ctx, r.queries).DeleteResumeProvenance(ctx, resumeID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to delete resume provenance")
+		return err
+	}
+
+	return nil
+}
+
+// provenanceFromRow converts a generated row to a domain provenance
+func provenanceFromRow(row dbgen.EntryProvenance) *domain.Provenance {
+	return &domain.Provenance{
+		ResumeID:   row.ResumeID,
+		Entity:     row.Entity,
+		EntityID:   row.EntityID,
+		Source:     row.Source,
+		ImportID:   row.ImportID,
+		SourceRef:  row.SourceRef.String,
+		Original:   row.Original,
+		ImportedAt: row.ImportedAt,
+		SyncedAt:   row.SyncedAt,
+	}
+}
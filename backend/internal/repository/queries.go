@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+)
+
+// preparedQueries holds the queries PrepareQueries prepared, by database
+var preparedQueries sync.Map
+
+// PrepareQueries prepares every generated query on db, so repositories
+// created for db afterwards run prepared statements instead of having the
+// server parse and plan each query on every call. Statements are prepared
+// again on each pooled connection the first time it runs them. Skip this
+// behind a transaction-pooling proxy such as PgBouncer, which can't keep
+// statements prepared on a server connection.
+func PrepareQueries(ctx context.Context, db *sqlx.DB) error {
+	queries, err := dbgen.Prepare(ctx, db)
+	if err != nil {
+		return err
+	}
+	preparedQueries.Store(db, queries)
+	return nil
+}
+
+// newQueries returns the queries of db: prepared ones after PrepareQueries
+func newQueries(db *sqlx.DB) *dbgen.Queries {
+	if queries, ok := preparedQueries.Load(db); ok {
+		return queries.(*dbgen.Queries)
+	}
+	return dbgen.New(db)
+}
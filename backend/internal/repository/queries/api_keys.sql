@@ -0,0 +1,47 @@
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetAPIKeyByHash :one
+SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at
+FROM api_keys
+WHERE key_hash = $1;
+
+-- name: GetAPIKeysByUserID :many
+SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at
+FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: CountAPIKeysByUserID :one
+SELECT COUNT(*) FROM api_keys
+WHERE user_id = $1;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = $1
+WHERE id = $2;
+
+-- name: DeleteAPIKey :execrows
+DELETE FROM api_keys
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetAPIKeyIDsUsedSince :many
+SELECT id FROM api_keys
+WHERE last_used_at >= $1;
+
+-- name: UpsertAPIKeyUsage :exec
+INSERT INTO api_key_usage (api_key_id, hour, requests)
+VALUES ($1, $2, $3)
+ON CONFLICT (api_key_id, hour) DO UPDATE
+SET requests = EXCLUDED.requests;
+
+-- name: GetAPIKeyUsageSince :many
+SELECT api_key_id, hour, requests
+FROM api_key_usage
+WHERE api_key_id = $1 AND hour >= $2
+ORDER BY hour;
+
+-- name: DeleteAPIKeyUsageBefore :execrows
+DELETE FROM api_key_usage
+WHERE hour < $1;
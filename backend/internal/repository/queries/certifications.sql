@@ -0,0 +1,33 @@
+-- name: CreateCertification :one
+INSERT INTO certifications (
+    id, resume_id, name, issuer, issue_date,
+    expiry_date, credential_id, url, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id;
+
+-- name: UpdateCertification :execrows
+UPDATE certifications
+SET name = $1,
+    issuer = $2,
+    issue_date = $3,
+    expiry_date = $4,
+    credential_id = $5,
+    url = $6,
+    updated_at = $7
+WHERE id = $8 AND resume_id = $9;
+
+-- name: DeleteCertification :execrows
+DELETE FROM certifications
+WHERE id = $1 AND resume_id = $2;
+
+-- name: GetCertification :one
+SELECT id, name, issuer, issue_date, expiry_date, credential_id, url
+FROM certifications
+WHERE id = $1;
+
+-- name: GetCertificationsByResume :many
+SELECT id, name, issuer, issue_date, expiry_date, credential_id, url
+FROM certifications
+WHERE resume_id = $1
+ORDER BY issue_date DESC;
@@ -0,0 +1,27 @@
+-- name: CreateConnector :exec
+INSERT INTO connectors (id, user_id, resume_id, provider, account, conflicts, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetConnector :one
+SELECT id, user_id, resume_id, provider, account, last_synced_at, conflicts, created_at
+FROM connectors
+WHERE id = $1;
+
+-- name: GetConnectorsByResume :many
+SELECT id, user_id, resume_id, provider, account, last_synced_at, conflicts, created_at
+FROM connectors
+WHERE resume_id = $1
+ORDER BY created_at;
+
+-- name: UpdateConnectorSync :execrows
+UPDATE connectors
+SET last_synced_at = $2, conflicts = $3
+WHERE id = $1;
+
+-- name: DeleteConnector :execrows
+DELETE FROM connectors
+WHERE id = $1;
+
+-- name: DeleteConnectorsByResume :exec
+DELETE FROM connectors
+WHERE resume_id = $1;
@@ -0,0 +1,38 @@
+-- name: RecordCSPViolation :exec
+INSERT INTO csp_violations (id, document_uri, violated_directive, blocked_uri, disposition, source_file, line_number, column_number, count, first_seen_at, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 1, $9, $10)
+ON CONFLICT (document_uri, violated_directive, blocked_uri, disposition) DO UPDATE
+SET count = csp_violations.count + 1,
+    source_file = EXCLUDED.source_file,
+    line_number = EXCLUDED.line_number,
+    column_number = EXCLUDED.column_number,
+    last_seen_at = GREATEST(csp_violations.last_seen_at, EXCLUDED.last_seen_at);
+
+-- name: ListCSPViolations :many
+SELECT id, document_uri, violated_directive, blocked_uri, disposition, source_file, line_number, column_number, count, first_seen_at, last_seen_at
+FROM csp_violations
+WHERE (sqlc.narg(seen_after)::timestamptz IS NULL OR last_seen_at >= sqlc.narg(seen_after))
+  AND (sqlc.narg(seen_before)::timestamptz IS NULL OR first_seen_at < sqlc.narg(seen_before))
+  AND (sqlc.arg(directive)::text = '' OR violated_directive = sqlc.arg(directive))
+  AND (sqlc.arg(search)::text = '' OR document_uri ILIKE sqlc.arg(search) OR blocked_uri ILIKE sqlc.arg(search))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'count' AND NOT sqlc.arg(sort_desc)::bool THEN count END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'count' AND sqlc.arg(sort_desc)::bool THEN count END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'last_seen_at' AND NOT sqlc.arg(sort_desc)::bool THEN last_seen_at END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'last_seen_at' AND sqlc.arg(sort_desc)::bool THEN last_seen_at END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::bool THEN first_seen_at END ASC,
+  first_seen_at DESC,
+  id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountCSPViolations :one
+SELECT COUNT(*)
+FROM csp_violations
+WHERE (sqlc.narg(seen_after)::timestamptz IS NULL OR last_seen_at >= sqlc.narg(seen_after))
+  AND (sqlc.narg(seen_before)::timestamptz IS NULL OR first_seen_at < sqlc.narg(seen_before))
+  AND (sqlc.arg(directive)::text = '' OR violated_directive = sqlc.arg(directive))
+  AND (sqlc.arg(search)::text = '' OR document_uri ILIKE sqlc.arg(search) OR blocked_uri ILIKE sqlc.arg(search));
+
+-- name: DeleteCSPViolationsSeenBefore :execrows
+DELETE FROM csp_violations
+WHERE last_seen_at < $1;
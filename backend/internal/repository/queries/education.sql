@@ -0,0 +1,34 @@
+-- name: CreateEducation :one
+INSERT INTO education (
+    id, resume_id, institution, location, degree, field,
+    start_date, end_date, description, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id;
+
+-- name: UpdateEducation :execrows
+UPDATE education
+SET institution = $1,
+    location = $2,
+    degree = $3,
+    field = $4,
+    start_date = $5,
+    end_date = $6,
+    description = $7,
+    updated_at = $8
+WHERE id = $9 AND resume_id = $10;
+
+-- name: DeleteEducation :execrows
+DELETE FROM education
+WHERE id = $1 AND resume_id = $2;
+
+-- name: GetEducation :one
+SELECT id, institution, location, degree, field, start_date, end_date, description
+FROM education
+WHERE id = $1;
+
+-- name: GetEducationByResume :many
+SELECT id, institution, location, degree, field, start_date, end_date, description
+FROM education
+WHERE resume_id = $1
+ORDER BY start_date DESC;
@@ -0,0 +1,28 @@
+-- name: SaveProvenance :exec
+INSERT INTO entry_provenance (resume_id, entity, entity_id, source, import_id, source_ref, original, imported_at, synced_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (resume_id, entity, entity_id) DO UPDATE
+SET source = EXCLUDED.source,
+    import_id = EXCLUDED.import_id,
+    source_ref = EXCLUDED.source_ref,
+    original = EXCLUDED.original,
+    synced_at = EXCLUDED.synced_at;
+
+-- name: GetProvenance :one
+SELECT resume_id, entity, entity_id, source, import_id, source_ref, original, imported_at, synced_at
+FROM entry_provenance
+WHERE resume_id = $1 AND entity = $2 AND entity_id = $3;
+
+-- name: ListProvenance :many
+SELECT resume_id, entity, entity_id, source, import_id, source_ref, original, imported_at, synced_at
+FROM entry_provenance
+WHERE resume_id = $1
+ORDER BY imported_at, entity, entity_id;
+
+-- name: DeleteProvenance :execrows
+DELETE FROM entry_provenance
+WHERE resume_id = $1 AND entity = $2 AND entity_id = $3;
+
+-- name: DeleteResumeProvenance :exec
+DELETE FROM entry_provenance
+WHERE resume_id = $1;
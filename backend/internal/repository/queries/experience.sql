@@ -0,0 +1,33 @@
+-- name: CreateExperience :one
+INSERT INTO experience (
+    id, resume_id, employer, job_title, location,
+    start_date, end_date, description, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id;
+
+-- name: UpdateExperience :execrows
+UPDATE experience
+SET employer = $1,
+    job_title = $2,
+    location = $3,
+    start_date = $4,
+    end_date = $5,
+    description = $6,
+    updated_at = $7
+WHERE id = $8 AND resume_id = $9;
+
+-- name: DeleteExperience :execrows
+DELETE FROM experience
+WHERE id = $1 AND resume_id = $2;
+
+-- name: GetExperience :one
+SELECT id, employer, job_title, location, start_date, end_date, description
+FROM experience
+WHERE id = $1 AND resume_id = $2;
+
+-- name: GetExperienceByResume :many
+SELECT id, employer, job_title, location, start_date, end_date, description
+FROM experience
+WHERE resume_id = $1
+ORDER BY start_date DESC;
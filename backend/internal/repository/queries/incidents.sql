@@ -0,0 +1,23 @@
+-- name: CreateIncident :exec
+INSERT INTO incidents (id, title, description, status, impact, started_at, resolved_at, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetIncidentByID :one
+SELECT id, title, description, status, impact, started_at, resolved_at, created_by, created_at, updated_at
+FROM incidents
+WHERE id = $1;
+
+-- name: UpdateIncident :execrows
+UPDATE incidents
+SET title = $1, description = $2, status = $3, impact = $4, resolved_at = $5, updated_at = $6
+WHERE id = $7;
+
+-- name: DeleteIncident :execrows
+DELETE FROM incidents
+WHERE id = $1;
+
+-- name: GetIncidentsSince :many
+SELECT id, title, description, status, impact, started_at, resolved_at, created_by, created_at, updated_at
+FROM incidents
+WHERE resolved_at IS NULL OR resolved_at >= $1
+ORDER BY started_at DESC;
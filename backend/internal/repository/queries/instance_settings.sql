@@ -0,0 +1,10 @@
+-- name: GetInstanceSetting :one
+SELECT key, value, updated_by, updated_at
+FROM instance_settings
+WHERE key = $1;
+
+-- name: SaveInstanceSetting :exec
+INSERT INTO instance_settings (key, value, updated_by, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (key) DO UPDATE
+SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at;
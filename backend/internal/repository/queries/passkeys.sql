@@ -0,0 +1,28 @@
+-- name: CreatePasskey :exec
+INSERT INTO passkeys (id, user_id, name, credential_id, credential, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: CountPasskeysByUserID :one
+SELECT COUNT(*)
+FROM passkeys
+WHERE user_id = $1;
+
+-- name: GetPasskeysByUserID :many
+SELECT id, user_id, name, credential_id, credential, created_at, last_used_at
+FROM passkeys
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: UpdatePasskeyCredential :execrows
+UPDATE passkeys
+SET credential = $2, last_used_at = $3
+WHERE id = $1;
+
+-- name: DeletePasskey :execrows
+DELETE FROM passkeys
+WHERE id = $1 AND user_id = $2;
+
+-- name: SetUserPasskeyRequired :execrows
+UPDATE users
+SET passkey_required = $2
+WHERE id = $1;
@@ -0,0 +1,63 @@
+-- name: CreateProject :one
+INSERT INTO projects (
+    id, resume_id, name, description, repo_url, demo_url,
+    start_date, end_date, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id;
+
+-- name: UpdateProject :execrows
+UPDATE projects
+SET name = $1,
+    description = $2,
+    repo_url = $3,
+    demo_url = $4,
+    start_date = $5,
+    end_date = $6,
+    updated_at = $7
+WHERE id = $8 AND resume_id = $9;
+
+-- name: DeleteProject :execrows
+DELETE FROM projects
+WHERE id = $1 AND resume_id = $2;
+
+-- name: GetProject :one
+SELECT id, name, description, repo_url, demo_url, start_date, end_date
+FROM projects
+WHERE id = $1;
+
+-- name: GetProjectsByResume :many
+SELECT id, name, description, repo_url, demo_url, start_date, end_date
+FROM projects
+WHERE resume_id = $1
+ORDER BY COALESCE(start_date, '9999-12-31') DESC;
+
+-- name: AddProjectTechnology :execrows
+INSERT INTO project_technologies (id, project_id, technology)
+SELECT $1, p.id, $2
+FROM projects p
+WHERE p.id = sqlc.arg(project_id) AND p.resume_id = sqlc.arg(resume_id);
+
+-- name: DeleteProjectTechnology :execrows
+DELETE FROM project_technologies pt
+USING projects p
+WHERE pt.project_id = p.id
+  AND p.id = sqlc.arg(project_id)
+  AND p.resume_id = sqlc.arg(resume_id)
+  AND pt.technology = sqlc.arg(technology);
+
+-- name: DeleteProjectTechnologies :exec
+DELETE FROM project_technologies
+WHERE project_id = $1;
+
+-- name: GetProjectTechnologies :many
+SELECT technology
+FROM project_technologies
+WHERE project_id = $1
+ORDER BY technology;
+
+-- name: GetProjectTechnologiesByProjects :many
+SELECT project_id, technology
+FROM project_technologies
+WHERE project_id = ANY(sqlc.arg(project_ids)::uuid[])
+ORDER BY project_id, technology;
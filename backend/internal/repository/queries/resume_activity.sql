@@ -0,0 +1,15 @@
+-- name: AddResumeActivity :exec
+INSERT INTO resume_activity (id, resume_id, kind, actor_id, details, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetResumeActivityBefore :many
+SELECT id, resume_id, kind, actor_id, details, created_at
+FROM resume_activity
+WHERE resume_id = sqlc.arg(resume_id)
+  AND (sqlc.narg(before)::TIMESTAMPTZ IS NULL OR (created_at, id) < (sqlc.narg(before)::TIMESTAMPTZ, sqlc.arg(before_id)::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: DeleteResumeActivity :exec
+DELETE FROM resume_activity
+WHERE resume_id = $1;
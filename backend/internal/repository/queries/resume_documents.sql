@@ -0,0 +1,70 @@
+-- name: CreateResumeDocument :exec
+INSERT INTO resume_documents (id, user_id, title, target_job_title, tags, language, translation_of, settings, document, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+
+-- name: GetResumeDocumentByID :one
+SELECT id, user_id, title, target_job_title, tags, language, translation_of, settings, created_at, updated_at
+FROM resume_documents
+WHERE id = $1;
+
+-- name: GetResumeDocumentsByUserID :many
+SELECT id, user_id, title, target_job_title, tags, language, translation_of, settings, created_at, updated_at
+FROM resume_documents
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListResumeDocumentsByUserID :many
+SELECT id, user_id, title, target_job_title, tags, language, translation_of, settings, created_at, updated_at
+FROM resume_documents
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.arg(search)::text = '' OR title ILIKE sqlc.arg(search) OR target_job_title ILIKE sqlc.arg(search))
+  AND (sqlc.arg(tag)::text = '' OR sqlc.arg(tag) = ANY(tags))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'title' AND NOT sqlc.arg(sort_desc)::bool THEN title END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'title' AND sqlc.arg(sort_desc)::bool THEN title END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::bool THEN created_at END ASC,
+  created_at DESC,
+  id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountResumeDocumentsByUserID :one
+SELECT COUNT(*)
+FROM resume_documents
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.arg(search)::text = '' OR title ILIKE sqlc.arg(search) OR target_job_title ILIKE sqlc.arg(search))
+  AND (sqlc.arg(tag)::text = '' OR sqlc.arg(tag) = ANY(tags));
+
+-- name: GetResumeDocument :one
+SELECT id, user_id, title, target_job_title, tags, language, translation_of, settings, created_at, updated_at, document
+FROM resume_documents
+WHERE id = $1;
+
+-- name: GetResumeDocumentForUpdate :one
+SELECT id, user_id, title, target_job_title, tags, language, translation_of, settings, created_at, updated_at, document
+FROM resume_documents
+WHERE id = $1
+FOR UPDATE;
+
+-- name: FindResumeDocumentIDByContent :one
+SELECT id
+FROM resume_documents
+WHERE document @> sqlc.arg(content)::jsonb
+LIMIT 1;
+
+-- name: UpdateResumeDocument :execrows
+UPDATE resume_documents
+SET document = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: UpdateResumeDocumentMetadata :execrows
+UPDATE resume_documents
+SET title = $1, target_job_title = $2, tags = $3, language = $4, settings = $5, updated_at = $6
+WHERE id = $7;
+
+-- name: DeleteResumeDocument :execrows
+DELETE FROM resume_documents
+WHERE id = $1;
@@ -0,0 +1,58 @@
+-- name: AppendResumeEvent :one
+-- The owner is looked up in either storage mode, or carried over from the
+-- resume's earlier events once it is deleted
+INSERT INTO resume_events (id, resume_id, version, entity, entity_id, op, payload, actor_id, created_at, owner_id)
+SELECT sqlc.arg(id), sqlc.arg(resume_id), COALESCE(MAX(version), 0) + 1,
+       sqlc.arg(entity), sqlc.narg(entity_id), sqlc.arg(op), sqlc.arg(payload), sqlc.narg(actor_id), sqlc.arg(created_at),
+       COALESCE(
+           (SELECT user_id FROM resumes WHERE id = sqlc.arg(resume_id)),
+           (SELECT user_id FROM resume_documents WHERE id = sqlc.arg(resume_id)),
+           (array_agg(owner_id ORDER BY version DESC) FILTER (WHERE owner_id IS NOT NULL))[1]
+       )
+FROM resume_events
+WHERE resume_id = sqlc.arg(resume_id)
+RETURNING version;
+
+-- name: GetResumeEvents :many
+SELECT id, resume_id, version, entity, entity_id, op, payload, actor_id, created_at, owner_id, seq
+FROM resume_events
+WHERE resume_id = $1 AND version > $2
+ORDER BY version
+LIMIT $3;
+
+-- name: GetResumeEventsUpTo :many
+SELECT id, resume_id, version, entity, entity_id, op, payload, actor_id, created_at, owner_id, seq
+FROM resume_events
+WHERE resume_id = $1 AND version <= $2
+ORDER BY version;
+
+-- name: CountResumeEventsSince :many
+SELECT resume_id, COUNT(*) AS changes
+FROM resume_events
+WHERE resume_id = ANY(sqlc.arg(resume_ids)::uuid[]) AND created_at >= sqlc.arg(since)
+GROUP BY resume_id;
+
+-- name: GetResumeEventsBefore :many
+SELECT id, resume_id, version, entity, entity_id, op, payload, actor_id, created_at, owner_id, seq
+FROM resume_events
+WHERE resume_id = sqlc.arg(resume_id)
+  AND (sqlc.narg(before)::TIMESTAMPTZ IS NULL OR (created_at, id) < (sqlc.narg(before)::TIMESTAMPTZ, sqlc.arg(before_id)::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetOwnerResumeEventsAfter :many
+SELECT id, resume_id, version, entity, entity_id, op, payload, actor_id, created_at, owner_id, seq
+FROM resume_events
+WHERE owner_id = sqlc.arg(owner_id) AND seq > sqlc.arg(after_seq)
+  AND created_at > sqlc.arg(since) AND created_at <= sqlc.arg(until)
+ORDER BY seq
+LIMIT sqlc.arg(page_size);
+
+-- name: GetOwnerChangedResumes :many
+SELECT resume_id, MAX(seq)::BIGINT AS seq, MAX(created_at)::TIMESTAMPTZ AS changed_at
+FROM resume_events
+WHERE owner_id = sqlc.arg(owner_id) AND seq > sqlc.arg(after_seq)
+  AND created_at > sqlc.arg(since) AND created_at <= sqlc.arg(until)
+GROUP BY resume_id
+ORDER BY MAX(seq)
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,29 @@
+-- name: CreateResumeNote :exec
+INSERT INTO resume_notes (resume_id, version, user_id, algorithm, key_id, nonce, ciphertext, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetLatestResumeNote :one
+SELECT resume_id, version, user_id, algorithm, key_id, nonce, ciphertext, created_at
+FROM resume_notes
+WHERE resume_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetResumeNoteVersion :one
+SELECT resume_id, version, user_id, algorithm, key_id, nonce, ciphertext, created_at
+FROM resume_notes
+WHERE resume_id = $1 AND version = $2;
+
+-- name: GetResumeNoteVersions :many
+SELECT resume_id, version, user_id, algorithm, key_id, octet_length(ciphertext) AS size, created_at
+FROM resume_notes
+WHERE resume_id = $1
+ORDER BY version DESC;
+
+-- name: DeleteResumeNoteVersionsBefore :execrows
+DELETE FROM resume_notes
+WHERE resume_id = $1 AND version < $2;
+
+-- name: DeleteResumeNotes :execrows
+DELETE FROM resume_notes
+WHERE resume_id = $1;
@@ -0,0 +1,25 @@
+-- name: UpsertResumePublication :one
+INSERT INTO resume_publications (resume_id, user_id, slug, hidden, published_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $5)
+ON CONFLICT (resume_id) DO UPDATE
+SET slug = EXCLUDED.slug, hidden = EXCLUDED.hidden, updated_at = EXCLUDED.updated_at
+RETURNING resume_id, user_id, slug, hidden, published_at, updated_at;
+
+-- name: GetResumePublication :one
+SELECT resume_id, user_id, slug, hidden, published_at, updated_at
+FROM resume_publications
+WHERE resume_id = $1;
+
+-- name: GetResumePublicationBySlug :one
+SELECT resume_id, user_id, slug, hidden, published_at, updated_at
+FROM resume_publications
+WHERE slug = $1;
+
+-- name: GetTakenResumePublicationSlugs :many
+SELECT slug
+FROM resume_publications
+WHERE slug = ANY(sqlc.arg(slugs)::TEXT[]);
+
+-- name: DeleteResumePublication :execrows
+DELETE FROM resume_publications
+WHERE resume_id = $1;
@@ -0,0 +1,33 @@
+-- name: CreateResumeShare :exec
+INSERT INTO resume_shares (id, resume_id, token, passphrase_hash, max_views, expires_at, hidden, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetResumeShareByID :one
+SELECT id, resume_id, token, passphrase_hash, created_at, max_views, expires_at, hidden
+FROM resume_shares
+WHERE id = $1 AND resume_id = $2;
+
+-- name: GetResumeShareByToken :one
+SELECT id, resume_id, token, passphrase_hash, created_at, max_views, expires_at, hidden
+FROM resume_shares
+WHERE token = $1;
+
+-- name: GetResumeSharesByResumeID :many
+SELECT id, resume_id, token, passphrase_hash, created_at, max_views, expires_at, hidden
+FROM resume_shares
+WHERE resume_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateResumeShare :execrows
+UPDATE resume_shares
+SET max_views = $1, expires_at = $2, hidden = $3
+WHERE id = $4 AND resume_id = $5;
+
+-- name: DeleteResumeShare :execrows
+DELETE FROM resume_shares
+WHERE id = $1 AND resume_id = $2;
+
+-- name: DeleteResumeSharesByResumeID :many
+DELETE FROM resume_shares
+WHERE resume_id = $1
+RETURNING id;
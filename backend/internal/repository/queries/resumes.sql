@@ -0,0 +1,82 @@
+-- name: CreateResume :exec
+INSERT INTO resumes (id, user_id, title, target_job_title, tags, language, translation_of, settings, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetResumeByID :one
+SELECT id, user_id, created_at, title, target_job_title, tags, updated_at, language, translation_of, settings
+FROM resumes
+WHERE id = $1;
+
+-- name: GetResumesByUserID :many
+SELECT id, user_id, created_at, title, target_job_title, tags, updated_at, language, translation_of, settings
+FROM resumes
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListResumesByUserID :many
+SELECT id, user_id, created_at, title, target_job_title, tags, updated_at, language, translation_of, settings
+FROM resumes
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.arg(search)::text = '' OR title ILIKE sqlc.arg(search) OR target_job_title ILIKE sqlc.arg(search))
+  AND (sqlc.arg(tag)::text = '' OR sqlc.arg(tag) = ANY(tags))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'title' AND NOT sqlc.arg(sort_desc)::bool THEN title END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'title' AND sqlc.arg(sort_desc)::bool THEN title END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::bool THEN created_at END ASC,
+  created_at DESC,
+  id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountResumesByUserID :one
+SELECT COUNT(*)
+FROM resumes
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.arg(search)::text = '' OR title ILIKE sqlc.arg(search) OR target_job_title ILIKE sqlc.arg(search))
+  AND (sqlc.arg(tag)::text = '' OR sqlc.arg(tag) = ANY(tags));
+
+-- name: UpdateResumeMetadata :execrows
+UPDATE resumes
+SET title = $1, target_job_title = $2, tags = $3, language = $4, settings = $5, updated_at = $6
+WHERE id = $7;
+
+-- name: DeleteResume :execrows
+DELETE FROM resumes
+WHERE id = $1;
+
+-- name: UpsertPersonalInfo :exec
+INSERT INTO personal_info (
+    id, resume_id, first_name, last_name, email, phone, phone_country,
+    street, address_line2, city, region, postal_code, country, address_free_form,
+    job_title, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+ON CONFLICT (resume_id) DO UPDATE SET
+    first_name = EXCLUDED.first_name,
+    last_name = EXCLUDED.last_name,
+    email = EXCLUDED.email,
+    phone = EXCLUDED.phone,
+    phone_country = EXCLUDED.phone_country,
+    street = EXCLUDED.street,
+    address_line2 = EXCLUDED.address_line2,
+    city = EXCLUDED.city,
+    region = EXCLUDED.region,
+    postal_code = EXCLUDED.postal_code,
+    country = EXCLUDED.country,
+    address_free_form = EXCLUDED.address_free_form,
+    job_title = EXCLUDED.job_title,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: GetPersonalInfo :one
+SELECT first_name, last_name, email, phone, phone_country, street, address_line2, city,
+    region, postal_code, country, address_free_form, job_title, photo_url
+FROM personal_info
+WHERE resume_id = $1;
+
+-- name: SetPersonalInfoPhoto :execrows
+UPDATE personal_info
+SET photo_url = $2, updated_at = $3
+WHERE resume_id = $1;
@@ -0,0 +1,14 @@
+-- name: ListRoleProfiles :many
+SELECT slug, profile, updated_by, updated_at
+FROM role_profiles
+ORDER BY slug;
+
+-- name: SaveRoleProfile :exec
+INSERT INTO role_profiles (slug, profile, updated_by, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (slug) DO UPDATE
+SET profile = EXCLUDED.profile, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteRoleProfile :execrows
+DELETE FROM role_profiles
+WHERE slug = $1;
@@ -0,0 +1,43 @@
+-- name: CreateServiceAccount :exec
+INSERT INTO service_accounts (id, name, description, prefix, key_hash, scopes, expires_at, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: GetServiceAccount :one
+SELECT id, name, description, prefix, key_hash, scopes, expires_at, last_used_at, created_by, created_at
+FROM service_accounts
+WHERE id = $1;
+
+-- name: GetServiceAccountByHash :one
+SELECT id, name, description, prefix, key_hash, scopes, expires_at, last_used_at, created_by, created_at
+FROM service_accounts
+WHERE key_hash = $1;
+
+-- name: ListServiceAccounts :many
+SELECT id, name, description, prefix, key_hash, scopes, expires_at, last_used_at, created_by, created_at
+FROM service_accounts
+ORDER BY created_at DESC;
+
+-- name: UpdateServiceAccountKey :execrows
+UPDATE service_accounts
+SET prefix = $1, key_hash = $2, last_used_at = NULL
+WHERE id = $3;
+
+-- name: TouchServiceAccount :exec
+UPDATE service_accounts
+SET last_used_at = $1
+WHERE id = $2;
+
+-- name: DeleteServiceAccount :execrows
+DELETE FROM service_accounts
+WHERE id = $1;
+
+-- name: AddServiceAccountAuditEntry :exec
+INSERT INTO service_account_audit (id, service_account_id, actor_id, action, details, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetServiceAccountAuditEntries :many
+SELECT id, service_account_id, actor_id, action, details, created_at
+FROM service_account_audit
+WHERE service_account_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
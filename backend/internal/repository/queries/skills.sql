@@ -0,0 +1,29 @@
+-- name: CreateSkill :one
+INSERT INTO skills (
+    id, resume_id, name, category, proficiency, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
+
+-- name: UpdateSkill :execrows
+UPDATE skills
+SET name = $1,
+    category = $2,
+    proficiency = $3,
+    updated_at = $4
+WHERE id = $5 AND resume_id = $6;
+
+-- name: DeleteSkill :execrows
+DELETE FROM skills
+WHERE id = $1 AND resume_id = $2;
+
+-- name: GetSkill :one
+SELECT id, name, category, proficiency
+FROM skills
+WHERE id = $1;
+
+-- name: GetSkillsByResume :many
+SELECT id, name, category, proficiency
+FROM skills
+WHERE resume_id = $1
+ORDER BY category, name;
@@ -0,0 +1,32 @@
+-- name: CountActiveSessions :one
+SELECT COUNT(*) FROM sessions WHERE expires_at > $1;
+
+-- name: GetResumesPerUser :many
+-- Users by how many resumes they have, including users without any
+SELECT COALESCE(r.resumes, 0)::BIGINT AS resumes, COUNT(*) AS users
+FROM users u
+LEFT JOIN (SELECT user_id, COUNT(*) AS resumes FROM resumes GROUP BY user_id) r ON r.user_id = u.id
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetResumeDocumentsPerUser :many
+-- Users by how many resume documents they have, including users without any
+SELECT COALESCE(r.resumes, 0)::BIGINT AS resumes, COUNT(*) AS users
+FROM users u
+LEFT JOIN (SELECT user_id, COUNT(*) AS resumes FROM resume_documents GROUP BY user_id) r ON r.user_id = u.id
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetExportsPerDay :many
+SELECT date_trunc('day', created_at AT TIME ZONE 'UTC')::TIMESTAMP AS day, COUNT(*) AS exports
+FROM resume_activity
+WHERE kind = 'export' AND created_at >= $1
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetSignupsPerWeek :many
+SELECT date_trunc('week', created_at AT TIME ZONE 'UTC')::TIMESTAMP AS week, COUNT(*) AS signups
+FROM users
+WHERE created_at >= $1
+GROUP BY 1
+ORDER BY 1;
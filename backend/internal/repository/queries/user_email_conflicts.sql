@@ -0,0 +1,35 @@
+-- name: ListUserEmailConflicts :many
+SELECT user_id, email, created_at
+FROM user_email_conflicts
+WHERE lower(email) = lower(sqlc.arg(email))
+ORDER BY created_at, user_id;
+
+-- name: MoveResumesToUser :exec
+UPDATE resumes
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: MoveResumeDocumentsToUser :exec
+UPDATE resume_documents
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: MoveResumeNotesToUser :exec
+UPDATE resume_notes
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: MoveResumePublicationsToUser :exec
+UPDATE resume_publications
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: MoveConnectorsToUser :exec
+UPDATE connectors
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: MoveResumeEventsToOwner :exec
+UPDATE resume_events
+SET owner_id = sqlc.arg(to_user_id)::uuid
+WHERE owner_id = sqlc.arg(from_user_id)::uuid;
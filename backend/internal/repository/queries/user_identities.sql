@@ -0,0 +1,19 @@
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (id, user_id, provider, subject, email, created_at, last_login_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetUserIdentity :one
+SELECT id, user_id, provider, subject, email, created_at, last_login_at
+FROM user_identities
+WHERE provider = $1 AND subject = $2;
+
+-- name: TouchUserIdentity :execrows
+UPDATE user_identities
+SET email = $1, last_login_at = $2
+WHERE id = $3;
+
+-- name: GetUserIdentitiesByUserID :many
+SELECT id, user_id, provider, subject, email, created_at, last_login_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at;
@@ -0,0 +1,20 @@
+-- name: UpsertUserPhone :one
+INSERT INTO user_phones (user_id, phone, verified_at, created_at, updated_at)
+VALUES ($1, $2, NULL, $3, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET phone = EXCLUDED.phone, verified_at = NULL, updated_at = EXCLUDED.updated_at
+RETURNING user_id, phone, verified_at, created_at, updated_at;
+
+-- name: GetUserPhone :one
+SELECT user_id, phone, verified_at, created_at, updated_at
+FROM user_phones
+WHERE user_id = $1;
+
+-- name: VerifyUserPhone :execrows
+UPDATE user_phones
+SET verified_at = $3, updated_at = $3
+WHERE user_id = $1 AND phone = $2;
+
+-- name: DeleteUserPhone :execrows
+DELETE FROM user_phones
+WHERE user_id = $1;
@@ -0,0 +1,14 @@
+-- name: ListUserRoles :many
+SELECT name, description, permissions, updated_at
+FROM user_roles
+ORDER BY name;
+
+-- name: SaveUserRole :exec
+INSERT INTO user_roles (name, description, permissions, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (name) DO UPDATE
+SET description = EXCLUDED.description, permissions = EXCLUDED.permissions, updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteUserRole :execrows
+DELETE FROM user_roles
+WHERE name = $1;
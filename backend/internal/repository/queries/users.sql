@@ -0,0 +1,133 @@
+-- name: CreateUser :exec
+INSERT INTO users (id, email, password_hash, role, timezone, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetUserByID :one
+SELECT id, email, password_hash, role, created_at, updated_at, timezone, weekly_digest, digest_sent_at, passkey_required, locale
+FROM users
+WHERE id = $1;
+
+-- name: GetUserByEmail :one
+SELECT id, email, password_hash, role, created_at, updated_at, timezone, weekly_digest, digest_sent_at, passkey_required, locale
+FROM users
+WHERE lower(email) = lower(sqlc.arg(email));
+
+-- name: ListUsers :many
+SELECT id, email, password_hash, role, created_at, updated_at, timezone, weekly_digest, digest_sent_at, passkey_required, locale
+FROM users
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.arg(search)::text = '' OR email ILIKE sqlc.arg(search))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'email' AND NOT sqlc.arg(sort_desc)::bool THEN email END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'email' AND sqlc.arg(sort_desc)::bool THEN email END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::bool THEN created_at END ASC,
+  created_at DESC,
+  id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.arg(search)::text = '' OR email ILIKE sqlc.arg(search));
+
+-- name: UpdateUser :execrows
+UPDATE users
+SET email = $1, password_hash = $2, role = $3, timezone = $4, weekly_digest = $5, locale = $6, updated_at = $7
+WHERE id = $8;
+
+-- name: ListDigestRecipients :many
+SELECT id, email, password_hash, role, created_at, updated_at, timezone, weekly_digest, digest_sent_at, passkey_required, locale
+FROM users
+WHERE weekly_digest
+  AND (digest_sent_at IS NULL OR digest_sent_at < sqlc.arg(sent_before)::timestamptz)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: MarkDigestSent :execrows
+UPDATE users
+SET digest_sent_at = $1
+WHERE id = $2;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
+
+-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at, last_used_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetSessionByID :one
+SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at, last_used_at
+FROM sessions
+WHERE id = $1;
+
+-- name: GetSessionByToken :one
+SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at, last_used_at
+FROM sessions
+WHERE refresh_token = $1;
+
+-- name: GetSessionsByUserID :many
+SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at, last_used_at
+FROM sessions
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: RotateSession :execrows
+UPDATE sessions
+SET refresh_token = sqlc.arg(new_refresh_token), user_agent = sqlc.arg(user_agent), client_ip = sqlc.arg(client_ip),
+    expires_at = sqlc.arg(expires_at), last_used_at = sqlc.arg(last_used_at)
+WHERE id = sqlc.arg(id) AND refresh_token = sqlc.arg(refresh_token);
+
+-- name: DeleteSession :execrows
+DELETE FROM sessions
+WHERE id = $1;
+
+-- name: DeleteUserSessions :exec
+DELETE FROM sessions
+WHERE user_id = $1;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < $1;
+
+-- name: CreatePasswordReset :exec
+INSERT INTO password_resets (id, user_id, token, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetPasswordResetByToken :one
+SELECT id, user_id, token, expires_at, created_at, used_at
+FROM password_resets
+WHERE token = $1;
+
+-- name: MarkPasswordResetUsed :execrows
+UPDATE password_resets
+SET used_at = $1
+WHERE id = $2;
+
+-- name: DeleteExpiredPasswordResets :exec
+DELETE FROM password_resets
+WHERE expires_at < $1
+OR used_at IS NOT NULL;
+
+-- name: CreateEmailChange :exec
+INSERT INTO email_changes (id, user_id, new_email, token, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetEmailChangeByToken :one
+SELECT id, user_id, new_email, token, expires_at, created_at, confirmed_at
+FROM email_changes
+WHERE token = $1;
+
+-- name: MarkEmailChangeConfirmed :execrows
+UPDATE email_changes
+SET confirmed_at = $1
+WHERE id = $2;
+
+-- name: DeleteExpiredEmailChanges :exec
+DELETE FROM email_changes
+WHERE expires_at < $1
+OR confirmed_at IS NOT NULL;
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresResumeActivityRepository implements the ResumeActivityRepository interface using PostgreSQL
+type PostgresResumeActivityRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresResumeActivityRepository creates a new PostgreSQL resume activity repository
+func NewPostgresResumeActivityRepository(db *sqlx.DB) *PostgresResumeActivityRepository {
+	return &PostgresResumeActivityRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// AddActivity records an activity of a resume
+func (r *PostgresResumeActivityRepository) AddActivity(ctx context.Context, activity *domain.ResumeActivity) error {
+	// Set default values if not provided
+	if activity.ID == uuid.Nil {
+		activity.ID = uuid.New()
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now().UTC()
+	}
+	if activity.Details == nil {
+		activity.Details = []byte("{}")
+	}
+
+	err := queriesFor(ctx, r.queries).AddResumeActivity(ctx, dbgen.AddResumeActivityParams{
+		ID:        activity.ID,
+		ResumeID:  activity.ResumeID,
+		Kind:      activity.Kind,
+		ActorID:   uuid.NullUUID{UUID: activity.ActorID, Valid: activity.ActorID != uuid.Nil},
+		Details:   activity.Details,
+		CreatedAt: activity.CreatedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", activity.ResumeID.String()).Str("kind", activity.Kind).Msg("Failed to add resume activity")
+		return err
+	}
+
+	return nil
+}
+
+// GetActivityBefore returns up to limit activities older than the one created
+// at before with ID beforeID, newest first; a zero before starts from the newest
+func (r *PostgresResumeActivityRepository) GetActivityBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*domain.ResumeActivity, error) {
+	rows, err := queriesFor(ctx, r.queries).GetResumeActivityBefore(ctx, dbgen.GetResumeActivityBeforeParams{
+		ResumeID: resumeID,
+		Before:   sql.NullTime{Time: before.UTC(), Valid: !before.IsZero()},
+		BeforeID: beforeID,
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get resume activity")
+		return nil, err
+	}
+
+	activities := make([]*domain.ResumeActivity, len(rows))
+	for i, row := range rows {
+		activities[i] = activityFromRow(row)
+	}
+	return activities, nil
+}
+
+// DeleteActivity deletes the activity of a resume
+func (r *PostgresResumeActivityRepository) DeleteActivity(ctx context.Context, resumeID uuid.UUID) error {
+	if err := queriesFor(ctx, r.queries).DeleteResumeActivity(ctx, resumeID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to delete resume activity")
+		return err
+	}
+
+	return nil
+}
+
+// activityFromRow converts a resume_activity row
+func activityFromRow(row dbgen.ResumeActivity) *domain.ResumeActivity {
+	return &domain.ResumeActivity{
+		ID:        row.ID,
+		ResumeID:  row.ResumeID,
+		Kind:      row.Kind,
+		ActorID:   row.ActorID.UUID,
+		Details:   row.Details,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,1093 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// resumeDocumentSchemaVersion is the current version of the stored document layout
+const resumeDocumentSchemaVersion = 1
+
+// Document section keys, also used for containment lookups
+const (
+	sectionEducation      = "education"
+	sectionExperience     = "experience"
+	sectionSkills         = "skills"
+	sectionProjects       = "projects"
+	sectionCertifications = "certifications"
+)
+
+// ErrInvalidDocument is returned when a stored or updated resume document fails validation
+var ErrInvalidDocument = errors.New("invalid resume document")
+
+// resumeDocument is the JSONB representation of a resume with all of its sections
+type resumeDocument struct {
+	SchemaVersion  int                     `json:"schema_version"`
+	PersonalInfo   *domain.PersonalInfo    `json:"personal_info,omitempty"`
+	Education      []documentEducation     `json:"education"`
+	Experience     []documentExperience    `json:"experience"`
+	Skills         []documentSkill         `json:"skills"`
+	Projects       []documentProject       `json:"projects"`
+	Certifications []documentCertification `json:"certifications"`
+}
+
+// Section entries carry their own ID so they can be addressed like table rows
+type (
+	documentEducation struct {
+		ID uuid.UUID `json:"id"`
+		domain.Education
+	}
+	documentExperience struct {
+		ID uuid.UUID `json:"id"`
+		domain.Experience
+	}
+	documentSkill struct {
+		ID uuid.UUID `json:"id"`
+		domain.Skill
+	}
+	documentProject struct {
+		ID uuid.UUID `json:"id"`
+		domain.Project
+	}
+	documentCertification struct {
+		ID uuid.UUID `json:"id"`
+		domain.Certification
+	}
+)
+
+// validate checks the document against the schema before it is written
+func (d *resumeDocument) validate() error {
+	if d.SchemaVersion != resumeDocumentSchemaVersion {
+		return fmt.Errorf("%w: unsupported schema version %d", ErrInvalidDocument, d.SchemaVersion)
+	}
+
+	if d.PersonalInfo != nil {
+		if err := d.PersonalInfo.Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range d.Education {
+		if err := d.Education[i].Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range d.Experience {
+		if err := d.Experience[i].Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range d.Skills {
+		if err := d.Skills[i].Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range d.Projects {
+		if err := d.Projects[i].Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range d.Certifications {
+		if err := d.Certifications[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PostgresResumeDocumentRepository implements the ResumeRepository interface by
+// storing each resume as a single JSONB document
+type PostgresResumeDocumentRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresResumeDocumentRepository creates a new PostgreSQL document-mode resume repository
+func NewPostgresResumeDocumentRepository(db *sqlx.DB) *PostgresResumeDocumentRepository {
+	return &PostgresResumeDocumentRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreateResume creates a new resume
+func (r *PostgresResumeDocumentRepository) CreateResume(ctx context.Context, userID uuid.UUID, metadata *domain.ResumeMetadata) (*domain.Resume, error) {
+	// Apply BeforeSave to sanitize the data
+	metadata.BeforeSave()
+
+	// Validate the metadata
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+
+	resumeID := uuid.New()
+	now := time.Now().UTC()
+
+	document, err := json.Marshal(&resumeDocument{SchemaVersion: resumeDocumentSchemaVersion})
+	if err != nil {
+		return nil, err
+	}
+	settings, err := json.Marshal(&metadata.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = queriesFor(ctx, r.queries).CreateResumeDocument(ctx, dbgen.CreateResumeDocumentParams{
+		ID:             resumeID,
+		UserID:         userID,
+		Title:          metadata.Title,
+		TargetJobTitle: metadata.TargetJobTitle,
+		Tags:           metadata.Tags,
+		Language:       metadata.Language,
+		TranslationOf:  nullUUID(metadata.TranslationOf),
+		Settings:       settings,
+		Document:       document,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create resume document")
+		return nil, err
+	}
+
+	resume := &domain.Resume{
+		ID:             resumeID,
+		UserID:         userID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ResumeMetadata: *metadata,
+	}
+
+	return resume, nil
+}
+
+// GetResumeByID retrieves a resume by ID
+func (r *PostgresResumeDocumentRepository) GetResumeByID(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	row, err := queriesFor(ctx, r.queries).GetResumeDocumentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", id.String()).Msg("Failed to get resume document by ID")
+		return nil, err
+	}
+
+	return &domain.Resume{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		ResumeMetadata: domain.ResumeMetadata{
+			Title:          row.Title,
+			TargetJobTitle: row.TargetJobTitle,
+			Tags:           row.Tags,
+			Language:       row.Language,
+			TranslationOf:  uuidPtr(row.TranslationOf),
+			Settings:       resumeSettings(row.Settings),
+		},
+	}, nil
+}
+
+// GetResumesByUserID retrieves all resumes for a user
+func (r *PostgresResumeDocumentRepository) GetResumesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Resume, error) {
+	rows, err := queriesFor(ctx, r.queries).GetResumeDocumentsByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get resume documents by user ID")
+		return nil, err
+	}
+
+	resumes := make([]*domain.Resume, len(rows))
+	for i, row := range rows {
+		resumes[i] = &domain.Resume{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			ResumeMetadata: domain.ResumeMetadata{
+				Title:          row.Title,
+				TargetJobTitle: row.TargetJobTitle,
+				Tags:           row.Tags,
+				Language:       row.Language,
+				TranslationOf:  uuidPtr(row.TranslationOf),
+				Settings:       resumeSettings(row.Settings),
+			},
+		}
+	}
+
+	return resumes, nil
+}
+
+// ListResumesByUserID retrieves a page of a user's resumes and the total matching the filters
+func (r *PostgresResumeDocumentRepository) ListResumesByUserID(ctx context.Context, userID uuid.UUID, opts domain.ListOptions) ([]*domain.Resume, int64, error) {
+	total, err := queriesFor(ctx, r.queries).CountResumeDocumentsByUserID(ctx, dbgen.CountResumeDocumentsByUserIDParams{
+		UserID:        userID,
+		CreatedAfter:  nullTime(opts.CreatedAfter),
+		CreatedBefore: nullTime(opts.CreatedBefore),
+		Search:        searchPattern(opts.Search),
+		Tag:           domain.NormalizeTag(opts.Tag),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to count resume documents by user ID")
+		return nil, 0, err
+	}
+
+	rows, err := queriesFor(ctx, r.queries).ListResumeDocumentsByUserID(ctx, dbgen.ListResumeDocumentsByUserIDParams{
+		UserID:        userID,
+		CreatedAfter:  nullTime(opts.CreatedAfter),
+		CreatedBefore: nullTime(opts.CreatedBefore),
+		Search:        searchPattern(opts.Search),
+		Tag:           domain.NormalizeTag(opts.Tag),
+		SortBy:        opts.SortBy,
+		SortDesc:      opts.SortDesc,
+		RowOffset:     int32(opts.Offset),
+		RowLimit:      int32(opts.Limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to list resume documents by user ID")
+		return nil, 0, err
+	}
+
+	resumes := make([]*domain.Resume, len(rows))
+	for i, row := range rows {
+		resumes[i] = &domain.Resume{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			ResumeMetadata: domain.ResumeMetadata{
+				Title:          row.Title,
+				TargetJobTitle: row.TargetJobTitle,
+				Tags:           row.Tags,
+				Language:       row.Language,
+				TranslationOf:  uuidPtr(row.TranslationOf),
+				Settings:       resumeSettings(row.Settings),
+			},
+		}
+	}
+
+	return resumes, total, nil
+}
+
+// UpdateResumeMetadata replaces a resume's title, target job title, tags and language
+func (r *PostgresResumeDocumentRepository) UpdateResumeMetadata(ctx context.Context, id uuid.UUID, metadata *domain.ResumeMetadata) error {
+	// Apply BeforeSave to sanitize the data
+	metadata.BeforeSave()
+
+	// Validate the metadata
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+
+	settings, err := json.Marshal(&metadata.Settings)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateResumeDocumentMetadata(ctx, dbgen.UpdateResumeDocumentMetadataParams{
+		Title:          metadata.Title,
+		TargetJobTitle: metadata.TargetJobTitle,
+		Tags:           metadata.Tags,
+		Language:       metadata.Language,
+		Settings:       settings,
+		UpdatedAt:      time.Now().UTC(),
+		ID:             id,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", id.String()).Msg("Failed to update resume document metadata")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteResume deletes a resume
+func (r *PostgresResumeDocumentRepository) DeleteResume(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteResumeDocument(ctx, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", id.String()).Msg("Failed to delete resume document")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SavePersonalInfo saves personal info for a resume
+func (r *PostgresResumeDocumentRepository) SavePersonalInfo(ctx context.Context, resumeID uuid.UUID, info *domain.PersonalInfo) error {
+	// Apply BeforeSave to sanitize the data
+	info.BeforeSave()
+
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		// The photo is only changed by SetPhotoURL
+		info.PhotoURL = ""
+		if doc.PersonalInfo != nil {
+			info.PhotoURL = doc.PersonalInfo.PhotoURL
+		}
+		doc.PersonalInfo = info
+		return nil
+	})
+}
+
+// SetPhotoURL sets the photo of a resume's personal info
+func (r *PostgresResumeDocumentRepository) SetPhotoURL(ctx context.Context, resumeID uuid.UUID, photoURL string) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		if doc.PersonalInfo == nil {
+			return ErrNotFound
+		}
+		doc.PersonalInfo.PhotoURL = photoURL
+		return nil
+	})
+}
+
+// GetPersonalInfo retrieves personal info for a resume
+func (r *PostgresResumeDocumentRepository) GetPersonalInfo(ctx context.Context, resumeID uuid.UUID) (*domain.PersonalInfo, error) {
+	doc, err := r.load(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.PersonalInfo == nil {
+		return nil, ErrNotFound
+	}
+
+	return doc.PersonalInfo, nil
+}
+
+// AddEducation adds an education entry to a resume
+func (r *PostgresResumeDocumentRepository) AddEducation(ctx context.Context, resumeID uuid.UUID, education *domain.Education) (uuid.UUID, error) {
+	// Apply BeforeSave to sanitize the data
+	education.BeforeSave()
+
+	// Validate the entry
+	if err := education.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	err := r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		doc.Education = append(doc.Education, documentEducation{ID: id, Education: *education})
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// UpdateEducation updates an education entry
+func (r *PostgresResumeDocumentRepository) UpdateEducation(ctx context.Context, resumeID, id uuid.UUID, education *domain.Education) error {
+	// Apply BeforeSave to sanitize the data
+	education.BeforeSave()
+
+	// Validate the entry
+	if err := education.Validate(); err != nil {
+		return err
+	}
+
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Education {
+			if doc.Education[i].ID == id {
+				doc.Education[i].Education = *education
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// DeleteEducation deletes an education entry
+func (r *PostgresResumeDocumentRepository) DeleteEducation(ctx context.Context, resumeID, id uuid.UUID) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Education {
+			if doc.Education[i].ID == id {
+				doc.Education = append(doc.Education[:i], doc.Education[i+1:]...)
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// GetEducation retrieves an education entry by ID
+func (r *PostgresResumeDocumentRepository) GetEducation(ctx context.Context, id uuid.UUID) (*domain.Education, error) {
+	doc, err := r.loadByEntry(ctx, sectionEducation, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range doc.Education {
+		if doc.Education[i].ID == id {
+			return &doc.Education[i].Education, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// GetEducationByResume retrieves all education entries for a resume
+func (r *PostgresResumeDocumentRepository) GetEducationByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Education, error) {
+	doc, err := r.load(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.educationList(), nil
+}
+
+// AddExperience adds an experience entry to a resume
+func (r *PostgresResumeDocumentRepository) AddExperience(ctx context.Context, resumeID uuid.UUID, experience *domain.Experience) (uuid.UUID, error) {
+	// Apply BeforeSave to sanitize the data
+	experience.BeforeSave()
+
+	// Validate the entry
+	if err := experience.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	err := r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		doc.Experience = append(doc.Experience, documentExperience{ID: id, Experience: *experience})
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// UpdateExperience updates an experience entry
+func (r *PostgresResumeDocumentRepository) UpdateExperience(ctx context.Context, resumeID, id uuid.UUID, experience *domain.Experience) error {
+	// Apply BeforeSave to sanitize the data
+	experience.BeforeSave()
+
+	// Validate the entry
+	if err := experience.Validate(); err != nil {
+		return err
+	}
+
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Experience {
+			if doc.Experience[i].ID == id {
+				doc.Experience[i].Experience = *experience
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// DeleteExperience deletes an experience entry
+func (r *PostgresResumeDocumentRepository) DeleteExperience(ctx context.Context, resumeID, id uuid.UUID) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Experience {
+			if doc.Experience[i].ID == id {
+				doc.Experience = append(doc.Experience[:i], doc.Experience[i+1:]...)
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// GetExperience retrieves an experience entry of a resume by ID
+func (r *PostgresResumeDocumentRepository) GetExperience(ctx context.Context, resumeID, id uuid.UUID) (*domain.Experience, error) {
+	doc, err := r.load(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range doc.Experience {
+		if doc.Experience[i].ID == id {
+			return &doc.Experience[i].Experience, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// GetExperienceByResume retrieves all experience entries for a resume
+func (r *PostgresResumeDocumentRepository) GetExperienceByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Experience, error) {
+	doc, err := r.load(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.experienceList(), nil
+}
+
+// AddSkill adds a skill entry to a resume
+func (r *PostgresResumeDocumentRepository) AddSkill(ctx context.Context, resumeID uuid.UUID, skill *domain.Skill) (uuid.UUID, error) {
+	// Apply BeforeSave to sanitize the data
+	skill.BeforeSave()
+
+	// Validate the entry
+	if err := skill.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	err := r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		doc.Skills = append(doc.Skills, documentSkill{ID: id, Skill: *skill})
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// AddSkills adds several skill entries to a resume in a single document update
+func (r *PostgresResumeDocumentRepository) AddSkills(ctx context.Context, resumeID uuid.UUID, skills []*domain.Skill) ([]uuid.UUID, error) {
+	for _, skill := range skills {
+		// Apply BeforeSave to sanitize the data
+		skill.BeforeSave()
+
+		// Validate the entry
+		if err := skill.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]uuid.UUID, len(skills))
+	err := r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i, skill := range skills {
+			ids[i] = uuid.New()
+			doc.Skills = append(doc.Skills, documentSkill{ID: ids[i], Skill: *skill})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// UpdateSkill updates a skill entry
+func (r *PostgresResumeDocumentRepository) UpdateSkill(ctx context.Context, resumeID, id uuid.UUID, skill *domain.Skill) error {
+	// Apply BeforeSave to sanitize the data
+	skill.BeforeSave()
+
+	// Validate the entry
+	if err := skill.Validate(); err != nil {
+		return err
+	}
+
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Skills {
+			if doc.Skills[i].ID == id {
+				doc.Skills[i].Skill = *skill
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// DeleteSkill deletes a skill entry
+func (r *PostgresResumeDocumentRepository) DeleteSkill(ctx context.Context, resumeID, id uuid.UUID) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Skills {
+			if doc.Skills[i].ID == id {
+				doc.Skills = append(doc.Skills[:i], doc.Skills[i+1:]...)
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// GetSkill retrieves a skill entry by ID
+func (r *PostgresResumeDocumentRepository) GetSkill(ctx context.Context, id uuid.UUID) (*domain.Skill, error) {
+	doc, err := r.loadByEntry(ctx, sectionSkills, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range doc.Skills {
+		if doc.Skills[i].ID == id {
+			return &doc.Skills[i].Skill, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// GetSkillsByResume retrieves all skill entries for a resume
+func (r *PostgresResumeDocumentRepository) GetSkillsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Skill, error) {
+	doc, err := r.load(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.skillList(), nil
+}
+
+// AddProject adds a project entry to a resume
+func (r *PostgresResumeDocumentRepository) AddProject(ctx context.Context, resumeID uuid.UUID, project *domain.Project) (uuid.UUID, error) {
+	// Apply BeforeSave to sanitize the data
+	project.BeforeSave()
+
+	// Validate the entry
+	if err := project.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	err := r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		doc.Projects = append(doc.Projects, documentProject{ID: id, Project: *project})
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// UpdateProject updates a project entry
+func (r *PostgresResumeDocumentRepository) UpdateProject(ctx context.Context, resumeID, id uuid.UUID, project *domain.Project) error {
+	// Apply BeforeSave to sanitize the data
+	project.BeforeSave()
+
+	// Validate the entry
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Projects {
+			if doc.Projects[i].ID == id {
+				doc.Projects[i].Project = *project
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// DeleteProject deletes a project entry
+func (r *PostgresResumeDocumentRepository) DeleteProject(ctx context.Context, resumeID, id uuid.UUID) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Projects {
+			if doc.Projects[i].ID == id {
+				doc.Projects = append(doc.Projects[:i], doc.Projects[i+1:]...)
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// GetProject retrieves a project entry by ID
+func (r *PostgresResumeDocumentRepository) GetProject(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
+	doc, err := r.loadByEntry(ctx, sectionProjects, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range doc.Projects {
+		if doc.Projects[i].ID == id {
+			return &doc.Projects[i].Project, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// GetProjectsByResume retrieves all project entries for a resume
+func (r *PostgresResumeDocumentRepository) GetProjectsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Project, error) {
+	doc, err := r.load(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.projectList(), nil
+}
+
+// AddProjectTechnology adds a technology to a project
+func (r *PostgresResumeDocumentRepository) AddProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Projects {
+			if doc.Projects[i].ID == projectID {
+				doc.Projects[i].Technologies = append(doc.Projects[i].Technologies, technology)
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// DeleteProjectTechnology deletes a technology from a project
+func (r *PostgresResumeDocumentRepository) DeleteProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Projects {
+			if doc.Projects[i].ID != projectID {
+				continue
+			}
+
+			technologies := doc.Projects[i].Technologies[:0]
+			for _, tech := range doc.Projects[i].Technologies {
+				if tech != technology {
+					technologies = append(technologies, tech)
+				}
+			}
+			if len(technologies) == len(doc.Projects[i].Technologies) {
+				return ErrNotFound
+			}
+			doc.Projects[i].Technologies = technologies
+			return nil
+		}
+		return ErrNotFound
+	})
+}
+
+// GetProjectTechnologies retrieves all technologies for a project
+func (r *PostgresResumeDocumentRepository) GetProjectTechnologies(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	project, err := r.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	technologies := append([]string{}, project.Technologies...)
+	sort.Strings(technologies)
+
+	return technologies, nil
+}
+
+// AddCertification adds a certification entry to a resume
+func (r *PostgresResumeDocumentRepository) AddCertification(ctx context.Context, resumeID uuid.UUID, certification *domain.Certification) (uuid.UUID, error) {
+	// Apply BeforeSave to sanitize the data
+	certification.BeforeSave()
+
+	// Validate the entry
+	if err := certification.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	err := r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		doc.Certifications = append(doc.Certifications, documentCertification{ID: id, Certification: *certification})
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// UpdateCertification updates a certification entry
+func (r *PostgresResumeDocumentRepository) UpdateCertification(ctx context.Context, resumeID, id uuid.UUID, certification *domain.Certification) error {
+	// Apply BeforeSave to sanitize the data
+	certification.BeforeSave()
+
+	// Validate the entry
+	if err := certification.Validate(); err != nil {
+		return err
+	}
+
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Certifications {
+			if doc.Certifications[i].ID == id {
+				doc.Certifications[i].Certification = *certification
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// DeleteCertification deletes a certification entry
+func (r *PostgresResumeDocumentRepository) DeleteCertification(ctx context.Context, resumeID, id uuid.UUID) error {
+	return r.update(ctx, resumeID, func(doc *resumeDocument) error {
+		for i := range doc.Certifications {
+			if doc.Certifications[i].ID == id {
+				doc.Certifications = append(doc.Certifications[:i], doc.Certifications[i+1:]...)
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+// GetCertification retrieves a certification entry by ID
+func (r *PostgresResumeDocumentRepository) GetCertification(ctx context.Context, id uuid.UUID) (*domain.Certification, error) {
+	doc, err := r.loadByEntry(ctx, sectionCertifications, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range doc.Certifications {
+		if doc.Certifications[i].ID == id {
+			return &doc.Certifications[i].Certification, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// GetCertificationsByResume retrieves all certification entries for a resume
+func (r *PostgresResumeDocumentRepository) GetCertificationsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Certification, error) {
+	doc, err := r.load(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.certificationList(), nil
+}
+
+// GetCompleteResume retrieves a resume with all its sections
+func (r *PostgresResumeDocumentRepository) GetCompleteResume(ctx context.Context, resumeID uuid.UUID) (*domain.Resume, error) {
+	row, err := queriesFor(ctx, r.queries).GetResumeDocument(ctx, resumeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get resume document")
+		return nil, err
+	}
+
+	doc, err := decodeResumeDocument(row.Document)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to decode resume document")
+		return nil, err
+	}
+
+	return &domain.Resume{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		ResumeMetadata: domain.ResumeMetadata{
+			Title:          row.Title,
+			TargetJobTitle: row.TargetJobTitle,
+			Tags:           row.Tags,
+			Language:       row.Language,
+			TranslationOf:  uuidPtr(row.TranslationOf),
+			Settings:       resumeSettings(row.Settings),
+		},
+		PersonalInfo:   doc.PersonalInfo,
+		Education:      doc.educationList(),
+		Experience:     doc.experienceList(),
+		Skills:         doc.skillList(),
+		Projects:       doc.projectList(),
+		Certifications: doc.certificationList(),
+	}, nil
+}
+
+// load reads and decodes the document of a resume
+func (r *PostgresResumeDocumentRepository) load(ctx context.Context, resumeID uuid.UUID) (*resumeDocument, error) {
+	row, err := queriesFor(ctx, r.queries).GetResumeDocument(ctx, resumeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get resume document")
+		return nil, err
+	}
+
+	doc, err := decodeResumeDocument(row.Document)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to decode resume document")
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// loadByEntry reads the document containing the given section entry
+func (r *PostgresResumeDocumentRepository) loadByEntry(ctx context.Context, section string, entryID uuid.UUID) (*resumeDocument, error) {
+	resumeID, err := r.findResumeByEntry(ctx, section, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.load(ctx, resumeID)
+}
+
+// findResumeByEntry locates the resume holding a section entry using the GIN containment index
+func (r *PostgresResumeDocumentRepository) findResumeByEntry(ctx context.Context, section string, entryID uuid.UUID) (uuid.UUID, error) {
+	content, err := json.Marshal(map[string][]map[string]uuid.UUID{
+		section: {{"id": entryID}},
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	resumeID, err := queriesFor(ctx, r.queries).FindResumeDocumentIDByContent(ctx, content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("section", section).Str("entry_id", entryID.String()).Msg("Failed to find resume document by entry")
+		return uuid.Nil, err
+	}
+
+	return resumeID, nil
+}
+
+// update applies a change to a resume document inside a transaction, locking the row
+// so concurrent edits to the same resume are serialized
+func (r *PostgresResumeDocumentRepository) update(ctx context.Context, resumeID uuid.UUID, apply func(doc *resumeDocument) error) (err error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	qtx := r.queries.WithTx(tx.Tx)
+
+	row, err := qtx.GetResumeDocumentForUpdate(ctx, resumeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to lock resume document")
+		return err
+	}
+
+	doc, err := decodeResumeDocument(row.Document)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to decode resume document")
+		return err
+	}
+
+	if err = apply(doc); err != nil {
+		return err
+	}
+	if err = doc.validate(); err != nil {
+		return err
+	}
+
+	document, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = qtx.UpdateResumeDocument(ctx, dbgen.UpdateResumeDocumentParams{
+		Document:  document,
+		UpdatedAt: time.Now().UTC(),
+		ID:        resumeID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to update resume document")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// decodeResumeDocument parses a stored document
+func decodeResumeDocument(data []byte) (*resumeDocument, error) {
+	var doc resumeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if doc.SchemaVersion != resumeDocumentSchemaVersion {
+		return nil, fmt.Errorf("%w: unsupported schema version %d", ErrInvalidDocument, doc.SchemaVersion)
+	}
+
+	return &doc, nil
+}
+
+// Section accessors return entries in the same order as the relational repository
+
+// educationList returns education entries, most recent first
+func (d *resumeDocument) educationList() []*domain.Education {
+	education := make([]*domain.Education, len(d.Education))
+	for i := range d.Education {
+		education[i] = &d.Education[i].Education
+	}
+	sort.SliceStable(education, func(i, j int) bool {
+		return education[i].StartDate > education[j].StartDate
+	})
+	return education
+}
+
+// experienceList returns experience entries, most recent first
+func (d *resumeDocument) experienceList() []*domain.Experience {
+	experience := make([]*domain.Experience, len(d.Experience))
+	for i := range d.Experience {
+		experience[i] = &d.Experience[i].Experience
+	}
+	sort.SliceStable(experience, func(i, j int) bool {
+		return experience[i].StartDate > experience[j].StartDate
+	})
+	return experience
+}
+
+// skillList returns skills ordered by category and name
+func (d *resumeDocument) skillList() []*domain.Skill {
+	skills := make([]*domain.Skill, len(d.Skills))
+	for i := range d.Skills {
+		skills[i] = &d.Skills[i].Skill
+	}
+	sort.SliceStable(skills, func(i, j int) bool {
+		if skills[i].Category != skills[j].Category {
+			return skills[i].Category < skills[j].Category
+		}
+		return skills[i].Name < skills[j].Name
+	})
+	return skills
+}
+
+// projectList returns projects, undated first and then most recent first
+func (d *resumeDocument) projectList() []*domain.Project {
+	projects := make([]*domain.Project, len(d.Projects))
+	for i := range d.Projects {
+		projects[i] = &d.Projects[i].Project
+	}
+	sortKey := func(p *domain.Project) string {
+		if p.StartDate == "" || p.StartDate == datePresent {
+			return "9999-12-31"
+		}
+		return p.StartDate
+	}
+	sort.SliceStable(projects, func(i, j int) bool {
+		return sortKey(projects[i]) > sortKey(projects[j])
+	})
+	return projects
+}
+
+// certificationList returns certifications, most recently issued first
+func (d *resumeDocument) certificationList() []*domain.Certification {
+	certifications := make([]*domain.Certification, len(d.Certifications))
+	for i := range d.Certifications {
+		certifications[i] = &d.Certifications[i].Certification
+	}
+	sort.SliceStable(certifications, func(i, j int) bool {
+		return certifications[i].IssueDate > certifications[j].IssueDate
+	})
+	return certifications
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// appendEventAttempts bounds retries when concurrent writers race for the same version
+const appendEventAttempts = 3
+
+// PostgresResumeEventRepository implements the ResumeEventRepository interface using PostgreSQL
+type PostgresResumeEventRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresResumeEventRepository creates a new PostgreSQL resume event repository
+func NewPostgresResumeEventRepository(db *sqlx.DB) *PostgresResumeEventRepository {
+	return &PostgresResumeEventRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// AppendEvent stores an event, assigning it the next version for its resume
+func (r *PostgresResumeEventRepository) AppendEvent(ctx context.Context, event *domain.ResumeEvent) error {
+	// Set default values if not provided
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	if event.Payload == nil {
+		event.Payload = []byte("null")
+	}
+
+	params := dbgen.AppendResumeEventParams{
+		ID:        event.ID,
+		ResumeID:  event.ResumeID,
+		Entity:    event.Entity,
+		EntityID:  uuid.NullUUID{UUID: event.EntityID, Valid: event.EntityID != uuid.Nil},
+		Op:        event.Op,
+		Payload:   event.Payload,
+		ActorID:   uuid.NullUUID{UUID: event.ActorID, Valid: event.ActorID != uuid.Nil},
+		CreatedAt: event.CreatedAt,
+	}
+
+	var err error
+	for attempt := 0; attempt < appendEventAttempts; attempt++ {
+		var version int64
+		version, err = r.queries.AppendResumeEvent(ctx, params)
+		if err == nil {
+			event.Version = version
+			return nil
+		}
+		// Another writer took this version; read the new maximum and try again
+		if !isDuplicateKeyError(err) {
+			break
+		}
+	}
+
+	log.Ctx(ctx).Error().Err(err).Str("resume_id", event.ResumeID.String()).Msg("Failed to append resume event")
+	return err
+}
+
+// GetEventsAfter returns up to limit events with a version greater than afterVersion
+func (r *PostgresResumeEventRepository) GetEventsAfter(ctx context.Context, resumeID uuid.UUID, afterVersion int64, limit int) ([]*domain.ResumeEvent, error) {
+	rows, err := r.queries.GetResumeEvents(ctx, dbgen.GetResumeEventsParams{
+		ResumeID: resumeID,
+		Version:  afterVersion,
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get resume events")
+		return nil, err
+	}
+
+	return resumeEventsFromRows(rows), nil
+}
+
+// GetEventsBefore returns up to limit events older than the one created at
+// before with ID beforeID, newest first; a zero before starts from the newest
+func (r *PostgresResumeEventRepository) GetEventsBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*domain.ResumeEvent, error) {
+	rows, err := queriesFor(ctx, r.queries).GetResumeEventsBefore(ctx, dbgen.GetResumeEventsBeforeParams{
+		ResumeID: resumeID,
+		Before:   sql.NullTime{Time: before.UTC(), Valid: !before.IsZero()},
+		BeforeID: beforeID,
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get resume events")
+		return nil, err
+	}
+
+	return resumeEventsFromRows(rows), nil
+}
+
+// GetEventsUpTo returns all events up to and including version
+func (r *PostgresResumeEventRepository) GetEventsUpTo(ctx context.Context, resumeID uuid.UUID, version int64) ([]*domain.ResumeEvent, error) {
+	rows, err := r.queries.GetResumeEventsUpTo(ctx, dbgen.GetResumeEventsUpToParams{
+		ResumeID: resumeID,
+		Version:  version,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get resume events")
+		return nil, err
+	}
+
+	return resumeEventsFromRows(rows), nil
+}
+
+// CountEventsSince returns the number of events recorded for each resume since the given time
+func (r *PostgresResumeEventRepository) CountEventsSince(ctx context.Context, resumeIDs []uuid.UUID, since time.Time) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64)
+	if len(resumeIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := r.queries.CountResumeEventsSince(ctx, dbgen.CountResumeEventsSinceParams{
+		ResumeIds: resumeIDs,
+		Since:     since.UTC(),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count resume events")
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ResumeID] = row.Changes
+	}
+
+	return counts, nil
+}
+
+// GetOwnerEventsAfter returns up to limit events of the owner's resumes after
+// afterSeq recorded in (since, until], in the order they were recorded
+func (r *PostgresResumeEventRepository) GetOwnerEventsAfter(ctx context.Context, ownerID uuid.UUID, afterSeq int64, since, until time.Time, limit int) ([]*domain.ResumeEvent, error) {
+	rows, err := r.queries.GetOwnerResumeEventsAfter(ctx, dbgen.GetOwnerResumeEventsAfterParams{
+		OwnerID:  uuid.NullUUID{UUID: ownerID, Valid: true},
+		AfterSeq: afterSeq,
+		Since:    since.UTC(),
+		Until:    until.UTC(),
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("owner_id", ownerID.String()).Msg("Failed to get resume events")
+		return nil, err
+	}
+
+	return resumeEventsFromRows(rows), nil
+}
+
+// GetOwnerChangedResumes returns up to limit of the owner's resumes with events
+// after afterSeq recorded in (since, until], ordered by their latest one
+func (r *PostgresResumeEventRepository) GetOwnerChangedResumes(ctx context.Context, ownerID uuid.UUID, afterSeq int64, since, until time.Time, limit int) ([]*domain.ResumeChange, error) {
+	rows, err := r.queries.GetOwnerChangedResumes(ctx, dbgen.GetOwnerChangedResumesParams{
+		OwnerID:  uuid.NullUUID{UUID: ownerID, Valid: true},
+		AfterSeq: afterSeq,
+		Since:    since.UTC(),
+		Until:    until.UTC(),
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("owner_id", ownerID.String()).Msg("Failed to get changed resumes")
+		return nil, err
+	}
+
+	changes := make([]*domain.ResumeChange, len(rows))
+	for i, row := range rows {
+		changes[i] = &domain.ResumeChange{ResumeID: row.ResumeID, Seq: row.Seq, ChangedAt: row.ChangedAt}
+	}
+	return changes, nil
+}
+
+// resumeEventsFromRows converts generated event rows to domain models
+func resumeEventsFromRows(rows []dbgen.ResumeEvent) []*domain.ResumeEvent {
+	events := make([]*domain.ResumeEvent, len(rows))
+	for i, row := range rows {
+		events[i] = &domain.ResumeEvent{
+			ID:        row.ID,
+			ResumeID:  row.ResumeID,
+			Version:   row.Version,
+			Entity:    row.Entity,
+			EntityID:  row.EntityID.UUID,
+			Op:        row.Op,
+			Payload:   row.Payload,
+			ActorID:   row.ActorID.UUID,
+			CreatedAt: row.CreatedAt,
+			Seq:       row.Seq,
+		}
+	}
+	return events
+}
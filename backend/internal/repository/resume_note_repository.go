@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresResumeNoteRepository implements the ResumeNoteRepository interface using PostgreSQL
+type PostgresResumeNoteRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresResumeNoteRepository creates a new PostgreSQL private note repository
+func NewPostgresResumeNoteRepository(db *sqlx.DB) *PostgresResumeNoteRepository {
+	return &PostgresResumeNoteRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreateNote stores a new version of a note
+func (r *PostgresResumeNoteRepository) CreateNote(ctx context.Context, note *domain.ResumeNote) error {
+	// Set default values if not provided
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = time.Now().UTC()
+	}
+	if note.Nonce == nil {
+		note.Nonce = []byte{}
+	}
+
+	err := r.queries.CreateResumeNote(ctx, dbgen.CreateResumeNoteParams{
+		ResumeID:   note.ResumeID,
+		Version:    int32(note.Version),
+		UserID:     note.UserID,
+		Algorithm:  note.Algorithm,
+		KeyID:      note.KeyID,
+		Nonce:      note.Nonce,
+		Ciphertext: note.Ciphertext,
+		CreatedAt:  note.CreatedAt,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", note.ResumeID.String()).Msg("Failed to create note")
+		return err
+	}
+
+	return nil
+}
+
+// GetLatestNote retrieves the latest version of a resume's note
+func (r *PostgresResumeNoteRepository) GetLatestNote(ctx context.Context, resumeID uuid.UUID) (*domain.ResumeNote, error) {
+	row, err := r.queries.GetLatestResumeNote(ctx, resumeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get note")
+		return nil, err
+	}
+
+	return noteFromRow(row), nil
+}
+
+// GetNoteVersion retrieves a version of a resume's note
+func (r *PostgresResumeNoteRepository) GetNoteVersion(ctx context.Context, resumeID uuid.UUID, version int) (*domain.ResumeNote, error) {
+	row, err := r.queries.GetResumeNoteVersion(ctx, dbgen.GetResumeNoteVersionParams{
+		ResumeID: resumeID,
+		Version:  int32(version),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Int("version", version).Msg("Failed to get note version")
+		return nil, err
+	}
+
+	return noteFromRow(row), nil
+}
+
+// GetNoteVersions lists the stored versions of a resume's note, newest first
+func (r *PostgresResumeNoteRepository) GetNoteVersions(ctx context.Context, resumeID uuid.UUID) ([]*domain.ResumeNoteVersion, error) {
+	rows, err := r.queries.GetResumeNoteVersions(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get note versions")
+		return nil, err
+	}
+
+	versions := make([]*domain.ResumeNoteVersion, len(rows))
+	for i, row := range rows {
+		versions[i] = &domain.ResumeNoteVersion{
+			Version:   int(row.Version),
+			Algorithm: row.Algorithm,
+			KeyID:     row.KeyID,
+			Size:      int(row.Size),
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	return versions, nil
+}
+
+// DeleteNoteVersionsBefore deletes the versions of a note older than version
+func (r *PostgresResumeNoteRepository) DeleteNoteVersionsBefore(ctx context.Context, resumeID uuid.UUID, version int) error {
+	_, err := r.queries.DeleteResumeNoteVersionsBefore(ctx, dbgen.DeleteResumeNoteVersionsBeforeParams{
+		ResumeID: resumeID,
+		Version:  int32(version),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to delete old note versions")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNotes deletes every version of a resume's note
+func (r *PostgresResumeNoteRepository) DeleteNotes(ctx context.Context, resumeID uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteResumeNotes(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to delete note")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// noteFromRow converts a generated note row to the domain model
+func noteFromRow(row dbgen.ResumeNote) *domain.ResumeNote {
+	return &domain.ResumeNote{
+		ResumeID:   row.ResumeID,
+		Version:    int(row.Version),
+		UserID:     row.UserID,
+		Algorithm:  row.Algorithm,
+		KeyID:      row.KeyID,
+		Nonce:      row.Nonce,
+		Ciphertext: row.Ciphertext,
+		CreatedAt:  row.CreatedAt,
+	}
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresResumePublicationRepository implements the ResumePublicationRepository interface using PostgreSQL
+type PostgresResumePublicationRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresResumePublicationRepository creates a new PostgreSQL published resume repository
+func NewPostgresResumePublicationRepository(db *sqlx.DB) *PostgresResumePublicationRepository {
+	return &PostgresResumePublicationRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// SavePublication publishes a resume, or changes the slug and hidden fields
+// of its page. It returns ErrConflict when another resume is published at the
+// slug.
+func (r *PostgresResumePublicationRepository) SavePublication(ctx context.Context, publication *domain.ResumePublication) error {
+	if publication.Hidden == nil {
+		publication.Hidden = []string{}
+	}
+
+	row, err := queriesFor(ctx, r.queries).UpsertResumePublication(ctx, dbgen.UpsertResumePublicationParams{
+		ResumeID:    publication.ResumeID,
+		UserID:      publication.UserID,
+		Slug:        publication.Slug,
+		Hidden:      publication.Hidden,
+		PublishedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", publication.ResumeID.String()).Msg("Failed to save resume publication")
+		return err
+	}
+
+	*publication = *publicationFromRow(row)
+	return nil
+}
+
+// GetPublication retrieves the publication of a resume
+func (r *PostgresResumePublicationRepository) GetPublication(ctx context.Context, resumeID uuid.UUID) (*domain.ResumePublication, error) {
+	row, err := queriesFor(ctx, r.queries).GetResumePublication(ctx, resumeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get resume publication")
+		return nil, err
+	}
+
+	return publicationFromRow(row), nil
+}
+
+// GetPublicationBySlug retrieves the publication at a slug
+func (r *PostgresResumePublicationRepository) GetPublicationBySlug(ctx context.Context, slug string) (*domain.ResumePublication, error) {
+	row, err := queriesFor(ctx, r.queries).GetResumePublicationBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("slug", slug).Msg("Failed to get resume publication by slug")
+		return nil, err
+	}
+
+	return publicationFromRow(row), nil
+}
+
+// SlugsTaken returns which of the slugs resumes are published at
+func (r *PostgresResumePublicationRepository) SlugsTaken(ctx context.Context, slugs []string) ([]string, error) {
+	taken, err := queriesFor(ctx, r.queries).GetTakenResumePublicationSlugs(ctx, slugs)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to check resume publication slugs")
+		return nil, err
+	}
+
+	return taken, nil
+}
+
+// DeletePublication unpublishes a resume
+func (r *PostgresResumePublicationRepository) DeletePublication(ctx context.Context, resumeID uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteResumePublication(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to delete resume publication")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// publicationFromRow converts a resume_publications row
+func publicationFromRow(row dbgen.ResumePublication) *domain.ResumePublication {
+	return &domain.ResumePublication{
+		ResumeID:    row.ResumeID,
+		UserID:      row.UserID,
+		Slug:        row.Slug,
+		Hidden:      row.Hidden,
+		PublishedAt: row.PublishedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
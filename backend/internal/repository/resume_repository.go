@@ -1,116 +1,184 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/lordaris/resume_generator/pkg/phone"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// Date layout and placeholders used by the domain for open-ended dates
+const (
+	dateLayout       = "2006-01-02"
+	datePresent      = "Present"
+	dateNoExpiration = "No Expiration"
 )
 
 // PostgresResumeRepository implements the ResumeRepository interface
 type PostgresResumeRepository struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	queries *dbgen.Queries
 }
 
 // NewPostgresResumeRepository creates a new PostgreSQL resume repository
 func NewPostgresResumeRepository(db *sqlx.DB) *PostgresResumeRepository {
 	return &PostgresResumeRepository{
-		db: db,
+		db:      db,
+		queries: newQueries(db),
 	}
 }
 
 // CreateResume creates a new resume
-func (r *PostgresResumeRepository) CreateResume(userID uuid.UUID) (*domain.Resume, error) {
-	query := `
-		INSERT INTO resumes (id, user_id, created_at)
-		VALUES ($1, $2, $3)
-		RETURNING id
-	`
+func (r *PostgresResumeRepository) CreateResume(ctx context.Context, userID uuid.UUID, metadata *domain.ResumeMetadata) (*domain.Resume, error) {
+	// Apply BeforeSave to sanitize the data
+	metadata.BeforeSave()
 
-	resumeID := uuid.New()
-	now := time.Now()
+	// Validate the metadata
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+
+	settings, err := json.Marshal(&metadata.Settings)
+	if err != nil {
+		return nil, err
+	}
 
-	var id uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		resumeID,
-		userID,
-		now,
-	).Scan(&id)
+	resumeID := uuid.New()
+	now := time.Now().UTC()
+
+	err = queriesFor(ctx, r.queries).CreateResume(ctx, dbgen.CreateResumeParams{
+		ID:             resumeID,
+		UserID:         userID,
+		Title:          metadata.Title,
+		TargetJobTitle: metadata.TargetJobTitle,
+		Tags:           metadata.Tags,
+		Language:       metadata.Language,
+		TranslationOf:  nullUUID(metadata.TranslationOf),
+		Settings:       settings,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create resume")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create resume")
 		return nil, err
 	}
 
 	resume := &domain.Resume{
-		ID:        resumeID,
-		UserID:    userID,
-		CreatedAt: now,
+		ID:             resumeID,
+		UserID:         userID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ResumeMetadata: *metadata,
 	}
 
 	return resume, nil
 }
 
 // GetResumeByID retrieves a resume by ID
-func (r *PostgresResumeRepository) GetResumeByID(id uuid.UUID) (*domain.Resume, error) {
-	query := `
-		SELECT id, user_id, created_at
-		FROM resumes
-		WHERE id = $1
-	`
-
-	var resume domain.Resume
-	err := r.db.Get(&resume, query, id)
+func (r *PostgresResumeRepository) GetResumeByID(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	row, err := queriesFor(ctx, r.queries).GetResumeByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("resume_id", id.String()).Msg("Failed to get resume by ID")
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", id.String()).Msg("Failed to get resume by ID")
 		return nil, err
 	}
 
-	return &resume, nil
+	return resumeFromRow(row), nil
 }
 
 // GetResumesByUserID retrieves all resumes for a user
-func (r *PostgresResumeRepository) GetResumesByUserID(userID uuid.UUID) ([]*domain.Resume, error) {
-	query := `
-		SELECT id, user_id, created_at
-		FROM resumes
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-	`
-
-	var resumes []*domain.Resume
-	err := r.db.Select(&resumes, query, userID)
-	if err != nil {
-		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get resumes by user ID")
+func (r *PostgresResumeRepository) GetResumesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Resume, error) {
+	rows, err := queriesFor(ctx, r.queries).GetResumesByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get resumes by user ID")
 		return nil, err
 	}
 
+	resumes := make([]*domain.Resume, len(rows))
+	for i, row := range rows {
+		resumes[i] = resumeFromRow(row)
+	}
+
 	return resumes, nil
 }
 
-// DeleteResume deletes a resume
-func (r *PostgresResumeRepository) DeleteResume(id uuid.UUID) error {
-	query := `
-		DELETE FROM resumes
-		WHERE id = $1
-	`
+// ListResumesByUserID retrieves a page of a user's resumes and the total matching the filters
+func (r *PostgresResumeRepository) ListResumesByUserID(ctx context.Context, userID uuid.UUID, opts domain.ListOptions) ([]*domain.Resume, int64, error) {
+	total, err := queriesFor(ctx, r.queries).CountResumesByUserID(ctx, dbgen.CountResumesByUserIDParams{
+		UserID:        userID,
+		CreatedAfter:  nullTime(opts.CreatedAfter),
+		CreatedBefore: nullTime(opts.CreatedBefore),
+		Search:        searchPattern(opts.Search),
+		Tag:           domain.NormalizeTag(opts.Tag),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to count resumes by user ID")
+		return nil, 0, err
+	}
+
+	rows, err := queriesFor(ctx, r.queries).ListResumesByUserID(ctx, dbgen.ListResumesByUserIDParams{
+		UserID:        userID,
+		CreatedAfter:  nullTime(opts.CreatedAfter),
+		CreatedBefore: nullTime(opts.CreatedBefore),
+		Search:        searchPattern(opts.Search),
+		Tag:           domain.NormalizeTag(opts.Tag),
+		SortBy:        opts.SortBy,
+		SortDesc:      opts.SortDesc,
+		RowOffset:     int32(opts.Offset),
+		RowLimit:      int32(opts.Limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to list resumes by user ID")
+		return nil, 0, err
+	}
+
+	resumes := make([]*domain.Resume, len(rows))
+	for i, row := range rows {
+		resumes[i] = resumeFromRow(row)
+	}
+
+	return resumes, total, nil
+}
+
+// UpdateResumeMetadata replaces a resume's title, target job title, tags and language
+func (r *PostgresResumeRepository) UpdateResumeMetadata(ctx context.Context, id uuid.UUID, metadata *domain.ResumeMetadata) error {
+	// Apply BeforeSave to sanitize the data
+	metadata.BeforeSave()
 
-	result, err := r.db.Exec(query, id)
+	// Validate the metadata
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+
+	settings, err := json.Marshal(&metadata.Settings)
 	if err != nil {
-		log.Error().Err(err).Str("resume_id", id.String()).Msg("Failed to delete resume")
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateResumeMetadata(ctx, dbgen.UpdateResumeMetadataParams{
+		Title:          metadata.Title,
+		TargetJobTitle: metadata.TargetJobTitle,
+		Tags:           metadata.Tags,
+		Language:       metadata.Language,
+		Settings:       settings,
+		UpdatedAt:      time.Now().UTC(),
+		ID:             id,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", id.String()).Msg("Failed to update resume metadata")
 		return err
 	}
 
@@ -121,51 +189,49 @@ func (r *PostgresResumeRepository) DeleteResume(id uuid.UUID) error {
 	return nil
 }
 
-// SavePersonalInfo saves personal info for a resume
-func (r *PostgresResumeRepository) SavePersonalInfo(resumeID uuid.UUID, info *domain.PersonalInfo) error {
-	query := `
-		INSERT INTO personal_info (
-			id, resume_id, first_name, last_name, email, phone, 
-			street, city, country, job_title, created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		ON CONFLICT (resume_id) DO UPDATE SET
-			first_name = $3,
-			last_name = $4,
-			email = $5,
-			phone = $6,
-			street = $7,
-			city = $8,
-			country = $9,
-			job_title = $10,
-			updated_at = $12
-		RETURNING id
-	`
+// DeleteResume deletes a resume
+func (r *PostgresResumeRepository) DeleteResume(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteResume(ctx, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", id.String()).Msg("Failed to delete resume")
+		return err
+	}
 
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SavePersonalInfo saves personal info for a resume
+func (r *PostgresResumeRepository) SavePersonalInfo(ctx context.Context, resumeID uuid.UUID, info *domain.PersonalInfo) error {
 	// Apply BeforeSave to sanitize the data
 	info.BeforeSave()
 
-	id := uuid.New()
-	now := time.Now()
-
-	var returnedID uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		id,
-		resumeID,
-		info.FirstName,
-		info.LastName,
-		info.Email,
-		info.Phone,
-		info.Address.Street,
-		info.Address.City,
-		info.Address.Country,
-		info.JobTitle,
-		now,
-		now,
-	).Scan(&returnedID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to save personal info")
+	now := time.Now().UTC()
+
+	err := queriesFor(ctx, r.queries).UpsertPersonalInfo(ctx, dbgen.UpsertPersonalInfoParams{
+		ID:              uuid.New(),
+		ResumeID:        resumeID,
+		FirstName:       info.FirstName,
+		LastName:        info.LastName,
+		Email:           info.Email,
+		Phone:           nullString(info.Phone),
+		PhoneCountry:    nullString(info.PhoneCountry),
+		Street:          nullString(info.Address.Street),
+		AddressLine2:    nullString(info.Address.Line2),
+		City:            nullString(info.Address.City),
+		Region:          nullString(info.Address.Region),
+		PostalCode:      nullString(info.Address.PostalCode),
+		Country:         nullString(info.Address.Country),
+		AddressFreeForm: nullString(info.Address.FreeForm),
+		JobTitle:        nullString(info.JobTitle),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to save personal info")
 		return err
 	}
 
@@ -173,58 +239,60 @@ func (r *PostgresResumeRepository) SavePersonalInfo(resumeID uuid.UUID, info *do
 }
 
 // GetPersonalInfo retrieves personal info for a resume
-func (r *PostgresResumeRepository) GetPersonalInfo(resumeID uuid.UUID) (*domain.PersonalInfo, error) {
-	query := `
-		SELECT first_name, last_name, email, phone, street, city, country, job_title
-		FROM personal_info
-		WHERE resume_id = $1
-	`
-
-	var info struct {
-		FirstName string `db:"first_name"`
-		LastName  string `db:"last_name"`
-		Email     string `db:"email"`
-		Phone     string `db:"phone"`
-		Street    string `db:"street"`
-		City      string `db:"city"`
-		Country   string `db:"country"`
-		JobTitle  string `db:"job_title"`
-	}
-
-	err := r.db.Get(&info, query, resumeID)
+func (r *PostgresResumeRepository) GetPersonalInfo(ctx context.Context, resumeID uuid.UUID) (*domain.PersonalInfo, error) {
+	info, err := queriesFor(ctx, r.queries).GetPersonalInfo(ctx, resumeID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get personal info")
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get personal info")
 		return nil, err
 	}
 
 	result := &domain.PersonalInfo{
-		FirstName: info.FirstName,
-		LastName:  info.LastName,
-		Email:     info.Email,
-		Phone:     info.Phone,
-		JobTitle:  info.JobTitle,
+		FirstName:    info.FirstName,
+		LastName:     info.LastName,
+		Email:        info.Email,
+		Phone:        info.Phone.String,
+		PhoneCountry: info.PhoneCountry.String,
+		PhoneDisplay: phone.Display(info.Phone.String, info.PhoneCountry.String),
+		Address: domain.Address{
+			Street:     info.Street.String,
+			Line2:      info.AddressLine2.String,
+			City:       info.City.String,
+			Region:     info.Region.String,
+			PostalCode: info.PostalCode.String,
+			Country:    info.Country.String,
+			FreeForm:   info.AddressFreeForm.String,
+		},
+		JobTitle: info.JobTitle.String,
+		PhotoURL: info.PhotoUrl.String,
 	}
-	result.Address.Street = info.Street
-	result.Address.City = info.City
-	result.Address.Country = info.Country
 
 	return result, nil
 }
 
-// AddEducation adds an education entry to a resume
-func (r *PostgresResumeRepository) AddEducation(resumeID uuid.UUID, education *domain.Education) (uuid.UUID, error) {
-	query := `
-		INSERT INTO education (
-			id, resume_id, institution, location, degree, field, 
-			start_date, end_date, description, created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		RETURNING id
-	`
+// SetPhotoURL sets the photo of a resume's personal info
+func (r *PostgresResumeRepository) SetPhotoURL(ctx context.Context, resumeID uuid.UUID, photoURL string) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).SetPersonalInfoPhoto(ctx, dbgen.SetPersonalInfoPhotoParams{
+		ResumeID:  resumeID,
+		PhotoUrl:  nullString(photoURL),
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to set personal info photo")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
 
+// AddEducation adds an education entry to a resume
+func (r *PostgresResumeRepository) AddEducation(ctx context.Context, resumeID uuid.UUID, education *domain.Education) (uuid.UUID, error) {
 	// Apply BeforeSave to sanitize the data
 	education.BeforeSave()
 
@@ -233,46 +301,33 @@ func (r *PostgresResumeRepository) AddEducation(resumeID uuid.UUID, education *d
 		return uuid.Nil, err
 	}
 
-	id := uuid.New()
-	now := time.Now()
-
 	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if education.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", education.StartDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		startDate = &parsedStartDate
+	startDate, err := time.Parse(dateLayout, education.StartDate)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	endDate, err := parseNullDate(education.EndDate, datePresent)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	if education.EndDate != "" && education.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", education.EndDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		endDate = &parsedEndDate
-	}
-
-	var returnedID uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		id,
-		resumeID,
-		education.Institution,
-		education.Location,
-		education.Degree,
-		education.Field,
-		startDate,
-		endDate,
-		education.Description,
-		now,
-		now,
-	).Scan(&returnedID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to add education")
+	now := time.Now().UTC()
+
+	returnedID, err := queriesFor(ctx, r.queries).CreateEducation(ctx, dbgen.CreateEducationParams{
+		ID:          uuid.New(),
+		ResumeID:    resumeID,
+		Institution: education.Institution,
+		Location:    nullString(education.Location),
+		Degree:      education.Degree,
+		Field:       nullString(education.Field),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Description: nullString(education.Description),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to add education")
 		return uuid.Nil, err
 	}
 
@@ -280,20 +335,7 @@ func (r *PostgresResumeRepository) AddEducation(resumeID uuid.UUID, education *d
 }
 
 // UpdateEducation updates an education entry
-func (r *PostgresResumeRepository) UpdateEducation(id uuid.UUID, education *domain.Education) error {
-	query := `
-		UPDATE education
-		SET institution = $1,
-			location = $2,
-			degree = $3,
-			field = $4,
-			start_date = $5,
-			end_date = $6,
-			description = $7,
-			updated_at = $8
-		WHERE id = $9
-	`
-
+func (r *PostgresResumeRepository) UpdateEducation(ctx context.Context, resumeID, id uuid.UUID, education *domain.Education) error {
 	// Apply BeforeSave to sanitize the data
 	education.BeforeSave()
 
@@ -302,48 +344,30 @@ func (r *PostgresResumeRepository) UpdateEducation(id uuid.UUID, education *doma
 		return err
 	}
 
-	now := time.Now()
-
 	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if education.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", education.StartDate)
-		if err != nil {
-			return err
-		}
-		startDate = &parsedStartDate
+	startDate, err := time.Parse(dateLayout, education.StartDate)
+	if err != nil {
+		return err
 	}
-
-	if education.EndDate != "" && education.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", education.EndDate)
-		if err != nil {
-			return err
-		}
-		endDate = &parsedEndDate
-	}
-
-	result, err := r.db.Exec(
-		query,
-		education.Institution,
-		education.Location,
-		education.Degree,
-		education.Field,
-		startDate,
-		endDate,
-		education.Description,
-		now,
-		id,
-	)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to update education")
+	endDate, err := parseNullDate(education.EndDate, datePresent)
+	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateEducation(ctx, dbgen.UpdateEducationParams{
+		Institution: education.Institution,
+		Location:    nullString(education.Location),
+		Degree:      education.Degree,
+		Field:       nullString(education.Field),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Description: nullString(education.Description),
+		UpdatedAt:   time.Now().UTC(),
+		ID:          id,
+		ResumeID:    resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update education")
 		return err
 	}
 
@@ -355,21 +379,13 @@ func (r *PostgresResumeRepository) UpdateEducation(id uuid.UUID, education *doma
 }
 
 // DeleteEducation deletes an education entry
-func (r *PostgresResumeRepository) DeleteEducation(id uuid.UUID) error {
-	query := `
-		DELETE FROM education
-		WHERE id = $1
-	`
-
-	result, err := r.db.Exec(query, id)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete education")
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
+func (r *PostgresResumeRepository) DeleteEducation(ctx context.Context, resumeID, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteEducation(ctx, dbgen.DeleteEducationParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete education")
 		return err
 	}
 
@@ -381,119 +397,37 @@ func (r *PostgresResumeRepository) DeleteEducation(id uuid.UUID) error {
 }
 
 // GetEducation retrieves an education entry by ID
-func (r *PostgresResumeRepository) GetEducation(id uuid.UUID) (*domain.Education, error) {
-	query := `
-		SELECT institution, location, degree, field, 
-		       start_date, end_date, description
-		FROM education
-		WHERE id = $1
-	`
-
-	var edu struct {
-		Institution string     `db:"institution"`
-		Location    string     `db:"location"`
-		Degree      string     `db:"degree"`
-		Field       string     `db:"field"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
-	}
-
-	err := r.db.Get(&edu, query, id)
+func (r *PostgresResumeRepository) GetEducation(ctx context.Context, id uuid.UUID) (*domain.Education, error) {
+	row, err := queriesFor(ctx, r.queries).GetEducation(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("education_id", id.String()).Msg("Failed to get education")
+		log.Ctx(ctx).Error().Err(err).Str("education_id", id.String()).Msg("Failed to get education")
 		return nil, err
 	}
 
-	// Format dates
-	startDate := edu.StartDate.Format("2006-01-02")
-	var endDate string
-	if edu.EndDate != nil {
-		endDate = edu.EndDate.Format("2006-01-02")
-	} else {
-		endDate = "Present"
-	}
-
-	education := &domain.Education{
-		Institution: edu.Institution,
-		Location:    edu.Location,
-		Degree:      edu.Degree,
-		Field:       edu.Field,
-		StartDate:   startDate,
-		EndDate:     endDate,
-		Description: edu.Description,
-	}
-
-	return education, nil
+	return educationFromRow(dbgen.GetEducationByResumeRow(row)), nil
 }
 
 // GetEducationByResume retrieves all education entries for a resume
-func (r *PostgresResumeRepository) GetEducationByResume(resumeID uuid.UUID) ([]*domain.Education, error) {
-	query := `
-		SELECT id, institution, location, degree, field, 
-		       start_date, end_date, description
-		FROM education
-		WHERE resume_id = $1
-		ORDER BY start_date DESC
-	`
-
-	type educationRow struct {
-		ID          uuid.UUID  `db:"id"`
-		Institution string     `db:"institution"`
-		Location    string     `db:"location"`
-		Degree      string     `db:"degree"`
-		Field       string     `db:"field"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
-	}
-
-	var rows []educationRow
-	err := r.db.Select(&rows, query, resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get education by resume")
+func (r *PostgresResumeRepository) GetEducationByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Education, error) {
+	rows, err := queriesFor(ctx, r.queries).GetEducationByResume(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get education by resume")
 		return nil, err
 	}
 
 	education := make([]*domain.Education, len(rows))
 	for i, row := range rows {
-		// Format dates
-		startDate := row.StartDate.Format("2006-01-02")
-		var endDate string
-		if row.EndDate != nil {
-			endDate = row.EndDate.Format("2006-01-02")
-		} else {
-			endDate = "Present"
-		}
-
-		education[i] = &domain.Education{
-			Institution: row.Institution,
-			Location:    row.Location,
-			Degree:      row.Degree,
-			Field:       row.Field,
-			StartDate:   startDate,
-			EndDate:     endDate,
-			Description: row.Description,
-		}
+		education[i] = educationFromRow(row)
 	}
 
 	return education, nil
 }
 
 // AddExperience adds an experience entry to a resume
-func (r *PostgresResumeRepository) AddExperience(resumeID uuid.UUID, experience *domain.Experience) (uuid.UUID, error) {
-	query := `
-		INSERT INTO experience (
-			id, resume_id, employer, job_title, location, 
-			start_date, end_date, description, created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id
-	`
-
+func (r *PostgresResumeRepository) AddExperience(ctx context.Context, resumeID uuid.UUID, experience *domain.Experience) (uuid.UUID, error) {
 	// Apply BeforeSave to sanitize the data
 	experience.BeforeSave()
 
@@ -502,45 +436,32 @@ func (r *PostgresResumeRepository) AddExperience(resumeID uuid.UUID, experience
 		return uuid.Nil, err
 	}
 
-	id := uuid.New()
-	now := time.Now()
-
 	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if experience.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", experience.StartDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		startDate = &parsedStartDate
+	startDate, err := time.Parse(dateLayout, experience.StartDate)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	endDate, err := parseNullDate(experience.EndDate, datePresent)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	if experience.EndDate != "" && experience.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", experience.EndDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		endDate = &parsedEndDate
-	}
-
-	var returnedID uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		id,
-		resumeID,
-		experience.Employer,
-		experience.JobTitle,
-		experience.Location,
-		startDate,
-		endDate,
-		experience.Description,
-		now,
-		now,
-	).Scan(&returnedID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to add experience")
+	now := time.Now().UTC()
+
+	returnedID, err := queriesFor(ctx, r.queries).CreateExperience(ctx, dbgen.CreateExperienceParams{
+		ID:          uuid.New(),
+		ResumeID:    resumeID,
+		Employer:    experience.Employer,
+		JobTitle:    experience.JobTitle,
+		Location:    nullString(experience.Location),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Description: nullString(experience.Description),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to add experience")
 		return uuid.Nil, err
 	}
 
@@ -553,19 +474,7 @@ func (r *PostgresResumeRepository) AddExperience(resumeID uuid.UUID, experience
 }
 
 // UpdateExperience updates an experience entry
-func (r *PostgresResumeRepository) UpdateExperience(id uuid.UUID, experience *domain.Experience) error {
-	query := `
-		UPDATE experience
-		SET employer = $1,
-			job_title = $2,
-			location = $3,
-			start_date = $4,
-			end_date = $5,
-			description = $6,
-			updated_at = $7
-		WHERE id = $8
-	`
-
+func (r *PostgresResumeRepository) UpdateExperience(ctx context.Context, resumeID, id uuid.UUID, experience *domain.Experience) error {
 	// Apply BeforeSave to sanitize the data
 	experience.BeforeSave()
 
@@ -574,47 +483,29 @@ func (r *PostgresResumeRepository) UpdateExperience(id uuid.UUID, experience *do
 		return err
 	}
 
-	now := time.Now()
-
 	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if experience.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", experience.StartDate)
-		if err != nil {
-			return err
-		}
-		startDate = &parsedStartDate
+	startDate, err := time.Parse(dateLayout, experience.StartDate)
+	if err != nil {
+		return err
 	}
-
-	if experience.EndDate != "" && experience.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", experience.EndDate)
-		if err != nil {
-			return err
-		}
-		endDate = &parsedEndDate
-	}
-
-	result, err := r.db.Exec(
-		query,
-		experience.Employer,
-		experience.JobTitle,
-		experience.Location,
-		startDate,
-		endDate,
-		experience.Description,
-		now,
-		id,
-	)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to update experience")
+	endDate, err := parseNullDate(experience.EndDate, datePresent)
+	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateExperience(ctx, dbgen.UpdateExperienceParams{
+		Employer:    experience.Employer,
+		JobTitle:    experience.JobTitle,
+		Location:    nullString(experience.Location),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Description: nullString(experience.Description),
+		UpdatedAt:   time.Now().UTC(),
+		ID:          id,
+		ResumeID:    resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update experience")
 		return err
 	}
 
@@ -631,21 +522,13 @@ func (r *PostgresResumeRepository) UpdateExperience(id uuid.UUID, experience *do
 }
 
 // DeleteExperience deletes an experience entry
-func (r *PostgresResumeRepository) DeleteExperience(id uuid.UUID) error {
-	query := `
-		DELETE FROM experience
-		WHERE id = $1
-	`
-
-	result, err := r.db.Exec(query, id)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete experience")
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
+func (r *PostgresResumeRepository) DeleteExperience(ctx context.Context, resumeID, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteExperience(ctx, dbgen.DeleteExperienceParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete experience")
 		return err
 	}
 
@@ -656,119 +539,41 @@ func (r *PostgresResumeRepository) DeleteExperience(id uuid.UUID) error {
 	return nil
 }
 
-// GetExperience retrieves an experience entry by ID
-func (r *PostgresResumeRepository) GetExperience(id uuid.UUID) (*domain.Experience, error) {
-	query := `
-		SELECT employer, job_title, location, 
-		       start_date, end_date, description
-		FROM experience
-		WHERE id = $1
-	`
-
-	var exp struct {
-		Employer    string     `db:"employer"`
-		JobTitle    string     `db:"job_title"`
-		Location    string     `db:"location"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
-	}
-
-	err := r.db.Get(&exp, query, id)
+// GetExperience retrieves an experience entry of a resume by ID
+func (r *PostgresResumeRepository) GetExperience(ctx context.Context, resumeID, id uuid.UUID) (*domain.Experience, error) {
+	row, err := queriesFor(ctx, r.queries).GetExperience(ctx, dbgen.GetExperienceParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("experience_id", id.String()).Msg("Failed to get experience")
+		log.Ctx(ctx).Error().Err(err).Str("experience_id", id.String()).Msg("Failed to get experience")
 		return nil, err
 	}
 
-	// Format dates
-	startDate := exp.StartDate.Format("2006-01-02")
-	var endDate string
-	if exp.EndDate != nil {
-		endDate = exp.EndDate.Format("2006-01-02")
-	} else {
-		endDate = "Present"
-	}
-
-	experience := &domain.Experience{
-		Employer:    exp.Employer,
-		JobTitle:    exp.JobTitle,
-		Location:    exp.Location,
-		StartDate:   startDate,
-		EndDate:     endDate,
-		Description: exp.Description,
-		// Fetch achievements if needed
-		Achievements: []string{},
-	}
-
-	return experience, nil
+	return experienceFromRow(dbgen.GetExperienceByResumeRow(row)), nil
 }
 
 // GetExperienceByResume retrieves all experience entries for a resume
-func (r *PostgresResumeRepository) GetExperienceByResume(resumeID uuid.UUID) ([]*domain.Experience, error) {
-	query := `
-		SELECT id, employer, job_title, location, 
-		       start_date, end_date, description
-		FROM experience
-		WHERE resume_id = $1
-		ORDER BY start_date DESC
-	`
-
-	type experienceRow struct {
-		ID          uuid.UUID  `db:"id"`
-		Employer    string     `db:"employer"`
-		JobTitle    string     `db:"job_title"`
-		Location    string     `db:"location"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
-	}
-
-	var rows []experienceRow
-	err := r.db.Select(&rows, query, resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get experience by resume")
+func (r *PostgresResumeRepository) GetExperienceByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Experience, error) {
+	rows, err := queriesFor(ctx, r.queries).GetExperienceByResume(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get experience by resume")
 		return nil, err
 	}
 
 	experience := make([]*domain.Experience, len(rows))
 	for i, row := range rows {
-		// Format dates
-		startDate := row.StartDate.Format("2006-01-02")
-		var endDate string
-		if row.EndDate != nil {
-			endDate = row.EndDate.Format("2006-01-02")
-		} else {
-			endDate = "Present"
-		}
-
-		experience[i] = &domain.Experience{
-			Employer:    row.Employer,
-			JobTitle:    row.JobTitle,
-			Location:    row.Location,
-			StartDate:   startDate,
-			EndDate:     endDate,
-			Description: row.Description,
-			// Fetch achievements if needed
-			Achievements: []string{},
-		}
+		experience[i] = experienceFromRow(row)
 	}
 
 	return experience, nil
 }
 
 // AddSkill adds a skill entry to a resume
-func (r *PostgresResumeRepository) AddSkill(resumeID uuid.UUID, skill *domain.Skill) (uuid.UUID, error) {
-	query := `
-		INSERT INTO skills (
-			id, resume_id, name, category, proficiency, created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id
-	`
-
+func (r *PostgresResumeRepository) AddSkill(ctx context.Context, resumeID uuid.UUID, skill *domain.Skill) (uuid.UUID, error) {
 	// Apply BeforeSave to sanitize the data
 	skill.BeforeSave()
 
@@ -777,81 +582,98 @@ func (r *PostgresResumeRepository) AddSkill(resumeID uuid.UUID, skill *domain.Sk
 		return uuid.Nil, err
 	}
 
-	id := uuid.New()
-	now := time.Now()
-
-	// Use NULL for zero proficiency
-	var proficiency any
-	if skill.Proficiency != 0 {
-		proficiency = skill.Proficiency
-	} else {
-		proficiency = nil
-	}
-
-	var returnedID uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		id,
-		resumeID,
-		skill.Name,
-		skill.Category,
-		proficiency,
-		now,
-		now,
-	).Scan(&returnedID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to add skill")
+	now := time.Now().UTC()
+
+	returnedID, err := queriesFor(ctx, r.queries).CreateSkill(ctx, dbgen.CreateSkillParams{
+		ID:          uuid.New(),
+		ResumeID:    resumeID,
+		Name:        skill.Name,
+		Category:    skill.Category,
+		Proficiency: nullProficiency(skill.Proficiency),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to add skill")
 		return uuid.Nil, err
 	}
 
 	return returnedID, nil
 }
 
-// UpdateSkill updates a skill entry
-func (r *PostgresResumeRepository) UpdateSkill(id uuid.UUID, skill *domain.Skill) error {
-	query := `
-		UPDATE skills
-		SET name = $1,
-			category = $2,
-			proficiency = $3,
-			updated_at = $4
-		WHERE id = $5
-	`
-
-	// Apply BeforeSave to sanitize the data
-	skill.BeforeSave()
+// AddSkills adds several skill entries to a resume in one transaction
+func (r *PostgresResumeRepository) AddSkills(ctx context.Context, resumeID uuid.UUID, skills []*domain.Skill) ([]uuid.UUID, error) {
+	for _, skill := range skills {
+		// Apply BeforeSave to sanitize the data
+		skill.BeforeSave()
 
-	// Validate the entry
-	if err := skill.Validate(); err != nil {
-		return err
+		// Validate the entry
+		if err := skill.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
-	now := time.Now()
+	now := time.Now().UTC()
 
-	// Use NULL for zero proficiency
-	var proficiency any
-	if skill.Proficiency != 0 {
-		proficiency = skill.Proficiency
-	} else {
-		proficiency = nil
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	qtx := r.queries.WithTx(tx.Tx)
+
+	ids := make([]uuid.UUID, 0, len(skills))
+	for _, skill := range skills {
+		var returnedID uuid.UUID
+		returnedID, err = qtx.CreateSkill(ctx, dbgen.CreateSkillParams{
+			ID:          uuid.New(),
+			ResumeID:    resumeID,
+			Name:        skill.Name,
+			Category:    skill.Category,
+			Proficiency: nullProficiency(skill.Proficiency),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Failed to add skill")
+			return nil, err
+		}
+		ids = append(ids, returnedID)
 	}
 
-	result, err := r.db.Exec(
-		query,
-		skill.Name,
-		skill.Category,
-		proficiency,
-		now,
-		id,
-	)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to update skill")
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to commit transaction")
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// UpdateSkill updates a skill entry
+func (r *PostgresResumeRepository) UpdateSkill(ctx context.Context, resumeID, id uuid.UUID, skill *domain.Skill) error {
+	// Apply BeforeSave to sanitize the data
+	skill.BeforeSave()
+
+	// Validate the entry
+	if err := skill.Validate(); err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateSkill(ctx, dbgen.UpdateSkillParams{
+		Name:        skill.Name,
+		Category:    skill.Category,
+		Proficiency: nullProficiency(skill.Proficiency),
+		UpdatedAt:   time.Now().UTC(),
+		ID:          id,
+		ResumeID:    resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update skill")
 		return err
 	}
 
@@ -863,21 +685,13 @@ func (r *PostgresResumeRepository) UpdateSkill(id uuid.UUID, skill *domain.Skill
 }
 
 // DeleteSkill deletes a skill entry
-func (r *PostgresResumeRepository) DeleteSkill(id uuid.UUID) error {
-	query := `
-		DELETE FROM skills
-		WHERE id = $1
-	`
-
-	result, err := r.db.Exec(query, id)
+func (r *PostgresResumeRepository) DeleteSkill(ctx context.Context, resumeID, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteSkill(ctx, dbgen.DeleteSkillParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete skill")
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete skill")
 		return err
 	}
 
@@ -889,89 +703,37 @@ func (r *PostgresResumeRepository) DeleteSkill(id uuid.UUID) error {
 }
 
 // GetSkill retrieves a skill entry by ID
-func (r *PostgresResumeRepository) GetSkill(id uuid.UUID) (*domain.Skill, error) {
-	query := `
-		SELECT name, category, proficiency
-		FROM skills
-		WHERE id = $1
-	`
-
-	var skillRow struct {
-		Name        string `db:"name"`
-		Category    string `db:"category"`
-		Proficiency *int   `db:"proficiency"`
-	}
-
-	err := r.db.Get(&skillRow, query, id)
+func (r *PostgresResumeRepository) GetSkill(ctx context.Context, id uuid.UUID) (*domain.Skill, error) {
+	row, err := queriesFor(ctx, r.queries).GetSkill(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("skill_id", id.String()).Msg("Failed to get skill")
+		log.Ctx(ctx).Error().Err(err).Str("skill_id", id.String()).Msg("Failed to get skill")
 		return nil, err
 	}
 
-	skill := &domain.Skill{
-		Name:     skillRow.Name,
-		Category: skillRow.Category,
-	}
-
-	if skillRow.Proficiency != nil {
-		skill.Proficiency = *skillRow.Proficiency
-	}
-
-	return skill, nil
+	return skillFromRow(dbgen.GetSkillsByResumeRow(row)), nil
 }
 
 // GetSkillsByResume retrieves all skill entries for a resume
-func (r *PostgresResumeRepository) GetSkillsByResume(resumeID uuid.UUID) ([]*domain.Skill, error) {
-	query := `
-		SELECT id, name, category, proficiency
-		FROM skills
-		WHERE resume_id = $1
-		ORDER BY category, name
-	`
-
-	type skillRow struct {
-		ID          uuid.UUID `db:"id"`
-		Name        string    `db:"name"`
-		Category    string    `db:"category"`
-		Proficiency *int      `db:"proficiency"`
-	}
-
-	var rows []skillRow
-	err := r.db.Select(&rows, query, resumeID)
+func (r *PostgresResumeRepository) GetSkillsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Skill, error) {
+	rows, err := queriesFor(ctx, r.queries).GetSkillsByResume(ctx, resumeID)
 	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get skills by resume")
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get skills by resume")
 		return nil, err
 	}
 
 	skills := make([]*domain.Skill, len(rows))
 	for i, row := range rows {
-		skills[i] = &domain.Skill{
-			Name:     row.Name,
-			Category: row.Category,
-		}
-
-		if row.Proficiency != nil {
-			skills[i].Proficiency = *row.Proficiency
-		}
+		skills[i] = skillFromRow(row)
 	}
 
 	return skills, nil
 }
 
 // AddProject adds a project entry to a resume
-func (r *PostgresResumeRepository) AddProject(resumeID uuid.UUID, project *domain.Project) (uuid.UUID, error) {
-	query := `
-		INSERT INTO projects (
-			id, resume_id, name, description, repo_url, demo_url, 
-			start_date, end_date, created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id
-	`
-
+func (r *PostgresResumeRepository) AddProject(ctx context.Context, resumeID uuid.UUID, project *domain.Project) (uuid.UUID, error) {
 	// Apply BeforeSave to sanitize the data
 	project.BeforeSave()
 
@@ -980,32 +742,22 @@ func (r *PostgresResumeRepository) AddProject(resumeID uuid.UUID, project *domai
 		return uuid.Nil, err
 	}
 
-	id := uuid.New()
-	now := time.Now()
-
 	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if project.StartDate != "" && project.StartDate != "Present" {
-		parsedStartDate, err := time.Parse("2006-01-02", project.StartDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		startDate = &parsedStartDate
+	startDate, err := parseNullDate(project.StartDate, datePresent)
+	if err != nil {
+		return uuid.Nil, err
 	}
-
-	if project.EndDate != "" && project.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", project.EndDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		endDate = &parsedEndDate
+	endDate, err := parseNullDate(project.EndDate, datePresent)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	tx, err := r.db.Beginx()
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to begin transaction")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to begin transaction")
 		return uuid.Nil, err
 	}
 	defer func() {
@@ -1013,69 +765,49 @@ func (r *PostgresResumeRepository) AddProject(resumeID uuid.UUID, project *domai
 			tx.Rollback()
 		}
 	}()
-
-	var returnedID uuid.UUID
-	err = tx.QueryRow(
-		query,
-		id,
-		resumeID,
-		project.Name,
-		project.Description,
-		project.RepoURL,
-		project.DemoURL,
-		startDate,
-		endDate,
-		now,
-		now,
-	).Scan(&returnedID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to add project")
+	qtx := r.queries.WithTx(tx.Tx)
+
+	returnedID, err := qtx.CreateProject(ctx, dbgen.CreateProjectParams{
+		ID:          id,
+		ResumeID:    resumeID,
+		Name:        project.Name,
+		Description: nullString(project.Description),
+		RepoUrl:     nullString(project.RepoURL),
+		DemoUrl:     nullString(project.DemoURL),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to add project")
 		return uuid.Nil, err
 	}
 
 	// Add technologies
 	for _, tech := range project.Technologies {
-		err = r.addProjectTechnologyTx(tx, id, tech)
+		_, err = qtx.AddProjectTechnology(ctx, dbgen.AddProjectTechnologyParams{
+			ID:         uuid.New(),
+			Technology: tech,
+			ProjectID:  id,
+			ResumeID:   resumeID,
+		})
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to add project technology")
+			log.Ctx(ctx).Error().Err(err).Msg("Failed to add project technology")
 			return uuid.Nil, err
 		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		log.Error().Err(err).Msg("Failed to commit transaction")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to commit transaction")
 		return uuid.Nil, err
 	}
 
 	return returnedID, nil
 }
 
-// addProjectTechnologyTx adds a technology to a project within a transaction
-func (r *PostgresResumeRepository) addProjectTechnologyTx(tx *sqlx.Tx, projectID uuid.UUID, technology string) error {
-	query := `
-		INSERT INTO project_technologies (id, project_id, technology)
-		VALUES ($1, $2, $3)
-	`
-
-	id := uuid.New()
-	_, err := tx.Exec(query, id, projectID, technology)
-	return err
-}
-
 // UpdateProject updates a project entry
-func (r *PostgresResumeRepository) UpdateProject(id uuid.UUID, project *domain.Project) error {
-	query := `
-		UPDATE projects
-		SET name = $1,
-			description = $2,
-			repo_url = $3,
-			demo_url = $4,
-			start_date = $5,
-			end_date = $6,
-			updated_at = $7
-		WHERE id = $8
-	`
-
+func (r *PostgresResumeRepository) UpdateProject(ctx context.Context, resumeID, id uuid.UUID, project *domain.Project) error {
 	// Apply BeforeSave to sanitize the data
 	project.BeforeSave()
 
@@ -1084,31 +816,19 @@ func (r *PostgresResumeRepository) UpdateProject(id uuid.UUID, project *domain.P
 		return err
 	}
 
-	now := time.Now()
-
 	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if project.StartDate != "" && project.StartDate != "Present" {
-		parsedStartDate, err := time.Parse("2006-01-02", project.StartDate)
-		if err != nil {
-			return err
-		}
-		startDate = &parsedStartDate
+	startDate, err := parseNullDate(project.StartDate, datePresent)
+	if err != nil {
+		return err
 	}
-
-	if project.EndDate != "" && project.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", project.EndDate)
-		if err != nil {
-			return err
-		}
-		endDate = &parsedEndDate
+	endDate, err := parseNullDate(project.EndDate, datePresent)
+	if err != nil {
+		return err
 	}
 
-	tx, err := r.db.Beginx()
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to begin transaction")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to begin transaction")
 		return err
 	}
 	defer func() {
@@ -1116,51 +836,51 @@ func (r *PostgresResumeRepository) UpdateProject(id uuid.UUID, project *domain.P
 			tx.Rollback()
 		}
 	}()
+	qtx := r.queries.WithTx(tx.Tx)
 
-	result, err := tx.Exec(
-		query,
-		project.Name,
-		project.Description,
-		project.RepoURL,
-		project.DemoURL,
-		startDate,
-		endDate,
-		now,
-		id,
-	)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to update project")
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, err := qtx.UpdateProject(ctx, dbgen.UpdateProjectParams{
+		Name:        project.Name,
+		Description: nullString(project.Description),
+		RepoUrl:     nullString(project.RepoURL),
+		DemoUrl:     nullString(project.DemoURL),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		UpdatedAt:   time.Now().UTC(),
+		ID:          id,
+		ResumeID:    resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update project")
 		return err
 	}
 
 	if rowsAffected == 0 {
-		return ErrNotFound
+		err = ErrNotFound
+		return err
 	}
 
 	// Delete existing technologies
-	_, err = tx.Exec("DELETE FROM project_technologies WHERE project_id = $1", id)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete project technologies")
+	if err = qtx.DeleteProjectTechnologies(ctx, id); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete project technologies")
 		return err
 	}
 
 	// Add updated technologies
 	for _, tech := range project.Technologies {
-		err = r.addProjectTechnologyTx(tx, id, tech)
+		_, err = qtx.AddProjectTechnology(ctx, dbgen.AddProjectTechnologyParams{
+			ID:         uuid.New(),
+			Technology: tech,
+			ProjectID:  id,
+			ResumeID:   resumeID,
+		})
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to add project technology")
+			log.Ctx(ctx).Error().Err(err).Msg("Failed to add project technology")
 			return err
 		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		log.Error().Err(err).Msg("Failed to commit transaction")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to commit transaction")
 		return err
 	}
 
@@ -1168,21 +888,13 @@ func (r *PostgresResumeRepository) UpdateProject(id uuid.UUID, project *domain.P
 }
 
 // DeleteProject deletes a project entry
-func (r *PostgresResumeRepository) DeleteProject(id uuid.UUID) error {
-	query := `
-		DELETE FROM projects
-		WHERE id = $1
-	`
-
-	result, err := r.db.Exec(query, id)
+func (r *PostgresResumeRepository) DeleteProject(ctx context.Context, resumeID, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteProject(ctx, dbgen.DeleteProjectParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete project")
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete project")
 		return err
 	}
 
@@ -1194,155 +906,102 @@ func (r *PostgresResumeRepository) DeleteProject(id uuid.UUID) error {
 }
 
 // GetProject retrieves a project entry by ID
-func (r *PostgresResumeRepository) GetProject(id uuid.UUID) (*domain.Project, error) {
-	query := `
-		SELECT name, description, repo_url, demo_url, start_date, end_date
-		FROM projects
-		WHERE id = $1
-	`
-
-	var projectRow struct {
-		Name        string     `db:"name"`
-		Description string     `db:"description"`
-		RepoURL     string     `db:"repo_url"`
-		DemoURL     string     `db:"demo_url"`
-		StartDate   *time.Time `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-	}
-
-	err := r.db.Get(&projectRow, query, id)
+func (r *PostgresResumeRepository) GetProject(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
+	row, err := queriesFor(ctx, r.queries).GetProject(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("project_id", id.String()).Msg("Failed to get project")
+		log.Ctx(ctx).Error().Err(err).Str("project_id", id.String()).Msg("Failed to get project")
 		return nil, err
 	}
 
-	// Format dates
-	var startDate, endDate string
-	if projectRow.StartDate != nil {
-		startDate = projectRow.StartDate.Format("2006-01-02")
-	}
-	if projectRow.EndDate != nil {
-		endDate = projectRow.EndDate.Format("2006-01-02")
-	} else {
-		endDate = "Present"
-	}
-
 	// Get technologies
-	technologies, err := r.GetProjectTechnologies(id)
+	technologies, err := r.GetProjectTechnologies(ctx, id)
 	if err != nil {
-		log.Error().Err(err).Str("project_id", id.String()).Msg("Failed to get project technologies")
+		log.Ctx(ctx).Error().Err(err).Str("project_id", id.String()).Msg("Failed to get project technologies")
 		return nil, err
 	}
 
-	project := &domain.Project{
-		Name:         projectRow.Name,
-		Description:  projectRow.Description,
-		RepoURL:      projectRow.RepoURL,
-		DemoURL:      projectRow.DemoURL,
-		StartDate:    startDate,
-		EndDate:      endDate,
-		Technologies: technologies,
-	}
-
-	return project, nil
+	return projectFromRow(dbgen.GetProjectsByResumeRow(row), technologies), nil
 }
 
 // GetProjectsByResume retrieves all project entries for a resume
-func (r *PostgresResumeRepository) GetProjectsByResume(resumeID uuid.UUID) ([]*domain.Project, error) {
-	query := `
-		SELECT id, name, description, repo_url, demo_url, start_date, end_date
-		FROM projects
-		WHERE resume_id = $1
-		ORDER BY COALESCE(start_date, '9999-12-31') DESC
-	`
-
-	type projectRow struct {
-		ID          uuid.UUID  `db:"id"`
-		Name        string     `db:"name"`
-		Description string     `db:"description"`
-		RepoURL     string     `db:"repo_url"`
-		DemoURL     string     `db:"demo_url"`
-		StartDate   *time.Time `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-	}
-
-	var rows []projectRow
-	err := r.db.Select(&rows, query, resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get projects by resume")
+func (r *PostgresResumeRepository) GetProjectsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Project, error) {
+	rows, err := queriesFor(ctx, r.queries).GetProjectsByResume(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get projects by resume")
 		return nil, err
 	}
 
-	projects := make([]*domain.Project, len(rows))
+	projectIDs := make([]uuid.UUID, len(rows))
 	for i, row := range rows {
-		// Format dates
-		var startDate, endDate string
-		if row.StartDate != nil {
-			startDate = row.StartDate.Format("2006-01-02")
-		}
-		if row.EndDate != nil {
-			endDate = row.EndDate.Format("2006-01-02")
-		} else {
-			endDate = "Present"
-		}
+		projectIDs[i] = row.ID
+	}
 
-		// Get technologies
-		technologies, err := r.GetProjectTechnologies(row.ID)
-		if err != nil {
-			log.Error().Err(err).Str("project_id", row.ID.String()).Msg("Failed to get project technologies")
-			continue
-		}
+	technologies, err := r.getTechnologiesByProjects(ctx, projectIDs)
+	if err != nil {
+		return nil, err
+	}
 
-		projects[i] = &domain.Project{
-			Name:         row.Name,
-			Description:  row.Description,
-			RepoURL:      row.RepoURL,
-			DemoURL:      row.DemoURL,
-			StartDate:    startDate,
-			EndDate:      endDate,
-			Technologies: technologies,
-		}
+	projects := make([]*domain.Project, len(rows))
+	for i, row := range rows {
+		projects[i] = projectFromRow(row, technologies[row.ID])
 	}
 
 	return projects, nil
 }
 
-// AddProjectTechnology adds a technology to a project
-func (r *PostgresResumeRepository) AddProjectTechnology(projectID uuid.UUID, technology string) error {
-	query := `
-		INSERT INTO project_technologies (id, project_id, technology)
-		VALUES ($1, $2, $3)
-	`
+// getTechnologiesByProjects loads the technologies of several projects in one query, keyed by project ID
+func (r *PostgresResumeRepository) getTechnologiesByProjects(ctx context.Context, projectIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	technologies := make(map[uuid.UUID][]string, len(projectIDs))
+	if len(projectIDs) == 0 {
+		return technologies, nil
+	}
 
-	id := uuid.New()
-	_, err := r.db.Exec(query, id, projectID, technology)
+	rows, err := queriesFor(ctx, r.queries).GetProjectTechnologiesByProjects(ctx, projectIDs)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to add project technology")
-		return err
+		log.Ctx(ctx).Error().Err(err).Int("projects", len(projectIDs)).Msg("Failed to get project technologies")
+		return nil, err
 	}
 
-	return nil
-}
+	for _, row := range rows {
+		technologies[row.ProjectID] = append(technologies[row.ProjectID], row.Technology)
+	}
 
-// DeleteProjectTechnology deletes a technology from a project
-func (r *PostgresResumeRepository) DeleteProjectTechnology(projectID uuid.UUID, technology string) error {
-	query := `
-		DELETE FROM project_technologies
-		WHERE project_id = $1 AND technology = $2
-	`
+	return technologies, nil
+}
 
-	result, err := r.db.Exec(query, projectID, technology)
+// AddProjectTechnology adds a technology to a project
+func (r *PostgresResumeRepository) AddProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).AddProjectTechnology(ctx, dbgen.AddProjectTechnologyParams{
+		ID:         uuid.New(),
+		Technology: technology,
+		ProjectID:  projectID,
+		ResumeID:   resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete project technology")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to add project technology")
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	// Nothing is inserted when the project does not belong to the resume
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteProjectTechnology deletes a technology from a project
+func (r *PostgresResumeRepository) DeleteProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteProjectTechnology(ctx, dbgen.DeleteProjectTechnologyParams{
+		ProjectID:  projectID,
+		ResumeID:   resumeID,
+		Technology: technology,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete project technology")
 		return err
 	}
 
@@ -1354,18 +1013,10 @@ func (r *PostgresResumeRepository) DeleteProjectTechnology(projectID uuid.UUID,
 }
 
 // GetProjectTechnologies retrieves all technologies for a project
-func (r *PostgresResumeRepository) GetProjectTechnologies(projectID uuid.UUID) ([]string, error) {
-	query := `
-		SELECT technology
-		FROM project_technologies
-		WHERE project_id = $1
-		ORDER BY technology
-	`
-
-	var technologies []string
-	err := r.db.Select(&technologies, query, projectID)
-	if err != nil {
-		log.Error().Err(err).Str("project_id", projectID.String()).Msg("Failed to get project technologies")
+func (r *PostgresResumeRepository) GetProjectTechnologies(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	technologies, err := queriesFor(ctx, r.queries).GetProjectTechnologies(ctx, projectID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("project_id", projectID.String()).Msg("Failed to get project technologies")
 		return nil, err
 	}
 
@@ -1373,16 +1024,7 @@ func (r *PostgresResumeRepository) GetProjectTechnologies(projectID uuid.UUID) (
 }
 
 // AddCertification adds a certification entry to a resume
-func (r *PostgresResumeRepository) AddCertification(resumeID uuid.UUID, certification *domain.Certification) (uuid.UUID, error) {
-	query := `
-		INSERT INTO certifications (
-			id, resume_id, name, issuer, issue_date, 
-			expiry_date, credential_id, url, created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id
-	`
-
+func (r *PostgresResumeRepository) AddCertification(ctx context.Context, resumeID uuid.UUID, certification *domain.Certification) (uuid.UUID, error) {
 	// Apply BeforeSave to sanitize the data
 	certification.BeforeSave()
 
@@ -1391,45 +1033,32 @@ func (r *PostgresResumeRepository) AddCertification(resumeID uuid.UUID, certific
 		return uuid.Nil, err
 	}
 
-	id := uuid.New()
-	now := time.Now()
-
 	// Parse dates
-	var issueDate *time.Time
-	var expiryDate *time.Time
-
-	if certification.IssueDate != "" {
-		parsedIssueDate, err := time.Parse("2006-01-02", certification.IssueDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		issueDate = &parsedIssueDate
+	issueDate, err := time.Parse(dateLayout, certification.IssueDate)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	expiryDate, err := parseNullDate(certification.ExpiryDate, dateNoExpiration)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	if certification.ExpiryDate != "" && certification.ExpiryDate != "No Expiration" {
-		parsedExpiryDate, err := time.Parse("2006-01-02", certification.ExpiryDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		expiryDate = &parsedExpiryDate
-	}
-
-	var returnedID uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		id,
-		resumeID,
-		certification.Name,
-		certification.Issuer,
-		issueDate,
-		expiryDate,
-		certification.CredentialID,
-		certification.URL,
-		now,
-		now,
-	).Scan(&returnedID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to add certification")
+	now := time.Now().UTC()
+
+	returnedID, err := queriesFor(ctx, r.queries).CreateCertification(ctx, dbgen.CreateCertificationParams{
+		ID:           uuid.New(),
+		ResumeID:     resumeID,
+		Name:         certification.Name,
+		Issuer:       certification.Issuer,
+		IssueDate:    issueDate,
+		ExpiryDate:   expiryDate,
+		CredentialID: nullString(certification.CredentialID),
+		Url:          nullString(certification.URL),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to add certification")
 		return uuid.Nil, err
 	}
 
@@ -1437,19 +1066,7 @@ func (r *PostgresResumeRepository) AddCertification(resumeID uuid.UUID, certific
 }
 
 // UpdateCertification updates a certification entry
-func (r *PostgresResumeRepository) UpdateCertification(id uuid.UUID, certification *domain.Certification) error {
-	query := `
-		UPDATE certifications
-		SET name = $1,
-			issuer = $2,
-			issue_date = $3,
-			expiry_date = $4,
-			credential_id = $5,
-			url = $6,
-			updated_at = $7
-		WHERE id = $8
-	`
-
+func (r *PostgresResumeRepository) UpdateCertification(ctx context.Context, resumeID, id uuid.UUID, certification *domain.Certification) error {
 	// Apply BeforeSave to sanitize the data
 	certification.BeforeSave()
 
@@ -1458,47 +1075,29 @@ func (r *PostgresResumeRepository) UpdateCertification(id uuid.UUID, certificati
 		return err
 	}
 
-	now := time.Now()
-
 	// Parse dates
-	var issueDate *time.Time
-	var expiryDate *time.Time
-
-	if certification.IssueDate != "" {
-		parsedIssueDate, err := time.Parse("2006-01-02", certification.IssueDate)
-		if err != nil {
-			return err
-		}
-		issueDate = &parsedIssueDate
+	issueDate, err := time.Parse(dateLayout, certification.IssueDate)
+	if err != nil {
+		return err
 	}
-
-	if certification.ExpiryDate != "" && certification.ExpiryDate != "No Expiration" {
-		parsedExpiryDate, err := time.Parse("2006-01-02", certification.ExpiryDate)
-		if err != nil {
-			return err
-		}
-		expiryDate = &parsedExpiryDate
-	}
-
-	result, err := r.db.Exec(
-		query,
-		certification.Name,
-		certification.Issuer,
-		issueDate,
-		expiryDate,
-		certification.CredentialID,
-		certification.URL,
-		now,
-		id,
-	)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to update certification")
+	expiryDate, err := parseNullDate(certification.ExpiryDate, dateNoExpiration)
+	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateCertification(ctx, dbgen.UpdateCertificationParams{
+		Name:         certification.Name,
+		Issuer:       certification.Issuer,
+		IssueDate:    issueDate,
+		ExpiryDate:   expiryDate,
+		CredentialID: nullString(certification.CredentialID),
+		Url:          nullString(certification.URL),
+		UpdatedAt:    time.Now().UTC(),
+		ID:           id,
+		ResumeID:     resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update certification")
 		return err
 	}
 
@@ -1510,21 +1109,13 @@ func (r *PostgresResumeRepository) UpdateCertification(id uuid.UUID, certificati
 }
 
 // DeleteCertification deletes a certification entry
-func (r *PostgresResumeRepository) DeleteCertification(id uuid.UUID) error {
-	query := `
-		DELETE FROM certifications
-		WHERE id = $1
-	`
-
-	result, err := r.db.Exec(query, id)
+func (r *PostgresResumeRepository) DeleteCertification(ctx context.Context, resumeID, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteCertification(ctx, dbgen.DeleteCertificationParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete certification")
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete certification")
 		return err
 	}
 
@@ -1536,158 +1127,256 @@ func (r *PostgresResumeRepository) DeleteCertification(id uuid.UUID) error {
 }
 
 // GetCertification retrieves a certification entry by ID
-func (r *PostgresResumeRepository) GetCertification(id uuid.UUID) (*domain.Certification, error) {
-	query := `
-		SELECT name, issuer, issue_date, expiry_date, credential_id, url
-		FROM certifications
-		WHERE id = $1
-	`
-
-	var certRow struct {
-		Name         string     `db:"name"`
-		Issuer       string     `db:"issuer"`
-		IssueDate    time.Time  `db:"issue_date"`
-		ExpiryDate   *time.Time `db:"expiry_date"`
-		CredentialID string     `db:"credential_id"`
-		URL          string     `db:"url"`
-	}
-
-	err := r.db.Get(&certRow, query, id)
+func (r *PostgresResumeRepository) GetCertification(ctx context.Context, id uuid.UUID) (*domain.Certification, error) {
+	row, err := queriesFor(ctx, r.queries).GetCertification(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("certification_id", id.String()).Msg("Failed to get certification")
+		log.Ctx(ctx).Error().Err(err).Str("certification_id", id.String()).Msg("Failed to get certification")
 		return nil, err
 	}
 
-	// Format dates
-	issueDate := certRow.IssueDate.Format("2006-01-02")
-	var expiryDate string
-	if certRow.ExpiryDate != nil {
-		expiryDate = certRow.ExpiryDate.Format("2006-01-02")
-	} else {
-		expiryDate = "No Expiration"
-	}
-
-	certification := &domain.Certification{
-		Name:         certRow.Name,
-		Issuer:       certRow.Issuer,
-		IssueDate:    issueDate,
-		ExpiryDate:   expiryDate,
-		CredentialID: certRow.CredentialID,
-		URL:          certRow.URL,
-	}
-
-	return certification, nil
+	return certificationFromRow(dbgen.GetCertificationsByResumeRow(row)), nil
 }
 
 // GetCertificationsByResume retrieves all certification entries for a resume
-func (r *PostgresResumeRepository) GetCertificationsByResume(resumeID uuid.UUID) ([]*domain.Certification, error) {
-	query := `
-		SELECT id, name, issuer, issue_date, expiry_date, credential_id, url
-		FROM certifications
-		WHERE resume_id = $1
-		ORDER BY issue_date DESC
-	`
-
-	type certRow struct {
-		ID           uuid.UUID  `db:"id"`
-		Name         string     `db:"name"`
-		Issuer       string     `db:"issuer"`
-		IssueDate    time.Time  `db:"issue_date"`
-		ExpiryDate   *time.Time `db:"expiry_date"`
-		CredentialID string     `db:"credential_id"`
-		URL          string     `db:"url"`
-	}
-
-	var rows []certRow
-	err := r.db.Select(&rows, query, resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get certifications by resume")
+func (r *PostgresResumeRepository) GetCertificationsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Certification, error) {
+	rows, err := queriesFor(ctx, r.queries).GetCertificationsByResume(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get certifications by resume")
 		return nil, err
 	}
 
 	certifications := make([]*domain.Certification, len(rows))
 	for i, row := range rows {
-		// Format dates
-		issueDate := row.IssueDate.Format("2006-01-02")
-		var expiryDate string
-		if row.ExpiryDate != nil {
-			expiryDate = row.ExpiryDate.Format("2006-01-02")
-		} else {
-			expiryDate = "No Expiration"
-		}
-
-		certifications[i] = &domain.Certification{
-			Name:         row.Name,
-			Issuer:       row.Issuer,
-			IssueDate:    issueDate,
-			ExpiryDate:   expiryDate,
-			CredentialID: row.CredentialID,
-			URL:          row.URL,
-		}
+		certifications[i] = certificationFromRow(row)
 	}
 
 	return certifications, nil
 }
 
-// GetCompleteResume retrieves a resume with all its sections
-func (r *PostgresResumeRepository) GetCompleteResume(resumeID uuid.UUID) (*domain.Resume, error) {
+// GetCompleteResume retrieves a complete resume with all related data. The
+// sections are independent, so they are loaded concurrently once the resume is
+// known to exist, or one after another inside a transaction, whose single
+// connection can't run queries concurrently.
+func (r *PostgresResumeRepository) GetCompleteResume(ctx context.Context, resumeID uuid.UUID) (*domain.Resume, error) {
+	ctx, span := tracer.Start(ctx, "PostgresResumeRepository.GetCompleteResume",
+		trace.WithAttributes(attribute.String("resume.id", resumeID.String())))
+	defer span.End()
+
 	// Get basic resume info
-	resume, err := r.GetResumeByID(resumeID)
+	resume, err := r.GetResumeByID(ctx, resumeID)
 	if err != nil {
 		return nil, err
 	}
 
+	g, gctx := errgroup.WithContext(ctx)
+	if InTx(ctx) {
+		g.SetLimit(1)
+	}
+
 	// Get personal info
-	personalInfo, err := r.GetPersonalInfo(resumeID)
-	if err != nil && !errors.Is(err, ErrNotFound) {
+	g.Go(func() error {
+		personalInfo, err := r.GetPersonalInfo(gctx, resumeID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		resume.PersonalInfo = personalInfo
+		return nil
+	})
+
+	// A failing section is logged and left empty rather than failing the whole resume
+	g.Go(func() error {
+		education, err := r.GetEducationByResume(gctx, resumeID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get education")
+			return nil
+		}
+		resume.Education = education
+		return nil
+	})
+
+	g.Go(func() error {
+		experience, err := r.GetExperienceByResume(gctx, resumeID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get experience")
+			return nil
+		}
+		resume.Experience = experience
+		return nil
+	})
+
+	g.Go(func() error {
+		skills, err := r.GetSkillsByResume(gctx, resumeID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get skills")
+			return nil
+		}
+		resume.Skills = skills
+		return nil
+	})
+
+	g.Go(func() error {
+		projects, err := r.GetProjectsByResume(gctx, resumeID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get projects")
+			return nil
+		}
+		resume.Projects = projects
+		return nil
+	})
+
+	g.Go(func() error {
+		certifications, err := r.GetCertificationsByResume(gctx, resumeID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get certifications")
+			return nil
+		}
+		resume.Certifications = certifications
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
 		return nil, err
 	}
-	if personalInfo != nil {
-		resume.PersonalInfo = personalInfo
+
+	return resume, nil
+}
+
+// Helper functions
+
+// resumeFromRow converts a generated resume row to the domain model
+func resumeFromRow(row dbgen.Resume) *domain.Resume {
+	return &domain.Resume{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		ResumeMetadata: domain.ResumeMetadata{
+			Title:          row.Title,
+			TargetJobTitle: row.TargetJobTitle,
+			Tags:           row.Tags,
+			Language:       row.Language,
+			TranslationOf:  uuidPtr(row.TranslationOf),
+			Settings:       resumeSettings(row.Settings),
+		},
 	}
+}
 
-	// Get education entries
-	education, err := r.GetEducationByResume(resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get education")
-	} else {
-		resume.Education = education
+// resumeSettings decodes a resume's stored settings. Settings that can't be
+// decoded are left at their defaults, as they only change how exports look.
+func resumeSettings(data json.RawMessage) domain.ResumeSettings {
+	var settings domain.ResumeSettings
+	_ = json.Unmarshal(data, &settings)
+	return settings
+}
+
+// nullUUID converts an optional ID to a nullable column value
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
 	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
 
-	// Get experience entries
-	experience, err := r.GetExperienceByResume(resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get experience")
-	} else {
-		resume.Experience = experience
+// uuidPtr converts a nullable column value to an optional ID
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
 	}
+	return &id.UUID
+}
 
-	// Get skills
-	skills, err := r.GetSkillsByResume(resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get skills")
-	} else {
-		resume.Skills = skills
+// educationFromRow converts a generated education row to the domain model
+func educationFromRow(row dbgen.GetEducationByResumeRow) *domain.Education {
+	return &domain.Education{
+		Institution: row.Institution,
+		Location:    row.Location.String,
+		Degree:      row.Degree,
+		Field:       row.Field.String,
+		StartDate:   row.StartDate.Format(dateLayout),
+		EndDate:     formatNullDate(row.EndDate, datePresent),
+		Description: row.Description.String,
 	}
+}
 
-	// Get projects
-	projects, err := r.GetProjectsByResume(resumeID)
-	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get projects")
-	} else {
-		resume.Projects = projects
+// experienceFromRow converts a generated experience row to the domain model
+func experienceFromRow(row dbgen.GetExperienceByResumeRow) *domain.Experience {
+	return &domain.Experience{
+		Employer:    row.Employer,
+		JobTitle:    row.JobTitle,
+		Location:    row.Location.String,
+		StartDate:   row.StartDate.Format(dateLayout),
+		EndDate:     formatNullDate(row.EndDate, datePresent),
+		Description: row.Description.String,
+		// Fetch achievements if needed
+		Achievements: []string{},
+	}
+}
+
+// skillFromRow converts a generated skill row to the domain model
+func skillFromRow(row dbgen.GetSkillsByResumeRow) *domain.Skill {
+	return &domain.Skill{
+		Name:        row.Name,
+		Category:    row.Category,
+		Proficiency: int(row.Proficiency.Int32),
+	}
+}
+
+// projectFromRow converts a generated project row to the domain model
+func projectFromRow(row dbgen.GetProjectsByResumeRow, technologies []string) *domain.Project {
+	return &domain.Project{
+		Name:         row.Name,
+		Description:  row.Description.String,
+		RepoURL:      row.RepoUrl.String,
+		DemoURL:      row.DemoUrl.String,
+		StartDate:    formatNullDate(row.StartDate, ""),
+		EndDate:      formatNullDate(row.EndDate, datePresent),
+		Technologies: technologies,
+	}
+}
+
+// certificationFromRow converts a generated certification row to the domain model
+func certificationFromRow(row dbgen.GetCertificationsByResumeRow) *domain.Certification {
+	return &domain.Certification{
+		Name:         row.Name,
+		Issuer:       row.Issuer,
+		IssueDate:    row.IssueDate.Format(dateLayout),
+		ExpiryDate:   formatNullDate(row.ExpiryDate, dateNoExpiration),
+		CredentialID: row.CredentialID.String,
+		URL:          row.Url.String,
 	}
+}
+
+// nullString maps an empty string to NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
 
-	// Get certifications
-	certifications, err := r.GetCertificationsByResume(resumeID)
+// nullProficiency maps an unset (zero) proficiency to NULL
+func nullProficiency(proficiency int) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(proficiency), Valid: proficiency != 0}
+}
+
+// parseNullDate parses a YYYY-MM-DD date, mapping an empty value or the open-ended placeholder to NULL
+func parseNullDate(value, openEnded string) (sql.NullTime, error) {
+	if value == "" || value == openEnded {
+		return sql.NullTime{}, nil
+	}
+
+	t, err := time.Parse(dateLayout, value)
 	if err != nil {
-		log.Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get certifications")
-	} else {
-		resume.Certifications = certifications
+		return sql.NullTime{}, err
 	}
 
-	return resume, nil
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
+
+// formatNullDate formats a nullable date, using the open-ended placeholder for NULL
+func formatNullDate(value sql.NullTime, openEnded string) string {
+	if !value.Valid {
+		return openEnded
+	}
+	return value.Time.Format(dateLayout)
 }
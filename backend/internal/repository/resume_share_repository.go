@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresResumeShareRepository implements the ResumeShareRepository interface using PostgreSQL
+type PostgresResumeShareRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresResumeShareRepository creates a new PostgreSQL share link repository
+func NewPostgresResumeShareRepository(db *sqlx.DB) *PostgresResumeShareRepository {
+	return &PostgresResumeShareRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreateShare creates a new share link
+func (r *PostgresResumeShareRepository) CreateShare(ctx context.Context, share *domain.ResumeShare) error {
+	// Set default values if not provided
+	if share.ID == uuid.Nil {
+		share.ID = uuid.New()
+	}
+	if share.CreatedAt.IsZero() {
+		share.CreatedAt = time.Now().UTC()
+	}
+	if share.Hidden == nil {
+		share.Hidden = []string{}
+	}
+
+	err := r.queries.CreateResumeShare(ctx, dbgen.CreateResumeShareParams{
+		ID:             share.ID,
+		ResumeID:       share.ResumeID,
+		Token:          share.Token,
+		PassphraseHash: sql.NullString{String: share.PassphraseHash, Valid: share.PassphraseHash != ""},
+		MaxViews:       nullMaxViews(share.MaxViews),
+		ExpiresAt:      nullTime(share.ExpiresAt),
+		Hidden:         share.Hidden,
+		CreatedAt:      share.CreatedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", share.ResumeID.String()).Msg("Failed to create share link")
+		return err
+	}
+
+	return nil
+}
+
+// GetShareByID retrieves one of a resume's share links
+func (r *PostgresResumeShareRepository) GetShareByID(ctx context.Context, resumeID, id uuid.UUID) (*domain.ResumeShare, error) {
+	row, err := r.queries.GetResumeShareByID(ctx, dbgen.GetResumeShareByIDParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("share_id", id.String()).Msg("Failed to get share link by ID")
+		return nil, err
+	}
+
+	return shareFromRow(row), nil
+}
+
+// GetShareByToken retrieves a share link by its token
+func (r *PostgresResumeShareRepository) GetShareByToken(ctx context.Context, token string) (*domain.ResumeShare, error) {
+	row, err := r.queries.GetResumeShareByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get share link by token")
+		return nil, err
+	}
+
+	return shareFromRow(row), nil
+}
+
+// GetSharesByResumeID retrieves all share links of a resume, newest first
+func (r *PostgresResumeShareRepository) GetSharesByResumeID(ctx context.Context, resumeID uuid.UUID) ([]*domain.ResumeShare, error) {
+	rows, err := r.queries.GetResumeSharesByResumeID(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to get share links")
+		return nil, err
+	}
+
+	shares := make([]*domain.ResumeShare, len(rows))
+	for i, row := range rows {
+		shares[i] = shareFromRow(row)
+	}
+
+	return shares, nil
+}
+
+// UpdateShare updates the view limit, expiry and hidden fields of a share link
+func (r *PostgresResumeShareRepository) UpdateShare(ctx context.Context, share *domain.ResumeShare) error {
+	if share.Hidden == nil {
+		share.Hidden = []string{}
+	}
+
+	rowsAffected, err := r.queries.UpdateResumeShare(ctx, dbgen.UpdateResumeShareParams{
+		MaxViews:  nullMaxViews(share.MaxViews),
+		ExpiresAt: nullTime(share.ExpiresAt),
+		Hidden:    share.Hidden,
+		ID:        share.ID,
+		ResumeID:  share.ResumeID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("share_id", share.ID.String()).Msg("Failed to update share link")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteShare deletes one of a resume's share links
+func (r *PostgresResumeShareRepository) DeleteShare(ctx context.Context, resumeID, id uuid.UUID) error {
+	rowsAffected, err := r.queries.DeleteResumeShare(ctx, dbgen.DeleteResumeShareParams{
+		ID:       id,
+		ResumeID: resumeID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("share_id", id.String()).Msg("Failed to delete share link")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// shareFromRow converts a generated share link row to the domain model
+func shareFromRow(row dbgen.ResumeShare) *domain.ResumeShare {
+	return &domain.ResumeShare{
+		ID:             row.ID,
+		ResumeID:       row.ResumeID,
+		Token:          row.Token,
+		PassphraseHash: row.PassphraseHash.String,
+		ShareLimits: domain.ShareLimits{
+			MaxViews:  int(row.MaxViews.Int32),
+			ExpiresAt: row.ExpiresAt.Time,
+		},
+		Hidden:    row.Hidden,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// nullMaxViews stores an unlimited view count as NULL
+func nullMaxViews(maxViews int) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(maxViews), Valid: maxViews > 0}
+}
+
+// DeleteSharesByResumeID deletes every share link of a resume, returning
+// their IDs
+func (r *PostgresResumeShareRepository) DeleteSharesByResumeID(ctx context.Context, resumeID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := queriesFor(ctx, r.queries).DeleteResumeSharesByResumeID(ctx, resumeID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to delete share links")
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresRoleProfileRepository implements the RoleProfileRepository interface using PostgreSQL
+type PostgresRoleProfileRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresRoleProfileRepository creates a new PostgreSQL role profile repository
+func NewPostgresRoleProfileRepository(db *sqlx.DB) *PostgresRoleProfileRepository {
+	return &PostgresRoleProfileRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// ListRoleProfiles retrieves every saved profile, by slug
+func (r *PostgresRoleProfileRepository) ListRoleProfiles(ctx context.Context) ([]*domain.RoleProfile, error) {
+	rows, err := r.queries.ListRoleProfiles(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to list role profiles")
+		return nil, err
+	}
+
+	profiles := make([]*domain.RoleProfile, 0, len(rows))
+	for _, row := range rows {
+		var profile domain.RoleProfile
+		if err := json.Unmarshal(row.Profile, &profile); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("slug", row.Slug).Msg("Failed to decode role profile")
+			return nil, err
+		}
+		profile.Slug = row.Slug
+		profile.Source = domain.RoleSourceOverride
+		profile.UpdatedBy = row.UpdatedBy.UUID
+		profile.UpdatedAt = row.UpdatedAt
+		profiles = append(profiles, &profile)
+	}
+
+	return profiles, nil
+}
+
+// SaveRoleProfile creates or replaces a profile
+func (r *PostgresRoleProfileRepository) SaveRoleProfile(ctx context.Context, profile *domain.RoleProfile) error {
+	// Set default values if not provided
+	if profile.UpdatedAt.IsZero() {
+		profile.UpdatedAt = time.Now().UTC()
+	}
+
+	// The slug, source and audit fields have columns of their own
+	stored := *profile
+	stored.Slug, stored.Source, stored.UpdatedBy, stored.UpdatedAt = "", "", uuid.Nil, time.Time{}
+	value, err := json.Marshal(&stored)
+	if err != nil {
+		return err
+	}
+
+	err = r.queries.SaveRoleProfile(ctx, dbgen.SaveRoleProfileParams{
+		Slug:      profile.Slug,
+		Profile:   value,
+		UpdatedBy: uuid.NullUUID{UUID: profile.UpdatedBy, Valid: profile.UpdatedBy != uuid.Nil},
+		UpdatedAt: profile.UpdatedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("slug", profile.Slug).Msg("Failed to save role profile")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteRoleProfile deletes a saved profile
+func (r *PostgresRoleProfileRepository) DeleteRoleProfile(ctx context.Context, slug string) error {
+	rowsAffected, err := r.queries.DeleteRoleProfile(ctx, slug)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("slug", slug).Msg("Failed to delete role profile")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresServiceAccountRepository implements the ServiceAccountRepository interface using PostgreSQL
+type PostgresServiceAccountRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresServiceAccountRepository creates a new PostgreSQL service account repository
+func NewPostgresServiceAccountRepository(db *sqlx.DB) *PostgresServiceAccountRepository {
+	return &PostgresServiceAccountRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// CreateServiceAccount creates a new service account
+func (r *PostgresServiceAccountRepository) CreateServiceAccount(ctx context.Context, account *domain.ServiceAccount) error {
+	// Set default values if not provided
+	if account.ID == uuid.Nil {
+		account.ID = uuid.New()
+	}
+	if account.CreatedAt.IsZero() {
+		account.CreatedAt = time.Now().UTC()
+	}
+	if account.Scopes == nil {
+		account.Scopes = []string{}
+	}
+
+	err := queriesFor(ctx, r.queries).CreateServiceAccount(ctx, dbgen.CreateServiceAccountParams{
+		ID:          account.ID,
+		Name:        account.Name,
+		Description: account.Description,
+		Prefix:      account.Prefix,
+		KeyHash:     account.KeyHash,
+		Scopes:      account.Scopes,
+		ExpiresAt:   nullTime(account.ExpiresAt),
+		CreatedBy:   uuid.NullUUID{UUID: account.CreatedBy, Valid: account.CreatedBy != uuid.Nil},
+		CreatedAt:   account.CreatedAt,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Ctx(ctx).Error().Err(err).Str("name", account.Name).Msg("Failed to create service account")
+		return err
+	}
+
+	return nil
+}
+
+// GetServiceAccount retrieves a service account by ID
+func (r *PostgresServiceAccountRepository) GetServiceAccount(ctx context.Context, id uuid.UUID) (*domain.ServiceAccount, error) {
+	row, err := queriesFor(ctx, r.queries).GetServiceAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("service_account_id", id.String()).Msg("Failed to get service account")
+		return nil, err
+	}
+
+	return serviceAccountFromRow(row), nil
+}
+
+// GetServiceAccountByHash retrieves a service account by the hash of its key
+func (r *PostgresServiceAccountRepository) GetServiceAccountByHash(ctx context.Context, keyHash string) (*domain.ServiceAccount, error) {
+	row, err := queriesFor(ctx, r.queries).GetServiceAccountByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get service account by hash")
+		return nil, err
+	}
+
+	return serviceAccountFromRow(row), nil
+}
+
+// ListServiceAccounts retrieves all service accounts, newest first
+func (r *PostgresServiceAccountRepository) ListServiceAccounts(ctx context.Context) ([]*domain.ServiceAccount, error) {
+	rows, err := queriesFor(ctx, r.queries).ListServiceAccounts(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to list service accounts")
+		return nil, err
+	}
+
+	accounts := make([]*domain.ServiceAccount, len(rows))
+	for i, row := range rows {
+		accounts[i] = serviceAccountFromRow(row)
+	}
+
+	return accounts, nil
+}
+
+// UpdateServiceAccountKey replaces the key of a service account
+func (r *PostgresServiceAccountRepository) UpdateServiceAccountKey(ctx context.Context, id uuid.UUID, prefix, keyHash string) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateServiceAccountKey(ctx, dbgen.UpdateServiceAccountKeyParams{
+		Prefix:  prefix,
+		KeyHash: keyHash,
+		ID:      id,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("service_account_id", id.String()).Msg("Failed to update service account key")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// TouchServiceAccount records when a service account was last used
+func (r *PostgresServiceAccountRepository) TouchServiceAccount(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	err := queriesFor(ctx, r.queries).TouchServiceAccount(ctx, dbgen.TouchServiceAccountParams{
+		LastUsedAt: nullTime(usedAt),
+		ID:         id,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("service_account_id", id.String()).Msg("Failed to update service account")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteServiceAccount deletes a service account; its audit trail is kept
+func (r *PostgresServiceAccountRepository) DeleteServiceAccount(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteServiceAccount(ctx, id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("service_account_id", id.String()).Msg("Failed to delete service account")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// AddServiceAccountAuditEntry adds an entry to the audit trail of a service account
+func (r *PostgresServiceAccountRepository) AddServiceAccountAuditEntry(ctx context.Context, entry *domain.ServiceAccountAuditEntry) error {
+	// Set default values if not provided
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	if entry.Details == nil {
+		entry.Details = []byte("{}")
+	}
+
+	err := queriesFor(ctx, r.queries).AddServiceAccountAuditEntry(ctx, dbgen.AddServiceAccountAuditEntryParams{
+		ID:               entry.ID,
+		ServiceAccountID: entry.ServiceAccountID,
+		ActorID:          uuid.NullUUID{UUID: entry.ActorID, Valid: entry.ActorID != uuid.Nil},
+		Action:           entry.Action,
+		Details:          entry.Details,
+		CreatedAt:        entry.CreatedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("service_account_id", entry.ServiceAccountID.String()).Str("action", entry.Action).Msg("Failed to add service account audit entry")
+		return err
+	}
+
+	return nil
+}
+
+// GetServiceAccountAuditEntries retrieves up to limit audit entries of a
+// service account, newest first
+func (r *PostgresServiceAccountRepository) GetServiceAccountAuditEntries(ctx context.Context, serviceAccountID uuid.UUID, limit int) ([]*domain.ServiceAccountAuditEntry, error) {
+	rows, err := queriesFor(ctx, r.queries).GetServiceAccountAuditEntries(ctx, dbgen.GetServiceAccountAuditEntriesParams{
+		ServiceAccountID: serviceAccountID,
+		Limit:            int32(limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("service_account_id", serviceAccountID.String()).Msg("Failed to get service account audit entries")
+		return nil, err
+	}
+
+	entries := make([]*domain.ServiceAccountAuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = &domain.ServiceAccountAuditEntry{
+			ID:               row.ID,
+			ServiceAccountID: row.ServiceAccountID,
+			ActorID:          row.ActorID.UUID,
+			Action:           row.Action,
+			Details:          row.Details,
+			CreatedAt:        row.CreatedAt,
+		}
+	}
+
+	return entries, nil
+}
+
+// serviceAccountFromRow converts a generated service account row to the domain model
+func serviceAccountFromRow(row dbgen.ServiceAccount) *domain.ServiceAccount {
+	return &domain.ServiceAccount{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		Prefix:      row.Prefix,
+		KeyHash:     row.KeyHash,
+		Scopes:      row.Scopes,
+		ExpiresAt:   row.ExpiresAt.Time,
+		LastUsedAt:  row.LastUsedAt.Time,
+		CreatedBy:   row.CreatedBy.UUID,
+		CreatedAt:   row.CreatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresStatsRepository implements the StatsRepository interface using PostgreSQL
+type PostgresStatsRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+	// documents counts resumes in resume_documents rather than resumes
+	documents bool
+}
+
+// NewPostgresStatsRepository creates a new PostgreSQL stats repository.
+// Resumes are counted in resume_documents when documents is true, as kept
+// by the document storage mode.
+func NewPostgresStatsRepository(db *sqlx.DB, documents bool) *PostgresStatsRepository {
+	return &PostgresStatsRepository{
+		db:        db,
+		queries:   newQueries(db),
+		documents: documents,
+	}
+}
+
+// CountActiveSessions counts the sessions not expired at now
+func (r *PostgresStatsRepository) CountActiveSessions(ctx context.Context, now time.Time) (int64, error) {
+	count, err := r.queries.CountActiveSessions(ctx, now)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count active sessions")
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetResumesPerUser counts users by how many resumes they have
+func (r *PostgresStatsRepository) GetResumesPerUser(ctx context.Context) ([]domain.ResumeCount, error) {
+	var counts []domain.ResumeCount
+	if r.documents {
+		rows, err := r.queries.GetResumeDocumentsPerUser(ctx)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Failed to count resume documents per user")
+			return nil, err
+		}
+		for _, row := range rows {
+			counts = append(counts, domain.ResumeCount{Resumes: row.Resumes, Users: row.Users})
+		}
+		return counts, nil
+	}
+
+	rows, err := r.queries.GetResumesPerUser(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count resumes per user")
+		return nil, err
+	}
+	for _, row := range rows {
+		counts = append(counts, domain.ResumeCount{Resumes: row.Resumes, Users: row.Users})
+	}
+	return counts, nil
+}
+
+// GetExportsPerDay counts exports per UTC day since a time
+func (r *PostgresStatsRepository) GetExportsPerDay(ctx context.Context, since time.Time) ([]domain.PeriodCount, error) {
+	rows, err := r.queries.GetExportsPerDay(ctx, since)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count exports per day")
+		return nil, err
+	}
+
+	counts := make([]domain.PeriodCount, len(rows))
+	for i, row := range rows {
+		counts[i] = domain.PeriodCount{Start: row.Day.UTC(), Count: row.Exports}
+	}
+	return counts, nil
+}
+
+// GetSignupsPerWeek counts new users per week since a time
+func (r *PostgresStatsRepository) GetSignupsPerWeek(ctx context.Context, since time.Time) ([]domain.PeriodCount, error) {
+	rows, err := r.queries.GetSignupsPerWeek(ctx, since)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count signups per week")
+		return nil, err
+	}
+
+	counts := make([]domain.PeriodCount, len(rows))
+	for i, row := range rows {
+		counts[i] = domain.PeriodCount{Start: row.Week.UTC(), Count: row.Signups}
+	}
+	return counts, nil
+}
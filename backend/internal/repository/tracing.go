@@ -0,0 +1,8 @@
+package repository
+
+import "go.opentelemetry.io/otel"
+
+// tracer starts spans around repository calls that run several queries, so
+// traces show the time spent in each beside the per-query spans of the
+// instrumented database driver
+var tracer = otel.Tracer("github.com/lordaris/resume_generator/internal/repository")
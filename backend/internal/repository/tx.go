@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// txKey is the context key of the transaction started by WithinTx
+type txKey struct{}
+
+// txState is a transaction in progress and what runs once it commits
+type txState struct {
+	tx *sql.Tx
+
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+// TxManager runs units of work in a database transaction. Repository calls
+// made with the context WithinTx passes its function run in the
+// transaction, so several calls, across repositories, commit or roll back
+// together.
+type TxManager struct {
+	db *sqlx.DB
+}
+
+// NewTxManager creates a transaction manager for the database
+func NewTxManager(db *sqlx.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx runs fn in a transaction, committing it when fn returns nil and
+// rolling it back when fn returns an error or panics. Called inside another
+// WithinTx, fn joins the outer transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if InTx(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	state := &txState{tx: tx}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to commit transaction")
+		return err
+	}
+	committed = true
+
+	for _, hook := range state.afterCommit {
+		hook()
+	}
+	return nil
+}
+
+// InTx reports whether ctx carries a transaction started by WithinTx
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// AfterCommit runs fn once the transaction in ctx commits, and never if it
+// rolls back. Outside a transaction fn runs straight away.
+func AfterCommit(ctx context.Context, fn func()) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		fn()
+		return
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.afterCommit = append(state.afterCommit, fn)
+}
+
+// queriesFor returns queries that run in the transaction in ctx, if any
+func queriesFor(ctx context.Context, queries *dbgen.Queries) *dbgen.Queries {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return queries.WithTx(state.tx)
+	}
+	return queries
+}
+
+// localTx is the transaction of a repository method that writes several
+// rows. Inside WithinTx it is the outer transaction, and Commit and Rollback
+// leave it to WithinTx to finish.
+type localTx struct {
+	*sql.Tx
+	joined bool
+}
+
+// beginTx starts a transaction for a repository method, or joins the one
+// in ctx
+func beginTx(ctx context.Context, db *sqlx.DB) (*localTx, error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return &localTx{Tx: state.tx, joined: true}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &localTx{Tx: tx}, nil
+}
+
+// Commit commits the transaction unless it was joined
+func (t *localTx) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback rolls the transaction back unless it was joined. A method that
+// fails inside WithinTx returns its error, which rolls the outer
+// transaction back.
+func (t *localTx) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
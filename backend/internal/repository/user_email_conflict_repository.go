@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresUserEmailConflictRepository implements the UserEmailConflictRepository interface using PostgreSQL
+type PostgresUserEmailConflictRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresUserEmailConflictRepository creates a new PostgreSQL user email conflict repository
+func NewPostgresUserEmailConflictRepository(db *sqlx.DB) *PostgresUserEmailConflictRepository {
+	return &PostgresUserEmailConflictRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// GetConflictsByEmail returns the accounts set aside for an address, oldest first
+func (r *PostgresUserEmailConflictRepository) GetConflictsByEmail(ctx context.Context, email string) ([]*domain.UserEmailConflict, error) {
+	rows, err := queriesFor(ctx, r.queries).ListUserEmailConflicts(ctx, email)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get user email conflicts")
+		return nil, err
+	}
+
+	conflicts := make([]*domain.UserEmailConflict, 0, len(rows))
+	for _, row := range rows {
+		conflicts = append(conflicts, &domain.UserEmailConflict{
+			UserID:    row.UserID,
+			Email:     row.Email,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return conflicts, nil
+}
+
+// MergeUser moves the resume data of fromID to intoID and deletes fromID in
+// one transaction
+func (r *PostgresUserEmailConflictRepository) MergeUser(ctx context.Context, fromID, intoID uuid.UUID) (err error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	qtx := r.queries.WithTx(tx.Tx)
+
+	moves := []func() error{
+		func() error {
+			return qtx.MoveResumesToUser(ctx, dbgen.MoveResumesToUserParams{ToUserID: intoID, FromUserID: fromID})
+		},
+		func() error {
+			return qtx.MoveResumeDocumentsToUser(ctx, dbgen.MoveResumeDocumentsToUserParams{ToUserID: intoID, FromUserID: fromID})
+		},
+		func() error {
+			return qtx.MoveResumeNotesToUser(ctx, dbgen.MoveResumeNotesToUserParams{ToUserID: intoID, FromUserID: fromID})
+		},
+		func() error {
+			return qtx.MoveResumePublicationsToUser(ctx, dbgen.MoveResumePublicationsToUserParams{ToUserID: intoID, FromUserID: fromID})
+		},
+		func() error {
+			return qtx.MoveConnectorsToUser(ctx, dbgen.MoveConnectorsToUserParams{ToUserID: intoID, FromUserID: fromID})
+		},
+		func() error {
+			return qtx.MoveResumeEventsToOwner(ctx, dbgen.MoveResumeEventsToOwnerParams{ToUserID: intoID, FromUserID: fromID})
+		},
+	}
+	for _, move := range moves {
+		if err = move(); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("user_id", fromID.String()).Msg("Failed to move resume data of merged user")
+			return err
+		}
+	}
+
+	// The conflict row, sessions and credentials go with the user
+	var rowsAffected int64
+	if rowsAffected, err = qtx.DeleteUser(ctx, fromID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", fromID.String()).Msg("Failed to delete merged user")
+		return err
+	}
+	if rowsAffected == 0 {
+		err = ErrNotFound
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to commit transaction")
+		return err
+	}
+	return nil
+}
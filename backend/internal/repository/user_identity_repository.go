@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresUserIdentityRepository implements the UserIdentityRepository interface using PostgreSQL
+type PostgresUserIdentityRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresUserIdentityRepository creates a new PostgreSQL user identity repository
+func NewPostgresUserIdentityRepository(db *sqlx.DB) *PostgresUserIdentityRepository {
+	return &PostgresUserIdentityRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// GetIdentity retrieves the identity of a provider account
+func (r *PostgresUserIdentityRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	row, err := queriesFor(ctx, r.queries).GetUserIdentity(ctx, dbgen.GetUserIdentityParams{Provider: provider, Subject: subject})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("provider", provider).Msg("Failed to get user identity")
+		return nil, err
+	}
+
+	return &domain.UserIdentity{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Provider:    row.Provider,
+		Subject:     row.Subject,
+		Email:       row.Email,
+		CreatedAt:   row.CreatedAt,
+		LastLoginAt: row.LastLoginAt,
+	}, nil
+}
+
+// GetIdentitiesByUserID retrieves the provider accounts linked to a user, oldest first
+func (r *PostgresUserIdentityRepository) GetIdentitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.UserIdentity, error) {
+	rows, err := queriesFor(ctx, r.queries).GetUserIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get user identities")
+		return nil, err
+	}
+
+	identities := make([]*domain.UserIdentity, len(rows))
+	for i, row := range rows {
+		identities[i] = &domain.UserIdentity{
+			ID:          row.ID,
+			UserID:      row.UserID,
+			Provider:    row.Provider,
+			Subject:     row.Subject,
+			Email:       row.Email,
+			CreatedAt:   row.CreatedAt,
+			LastLoginAt: row.LastLoginAt,
+		}
+	}
+	return identities, nil
+}
+
+// CreateIdentity links a provider account to an existing user
+func (r *PostgresUserIdentityRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	if err := createIdentity(ctx, queriesFor(ctx, r.queries), identity); err != nil {
+		if isDuplicateKeyError(err) {
+			return conflictError(err)
+		}
+		log.Ctx(ctx).Error().Err(err).Str("provider", identity.Provider).Msg("Failed to create user identity")
+		return err
+	}
+
+	return nil
+}
+
+// CreateUserWithIdentity creates a user and links the identity to it in one transaction
+func (r *PostgresUserIdentityRepository) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	qtx := r.queries.WithTx(tx.Tx)
+
+	if err = createUser(ctx, qtx, user); err != nil {
+		if isDuplicateKeyError(err) {
+			return conflictError(err)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create user")
+		return err
+	}
+
+	identity.UserID = user.ID
+	if err = createIdentity(ctx, qtx, identity); err != nil {
+		if isDuplicateKeyError(err) {
+			return conflictError(err)
+		}
+		log.Ctx(ctx).Error().Err(err).Str("provider", identity.Provider).Msg("Failed to create user identity")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// TouchIdentity records a sign-in, storing the account's current email
+func (r *PostgresUserIdentityRepository) TouchIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	identity.LastLoginAt = time.Now().UTC()
+
+	rowsAffected, err := queriesFor(ctx, r.queries).TouchUserIdentity(ctx, dbgen.TouchUserIdentityParams{
+		Email:       identity.Email,
+		LastLoginAt: identity.LastLoginAt,
+		ID:          identity.ID,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("provider", identity.Provider).Msg("Failed to update user identity")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// createIdentity fills in the defaults of a new identity and inserts it
+func createIdentity(ctx context.Context, queries *dbgen.Queries, identity *domain.UserIdentity) error {
+	// Set default values if not provided
+	if identity.ID == uuid.Nil {
+		identity.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if identity.CreatedAt.IsZero() {
+		identity.CreatedAt = now
+	}
+	if identity.LastLoginAt.IsZero() {
+		identity.LastLoginAt = now
+	}
+
+	return queries.CreateUserIdentity(ctx, dbgen.CreateUserIdentityParams{
+		ID:          identity.ID,
+		UserID:      identity.UserID,
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	})
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// PostgresUserPhoneRepository implements the UserPhoneRepository interface using PostgreSQL
+type PostgresUserPhoneRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresUserPhoneRepository creates a new PostgreSQL user phone repository
+func NewPostgresUserPhoneRepository(db *sqlx.DB) *PostgresUserPhoneRepository {
+	return &PostgresUserPhoneRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// SavePhone sets a user's phone number, replacing any earlier one and
+// clearing its verification
+func (r *PostgresUserPhoneRepository) SavePhone(ctx context.Context, phone *domain.UserPhone) error {
+	row, err := queriesFor(ctx, r.queries).UpsertUserPhone(ctx, dbgen.UpsertUserPhoneParams{
+		UserID:    phone.UserID,
+		Phone:     phone.Phone,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", phone.UserID.String()).Msg("Failed to save user phone")
+		return err
+	}
+
+	*phone = *userPhoneFromRow(row)
+	return nil
+}
+
+// GetPhone retrieves a user's phone number
+func (r *PostgresUserPhoneRepository) GetPhone(ctx context.Context, userID uuid.UUID) (*domain.UserPhone, error) {
+	row, err := queriesFor(ctx, r.queries).GetUserPhone(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get user phone")
+		return nil, err
+	}
+
+	return userPhoneFromRow(row), nil
+}
+
+// VerifyPhone marks a user's number verified. It returns ErrNotFound when the
+// user has no number or it has changed from number.
+func (r *PostgresUserPhoneRepository) VerifyPhone(ctx context.Context, userID uuid.UUID, number string) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).VerifyUserPhone(ctx, dbgen.VerifyUserPhoneParams{
+		UserID:     userID,
+		Phone:      number,
+		VerifiedAt: nullTime(time.Now().UTC()),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to verify user phone")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeletePhone removes a user's phone number
+func (r *PostgresUserPhoneRepository) DeletePhone(ctx context.Context, userID uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteUserPhone(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to delete user phone")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// userPhoneFromRow converts a user_phones row
+func userPhoneFromRow(row dbgen.UserPhone) *domain.UserPhone {
+	return &domain.UserPhone{
+		UserID:     row.UserID,
+		Phone:      row.Phone,
+		VerifiedAt: row.VerifiedAt.Time,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+}
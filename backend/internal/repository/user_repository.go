@@ -1,13 +1,17 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
 	"github.com/rs/zerolog/log"
 )
 
@@ -17,26 +21,43 @@ var (
 	ErrConflict = errors.New("record already exists")
 )
 
+// Conflicts with a known unique constraint. Each matches ErrConflict too.
+var (
+	ErrDuplicateEmail    = fmt.Errorf("%w: email is taken", ErrConflict)
+	ErrDuplicateIdentity = fmt.Errorf("%w: provider account is linked", ErrConflict)
+)
+
 // PostgresUserRepository implements the UserRepository interface using PostgreSQL
 type PostgresUserRepository struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	queries *dbgen.Queries
 }
 
 // NewPostgresUserRepository creates a new PostgreSQL user repository
 func NewPostgresUserRepository(db *sqlx.DB) *PostgresUserRepository {
 	return &PostgresUserRepository{
-		db: db,
+		db:      db,
+		queries: newQueries(db),
 	}
 }
 
 // CreateUser creates a new user
-func (r *PostgresUserRepository) CreateUser(user *domain.User) error {
-	query := `
-		INSERT INTO users (id, email, password_hash, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id
-	`
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	err := createUser(ctx, queriesFor(ctx, r.queries), user)
+	if err != nil {
+		// Check for duplicate email
+		if isDuplicateKeyError(err) {
+			return conflictError(err)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create user")
+		return err
+	}
+
+	return nil
+}
 
+// createUser fills in the defaults of a new user and inserts it
+func createUser(ctx context.Context, queries *dbgen.Queries, user *domain.User) error {
 	// Set default values if not provided
 	if user.ID == uuid.Nil {
 		user.ID = uuid.New()
@@ -44,7 +65,10 @@ func (r *PostgresUserRepository) CreateUser(user *domain.User) error {
 	if user.Role == "" {
 		user.Role = "user" // Default role
 	}
-	now := time.Now()
+	if user.Timezone == "" {
+		user.Timezone = domain.DefaultTimezone
+	}
+	now := time.Now().UTC()
 	if user.CreatedAt.IsZero() {
 		user.CreatedAt = now
 	}
@@ -52,103 +76,119 @@ func (r *PostgresUserRepository) CreateUser(user *domain.User) error {
 		user.UpdatedAt = now
 	}
 
-	var id uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		user.ID,
-		user.Email,
-		user.PasswordHash,
-		user.Role,
-		user.CreatedAt,
-		user.UpdatedAt,
-	).Scan(&id)
-	if err != nil {
-		// Check for duplicate email
-		if isDuplicateKeyError(err) {
-			log.Error().Err(err).Str("email", user.Email).Msg("Failed to create user: duplicate email")
-			return ErrConflict
-		}
-		log.Error().Err(err).Msg("Failed to create user")
-		return err
-	}
-
-	return nil
+	return queries.CreateUser(ctx, dbgen.CreateUserParams{
+		ID:           user.ID,
+		Email:        user.Email,
+		PasswordHash: user.PasswordHash,
+		Role:         user.Role,
+		Timezone:     user.Timezone,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+	})
 }
 
 // GetUserByID retrieves a user by ID
-func (r *PostgresUserRepository) GetUserByID(id uuid.UUID) (*domain.User, error) {
-	query := `
-		SELECT id, email, password_hash, role, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
-
-	var user domain.User
-	err := r.db.Get(&user, query, id)
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	row, err := queriesFor(ctx, r.queries).GetUserByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("user_id", id.String()).Msg("Failed to get user by ID")
+		log.Ctx(ctx).Error().Err(err).Str("user_id", id.String()).Msg("Failed to get user by ID")
 		return nil, err
 	}
 
-	return &user, nil
+	return userFromRow(row), nil
 }
 
 // GetUserByEmail retrieves a user by email
-func (r *PostgresUserRepository) GetUserByEmail(email string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password_hash, role, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-
-	var user domain.User
-	err := r.db.Get(&user, query, email)
+func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	row, err := queriesFor(ctx, r.queries).GetUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("email", email).Msg("Failed to get user by email")
+		log.Ctx(ctx).Error().Err(err).Str("email", email).Msg("Failed to get user by email")
 		return nil, err
 	}
 
-	return &user, nil
+	return userFromRow(row), nil
 }
 
-// UpdateUser updates a user
-func (r *PostgresUserRepository) UpdateUser(user *domain.User) error {
-	query := `
-		UPDATE users
-		SET email = $1, password_hash = $2, role = $3, updated_at = $4
-		WHERE id = $5
-	`
+// ListUsers retrieves a page of users and the total matching the filters
+func (r *PostgresUserRepository) ListUsers(ctx context.Context, opts domain.ListOptions) ([]*domain.User, int64, error) {
+	total, err := queriesFor(ctx, r.queries).CountUsers(ctx, dbgen.CountUsersParams{
+		CreatedAfter:  nullTime(opts.CreatedAfter),
+		CreatedBefore: nullTime(opts.CreatedBefore),
+		Search:        searchPattern(opts.Search),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count users")
+		return nil, 0, err
+	}
+
+	rows, err := queriesFor(ctx, r.queries).ListUsers(ctx, dbgen.ListUsersParams{
+		CreatedAfter:  nullTime(opts.CreatedAfter),
+		CreatedBefore: nullTime(opts.CreatedBefore),
+		Search:        searchPattern(opts.Search),
+		SortBy:        opts.SortBy,
+		SortDesc:      opts.SortDesc,
+		RowOffset:     int32(opts.Offset),
+		RowLimit:      int32(opts.Limit),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to list users")
+		return nil, 0, err
+	}
+
+	users := make([]*domain.User, len(rows))
+	for i, row := range rows {
+		users[i] = userFromRow(row)
+	}
+
+	return users, total, nil
+}
 
+// UpdateUser updates a user
+func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
 	// Update the updated_at timestamp
-	user.UpdatedAt = time.Now()
-
-	result, err := r.db.Exec(
-		query,
-		user.Email,
-		user.PasswordHash,
-		user.Role,
-		user.UpdatedAt,
-		user.ID,
-	)
+	user.UpdatedAt = time.Now().UTC()
+	if user.Timezone == "" {
+		user.Timezone = domain.DefaultTimezone
+	}
+
+	rowsAffected, err := queriesFor(ctx, r.queries).UpdateUser(ctx, dbgen.UpdateUserParams{
+		Email:        user.Email,
+		PasswordHash: user.PasswordHash,
+		Role:         user.Role,
+		Timezone:     user.Timezone,
+		WeeklyDigest: user.WeeklyDigest,
+		Locale:       user.Locale,
+		UpdatedAt:    user.UpdatedAt,
+		ID:           user.ID,
+	})
 	if err != nil {
 		// Check for duplicate email
 		if isDuplicateKeyError(err) {
-			log.Error().Err(err).Str("email", user.Email).Msg("Failed to update user: duplicate email")
-			return ErrConflict
+			log.Ctx(ctx).Error().Err(err).Str("email", user.Email).Msg("Failed to update user: duplicate email")
+			return conflictError(err)
 		}
-		log.Error().Err(err).Msg("Failed to update user")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update user")
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a user
+func (r *PostgresUserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteUser(ctx, id)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Str("user_id", id.String()).Msg("Failed to delete user")
 		return err
 	}
 
@@ -159,22 +199,35 @@ func (r *PostgresUserRepository) UpdateUser(user *domain.User) error {
 	return nil
 }
 
-// DeleteUser deletes a user
-func (r *PostgresUserRepository) DeleteUser(id uuid.UUID) error {
-	query := `
-		DELETE FROM users
-		WHERE id = $1
-	`
-
-	result, err := r.db.Exec(query, id)
+// ListDigestRecipients retrieves up to limit users ordered by ID after afterID
+// who opted in to the weekly digest and haven't been sent one since sentBefore
+func (r *PostgresUserRepository) ListDigestRecipients(ctx context.Context, sentBefore time.Time, afterID uuid.UUID, limit int) ([]*domain.User, error) {
+	rows, err := queriesFor(ctx, r.queries).ListDigestRecipients(ctx, dbgen.ListDigestRecipientsParams{
+		SentBefore: sentBefore.UTC(),
+		AfterID:    afterID,
+		RowLimit:   int32(limit),
+	})
 	if err != nil {
-		log.Error().Err(err).Str("user_id", id.String()).Msg("Failed to delete user")
-		return err
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to list digest recipients")
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	users := make([]*domain.User, len(rows))
+	for i, row := range rows {
+		users[i] = userFromRow(row)
+	}
+
+	return users, nil
+}
+
+// MarkDigestSent records when the user's last weekly digest was sent
+func (r *PostgresUserRepository) MarkDigestSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).MarkDigestSent(ctx, dbgen.MarkDigestSentParams{
+		DigestSentAt: sql.NullTime{Time: sentAt.UTC(), Valid: true},
+		ID:           id,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Str("user_id", id.String()).Msg("Failed to mark digest sent")
 		return err
 	}
 
@@ -186,35 +239,31 @@ func (r *PostgresUserRepository) DeleteUser(id uuid.UUID) error {
 }
 
 // CreateSession creates a new session
-func (r *PostgresUserRepository) CreateSession(session *domain.Session) error {
-	query := `
-		INSERT INTO sessions (id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id
-	`
-
+func (r *PostgresUserRepository) CreateSession(ctx context.Context, session *domain.Session) error {
 	// Set default values if not provided
 	if session.ID == uuid.Nil {
 		session.ID = uuid.New()
 	}
-	now := time.Now()
+	now := time.Now().UTC()
 	if session.CreatedAt.IsZero() {
 		session.CreatedAt = now
 	}
+	if session.LastUsedAt.IsZero() {
+		session.LastUsedAt = session.CreatedAt
+	}
 
-	var id uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		session.ID,
-		session.UserID,
-		session.RefreshToken,
-		session.UserAgent,
-		session.ClientIP,
-		session.ExpiresAt,
-		session.CreatedAt,
-	).Scan(&id)
+	err := queriesFor(ctx, r.queries).CreateSession(ctx, dbgen.CreateSessionParams{
+		ID:           session.ID,
+		UserID:       session.UserID,
+		RefreshToken: session.RefreshToken,
+		UserAgent:    session.UserAgent,
+		ClientIp:     session.ClientIP,
+		ExpiresAt:    session.ExpiresAt,
+		CreatedAt:    session.CreatedAt,
+		LastUsedAt:   session.LastUsedAt,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create session")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create session")
 		return err
 	}
 
@@ -222,63 +271,79 @@ func (r *PostgresUserRepository) CreateSession(session *domain.Session) error {
 }
 
 // GetSessionByID retrieves a session by ID
-func (r *PostgresUserRepository) GetSessionByID(id uuid.UUID) (*domain.Session, error) {
-	query := `
-		SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at
-		FROM sessions
-		WHERE id = $1
-	`
-
-	var session domain.Session
-	err := r.db.Get(&session, query, id)
+func (r *PostgresUserRepository) GetSessionByID(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	row, err := queriesFor(ctx, r.queries).GetSessionByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Str("session_id", id.String()).Msg("Failed to get session by ID")
+		log.Ctx(ctx).Error().Err(err).Str("session_id", id.String()).Msg("Failed to get session by ID")
 		return nil, err
 	}
 
-	return &session, nil
+	return sessionFromRow(row), nil
 }
 
 // GetSessionByToken retrieves a session by refresh token
-func (r *PostgresUserRepository) GetSessionByToken(token string) (*domain.Session, error) {
-	query := `
-		SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at
-		FROM sessions
-		WHERE refresh_token = $1
-	`
-
-	var session domain.Session
-	err := r.db.Get(&session, query, token)
+func (r *PostgresUserRepository) GetSessionByToken(ctx context.Context, token string) (*domain.Session, error) {
+	row, err := queriesFor(ctx, r.queries).GetSessionByToken(ctx, token)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Msg("Failed to get session by token")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get session by token")
 		return nil, err
 	}
 
-	return &session, nil
+	return sessionFromRow(row), nil
 }
 
-// DeleteSession deletes a session
-func (r *PostgresUserRepository) DeleteSession(id uuid.UUID) error {
-	query := `
-		DELETE FROM sessions
-		WHERE id = $1
-	`
+// GetSessionsByUserID retrieves all sessions for a user
+func (r *PostgresUserRepository) GetSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error) {
+	rows, err := queriesFor(ctx, r.queries).GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get sessions by user ID")
+		return nil, err
+	}
+
+	sessions := make([]*domain.Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = sessionFromRow(row)
+	}
 
-	result, err := r.db.Exec(query, id)
+	return sessions, nil
+}
+
+// RotateSession stores a session's new refresh token, client and expiry,
+// provided it still holds previousToken. A session already rotated with that
+// token, by a concurrent refresh, is reported as ErrNotFound.
+func (r *PostgresUserRepository) RotateSession(ctx context.Context, session *domain.Session, previousToken string) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).RotateSession(ctx, dbgen.RotateSessionParams{
+		NewRefreshToken: session.RefreshToken,
+		UserAgent:       session.UserAgent,
+		ClientIp:        session.ClientIP,
+		ExpiresAt:       session.ExpiresAt,
+		LastUsedAt:      session.LastUsedAt,
+		ID:              session.ID,
+		RefreshToken:    previousToken,
+	})
 	if err != nil {
-		log.Error().Err(err).Str("session_id", id.String()).Msg("Failed to delete session")
+		log.Ctx(ctx).Error().Err(err).Str("session_id", session.ID.String()).Msg("Failed to rotate session")
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteSession deletes a session
+func (r *PostgresUserRepository) DeleteSession(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteSession(ctx, id)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		log.Ctx(ctx).Error().Err(err).Str("session_id", id.String()).Msg("Failed to delete session")
 		return err
 	}
 
@@ -290,49 +355,47 @@ func (r *PostgresUserRepository) DeleteSession(id uuid.UUID) error {
 }
 
 // DeleteUserSessions deletes all sessions for a user
-func (r *PostgresUserRepository) DeleteUserSessions(userID uuid.UUID) error {
-	query := `
-		DELETE FROM sessions
-		WHERE user_id = $1
-	`
-
-	_, err := r.db.Exec(query, userID)
+func (r *PostgresUserRepository) DeleteUserSessions(ctx context.Context, userID uuid.UUID) error {
+	err := queriesFor(ctx, r.queries).DeleteUserSessions(ctx, userID)
 	if err != nil {
-		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to delete user sessions")
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to delete user sessions")
 		return err
 	}
 
 	return nil
 }
 
-// CreatePasswordReset creates a new password reset
-func (r *PostgresUserRepository) CreatePasswordReset(reset *domain.PasswordReset) error {
-	query := `
-		INSERT INTO password_resets (id, user_id, token, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id
-	`
+// DeleteExpiredSessions deletes all sessions past their expiry and returns how many were removed
+func (r *PostgresUserRepository) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteExpiredSessions(ctx, time.Now().UTC())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete expired sessions")
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
 
+// CreatePasswordReset creates a new password reset
+func (r *PostgresUserRepository) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) error {
 	// Set default values if not provided
 	if reset.ID == uuid.Nil {
 		reset.ID = uuid.New()
 	}
-	now := time.Now()
+	now := time.Now().UTC()
 	if reset.CreatedAt.IsZero() {
 		reset.CreatedAt = now
 	}
 
-	var id uuid.UUID
-	err := r.db.QueryRow(
-		query,
-		reset.ID,
-		reset.UserID,
-		reset.Token,
-		reset.ExpiresAt,
-		reset.CreatedAt,
-	).Scan(&id)
+	err := queriesFor(ctx, r.queries).CreatePasswordReset(ctx, dbgen.CreatePasswordResetParams{
+		ID:        reset.ID,
+		UserID:    reset.UserID,
+		Token:     reset.Token,
+		ExpiresAt: reset.ExpiresAt,
+		CreatedAt: reset.CreatedAt,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create password reset")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create password reset")
 		return err
 	}
 
@@ -340,44 +403,111 @@ func (r *PostgresUserRepository) CreatePasswordReset(reset *domain.PasswordReset
 }
 
 // GetPasswordResetByToken retrieves a password reset by token
-func (r *PostgresUserRepository) GetPasswordResetByToken(token string) (*domain.PasswordReset, error) {
-	query := `
-		SELECT id, user_id, token, expires_at, created_at, used_at
-		FROM password_resets
-		WHERE token = $1
-	`
-
-	var reset domain.PasswordReset
-	err := r.db.Get(&reset, query, token)
+func (r *PostgresUserRepository) GetPasswordResetByToken(ctx context.Context, token string) (*domain.PasswordReset, error) {
+	row, err := queriesFor(ctx, r.queries).GetPasswordResetByToken(ctx, token)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		log.Error().Err(err).Msg("Failed to get password reset by token")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get password reset by token")
 		return nil, err
 	}
 
-	return &reset, nil
+	return &domain.PasswordReset{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Token:     row.Token,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+		UsedAt:    row.UsedAt.Time,
+	}, nil
 }
 
 // MarkPasswordResetUsed marks a password reset as used
-func (r *PostgresUserRepository) MarkPasswordResetUsed(id uuid.UUID) error {
-	query := `
-		UPDATE password_resets
-		SET used_at = $1
-		WHERE id = $2
-	`
-
-	now := time.Now()
-	result, err := r.db.Exec(query, now, id)
+func (r *PostgresUserRepository) MarkPasswordResetUsed(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).MarkPasswordResetUsed(ctx, dbgen.MarkPasswordResetUsedParams{
+		UsedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:     id,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("reset_id", id.String()).Msg("Failed to mark password reset as used")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteExpiredPasswordResets deletes expired password resets
+func (r *PostgresUserRepository) DeleteExpiredPasswordResets(ctx context.Context) error {
+	err := queriesFor(ctx, r.queries).DeleteExpiredPasswordResets(ctx, time.Now().UTC())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete expired password resets")
+		return err
+	}
+
+	return nil
+}
+
+// CreateEmailChange creates a new pending email change
+func (r *PostgresUserRepository) CreateEmailChange(ctx context.Context, change *domain.EmailChange) error {
+	// Set default values if not provided
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now().UTC()
+	}
+
+	err := queriesFor(ctx, r.queries).CreateEmailChange(ctx, dbgen.CreateEmailChangeParams{
+		ID:        change.ID,
+		UserID:    change.UserID,
+		NewEmail:  change.NewEmail,
+		Token:     change.Token,
+		ExpiresAt: change.ExpiresAt,
+		CreatedAt: change.CreatedAt,
+	})
 	if err != nil {
-		log.Error().Err(err).Str("reset_id", id.String()).Msg("Failed to mark password reset as used")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create email change")
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return nil
+}
+
+// GetEmailChangeByToken retrieves a pending email change by token
+func (r *PostgresUserRepository) GetEmailChangeByToken(ctx context.Context, token string) (*domain.EmailChange, error) {
+	row, err := queriesFor(ctx, r.queries).GetEmailChangeByToken(ctx, token)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get rows affected")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get email change by token")
+		return nil, err
+	}
+
+	return &domain.EmailChange{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		NewEmail:    row.NewEmail,
+		Token:       row.Token,
+		ExpiresAt:   row.ExpiresAt,
+		CreatedAt:   row.CreatedAt,
+		ConfirmedAt: row.ConfirmedAt.Time,
+	}, nil
+}
+
+// MarkEmailChangeConfirmed marks an email change as confirmed
+func (r *PostgresUserRepository) MarkEmailChangeConfirmed(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).MarkEmailChangeConfirmed(ctx, dbgen.MarkEmailChangeConfirmedParams{
+		ConfirmedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:          id,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("email_change_id", id.String()).Msg("Failed to mark email change as confirmed")
 		return err
 	}
 
@@ -388,18 +518,11 @@ func (r *PostgresUserRepository) MarkPasswordResetUsed(id uuid.UUID) error {
 	return nil
 }
 
-// DeleteExpiredPasswordResets deletes expired password resets
-func (r *PostgresUserRepository) DeleteExpiredPasswordResets() error {
-	query := `
-		DELETE FROM password_resets
-		WHERE expires_at < $1
-		OR used_at IS NOT NULL
-	`
-
-	now := time.Now()
-	_, err := r.db.Exec(query, now)
+// DeleteExpiredEmailChanges deletes expired and confirmed email changes
+func (r *PostgresUserRepository) DeleteExpiredEmailChanges(ctx context.Context) error {
+	err := queriesFor(ctx, r.queries).DeleteExpiredEmailChanges(ctx, time.Now().UTC())
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to delete expired password resets")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete expired email changes")
 		return err
 	}
 
@@ -408,11 +531,63 @@ func (r *PostgresUserRepository) DeleteExpiredPasswordResets() error {
 
 // Helper functions
 
-// isDuplicateKeyError checks if an error is a duplicate key error
+// userFromRow converts a generated user row to the domain model
+func userFromRow(row dbgen.User) *domain.User {
+	return &domain.User{
+		ID:              row.ID,
+		Email:           row.Email,
+		PasswordHash:    row.PasswordHash,
+		Role:            row.Role,
+		Timezone:        row.Timezone,
+		WeeklyDigest:    row.WeeklyDigest,
+		Locale:          row.Locale,
+		DigestSentAt:    row.DigestSentAt.Time,
+		PasskeyRequired: row.PasskeyRequired,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+}
+
+// sessionFromRow converts a generated session row to the domain model
+func sessionFromRow(row dbgen.Session) *domain.Session {
+	return &domain.Session{
+		ID:           row.ID,
+		UserID:       row.UserID,
+		RefreshToken: row.RefreshToken,
+		UserAgent:    row.UserAgent,
+		ClientIP:     row.ClientIp,
+		ExpiresAt:    row.ExpiresAt,
+		CreatedAt:    row.CreatedAt,
+		LastUsedAt:   row.LastUsedAt,
+	}
+}
+
+// uniqueViolation is the SQLSTATE of a unique constraint violation
+const uniqueViolation = "23505"
+
+// constraintConflicts are the errors of unique constraints callers tell
+// apart, by constraint or unique index name
+var constraintConflicts = map[string]error{
+	"users_email_key":                      ErrDuplicateEmail,
+	"idx_users_email_lower":                ErrDuplicateEmail,
+	"user_identities_provider_subject_key": ErrDuplicateIdentity,
+}
+
+// isDuplicateKeyError checks if an error is a duplicate key error. The
+// driver's message names the constraint, so the SQLSTATE is checked instead.
 func isDuplicateKeyError(err error) bool {
-	// PostgreSQL error code for unique_violation is 23505
-	// This is not an elegant solution, but it works
-	return err != nil && err.Error() != "" &&
-		(err.Error() == "pq: duplicate key value violates unique constraint" ||
-			err.Error() == "ERROR: duplicate key value violates unique constraint (SQLSTATE 23505)")
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// conflictError returns the conflict a duplicate key error reports:
+// the constraint's own error when callers tell it apart, else ErrConflict
+func conflictError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if conflict, ok := constraintConflicts[pqErr.Constraint]; ok {
+			return conflict
+		}
+	}
+	return ErrConflict
 }
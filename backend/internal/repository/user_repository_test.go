@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	duplicate := &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "users_email_key"`}
+	assert.True(t, isDuplicateKeyError(duplicate))
+	assert.True(t, isDuplicateKeyError(fmt.Errorf("create user: %w", duplicate)))
+
+	assert.False(t, isDuplicateKeyError(&pq.Error{Code: "23503", Message: "violates foreign key constraint"}))
+	assert.False(t, isDuplicateKeyError(errors.New("pq: duplicate key value violates unique constraint")))
+	assert.False(t, isDuplicateKeyError(nil))
+}
+
+func TestConflictError(t *testing.T) {
+	assert.ErrorIs(t, conflictError(&pq.Error{Code: "23505", Constraint: "idx_users_email_lower"}), ErrDuplicateEmail)
+	assert.ErrorIs(t, conflictError(&pq.Error{Code: "23505", Constraint: "user_identities_provider_subject_key"}), ErrDuplicateIdentity)
+
+	// Every conflict is still ErrConflict
+	assert.ErrorIs(t, ErrDuplicateEmail, ErrConflict)
+	other := conflictError(&pq.Error{Code: "23505", Constraint: "uq_api_keys_key_hash"})
+	assert.Equal(t, ErrConflict, other)
+	assert.NotErrorIs(t, other, ErrDuplicateEmail)
+}
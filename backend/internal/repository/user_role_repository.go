@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository/dbgen"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrRoleInUse is returned when deleting a role users still have
+var ErrRoleInUse = errors.New("role is in use")
+
+// foreignKeyViolation is the SQLSTATE of a foreign key constraint violation
+const foreignKeyViolation = "23503"
+
+// PostgresUserRoleRepository implements the UserRoleRepository interface using PostgreSQL
+type PostgresUserRoleRepository struct {
+	db      *sqlx.DB
+	queries *dbgen.Queries
+}
+
+// NewPostgresUserRoleRepository creates a new PostgreSQL user role repository
+func NewPostgresUserRoleRepository(db *sqlx.DB) *PostgresUserRoleRepository {
+	return &PostgresUserRoleRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// ListUserRoles retrieves every role, by name
+func (r *PostgresUserRoleRepository) ListUserRoles(ctx context.Context) ([]*domain.UserRole, error) {
+	rows, err := r.queries.ListUserRoles(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to list user roles")
+		return nil, err
+	}
+
+	roles := make([]*domain.UserRole, len(rows))
+	for i, row := range rows {
+		roles[i] = &domain.UserRole{
+			Name:        row.Name,
+			Description: row.Description,
+			Permissions: row.Permissions,
+			UpdatedAt:   row.UpdatedAt,
+		}
+	}
+	return roles, nil
+}
+
+// SaveUserRole creates a role or replaces the one with its name
+func (r *PostgresUserRoleRepository) SaveUserRole(ctx context.Context, role *domain.UserRole) error {
+	// Set default values if not provided
+	if role.UpdatedAt.IsZero() {
+		role.UpdatedAt = time.Now().UTC()
+	}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+
+	err := queriesFor(ctx, r.queries).SaveUserRole(ctx, dbgen.SaveUserRoleParams{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		UpdatedAt:   role.UpdatedAt,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("role", role.Name).Msg("Failed to save user role")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUserRole deletes a role. It returns ErrRoleInUse while users still
+// have it.
+func (r *PostgresUserRoleRepository) DeleteUserRole(ctx context.Context, name string) error {
+	rowsAffected, err := queriesFor(ctx, r.queries).DeleteUserRole(ctx, name)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return ErrRoleInUse
+		}
+		log.Ctx(ctx).Error().Err(err).Str("role", name).Msg("Failed to delete user role")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// APIKeyPrefix starts every API key, telling keys apart from access tokens
+const APIKeyPrefix = "rg_"
+
+// apiKeyDisplayLength is how much of a key is kept to tell keys apart
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// JobTypeFlushAPIKeyUsage is the job type for moving the request counts of
+// API keys from the cache to the database
+const JobTypeFlushAPIKeyUsage = "api_keys.flush_usage"
+
+// APIKeyService errors
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyLimit    = errors.New("api key limit reached")
+)
+
+// APIKeyServiceConfig contains configuration for the API key service
+type APIKeyServiceConfig struct {
+	// MaxKeysPerUser caps the keys a user may hold
+	MaxKeysPerUser int
+	// TouchInterval is how stale the last use of a key may get before it is
+	// updated, so busy keys don't write on every request
+	TouchInterval time.Duration
+	// UsageFlushWindow is how many past hours each flush looks at, so counts
+	// survive flushes that didn't run. Counters are kept twice as long.
+	UsageFlushWindow time.Duration
+	// UsageRetention is how long hourly request counts are kept
+	UsageRetention time.Duration
+}
+
+// APIKeyService manages personal API keys. Keys are random tokens shown to
+// the user once; only their SHA-256 hash is stored, which is enough for
+// tokens with this much entropy and lets a key be found by its hash.
+type APIKeyService struct {
+	keyRepo   domain.APIKeyRepository
+	userRepo  domain.UserRepository
+	usageRepo domain.APIKeyUsageRepository
+	cache     cache.Cache
+	config    APIKeyServiceConfig
+	now       func() time.Time
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(keyRepo domain.APIKeyRepository, userRepo domain.UserRepository, config APIKeyServiceConfig) *APIKeyService {
+	// Set default values if not provided
+	if config.MaxKeysPerUser == 0 {
+		config.MaxKeysPerUser = 20
+	}
+	if config.TouchInterval == 0 {
+		config.TouchInterval = time.Minute
+	}
+	if config.UsageFlushWindow == 0 {
+		config.UsageFlushWindow = 24 * time.Hour
+	}
+	if config.UsageRetention == 0 {
+		config.UsageRetention = 90 * 24 * time.Hour
+	}
+
+	return &APIKeyService{
+		keyRepo:  keyRepo,
+		userRepo: userRepo,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// SetUsageStore sets where request counts are kept. Requests are counted in
+// the cache per key and hour, and HandleFlushUsageJob moves the counts of
+// past hours to usageRepo. Without it requests aren't counted.
+func (s *APIKeyService) SetUsageStore(usageRepo domain.APIKeyUsageRepository, store cache.Cache) {
+	s.usageRepo = usageRepo
+	s.cache = store
+}
+
+// CreateKey creates an API key for a user, returning it with the key itself,
+// which can't be recovered later
+func (s *APIKeyService) CreateKey(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt time.Time) (*domain.APIKey, string, error) {
+	key := &domain.APIKey{UserID: userID, Name: strings.TrimSpace(name), ExpiresAt: expiresAt}
+	if err := key.Validate(); err != nil {
+		return nil, "", err
+	}
+	scopes, err := domain.NormalizeAPIKeyScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	key.Scopes = scopes
+
+	count, err := s.keyRepo.CountAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= s.config.MaxKeysPerUser {
+		return nil, "", ErrAPIKeyLimit
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	secret := APIKeyPrefix + token
+	key.Prefix = secret[:apiKeyDisplayLength]
+	key.KeyHash = hashAPIKey(secret)
+
+	if err := s.keyRepo.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID.String()).Str("api_key_id", key.ID.String()).Strs("scopes", key.Scopes).Msg("API key created")
+	return key, secret, nil
+}
+
+// Keys returns the API keys of a user
+func (s *APIKeyService) Keys(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	return s.keyRepo.GetAPIKeysByUserID(ctx, userID)
+}
+
+// RevokeKey deletes one of a user's API keys
+func (s *APIKeyService) RevokeKey(ctx context.Context, userID, keyID uuid.UUID) error {
+	err := s.keyRepo.DeleteAPIKey(ctx, userID, keyID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
+
+// Authenticate resolves an API key to the key and the user it acts as
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (*domain.APIKey, *domain.User, error) {
+	if !IsAPIKey(secret) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.keyRepo.GetAPIKeyByHash(ctx, hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	now := s.now().UTC()
+	if key.Expired(now) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	// The first use in an hour is always recorded, which is how flushes find
+	// the keys with counts to move
+	first := s.countUse(ctx, key.ID, now)
+	if first || now.Sub(key.LastUsedAt) >= s.config.TouchInterval {
+		// A failed update only loses the usage time, so the request goes on
+		if err := s.keyRepo.TouchAPIKey(ctx, key.ID, now); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("api_key_id", key.ID.String()).Msg("Failed to record API key use")
+		} else {
+			key.LastUsedAt = now
+		}
+	}
+
+	return key, user, nil
+}
+
+// Usage returns the hourly request counts of one of a user's keys from since
+// on, oldest first. Counts of hours not yet flushed come from the cache.
+func (s *APIKeyService) Usage(ctx context.Context, userID, keyID uuid.UUID, since time.Time) (*domain.APIKey, []*domain.APIKeyUsage, error) {
+	keys, err := s.keyRepo.GetAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var key *domain.APIKey
+	for _, k := range keys {
+		if k.ID == keyID {
+			key = k
+		}
+	}
+	if key == nil {
+		return nil, nil, ErrAPIKeyNotFound
+	}
+	if s.usageRepo == nil {
+		return key, []*domain.APIKeyUsage{}, nil
+	}
+
+	since = since.UTC().Truncate(time.Hour)
+	usage, err := s.usageRepo.GetAPIKeyUsage(ctx, keyID, since)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Cached counts replace flushed ones, which they include
+	hours := make(map[time.Time]*domain.APIKeyUsage, len(usage))
+	for _, u := range usage {
+		hours[u.Hour] = u
+	}
+	current := s.now().UTC().Truncate(time.Hour)
+	start := current.Add(-s.config.UsageFlushWindow)
+	if since.After(start) {
+		start = since
+	}
+	for hour := start; !hour.After(current); hour = hour.Add(time.Hour) {
+		requests, err := s.cachedUsage(ctx, keyID, hour)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("api_key_id", keyID.String()).Msg("Failed to read API key usage")
+			break
+		}
+		if requests == 0 {
+			continue
+		}
+		if u, ok := hours[hour]; ok {
+			u.Requests = requests
+		} else {
+			usage = append(usage, &domain.APIKeyUsage{APIKeyID: keyID, Hour: hour, Requests: requests})
+		}
+	}
+	slices.SortFunc(usage, func(a, b *domain.APIKeyUsage) int { return a.Hour.Compare(b.Hour) })
+	return key, usage, nil
+}
+
+// HandleFlushUsageJob moves the request counts of the hours before the
+// current one from the cache to the database and deletes counts past the
+// retention period. Counts replace stored ones, so flushing twice is harmless.
+func (s *APIKeyService) HandleFlushUsageJob(ctx context.Context, job *jobs.Job) error {
+	if s.usageRepo == nil {
+		return nil
+	}
+
+	current := s.now().UTC().Truncate(time.Hour)
+	oldest := current.Add(-s.config.UsageFlushWindow)
+	keyIDs, err := s.usageRepo.GetAPIKeyIDsUsedSince(ctx, oldest)
+	if err != nil {
+		return err
+	}
+
+	flushed := 0
+	for _, keyID := range keyIDs {
+		for hour := oldest; hour.Before(current); hour = hour.Add(time.Hour) {
+			requests, err := s.cachedUsage(ctx, keyID, hour)
+			if err != nil {
+				return err
+			}
+			if requests == 0 {
+				continue
+			}
+			if err := s.usageRepo.SaveAPIKeyUsage(ctx, &domain.APIKeyUsage{APIKeyID: keyID, Hour: hour, Requests: requests}); err != nil {
+				return err
+			}
+			// A counter left behind is saved again by the next flush
+			if err := s.cache.Del(ctx, apiKeyUsageKey(keyID, hour)); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Str("api_key_id", keyID.String()).Msg("Failed to delete flushed API key usage")
+			}
+			flushed++
+		}
+	}
+
+	deleted, err := s.usageRepo.DeleteAPIKeyUsageBefore(ctx, current.Add(-s.config.UsageRetention))
+	if err != nil {
+		return err
+	}
+	if flushed > 0 || deleted > 0 {
+		log.Ctx(ctx).Info().Int("flushed", flushed).Int64("deleted", deleted).Msg("Flushed API key usage")
+	}
+	return nil
+}
+
+// countUse counts a request made with a key, reporting whether it is the
+// first in its hour. A failure only loses the count, so the request goes on.
+func (s *APIKeyService) countUse(ctx context.Context, keyID uuid.UUID, now time.Time) bool {
+	if s.cache == nil {
+		return false
+	}
+	count, err := s.cache.Incr(ctx, apiKeyUsageKey(keyID, now.Truncate(time.Hour)), 2*s.config.UsageFlushWindow)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("api_key_id", keyID.String()).Msg("Failed to count API key use")
+		return false
+	}
+	return count == 1
+}
+
+// cachedUsage returns the cached request count of a key in an hour, zero
+// when there is none
+func (s *APIKeyService) cachedUsage(ctx context.Context, keyID uuid.UUID, hour time.Time) (int64, error) {
+	data, err := s.cache.Get(ctx, apiKeyUsageKey(keyID, hour))
+	if errors.Is(err, cache.ErrMiss) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// apiKeyUsageKey is the cache key counting the requests of a key in an hour
+func apiKeyUsageKey(keyID uuid.UUID, hour time.Time) string {
+	return fmt.Sprintf("api_key_usage:%s:%d", keyID, hour.Unix())
+}
+
+// IsAPIKey reports whether a bearer token is an API key rather than an
+// access token
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// hashAPIKey returns the stored hash of an API key
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
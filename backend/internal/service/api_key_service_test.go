@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAPIKeyRepository keeps API keys in memory
+type memoryAPIKeyRepository struct {
+	keys    []*domain.APIKey
+	touches int
+}
+
+func (r *memoryAPIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	key.ID = uuid.New()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepository) CountAPIKeysByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	keys, _ := r.GetAPIKeysByUserID(ctx, userID)
+	return len(keys), nil
+}
+
+func (r *memoryAPIKeyRepository) TouchAPIKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	r.touches++
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) DeleteAPIKey(ctx context.Context, userID, id uuid.UUID) error {
+	for i, key := range r.keys {
+		if key.ID == id && key.UserID == userID {
+			r.keys = append(r.keys[:i], r.keys[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+// memoryAPIKeyUsageRepository keeps hourly API key usage in memory, finding
+// used keys in keys
+type memoryAPIKeyUsageRepository struct {
+	keys  *memoryAPIKeyRepository
+	usage []*domain.APIKeyUsage
+}
+
+func (r *memoryAPIKeyUsageRepository) GetAPIKeyIDsUsedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, key := range r.keys.keys {
+		if !key.LastUsedAt.Before(since) {
+			ids = append(ids, key.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *memoryAPIKeyUsageRepository) SaveAPIKeyUsage(ctx context.Context, usage *domain.APIKeyUsage) error {
+	for _, u := range r.usage {
+		if u.APIKeyID == usage.APIKeyID && u.Hour.Equal(usage.Hour) {
+			u.Requests = usage.Requests
+			return nil
+		}
+	}
+	copied := *usage
+	r.usage = append(r.usage, &copied)
+	return nil
+}
+
+func (r *memoryAPIKeyUsageRepository) GetAPIKeyUsage(ctx context.Context, keyID uuid.UUID, since time.Time) ([]*domain.APIKeyUsage, error) {
+	var usage []*domain.APIKeyUsage
+	for _, u := range r.usage {
+		if u.APIKeyID == keyID && !u.Hour.Before(since) {
+			copied := *u
+			usage = append(usage, &copied)
+		}
+	}
+	return usage, nil
+}
+
+func (r *memoryAPIKeyUsageRepository) DeleteAPIKeyUsageBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	kept := r.usage[:0]
+	for _, u := range r.usage {
+		if u.Hour.Before(before) {
+			deleted++
+		} else {
+			kept = append(kept, u)
+		}
+	}
+	r.usage = kept
+	return deleted, nil
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ci@example.com", Role: "user"}
+	keyRepo := &memoryAPIKeyRepository{}
+	s := NewAPIKeyService(keyRepo, &identityUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}, APIKeyServiceConfig{MaxKeysPerUser: 2})
+
+	key, secret, err := s.CreateKey(ctx, user.ID, " CI ", []string{"resumes:read", "RESUMES:READ"}, time.Time{})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(secret, key.Prefix))
+	assert.Equal(t, "CI", key.Name)
+	assert.Equal(t, []string{domain.ScopeResumesRead}, key.Scopes)
+	// Only the hash of the key is stored
+	assert.NotContains(t, key.KeyHash, secret[len(APIKeyPrefix):])
+
+	found, foundUser, err := s.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, found.ID)
+	assert.Equal(t, user.ID, foundUser.ID)
+	assert.Equal(t, 1, keyRepo.touches)
+
+	// Uses within the touch interval aren't recorded again
+	_, _, err = s.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, 1, keyRepo.touches)
+
+	for _, invalid := range []string{secret + "x", "rg_unknown", "not-a-key"} {
+		_, _, err := s.Authenticate(ctx, invalid)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, invalid)
+	}
+
+	// Expired keys stop working
+	key.ExpiresAt = time.Now().Add(-time.Minute)
+	_, _, err = s.Authenticate(ctx, secret)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, _, err = s.CreateKey(ctx, user.ID, "deploy", []string{domain.ScopeResumesWrite}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, _, err = s.CreateKey(ctx, user.ID, "third", []string{domain.ScopeResumesRead}, time.Time{})
+	assert.ErrorIs(t, err, ErrAPIKeyLimit)
+
+	require.NoError(t, s.RevokeKey(ctx, user.ID, key.ID))
+	assert.ErrorIs(t, s.RevokeKey(ctx, uuid.New(), keyRepo.keys[0].ID), ErrAPIKeyNotFound)
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	s := NewAPIKeyService(&memoryAPIKeyRepository{}, &identityUserRepository{}, APIKeyServiceConfig{})
+
+	for name, tc := range map[string]struct {
+		name      string
+		scopes    []string
+		expiresAt time.Time
+	}{
+		"no name":       {scopes: []string{domain.ScopeResumesRead}},
+		"no scopes":     {name: "CI"},
+		"unknown scope": {name: "CI", scopes: []string{"admin"}},
+		"expired":       {name: "CI", scopes: []string{domain.ScopeResumesRead}, expiresAt: time.Now().Add(-time.Hour)},
+	} {
+		var validationErr *domain.ValidationError
+		_, _, err := s.CreateKey(context.Background(), uuid.New(), tc.name, tc.scopes, tc.expiresAt)
+		assert.True(t, errors.As(err, &validationErr), name)
+	}
+}
+
+func TestAPIKeyUsage(t *testing.T) {
+	ctx := context.Background()
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	user := &domain.User{ID: uuid.New(), Email: "ci@example.com", Role: "user"}
+	keyRepo := &memoryAPIKeyRepository{}
+	usageRepo := &memoryAPIKeyUsageRepository{keys: keyRepo}
+	s := NewAPIKeyService(keyRepo, &identityUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}, APIKeyServiceConfig{})
+	s.SetUsageStore(usageRepo, store)
+	hour := time.Date(2025, 4, 11, 9, 0, 0, 0, time.UTC)
+	now := hour.Add(50 * time.Minute)
+	s.now = func() time.Time { return now }
+
+	key, secret, err := s.CreateKey(ctx, user.ID, "CI", []string{domain.ScopeResumesRead}, time.Time{})
+	require.NoError(t, err)
+	use := func(times int) {
+		t.Helper()
+		for range times {
+			_, _, err := s.Authenticate(ctx, secret)
+			require.NoError(t, err)
+		}
+	}
+
+	// Requests are counted per hour, and the first use in an hour is always
+	// recorded even within the touch interval
+	use(3)
+	now = hour.Add(time.Hour + 10*time.Second)
+	keyRepo.touches = 0
+	use(2)
+	assert.Equal(t, 1, keyRepo.touches)
+
+	_, usage, err := s.Usage(ctx, user.ID, key.ID, hour.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.APIKeyUsage{
+		{APIKeyID: key.ID, Hour: hour, Requests: 3},
+		{APIKeyID: key.ID, Hour: hour.Add(time.Hour), Requests: 2},
+	}, usage)
+
+	// Flushing moves the counts of past hours to the database, and flushing
+	// again changes nothing
+	require.NoError(t, s.HandleFlushUsageJob(ctx, nil))
+	require.NoError(t, s.HandleFlushUsageJob(ctx, nil))
+	assert.Equal(t, []*domain.APIKeyUsage{{APIKeyID: key.ID, Hour: hour, Requests: 3}}, usageRepo.usage)
+	_, err = store.Get(ctx, apiKeyUsageKey(key.ID, hour))
+	assert.ErrorIs(t, err, cache.ErrMiss)
+
+	_, flushed, err := s.Usage(ctx, user.ID, key.ID, hour.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, usage, flushed)
+
+	// Counts past the retention period are deleted
+	now = hour.Add(91 * 24 * time.Hour)
+	require.NoError(t, s.HandleFlushUsageJob(ctx, nil))
+	assert.Empty(t, usageRepo.usage)
+
+	// Other users' keys have no usage to show
+	_, _, err = s.Usage(ctx, uuid.New(), key.ID, hour)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+}
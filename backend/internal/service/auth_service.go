@@ -1,13 +1,20 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
 	"github.com/lordaris/resume_generator/internal/repository"
 	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/mail"
 	"github.com/lordaris/resume_generator/pkg/security"
 	"github.com/rs/zerolog/log"
 )
@@ -19,9 +26,20 @@ var (
 	ErrUserNotFound         = errors.New("user not found")
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrExpiredToken         = errors.New("token expired")
+	ErrTokenRevoked         = errors.New("token revoked")
 	ErrInvalidSession       = errors.New("invalid session")
 	ErrPasswordResetExpired = errors.New("password reset expired")
 	ErrPasswordResetUsed    = errors.New("password reset already used")
+	ErrEmailUnchanged       = errors.New("new email matches current email")
+	ErrEmailChangeExpired   = errors.New("email change expired")
+	ErrEmailChangeUsed      = errors.New("email change already confirmed")
+	ErrChannelUnavailable   = errors.New("delivery channel unavailable")
+)
+
+// Channels password reset instructions are delivered over
+const (
+	ResetChannelEmail = "email"
+	ResetChannelSMS   = "sms"
 )
 
 // TokenPair contains access and refresh tokens
@@ -31,17 +49,73 @@ type TokenPair struct {
 	ExpiresIn    int64  `json:"expires_in"` // Seconds until access token expires
 }
 
+// Job types for auth maintenance
+const (
+	JobTypeCleanupPasswordResets = "auth.cleanup_password_resets"
+	JobTypePurgeExpiredSessions  = "auth.purge_expired_sessions"
+	JobTypeCleanupEmailChanges   = "auth.cleanup_email_changes"
+)
+
 // AuthService handles authentication and authorization
 type AuthService struct {
-	userRepo domain.UserRepository
-	jwt      *auth.JWT
-	config   AuthServiceConfig
+	userRepo    domain.UserRepository
+	jwt         *auth.JWT
+	config      AuthServiceConfig
+	mailService *MailService
+	denylist    *auth.Denylist
+	// phoneService texts password reset codes to verified numbers, when set
+	phoneService *PhoneService
+	// passkeyService confirms password logins of users who require a
+	// passkey, when set
+	passkeyService *PasskeyService
+	// claimsCache keeps the users minimal tokens resolve to, when set
+	claimsCache cache.Cache
+}
+
+// SetMailService enables delivery of password reset emails
+func (s *AuthService) SetMailService(mailService *MailService) {
+	s.mailService = mailService
+}
+
+// SetPhoneService enables password resets by SMS, for users with a verified
+// phone number
+func (s *AuthService) SetPhoneService(phoneService *PhoneService) {
+	s.phoneService = phoneService
 }
 
-// Logout logs out a user by invalidating their refresh token
-func (s *AuthService) Logout(refreshToken string) error {
+// SetPasskeyService enables confirming password logins with a passkey.
+// Without it, users who require a passkey can't sign in with their password.
+func (s *AuthService) SetPasskeyService(passkeyService *PasskeyService) {
+	s.passkeyService = passkeyService
+}
+
+// SetDenylist enables revocation of access tokens before they expire.
+// Without it, access tokens stay valid until expiry after logout.
+func (s *AuthService) SetDenylist(denylist *auth.Denylist) {
+	s.denylist = denylist
+}
+
+// SetClaimsCache caches the email and role minimal access tokens resolve
+// to. Without it, each request with a minimal token looks up its user.
+func (s *AuthService) SetClaimsCache(store cache.Cache) {
+	s.claimsCache = store
+}
+
+// Logout logs out a user by invalidating their refresh token and, when
+// given, the access token it was used with
+func (s *AuthService) Logout(ctx context.Context, refreshToken, accessToken string) error {
+	if accessToken != "" && s.denylist != nil {
+		// Tokens that no longer validate can't be used anyway
+		if claims, err := s.jwt.ValidateAccessToken(accessToken); err == nil && claims.ID != "" {
+			if err := s.denylist.RevokeToken(ctx, claims); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("Failed to revoke access token")
+				return err
+			}
+		}
+	}
+
 	// Get session by refresh token
-	session, err := s.userRepo.GetSessionByToken(refreshToken)
+	session, err := s.userRepo.GetSessionByToken(ctx, refreshToken)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			// Session not found, consider it already logged out
@@ -51,30 +125,71 @@ func (s *AuthService) Logout(refreshToken string) error {
 	}
 
 	// Delete session
-	return s.userRepo.DeleteSession(session.ID)
+	return s.userRepo.DeleteSession(ctx, session.ID)
 }
 
-// LogoutAll logs out a user from all devices
-func (s *AuthService) LogoutAll(userID uuid.UUID) error {
-	return s.userRepo.DeleteUserSessions(userID)
+// LogoutAll logs out a user from all devices, revoking their sessions and
+// every access token issued so far
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.userRepo.DeleteUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	return s.revokeUserTokens(ctx, userID)
+}
+
+// revokeUserTokens revokes every access token issued to a user so far
+func (s *AuthService) revokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	if s.denylist == nil {
+		return nil
+	}
+
+	// Keep the entry until the last token it covers has expired
+	ttl := s.config.AccessTokenExpiry + s.jwt.Leeway()
+	if err := s.denylist.RevokeUser(ctx, userID.String(), time.Now(), ttl); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to revoke access tokens")
+		return err
+	}
+	return nil
 }
 
-// RequestPasswordReset generates a password reset token
-func (s *AuthService) RequestPasswordReset(email string) (string, error) {
+// RequestPasswordReset generates a password reset token and delivers it
+// over channel. Over email the token itself is sent. Over SMS a code that
+// unlocks it is texted to the user's verified number instead; the code is
+// redeemed with ResetPasswordWithCode. The token is never returned, so only
+// the owner of the email address or phone number can use it.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email, channel string) error {
+	if channel == ResetChannelSMS && s.phoneService == nil {
+		return ErrChannelUnavailable
+	}
+
 	// Get user by email
-	user, err := s.userRepo.GetUserByEmail(email)
+	user, err := s.userByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return "", ErrUserNotFound
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if channel == ResetChannelSMS {
+		// Only numbers the user verified get codes
+		userPhone, err := s.phoneService.Phone(ctx, user.ID)
+		if errors.Is(err, ErrPhoneNotFound) {
+			return ErrPhoneNotVerified
+		}
+		if err != nil {
+			return err
+		}
+		if !userPhone.Verified() {
+			return ErrPhoneNotVerified
 		}
-		return "", err
 	}
 
 	// Generate reset token
 	resetToken, err := s.jwt.GenerateResetToken(user.ID.String(), user.Email)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to generate reset token")
-		return "", err
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to generate reset token")
+		return err
 	}
 
 	// Store password reset
@@ -82,20 +197,89 @@ func (s *AuthService) RequestPasswordReset(email string) (string, error) {
 		ID:        uuid.New(),
 		UserID:    user.ID,
 		Token:     resetToken,
-		ExpiresAt: time.Now().Add(s.config.ResetTokenExpiry),
-		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().UTC().Add(s.config.ResetTokenExpiry),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.userRepo.CreatePasswordReset(ctx, reset); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create password reset")
+		return err
+	}
+
+	if channel == ResetChannelSMS {
+		if err := s.phoneService.SendResetCode(ctx, user.ID, resetToken, s.config.ResetTokenExpiry); err != nil {
+			if !errors.Is(err, ErrCodeLimitReached) {
+				log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send password reset code")
+			}
+			return err
+		}
+		return nil
+	}
+
+	// Email the token; a delivery failure shouldn't fail the request since the job is retried
+	if s.mailService != nil {
+		msg := &mail.Message{
+			To:      user.Email,
+			Subject: "Reset your password",
+			Body: fmt.Sprintf("Use the following token to reset your password. It expires on %s.\n\n%s\n\nIf you did not request a password reset, you can ignore this email.",
+				formatLocalTime(user, reset.ExpiresAt), resetToken),
+		}
+		if err := s.mailService.Send(ctx, msg); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to queue password reset email")
+		}
+	}
+
+	return nil
+}
+
+// ResetPasswordWithCode resets a user's password using a code texted to them
+// by RequestPasswordReset
+func (s *AuthService) ResetPasswordWithCode(ctx context.Context, email, code, newPassword string) error {
+	if s.phoneService == nil {
+		return ErrChannelUnavailable
+	}
+
+	user, err := s.userByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// Unknown addresses look like wrong codes
+			return ErrInvalidCode
+		}
+		return err
+	}
+
+	resetToken, err := s.phoneService.CheckResetCode(ctx, user.ID, code)
+	if err != nil {
+		return err
 	}
+	return s.ResetPassword(ctx, resetToken, newPassword)
+}
+
+// HandleCleanupPasswordResetsJob deletes expired and used password resets
+func (s *AuthService) HandleCleanupPasswordResetsJob(ctx context.Context, job *jobs.Job) error {
+	return s.userRepo.DeleteExpiredPasswordResets(ctx)
+}
+
+// HandleCleanupEmailChangesJob deletes expired and confirmed email changes
+func (s *AuthService) HandleCleanupEmailChangesJob(ctx context.Context, job *jobs.Job) error {
+	return s.userRepo.DeleteExpiredEmailChanges(ctx)
+}
 
-	if err := s.userRepo.CreatePasswordReset(reset); err != nil {
-		log.Error().Err(err).Msg("Failed to create password reset")
-		return "", err
+// HandlePurgeExpiredSessionsJob deletes sessions whose refresh token has expired
+func (s *AuthService) HandlePurgeExpiredSessionsJob(ctx context.Context, job *jobs.Job) error {
+	count, err := s.userRepo.DeleteExpiredSessions(ctx)
+	if err != nil {
+		return err
 	}
 
-	return resetToken, nil
+	if count > 0 {
+		log.Ctx(ctx).Info().Int64("count", count).Msg("Purged expired sessions")
+	}
+	return nil
 }
 
 // ResetPassword resets a user's password using a reset token
-func (s *AuthService) ResetPassword(resetToken, newPassword string) error {
+func (s *AuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
 	// Validate reset token
 	claims, err := s.jwt.ValidateResetToken(resetToken)
 	if err != nil {
@@ -108,11 +292,11 @@ func (s *AuthService) ResetPassword(resetToken, newPassword string) error {
 	// Get user
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		log.Error().Err(err).Msg("Invalid user ID in token")
+		log.Ctx(ctx).Error().Err(err).Msg("Invalid user ID in token")
 		return ErrInvalidToken
 	}
 
-	user, err := s.userRepo.GetUserByID(userID)
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrUserNotFound
@@ -121,7 +305,7 @@ func (s *AuthService) ResetPassword(resetToken, newPassword string) error {
 	}
 
 	// Get password reset by token
-	reset, err := s.userRepo.GetPasswordResetByToken(resetToken)
+	reset, err := s.userRepo.GetPasswordResetByToken(ctx, resetToken)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrInvalidToken
@@ -130,7 +314,7 @@ func (s *AuthService) ResetPassword(resetToken, newPassword string) error {
 	}
 
 	// Check if reset is expired
-	if time.Now().After(reset.ExpiresAt) {
+	if time.Now().UTC().After(reset.ExpiresAt) {
 		return ErrPasswordResetExpired
 	}
 
@@ -142,36 +326,201 @@ func (s *AuthService) ResetPassword(resetToken, newPassword string) error {
 	// Hash new password
 	passwordHash, err := security.HashPassword(newPassword, nil)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to hash password")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to hash password")
 		return err
 	}
 
 	// Update user's password
 	user.PasswordHash = passwordHash
-	user.UpdatedAt = time.Now()
+	user.UpdatedAt = time.Now().UTC()
 
-	if err := s.userRepo.UpdateUser(user); err != nil {
-		log.Error().Err(err).Msg("Failed to update user password")
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update user password")
 		return err
 	}
 
 	// Mark reset as used
-	if err := s.userRepo.MarkPasswordResetUsed(reset.ID); err != nil {
-		log.Error().Err(err).Msg("Failed to mark password reset as used")
+	if err := s.userRepo.MarkPasswordResetUsed(ctx, reset.ID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to mark password reset as used")
 		// Continue anyway, just log the error
 	}
 
 	// Delete all user sessions
-	if err := s.userRepo.DeleteUserSessions(user.ID); err != nil {
-		log.Error().Err(err).Msg("Failed to delete user sessions")
+	if err := s.userRepo.DeleteUserSessions(ctx, user.ID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete user sessions")
 		// Continue anyway, just log the error
 	}
 
+	// Access tokens issued with the old password must stop working too
+	return s.revokeUserTokens(ctx, user.ID)
+}
+
+// RequestEmailChange starts a change of the user's email address. The current
+// password is required, and the new address must confirm the change before it applies.
+func (s *AuthService) RequestEmailChange(ctx context.Context, userID uuid.UUID, newEmail, password string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	// Users who signed up with an OAuth provider have no password to verify
+	if user.PasswordHash == "" {
+		return ErrInvalidCredentials
+	}
+
+	// Verify password
+	match, err := security.VerifyPassword(password, user.PasswordHash)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to verify password")
+		return err
+	}
+
+	if !match {
+		return ErrInvalidCredentials
+	}
+
+	newEmail = domain.NormalizeEmail(newEmail)
+	if newEmail == domain.NormalizeEmail(user.Email) {
+		return ErrEmailUnchanged
+	}
+
+	// Check the new address isn't already taken
+	existingUser, err := s.userRepo.GetUserByEmail(ctx, newEmail)
+	if err == nil && existingUser != nil {
+		return ErrUserAlreadyExists
+	} else if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	// Generate confirmation token
+	token, err := s.jwt.GenerateEmailChangeToken(user.ID.String(), newEmail)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to generate email change token")
+		return err
+	}
+
+	// Store email change
+	change := &domain.EmailChange{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		Token:     token,
+		ExpiresAt: time.Now().UTC().Add(s.config.EmailChangeTokenExpiry),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.userRepo.CreateEmailChange(ctx, change); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create email change")
+		return err
+	}
+
+	// Send the token to the new address and let the current one know a change was requested
+	if s.mailService != nil {
+		confirm := &mail.Message{
+			To:      newEmail,
+			Subject: "Confirm your new email address",
+			Body: fmt.Sprintf("Use the following token to confirm your new email address. It expires on %s.\n\n%s\n\nIf you did not request this change, you can ignore this email.",
+				formatLocalTime(user, change.ExpiresAt), token),
+		}
+		if err := s.mailService.Send(ctx, confirm); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to queue email change confirmation")
+			return err
+		}
+
+		notice := &mail.Message{
+			To:      user.Email,
+			Subject: "Email change requested",
+			Body: fmt.Sprintf("A request to change the email address on your account was made on %s. The change only applies once it is confirmed from the new address.\n\nIf you did not request this change, reset your password.",
+				formatLocalTime(user, change.CreatedAt)),
+		}
+		if err := s.mailService.Send(ctx, notice); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to queue email change notice")
+		}
+	}
+
 	return nil
 }
 
-// ValidateAccessToken validates an access token and returns the claims
-func (s *AuthService) ValidateAccessToken(accessToken string) (*auth.JWTClaims, error) {
+// ConfirmEmailChange applies a pending email change and returns new tokens carrying
+// the updated email. All existing sessions are revoked since their tokens hold the old one.
+// Users who require a passkey get a *SecondFactorRequiredError like Login instead of tokens.
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, token, userAgent, clientIP string) (*TokenPair, error) {
+	// Validate confirmation token
+	claims, err := s.jwt.ValidateEmailChangeToken(token)
+	if err != nil {
+		if errors.Is(err, auth.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	// Get email change by token
+	change, err := s.userRepo.GetEmailChangeByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if change.UserID.String() != claims.UserID {
+		return nil, ErrInvalidToken
+	}
+
+	// Check if change is expired
+	if time.Now().UTC().After(change.ExpiresAt) {
+		return nil, ErrEmailChangeExpired
+	}
+
+	// Check if change was already confirmed
+	if !change.ConfirmedAt.IsZero() {
+		return nil, ErrEmailChangeUsed
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, change.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// Update the email; the unique constraint catches addresses registered since the request
+	user.Email = change.NewEmail
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			return nil, ErrUserAlreadyExists
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to update user email")
+		return nil, err
+	}
+	s.ForgetClaimsUser(ctx, user.ID)
+
+	// Mark change as confirmed
+	if err := s.userRepo.MarkEmailChangeConfirmed(ctx, change.ID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to mark email change as confirmed")
+		// Continue anyway, just log the error
+	}
+
+	// Delete all user sessions
+	if err := s.userRepo.DeleteUserSessions(ctx, user.ID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to delete user sessions")
+		// Continue anyway, just log the error
+	}
+	if err := s.revokeUserTokens(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, userAgent, clientIP)
+}
+
+// ValidateAccessToken validates an access token and returns the claims.
+// Revoked tokens return ErrTokenRevoked; if the denylist can't be checked the
+// error is returned as is, so the token is refused rather than trusted.
+func (s *AuthService) ValidateAccessToken(ctx context.Context, accessToken string) (*auth.JWTClaims, error) {
 	claims, err := s.jwt.ValidateAccessToken(accessToken)
 	if err != nil {
 		if errors.Is(err, auth.ErrTokenExpired) {
@@ -179,14 +528,109 @@ func (s *AuthService) ValidateAccessToken(accessToken string) (*auth.JWTClaims,
 		}
 		return nil, ErrInvalidToken
 	}
+
+	if s.denylist != nil {
+		revoked, err := s.denylist.Revoked(ctx, claims)
+		if err != nil {
+			return nil, fmt.Errorf("check token denylist: %w", err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	if claims.Minimal() {
+		if err := s.resolveClaims(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+
 	return claims, nil
 }
 
+// claimsUser is what the claims of a minimal token resolve to
+type claimsUser struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// claimsUserKey returns the cache key of the user minimal tokens resolve to
+func claimsUserKey(userID uuid.UUID) string {
+	return "auth:claims_user:" + userID.String()
+}
+
+// resolveClaims fills in the email and role of minimal token claims from
+// their user, cached for ClaimsCacheTTL. Like the role of a full token,
+// which lasts until it expires, a changed role can take that long to apply.
+// Cache failures fall back to the repository.
+func (s *AuthService) resolveClaims(ctx context.Context, claims *auth.JWTClaims) error {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	key := claimsUserKey(userID)
+
+	if s.claimsCache != nil {
+		data, err := s.claimsCache.Get(ctx, key)
+		if err == nil {
+			var cached claimsUser
+			if err := json.Unmarshal(data, &cached); err == nil {
+				claims.Email, claims.Role = cached.Email, cached.Role
+				return nil
+			}
+		} else if !errors.Is(err, cache.ErrMiss) {
+			log.Ctx(ctx).Warn().Err(err).Str("user_id", claims.UserID).Msg("Failed to read cached token user")
+		}
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		// Tokens of deleted users are refused
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+	claims.Email, claims.Role = user.Email, user.Role
+
+	if s.claimsCache != nil {
+		data, err := json.Marshal(claimsUser{Email: user.Email, Role: user.Role})
+		if err == nil {
+			err = s.claimsCache.Set(ctx, key, data, s.config.ClaimsCacheTTL)
+		}
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("user_id", claims.UserID).Msg("Failed to cache token user")
+		}
+	}
+	return nil
+}
+
+// ForgetClaimsUser drops the cached user of minimal tokens after the user's
+// email or role changed. A failure only leaves it stale until the TTL expires.
+func (s *AuthService) ForgetClaimsUser(ctx context.Context, userID uuid.UUID) {
+	if s.claimsCache == nil {
+		return
+	}
+	if err := s.claimsCache.Del(ctx, claimsUserKey(userID)); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("user_id", userID.String()).Msg("Failed to drop cached token user")
+	}
+}
+
 // AuthServiceConfig contains configuration for the auth service
 type AuthServiceConfig struct {
 	AccessTokenExpiry  time.Duration
 	RefreshTokenExpiry time.Duration
 	ResetTokenExpiry   time.Duration
+	// EmailChangeTokenExpiry is how long an email change can be confirmed
+	EmailChangeTokenExpiry time.Duration
+	// SessionIdleTimeout ends sessions that haven't been refreshed for this
+	// long; zero leaves them to the refresh token expiry
+	SessionIdleTimeout time.Duration
+	// SessionMaxLifetime ends sessions this long after the login, however
+	// often they are refreshed; zero lets them last while in use
+	SessionMaxLifetime time.Duration
+	// ClaimsCacheTTL is how long the user of minimal tokens is cached
+	ClaimsCacheTTL time.Duration
 }
 
 // NewAuthService creates a new auth service
@@ -201,6 +645,12 @@ func NewAuthService(userRepo domain.UserRepository, jwt *auth.JWT, config AuthSe
 	if config.ResetTokenExpiry == 0 {
 		config.ResetTokenExpiry = 1 * time.Hour
 	}
+	if config.EmailChangeTokenExpiry == 0 {
+		config.EmailChangeTokenExpiry = 24 * time.Hour
+	}
+	if config.ClaimsCacheTTL == 0 {
+		config.ClaimsCacheTTL = time.Minute
+	}
 
 	return &AuthService{
 		userRepo: userRepo,
@@ -210,9 +660,13 @@ func NewAuthService(userRepo domain.UserRepository, jwt *auth.JWT, config AuthSe
 }
 
 // Register registers a new user
-func (s *AuthService) Register(email, password, role string) (*domain.User, error) {
-	// Check if user already exists
-	existingUser, err := s.userRepo.GetUserByEmail(email)
+func (s *AuthService) Register(ctx context.Context, email, password, role string) (*domain.User, error) {
+	// Skip hashing the password for a taken address. Two registrations for
+	// the same address can both get past this check; the unique constraint
+	// on the email decides between them, and the loser gets
+	// ErrUserAlreadyExists too.
+	email = domain.NormalizeEmail(email)
+	existingUser, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err == nil && existingUser != nil {
 		return nil, ErrUserAlreadyExists
 	} else if err != nil && !errors.Is(err, repository.ErrNotFound) {
@@ -222,7 +676,7 @@ func (s *AuthService) Register(email, password, role string) (*domain.User, erro
 	// Hash password
 	passwordHash, err := security.HashPassword(password, nil)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to hash password")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to hash password")
 		return nil, err
 	}
 
@@ -232,15 +686,15 @@ func (s *AuthService) Register(email, password, role string) (*domain.User, erro
 		Email:        email,
 		PasswordHash: passwordHash,
 		Role:         role,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
 	}
 
-	if err := s.userRepo.CreateUser(user); err != nil {
-		log.Error().Err(err).Msg("Failed to create user")
-		if errors.Is(err, repository.ErrConflict) {
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrDuplicateEmail) {
 			return nil, ErrUserAlreadyExists
 		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create user")
 		return nil, err
 	}
 
@@ -248,9 +702,9 @@ func (s *AuthService) Register(email, password, role string) (*domain.User, erro
 }
 
 // Login authenticates a user and returns tokens
-func (s *AuthService) Login(email, password, userAgent, clientIP string) (*TokenPair, error) {
+func (s *AuthService) Login(ctx context.Context, email, password, userAgent, clientIP string) (*TokenPair, error) {
 	// Get user by email
-	user, err := s.userRepo.GetUserByEmail(email)
+	user, err := s.userByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrInvalidCredentials
@@ -258,10 +712,15 @@ func (s *AuthService) Login(email, password, userAgent, clientIP string) (*Token
 		return nil, err
 	}
 
+	// Users who signed up with an OAuth provider have no password to verify
+	if user.PasswordHash == "" {
+		return nil, ErrInvalidCredentials
+	}
+
 	// Verify password
 	match, err := security.VerifyPassword(password, user.PasswordHash)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to verify password")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to verify password")
 		return nil, err
 	}
 
@@ -269,32 +728,70 @@ func (s *AuthService) Login(email, password, userAgent, clientIP string) (*Token
 		return nil, ErrInvalidCredentials
 	}
 
+	return s.completeLogin(ctx, user, userAgent, clientIP)
+}
+
+// completeLogin issues tokens to a user who proved who they are, unless they
+// require a passkey, in which case the login is confirmed with one first
+func (s *AuthService) completeLogin(ctx context.Context, user *domain.User, userAgent, clientIP string) (*TokenPair, error) {
+	if user.PasskeyRequired {
+		if s.passkeyService == nil {
+			return nil, ErrSecondFactorUnavailable
+		}
+		challenge, err := s.passkeyService.beginSecondFactor(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &SecondFactorRequiredError{Challenge: challenge}
+	}
+
+	return s.issueTokens(ctx, user, userAgent, clientIP)
+}
+
+// userByEmail looks up the account of an email address. Accounts created
+// before plus-tag folding was turned on keep their tagged address, so that
+// address is tried when the folded one has no account.
+func (s *AuthService) userByEmail(ctx context.Context, email string) (*domain.User, error) {
+	normalized := domain.NormalizeEmail(email)
+	user, err := s.userRepo.GetUserByEmail(ctx, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		if tagged := strings.ToLower(strings.TrimSpace(email)); tagged != normalized {
+			return s.userRepo.GetUserByEmail(ctx, tagged)
+		}
+	}
+	return user, err
+}
+
+// issueTokens generates a token pair for the user and stores a new session
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, userAgent, clientIP string) (*TokenPair, error) {
 	// Generate tokens
 	accessToken, err := s.jwt.GenerateAccessToken(user.ID.String(), user.Email, user.Role)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to generate access token")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to generate access token")
 		return nil, err
 	}
 
 	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID.String(), user.Email, user.Role)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to generate refresh token")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to generate refresh token")
 		return nil, err
 	}
 
 	// Store session
+	now := time.Now().UTC()
 	session := &domain.Session{
 		ID:           uuid.New(),
 		UserID:       user.ID,
 		RefreshToken: refreshToken,
 		UserAgent:    userAgent,
 		ClientIP:     clientIP,
-		ExpiresAt:    time.Now().Add(s.config.RefreshTokenExpiry),
-		CreatedAt:    time.Now(),
+		ExpiresAt:    s.sessionExpiry(now, now),
+		CreatedAt:    now,
+		LastUsedAt:   now,
 	}
 
-	if err := s.userRepo.CreateSession(session); err != nil {
-		log.Error().Err(err).Msg("Failed to create session")
+	if err := s.userRepo.CreateSession(ctx, session); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to create session")
 		return nil, err
 	}
 
@@ -305,8 +802,30 @@ func (s *AuthService) Login(email, password, userAgent, clientIP string) (*Token
 	}, nil
 }
 
+// sessionExpiry is when a session created at createdAt and used at now
+// expires: when its refresh token does, unless it goes idle or reaches its
+// maximum lifetime first
+func (s *AuthService) sessionExpiry(createdAt, now time.Time) time.Time {
+	expiresAt := now.Add(s.config.RefreshTokenExpiry)
+	if s.config.SessionIdleTimeout > 0 {
+		expiresAt = minTime(expiresAt, now.Add(s.config.SessionIdleTimeout))
+	}
+	if s.config.SessionMaxLifetime > 0 {
+		expiresAt = minTime(expiresAt, createdAt.Add(s.config.SessionMaxLifetime))
+	}
+	return expiresAt
+}
+
+// minTime returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
 // RefreshToken refreshes an access token using a refresh token
-func (s *AuthService) RefreshToken(refreshToken, userAgent, clientIP string) (*TokenPair, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, userAgent, clientIP string) (*TokenPair, error) {
 	// Validate refresh token
 	claims, err := s.jwt.ValidateRefreshToken(refreshToken)
 	if err != nil {
@@ -319,11 +838,11 @@ func (s *AuthService) RefreshToken(refreshToken, userAgent, clientIP string) (*T
 	// Get user
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		log.Error().Err(err).Msg("Invalid user ID in token")
+		log.Ctx(ctx).Error().Err(err).Msg("Invalid user ID in token")
 		return nil, ErrInvalidToken
 	}
 
-	user, err := s.userRepo.GetUserByID(userID)
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrUserNotFound
@@ -332,7 +851,7 @@ func (s *AuthService) RefreshToken(refreshToken, userAgent, clientIP string) (*T
 	}
 
 	// Get session by refresh token
-	session, err := s.userRepo.GetSessionByToken(refreshToken)
+	session, err := s.userRepo.GetSessionByToken(ctx, refreshToken)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrInvalidSession
@@ -340,45 +859,42 @@ func (s *AuthService) RefreshToken(refreshToken, userAgent, clientIP string) (*T
 		return nil, err
 	}
 
-	// Check if session is expired
-	if time.Now().After(session.ExpiresAt) {
+	// Check if session is expired. The policies are checked too, so that
+	// tightening them applies to existing sessions.
+	now := time.Now().UTC()
+	idle := s.config.SessionIdleTimeout > 0 && now.Sub(session.LastUsedAt) > s.config.SessionIdleTimeout
+	tooOld := s.config.SessionMaxLifetime > 0 && now.Sub(session.CreatedAt) > s.config.SessionMaxLifetime
+	if now.After(session.ExpiresAt) || idle || tooOld {
 		// Delete expired session
-		_ = s.userRepo.DeleteSession(session.ID)
+		_ = s.userRepo.DeleteSession(ctx, session.ID)
 		return nil, ErrExpiredToken
 	}
 
 	// Generate new tokens
 	newAccessToken, err := s.jwt.GenerateAccessToken(user.ID.String(), user.Email, user.Role)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to generate access token")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to generate access token")
 		return nil, err
 	}
 
 	newRefreshToken, err := s.jwt.GenerateRefreshToken(user.ID.String(), user.Email, user.Role)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to generate refresh token")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to generate refresh token")
 		return nil, err
 	}
 
-	// Delete old session
-	if err := s.userRepo.DeleteSession(session.ID); err != nil {
-		log.Error().Err(err).Msg("Failed to delete old session")
-		// Continue anyway, just log the error
-	}
-
-	// Create new session
-	newSession := &domain.Session{
-		ID:           uuid.New(),
-		UserID:       user.ID,
-		RefreshToken: newRefreshToken,
-		UserAgent:    userAgent,
-		ClientIP:     clientIP,
-		ExpiresAt:    time.Now().Add(s.config.RefreshTokenExpiry),
-		CreatedAt:    time.Now(),
-	}
-
-	if err := s.userRepo.CreateSession(newSession); err != nil {
-		log.Error().Err(err).Msg("Failed to create new session")
+	// Rotate the token in place, keeping the session's login time. Of two
+	// refreshes with the same token only the first succeeds.
+	session.RefreshToken = newRefreshToken
+	session.UserAgent = userAgent
+	session.ClientIP = clientIP
+	session.LastUsedAt = now
+	session.ExpiresAt = s.sessionExpiry(session.CreatedAt, now)
+	if err := s.userRepo.RotateSession(ctx, session, refreshToken); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidSession
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to rotate session")
 		return nil, err
 	}
 
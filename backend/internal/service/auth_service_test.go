@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// racingUserRepository lets every lookup see no user until all of them have
+// been made, as when registrations for one address arrive together, and
+// enforces unique emails like the database's constraint
+type racingUserRepository struct {
+	domain.UserRepository
+	lookups sync.WaitGroup
+
+	mu    sync.Mutex
+	users map[string]*domain.User
+}
+
+func (r *racingUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.lookups.Done()
+	r.lookups.Wait()
+	return nil, repository.ErrNotFound
+}
+
+func (r *racingUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.Email]; ok {
+		return repository.ErrDuplicateEmail
+	}
+	r.users[user.Email] = user
+	return nil
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	const attempts = 5
+	repo := &racingUserRepository{users: map[string]*domain.User{}}
+	repo.lookups.Add(attempts)
+	svc := NewAuthService(repo, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), AuthServiceConfig{})
+
+	errs := make([]error, attempts)
+	var wg sync.WaitGroup
+	for i := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = svc.Register(context.Background(), "race@example.com", "correct horse battery", "user")
+		}()
+	}
+	wg.Wait()
+
+	// Exactly one registration wins; the others are told the address is taken
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+	}
+	assert.Equal(t, 1, created)
+	assert.Len(t, repo.users, 1)
+}
+
+// emailUserRepository finds users by their stored email, as the database
+// does: case-insensitively
+type emailUserRepository struct {
+	domain.UserRepository
+	users   []*domain.User
+	lookups []string
+}
+
+func (r *emailUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.lookups = append(r.lookups, email)
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func TestUserByEmailWithPlusTagFolding(t *testing.T) {
+	domain.SetEmailPlusTagFolding(true)
+	t.Cleanup(func() { domain.SetEmailPlusTagFolding(false) })
+
+	tagged := &domain.User{ID: uuid.New(), Email: "jane+jobs@example.com"}
+	repo := &emailUserRepository{users: []*domain.User{tagged}}
+	svc := NewAuthService(repo, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), AuthServiceConfig{})
+
+	// An account registered with its tag before folding is still found
+	user, err := svc.userByEmail(context.Background(), " Jane+Jobs@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, tagged.ID, user.ID)
+	assert.Equal(t, []string{"jane@example.com", "jane+jobs@example.com"}, repo.lookups)
+
+	// The folded address wins once it has an account
+	folded := &domain.User{ID: uuid.New(), Email: "jane@example.com"}
+	repo.users = append(repo.users, folded)
+	user, err = svc.userByEmail(context.Background(), "jane+other@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, folded.ID, user.ID)
+
+	_, err = svc.Register(context.Background(), "JANE+new@example.com", "correct horse battery", "user")
+	assert.ErrorIs(t, err, ErrUserAlreadyExists)
+}
+
+// sessionUserRepository keeps one user's sessions in memory, rotating them
+// only while they hold the previous token, like the database's query
+type sessionUserRepository struct {
+	domain.UserRepository
+	user     *domain.User
+	sessions map[uuid.UUID]*domain.Session
+}
+
+func (r *sessionUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.user, nil
+}
+
+func (r *sessionUserRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+func (r *sessionUserRepository) GetSessionByToken(ctx context.Context, token string) (*domain.Session, error) {
+	for _, session := range r.sessions {
+		if session.RefreshToken == token {
+			found := *session
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *sessionUserRepository) RotateSession(ctx context.Context, session *domain.Session, previousToken string) error {
+	stored, ok := r.sessions[session.ID]
+	if !ok || stored.RefreshToken != previousToken {
+		return repository.ErrNotFound
+	}
+	*stored = *session
+	return nil
+}
+
+func (r *sessionUserRepository) DeleteSession(ctx context.Context, id uuid.UUID) error {
+	delete(r.sessions, id)
+	return nil
+}
+
+func TestRefreshTokenSessionPolicies(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "user"}
+	newService := func(config AuthServiceConfig) (*AuthService, *sessionUserRepository) {
+		repo := &sessionUserRepository{user: user, sessions: map[uuid.UUID]*domain.Session{}}
+		return NewAuthService(repo, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), config), repo
+	}
+	// only returns the single session of a repository
+	only := func(t *testing.T, repo *sessionUserRepository) *domain.Session {
+		require.Len(t, repo.sessions, 1)
+		for _, session := range repo.sessions {
+			return session
+		}
+		return nil
+	}
+
+	t.Run("rotates in place", func(t *testing.T) {
+		svc, repo := newService(AuthServiceConfig{SessionIdleTimeout: time.Hour})
+		tokens, err := svc.issueTokens(ctx, user, "Firefox", "198.51.100.1")
+		require.NoError(t, err)
+		session := only(t, repo)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), session.ExpiresAt, time.Minute)
+
+		session.CreatedAt = session.CreatedAt.Add(-time.Hour)
+		session.LastUsedAt = session.LastUsedAt.Add(-30 * time.Minute)
+		loggedIn := session.CreatedAt
+		refreshed, err := svc.RefreshToken(ctx, tokens.RefreshToken, "Chrome", "198.51.100.2")
+		require.NoError(t, err)
+
+		rotated := only(t, repo)
+		assert.Equal(t, session.ID, rotated.ID)
+		assert.Equal(t, refreshed.RefreshToken, rotated.RefreshToken)
+		assert.Equal(t, "Chrome", rotated.UserAgent)
+		assert.Equal(t, loggedIn, rotated.CreatedAt)
+		assert.WithinDuration(t, time.Now(), rotated.LastUsedAt, time.Minute)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), rotated.ExpiresAt, time.Minute)
+
+		// The previous token was used up by the rotation
+		_, err = svc.RefreshToken(ctx, tokens.RefreshToken, "Firefox", "198.51.100.1")
+		assert.ErrorIs(t, err, ErrInvalidSession)
+	})
+
+	t.Run("idle timeout", func(t *testing.T) {
+		svc, repo := newService(AuthServiceConfig{SessionIdleTimeout: time.Hour})
+		tokens, err := svc.issueTokens(ctx, user, "Firefox", "198.51.100.1")
+		require.NoError(t, err)
+		session := only(t, repo)
+		session.LastUsedAt = session.LastUsedAt.Add(-2 * time.Hour)
+
+		_, err = svc.RefreshToken(ctx, tokens.RefreshToken, "Firefox", "198.51.100.1")
+		assert.ErrorIs(t, err, ErrExpiredToken)
+		assert.Empty(t, repo.sessions)
+	})
+
+	t.Run("maximum lifetime", func(t *testing.T) {
+		svc, repo := newService(AuthServiceConfig{SessionMaxLifetime: 24 * time.Hour})
+		tokens, err := svc.issueTokens(ctx, user, "Firefox", "198.51.100.1")
+		require.NoError(t, err)
+		session := only(t, repo)
+		session.CreatedAt = session.CreatedAt.Add(-23 * time.Hour)
+
+		// Refreshing doesn't extend the session past its lifetime
+		tokens, err = svc.RefreshToken(ctx, tokens.RefreshToken, "Firefox", "198.51.100.1")
+		require.NoError(t, err)
+		session = only(t, repo)
+		assert.Equal(t, session.CreatedAt.Add(24*time.Hour), session.ExpiresAt)
+
+		session.CreatedAt = session.CreatedAt.Add(-2 * time.Hour)
+		_, err = svc.RefreshToken(ctx, tokens.RefreshToken, "Firefox", "198.51.100.1")
+		assert.ErrorIs(t, err, ErrExpiredToken)
+		assert.Empty(t, repo.sessions)
+	})
+}
+
+// countingUserRepository counts lookups of its users by ID
+type countingUserRepository struct {
+	domain.UserRepository
+	users   map[uuid.UUID]*domain.User
+	lookups int
+}
+
+func (r *countingUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.lookups++
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+func TestValidateMinimalAccessToken(t *testing.T) {
+	ctx := context.Background()
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "admin"}
+	repo := &countingUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	jwt := auth.NewJWT(auth.JWTConfig{Secret: "test-secret", MinimalClaims: true})
+	svc := NewAuthService(repo, jwt, AuthServiceConfig{})
+	svc.SetClaimsCache(store)
+
+	// The email and role are looked up once and then served from the cache
+	token, err := jwt.GenerateAccessToken(user.ID.String(), user.Email, user.Role)
+	require.NoError(t, err)
+	for range 2 {
+		claims, err := svc.ValidateAccessToken(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID.String(), claims.UserID)
+		assert.Equal(t, "ana@example.com", claims.Email)
+		assert.Equal(t, "admin", claims.Role)
+	}
+	assert.Equal(t, 1, repo.lookups)
+
+	// A role change shows once the cached user is forgotten
+	user.Role = "user"
+	svc.ForgetClaimsUser(ctx, user.ID)
+	claims, err := svc.ValidateAccessToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "user", claims.Role)
+	assert.Equal(t, 2, repo.lookups)
+
+	// Full tokens are trusted as they are
+	full, err := auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}).GenerateAccessToken(user.ID.String(), "old@example.com", "user")
+	require.NoError(t, err)
+	claims, err = svc.ValidateAccessToken(ctx, full)
+	require.NoError(t, err)
+	assert.Equal(t, "old@example.com", claims.Email)
+	assert.Equal(t, 2, repo.lookups)
+
+	// Tokens of deleted users are refused
+	deleted, err := jwt.GenerateAccessToken(uuid.NewString(), "", "")
+	require.NoError(t, err)
+	_, err = svc.ValidateAccessToken(ctx, deleted)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/connector"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// Conflict resolutions
+const (
+	// ResolutionLocal keeps the local edits
+	ResolutionLocal = "local"
+	// ResolutionUpstream takes the source's value
+	ResolutionUpstream = "upstream"
+	// ResolutionMerged sets the entry to a value the user merged
+	ResolutionMerged = "merged"
+)
+
+var (
+	// ErrConnectorNotFound is returned when no connector has the requested ID
+	ErrConnectorNotFound = errors.New("connector not found")
+	// ErrConnectorExists is returned when a resume already has a connector for the account
+	ErrConnectorExists = errors.New("connector already exists")
+	// ErrConflictNotFound is returned when a connector has no pending conflict for an entry
+	ErrConflictNotFound = errors.New("conflict not found")
+)
+
+// SyncResult reports what a connector's sync changed
+type SyncResult struct {
+	// Added are the entries new at the source
+	Added []*domain.Provenance `json:"added"`
+	// Updated are the entries that changed at the source only, and took
+	// its value
+	Updated []*domain.Provenance `json:"updated"`
+	// Conflicts are the entries that changed both locally and at the
+	// source; they are left as they are until resolved
+	Conflicts []*domain.ConnectorConflict `json:"conflicts"`
+	Unchanged int                         `json:"unchanged"`
+	// Skipped counts the source's entries that aren't valid resume entries,
+	// and those the user deleted, which aren't added back
+	Skipped int `json:"skipped"`
+}
+
+// ConnectorService syncs resume entries from accounts at external sources.
+// A sync adds new entries and takes the source's changes to entries the user
+// hasn't edited. Entries changed on both sides become conflicts the user
+// resolves instead of having their edits overwritten.
+type ConnectorService struct {
+	connectorRepo domain.ConnectorRepository
+	resumeRepo    domain.ResumeRepository
+	importService *EntryImportService
+	sources       map[string]connector.Source
+}
+
+// NewConnectorService creates a new connector service for the sources, by provider
+func NewConnectorService(connectorRepo domain.ConnectorRepository, resumeRepo domain.ResumeRepository, importService *EntryImportService, sources map[string]connector.Source) *ConnectorService {
+	return &ConnectorService{
+		connectorRepo: connectorRepo,
+		resumeRepo:    resumeRepo,
+		importService: importService,
+		sources:       sources,
+	}
+}
+
+// Create connects the account at provider to a resume. Its entries are
+// imported by the first sync.
+func (s *ConnectorService) Create(ctx context.Context, userID, resumeID uuid.UUID, provider, account string) (*domain.Connector, error) {
+	if _, ok := s.sources[provider]; !ok {
+		return nil, domain.NewValidationError("provider", "Provider must be one of github or credly", domain.ErrInvalidField)
+	}
+	if !connector.ValidAccount(account) {
+		return nil, domain.NewValidationError("account", "Account must be a user name at the provider", domain.ErrInvalidField)
+	}
+
+	c := &domain.Connector{
+		UserID:   userID,
+		ResumeID: resumeID,
+		Provider: provider,
+		Account:  account,
+	}
+	if err := s.connectorRepo.CreateConnector(ctx, c); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrConnectorExists
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Connectors lists the connectors of a resume
+func (s *ConnectorService) Connectors(ctx context.Context, resumeID uuid.UUID) ([]*domain.Connector, error) {
+	return s.connectorRepo.GetConnectorsByResume(ctx, resumeID)
+}
+
+// Get returns a connector
+func (s *ConnectorService) Get(ctx context.Context, id uuid.UUID) (*domain.Connector, error) {
+	c, err := s.connectorRepo.GetConnector(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrConnectorNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// Delete disconnects a connector. The entries it synced stay.
+func (s *ConnectorService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.connectorRepo.DeleteConnector(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrConnectorNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Sync fetches the connector's entries from its source and merges them into
+// its resume, replacing the conflicts pending from the last sync
+func (s *ConnectorService) Sync(ctx context.Context, c *domain.Connector) (*SyncResult, error) {
+	source, ok := s.sources[c.Provider]
+	if !ok {
+		return nil, connector.ErrUnavailable
+	}
+
+	// Connectors are deleted with their resume; one whose resume is gone
+	// has nothing to sync into
+	if _, err := s.resumeRepo.GetResumeByID(ctx, c.ResumeID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrConnectorNotFound
+		}
+		return nil, err
+	}
+
+	entries, err := source.Fetch(ctx, c.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	provenance, err := s.importService.Provenance(ctx, c.ResumeID)
+	if err != nil {
+		return nil, err
+	}
+	synced := make(map[string]*domain.Provenance)
+	for _, p := range provenance {
+		if p.Source == c.Provider && p.SourceRef != "" {
+			synced[p.Entity+"/"+p.SourceRef] = p
+		}
+	}
+
+	result := &SyncResult{
+		Added:     []*domain.Provenance{},
+		Updated:   []*domain.Provenance{},
+		Conflicts: []*domain.ConnectorConflict{},
+	}
+	var added []ImportEntry
+	for _, entry := range entries {
+		upstream, err := upstreamValue(entry)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("provider", c.Provider).Str("source_ref", entry.SourceRef).Msg("Skipping invalid connector entry")
+			result.Skipped++
+			continue
+		}
+
+		p, ok := synced[entry.Entity+"/"+entry.SourceRef]
+		if !ok {
+			added = append(added, ImportEntry{Entity: entry.Entity, SourceRef: entry.SourceRef, Value: upstream})
+			continue
+		}
+		if jsonEqual(upstream, p.Original) {
+			result.Unchanged++
+			continue
+		}
+
+		local, err := s.importService.Entry(ctx, c.ResumeID, p.Entity, p.EntityID)
+		if err != nil {
+			if errors.Is(err, ErrProvenanceNotFound) {
+				result.Skipped++
+				continue
+			}
+			return nil, err
+		}
+
+		// Take the source's value unless the user edited the entry to
+		// something else
+		if jsonEqual(local, p.Original) || jsonEqual(local, upstream) {
+			updated, err := s.importService.Resync(ctx, c.ResumeID, p.Entity, p.EntityID, upstream)
+			if err != nil {
+				return nil, err
+			}
+			result.Updated = append(result.Updated, updated)
+			continue
+		}
+
+		result.Conflicts = append(result.Conflicts, &domain.ConnectorConflict{
+			Entity:    p.Entity,
+			EntityID:  p.EntityID,
+			SourceRef: p.SourceRef,
+			Base:      p.Original,
+			Local:     local,
+			Upstream:  upstream,
+			Fields:    diffFields(local, upstream),
+		})
+	}
+
+	if len(added) > 0 {
+		imported, err := s.importService.Import(ctx, c.ResumeID, c.Provider, added)
+		if err != nil {
+			return nil, err
+		}
+		result.Added = imported.Entries
+	}
+
+	now := time.Now().UTC()
+	c.LastSyncedAt = &now
+	c.Conflicts = result.Conflicts
+	if err := s.connectorRepo.UpdateConnectorSync(ctx, c); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Resolve settles the connector's pending conflict for an entry: keeping the
+// local edits, taking the source's value, or setting a value the user merged
+func (s *ConnectorService) Resolve(ctx context.Context, c *domain.Connector, entityID uuid.UUID, resolution string, value json.RawMessage) (*domain.Provenance, error) {
+	i := slices.IndexFunc(c.Conflicts, func(conflict *domain.ConnectorConflict) bool { return conflict.EntityID == entityID })
+	if i < 0 {
+		return nil, ErrConflictNotFound
+	}
+	conflict := c.Conflicts[i]
+
+	switch resolution {
+	case ResolutionLocal:
+		value = conflict.Local
+	case ResolutionUpstream:
+		value = conflict.Upstream
+	case ResolutionMerged:
+		if len(value) == 0 {
+			return nil, domain.NewValidationError("value", "A merged value is required", domain.ErrInvalidField)
+		}
+	default:
+		return nil, domain.NewValidationError("resolution", "Resolution must be one of local, upstream or merged", domain.ErrInvalidField)
+	}
+
+	provenance, err := s.importService.Resolve(ctx, c.ResumeID, conflict.Entity, conflict.EntityID, value, conflict.Upstream)
+	if err != nil && !errors.Is(err, ErrProvenanceNotFound) {
+		return nil, err
+	}
+
+	// A conflict over an entry since deleted is settled too
+	c.Conflicts = slices.Delete(c.Conflicts, i, i+1)
+	if updateErr := s.connectorRepo.UpdateConnectorSync(ctx, c); updateErr != nil {
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return provenance, nil
+}
+
+// upstreamValue returns a source's entry in the form provenance keeps values in
+func upstreamValue(entry connector.Entry) (json.RawMessage, error) {
+	value, err := json.Marshal(entry.Value)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeImportedEntry("value", entry.Entity, value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(decoded)
+}
+
+// jsonEqual reports whether two JSON values are the same
+func jsonEqual(a, b json.RawMessage) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return bytes.Equal(a, b)
+	}
+	ja, _ := json.Marshal(x)
+	jb, _ := json.Marshal(y)
+	return bytes.Equal(ja, jb)
+}
+
+// diffFields lists the top-level fields of two JSON objects that differ, by name
+func diffFields(a, b json.RawMessage) []string {
+	var x, y map[string]json.RawMessage
+	_ = json.Unmarshal(a, &x)
+	_ = json.Unmarshal(b, &y)
+
+	fields := []string{}
+	for name, value := range x {
+		if other, ok := y[name]; !ok || !jsonEqual(value, other) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range y {
+		if _, ok := x[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/connector"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connectorRepository keeps connectors in memory
+type connectorRepository struct {
+	domain.ConnectorRepository
+	connectors map[uuid.UUID]*domain.Connector
+}
+
+func (r *connectorRepository) CreateConnector(ctx context.Context, c *domain.Connector) error {
+	c.ID = uuid.New()
+	r.connectors[c.ID] = c
+	return nil
+}
+
+func (r *connectorRepository) DeleteConnectorsByResume(ctx context.Context, resumeID uuid.UUID) error {
+	for id, c := range r.connectors {
+		if c.ResumeID == resumeID {
+			delete(r.connectors, id)
+		}
+	}
+	return nil
+}
+
+func (r *connectorRepository) UpdateConnectorSync(ctx context.Context, c *domain.Connector) error {
+	if _, ok := r.connectors[c.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	r.connectors[c.ID] = c
+	return nil
+}
+
+// staticSource returns fixed entries
+type staticSource struct {
+	entries []connector.Entry
+}
+
+func (s *staticSource) Fetch(ctx context.Context, account string) ([]connector.Entry, error) {
+	return s.entries, nil
+}
+
+func repoEntry(ref, name, description string) connector.Entry {
+	return connector.Entry{
+		Entity:    domain.EventEntityProject,
+		SourceRef: ref,
+		Value:     &domain.Project{Name: name, Description: description, RepoURL: "https://github.com/octocat/" + name},
+	}
+}
+
+func TestConnectorServiceSync(t *testing.T) {
+	ctx := context.Background()
+	importService, resumeRepo, _ := newImportService()
+	source := &staticSource{entries: []connector.Entry{
+		repoEntry("repo/1", "hello", "Says hello"),
+		repoEntry("repo/2", "world", "Says world"),
+		repoEntry("repo/3", "broken", ""),
+	}}
+	source.entries[2].Value.(*domain.Project).Name = ""
+	connectorRepo := &connectorRepository{connectors: map[uuid.UUID]*domain.Connector{}}
+	svc := NewConnectorService(connectorRepo, resumeRepo, importService, map[string]connector.Source{domain.ConnectorGitHub: source})
+
+	c, err := svc.Create(ctx, uuid.New(), uuid.New(), domain.ConnectorGitHub, "octocat")
+	require.NoError(t, err)
+
+	// The first sync imports everything valid
+	result, err := svc.Sync(ctx, c)
+	require.NoError(t, err)
+	require.Len(t, result.Added, 2)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Len(t, resumeRepo.projects, 2)
+	assert.NotNil(t, c.LastSyncedAt)
+	hello, world := result.Added[0].EntityID, result.Added[1].EntityID
+
+	// Nothing changed
+	result, err = svc.Sync(ctx, c)
+	require.NoError(t, err)
+	assert.Empty(t, result.Added)
+	assert.Equal(t, 2, result.Unchanged)
+
+	// Both change upstream; the user edited one of them
+	resumeRepo.projects[world].Description = "My own words"
+	source.entries[0] = repoEntry("repo/1", "hello", "Says hello, louder")
+	source.entries[1] = repoEntry("repo/2", "world", "Says world, louder")
+	result, err = svc.Sync(ctx, c)
+	require.NoError(t, err)
+	require.Len(t, result.Updated, 1)
+	assert.Equal(t, hello, result.Updated[0].EntityID)
+	assert.Equal(t, "Says hello, louder", resumeRepo.projects[hello].Description)
+
+	// The edited entry is left alone and reported as a conflict
+	require.Len(t, result.Conflicts, 1)
+	conflict := result.Conflicts[0]
+	assert.Equal(t, world, conflict.EntityID)
+	assert.Equal(t, []string{"description"}, conflict.Fields)
+	assert.Contains(t, string(conflict.Base), "Says world")
+	assert.Contains(t, string(conflict.Local), "My own words")
+	assert.Contains(t, string(conflict.Upstream), "Says world, louder")
+	assert.Equal(t, "My own words", resumeRepo.projects[world].Description)
+	assert.Len(t, c.Conflicts, 1)
+
+	// Keeping the local edits settles the conflict for later syncs
+	_, err = svc.Resolve(ctx, c, world, "mine", nil)
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	_, err = svc.Resolve(ctx, c, world, ResolutionLocal, nil)
+	require.NoError(t, err)
+	assert.Empty(t, c.Conflicts)
+	assert.Equal(t, "My own words", resumeRepo.projects[world].Description)
+	_, err = svc.Resolve(ctx, c, world, ResolutionLocal, nil)
+	assert.ErrorIs(t, err, ErrConflictNotFound)
+
+	result, err = svc.Sync(ctx, c)
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, 2, result.Unchanged)
+}
+
+func TestConnectorServiceSyncDeletedResume(t *testing.T) {
+	ctx := context.Background()
+	importService, resumeRepo, _ := newImportService()
+	source := &staticSource{entries: []connector.Entry{repoEntry("repo/1", "hello", "Says hello")}}
+	svc := NewConnectorService(&connectorRepository{connectors: map[uuid.UUID]*domain.Connector{}}, resumeRepo, importService, map[string]connector.Source{domain.ConnectorGitHub: source})
+
+	c, err := svc.Create(ctx, uuid.New(), uuid.New(), domain.ConnectorGitHub, "octocat")
+	require.NoError(t, err)
+
+	// A connector left behind by its resume imports nothing
+	resumeRepo.deleted = map[uuid.UUID]bool{c.ResumeID: true}
+	_, err = svc.Sync(ctx, c)
+	assert.ErrorIs(t, err, ErrConnectorNotFound)
+	assert.Empty(t, resumeRepo.projects)
+}
+
+func TestConnectorServiceResolveMerged(t *testing.T) {
+	ctx := context.Background()
+	importService, resumeRepo, _ := newImportService()
+	source := &staticSource{entries: []connector.Entry{repoEntry("repo/1", "hello", "Says hello")}}
+	connectorRepo := &connectorRepository{connectors: map[uuid.UUID]*domain.Connector{}}
+	svc := NewConnectorService(connectorRepo, resumeRepo, importService, map[string]connector.Source{domain.ConnectorGitHub: source})
+
+	c, err := svc.Create(ctx, uuid.New(), uuid.New(), domain.ConnectorGitHub, "octocat")
+	require.NoError(t, err)
+	result, err := svc.Sync(ctx, c)
+	require.NoError(t, err)
+	id := result.Added[0].EntityID
+
+	resumeRepo.projects[id].Description = "Mine"
+	source.entries[0] = repoEntry("repo/1", "hello", "Theirs")
+	_, err = svc.Sync(ctx, c)
+	require.NoError(t, err)
+
+	merged := json.RawMessage(`{"name":"hello","description":"Mine and theirs","repo_url":"https://github.com/octocat/hello"}`)
+	p, err := svc.Resolve(ctx, c, id, ResolutionMerged, merged)
+	require.NoError(t, err)
+	assert.Equal(t, "Mine and theirs", resumeRepo.projects[id].Description)
+	// The source's value is what the entry reverts to
+	assert.Contains(t, string(p.Original), "Theirs")
+}
+
+func TestConnectorServiceCreateValidation(t *testing.T) {
+	svc := NewConnectorService(&connectorRepository{connectors: map[uuid.UUID]*domain.Connector{}}, nil, nil, map[string]connector.Source{domain.ConnectorGitHub: &staticSource{}})
+
+	var validationErr *domain.ValidationError
+	_, err := svc.Create(context.Background(), uuid.New(), uuid.New(), "myspace", "tom")
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "provider", validationErr.Field)
+	_, err = svc.Create(context.Background(), uuid.New(), uuid.New(), domain.ConnectorGitHub, "../admin")
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "account", validationErr.Field)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/rs/zerolog/log"
+)
+
+// JobTypePruneCSPViolations is the job type for deleting violations no longer reported
+const JobTypePruneCSPViolations = "csp_violations.prune"
+
+// Bounds on the reported values stored with a violation
+const (
+	maxCSPURILength       = 2048
+	maxCSPDirectiveLength = 100
+)
+
+// CSP report dispositions
+const (
+	cspDispositionEnforce = "enforce"
+	cspDispositionReport  = "report"
+)
+
+// CSPReportServiceConfig contains configuration for the CSP report service
+type CSPReportServiceConfig struct {
+	// Retention is how long a violation is kept after it was last reported
+	Retention time.Duration
+}
+
+// CSPReportService collects the Content-Security-Policy violations browsers
+// report, so admins can see what a stricter policy would break before
+// enforcing it. Reports are aggregated: URLs are reduced to the page path and
+// the blocked origin, and each distinct violation is stored once with a count.
+type CSPReportService struct {
+	violationRepo domain.CSPViolationRepository
+	config        CSPReportServiceConfig
+}
+
+// NewCSPReportService creates a new CSP report service
+func NewCSPReportService(violationRepo domain.CSPViolationRepository, config CSPReportServiceConfig) *CSPReportService {
+	// Set default values if not provided
+	if config.Retention == 0 {
+		config.Retention = 30 * 24 * time.Hour
+	}
+
+	return &CSPReportService{
+		violationRepo: violationRepo,
+		config:        config,
+	}
+}
+
+// Record stores reported violations. Reports without a directive are skipped.
+func (s *CSPReportService) Record(ctx context.Context, reports []security.CSPViolation) error {
+	now := time.Now().UTC()
+	for _, report := range reports {
+		violation := aggregateCSPViolation(report)
+		if violation.ViolatedDirective == "" {
+			continue
+		}
+		violation.LastSeenAt = now
+		if err := s.violationRepo.RecordViolation(ctx, violation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Violations returns a page of violations and the total matching the filters
+func (s *CSPReportService) Violations(ctx context.Context, directive string, opts domain.ListOptions) ([]*domain.CSPViolation, int64, error) {
+	return s.violationRepo.ListViolations(ctx, strings.ToLower(directive), opts)
+}
+
+// HandlePruneJob deletes violations that haven't been reported within the retention period
+func (s *CSPReportService) HandlePruneJob(ctx context.Context, job *jobs.Job) error {
+	deleted, err := s.violationRepo.DeleteViolationsSeenBefore(ctx, time.Now().Add(-s.config.Retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Ctx(ctx).Info().Int64("deleted", deleted).Msg("Pruned CSP violations")
+	}
+	return nil
+}
+
+// aggregateCSPViolation reduces a report to the fields violations are
+// counted by, dropping query strings that may carry tokens
+func aggregateCSPViolation(report security.CSPViolation) *domain.CSPViolation {
+	// Older browsers send the directive with its sources
+	directive := ""
+	if fields := strings.Fields(report.ViolatedDirective); len(fields) > 0 {
+		directive = truncateReported(strings.ToLower(fields[0]), maxCSPDirectiveLength)
+	}
+
+	disposition := cspDispositionEnforce
+	if strings.EqualFold(report.Disposition, cspDispositionReport) {
+		disposition = cspDispositionReport
+	}
+
+	return &domain.CSPViolation{
+		DocumentURI:       truncateReported(cspPageURI(report.DocumentURI), maxCSPURILength),
+		ViolatedDirective: directive,
+		BlockedURI:        truncateReported(cspBlockedSource(report.BlockedURI), maxCSPURILength),
+		Disposition:       disposition,
+		SourceFile:        truncateReported(cspPageURI(report.SourceFile), maxCSPURILength),
+		LineNumber:        max(report.LineNumber, 0),
+		ColumnNumber:      max(report.ColumnNumber, 0),
+	}
+}
+
+// cspPageURI strips the credentials, query and fragment from a URL
+func cspPageURI(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSpace(raw))
+	}
+	return u.Scheme + "://" + u.Host + u.EscapedPath()
+}
+
+// cspBlockedSource reduces a blocked URL to its origin, or to its scheme for
+// data:, blob: and similar URLs. Keywords such as inline and eval are kept.
+func cspBlockedSource(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(raw))
+	}
+	if u.Host != "" {
+		return u.Scheme + "://" + u.Host
+	}
+	if u.Scheme != "" {
+		return strings.ToLower(u.Scheme)
+	}
+	return strings.ToLower(strings.TrimSpace(raw))
+}
+
+// truncateReported cuts a reported value to at most n bytes without splitting a character
+func truncateReported(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
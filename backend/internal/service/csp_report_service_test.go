@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingViolationRepository keeps recorded violations in memory
+type recordingViolationRepository struct {
+	domain.CSPViolationRepository
+	recorded     []*domain.CSPViolation
+	prunedBefore time.Time
+}
+
+func (r *recordingViolationRepository) RecordViolation(ctx context.Context, violation *domain.CSPViolation) error {
+	r.recorded = append(r.recorded, violation)
+	return nil
+}
+
+func (r *recordingViolationRepository) DeleteViolationsSeenBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.prunedBefore = before
+	return 0, nil
+}
+
+func TestCSPReportServiceRecord(t *testing.T) {
+	ctx := context.Background()
+	repo := &recordingViolationRepository{}
+	svc := NewCSPReportService(repo, CSPReportServiceConfig{})
+
+	err := svc.Record(ctx, []security.CSPViolation{
+		{
+			DocumentURI:       "https://app.example.com/shared/abc?token=secret#top",
+			ViolatedDirective: "script-src-elem 'self'",
+			BlockedURI:        "https://cdn.example.net/lib.js?v=2",
+			SourceFile:        "https://app.example.com/main.js?v=1",
+			LineNumber:        12,
+			Disposition:       "report",
+		},
+		{DocumentURI: "https://app.example.com/", ViolatedDirective: "img-src", BlockedURI: "data:image/png;base64,AAAA"},
+		{DocumentURI: "https://app.example.com/", ViolatedDirective: "script-src", BlockedURI: "inline"},
+		// Reports without a directive are skipped
+		{DocumentURI: "https://app.example.com/"},
+	})
+	require.NoError(t, err)
+	require.Len(t, repo.recorded, 3)
+
+	// Queries are dropped and blocked URLs reduced to their origin
+	first := repo.recorded[0]
+	assert.Equal(t, "https://app.example.com/shared/abc", first.DocumentURI)
+	assert.Equal(t, "script-src-elem", first.ViolatedDirective)
+	assert.Equal(t, "https://cdn.example.net", first.BlockedURI)
+	assert.Equal(t, "https://app.example.com/main.js", first.SourceFile)
+	assert.Equal(t, 12, first.LineNumber)
+	assert.Equal(t, "report", first.Disposition)
+	assert.False(t, first.LastSeenAt.IsZero())
+
+	assert.Equal(t, "data", repo.recorded[1].BlockedURI)
+	assert.Equal(t, "enforce", repo.recorded[1].Disposition)
+	assert.Equal(t, "inline", repo.recorded[2].BlockedURI)
+}
+
+func TestCSPReportServicePrune(t *testing.T) {
+	repo := &recordingViolationRepository{}
+	svc := NewCSPReportService(repo, CSPReportServiceConfig{Retention: time.Hour})
+
+	require.NoError(t, svc.HandlePruneJob(context.Background(), nil))
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), repo.prunedBefore, time.Minute)
+}
+
+func TestTruncateReported(t *testing.T) {
+	assert.Equal(t, "abc", truncateReported("abc", 5))
+	// Multi-byte characters aren't split
+	assert.Equal(t, "a", truncateReported("aé", 2))
+}
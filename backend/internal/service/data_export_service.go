@@ -0,0 +1,554 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// Data export statuses
+const (
+	DataExportStatusPending = "pending"
+	DataExportStatusReady   = "ready"
+	DataExportStatusFailed  = "failed"
+)
+
+// Data export job types
+const (
+	// JobTypeGenerateDataExport is the job type for building a user's data archive
+	JobTypeGenerateDataExport = "data_export.generate"
+	// JobTypeExpireDataExport is the job type for deleting an archive once
+	// it is no longer available
+	JobTypeExpireDataExport = "data_export.expire"
+)
+
+// DataExportService errors
+var (
+	ErrDataExportNotFound = errors.New("data export not found")
+	ErrDataExportNotReady = errors.New("data export not ready")
+)
+
+// DataExportStatus describes the state of a user's data export
+type DataExportStatus struct {
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at"`
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// dataExportJobPayload identifies the export a generation job belongs to
+type dataExportJobPayload struct {
+	UserID      uuid.UUID `json:"user_id"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// DataExportServiceConfig contains configuration for the data export service
+type DataExportServiceConfig struct {
+	// ArchiveTTL is how long a generated archive is kept before it must be requested again
+	ArchiveTTL time.Duration
+	// GenerationTimeout bounds the time spent building a single archive
+	GenerationTimeout time.Duration
+}
+
+// dataExportPageSize is how many events or activities are read at a time
+const dataExportPageSize = 500
+
+// DataExportRepositories hold the data of other features that archives
+// include. Sections whose repository isn't set are left out.
+type DataExportRepositories struct {
+	Events     domain.ResumeEventRepository
+	Activity   domain.ResumeActivityRepository
+	Notes      domain.ResumeNoteRepository
+	Identities domain.UserIdentityRepository
+	Phones     domain.UserPhoneRepository
+	Passkeys   domain.PasskeyRepository
+	APIKeys    domain.APIKeyRepository
+}
+
+// DataExportService builds downloadable archives of everything stored about a
+// user. The status of an export is kept in the cache and its archive in file
+// storage, where the next export of the user replaces it.
+type DataExportService struct {
+	userRepo    domain.UserRepository
+	resumeRepo  domain.ResumeRepository
+	cache       cache.Cache
+	files       storage.Storage
+	queue       *jobs.Queue
+	mailService *MailService
+	repos       DataExportRepositories
+	config      DataExportServiceConfig
+}
+
+// NewDataExportService creates a new data export service
+func NewDataExportService(userRepo domain.UserRepository, resumeRepo domain.ResumeRepository, store cache.Cache, files storage.Storage, queue *jobs.Queue, mailService *MailService, config DataExportServiceConfig) *DataExportService {
+	// Set default values if not provided
+	if config.ArchiveTTL == 0 {
+		config.ArchiveTTL = 24 * time.Hour
+	}
+	if config.GenerationTimeout == 0 {
+		config.GenerationTimeout = 2 * time.Minute
+	}
+
+	return &DataExportService{
+		userRepo:    userRepo,
+		resumeRepo:  resumeRepo,
+		cache:       store,
+		files:       files,
+		queue:       queue,
+		mailService: mailService,
+		config:      config,
+	}
+}
+
+// SetRepositories adds the resume history, notes and account data kept by
+// other features to archives
+func (s *DataExportService) SetRepositories(repos DataExportRepositories) {
+	s.repos = repos
+}
+
+// RequestExport starts generating an export for the user unless one is already pending or ready
+func (s *DataExportService) RequestExport(ctx context.Context, userID uuid.UUID) (*DataExportStatus, error) {
+	status, err := s.GetStatus(ctx, userID)
+	if err == nil && status.Status != DataExportStatusFailed {
+		return status, nil
+	}
+	if err != nil && !errors.Is(err, ErrDataExportNotFound) {
+		return nil, err
+	}
+
+	status = &DataExportStatus{
+		Status:      DataExportStatusPending,
+		RequestedAt: time.Now().UTC(),
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claim the export slot atomically so concurrent requests don't start two generations.
+	// A failed export is simply overwritten so the user can retry.
+	ok, err := s.cache.SetNX(ctx, dataExportStatusKey(userID), data, s.config.ArchiveTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		existing, err := s.GetStatus(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if existing.Status != DataExportStatusFailed {
+			return existing, nil
+		}
+		if err := s.cache.Set(ctx, dataExportStatusKey(userID), data, s.config.ArchiveTTL); err != nil {
+			return nil, err
+		}
+	}
+
+	payload := dataExportJobPayload{UserID: userID, RequestedAt: status.RequestedAt}
+	// The user is waiting for the export, so it goes ahead of batch work
+	job, err := s.queue.Enqueue(ctx, JobTypeGenerateDataExport, payload,
+		jobs.WithMaxAttempts(3), jobs.WithPriority(jobs.PriorityHigh), jobs.WithOwner(userID.String()))
+	if err != nil {
+		// Release the slot so the user can retry
+		s.cache.Del(ctx, dataExportStatusKey(userID))
+		return nil, err
+	}
+	s.publish(ctx, userID, jobs.Event{JobID: job.ID, JobType: job.Type, Status: jobs.EventStatusQueued})
+
+	return status, nil
+}
+
+// GetStatus returns the current state of the user's export
+func (s *DataExportService) GetStatus(ctx context.Context, userID uuid.UUID) (*DataExportStatus, error) {
+	data, err := s.cache.Get(ctx, dataExportStatusKey(userID))
+	if err != nil {
+		if errors.Is(err, cache.ErrMiss) {
+			return nil, ErrDataExportNotFound
+		}
+		return nil, err
+	}
+
+	var status DataExportStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// GetArchive returns the generated ZIP archive for the user. The caller
+// closes its body.
+func (s *DataExportService) GetArchive(ctx context.Context, userID uuid.UUID) (*storage.Object, error) {
+	status, err := s.GetStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Status != DataExportStatusReady {
+		return nil, ErrDataExportNotReady
+	}
+
+	archive, err := s.files.Get(ctx, dataExportArchiveKey(userID))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrDataExportNotFound
+		}
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// HandleGenerateJob builds the archive, records the outcome and notifies the
+// user, publishing its progress as job events
+func (s *DataExportService) HandleGenerateJob(ctx context.Context, job *jobs.Job) error {
+	var payload dataExportJobPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return err
+	}
+	userID := payload.UserID
+	event := jobs.Event{JobID: job.ID, JobType: job.Type, Status: jobs.EventStatusRunning, Attempt: job.Attempts + 1}
+	s.publish(ctx, userID, event)
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.GenerationTimeout)
+	defer cancel()
+
+	archive, err := s.buildArchive(ctx, userID, func(progress int) {
+		event.Progress = progress
+		s.publish(ctx, userID, event)
+	})
+	if err == nil {
+		err = s.files.Put(ctx, dataExportArchiveKey(userID), bytes.NewReader(archive), "application/zip")
+	}
+
+	status := &DataExportStatus{
+		RequestedAt: payload.RequestedAt,
+		CompletedAt: time.Now().UTC(),
+	}
+
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to generate data export")
+		// Leave the export pending while the job still has retries left
+		if job.Attempts+1 < job.MaxAttempts {
+			s.publish(ctx, userID, jobs.Event{JobID: job.ID, JobType: job.Type, Status: jobs.EventStatusQueued,
+				Message: "Generation failed and will be retried", Attempt: event.Attempt})
+			return err
+		}
+		status.Status = DataExportStatusFailed
+		status.Error = "Failed to generate data export"
+	} else {
+		log.Ctx(ctx).Info().Str("user_id", userID.String()).Int("size", len(archive)).Msg("Data export ready")
+		status.Status = DataExportStatusReady
+	}
+
+	data, marshalErr := json.Marshal(status)
+	if marshalErr != nil {
+		return marshalErr
+	}
+	if setErr := s.cache.Set(ctx, dataExportStatusKey(userID), data, s.config.ArchiveTTL); setErr != nil {
+		log.Ctx(ctx).Error().Err(setErr).Str("user_id", userID.String()).Msg("Failed to store data export status")
+		return setErr
+	}
+	if err != nil {
+		s.publish(ctx, userID, jobs.Event{JobID: job.ID, JobType: job.Type, Status: jobs.EventStatusFailed,
+			Message: status.Error, Attempt: event.Attempt})
+		return err
+	}
+
+	s.publish(ctx, userID, jobs.Event{JobID: job.ID, JobType: job.Type, Status: jobs.EventStatusSucceeded,
+		Progress: 100, Attempt: event.Attempt})
+	expiresAt := status.CompletedAt.Add(s.config.ArchiveTTL)
+	if _, err := s.queue.Enqueue(ctx, JobTypeExpireDataExport, payload,
+		jobs.WithRunAt(expiresAt), jobs.WithPriority(jobs.PriorityLow), jobs.WithOwner(userID.String())); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to schedule data export expiry")
+	}
+	s.notifyReady(ctx, userID, expiresAt)
+	return nil
+}
+
+// HandleExpireJob deletes an archive once it is no longer available, unless
+// a newer export has replaced it since
+func (s *DataExportService) HandleExpireJob(ctx context.Context, job *jobs.Job) error {
+	var payload dataExportJobPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	status, err := s.GetStatus(ctx, payload.UserID)
+	if err != nil && !errors.Is(err, ErrDataExportNotFound) {
+		return err
+	}
+	if status != nil && !status.RequestedAt.Equal(payload.RequestedAt) {
+		return nil
+	}
+
+	if err := s.files.Delete(ctx, dataExportArchiveKey(payload.UserID)); err != nil {
+		return err
+	}
+	// The status may outlive the archive by a moment; without it the next
+	// request starts a new export
+	return s.cache.Del(ctx, dataExportStatusKey(payload.UserID))
+}
+
+// publish sends a job event to the user. Events only drive live progress
+// displays, so failing to send one doesn't fail the job.
+func (s *DataExportService) publish(ctx context.Context, userID uuid.UUID, event jobs.Event) {
+	if err := s.queue.PublishEvent(ctx, userID, event); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("user_id", userID.String()).Str("job_id", event.JobID).Msg("Failed to publish job event")
+	}
+}
+
+// notifyReady emails the user that their archive can be downloaded until expiresAt
+func (s *DataExportService) notifyReady(ctx context.Context, userID uuid.UUID, expiresAt time.Time) {
+	if s.mailService == nil {
+		return
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to load user for data export notification")
+		return
+	}
+
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "Your data export is ready",
+		Body: fmt.Sprintf("The export of your Resume Generator data is ready to download. It will be available until %s.",
+			formatLocalTime(user, expiresAt)),
+	}
+	if err := s.mailService.Send(ctx, msg); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("Failed to queue data export notification")
+	}
+}
+
+// BuildArchive collects all data stored about a user into a ZIP archive
+func (s *DataExportService) BuildArchive(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	return s.buildArchive(ctx, userID, func(int) {})
+}
+
+// buildArchive builds the archive, calling progress with the percentage of
+// resumes written so far
+func (s *DataExportService) buildArchive(ctx context.Context, userID uuid.UUID, progress func(int)) ([]byte, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.userRepo.GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resumes, err := s.resumeRepo.GetResumesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	// Profile (password hash is excluded by the domain JSON tags)
+	if err := writeJSONFile(zw, "profile.json", user); err != nil {
+		return nil, err
+	}
+
+	// Session metadata, without the refresh tokens themselves
+	type sessionExport struct {
+		ID         uuid.UUID `json:"id"`
+		UserAgent  string    `json:"user_agent"`
+		ClientIP   string    `json:"client_ip"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastUsedAt time.Time `json:"last_used_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+	}
+	sessionList := make([]sessionExport, len(sessions))
+	for i, session := range sessions {
+		sessionList[i] = sessionExport{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			ClientIP:   session.ClientIP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+	if err := writeJSONFile(zw, "sessions.json", sessionList); err != nil {
+		return nil, err
+	}
+
+	if err := s.writeAccountData(ctx, zw, userID); err != nil {
+		return nil, err
+	}
+
+	// Every resume with all of its sections. The archive is only done once
+	// stored, so progress stays below 100 here.
+	for i, resume := range resumes {
+		complete, err := s.resumeRepo.GetCompleteResume(ctx, resume.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeJSONFile(zw, fmt.Sprintf("resumes/%s.json", resume.ID), complete); err != nil {
+			return nil, err
+		}
+		if err := s.writeResumeHistory(ctx, zw, resume.ID); err != nil {
+			return nil, err
+		}
+		progress((i + 1) * 99 / len(resumes))
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeAccountData writes the linked sign-in accounts, phone number and
+// passkey and API key metadata of a user. Credentials and key hashes are
+// excluded by the domain JSON tags.
+func (s *DataExportService) writeAccountData(ctx context.Context, zw *zip.Writer, userID uuid.UUID) error {
+	if s.repos.Identities != nil {
+		identities, err := s.repos.Identities.GetIdentitiesByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := writeJSONFile(zw, "identities.json", identities); err != nil {
+			return err
+		}
+	}
+
+	if s.repos.Phones != nil {
+		phone, err := s.repos.Phones.GetPhone(ctx, userID)
+		switch {
+		case err == nil:
+			if err := writeJSONFile(zw, "phone.json", phone); err != nil {
+				return err
+			}
+		case !errors.Is(err, repository.ErrNotFound):
+			return err
+		}
+	}
+
+	if s.repos.Passkeys != nil {
+		passkeys, err := s.repos.Passkeys.GetPasskeysByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := writeJSONFile(zw, "passkeys.json", passkeys); err != nil {
+			return err
+		}
+	}
+
+	if s.repos.APIKeys != nil {
+		keys, err := s.repos.APIKeys.GetAPIKeysByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := writeJSONFile(zw, "api_keys.json", keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeResumeHistory writes the event log, activity feed and every note
+// version of a resume next to it. Notes stay encrypted with the user's key.
+func (s *DataExportService) writeResumeHistory(ctx context.Context, zw *zip.Writer, resumeID uuid.UUID) error {
+	if s.repos.Events != nil {
+		events := []*domain.ResumeEvent{}
+		for {
+			var after int64
+			if len(events) > 0 {
+				after = events[len(events)-1].Version
+			}
+			page, err := s.repos.Events.GetEventsAfter(ctx, resumeID, after, dataExportPageSize)
+			if err != nil {
+				return err
+			}
+			events = append(events, page...)
+			if len(page) < dataExportPageSize {
+				break
+			}
+		}
+		if err := writeJSONFile(zw, fmt.Sprintf("resumes/%s/events.json", resumeID), events); err != nil {
+			return err
+		}
+	}
+
+	if s.repos.Activity != nil {
+		activity := []*domain.ResumeActivity{}
+		for {
+			var before time.Time
+			var beforeID uuid.UUID
+			if len(activity) > 0 {
+				last := activity[len(activity)-1]
+				before, beforeID = last.CreatedAt, last.ID
+			}
+			page, err := s.repos.Activity.GetActivityBefore(ctx, resumeID, before, beforeID, dataExportPageSize)
+			if err != nil {
+				return err
+			}
+			activity = append(activity, page...)
+			if len(page) < dataExportPageSize {
+				break
+			}
+		}
+		if err := writeJSONFile(zw, fmt.Sprintf("resumes/%s/activity.json", resumeID), activity); err != nil {
+			return err
+		}
+	}
+
+	if s.repos.Notes != nil {
+		versions, err := s.repos.Notes.GetNoteVersions(ctx, resumeID)
+		if err != nil {
+			return err
+		}
+		if len(versions) > 0 {
+			notes := make([]*domain.ResumeNote, len(versions))
+			for i, version := range versions {
+				if notes[i], err = s.repos.Notes.GetNoteVersion(ctx, resumeID, version.Version); err != nil {
+					return err
+				}
+			}
+			if err := writeJSONFile(zw, fmt.Sprintf("resumes/%s/notes.json", resumeID), notes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeJSONFile writes an indented JSON document into the archive
+func writeJSONFile(zw *zip.Writer, name string, data any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// dataExportStatusKey returns the Redis key holding the export status for a user
+func dataExportStatusKey(userID uuid.UUID) string {
+	return "data_export:" + userID.String() + ":status"
+}
+
+// dataExportArchiveKey returns the storage key holding the export archive for a user
+func dataExportArchiveKey(userID uuid.UUID) string {
+	return "exports/" + userID.String() + ".zip"
+}
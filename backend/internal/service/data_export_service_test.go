@@ -0,0 +1,213 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportUserRepository has a single user without sessions
+type exportUserRepository struct {
+	domain.UserRepository
+	user *domain.User
+}
+
+func (r *exportUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.user, nil
+}
+
+func (r *exportUserRepository) GetSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error) {
+	return nil, nil
+}
+
+// exportResumeRepository has no resumes
+type exportResumeRepository struct {
+	domain.ResumeRepository
+}
+
+func (r *exportResumeRepository) GetResumesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Resume, error) {
+	return nil, nil
+}
+
+// exportHistoryRepository has the events, activity and note versions of
+// every resume and the account data of every user
+type exportHistoryRepository struct {
+	domain.ResumeEventRepository
+	domain.ResumeActivityRepository
+	domain.ResumeNoteRepository
+	domain.UserIdentityRepository
+	domain.UserPhoneRepository
+	domain.PasskeyRepository
+	domain.APIKeyRepository
+}
+
+func (r *exportHistoryRepository) GetEventsAfter(ctx context.Context, resumeID uuid.UUID, after int64, limit int) ([]*domain.ResumeEvent, error) {
+	if after > 0 {
+		return nil, nil
+	}
+	return []*domain.ResumeEvent{{ResumeID: resumeID, Version: 1, Entity: "resume", Op: "create"}}, nil
+}
+
+func (r *exportHistoryRepository) GetActivityBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*domain.ResumeActivity, error) {
+	if !before.IsZero() {
+		return nil, nil
+	}
+	return []*domain.ResumeActivity{{ID: uuid.New(), ResumeID: resumeID, Kind: domain.ActivityExport, CreatedAt: time.Now()}}, nil
+}
+
+func (r *exportHistoryRepository) GetNoteVersions(ctx context.Context, resumeID uuid.UUID) ([]*domain.ResumeNoteVersion, error) {
+	return []*domain.ResumeNoteVersion{{Version: 2}, {Version: 1}}, nil
+}
+
+func (r *exportHistoryRepository) GetNoteVersion(ctx context.Context, resumeID uuid.UUID, version int) (*domain.ResumeNote, error) {
+	return &domain.ResumeNote{ResumeID: resumeID, Version: version, Algorithm: "AES-256-GCM", Ciphertext: []byte("sealed")}, nil
+}
+
+func (r *exportHistoryRepository) GetIdentitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.UserIdentity, error) {
+	return []*domain.UserIdentity{{UserID: userID, Provider: "github", Subject: "42"}}, nil
+}
+
+func (r *exportHistoryRepository) GetPhone(ctx context.Context, userID uuid.UUID) (*domain.UserPhone, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (r *exportHistoryRepository) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Passkey, error) {
+	return []*domain.Passkey{{UserID: userID, Name: "Laptop", CredentialID: []byte("credential")}}, nil
+}
+
+func (r *exportHistoryRepository) GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	return []*domain.APIKey{{UserID: userID, Name: "CI", Prefix: "rk_abc", KeyHash: "hash"}}, nil
+}
+
+// historyResumeRepository has one resume
+type historyResumeRepository struct {
+	domain.ResumeRepository
+	resume *domain.Resume
+}
+
+func (r *historyResumeRepository) GetResumesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Resume, error) {
+	return []*domain.Resume{r.resume}, nil
+}
+
+func (r *historyResumeRepository) GetCompleteResume(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	return r.resume, nil
+}
+
+// runExportJob runs a data export job as the worker would
+func runExportJob(t *testing.T, handle func(context.Context, *jobs.Job) error, jobType string, userID uuid.UUID, status *DataExportStatus) {
+	payload, err := json.Marshal(dataExportJobPayload{UserID: userID, RequestedAt: status.RequestedAt})
+	require.NoError(t, err)
+	require.NoError(t, handle(context.Background(), &jobs.Job{Type: jobType, Payload: payload, MaxAttempts: 3}))
+}
+
+func TestDataExportArchiveStorage(t *testing.T) {
+	ctx := context.Background()
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	mr := miniredis.RunT(t)
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})})
+	files := storage.NewMemory()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com"}
+	svc := NewDataExportService(&exportUserRepository{user: user}, &exportResumeRepository{}, store, files, queue, nil, DataExportServiceConfig{})
+
+	status, err := svc.RequestExport(ctx, user.ID)
+	require.NoError(t, err)
+	_, err = svc.GetArchive(ctx, user.ID)
+	assert.ErrorIs(t, err, ErrDataExportNotReady)
+
+	// The archive is kept in file storage
+	runExportJob(t, svc.HandleGenerateJob, JobTypeGenerateDataExport, user.ID, status)
+	archive, err := svc.GetArchive(ctx, user.ID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(archive.Body)
+	require.NoError(t, err)
+	archive.Body.Close()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "profile.json", zr.File[0].Name)
+
+	// Expiry jobs of replaced exports leave the newer archive
+	stale := &DataExportStatus{RequestedAt: status.RequestedAt.Add(-1)}
+	runExportJob(t, svc.HandleExpireJob, JobTypeExpireDataExport, user.ID, stale)
+	_, err = files.Get(ctx, dataExportArchiveKey(user.ID))
+	require.NoError(t, err)
+
+	runExportJob(t, svc.HandleExpireJob, JobTypeExpireDataExport, user.ID, status)
+	_, err = files.Get(ctx, dataExportArchiveKey(user.ID))
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = svc.GetStatus(ctx, user.ID)
+	assert.ErrorIs(t, err, ErrDataExportNotFound)
+}
+
+func TestDataExportArchiveContents(t *testing.T) {
+	ctx := context.Background()
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	mr := miniredis.RunT(t)
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})})
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com"}
+	resume := &domain.Resume{ID: uuid.New(), UserID: user.ID}
+	history := &exportHistoryRepository{}
+	svc := NewDataExportService(&exportUserRepository{user: user}, &historyResumeRepository{resume: resume}, store, storage.NewMemory(), queue, nil, DataExportServiceConfig{})
+	svc.SetRepositories(DataExportRepositories{
+		Events: history, Activity: history, Notes: history, Identities: history,
+		Phones: history, Passkeys: history, APIKeys: history,
+	})
+
+	status, err := svc.RequestExport(ctx, user.ID)
+	require.NoError(t, err)
+	runExportJob(t, svc.HandleGenerateJob, JobTypeGenerateDataExport, user.ID, status)
+	archive, err := svc.GetArchive(ctx, user.ID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(archive.Body)
+	require.NoError(t, err)
+	archive.Body.Close()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+
+	// Account data is included, without secrets; users without a phone
+	// number get no phone.json
+	assert.Contains(t, files["identities.json"], `"github"`)
+	assert.Contains(t, files["passkeys.json"], `"Laptop"`)
+	assert.NotContains(t, files["passkeys.json"], "credential_id")
+	assert.Contains(t, files["api_keys.json"], `"rk_abc"`)
+	assert.NotContains(t, files["api_keys.json"], "hash")
+	assert.NotContains(t, files, "phone.json")
+
+	// So is the history of each resume, with every version of its note
+	dir := "resumes/" + resume.ID.String() + "/"
+	assert.Contains(t, files[dir+"events.json"], `"create"`)
+	assert.Contains(t, files[dir+"activity.json"], `"`+domain.ActivityExport+`"`)
+	var notes []*domain.ResumeNote
+	require.NoError(t, json.Unmarshal([]byte(files[dir+"notes.json"]), &notes))
+	require.Len(t, notes, 2)
+	assert.Equal(t, 2, notes[0].Version)
+	assert.Equal(t, []byte("sealed"), notes[1].Ciphertext)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/rs/zerolog/log"
+)
+
+// JobTypeSendWeeklyDigests is the job type for emailing the weekly digests that are due
+const JobTypeSendWeeklyDigests = "digest.send_weekly"
+
+// digestDueSlack lets a digest go out on the run just before it is exactly one
+// interval old, so send times don't drift later by one job period each week
+const digestDueSlack = 5 * time.Minute
+
+// DigestServiceConfig contains configuration for the digest service
+type DigestServiceConfig struct {
+	// Interval is the time between two digests to the same user
+	Interval time.Duration
+	// BatchSize is the number of recipients loaded at a time
+	BatchSize int
+}
+
+// DigestService emails users who opted in a weekly summary of their resume
+// activity with tips for completing their resumes
+type DigestService struct {
+	userRepo    domain.UserRepository
+	resumeRepo  domain.ResumeRepository
+	eventRepo   domain.ResumeEventRepository
+	mailService *MailService
+	config      DigestServiceConfig
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(userRepo domain.UserRepository, resumeRepo domain.ResumeRepository, eventRepo domain.ResumeEventRepository, mailService *MailService, config DigestServiceConfig) *DigestService {
+	// Set default values if not provided
+	if config.Interval == 0 {
+		config.Interval = 7 * 24 * time.Hour
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 100
+	}
+
+	return &DigestService{
+		userRepo:    userRepo,
+		resumeRepo:  resumeRepo,
+		eventRepo:   eventRepo,
+		mailService: mailService,
+		config:      config,
+	}
+}
+
+// HandleSendDigestsJob emails every opted-in user whose digest is due. It runs
+// more often than the interval; each user's last send time decides who is due.
+func (s *DigestService) HandleSendDigestsJob(ctx context.Context, job *jobs.Job) error {
+	now := time.Now().UTC()
+	sentBefore := now.Add(-s.config.Interval + digestDueSlack)
+
+	sent := 0
+	afterID := uuid.Nil
+	for {
+		users, err := s.userRepo.ListDigestRecipients(ctx, sentBefore, afterID, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			if err := s.send(ctx, user, now); err != nil {
+				// Leave the digest due so the next run tries again
+				log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send weekly digest")
+				continue
+			}
+			sent++
+		}
+
+		if len(users) < s.config.BatchSize {
+			break
+		}
+		afterID = users[len(users)-1].ID
+	}
+
+	if sent > 0 {
+		log.Ctx(ctx).Info().Int("sent", sent).Msg("Sent weekly digests")
+	}
+	return nil
+}
+
+// send queues the user's digest and records when it was sent
+func (s *DigestService) send(ctx context.Context, user *domain.User, now time.Time) error {
+	msg, err := s.Compose(ctx, user, now)
+	if err != nil {
+		return err
+	}
+	if err := s.mailService.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	return s.userRepo.MarkDigestSent(ctx, user.ID, now)
+}
+
+// Compose builds the user's digest covering the interval before now
+func (s *DigestService) Compose(ctx context.Context, user *domain.User, now time.Time) (*mail.Message, error) {
+	since := now.Add(-s.config.Interval)
+
+	resumes, err := s.resumeRepo.GetResumesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(resumes))
+	for i, resume := range resumes {
+		ids[i] = resume.ID
+	}
+	changes, err := s.eventRepo.CountEventsSince(ctx, ids, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Here is what happened with your resumes since %s.\n\n", formatLocalTime(user, since))
+
+	body.WriteString("Activity\n")
+	active := 0
+	for _, resume := range resumes {
+		if n := changes[resume.ID]; n > 0 {
+			fmt.Fprintf(&body, "- %s: %s\n", digestResumeTitle(resume), pluralize(n, "change", "changes"))
+			active++
+		}
+	}
+	if active == 0 {
+		body.WriteString("- No changes to your resumes this week.\n")
+	}
+
+	var tips []string
+	if len(resumes) == 0 {
+		tips = append(tips, "Create your first resume to get started.")
+	}
+	for _, resume := range resumes {
+		complete, err := s.resumeRepo.GetCompleteResume(ctx, resume.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tip := range completenessTips(complete) {
+			tips = append(tips, fmt.Sprintf("%s: %s", digestResumeTitle(resume), tip))
+		}
+	}
+	if len(tips) > 0 {
+		body.WriteString("\nTips to complete your resumes\n")
+		for _, tip := range tips {
+			fmt.Fprintf(&body, "- %s\n", tip)
+		}
+	}
+
+	body.WriteString("\nYou are receiving this email because you turned on the weekly digest. You can turn it off in your preferences.")
+
+	return &mail.Message{
+		To:      user.Email,
+		Subject: "Your weekly resume digest",
+		Body:    body.String(),
+	}, nil
+}
+
+// completenessTips suggests what is missing from resume, most important first
+func completenessTips(resume *domain.Resume) []string {
+	var tips []string
+	if resume.PersonalInfo == nil {
+		tips = append(tips, "add your name and contact details")
+	} else if strings.TrimSpace(resume.PersonalInfo.JobTitle) == "" {
+		tips = append(tips, "add a job title under your name")
+	}
+	if len(resume.Experience) == 0 {
+		tips = append(tips, "add your work experience")
+	}
+	for _, experience := range resume.Experience {
+		if len(experience.Achievements) == 0 {
+			tips = append(tips, fmt.Sprintf("list achievements for your role at %s", experience.Employer))
+		}
+	}
+	if len(resume.Education) == 0 {
+		tips = append(tips, "add your education")
+	}
+	if len(resume.Skills) == 0 {
+		tips = append(tips, "add your skills")
+	}
+	return tips
+}
+
+// digestResumeTitle names resume in a digest
+func digestResumeTitle(resume *domain.Resume) string {
+	if title := strings.TrimSpace(resume.Title); title != "" {
+		return fmt.Sprintf("%q", title)
+	}
+	return "Untitled resume"
+}
+
+// pluralize formats n with the singular or plural noun
+func pluralize(n int64, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
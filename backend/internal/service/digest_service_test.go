@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingEventRepository returns fixed event counts
+type countingEventRepository struct {
+	domain.ResumeEventRepository
+	counts map[uuid.UUID]int64
+	since  time.Time
+}
+
+func (r *countingEventRepository) CountEventsSince(ctx context.Context, resumeIDs []uuid.UUID, since time.Time) (map[uuid.UUID]int64, error) {
+	r.since = since
+	return r.counts, nil
+}
+
+func TestDigestCompose(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Timezone: "America/Mexico_City"}
+	finished := &domain.Resume{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		ResumeMetadata: domain.ResumeMetadata{Title: "Backend"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Ana", JobTitle: "Engineer"},
+		Experience:     []*domain.Experience{{Employer: "Acme", Achievements: []string{"Shipped"}}},
+		Education:      []*domain.Education{{Institution: "UNAM"}},
+		Skills:         []*domain.Skill{{Name: "Go"}},
+	}
+	draft := &domain.Resume{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		PersonalInfo: &domain.PersonalInfo{FirstName: "Ana"},
+		Experience:   []*domain.Experience{{Employer: "Initech"}},
+	}
+	resumes := &translationRepository{resumes: map[uuid.UUID]*domain.Resume{finished.ID: finished, draft.ID: draft}}
+	events := &countingEventRepository{counts: map[uuid.UUID]int64{finished.ID: 3}}
+	svc := NewDigestService(nil, resumes, events, nil, DigestServiceConfig{})
+
+	now := time.Date(2025, 4, 14, 15, 0, 0, 0, time.UTC)
+	msg, err := svc.Compose(ctx, user, now)
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, msg.To)
+	assert.Equal(t, now.Add(-7*24*time.Hour), events.since)
+
+	// The period starts in the user's time zone
+	assert.Contains(t, msg.Body, "Monday, April 7, 2025 at 09:00 CST")
+	assert.Contains(t, msg.Body, `"Backend": 3 changes`)
+	assert.NotContains(t, msg.Body, "Untitled resume: 0")
+
+	// Only the draft needs work
+	assert.Contains(t, msg.Body, "Untitled resume: add a job title under your name")
+	assert.Contains(t, msg.Body, "Untitled resume: list achievements for your role at Initech")
+	assert.Contains(t, msg.Body, "Untitled resume: add your education")
+	assert.Contains(t, msg.Body, "Untitled resume: add your skills")
+	assert.NotContains(t, msg.Body, `"Backend": add`)
+	require.NoError(t, msg.Validate())
+
+	// Users without resumes are pointed at creating one
+	msg, err = svc.Compose(ctx, &domain.User{ID: uuid.New(), Email: "new@example.com"}, now)
+	require.NoError(t, err)
+	assert.Contains(t, msg.Body, "No changes to your resumes this week")
+	assert.Contains(t, msg.Body, "Create your first resume")
+}
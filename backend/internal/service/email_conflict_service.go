@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrEmailConflictNotFound is returned when no account was set aside for
+// the user's address
+var ErrEmailConflictNotFound = errors.New("no account set aside for this address")
+
+// EmailConflictService merges accounts set aside when emails became
+// case-insensitive into the account holding their address. Addresses were
+// never verified, so accounts are only merged once one person proves they
+// own both.
+type EmailConflictService struct {
+	conflictRepo domain.UserEmailConflictRepository
+	userRepo     domain.UserRepository
+}
+
+// NewEmailConflictService creates a new email conflict service
+func NewEmailConflictService(conflictRepo domain.UserEmailConflictRepository, userRepo domain.UserRepository) *EmailConflictService {
+	return &EmailConflictService{
+		conflictRepo: conflictRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// MergeConflictingAccount merges an account set aside for the address of
+// userID into userID. The caller is signed in as userID and confirms owning
+// the other account with its password. The other account's resume data
+// moves to userID; its credentials are deleted with it.
+func (s *EmailConflictService) MergeConflictingAccount(ctx context.Context, userID uuid.UUID, password string) (*domain.UserEmailConflict, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	conflicts, err := s.conflictRepo.GetConflictsByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return nil, ErrEmailConflictNotFound
+	}
+
+	for _, conflict := range conflicts {
+		other, err := s.userRepo.GetUserByID(ctx, conflict.UserID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Accounts created with an OAuth provider have no password to confirm
+		if other.PasswordHash == "" {
+			continue
+		}
+		match, err := security.VerifyPassword(password, other.PasswordHash)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Failed to verify password")
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+
+		if err := s.conflictRepo.MergeUser(ctx, other.ID, user.ID); err != nil {
+			return nil, err
+		}
+		log.Ctx(ctx).Info().Str("user_id", user.ID.String()).Str("merged_user_id", other.ID.String()).Msg("Merged account set aside for the same address")
+		return conflict, nil
+	}
+
+	return nil, ErrInvalidCredentials
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEmailConflictRepository keeps conflicts in memory and records merges
+type memoryEmailConflictRepository struct {
+	conflicts []*domain.UserEmailConflict
+	users     *identityUserRepository
+	merged    map[uuid.UUID]uuid.UUID
+}
+
+func (r *memoryEmailConflictRepository) GetConflictsByEmail(ctx context.Context, email string) ([]*domain.UserEmailConflict, error) {
+	var conflicts []*domain.UserEmailConflict
+	for _, conflict := range r.conflicts {
+		if strings.EqualFold(conflict.Email, email) {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts, nil
+}
+
+func (r *memoryEmailConflictRepository) MergeUser(ctx context.Context, fromID, intoID uuid.UUID) error {
+	r.merged[fromID] = intoID
+	delete(r.users.users, fromID)
+	return nil
+}
+
+func TestMergeConflictingAccount(t *testing.T) {
+	ctx := context.Background()
+	hash := func(password string) string {
+		h, err := security.HashPassword(password, nil)
+		require.NoError(t, err)
+		return h
+	}
+
+	holder := &domain.User{ID: uuid.New(), Email: "ana@example.com", PasswordHash: hash("holder password")}
+	setAside := &domain.User{ID: uuid.New(), Email: setAsideEmail(), PasswordHash: hash("set aside password")}
+	oauthOnly := &domain.User{ID: uuid.New(), Email: setAsideEmail()}
+	other := &domain.User{ID: uuid.New(), Email: "bo@example.com"}
+	users := &identityUserRepository{users: map[uuid.UUID]*domain.User{
+		holder.ID: holder, setAside.ID: setAside, oauthOnly.ID: oauthOnly, other.ID: other,
+	}}
+	repo := &memoryEmailConflictRepository{
+		conflicts: []*domain.UserEmailConflict{
+			{UserID: oauthOnly.ID, Email: "ANA@example.com"},
+			{UserID: setAside.ID, Email: "Ana@Example.com"},
+		},
+		users:  users,
+		merged: map[uuid.UUID]uuid.UUID{},
+	}
+	svc := NewEmailConflictService(repo, users)
+
+	// Nothing was set aside for other addresses
+	_, err := svc.MergeConflictingAccount(ctx, other.ID, "set aside password")
+	assert.ErrorIs(t, err, ErrEmailConflictNotFound)
+
+	// Holding the address isn't enough; the other account's password is
+	// needed too
+	_, err = svc.MergeConflictingAccount(ctx, holder.ID, "holder password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Empty(t, repo.merged)
+
+	conflict, err := svc.MergeConflictingAccount(ctx, holder.ID, "set aside password")
+	require.NoError(t, err)
+	assert.Equal(t, setAside.ID, conflict.UserID)
+	assert.Equal(t, map[uuid.UUID]uuid.UUID{setAside.ID: holder.ID}, repo.merged)
+}
+
+// setAsideEmail returns a placeholder address like the one accounts are
+// moved to
+func setAsideEmail() string {
+	return uuid.NewString() + "@email-conflict.invalid"
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+)
+
+// MaxImportEntries is the most entries one import may add
+const MaxImportEntries = 200
+
+// ErrProvenanceNotFound is returned when an entry wasn't imported, or no
+// longer exists
+var ErrProvenanceNotFound = errors.New("imported entry not found")
+
+// ImportEntry is an entry of an import, as its source has it
+type ImportEntry struct {
+	// Entity is the section of the entry, with the entities of the event log
+	Entity    string          `json:"entity"`
+	SourceRef string          `json:"source_ref,omitempty"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// ImportResult reports the entries an import added
+type ImportResult struct {
+	ImportID uuid.UUID            `json:"import_id"`
+	Entries  []*domain.Provenance `json:"entries"`
+}
+
+// importedEntry is an entry of any importable section
+type importedEntry interface {
+	Validate() error
+	BeforeSave()
+}
+
+// EntryImportService adds resume entries from LinkedIn, GitHub and file
+// imports, recording the provenance of each so its user can see where it came
+// from, revert their edits to the imported value, or re-sync it when the
+// source changes
+type EntryImportService struct {
+	resumeRepo     domain.ResumeRepository
+	provenanceRepo domain.ProvenanceRepository
+	transactor     domain.Transactor
+}
+
+// NewEntryImportService creates a new entry import service
+func NewEntryImportService(resumeRepo domain.ResumeRepository, provenanceRepo domain.ProvenanceRepository) *EntryImportService {
+	return &EntryImportService{
+		resumeRepo:     resumeRepo,
+		provenanceRepo: provenanceRepo,
+	}
+}
+
+// SetTransactor adds the entries of an import and their provenance in one
+// transaction. Without it, entries added before a failure are kept.
+func (s *EntryImportService) SetTransactor(transactor domain.Transactor) {
+	s.transactor = transactor
+}
+
+// Import validates every entry, then adds them all to the resume with
+// resumeID under a new import ID
+func (s *EntryImportService) Import(ctx context.Context, resumeID uuid.UUID, source string, entries []ImportEntry) (*ImportResult, error) {
+	if !slices.Contains(domain.ImportSources, source) {
+		return nil, domain.NewValidationError("source", "Source must be one of text, file, linkedin, github or credly", domain.ErrInvalidField)
+	}
+	if len(entries) == 0 {
+		return nil, domain.NewValidationError("entries", "At least one entry is required", domain.ErrInvalidField)
+	}
+	if len(entries) > MaxImportEntries {
+		return nil, domain.NewValidationError("entries", fmt.Sprintf("An import may add at most %d entries", MaxImportEntries), domain.ErrInvalidField)
+	}
+
+	decoded := make([]importedEntry, len(entries))
+	for i, entry := range entries {
+		e, err := decodeImportedEntry(fmt.Sprintf("entries[%d]", i), entry.Entity, entry.Value)
+		if err != nil {
+			return nil, err
+		}
+		decoded[i] = e
+	}
+
+	now := time.Now().UTC()
+	result := &ImportResult{ImportID: uuid.New(), Entries: make([]*domain.Provenance, 0, len(entries))}
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		for i, entry := range entries {
+			id, err := s.addEntry(ctx, resumeID, decoded[i])
+			if err != nil {
+				return err
+			}
+
+			// Keep the entry as stored, after sanitizing
+			original, err := json.Marshal(decoded[i])
+			if err != nil {
+				return err
+			}
+			provenance := &domain.Provenance{
+				ResumeID:   resumeID,
+				Entity:     entry.Entity,
+				EntityID:   id,
+				Source:     source,
+				ImportID:   result.ImportID,
+				SourceRef:  entry.SourceRef,
+				Original:   original,
+				ImportedAt: now,
+				SyncedAt:   now,
+			}
+			if err := s.provenanceRepo.SaveProvenance(ctx, provenance); err != nil {
+				return err
+			}
+			result.Entries = append(result.Entries, provenance)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Provenance lists the provenance of a resume's imported entries
+func (s *EntryImportService) Provenance(ctx context.Context, resumeID uuid.UUID) ([]*domain.Provenance, error) {
+	return s.provenanceRepo.ListProvenance(ctx, resumeID)
+}
+
+// Revert restores an imported entry to its imported, or last re-synced, value
+func (s *EntryImportService) Revert(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) (*domain.Provenance, error) {
+	provenance, err := s.getProvenance(ctx, resumeID, entity, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := decodeImportedEntry("original", entity, provenance.Original)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.updateEntry(ctx, resumeID, entityID, entry); err != nil {
+		return nil, s.entryError(ctx, provenance, err)
+	}
+
+	return provenance, nil
+}
+
+// Resync replaces an imported entry with the value its source has now,
+// which becomes the value it reverts to
+func (s *EntryImportService) Resync(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID, value json.RawMessage) (*domain.Provenance, error) {
+	return s.Resolve(ctx, resumeID, entity, entityID, value, value)
+}
+
+// Resolve replaces an imported entry with value and records upstream as the
+// value its source has now, which it reverts to. They differ when a user
+// keeps their edits, or merges them, over a change at the source.
+func (s *EntryImportService) Resolve(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID, value, upstream json.RawMessage) (*domain.Provenance, error) {
+	provenance, err := s.getProvenance(ctx, resumeID, entity, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := decodeImportedEntry("value", entity, value)
+	if err != nil {
+		return nil, err
+	}
+	upstreamEntry, err := decodeImportedEntry("upstream", entity, upstream)
+	if err != nil {
+		return nil, err
+	}
+	original, err := json.Marshal(upstreamEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.updateEntry(ctx, resumeID, entityID, entry); err != nil {
+			return err
+		}
+		provenance.Original = original
+		provenance.SyncedAt = time.Now().UTC()
+		return s.provenanceRepo.SaveProvenance(ctx, provenance)
+	})
+	if err != nil {
+		// Outside the transaction, so a stale provenance isn't rolled back
+		return nil, s.entryError(ctx, provenance, err)
+	}
+
+	return provenance, nil
+}
+
+// Entry returns an imported entry as it is now, in the form its provenance
+// keeps values in. It returns ErrProvenanceNotFound when the entry is gone.
+func (s *EntryImportService) Entry(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) (json.RawMessage, error) {
+	var entry importedEntry
+	var err error
+	switch entity {
+	case domain.EventEntityExperience:
+		entry, err = s.resumeRepo.GetExperience(ctx, resumeID, entityID)
+	case domain.EventEntityEducation:
+		entry, err = s.resumeRepo.GetEducation(ctx, entityID)
+	case domain.EventEntitySkill:
+		entry, err = s.resumeRepo.GetSkill(ctx, entityID)
+	case domain.EventEntityProject:
+		entry, err = s.resumeRepo.GetProject(ctx, entityID)
+	case domain.EventEntityCertification:
+		entry, err = s.resumeRepo.GetCertification(ctx, entityID)
+	default:
+		return nil, ErrProvenanceNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProvenanceNotFound
+		}
+		return nil, err
+	}
+
+	entry.BeforeSave()
+	canonicalize(entry)
+	return json.Marshal(entry)
+}
+
+// getProvenance returns the provenance of an entry
+func (s *EntryImportService) getProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) (*domain.Provenance, error) {
+	provenance, err := s.provenanceRepo.GetProvenance(ctx, resumeID, entity, entityID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProvenanceNotFound
+		}
+		return nil, err
+	}
+	return provenance, nil
+}
+
+// entryError maps the error of updating an imported entry. An entry the
+// user deleted since its import takes its provenance with it.
+func (s *EntryImportService) entryError(ctx context.Context, provenance *domain.Provenance, err error) error {
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if err := s.provenanceRepo.DeleteProvenance(ctx, provenance.ResumeID, provenance.Entity, provenance.EntityID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return ErrProvenanceNotFound
+}
+
+// decodeImportedEntry decodes value into the section type of entity,
+// sanitizes and validates it. field names value in validation errors.
+func decodeImportedEntry(field, entity string, value json.RawMessage) (importedEntry, error) {
+	var entry importedEntry
+	switch entity {
+	case domain.EventEntityExperience:
+		entry = &domain.Experience{}
+	case domain.EventEntityEducation:
+		entry = &domain.Education{}
+	case domain.EventEntitySkill:
+		entry = &domain.Skill{}
+	case domain.EventEntityProject:
+		entry = &domain.Project{}
+	case domain.EventEntityCertification:
+		entry = &domain.Certification{}
+	default:
+		return nil, domain.NewValidationError(field, "Entity must be one of experience, education, skill, project or certification", domain.ErrInvalidField)
+	}
+
+	if err := json.Unmarshal(value, entry); err != nil {
+		return nil, domain.NewValidationError(field, fmt.Sprintf("Value must be a %s entry", entity), domain.ErrInvalidField)
+	}
+	entry.BeforeSave()
+	if err := entry.Validate(); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, domain.NewValidationError(field+"."+validationErr.Field, validationErr.Message, validationErr.Err)
+		}
+		return nil, err
+	}
+	canonicalize(entry)
+	return entry, nil
+}
+
+// canonicalize puts an entry in the form the repository reads entries back
+// in, so an entry compares equal to its stored copy: open-ended dates carry
+// their placeholder, technologies are sorted and achievements, which aren't
+// stored, are dropped
+func canonicalize(entry importedEntry) {
+	switch e := entry.(type) {
+	case *domain.Experience:
+		if e.EndDate == "" {
+			e.EndDate = "Present"
+		}
+		e.Achievements = nil
+	case *domain.Education:
+		if e.EndDate == "" {
+			e.EndDate = "Present"
+		}
+	case *domain.Project:
+		if e.EndDate == "" {
+			e.EndDate = "Present"
+		}
+		slices.Sort(e.Technologies)
+		e.Technologies = slices.Compact(e.Technologies)
+	case *domain.Certification:
+		if e.ExpiryDate == "" {
+			e.ExpiryDate = "No Expiration"
+		}
+	}
+}
+
+// addEntry adds an entry to its section
+func (s *EntryImportService) addEntry(ctx context.Context, resumeID uuid.UUID, entry importedEntry) (uuid.UUID, error) {
+	switch e := entry.(type) {
+	case *domain.Experience:
+		return s.resumeRepo.AddExperience(ctx, resumeID, e)
+	case *domain.Education:
+		return s.resumeRepo.AddEducation(ctx, resumeID, e)
+	case *domain.Skill:
+		return s.resumeRepo.AddSkill(ctx, resumeID, e)
+	case *domain.Project:
+		return s.resumeRepo.AddProject(ctx, resumeID, e)
+	case *domain.Certification:
+		return s.resumeRepo.AddCertification(ctx, resumeID, e)
+	}
+	return uuid.Nil, fmt.Errorf("unsupported entry type %T", entry)
+}
+
+// updateEntry replaces an entry of its section
+func (s *EntryImportService) updateEntry(ctx context.Context, resumeID, id uuid.UUID, entry importedEntry) error {
+	switch e := entry.(type) {
+	case *domain.Experience:
+		return s.resumeRepo.UpdateExperience(ctx, resumeID, id, e)
+	case *domain.Education:
+		return s.resumeRepo.UpdateEducation(ctx, resumeID, id, e)
+	case *domain.Skill:
+		return s.resumeRepo.UpdateSkill(ctx, resumeID, id, e)
+	case *domain.Project:
+		return s.resumeRepo.UpdateProject(ctx, resumeID, id, e)
+	case *domain.Certification:
+		return s.resumeRepo.UpdateCertification(ctx, resumeID, id, e)
+	}
+	return fmt.Errorf("unsupported entry type %T", entry)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importRepository keeps experience, skill and project entries in memory
+type importRepository struct {
+	domain.ResumeRepository
+	experience map[uuid.UUID]*domain.Experience
+	skills     map[uuid.UUID]*domain.Skill
+	projects   map[uuid.UUID]*domain.Project
+	// deleted are the resumes that don't exist; every other one does
+	deleted map[uuid.UUID]bool
+}
+
+func (r *importRepository) GetResumeByID(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	if r.deleted[id] {
+		return nil, repository.ErrNotFound
+	}
+	return &domain.Resume{ID: id}, nil
+}
+
+func (r *importRepository) AddExperience(ctx context.Context, resumeID uuid.UUID, experience *domain.Experience) (uuid.UUID, error) {
+	id := uuid.New()
+	r.experience[id] = experience
+	return id, nil
+}
+
+func (r *importRepository) UpdateExperience(ctx context.Context, resumeID, id uuid.UUID, experience *domain.Experience) error {
+	if _, ok := r.experience[id]; !ok {
+		return repository.ErrNotFound
+	}
+	r.experience[id] = experience
+	return nil
+}
+
+func (r *importRepository) AddProject(ctx context.Context, resumeID uuid.UUID, project *domain.Project) (uuid.UUID, error) {
+	id := uuid.New()
+	r.projects[id] = project
+	return id, nil
+}
+
+func (r *importRepository) UpdateProject(ctx context.Context, resumeID, id uuid.UUID, project *domain.Project) error {
+	if _, ok := r.projects[id]; !ok {
+		return repository.ErrNotFound
+	}
+	r.projects[id] = project
+	return nil
+}
+
+func (r *importRepository) GetProject(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
+	project, ok := r.projects[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *project
+	return &copied, nil
+}
+
+func (r *importRepository) AddSkill(ctx context.Context, resumeID uuid.UUID, skill *domain.Skill) (uuid.UUID, error) {
+	id := uuid.New()
+	r.skills[id] = skill
+	return id, nil
+}
+
+// provenanceRepository keeps provenance in memory, by entity ID
+type provenanceRepository struct {
+	provenance map[uuid.UUID]*domain.Provenance
+}
+
+func (r *provenanceRepository) SaveProvenance(ctx context.Context, provenance *domain.Provenance) error {
+	copied := *provenance
+	r.provenance[provenance.EntityID] = &copied
+	return nil
+}
+
+func (r *provenanceRepository) GetProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) (*domain.Provenance, error) {
+	p, ok := r.provenance[entityID]
+	if !ok || p.ResumeID != resumeID || p.Entity != entity {
+		return nil, repository.ErrNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (r *provenanceRepository) ListProvenance(ctx context.Context, resumeID uuid.UUID) ([]*domain.Provenance, error) {
+	var provenance []*domain.Provenance
+	for _, p := range r.provenance {
+		if p.ResumeID == resumeID {
+			provenance = append(provenance, p)
+		}
+	}
+	return provenance, nil
+}
+
+func (r *provenanceRepository) DeleteProvenance(ctx context.Context, resumeID uuid.UUID, entity string, entityID uuid.UUID) error {
+	if _, ok := r.provenance[entityID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.provenance, entityID)
+	return nil
+}
+
+func (r *provenanceRepository) DeleteResumeProvenance(ctx context.Context, resumeID uuid.UUID) error {
+	for entityID, provenance := range r.provenance {
+		if provenance.ResumeID == resumeID {
+			delete(r.provenance, entityID)
+		}
+	}
+	return nil
+}
+
+func newImportService() (*EntryImportService, *importRepository, *provenanceRepository) {
+	resumeRepo := &importRepository{
+		experience: map[uuid.UUID]*domain.Experience{},
+		skills:     map[uuid.UUID]*domain.Skill{},
+		projects:   map[uuid.UUID]*domain.Project{},
+	}
+	provenanceRepo := &provenanceRepository{provenance: map[uuid.UUID]*domain.Provenance{}}
+	return NewEntryImportService(resumeRepo, provenanceRepo), resumeRepo, provenanceRepo
+}
+
+func TestEntryImportServiceImport(t *testing.T) {
+	ctx := context.Background()
+	svc, resumeRepo, provenanceRepo := newImportService()
+	resumeID := uuid.New()
+
+	result, err := svc.Import(ctx, resumeID, domain.ImportSourceLinkedIn, []ImportEntry{
+		{Entity: domain.EventEntityExperience, SourceRef: "position/1", Value: json.RawMessage(`{"employer":"Acme","title":"Engineer","start_date":"2020-01-01","end_date":"Present"}`)},
+		{Entity: domain.EventEntitySkill, Value: json.RawMessage(`{"name":"Go"}`)},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 2)
+	assert.Len(t, resumeRepo.experience, 1)
+	assert.Len(t, resumeRepo.skills, 1)
+
+	experience := result.Entries[0]
+	assert.Equal(t, result.ImportID, experience.ImportID)
+	assert.Equal(t, domain.ImportSourceLinkedIn, experience.Source)
+	assert.Equal(t, "position/1", experience.SourceRef)
+	assert.Contains(t, resumeRepo.experience, experience.EntityID)
+	assert.Contains(t, provenanceRepo.provenance, experience.EntityID)
+	assert.Contains(t, string(experience.Original), `"employer":"Acme"`)
+}
+
+func TestEntryImportServiceImportValidation(t *testing.T) {
+	ctx := context.Background()
+	svc, resumeRepo, _ := newImportService()
+
+	tests := []struct {
+		name    string
+		source  string
+		entries []ImportEntry
+		field   string
+	}{
+		{"unknown source", "myspace", []ImportEntry{{Entity: domain.EventEntitySkill, Value: json.RawMessage(`{"name":"Go"}`)}}, "source"},
+		{"no entries", domain.ImportSourceFile, nil, "entries"},
+		{"unknown entity", domain.ImportSourceFile, []ImportEntry{{Entity: "resume", Value: json.RawMessage(`{}`)}}, "entries[0]"},
+		{"invalid entry", domain.ImportSourceFile, []ImportEntry{
+			{Entity: domain.EventEntitySkill, Value: json.RawMessage(`{"name":"Go"}`)},
+			{Entity: domain.EventEntitySkill, Value: json.RawMessage(`{"name":""}`)},
+		}, "entries[1].name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Import(ctx, uuid.New(), tt.source, tt.entries)
+			var validationErr *domain.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+
+	// Nothing is added unless every entry is valid
+	assert.Empty(t, resumeRepo.skills)
+}
+
+func TestEntryImportServiceRevertAndResync(t *testing.T) {
+	ctx := context.Background()
+	svc, resumeRepo, provenanceRepo := newImportService()
+	resumeID := uuid.New()
+
+	result, err := svc.Import(ctx, resumeID, domain.ImportSourceGitHub, []ImportEntry{
+		{Entity: domain.EventEntityExperience, Value: json.RawMessage(`{"employer":"Acme","title":"Engineer","start_date":"2020-01-01"}`)},
+	})
+	require.NoError(t, err)
+	id := result.Entries[0].EntityID
+
+	// The user edits the entry, then reverts it
+	resumeRepo.experience[id] = &domain.Experience{Employer: "Acme Corp", JobTitle: "Lead", StartDate: "2020-01-01"}
+	_, err = svc.Revert(ctx, resumeID, domain.EventEntityExperience, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Engineer", resumeRepo.experience[id].JobTitle)
+
+	// Re-syncing takes the source's value, which later reverts restore
+	_, err = svc.Resync(ctx, resumeID, domain.EventEntityExperience, id, json.RawMessage(`{"employer":"Acme","title":"Senior Engineer","start_date":"2020-01-01"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Senior Engineer", resumeRepo.experience[id].JobTitle)
+	resumeRepo.experience[id].JobTitle = "Lead"
+	_, err = svc.Revert(ctx, resumeID, domain.EventEntityExperience, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Senior Engineer", resumeRepo.experience[id].JobTitle)
+
+	// Entries that weren't imported have nothing to revert to
+	_, err = svc.Revert(ctx, resumeID, domain.EventEntityExperience, uuid.New())
+	assert.ErrorIs(t, err, ErrProvenanceNotFound)
+	_, err = svc.Revert(ctx, uuid.New(), domain.EventEntityExperience, id)
+	assert.ErrorIs(t, err, ErrProvenanceNotFound)
+
+	// A deleted entry takes its provenance with it
+	delete(resumeRepo.experience, id)
+	_, err = svc.Revert(ctx, resumeID, domain.EventEntityExperience, id)
+	assert.ErrorIs(t, err, ErrProvenanceNotFound)
+	assert.NotContains(t, provenanceRepo.provenance, id)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/rs/zerolog/log"
+)
+
+// MagicLinkService errors
+var (
+	ErrMagicLinkInvalid = errors.New("sign-in link invalid, expired or already used")
+	ErrMagicLinkDevice  = errors.New("sign-in link opened on a device that didn't ask for one")
+)
+
+// MagicLinkServiceConfig contains configuration for the magic link service
+type MagicLinkServiceConfig struct {
+	// LinkURL is the page of the web app links open, such as
+	// "https://app.example.com/login/magic"; the token is added as its token
+	// query parameter
+	LinkURL string
+	// LinkTTL is how long a link can be used, matching the expiry of the
+	// tokens it carries
+	LinkTTL time.Duration
+	// MaxLinksPerHour limits the links emailed to each user, whatever the
+	// client IP the requests come from
+	MaxLinksPerHour int
+}
+
+// MagicLinkService signs users in with links emailed to them, for users who
+// would rather not keep a password. Each link carries a signed token that can
+// be exchanged once for a token pair, from the device that asked for it: the
+// request returns a device secret that must come with the token, so a link
+// forwarded or intercepted on its way is of no use on its own.
+type MagicLinkService struct {
+	userRepo    domain.UserRepository
+	authService *AuthService
+	mailService *MailService
+	cache       cache.Cache
+	config      MagicLinkServiceConfig
+	// sendLink delivers a link to the user, replaced in tests
+	sendLink func(ctx context.Context, user *domain.User, link string)
+}
+
+// NewMagicLinkService creates a new magic link service
+func NewMagicLinkService(userRepo domain.UserRepository, authService *AuthService, mailService *MailService, store cache.Cache, config MagicLinkServiceConfig) *MagicLinkService {
+	// Set default values if not provided
+	if config.LinkTTL <= 0 {
+		config.LinkTTL = 15 * time.Minute
+	}
+	if config.MaxLinksPerHour <= 0 {
+		config.MaxLinksPerHour = 5
+	}
+
+	s := &MagicLinkService{
+		userRepo:    userRepo,
+		authService: authService,
+		mailService: mailService,
+		cache:       store,
+		config:      config,
+	}
+	s.sendLink = s.mailLink
+	return s
+}
+
+// LinkTTL is how long a link can be used
+func (s *MagicLinkService) LinkTTL() time.Duration {
+	return s.config.LinkTTL
+}
+
+// Request emails a sign-in link to the account of email and returns the
+// device secret the link must be exchanged with: device when the browser
+// already has one, so earlier links keep working, or a new one. Unknown
+// addresses and users who reached their hourly allowance get no email but a
+// device secret all the same, so the response doesn't tell whether an
+// account exists.
+func (s *MagicLinkService) Request(ctx context.Context, email, device string) (string, error) {
+	if device == "" {
+		var err error
+		if device, err = randomToken(); err != nil {
+			return "", err
+		}
+	}
+
+	user, err := s.authService.userByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return device, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sent, err := s.cache.Incr(ctx, "magic_links_sent:"+user.ID.String(), time.Hour)
+	if err != nil {
+		return "", err
+	}
+	if sent > int64(s.config.MaxLinksPerHour) {
+		log.Ctx(ctx).Warn().Str("user_id", user.ID.String()).Msg("Sign-in link limit reached, no link sent")
+		return device, nil
+	}
+
+	token, err := s.authService.jwt.GenerateMagicLinkToken(user.ID.String(), user.Email)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to generate sign-in link token")
+		return "", err
+	}
+	// The link can be used until the token is exchanged or expires
+	if err := s.cache.Set(ctx, magicLinkKey(token, device), []byte("1"), s.config.LinkTTL); err != nil {
+		return "", err
+	}
+
+	link, err := s.linkURL(token)
+	if err != nil {
+		return "", err
+	}
+	s.sendLink(ctx, user, link)
+
+	return device, nil
+}
+
+// Exchange signs the user in with the token of a link and the device secret
+// Request returned. Users who require a passkey confirm the login with one,
+// as with passwords.
+func (s *MagicLinkService) Exchange(ctx context.Context, token, device, userAgent, clientIP string) (*TokenPair, error) {
+	claims, err := s.authService.jwt.ValidateMagicLinkToken(token)
+	if err != nil {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	if device == "" {
+		return nil, ErrMagicLinkDevice
+	}
+	// The link is stored under the device that asked for it, so another
+	// device misses and leaves it alone, and taking it in one step keeps
+	// concurrent exchanges from both signing in
+	if _, err := s.cache.Take(ctx, magicLinkKey(token, device)); err != nil {
+		if errors.Is(err, cache.ErrMiss) {
+			return nil, ErrMagicLinkInvalid
+		}
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, ErrMagicLinkInvalid
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrMagicLinkInvalid
+		}
+		return nil, err
+	}
+	// Links sent to an address the account has since left don't sign in
+	if user.Email != claims.Email {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	return s.authService.completeLogin(ctx, user, userAgent, clientIP)
+}
+
+// mailLink emails a sign-in link. The link has been stored, so a failure is
+// logged rather than returned, which would tell the address has an account.
+func (s *MagicLinkService) mailLink(ctx context.Context, user *domain.User, link string) {
+	if s.mailService == nil {
+		return
+	}
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf("Open the following link to sign in. It works once, on the device you asked for it from, until %s.\n\n%s\n\nIf you did not ask to sign in, you can ignore this email.",
+			formatLocalTime(user, time.Now().Add(s.config.LinkTTL)), link),
+	}
+	if err := s.mailService.Send(ctx, msg); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to queue sign-in link email")
+	}
+}
+
+// linkURL is the link a token is emailed in
+func (s *MagicLinkService) linkURL(token string) (string, error) {
+	u, err := url.Parse(s.config.LinkURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// magicLinkKey is the cache key of an unused sign-in link requested from
+// device. The token and device secret are hashed so the cache doesn't hold
+// usable ones.
+func magicLinkKey(token, device string) string {
+	return "magic_link:" + hashAPIKey(token) + ":" + hashAPIKey(device)
+}
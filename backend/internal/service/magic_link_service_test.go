@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMagicLinkService returns a magic link service whose links are
+// collected in the returned slice instead of emailed
+func newTestMagicLinkService(t *testing.T, users *identityUserRepository, config MagicLinkServiceConfig) (*MagicLinkService, *[]string) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	authService := NewAuthService(users, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), AuthServiceConfig{})
+	svc := NewMagicLinkService(users, authService, nil, store, config)
+	var tokens []string
+	svc.sendLink = func(ctx context.Context, user *domain.User, link string) {
+		parsed, err := url.Parse(link)
+		require.NoError(t, err)
+		tokens = append(tokens, parsed.Query().Get("token"))
+	}
+	return svc, &tokens
+}
+
+func TestMagicLinkService(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "user"}
+	users := &identityUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	svc, tokens := newTestMagicLinkService(t, users, MagicLinkServiceConfig{LinkURL: "https://app.example.com/login/magic?lang=en"})
+
+	// Unknown addresses get a device secret but no link
+	device, err := svc.Request(ctx, "nobody@example.com", "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, device)
+	assert.Empty(t, *tokens)
+
+	device, err = svc.Request(ctx, "Ana@Example.com", "")
+	require.NoError(t, err)
+	require.Len(t, *tokens, 1)
+	token := (*tokens)[0]
+
+	// Links only work with the secret of the device that asked for them;
+	// other devices find no link and leave it for the right one
+	_, err = svc.Exchange(ctx, token, "", "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrMagicLinkDevice)
+	_, err = svc.Exchange(ctx, token, "other-device", "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+
+	pair, err := svc.Exchange(ctx, token, device, "test", "127.0.0.1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.Equal(t, 1, users.sessions)
+
+	// and only once
+	_, err = svc.Exchange(ctx, token, device, "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+	_, err = svc.Exchange(ctx, "not-a-token", device, "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+
+	// Asking again from the same browser keeps its secret
+	again, err := svc.Request(ctx, user.Email, device)
+	require.NoError(t, err)
+	assert.Equal(t, device, again)
+
+	// Links sent before the email changed no longer sign in
+	_, err = svc.Request(ctx, user.Email, device)
+	require.NoError(t, err)
+	user.Email = "ana@new.example.com"
+	_, err = svc.Exchange(ctx, (*tokens)[len(*tokens)-1], device, "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+}
+
+func TestMagicLinkServiceConcurrentExchange(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "user"}
+	users := &identityUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	svc, tokens := newTestMagicLinkService(t, users, MagicLinkServiceConfig{LinkURL: "https://app.example.com/login/magic"})
+
+	device, err := svc.Request(ctx, user.Email, "")
+	require.NoError(t, err)
+	require.Len(t, *tokens, 1)
+
+	// A link exchanged twice at once still signs in only once
+	var signedIn atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Exchange(ctx, (*tokens)[0], device, "test", "127.0.0.1")
+			if err == nil {
+				signedIn.Add(1)
+			} else {
+				assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), signedIn.Load())
+	assert.Equal(t, 1, users.sessions)
+}
+
+func TestMagicLinkServiceHourlyLimit(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "user"}
+	users := &identityUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	svc, tokens := newTestMagicLinkService(t, users, MagicLinkServiceConfig{LinkURL: "https://app.example.com/login/magic", MaxLinksPerHour: 2})
+
+	// Requests over the limit look the same but send nothing
+	for i := 0; i < 3; i++ {
+		device, err := svc.Request(ctx, user.Email, "")
+		require.NoError(t, err)
+		assert.NotEmpty(t, device)
+	}
+	assert.Len(t, *tokens, 2)
+}
+
+func TestMagicLinkServicePasskeyRequired(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "user", PasskeyRequired: true}
+	users := &identityUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	svc, tokens := newTestMagicLinkService(t, users, MagicLinkServiceConfig{LinkURL: "https://app.example.com/login/magic"})
+
+	device, err := svc.Request(ctx, user.Email, "")
+	require.NoError(t, err)
+	require.Len(t, *tokens, 1)
+
+	// Links don't skip the passkey the account requires
+	_, err = svc.Exchange(ctx, (*tokens)[0], device, "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrSecondFactorUnavailable)
+	assert.Zero(t, users.sessions)
+}
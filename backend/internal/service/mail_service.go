@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/pkg/mail"
+)
+
+// JobTypeSendMail is the job type for delivering an email
+const JobTypeSendMail = "mail.send"
+
+// mailTimeLayout is how times are written in email
+const mailTimeLayout = "Monday, January 2, 2006 at 15:04 MST"
+
+// formatLocalTime formats t in user's time zone for an email to them
+func formatLocalTime(user *domain.User, t time.Time) string {
+	return t.In(user.Location()).Format(mailTimeLayout)
+}
+
+// MailService queues outgoing email for delivery by the job worker
+type MailService struct {
+	queue  *jobs.Queue
+	sender mail.Sender
+}
+
+// NewMailService creates a new mail service
+func NewMailService(queue *jobs.Queue, sender mail.Sender) *MailService {
+	return &MailService{
+		queue:  queue,
+		sender: sender,
+	}
+}
+
+// Send queues a message for delivery
+func (s *MailService) Send(ctx context.Context, msg *mail.Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.queue.Enqueue(ctx, JobTypeSendMail, msg)
+	return err
+}
+
+// HandleSendJob delivers a queued message
+func (s *MailService) HandleSendJob(ctx context.Context, job *jobs.Job) error {
+	var msg mail.Message
+	if err := job.DecodePayload(&msg); err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, &msg)
+}
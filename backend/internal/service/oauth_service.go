@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// OAuthService errors
+var (
+	ErrUnknownProvider   = errors.New("unknown oauth provider")
+	ErrInvalidOAuthState = errors.New("invalid or expired oauth state")
+	// ErrOAuthAccountExists is returned when a provider account that isn't
+	// linked has the email of an existing user, who must sign in another way
+	// and link it
+	ErrOAuthAccountExists = errors.New("an account with this email already exists")
+	// ErrIdentityLinked is returned when linking a provider account that is
+	// linked to another user
+	ErrIdentityLinked = errors.New("provider account is linked to another user")
+)
+
+// OAuthServiceConfig contains configuration for the OAuth service
+type OAuthServiceConfig struct {
+	// StateTTL is how long a user has to complete sign-in at the provider
+	StateTTL time.Duration
+}
+
+// oauthState is what a sign-in remembers between redirect and callback
+type oauthState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	// LinkUserID is the user linking the provider account, if the redirect
+	// links one rather than signing in
+	LinkUserID uuid.UUID `json:"link_user_id,omitzero"`
+}
+
+// OAuthResult is what completing an OAuth redirect did: sign a user in, or
+// link the provider account to the user who started the link
+type OAuthResult struct {
+	Tokens *TokenPair
+	// Linked is the identity added when the redirect linked an account
+	Linked *domain.UserIdentity
+}
+
+// OAuthService signs users in with accounts at OAuth providers. A provider
+// account is matched to a user by the identity linked to it; users are
+// created for new addresses and get the same token pair as a password login.
+// Accounts registered with the provider account's email aren't signed in to:
+// addresses aren't verified at registration, so whoever registered one may
+// not own it. Their users sign in another way and link the provider account.
+type OAuthService struct {
+	providers    map[string]*auth.OAuthProvider
+	identityRepo domain.UserIdentityRepository
+	userRepo     domain.UserRepository
+	authService  *AuthService
+	cache        cache.Cache
+	config       OAuthServiceConfig
+}
+
+// NewOAuthService creates a new OAuth service for the configured providers
+func NewOAuthService(providers []*auth.OAuthProvider, identityRepo domain.UserIdentityRepository, userRepo domain.UserRepository, authService *AuthService, store cache.Cache, config OAuthServiceConfig) *OAuthService {
+	// Set default values if not provided
+	if config.StateTTL == 0 {
+		config.StateTTL = 10 * time.Minute
+	}
+
+	byName := make(map[string]*auth.OAuthProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &OAuthService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		cache:        store,
+		config:       config,
+	}
+}
+
+// Providers returns the names of the configured providers
+func (s *OAuthService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StateTTL returns how long a sign-in may take
+func (s *OAuthService) StateTTL() time.Duration {
+	return s.config.StateTTL
+}
+
+// Begin starts a sign-in with a provider, returning the URL to send the user
+// to and the state the callback must carry
+func (s *OAuthService) Begin(ctx context.Context, providerName string) (string, string, error) {
+	return s.begin(ctx, providerName, uuid.Nil)
+}
+
+// BeginLink starts linking an account at a provider to a signed-in user, who
+// can then sign in with it. It returns what Begin does.
+func (s *OAuthService) BeginLink(ctx context.Context, providerName string, userID uuid.UUID) (string, string, error) {
+	return s.begin(ctx, providerName, userID)
+}
+
+// begin stores the state of a redirect to a provider
+func (s *OAuthService) begin(ctx context.Context, providerName string, linkUserID uuid.UUID) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := auth.NewOAuthToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := auth.NewOAuthToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := json.Marshal(oauthState{Provider: providerName, CodeVerifier: verifier, LinkUserID: linkUserID})
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.cache.Set(ctx, oauthStateKey(state), data, s.config.StateTTL); err != nil {
+		return "", "", err
+	}
+
+	return provider.AuthCodeURL(state, verifier), state, nil
+}
+
+// Complete finishes a sign-in or link with the code the provider sent to the
+// callback. Sign-ins issue tokens for the matching user, or return a
+// *SecondFactorRequiredError like Login when the user requires a passkey.
+// Each state works once.
+func (s *OAuthService) Complete(ctx context.Context, providerName, state, code, userAgent, clientIP string) (*OAuthResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	// Taking the state in one step keeps concurrent callbacks from both
+	// completing with it
+	data, err := s.cache.Take(ctx, oauthStateKey(state))
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, ErrInvalidOAuthState
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pending oauthState
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, err
+	}
+	// A state issued for one provider can't complete a sign-in with another
+	if pending.Provider != providerName {
+		return nil, ErrInvalidOAuthState
+	}
+
+	account, err := provider.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending.LinkUserID != uuid.Nil {
+		identity, err := s.link(ctx, pending.LinkUserID, account)
+		if err != nil {
+			return nil, err
+		}
+		return &OAuthResult{Linked: identity}, nil
+	}
+
+	user, err := s.resolveUser(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.authService.completeLogin(ctx, user, userAgent, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthResult{Tokens: tokens}, nil
+}
+
+// link links a provider account to a user. Linking an account the user
+// already linked does nothing.
+func (s *OAuthService) link(ctx context.Context, userID uuid.UUID, account *auth.OAuthIdentity) (*domain.UserIdentity, error) {
+	identity, err := s.identityRepo.GetIdentity(ctx, account.Provider, account.Subject)
+	if err == nil {
+		if identity.UserID != userID {
+			return nil, ErrIdentityLinked
+		}
+		return identity, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	identity = &domain.UserIdentity{
+		UserID:   userID,
+		Provider: account.Provider,
+		Subject:  account.Subject,
+		Email:    account.Email,
+	}
+	if err := s.identityRepo.CreateIdentity(ctx, identity); err != nil {
+		if errors.Is(err, repository.ErrDuplicateIdentity) {
+			return nil, ErrIdentityLinked
+		}
+		return nil, err
+	}
+	log.Ctx(ctx).Info().Str("user_id", userID.String()).Str("provider", account.Provider).Msg("Linked OAuth identity")
+	return identity, nil
+}
+
+// resolveUser finds or creates the user a provider account signs in as
+func (s *OAuthService) resolveUser(ctx context.Context, account *auth.OAuthIdentity) (*domain.User, error) {
+	identity, err := s.identityRepo.GetIdentity(ctx, account.Provider, account.Subject)
+	if err == nil {
+		identity.Email = account.Email
+		if err := s.identityRepo.TouchIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+		return s.userRepo.GetUserByID(ctx, identity.UserID)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	identity = &domain.UserIdentity{
+		Provider: account.Provider,
+		Subject:  account.Subject,
+		Email:    account.Email,
+	}
+
+	// The provider verified the address, but the account registered with it
+	// may not belong to its owner, so it is only reached by linking
+	email := domain.NormalizeEmail(account.Email)
+	_, err = s.userRepo.GetUserByEmail(ctx, email)
+	if err == nil {
+		return nil, ErrOAuthAccountExists
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	// New users have no password until they reset one
+	user := &domain.User{Email: email, Role: "user"}
+	if err := s.identityRepo.CreateUserWithIdentity(ctx, user, identity); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateIdentity):
+			return s.linkedUser(ctx, account)
+		case errors.Is(err, repository.ErrDuplicateEmail):
+			return nil, ErrOAuthAccountExists
+		}
+		return nil, err
+	}
+	log.Ctx(ctx).Info().Str("user_id", user.ID.String()).Str("provider", account.Provider).Msg("Created user from OAuth identity")
+
+	return user, nil
+}
+
+// linkedUser returns the user a concurrent sign-in with the same provider
+// account linked it to first
+func (s *OAuthService) linkedUser(ctx context.Context, account *auth.OAuthIdentity) (*domain.User, error) {
+	identity, err := s.identityRepo.GetIdentity(ctx, account.Provider, account.Subject)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetUserByID(ctx, identity.UserID)
+}
+
+// oauthStateKey is the cache key of a pending sign-in
+func oauthStateKey(state string) string {
+	return "oauth_state:" + state
+}
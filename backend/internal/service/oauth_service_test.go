@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityUserRepository keeps users and their sessions in memory
+type identityUserRepository struct {
+	domain.UserRepository
+	users    map[uuid.UUID]*domain.User
+	mu       sync.Mutex
+	sessions int
+}
+
+func (r *identityUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *identityUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *identityUserRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions++
+	return nil
+}
+
+// memoryIdentityRepository keeps identities in memory, creating users in users
+type memoryIdentityRepository struct {
+	users      *identityUserRepository
+	identities []*domain.UserIdentity
+}
+
+func (r *memoryIdentityRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memoryIdentityRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *memoryIdentityRepository) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	user.ID = uuid.New()
+	r.users.users[user.ID] = user
+	identity.UserID = user.ID
+	return r.CreateIdentity(ctx, identity)
+}
+
+func (r *memoryIdentityRepository) GetIdentitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.UserIdentity, error) {
+	var identities []*domain.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+func (r *memoryIdentityRepository) TouchIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	return nil
+}
+
+// newTestOAuthProvider returns a Google provider whose accounts sign in as
+// whoever subject and email hold at the time
+func newTestOAuthProvider(t *testing.T, subject, email *string) *auth.OAuthProvider {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token"})
+	})
+	mux.HandleFunc("GET /userinfo", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"sub": *subject, "email": *email, "email_verified": true})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return auth.NewGoogleProvider(auth.OAuthConfig{AuthURL: server.URL + "/authorize", TokenURL: server.URL + "/token", APIURL: server.URL})
+}
+
+func TestOAuthService(t *testing.T) {
+	ctx := context.Background()
+
+	// A provider whose accounts sign in as whoever the test chooses
+	var subject, email string
+	provider := newTestOAuthProvider(t, &subject, &email)
+
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	existing := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "user"}
+	users := &identityUserRepository{users: map[uuid.UUID]*domain.User{existing.ID: existing}}
+	identities := &memoryIdentityRepository{users: users}
+	authService := NewAuthService(users, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), AuthServiceConfig{})
+	svc := NewOAuthService([]*auth.OAuthProvider{provider}, identities, users, authService, store, OAuthServiceConfig{})
+	assert.Equal(t, []string{auth.ProviderGoogle}, svc.Providers())
+
+	signIn := func() (*TokenPair, error) {
+		redirectURL, state, err := svc.Begin(ctx, auth.ProviderGoogle)
+		require.NoError(t, err)
+		parsed, err := url.Parse(redirectURL)
+		require.NoError(t, err)
+		assert.Equal(t, state, parsed.Query().Get("state"))
+		result, err := svc.Complete(ctx, auth.ProviderGoogle, state, "code", "test", "127.0.0.1")
+		if err != nil {
+			return nil, err
+		}
+		assert.Nil(t, result.Linked)
+		return result.Tokens, nil
+	}
+	link := func(userID uuid.UUID) (*domain.UserIdentity, error) {
+		_, state, err := svc.BeginLink(ctx, auth.ProviderGoogle, userID)
+		require.NoError(t, err)
+		result, err := svc.Complete(ctx, auth.ProviderGoogle, state, "code", "test", "127.0.0.1")
+		if err != nil {
+			return nil, err
+		}
+		assert.Nil(t, result.Tokens)
+		return result.Linked, nil
+	}
+
+	// Registration doesn't verify addresses, so a provider account doesn't
+	// sign in to the account registered with its email
+	subject, email = "g-1", "ana@example.com"
+	_, err = signIn()
+	assert.ErrorIs(t, err, ErrOAuthAccountExists)
+	assert.Empty(t, identities.identities)
+	assert.Zero(t, users.sessions)
+
+	// Its user links it instead, after which it signs in
+	linked, err := link(existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, linked.UserID)
+	require.Len(t, identities.identities, 1)
+	_, err = link(existing.ID)
+	require.NoError(t, err)
+	assert.Len(t, identities.identities, 1)
+	_, err = link(uuid.New())
+	assert.ErrorIs(t, err, ErrIdentityLinked)
+
+	tokens, err := signIn()
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+
+	// Later sign-ins follow the identity, even when the email changed
+	email = "ana@new.example.com"
+	_, err = signIn()
+	require.NoError(t, err)
+	assert.Len(t, identities.identities, 1)
+	assert.Len(t, users.users, 1)
+
+	// New addresses get a new user without a password
+	subject, email = "g-2", "bo@example.com"
+	_, err = signIn()
+	require.NoError(t, err)
+	require.Len(t, users.users, 2)
+	created, err := users.GetUserByEmail(ctx, "bo@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, created.PasswordHash)
+	assert.Equal(t, 3, users.sessions)
+
+	// Password login is refused for them rather than failing
+	_, err = authService.Login(ctx, "bo@example.com", "anything", "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// States work once and only with the provider they were issued for
+	_, state, err := svc.Begin(ctx, auth.ProviderGoogle)
+	require.NoError(t, err)
+	_, err = svc.Complete(ctx, auth.ProviderGitHub, state, "code", "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+	_, err = svc.Complete(ctx, auth.ProviderGoogle, state, "code", "test", "127.0.0.1")
+	require.NoError(t, err)
+	_, err = svc.Complete(ctx, auth.ProviderGoogle, state, "code", "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidOAuthState)
+
+	_, _, err = svc.Begin(ctx, "myspace")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+}
+
+func TestOAuthServicePasskeyRequired(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "ana@example.com", Role: "user", PasskeyRequired: true}
+	users := map[uuid.UUID]*domain.User{user.ID: user}
+	_, authService, passkeyRepo := newTestPasskeyService(t, users)
+	addTestPasskey(t, passkeyRepo, user.ID, "key-1")
+
+	subject, email := "g-1", user.Email
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	userRepo := &identityUserRepository{users: users}
+	identities := &memoryIdentityRepository{users: userRepo}
+	identities.identities = []*domain.UserIdentity{{UserID: user.ID, Provider: auth.ProviderGoogle, Subject: subject}}
+	svc := NewOAuthService([]*auth.OAuthProvider{newTestOAuthProvider(t, &subject, &email)}, identities, userRepo, authService, store, OAuthServiceConfig{})
+
+	// Signing in with a provider doesn't skip the passkey the account requires
+	_, state, err := svc.Begin(ctx, auth.ProviderGoogle)
+	require.NoError(t, err)
+	result, err := svc.Complete(ctx, auth.ProviderGoogle, state, "code", "test", "127.0.0.1")
+	var secondFactor *SecondFactorRequiredError
+	require.ErrorAs(t, err, &secondFactor)
+	assert.NotEmpty(t, secondFactor.Challenge.ID)
+	assert.Nil(t, result)
+}
+
+// racingIdentityRepository links the provider account from a concurrent
+// sign-in just before this one tries to, as the database's unique
+// constraint would then report
+type racingIdentityRepository struct {
+	*memoryIdentityRepository
+	winner *domain.UserIdentity
+}
+
+func (r *racingIdentityRepository) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	r.identities = append(r.identities, r.winner)
+	return repository.ErrDuplicateIdentity
+}
+
+func TestResolveUserAfterConcurrentSignIn(t *testing.T) {
+	// The other sign-in's user isn't visible by email yet, as before its
+	// transaction commits
+	winner := &domain.User{ID: uuid.New(), Email: "cy@other.example.com", Role: "user"}
+	users := &identityUserRepository{users: map[uuid.UUID]*domain.User{winner.ID: winner}}
+	identities := &racingIdentityRepository{
+		memoryIdentityRepository: &memoryIdentityRepository{users: users},
+		winner:                   &domain.UserIdentity{UserID: winner.ID, Provider: auth.ProviderGoogle, Subject: "g-3"},
+	}
+	svc := NewOAuthService(nil, identities, users, nil, nil, OAuthServiceConfig{})
+
+	user, err := svc.resolveUser(context.Background(), &auth.OAuthIdentity{Provider: auth.ProviderGoogle, Subject: "g-3", Email: "cy@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, winner.ID, user.ID)
+}
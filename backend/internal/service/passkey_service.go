@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// PasskeyService errors
+var (
+	ErrPasskeyNotFound         = errors.New("passkey not found")
+	ErrPasskeyExists           = errors.New("passkey already registered")
+	ErrPasskeyLimit            = errors.New("passkey limit reached")
+	ErrNoPasskeys              = errors.New("no passkeys registered")
+	ErrCeremonyNotFound        = errors.New("passkey ceremony not found or expired")
+	ErrPasskeyInvalid          = errors.New("passkey verification failed")
+	ErrSecondFactorRequired    = errors.New("second factor required")
+	ErrSecondFactorUnavailable = errors.New("second factor unavailable")
+)
+
+// Kinds of passkey ceremonies
+const (
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+	ceremonySecondFactor = "second_factor"
+)
+
+// PasskeyServiceConfig contains configuration for the passkey service
+type PasskeyServiceConfig struct {
+	// RPID is the domain passkeys are bound to, such as "example.com"
+	RPID string
+	// RPDisplayName is the name authenticators show for the site
+	RPDisplayName string
+	// RPOrigins are the origins of the pages passkeys are used from, such as
+	// "https://app.example.com"
+	RPOrigins []string
+	// CeremonyTTL is how long a registration or login can take
+	CeremonyTTL time.Duration
+	// MaxPasskeysPerUser limits how many passkeys each user can register
+	MaxPasskeysPerUser int
+}
+
+// PasskeyChallenge starts a passkey ceremony. Options are passed to the
+// browser's navigator.credentials API, and the response is sent back with
+// the challenge ID.
+type PasskeyChallenge struct {
+	ID      string `json:"challenge_id"`
+	Options any    `json:"options"`
+}
+
+// SecondFactorRequiredError is returned by Login when the password was right
+// but the user must confirm the login with a passkey. The challenge is
+// finished with PasskeyService.FinishLogin.
+type SecondFactorRequiredError struct {
+	Challenge *PasskeyChallenge
+}
+
+func (e *SecondFactorRequiredError) Error() string {
+	return ErrSecondFactorRequired.Error()
+}
+
+// Is makes the error match ErrSecondFactorRequired
+func (e *SecondFactorRequiredError) Is(target error) bool {
+	return target == ErrSecondFactorRequired
+}
+
+// passkeyCeremony is what is kept of a ceremony between its two requests
+type passkeyCeremony struct {
+	Kind    string               `json:"kind"`
+	UserID  uuid.UUID            `json:"user_id,omitzero"`
+	Name    string               `json:"name,omitempty"`
+	Session webauthn.SessionData `json:"session"`
+}
+
+// PasskeyService registers passkeys and signs users in with them, either
+// instead of a password or to confirm a password login. Ceremonies are kept
+// in the cache between their begin and finish requests.
+type PasskeyService struct {
+	passkeyRepo domain.PasskeyRepository
+	userRepo    domain.UserRepository
+	authService *AuthService
+	cache       cache.Cache
+	webauthn    *webauthn.WebAuthn
+	config      PasskeyServiceConfig
+	transactor  domain.Transactor
+}
+
+// NewPasskeyService creates a new passkey service. It fails when the relying
+// party configuration is invalid.
+func NewPasskeyService(passkeyRepo domain.PasskeyRepository, userRepo domain.UserRepository, authService *AuthService, store cache.Cache, config PasskeyServiceConfig) (*PasskeyService, error) {
+	// Set default values if not provided
+	if config.RPDisplayName == "" {
+		config.RPDisplayName = "Resume Generator"
+	}
+	if config.CeremonyTTL <= 0 {
+		config.CeremonyTTL = 5 * time.Minute
+	}
+	if config.MaxPasskeysPerUser <= 0 {
+		config.MaxPasskeysPerUser = 10
+	}
+
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: config.CeremonyTTL}
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          config.RPID,
+		RPDisplayName: config.RPDisplayName,
+		RPOrigins:     config.RPOrigins,
+		Timeouts:      webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasskeyService{
+		passkeyRepo: passkeyRepo,
+		userRepo:    userRepo,
+		authService: authService,
+		cache:       store,
+		webauthn:    w,
+		config:      config,
+	}, nil
+}
+
+// SetTransactor makes removing a user's last passkey and turning off their
+// passkey requirement atomic
+func (s *PasskeyService) SetTransactor(transactor domain.Transactor) {
+	s.transactor = transactor
+}
+
+// BeginRegistration starts registering a passkey for a user under name
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID uuid.UUID, name string) (*PasskeyChallenge, error) {
+	name = strings.TrimSpace(name)
+	if err := (&domain.Passkey{Name: name}).Validate(); err != nil {
+		return nil, err
+	}
+
+	user, passkeys, err := s.passkeyUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(passkeys.credentials) >= s.config.MaxPasskeysPerUser {
+		return nil, ErrPasskeyLimit
+	}
+
+	// Passkeys must be discoverable so they can sign in without an email,
+	// and each authenticator can only be registered once
+	creation, session, err := s.webauthn.BeginRegistration(passkeys,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(passkeys.credentials).CredentialDescriptors()))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.saveCeremony(ctx, &passkeyCeremony{Kind: ceremonyRegistration, UserID: user.ID, Name: name, Session: *session}, creation)
+}
+
+// FinishRegistration verifies the authenticator's response to a
+// registration challenge and stores the new passkey
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID uuid.UUID, challengeID string, response []byte) (*domain.Passkey, error) {
+	ceremony, err := s.takeCeremony(ctx, challengeID, ceremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserID != userID {
+		return nil, ErrCeremonyNotFound
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, ErrPasskeyInvalid
+	}
+	_, passkeys, err := s.passkeyUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.webauthn.CreateCredential(passkeys, ceremony.Session, parsed)
+	if err != nil {
+		log.Ctx(ctx).Info().Err(err).Str("user_id", userID.String()).Msg("Passkey registration rejected")
+		return nil, ErrPasskeyInvalid
+	}
+
+	encoded, err := json.Marshal(credential)
+	if err != nil {
+		return nil, err
+	}
+	passkey := &domain.Passkey{UserID: userID, Name: ceremony.Name, CredentialID: credential.ID, Credential: encoded}
+	if err := s.passkeyRepo.CreatePasskey(ctx, passkey); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrPasskeyExists
+		}
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID.String()).Str("passkey_id", passkey.ID.String()).Msg("Passkey registered")
+	return passkey, nil
+}
+
+// Passkeys lists a user's passkeys
+func (s *PasskeyService) Passkeys(ctx context.Context, userID uuid.UUID) ([]*domain.Passkey, error) {
+	return s.passkeyRepo.GetPasskeysByUserID(ctx, userID)
+}
+
+// DeletePasskey removes one of a user's passkeys. Removing the last one turns
+// off the user's passkey requirement, so they can still sign in.
+func (s *PasskeyService) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	return withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.passkeyRepo.DeletePasskey(ctx, userID, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrPasskeyNotFound
+			}
+			return err
+		}
+
+		count, err := s.passkeyRepo.CountPasskeysByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return s.passkeyRepo.SetPasskeyRequired(ctx, userID, false)
+		}
+		return nil
+	})
+}
+
+// SetSecondFactor sets whether the user's password logins must be confirmed
+// with a passkey. It can only be turned on once the user has one.
+func (s *PasskeyService) SetSecondFactor(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	if enabled {
+		count, err := s.passkeyRepo.CountPasskeysByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNoPasskeys
+		}
+	}
+
+	err := s.passkeyRepo.SetPasskeyRequired(ctx, userID, enabled)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// BeginLogin starts a passwordless login with any passkey the browser
+// offers
+func (s *PasskeyService) BeginLogin(ctx context.Context) (*PasskeyChallenge, error) {
+	assertion, session, err := s.webauthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, err
+	}
+	return s.saveCeremony(ctx, &passkeyCeremony{Kind: ceremonyLogin, Session: *session}, assertion)
+}
+
+// beginSecondFactor starts confirming a password login with one of the
+// user's passkeys
+func (s *PasskeyService) beginSecondFactor(ctx context.Context, user *domain.User) (*PasskeyChallenge, error) {
+	_, passkeys, err := s.passkeyUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(passkeys.credentials) == 0 {
+		return nil, ErrNoPasskeys
+	}
+
+	assertion, session, err := s.webauthn.BeginLogin(passkeys)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveCeremony(ctx, &passkeyCeremony{Kind: ceremonySecondFactor, UserID: user.ID, Session: *session}, assertion)
+}
+
+// FinishLogin verifies the authenticator's response to a login challenge,
+// whether a passwordless login or a password login's second factor, and
+// issues tokens for the user it signs in
+func (s *PasskeyService) FinishLogin(ctx context.Context, challengeID string, response []byte, userAgent, clientIP string) (*TokenPair, error) {
+	ceremony, err := s.takeCeremony(ctx, challengeID, ceremonyLogin, ceremonySecondFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, ErrPasskeyInvalid
+	}
+
+	var passkeys *webauthnUser
+	var credential *webauthn.Credential
+	if ceremony.Kind == ceremonySecondFactor {
+		if _, passkeys, err = s.passkeyUser(ctx, ceremony.UserID); err != nil {
+			return nil, err
+		}
+		credential, err = s.webauthn.ValidateLogin(passkeys, ceremony.Session, parsed)
+	} else {
+		credential, err = s.webauthn.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+			userID, err := uuid.FromBytes(userHandle)
+			if err != nil {
+				return nil, err
+			}
+			_, passkeys, err = s.passkeyUser(ctx, userID)
+			return passkeys, err
+		}, ceremony.Session, parsed)
+	}
+	if err != nil {
+		log.Ctx(ctx).Info().Err(err).Msg("Passkey login rejected")
+		return nil, ErrPasskeyInvalid
+	}
+	// A sign count that went backwards means the authenticator was cloned
+	if credential.Authenticator.CloneWarning {
+		log.Ctx(ctx).Warn().Str("user_id", passkeys.user.ID.String()).Msg("Passkey sign count went backwards, possible cloned authenticator")
+		return nil, ErrPasskeyInvalid
+	}
+
+	if err := s.updateCredential(ctx, passkeys, credential); err != nil {
+		return nil, err
+	}
+	return s.authService.issueTokens(ctx, passkeys.user, userAgent, clientIP)
+}
+
+// updateCredential stores the sign count and flags of a credential that was
+// just used
+func (s *PasskeyService) updateCredential(ctx context.Context, passkeys *webauthnUser, credential *webauthn.Credential) error {
+	for _, passkey := range passkeys.passkeys {
+		if string(passkey.CredentialID) != string(credential.ID) {
+			continue
+		}
+		encoded, err := json.Marshal(credential)
+		if err != nil {
+			return err
+		}
+		return s.passkeyRepo.UpdatePasskeyCredential(ctx, passkey.ID, encoded, time.Now().UTC())
+	}
+	return ErrPasskeyNotFound
+}
+
+// passkeyUser loads a user with their passkeys
+func (s *PasskeyService) passkeyUser(ctx context.Context, userID uuid.UUID) (*domain.User, *webauthnUser, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrUserNotFound
+		}
+		return nil, nil, err
+	}
+	passkeys, err := s.passkeyRepo.GetPasskeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u := &webauthnUser{user: user, passkeys: passkeys}
+	for _, passkey := range passkeys {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(passkey.Credential, &credential); err != nil {
+			return nil, nil, err
+		}
+		u.credentials = append(u.credentials, credential)
+	}
+	return user, u, nil
+}
+
+// saveCeremony keeps a ceremony until it is finished or expires, and returns
+// the challenge starting it
+func (s *PasskeyService) saveCeremony(ctx context.Context, ceremony *passkeyCeremony, options any) (*PasskeyChallenge, error) {
+	id, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(ceremony)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, ceremonyKey(ceremony.Kind, id), value, s.config.CeremonyTTL); err != nil {
+		return nil, err
+	}
+	return &PasskeyChallenge{ID: id, Options: options}, nil
+}
+
+// takeCeremony returns and forgets the ceremony a challenge started, which
+// must be of one of kinds. Each challenge can only be answered once.
+func (s *PasskeyService) takeCeremony(ctx context.Context, challengeID string, kinds ...string) (*passkeyCeremony, error) {
+	// Ceremonies are keyed by kind, so answering a challenge for another
+	// kind leaves it in place; taking it in one step keeps concurrent
+	// finishes from both using it
+	for _, kind := range kinds {
+		value, err := s.cache.Take(ctx, ceremonyKey(kind, challengeID))
+		if errors.Is(err, cache.ErrMiss) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var ceremony passkeyCeremony
+		if err := json.Unmarshal(value, &ceremony); err != nil {
+			return nil, err
+		}
+		return &ceremony, nil
+	}
+	return nil, ErrCeremonyNotFound
+}
+
+// ceremonyKey is the cache key of a passkey ceremony of kind
+func ceremonyKey(kind, challengeID string) string {
+	return "passkey_ceremony:" + kind + ":" + challengeID
+}
+
+// webauthnUser is a user as the WebAuthn library sees them. The user handle
+// is the user's ID, which discoverable logins are looked up by.
+type webauthnUser struct {
+	user        *domain.User
+	passkeys    []*domain.Passkey
+	credentials []webauthn.Credential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
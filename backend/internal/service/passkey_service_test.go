@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPasskeyRepository keeps passkeys in memory, and the passkey
+// requirement on the users it shares with a user repository
+type memoryPasskeyRepository struct {
+	passkeys []*domain.Passkey
+	users    map[uuid.UUID]*domain.User
+}
+
+func (r *memoryPasskeyRepository) CreatePasskey(ctx context.Context, passkey *domain.Passkey) error {
+	for _, existing := range r.passkeys {
+		if string(existing.CredentialID) == string(passkey.CredentialID) {
+			return repository.ErrConflict
+		}
+	}
+	passkey.ID = uuid.New()
+	r.passkeys = append(r.passkeys, passkey)
+	return nil
+}
+
+func (r *memoryPasskeyRepository) CountPasskeysByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	passkeys, _ := r.GetPasskeysByUserID(ctx, userID)
+	return len(passkeys), nil
+}
+
+func (r *memoryPasskeyRepository) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Passkey, error) {
+	var passkeys []*domain.Passkey
+	for _, passkey := range r.passkeys {
+		if passkey.UserID == userID {
+			passkeys = append(passkeys, passkey)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *memoryPasskeyRepository) UpdatePasskeyCredential(ctx context.Context, id uuid.UUID, credential json.RawMessage, usedAt time.Time) error {
+	for _, passkey := range r.passkeys {
+		if passkey.ID == id {
+			passkey.Credential = credential
+			passkey.LastUsedAt = usedAt
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *memoryPasskeyRepository) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	for i, passkey := range r.passkeys {
+		if passkey.ID == id && passkey.UserID == userID {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *memoryPasskeyRepository) SetPasskeyRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	user, ok := r.users[userID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.PasskeyRequired = required
+	return nil
+}
+
+// newTestPasskeyService returns a passkey service for the users, and the
+// auth service it issues tokens with
+func newTestPasskeyService(t *testing.T, users map[uuid.UUID]*domain.User) (*PasskeyService, *AuthService, *memoryPasskeyRepository) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	userRepo := &identityUserRepository{users: users}
+	passkeyRepo := &memoryPasskeyRepository{users: users}
+	authService := NewAuthService(userRepo, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), AuthServiceConfig{})
+
+	svc, err := NewPasskeyService(passkeyRepo, userRepo, authService, store, PasskeyServiceConfig{
+		RPID:      "example.com",
+		RPOrigins: []string{"https://app.example.com"},
+	})
+	require.NoError(t, err)
+	authService.SetPasskeyService(svc)
+	return svc, authService, passkeyRepo
+}
+
+// addTestPasskey registers a passkey for the user without a ceremony
+func addTestPasskey(t *testing.T, repo *memoryPasskeyRepository, userID uuid.UUID, credentialID string) *domain.Passkey {
+	credential, err := json.Marshal(webauthn.Credential{ID: []byte(credentialID), PublicKey: []byte("key")})
+	require.NoError(t, err)
+	passkey := &domain.Passkey{UserID: userID, Name: credentialID, CredentialID: []byte(credentialID), Credential: credential}
+	require.NoError(t, repo.CreatePasskey(context.Background(), passkey))
+	return passkey
+}
+
+func TestPasskeyServiceCeremonyIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestPasskeyService(t, map[uuid.UUID]*domain.User{})
+
+	challenge, err := svc.BeginLogin(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, challenge.ID)
+	assert.NotNil(t, challenge.Options)
+
+	// A login challenge can't finish a registration
+	_, err = svc.FinishRegistration(ctx, uuid.New(), challenge.ID, []byte(`{}`))
+	assert.ErrorIs(t, err, ErrCeremonyNotFound)
+
+	// A malformed answer still uses the challenge up
+	_, err = svc.FinishLogin(ctx, challenge.ID, []byte(`{}`), "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrPasskeyInvalid)
+	_, err = svc.FinishLogin(ctx, challenge.ID, []byte(`{}`), "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrCeremonyNotFound)
+}
+
+func TestPasskeyServiceConcurrentFinish(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestPasskeyService(t, map[uuid.UUID]*domain.User{})
+
+	challenge, err := svc.BeginLogin(ctx)
+	require.NoError(t, err)
+
+	// A challenge answered twice at once is only used by one of the answers
+	var taken atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.takeCeremony(ctx, challenge.ID, ceremonyLogin)
+			if err == nil {
+				taken.Add(1)
+			} else {
+				assert.ErrorIs(t, err, ErrCeremonyNotFound)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), taken.Load())
+}
+
+func TestPasskeyServiceSecondFactor(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com"}
+	svc, _, repo := newTestPasskeyService(t, map[uuid.UUID]*domain.User{user.ID: user})
+
+	// Passkeys can only be required once there is one
+	assert.ErrorIs(t, svc.SetSecondFactor(ctx, user.ID, true), ErrNoPasskeys)
+
+	first := addTestPasskey(t, repo, user.ID, "first")
+	second := addTestPasskey(t, repo, user.ID, "second")
+	require.NoError(t, svc.SetSecondFactor(ctx, user.ID, true))
+	assert.True(t, user.PasskeyRequired)
+
+	// Removing the last passkey stops requiring one
+	require.NoError(t, svc.DeletePasskey(ctx, user.ID, first.ID))
+	assert.True(t, user.PasskeyRequired)
+	assert.ErrorIs(t, svc.DeletePasskey(ctx, uuid.New(), second.ID), ErrPasskeyNotFound)
+	require.NoError(t, svc.DeletePasskey(ctx, user.ID, second.ID))
+	assert.False(t, user.PasskeyRequired)
+}
+
+func TestLoginRequiresPasskey(t *testing.T) {
+	ctx := context.Background()
+	hash, err := security.HashPassword("correct horse battery", nil)
+	require.NoError(t, err)
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", PasswordHash: hash, PasskeyRequired: true}
+	svc, authService, repo := newTestPasskeyService(t, map[uuid.UUID]*domain.User{user.ID: user})
+	addTestPasskey(t, repo, user.ID, "first")
+
+	// The right password only gets a challenge for the user's passkeys
+	_, err = authService.Login(ctx, user.Email, "correct horse battery", "test", "127.0.0.1")
+	var secondFactor *SecondFactorRequiredError
+	require.ErrorAs(t, err, &secondFactor)
+	assert.ErrorIs(t, err, ErrSecondFactorRequired)
+	ceremony, err := svc.takeCeremony(ctx, secondFactor.Challenge.ID, ceremonySecondFactor)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, ceremony.UserID)
+
+	_, err = authService.Login(ctx, user.Email, "wrong password", "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// Without passkeys set up the login can't be confirmed
+	authService.SetPasskeyService(nil)
+	_, err = authService.Login(ctx, user.Email, "correct horse battery", "test", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrSecondFactorUnavailable)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// Errors returned by the permission service
+var (
+	// ErrUserRoleNotFound is returned when no user role has the requested name
+	ErrUserRoleNotFound = errors.New("user role not found")
+	// ErrBuiltinRole is returned when changing the admin role or deleting
+	// a role the instance depends on
+	ErrBuiltinRole = errors.New("built-in role can't be changed")
+)
+
+// userRolesKey is the cache key of every user role, as checked on requests
+const userRolesKey = "auth:user_roles"
+
+// PermissionServiceConfig contains configuration for the permission service
+type PermissionServiceConfig struct {
+	// CacheTTL bounds how long roles are cached between checks. Changes made
+	// through the service apply at once.
+	CacheTTL time.Duration
+}
+
+// PermissionService checks what the role of a user lets them do beyond their
+// own resumes and account, and manages the roles
+type PermissionService struct {
+	roleRepo domain.UserRoleRepository
+	userRepo domain.UserRepository
+	cache    cache.Cache
+	config   PermissionServiceConfig
+}
+
+// NewPermissionService creates a new permission service
+func NewPermissionService(roleRepo domain.UserRoleRepository, userRepo domain.UserRepository, store cache.Cache, config PermissionServiceConfig) *PermissionService {
+	// Set default values if not provided
+	if config.CacheTTL == 0 {
+		config.CacheTTL = 5 * time.Minute
+	}
+
+	return &PermissionService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		cache:    store,
+		config:   config,
+	}
+}
+
+// HasPermission reports whether a role grants a permission. The admin role
+// grants every permission; roles that don't exist grant none.
+func (s *PermissionService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	if role == domain.RoleAdmin {
+		return true, nil
+	}
+
+	roles, err := s.cachedRoles(ctx)
+	if err != nil {
+		return false, err
+	}
+	i := slices.IndexFunc(roles, func(r *domain.UserRole) bool { return r.Name == role })
+	return i >= 0 && roles[i].HasPermission(permission), nil
+}
+
+// Roles returns every user role, by name
+func (s *PermissionService) Roles(ctx context.Context) ([]*domain.UserRole, error) {
+	return s.roleRepo.ListUserRoles(ctx)
+}
+
+// SaveRole validates and stores a role, replacing the one with its name. The
+// admin role always grants every permission, so it can't be changed.
+func (s *PermissionService) SaveRole(ctx context.Context, role *domain.UserRole) error {
+	role.BeforeSave()
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	if role.Name == domain.RoleAdmin {
+		return ErrBuiltinRole
+	}
+
+	role.UpdatedAt = time.Now().UTC()
+	if err := s.roleRepo.SaveUserRole(ctx, role); err != nil {
+		return err
+	}
+	s.forgetRoles(ctx)
+	return nil
+}
+
+// DeleteRole deletes a role. The user and admin roles can't be deleted, nor
+// can roles users still have (repository.ErrRoleInUse).
+func (s *PermissionService) DeleteRole(ctx context.Context, name string) error {
+	if name == domain.RoleUser || name == domain.RoleAdmin {
+		return ErrBuiltinRole
+	}
+
+	if err := s.roleRepo.DeleteUserRole(ctx, name); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserRoleNotFound
+		}
+		return err
+	}
+	s.forgetRoles(ctx)
+	return nil
+}
+
+// SetUserRole gives a user a role. Tokens the user holds keep their old
+// role until they expire, so callers sign the user out.
+func (s *PermissionService) SetUserRole(ctx context.Context, userID uuid.UUID, role string) (*domain.User, error) {
+	roles, err := s.roleRepo.ListUserRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(roles, func(r *domain.UserRole) bool { return r.Name == role }) {
+		return nil, ErrUserRoleNotFound
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Role = role
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// cachedRoles returns every role from the cache, loading them on a miss.
+// Cache failures fall back to the repository.
+func (s *PermissionService) cachedRoles(ctx context.Context) ([]*domain.UserRole, error) {
+	data, err := s.cache.Get(ctx, userRolesKey)
+	if err == nil {
+		var roles []*domain.UserRole
+		if err := json.Unmarshal(data, &roles); err == nil {
+			return roles, nil
+		}
+	} else if !errors.Is(err, cache.ErrMiss) {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to read cached user roles")
+	}
+
+	roles, err := s.roleRepo.ListUserRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(roles)
+	if err == nil {
+		err = s.cache.Set(ctx, userRolesKey, data, s.config.CacheTTL)
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to cache user roles")
+	}
+	return roles, nil
+}
+
+// forgetRoles drops the cached roles after a change. A failure only leaves
+// them stale until the TTL expires.
+func (s *PermissionService) forgetRoles(ctx context.Context) {
+	if err := s.cache.Del(ctx, userRolesKey); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to drop cached user roles")
+	}
+}
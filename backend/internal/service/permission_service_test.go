@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userRoleRepository keeps user roles in memory, refusing to delete roles
+// the users in users have
+type userRoleRepository struct {
+	roles map[string]*domain.UserRole
+	users *roleUserRepository
+	lists int
+}
+
+func (r *userRoleRepository) ListUserRoles(ctx context.Context) ([]*domain.UserRole, error) {
+	r.lists++
+	var roles []*domain.UserRole
+	for _, role := range r.roles {
+		copied := *role
+		roles = append(roles, &copied)
+	}
+	slices.SortFunc(roles, func(a, b *domain.UserRole) int { return strings.Compare(a.Name, b.Name) })
+	return roles, nil
+}
+
+func (r *userRoleRepository) SaveUserRole(ctx context.Context, role *domain.UserRole) error {
+	copied := *role
+	r.roles[role.Name] = &copied
+	return nil
+}
+
+func (r *userRoleRepository) DeleteUserRole(ctx context.Context, name string) error {
+	if _, ok := r.roles[name]; !ok {
+		return repository.ErrNotFound
+	}
+	for _, user := range r.users.users {
+		if user.Role == name {
+			return repository.ErrRoleInUse
+		}
+	}
+	delete(r.roles, name)
+	return nil
+}
+
+// roleUserRepository keeps users in memory
+type roleUserRepository struct {
+	domain.UserRepository
+	users map[uuid.UUID]*domain.User
+}
+
+func (r *roleUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if user, ok := r.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *roleUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func newTestPermissionService(t *testing.T) (*PermissionService, *userRoleRepository) {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	users := &roleUserRepository{users: map[uuid.UUID]*domain.User{}}
+	roles := &userRoleRepository{users: users, roles: map[string]*domain.UserRole{
+		domain.RoleUser:    {Name: domain.RoleUser, Permissions: []string{}},
+		domain.RoleAdmin:   {Name: domain.RoleAdmin, Permissions: domain.Permissions},
+		domain.RoleSupport: {Name: domain.RoleSupport, Permissions: []string{domain.PermissionResumesReadAny, domain.PermissionUsersRead}},
+	}}
+	return NewPermissionService(roles, users, store, PermissionServiceConfig{}), roles
+}
+
+func TestPermissionServiceHasPermission(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestPermissionService(t)
+
+	check := func(role, permission string) bool {
+		t.Helper()
+		allowed, err := svc.HasPermission(ctx, role, permission)
+		require.NoError(t, err)
+		return allowed
+	}
+	assert.True(t, check(domain.RoleSupport, domain.PermissionResumesReadAny))
+	assert.False(t, check(domain.RoleSupport, domain.PermissionResumesWriteAny))
+	assert.False(t, check(domain.RoleUser, domain.PermissionUsersRead))
+	assert.False(t, check("unknown", domain.PermissionUsersRead))
+	// The admin role grants permissions it wasn't saved with
+	assert.True(t, check(domain.RoleAdmin, "reports:read"))
+
+	// Roles are loaded once, then read from the cache
+	assert.Equal(t, 1, repo.lists)
+
+	// Changes apply at once
+	require.NoError(t, svc.SaveRole(ctx, &domain.UserRole{
+		Name:        " Support ",
+		Permissions: []string{domain.PermissionResumesWriteAny, domain.PermissionResumesReadAny, domain.PermissionResumesReadAny},
+	}))
+	assert.Equal(t, []string{domain.PermissionResumesReadAny, domain.PermissionResumesWriteAny}, repo.roles[domain.RoleSupport].Permissions)
+	assert.True(t, check(domain.RoleSupport, domain.PermissionResumesWriteAny))
+	assert.False(t, check(domain.RoleSupport, domain.PermissionUsersRead))
+}
+
+func TestPermissionServiceManagesRoles(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestPermissionService(t)
+
+	// Invalid roles and the admin role aren't saved
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, svc.SaveRole(ctx, &domain.UserRole{Name: "billing", Permissions: []string{"billing:manage"}}), &validationErr)
+	require.ErrorAs(t, svc.SaveRole(ctx, &domain.UserRole{Name: domain.RoleServiceAccount}), &validationErr)
+	assert.ErrorIs(t, svc.SaveRole(ctx, &domain.UserRole{Name: domain.RoleAdmin}), ErrBuiltinRole)
+	assert.Len(t, repo.roles, 3)
+
+	require.NoError(t, svc.SaveRole(ctx, &domain.UserRole{Name: "auditor", Permissions: []string{domain.PermissionStatsRead}}))
+	assert.False(t, repo.roles["auditor"].UpdatedAt.IsZero())
+
+	// Users can only be given roles that exist
+	userID := uuid.New()
+	repo.users.users[userID] = &domain.User{ID: userID, Email: "ana@example.com", Role: domain.RoleUser}
+	_, err := svc.SetUserRole(ctx, userID, "billing")
+	assert.ErrorIs(t, err, ErrUserRoleNotFound)
+	_, err = svc.SetUserRole(ctx, uuid.New(), "auditor")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	user, err := svc.SetUserRole(ctx, userID, "auditor")
+	require.NoError(t, err)
+	assert.Equal(t, "auditor", user.Role)
+	assert.Equal(t, "auditor", repo.users.users[userID].Role)
+
+	// Roles users have, and the user and admin roles, can't be deleted
+	assert.ErrorIs(t, svc.DeleteRole(ctx, "auditor"), repository.ErrRoleInUse)
+	assert.ErrorIs(t, svc.DeleteRole(ctx, domain.RoleUser), ErrBuiltinRole)
+	assert.ErrorIs(t, svc.DeleteRole(ctx, domain.RoleAdmin), ErrBuiltinRole)
+	assert.ErrorIs(t, svc.DeleteRole(ctx, "billing"), ErrUserRoleNotFound)
+
+	_, err = svc.SetUserRole(ctx, userID, domain.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteRole(ctx, "auditor"))
+	allowed, err := svc.HasPermission(ctx, "auditor", domain.PermissionStatsRead)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
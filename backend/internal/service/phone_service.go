@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/phone"
+	"github.com/lordaris/resume_generator/pkg/sms"
+)
+
+// PhoneService errors
+var (
+	ErrPhoneNotFound    = errors.New("phone number not found")
+	ErrPhoneNotVerified = errors.New("phone number not verified")
+	ErrInvalidCode      = errors.New("invalid or expired code")
+	ErrCodeLimitReached = errors.New("sms code limit reached")
+)
+
+// What SMS codes are sent for. Each has its own code, so verifying a number
+// doesn't cancel a pending password reset.
+const (
+	codePurposeVerifyPhone   = "verify_phone"
+	codePurposeResetPassword = "reset_password"
+)
+
+// PhoneServiceConfig contains configuration for the phone service
+type PhoneServiceConfig struct {
+	// CodeTTL is how long a code can be used
+	CodeTTL time.Duration
+	// MaxAttempts is how many times a code can be checked
+	MaxAttempts int
+	// MaxCodesPerHour limits the texts sent to each user, whatever the
+	// client IP the requests come from
+	MaxCodesPerHour int
+}
+
+// PhoneService manages the phone numbers users receive SMS codes at, and the
+// codes themselves. Codes are six digits, kept hashed in the cache until used
+// or expired.
+type PhoneService struct {
+	phoneRepo  domain.UserPhoneRepository
+	smsService *SMSService
+	cache      cache.Cache
+	config     PhoneServiceConfig
+	// newCode returns a random code, replaced in tests
+	newCode func() (string, error)
+}
+
+// NewPhoneService creates a new phone service
+func NewPhoneService(phoneRepo domain.UserPhoneRepository, smsService *SMSService, store cache.Cache, config PhoneServiceConfig) *PhoneService {
+	// Set default values if not provided
+	if config.CodeTTL <= 0 {
+		config.CodeTTL = 10 * time.Minute
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.MaxCodesPerHour <= 0 {
+		config.MaxCodesPerHour = 5
+	}
+
+	return &PhoneService{
+		phoneRepo:  phoneRepo,
+		smsService: smsService,
+		cache:      store,
+		config:     config,
+		newCode:    randomCode,
+	}
+}
+
+// pendingCode is a code waiting to be entered, with what it unlocks
+type pendingCode struct {
+	Hash string `json:"hash"`
+	// Payload is returned when the code is entered: the number being
+	// verified or the password reset token
+	Payload string `json:"payload"`
+}
+
+// SetPhone sets the user's phone number and texts it a code to verify it
+// with. number is read as a national number of region unless it is
+// international.
+func (s *PhoneService) SetPhone(ctx context.Context, userID uuid.UUID, number, region string) (*domain.UserPhone, error) {
+	normalized, err := phone.Normalize(number, region)
+	if err != nil {
+		return nil, domain.NewValidationError("phone", "Phone number is not valid", domain.ErrInvalidField)
+	}
+
+	// Check the allowance before changing anything, so a user over it keeps
+	// their verified number
+	if err := s.countCode(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	userPhone := &domain.UserPhone{UserID: userID, Phone: normalized}
+	if err := s.phoneRepo.SavePhone(ctx, userPhone); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendCode(ctx, codePurposeVerifyPhone, userID, normalized, normalized, s.config.CodeTTL,
+		"Your verification code is %s. It expires in %d minutes."); err != nil {
+		return nil, err
+	}
+	return userPhone, nil
+}
+
+// Phone returns the user's phone number
+func (s *PhoneService) Phone(ctx context.Context, userID uuid.UUID) (*domain.UserPhone, error) {
+	userPhone, err := s.phoneRepo.GetPhone(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrPhoneNotFound
+	}
+	return userPhone, err
+}
+
+// VerifyPhone verifies the user's number with the code texted to it
+func (s *PhoneService) VerifyPhone(ctx context.Context, userID uuid.UUID, code string) (*domain.UserPhone, error) {
+	number, err := s.checkCode(ctx, codePurposeVerifyPhone, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	// The code was for the number it was sent to, which may have changed since
+	if err := s.phoneRepo.VerifyPhone(ctx, userID, number); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCode
+		}
+		return nil, err
+	}
+	return s.Phone(ctx, userID)
+}
+
+// RemovePhone removes the user's phone number, and any code pending for it
+func (s *PhoneService) RemovePhone(ctx context.Context, userID uuid.UUID) error {
+	err := s.phoneRepo.DeletePhone(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrPhoneNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Del(ctx,
+		codeKey(codePurposeVerifyPhone, userID), codeAttemptsKey(codePurposeVerifyPhone, userID),
+		codeKey(codePurposeResetPassword, userID), codeAttemptsKey(codePurposeResetPassword, userID))
+}
+
+// SendResetCode texts the user's verified number a code that unlocks
+// resetToken, valid for at most ttl
+func (s *PhoneService) SendResetCode(ctx context.Context, userID uuid.UUID, resetToken string, ttl time.Duration) error {
+	userPhone, err := s.Phone(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !userPhone.Verified() {
+		return ErrPhoneNotVerified
+	}
+
+	if err := s.countCode(ctx, userID); err != nil {
+		return err
+	}
+	return s.sendCode(ctx, codePurposeResetPassword, userID, userPhone.Phone, resetToken, min(ttl, s.config.CodeTTL),
+		"Your password reset code is %s. It expires in %d minutes. If you did not request it, you can ignore this message.")
+}
+
+// CheckResetCode returns the password reset token the code texted to the
+// user unlocks. Each code can be used once.
+func (s *PhoneService) CheckResetCode(ctx context.Context, userID uuid.UUID, code string) (string, error) {
+	return s.checkCode(ctx, codePurposeResetPassword, userID, code)
+}
+
+// countCode counts a text to the user against their hourly allowance
+func (s *PhoneService) countCode(ctx context.Context, userID uuid.UUID) error {
+	sent, err := s.cache.Incr(ctx, "sms_codes_sent:"+userID.String(), time.Hour)
+	if err != nil {
+		return err
+	}
+	if sent > int64(s.config.MaxCodesPerHour) {
+		return ErrCodeLimitReached
+	}
+	return nil
+}
+
+// sendCode stores a new code for purpose, replacing any pending one, and
+// texts it to number. text formats the code and the minutes it lasts.
+func (s *PhoneService) sendCode(ctx context.Context, purpose string, userID uuid.UUID, number, payload string, ttl time.Duration, text string) error {
+	code, err := s.newCode()
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(pendingCode{Hash: hashAPIKey(code), Payload: payload})
+	if err != nil {
+		return err
+	}
+	if err := s.cache.Set(ctx, codeKey(purpose, userID), value, ttl); err != nil {
+		return err
+	}
+	// A new code gets a fresh set of attempts, counted for as long as it lasts
+	if err := s.cache.Set(ctx, codeAttemptsKey(purpose, userID), []byte("0"), ttl); err != nil {
+		return err
+	}
+
+	return s.smsService.Send(ctx, &sms.Message{To: number, Body: fmt.Sprintf(text, code, max(int(ttl/time.Minute), 1))})
+}
+
+// checkCode returns the payload of the pending code for purpose when code
+// matches it, and uses the code up. Every check counts as an attempt before
+// the code is compared, so parallel guesses can't get past MaxAttempts; a
+// code checked more often than that is discarded.
+func (s *PhoneService) checkCode(ctx context.Context, purpose string, userID uuid.UUID, code string) (string, error) {
+	attempts, err := s.cache.Incr(ctx, codeAttemptsKey(purpose, userID), s.config.CodeTTL)
+	if err != nil {
+		return "", err
+	}
+	if attempts > int64(s.config.MaxAttempts) {
+		if err := s.cache.Del(ctx, codeKey(purpose, userID), codeAttemptsKey(purpose, userID)); err != nil {
+			return "", err
+		}
+		return "", ErrInvalidCode
+	}
+
+	pending, err := s.pendingCode(ctx, purpose, userID, s.cache.Get)
+	if err != nil {
+		return "", err
+	}
+	hash := []byte(hashAPIKey(code))
+	if subtle.ConstantTimeCompare(hash, []byte(pending.Hash)) != 1 {
+		return "", ErrInvalidCode
+	}
+
+	// Taking the code in one step lets only one of concurrent checks redeem
+	// it; a code replaced in between doesn't match
+	pending, err = s.pendingCode(ctx, purpose, userID, s.cache.Take)
+	if err != nil {
+		return "", err
+	}
+	if subtle.ConstantTimeCompare(hash, []byte(pending.Hash)) != 1 {
+		return "", ErrInvalidCode
+	}
+	if err := s.cache.Del(ctx, codeAttemptsKey(purpose, userID)); err != nil {
+		return "", err
+	}
+	return pending.Payload, nil
+}
+
+// pendingCode reads the pending code for purpose with read, which is the
+// cache's Get or Take
+func (s *PhoneService) pendingCode(ctx context.Context, purpose string, userID uuid.UUID, read func(context.Context, string) ([]byte, error)) (*pendingCode, error) {
+	value, err := read(ctx, codeKey(purpose, userID))
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, ErrInvalidCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pending pendingCode
+	if err := json.Unmarshal(value, &pending); err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// randomCode returns a random six-digit code
+func randomCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// codeKey is the cache key of a user's pending code for purpose. A user's
+// code keys share a hash tag, so they are deleted together on Redis Cluster.
+func codeKey(purpose string, userID uuid.UUID) string {
+	return "sms_code:{" + userID.String() + "}:" + purpose
+}
+
+// codeAttemptsKey is the cache key counting wrong guesses of a user's
+// pending code for purpose
+func codeAttemptsKey(purpose string, userID uuid.UUID) string {
+	return "sms_code_attempts:{" + userID.String() + "}:" + purpose
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/auth"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/sms"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPhoneRepository keeps phone numbers in memory
+type memoryPhoneRepository struct {
+	phones map[uuid.UUID]*domain.UserPhone
+}
+
+func (r *memoryPhoneRepository) SavePhone(ctx context.Context, phone *domain.UserPhone) error {
+	phone.VerifiedAt = time.Time{}
+	saved := *phone
+	r.phones[phone.UserID] = &saved
+	return nil
+}
+
+func (r *memoryPhoneRepository) GetPhone(ctx context.Context, userID uuid.UUID) (*domain.UserPhone, error) {
+	phone, ok := r.phones[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	found := *phone
+	return &found, nil
+}
+
+func (r *memoryPhoneRepository) VerifyPhone(ctx context.Context, userID uuid.UUID, number string) error {
+	phone, ok := r.phones[userID]
+	if !ok || phone.Phone != number {
+		return repository.ErrNotFound
+	}
+	phone.VerifiedAt = time.Now()
+	return nil
+}
+
+func (r *memoryPhoneRepository) DeletePhone(ctx context.Context, userID uuid.UUID) error {
+	if _, ok := r.phones[userID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.phones, userID)
+	return nil
+}
+
+// newTestPhoneService returns a phone service whose codes are handed out from
+// codes in turn
+func newTestPhoneService(t *testing.T, config PhoneServiceConfig, codes ...string) *PhoneService {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	mr := miniredis.RunT(t)
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})})
+
+	svc := NewPhoneService(&memoryPhoneRepository{phones: map[uuid.UUID]*domain.UserPhone{}}, NewSMSService(queue, sms.NewLogSender()), store, config)
+	svc.newCode = func() (string, error) {
+		code := codes[0]
+		codes = codes[1:]
+		return code, nil
+	}
+	return svc
+}
+
+func TestPhoneServiceVerify(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestPhoneService(t, PhoneServiceConfig{}, "123456", "654321")
+	userID := uuid.New()
+
+	_, err := svc.SetPhone(ctx, userID, "not a number", "US")
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	userPhone, err := svc.SetPhone(ctx, userID, "(415) 555-2671", "US")
+	require.NoError(t, err)
+	assert.Equal(t, "+14155552671", userPhone.Phone)
+	assert.False(t, userPhone.Verified())
+
+	// A code sent to an earlier number doesn't verify the new one
+	_, err = svc.SetPhone(ctx, userID, "+442071838750", "")
+	require.NoError(t, err)
+	_, err = svc.VerifyPhone(ctx, userID, "123456")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	userPhone, err = svc.VerifyPhone(ctx, userID, "654321")
+	require.NoError(t, err)
+	assert.True(t, userPhone.Verified())
+	assert.Equal(t, "+442071838750", userPhone.Phone)
+
+	// Codes are used up
+	_, err = svc.VerifyPhone(ctx, userID, "654321")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
+
+func TestPhoneServiceCodeAttempts(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestPhoneService(t, PhoneServiceConfig{MaxAttempts: 2}, "123456")
+	userID := uuid.New()
+
+	_, err := svc.SetPhone(ctx, userID, "+14155552671", "")
+	require.NoError(t, err)
+
+	// Too many wrong guesses discard the code, so the right one fails too
+	for range 2 {
+		_, err = svc.VerifyPhone(ctx, userID, "000000")
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+	_, err = svc.VerifyPhone(ctx, userID, "123456")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
+
+func TestPhoneServiceConcurrentChecks(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestPhoneService(t, PhoneServiceConfig{MaxAttempts: 3}, "123456", "654321")
+	userID := uuid.New()
+
+	_, err := svc.SetPhone(ctx, userID, "+14155552671", "")
+	require.NoError(t, err)
+
+	// Parallel wrong guesses all count, so the right code no longer works
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.VerifyPhone(ctx, userID, "000000")
+			assert.ErrorIs(t, err, ErrInvalidCode)
+		}()
+	}
+	wg.Wait()
+	_, err = svc.VerifyPhone(ctx, userID, "123456")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	// and a code redeemed twice at once only works once
+	_, err = svc.SetPhone(ctx, userID, "+14155552671", "")
+	require.NoError(t, err)
+	var verified atomic.Int32
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.VerifyPhone(ctx, userID, "654321"); err == nil {
+				verified.Add(1)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidCode)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), verified.Load())
+}
+
+func TestPhoneServiceCodeKeysShareASlot(t *testing.T) {
+	userID := uuid.New()
+
+	// RemovePhone deletes all of a user's code keys at once, which Redis
+	// Cluster refuses for keys in different slots
+	for _, key := range []string{
+		codeKey(codePurposeVerifyPhone, userID), codeAttemptsKey(codePurposeVerifyPhone, userID),
+		codeKey(codePurposeResetPassword, userID), codeAttemptsKey(codePurposeResetPassword, userID),
+	} {
+		start, end := strings.IndexByte(key, '{'), strings.IndexByte(key, '}')
+		require.True(t, start >= 0 && end > start+1, key)
+		assert.Equal(t, userID.String(), key[start+1:end], key)
+	}
+}
+
+func TestPhoneServiceCodeLimit(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestPhoneService(t, PhoneServiceConfig{MaxCodesPerHour: 1}, "123456", "654321")
+	userID := uuid.New()
+
+	_, err := svc.SetPhone(ctx, userID, "+14155552671", "")
+	require.NoError(t, err)
+	_, err = svc.VerifyPhone(ctx, userID, "123456")
+	require.NoError(t, err)
+
+	// Over the allowance the verified number is kept
+	_, err = svc.SetPhone(ctx, userID, "+442071838750", "")
+	assert.ErrorIs(t, err, ErrCodeLimitReached)
+	userPhone, err := svc.Phone(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, userPhone.Verified())
+	assert.Equal(t, "+14155552671", userPhone.Phone)
+}
+
+// resetUserRepository stores the password resets of one user
+type resetUserRepository struct {
+	domain.UserRepository
+	user   *domain.User
+	resets map[string]*domain.PasswordReset
+}
+
+func (r *resetUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	if email != r.user.Email {
+		return nil, repository.ErrNotFound
+	}
+	return r.user, nil
+}
+
+func (r *resetUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.user, nil
+}
+
+func (r *resetUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	return nil
+}
+
+func (r *resetUserRepository) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) error {
+	r.resets[reset.Token] = reset
+	return nil
+}
+
+func (r *resetUserRepository) GetPasswordResetByToken(ctx context.Context, token string) (*domain.PasswordReset, error) {
+	reset, ok := r.resets[token]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return reset, nil
+}
+
+func (r *resetUserRepository) MarkPasswordResetUsed(ctx context.Context, id uuid.UUID) error {
+	for _, reset := range r.resets {
+		if reset.ID == id {
+			reset.UsedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *resetUserRepository) DeleteUserSessions(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+func TestResetPasswordBySMS(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", PasswordHash: "old"}
+	repo := &resetUserRepository{user: user, resets: map[string]*domain.PasswordReset{}}
+	authService := NewAuthService(repo, auth.NewJWT(auth.JWTConfig{Secret: "test-secret"}), AuthServiceConfig{ResetTokenExpiry: time.Hour})
+
+	// Without a phone service there is no SMS channel
+	err := authService.RequestPasswordReset(ctx, user.Email, ResetChannelSMS)
+	assert.ErrorIs(t, err, ErrChannelUnavailable)
+
+	phoneService := newTestPhoneService(t, PhoneServiceConfig{}, "111111", "222222")
+	authService.SetPhoneService(phoneService)
+
+	// Codes only go to verified numbers
+	err = authService.RequestPasswordReset(ctx, user.Email, ResetChannelSMS)
+	assert.ErrorIs(t, err, ErrPhoneNotVerified)
+	_, err = phoneService.SetPhone(ctx, user.ID, "+14155552671", "")
+	require.NoError(t, err)
+	err = authService.RequestPasswordReset(ctx, user.Email, ResetChannelSMS)
+	assert.ErrorIs(t, err, ErrPhoneNotVerified)
+	_, err = phoneService.VerifyPhone(ctx, user.ID, "111111")
+	require.NoError(t, err)
+
+	// Only the texted code unlocks the token
+	require.NoError(t, authService.RequestPasswordReset(ctx, user.Email, ResetChannelSMS))
+
+	err = authService.ResetPasswordWithCode(ctx, "someone@example.com", "222222", "new password")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	err = authService.ResetPasswordWithCode(ctx, user.Email, "999999", "new password")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	require.NoError(t, authService.ResetPasswordWithCode(ctx, user.Email, "222222", "new password"))
+	assert.NotEqual(t, "old", user.PasswordHash)
+	err = authService.ResetPasswordWithCode(ctx, user.Email, "222222", "new password")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
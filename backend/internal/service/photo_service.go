@@ -0,0 +1,303 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // decodes uploaded GIF photos
+	"image/jpeg"
+	_ "image/png" // decodes uploaded PNG photos
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // decodes uploaded WebP photos
+)
+
+// Photo errors
+var (
+	ErrUnsupportedPhoto = errors.New("photo must be a JPEG, PNG, GIF or WebP image")
+	ErrPhotoTooLarge    = errors.New("photo has too many pixels")
+	ErrNoPersonalInfo   = errors.New("save personal info before uploading a photo")
+	ErrPhotoNotFound    = errors.New("photo not found")
+)
+
+// photoPrefix is the storage folder photos are kept in
+const photoPrefix = "photos/"
+
+// PhotoServiceConfig contains configuration for the photo service
+type PhotoServiceConfig struct {
+	// BaseURL is the public URL of this API photo URLs start with; they
+	// are relative when empty
+	BaseURL string
+	// MaxSourcePixels rejects uploads with more pixels before decoding
+	// them, so small files can't expand into huge images
+	MaxSourcePixels int
+	// MaxDimension is the largest width and height stored photos are
+	// scaled down to
+	MaxDimension int
+	// Quality is the JPEG quality stored photos are encoded with
+	Quality int
+}
+
+// PhotoService stores the profile photos of resumes. Uploads are decoded,
+// scaled down and re-encoded as JPEG, which drops their metadata, and
+// linked from the resume's personal info.
+type PhotoService struct {
+	resumeRepo domain.ResumeRepository
+	store      storage.Storage
+	config     PhotoServiceConfig
+}
+
+// NewPhotoService creates a new photo service
+func NewPhotoService(resumeRepo domain.ResumeRepository, store storage.Storage, config PhotoServiceConfig) *PhotoService {
+	// Set default values if not provided
+	if config.MaxSourcePixels <= 0 {
+		config.MaxSourcePixels = 40_000_000
+	}
+	if config.MaxDimension <= 0 {
+		config.MaxDimension = 512
+	}
+	if config.Quality <= 0 || config.Quality > 100 {
+		config.Quality = 85
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return &PhotoService{
+		resumeRepo: resumeRepo,
+		store:      store,
+		config:     config,
+	}
+}
+
+// Upload stores the photo read from r as the resume's profile photo,
+// replacing any previous one, and returns its URL
+func (s *PhotoService) Upload(ctx context.Context, resumeID uuid.UUID, r io.Reader) (string, error) {
+	info, err := s.resumeRepo.GetPersonalInfo(ctx, resumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", ErrNoPersonalInfo
+	}
+	if err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	photo, err := s.process(data)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	name := token + ".jpg"
+	if err := s.store.Put(ctx, photoPrefix+name, bytes.NewReader(photo), "image/jpeg"); err != nil {
+		return "", err
+	}
+
+	photoURL := s.config.BaseURL + "/api/v1/photos/" + name
+	if err := s.resumeRepo.SetPhotoURL(ctx, resumeID, photoURL); err != nil {
+		s.deleteObject(ctx, name)
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrNoPersonalInfo
+		}
+		return "", err
+	}
+
+	if info.PhotoURL != "" {
+		s.deleteObject(ctx, path.Base(info.PhotoURL))
+	}
+	return photoURL, nil
+}
+
+// Remove removes the resume's profile photo
+func (s *PhotoService) Remove(ctx context.Context, resumeID uuid.UUID) error {
+	info, err := s.resumeRepo.GetPersonalInfo(ctx, resumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrPhotoNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if info.PhotoURL == "" {
+		return ErrPhotoNotFound
+	}
+
+	if err := s.resumeRepo.SetPhotoURL(ctx, resumeID, ""); err != nil {
+		return err
+	}
+	s.deleteObject(ctx, path.Base(info.PhotoURL))
+	return nil
+}
+
+// Get returns the stored photo with the given name, the last element of
+// its URL
+func (s *PhotoService) Get(ctx context.Context, name string) (*storage.Object, error) {
+	if strings.Contains(name, "/") || !storage.ValidKey(name) {
+		return nil, ErrPhotoNotFound
+	}
+
+	object, err := s.store.Get(ctx, photoPrefix+name)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrPhotoNotFound
+	}
+	return object, err
+}
+
+// deleteObject deletes a replaced photo. The resume no longer links it, so a
+// failure only leaves an orphaned file and is logged rather than returned.
+func (s *PhotoService) deleteObject(ctx context.Context, name string) {
+	if err := s.store.Delete(ctx, photoPrefix+name); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("photo", name).Msg("Failed to delete photo")
+	}
+}
+
+// process decodes an uploaded image, scales it down to fit the maximum
+// dimension, turns it upright and encodes it as JPEG
+func (s *PhotoService) process(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedPhoto
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrUnsupportedPhoto
+	}
+	if cfg.Width*cfg.Height > s.config.MaxSourcePixels {
+		return nil, ErrPhotoTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedPhoto
+	}
+
+	// Scale to fit a square, so the bound holds whichever way the photo is
+	// turned afterwards
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longest := max(width, height); longest > s.config.MaxDimension {
+		width = max(1, width*s.config.MaxDimension/longest)
+		height = max(1, height*s.config.MaxDimension/longest)
+	}
+
+	// JPEG has no transparency, so transparent parts turn white
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(scaled, scaled.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), src, bounds, draw.Over, nil)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, orient(scaled, jpegOrientation(data)), &jpeg.Options{Quality: s.config.Quality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// orient turns an image upright according to its EXIF orientation, from 1
+// (already upright) to 8
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	// Orientations 5 to 8 swap width and height
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // rotated 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // mirrored and rotated 270° clockwise
+				sx, sy = y, x
+			case 6: // rotated 90° clockwise
+				sx, sy = y, h-1-x
+			case 7: // mirrored and rotated 90° clockwise
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 270° clockwise
+				sx, sy = w-1-y, x
+			}
+			dst.SetRGBA(x, y, img.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG image, 1 (upright)
+// when it has none. Cameras store photos as the sensor reads them and
+// record how they should be turned, which re-encoding would lose.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		// Start of scan: the metadata segments are over
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			break
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag of the first image directory
+// of EXIF data
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		// The orientation tag, a SHORT stored in the value field
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
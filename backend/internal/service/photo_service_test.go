@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// photoResumeRepository keeps the personal info of resumes
+type photoResumeRepository struct {
+	domain.ResumeRepository
+	info map[uuid.UUID]*domain.PersonalInfo
+}
+
+func (r *photoResumeRepository) GetPersonalInfo(ctx context.Context, resumeID uuid.UUID) (*domain.PersonalInfo, error) {
+	info, ok := r.info[resumeID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *info
+	return &copied, nil
+}
+
+func (r *photoResumeRepository) SetPhotoURL(ctx context.Context, resumeID uuid.UUID, photoURL string) error {
+	info, ok := r.info[resumeID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	info.PhotoURL = photoURL
+	return nil
+}
+
+// encodePNG encodes a solid image of the given size
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// decodeStored decodes the photo stored under a photo URL
+func decodeStored(t *testing.T, store storage.Storage, photoURL string) image.Image {
+	object, err := store.Get(context.Background(), "photos/"+path.Base(photoURL))
+	require.NoError(t, err)
+	defer object.Body.Close()
+	assert.Equal(t, "image/jpeg", object.ContentType)
+	img, err := jpeg.Decode(object.Body)
+	require.NoError(t, err)
+	return img
+}
+
+func TestPhotoServiceUpload(t *testing.T) {
+	ctx := context.Background()
+	resumeID := uuid.New()
+	repo := &photoResumeRepository{info: map[uuid.UUID]*domain.PersonalInfo{resumeID: {FirstName: "Ada"}}}
+	store := storage.NewMemory()
+	svc := NewPhotoService(repo, store, PhotoServiceConfig{BaseURL: "https://api.example.com/"})
+
+	first, err := svc.Upload(ctx, resumeID, bytes.NewReader(encodePNG(t, 1000, 500)))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "https://api.example.com/api/v1/photos/"), first)
+	assert.Equal(t, first, repo.info[resumeID].PhotoURL)
+	assert.Equal(t, image.Rect(0, 0, 512, 256), decodeStored(t, store, first).Bounds())
+
+	// Small photos aren't scaled up, and replace the previous one
+	second, err := svc.Upload(ctx, resumeID, bytes.NewReader(encodePNG(t, 64, 80)))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, image.Rect(0, 0, 64, 80), decodeStored(t, store, second).Bounds())
+	_, err = svc.Get(ctx, path.Base(first))
+	assert.ErrorIs(t, err, ErrPhotoNotFound)
+
+	object, err := svc.Get(ctx, path.Base(second))
+	require.NoError(t, err)
+	object.Body.Close()
+
+	require.NoError(t, svc.Remove(ctx, resumeID))
+	assert.Empty(t, repo.info[resumeID].PhotoURL)
+	_, err = svc.Get(ctx, path.Base(second))
+	assert.ErrorIs(t, err, ErrPhotoNotFound)
+	assert.ErrorIs(t, svc.Remove(ctx, resumeID), ErrPhotoNotFound)
+}
+
+func TestPhotoServiceUploadRejected(t *testing.T) {
+	ctx := context.Background()
+	resumeID := uuid.New()
+	repo := &photoResumeRepository{info: map[uuid.UUID]*domain.PersonalInfo{resumeID: {FirstName: "Ada"}}}
+	svc := NewPhotoService(repo, storage.NewMemory(), PhotoServiceConfig{MaxSourcePixels: 10_000})
+
+	_, err := svc.Upload(ctx, resumeID, strings.NewReader("not an image"))
+	assert.ErrorIs(t, err, ErrUnsupportedPhoto)
+
+	_, err = svc.Upload(ctx, resumeID, bytes.NewReader(encodePNG(t, 200, 100)))
+	assert.ErrorIs(t, err, ErrPhotoTooLarge)
+
+	_, err = svc.Upload(ctx, uuid.New(), bytes.NewReader(encodePNG(t, 10, 10)))
+	assert.ErrorIs(t, err, ErrNoPersonalInfo)
+
+	_, err = svc.Get(ctx, "../secret")
+	assert.ErrorIs(t, err, ErrPhotoNotFound)
+}
+
+func TestPhotoServiceOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 100, 50)), nil))
+	// An APP1 segment saying the photo is rotated 90° clockwise
+	exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00")
+	segment := append([]byte{0xFF, 0xE1, 0x00, byte(len(exif) + 2)}, exif...)
+	data := append(append([]byte{0xFF, 0xD8}, segment...), buf.Bytes()[2:]...)
+	assert.Equal(t, 6, jpegOrientation(data))
+	assert.Equal(t, 1, jpegOrientation(buf.Bytes()))
+
+	svc := NewPhotoService(nil, nil, PhotoServiceConfig{})
+	out, err := svc.process(data)
+	require.NoError(t, err)
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 50, 100), img.Bounds())
+
+	// Turning clockwise puts the left edge on top
+	red := color.RGBA{R: 255, A: 255}
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.SetRGBA(0, 0, red)
+	turned := orient(src, 6)
+	assert.Equal(t, image.Rect(0, 0, 1, 2), turned.Bounds())
+	assert.Equal(t, red, turned.RGBAAt(0, 0))
+	assert.Equal(t, red, orient(src, 8).RGBAAt(0, 1))
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// ErrInvalidCursor is returned for activity feed cursors the service didn't issue
+var ErrInvalidCursor = errors.New("invalid activity cursor")
+
+// ResumeActivityServiceConfig contains configuration for the resume activity service
+type ResumeActivityServiceConfig struct {
+	// DefaultPageSize is the number of items returned when no limit is given
+	DefaultPageSize int
+	// MaxPageSize caps the number of items returned in one page
+	MaxPageSize int
+}
+
+// ResumeActivityService records what happens to resumes and lists it,
+// together with their edits, as an activity feed
+type ResumeActivityService struct {
+	activityRepo domain.ResumeActivityRepository
+	eventRepo    domain.ResumeEventRepository
+	config       ResumeActivityServiceConfig
+}
+
+// NewResumeActivityService creates a new resume activity service
+func NewResumeActivityService(activityRepo domain.ResumeActivityRepository, eventRepo domain.ResumeEventRepository, config ResumeActivityServiceConfig) *ResumeActivityService {
+	// Set default values if not provided
+	if config.DefaultPageSize == 0 {
+		config.DefaultPageSize = 50
+	}
+	if config.MaxPageSize == 0 {
+		config.MaxPageSize = 200
+	}
+
+	return &ResumeActivityService{
+		activityRepo: activityRepo,
+		eventRepo:    eventRepo,
+		config:       config,
+	}
+}
+
+// ActivityPage is a page of a resume's activity feed, newest first
+type ActivityPage struct {
+	Activity []*domain.ResumeActivity `json:"activity"`
+	// NextCursor fetches the next, older page; it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Record records an activity of a resume. actorID is uuid.Nil for anonymous
+// viewers; details, such as the format of an export, may be nil.
+func (s *ResumeActivityService) Record(ctx context.Context, resumeID, actorID uuid.UUID, kind string, details any) error {
+	activity := &domain.ResumeActivity{
+		ResumeID: resumeID,
+		Kind:     kind,
+		ActorID:  actorID,
+	}
+
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to encode activity details: %w", err)
+		}
+		activity.Details = data
+	}
+
+	return s.activityRepo.AddActivity(ctx, activity)
+}
+
+// Feed returns a page of a resume's activity, merging its edits from the
+// event log with the rest of its activity. cursor is the NextCursor of the
+// previous page, empty for the newest.
+func (s *ResumeActivityService) Feed(ctx context.Context, resumeID uuid.UUID, cursor string, limit int) (*ActivityPage, error) {
+	if limit <= 0 {
+		limit = s.config.DefaultPageSize
+	}
+	if limit > s.config.MaxPageSize {
+		limit = s.config.MaxPageSize
+	}
+
+	var before time.Time
+	var beforeID uuid.UUID
+	if cursor != "" {
+		var err error
+		if before, beforeID, err = decodeActivityCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each source has at most limit items newer than the rest of the page,
+	// and one more tells whether there is a next page
+	events, err := s.eventRepo.GetEventsBefore(ctx, resumeID, before, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.activityRepo.GetActivityBefore(ctx, resumeID, before, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := make([]*domain.ResumeActivity, 0, len(events)+len(activities))
+	i, j := 0, 0
+	for i < len(events) || j < len(activities) {
+		if j == len(activities) || (i < len(events) && newerThan(events[i].CreatedAt, events[i].ID, activities[j].CreatedAt, activities[j].ID)) {
+			feed = append(feed, editActivity(events[i]))
+			i++
+		} else {
+			feed = append(feed, activities[j])
+			j++
+		}
+	}
+
+	page := &ActivityPage{Activity: feed}
+	if len(feed) > limit {
+		page.Activity = feed[:limit]
+		last := page.Activity[limit-1]
+		page.NextCursor = encodeActivityCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
+
+// editDetails describes an edit in the activity feed
+type editDetails struct {
+	Entity   string    `json:"entity"`
+	EntityID uuid.UUID `json:"entity_id,omitzero"`
+	Op       string    `json:"op"`
+	Version  int64     `json:"version"`
+}
+
+// editActivity presents an event as an edit in the activity feed. The
+// payload is left out; it can be read from the event log by version.
+func editActivity(event *domain.ResumeEvent) *domain.ResumeActivity {
+	// Encoding a struct of plain fields can't fail
+	details, _ := json.Marshal(editDetails{
+		Entity:   event.Entity,
+		EntityID: event.EntityID,
+		Op:       event.Op,
+		Version:  event.Version,
+	})
+
+	return &domain.ResumeActivity{
+		ID:        event.ID,
+		ResumeID:  event.ResumeID,
+		Kind:      domain.ActivityEdit,
+		ActorID:   event.ActorID,
+		Details:   details,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// newerThan reports whether the item created at a with ID aID comes before
+// the one created at b with ID bID in a newest first feed
+func newerThan(a time.Time, aID uuid.UUID, b time.Time, bID uuid.UUID) bool {
+	if !a.Equal(b) {
+		return a.After(b)
+	}
+	return strings.Compare(aID.String(), bID.String()) > 0
+}
+
+// encodeActivityCursor encodes the position after an item of the feed
+func encodeActivityCursor(createdAt time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()))
+}
+
+// decodeActivityCursor decodes a cursor made by encodeActivityCursor
+func decodeActivityCursor(cursor string) (time.Time, uuid.UUID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	at, id, ok := strings.Cut(string(data), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil || createdAt.IsZero() {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	activityID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	return createdAt, activityID, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activityRepository keeps activity in memory
+type activityRepository struct {
+	activities []*domain.ResumeActivity
+}
+
+func (r *activityRepository) AddActivity(ctx context.Context, activity *domain.ResumeActivity) error {
+	if activity.ID == uuid.Nil {
+		activity.ID = uuid.New()
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now().UTC()
+	}
+	r.activities = append(r.activities, activity)
+	return nil
+}
+
+func (r *activityRepository) GetActivityBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*domain.ResumeActivity, error) {
+	var page []*domain.ResumeActivity
+	for _, activity := range r.activities {
+		if activity.ResumeID == resumeID && (before.IsZero() || newerThan(before, beforeID, activity.CreatedAt, activity.ID)) {
+			page = append(page, activity)
+		}
+	}
+	slices.SortFunc(page, func(a, b *domain.ResumeActivity) int {
+		if newerThan(a.CreatedAt, a.ID, b.CreatedAt, b.ID) {
+			return -1
+		}
+		return 1
+	})
+	return page[:min(limit, len(page))], nil
+}
+
+func (r *activityRepository) DeleteActivity(ctx context.Context, resumeID uuid.UUID) error {
+	r.activities = slices.DeleteFunc(r.activities, func(a *domain.ResumeActivity) bool { return a.ResumeID == resumeID })
+	return nil
+}
+
+// feedEventRepository serves events for activity feeds
+type feedEventRepository struct {
+	domain.ResumeEventRepository
+	events []*domain.ResumeEvent
+}
+
+func (r *feedEventRepository) GetEventsBefore(ctx context.Context, resumeID uuid.UUID, before time.Time, beforeID uuid.UUID, limit int) ([]*domain.ResumeEvent, error) {
+	var page []*domain.ResumeEvent
+	for i := len(r.events) - 1; i >= 0 && len(page) < limit; i-- {
+		event := r.events[i]
+		if event.ResumeID == resumeID && (before.IsZero() || newerThan(before, beforeID, event.CreatedAt, event.ID)) {
+			page = append(page, event)
+		}
+	}
+	return page, nil
+}
+
+func TestResumeActivityFeed(t *testing.T) {
+	ctx := context.Background()
+	resumeID := uuid.New()
+	userID := uuid.New()
+	start := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	// Edits at even minutes, other activity at odd ones
+	events := &feedEventRepository{}
+	for i := range 3 {
+		events.events = append(events.events, &domain.ResumeEvent{
+			ID:        uuid.New(),
+			ResumeID:  resumeID,
+			Version:   int64(i + 1),
+			Entity:    domain.EventEntitySkill,
+			EntityID:  uuid.New(),
+			Op:        domain.EventOpCreate,
+			Payload:   json.RawMessage(`{"name":"Go"}`),
+			ActorID:   userID,
+			CreatedAt: start.Add(time.Duration(2*i) * time.Minute),
+		})
+	}
+	activities := &activityRepository{}
+	kinds := []string{domain.ActivityExport, domain.ActivityShareView, domain.ActivityPublish}
+	for i, kind := range kinds {
+		activities.activities = append(activities.activities, &domain.ResumeActivity{
+			ID:        uuid.New(),
+			ResumeID:  resumeID,
+			Kind:      kind,
+			CreatedAt: start.Add(time.Duration(2*i+1) * time.Minute),
+		})
+	}
+	svc := NewResumeActivityService(activities, events, ResumeActivityServiceConfig{})
+
+	var feed []*domain.ResumeActivity
+	var cursor string
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page, err := svc.Feed(ctx, resumeID, cursor, 2)
+		require.NoError(t, err)
+		feed = append(feed, page.Activity...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	// Newest first, alternating between activity and edits, each once
+	require.Len(t, feed, 6)
+	expected := []string{domain.ActivityPublish, domain.ActivityEdit, domain.ActivityShareView, domain.ActivityEdit, domain.ActivityExport, domain.ActivityEdit}
+	for i, activity := range feed {
+		assert.Equal(t, expected[i], activity.Kind)
+		assert.Equal(t, start.Add(time.Duration(5-i)*time.Minute), activity.CreatedAt)
+	}
+
+	// Edits describe the change without its payload
+	var details map[string]any
+	require.NoError(t, json.Unmarshal(feed[5].Details, &details))
+	assert.Equal(t, domain.EventEntitySkill, details["entity"])
+	assert.Equal(t, domain.EventOpCreate, details["op"])
+	assert.EqualValues(t, 1, details["version"])
+	assert.NotContains(t, details, "payload")
+	assert.Equal(t, userID, feed[5].ActorID)
+}
+
+func TestResumeActivityRecord(t *testing.T) {
+	ctx := context.Background()
+	resumeID := uuid.New()
+	activities := &activityRepository{}
+	svc := NewResumeActivityService(activities, &feedEventRepository{}, ResumeActivityServiceConfig{})
+
+	require.NoError(t, svc.Record(ctx, resumeID, uuid.Nil, domain.ActivityExport, map[string]string{"format": "pdf"}))
+
+	page, err := svc.Feed(ctx, resumeID, "", 0)
+	require.NoError(t, err)
+	require.Len(t, page.Activity, 1)
+	assert.Equal(t, domain.ActivityExport, page.Activity[0].Kind)
+	assert.JSONEq(t, `{"format":"pdf"}`, string(page.Activity[0].Details))
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestResumeActivityInvalidCursor(t *testing.T) {
+	svc := NewResumeActivityService(&activityRepository{}, &feedEventRepository{}, ResumeActivityServiceConfig{})
+
+	for _, cursor := range []string{"not base64!", "bm9wZQ", encodeActivityCursor(time.Time{}, uuid.New())} {
+		_, err := svc.Feed(context.Background(), uuid.New(), cursor, 10)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
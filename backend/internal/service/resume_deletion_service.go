@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// ResumeDataRepositories hold what other features store about a resume.
+// Data whose repository isn't set is left behind when the resume is deleted.
+type ResumeDataRepositories struct {
+	Shares       domain.ResumeShareRepository
+	Notes        domain.ResumeNoteRepository
+	Publications domain.ResumePublicationRepository
+	Activity     domain.ResumeActivityRepository
+	Provenance   domain.ProvenanceRepository
+	Connectors   domain.ConnectorRepository
+}
+
+// ResumeDeletionService deletes resumes with everything stored about them.
+// Resumes live in either of two tables depending on the storage mode, so the
+// tables of other features have no foreign key to cascade from and are
+// cleared here instead.
+type ResumeDeletionService struct {
+	resumeRepo domain.ResumeRepository
+	repos      ResumeDataRepositories
+	cache      cache.Cache
+	transactor domain.Transactor
+}
+
+// NewResumeDeletionService creates a new resume deletion service
+func NewResumeDeletionService(resumeRepo domain.ResumeRepository, store cache.Cache, repos ResumeDataRepositories) *ResumeDeletionService {
+	return &ResumeDeletionService{
+		resumeRepo: resumeRepo,
+		repos:      repos,
+		cache:      store,
+	}
+}
+
+// SetTransactor deletes a resume and its data in one transaction. Without
+// it, data deleted before a failure stays deleted.
+func (s *ResumeDeletionService) SetTransactor(transactor domain.Transactor) {
+	s.transactor = transactor
+}
+
+// DeleteResume deletes a resume and its data: share links, every version of
+// its note, its public page, its activity, where its imported entries came
+// from and its connectors. Its events are kept, so sync clients learn of the
+// deletion.
+// Share view counters are dropped from the cache once the deletion is
+// committed.
+func (s *ResumeDeletionService) DeleteResume(ctx context.Context, resumeID uuid.UUID) error {
+	var shareIDs []uuid.UUID
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.resumeRepo.DeleteResume(ctx, resumeID); err != nil {
+			return err
+		}
+
+		if s.repos.Shares != nil {
+			var err error
+			if shareIDs, err = s.repos.Shares.DeleteSharesByResumeID(ctx, resumeID); err != nil {
+				return err
+			}
+		}
+		if s.repos.Notes != nil {
+			if err := ignoreNotFound(s.repos.Notes.DeleteNotes(ctx, resumeID)); err != nil {
+				return err
+			}
+		}
+		if s.repos.Publications != nil {
+			if err := ignoreNotFound(s.repos.Publications.DeletePublication(ctx, resumeID)); err != nil {
+				return err
+			}
+		}
+		if s.repos.Activity != nil {
+			if err := s.repos.Activity.DeleteActivity(ctx, resumeID); err != nil {
+				return err
+			}
+		}
+		if s.repos.Provenance != nil {
+			if err := s.repos.Provenance.DeleteResumeProvenance(ctx, resumeID); err != nil {
+				return err
+			}
+		}
+		if s.repos.Connectors != nil {
+			if err := s.repos.Connectors.DeleteConnectorsByResume(ctx, resumeID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Counters are deleted one by one as they may be on different cluster
+	// nodes. A counter left behind only takes up space.
+	for _, id := range shareIDs {
+		if err := s.cache.Del(ctx, shareViewsKey(id)); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("share_id", id.String()).Msg("Failed to delete share view counter")
+		}
+	}
+	return nil
+}
+
+// ignoreNotFound drops repository.ErrNotFound, returned when a resume has no
+// data of a kind to delete
+func ignoreNotFound(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deletionResumeRepository keeps resumes in memory
+type deletionResumeRepository struct {
+	domain.ResumeRepository
+	resumes map[uuid.UUID]bool
+}
+
+func (r *deletionResumeRepository) DeleteResume(ctx context.Context, id uuid.UUID) error {
+	if !r.resumes[id] {
+		return repository.ErrNotFound
+	}
+	delete(r.resumes, id)
+	return nil
+}
+
+// deletionPublicationRepository keeps publications in memory
+type deletionPublicationRepository struct {
+	domain.ResumePublicationRepository
+	publications map[uuid.UUID]*domain.ResumePublication
+}
+
+func (r *deletionPublicationRepository) DeletePublication(ctx context.Context, resumeID uuid.UUID) error {
+	if _, ok := r.publications[resumeID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.publications, resumeID)
+	return nil
+}
+
+func TestResumeDeletionService(t *testing.T) {
+	ctx := context.Background()
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	resumeID, otherID := uuid.New(), uuid.New()
+	resumes := &deletionResumeRepository{resumes: map[uuid.UUID]bool{resumeID: true, otherID: true}}
+	shares := &shareRepository{}
+	for _, id := range []uuid.UUID{resumeID, resumeID, otherID} {
+		require.NoError(t, shares.CreateShare(ctx, &domain.ResumeShare{ResumeID: id}))
+	}
+	for _, share := range shares.shares {
+		_, err := store.Incr(ctx, shareViewsKey(share.ID), 0)
+		require.NoError(t, err)
+	}
+	deleted := slices.Clone(shares.shares[:2])
+
+	notes := &noteRepository{}
+	activity := &activityRepository{}
+	publications := &deletionPublicationRepository{publications: map[uuid.UUID]*domain.ResumePublication{}}
+	for _, id := range []uuid.UUID{resumeID, otherID} {
+		require.NoError(t, notes.CreateNote(ctx, &domain.ResumeNote{ResumeID: id, Version: 1}))
+		require.NoError(t, activity.AddActivity(ctx, &domain.ResumeActivity{ResumeID: id, Kind: domain.ActivityEdit}))
+		publications.publications[id] = &domain.ResumePublication{ResumeID: id}
+	}
+	require.NoError(t, notes.CreateNote(ctx, &domain.ResumeNote{ResumeID: resumeID, Version: 2}))
+	provenance := &provenanceRepository{provenance: map[uuid.UUID]*domain.Provenance{}}
+	connectors := &connectorRepository{connectors: map[uuid.UUID]*domain.Connector{}}
+	for _, id := range []uuid.UUID{resumeID, otherID} {
+		entityID := uuid.New()
+		provenance.provenance[entityID] = &domain.Provenance{ResumeID: id, Entity: domain.EventEntityExperience, EntityID: entityID}
+		require.NoError(t, connectors.CreateConnector(ctx, &domain.Connector{ResumeID: id, Provider: domain.ConnectorGitHub}))
+	}
+
+	svc := NewResumeDeletionService(resumes, store, ResumeDataRepositories{
+		Shares:       shares,
+		Notes:        notes,
+		Publications: publications,
+		Activity:     activity,
+		Provenance:   provenance,
+		Connectors:   connectors,
+	})
+
+	// The resume goes with its share links and their view counters, every
+	// version of its note, its public page, its activity, its provenance
+	// and its connectors
+	require.NoError(t, svc.DeleteResume(ctx, resumeID))
+	assert.NotContains(t, resumes.resumes, resumeID)
+	require.Len(t, notes.notes, 1)
+	assert.Equal(t, otherID, notes.notes[0].ResumeID)
+	assert.NotContains(t, publications.publications, resumeID)
+	assert.Contains(t, publications.publications, otherID)
+	require.Len(t, activity.activities, 1)
+	assert.Equal(t, otherID, activity.activities[0].ResumeID)
+	require.Len(t, provenance.provenance, 1)
+	for _, p := range provenance.provenance {
+		assert.Equal(t, otherID, p.ResumeID)
+	}
+	require.Len(t, connectors.connectors, 1)
+	for _, c := range connectors.connectors {
+		assert.Equal(t, otherID, c.ResumeID)
+	}
+	for _, share := range deleted {
+		_, err := store.Get(ctx, shareViewsKey(share.ID))
+		assert.ErrorIs(t, err, cache.ErrMiss)
+	}
+	require.Len(t, shares.shares, 1)
+	assert.Equal(t, otherID, shares.shares[0].ResumeID)
+	_, err = store.Get(ctx, shareViewsKey(shares.shares[0].ID))
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, svc.DeleteResume(ctx, resumeID), repository.ErrNotFound)
+
+	// Resumes without a note or public page are deleted all the same
+	delete(publications.publications, otherID)
+	require.NoError(t, notes.DeleteNotes(ctx, otherID))
+	require.NoError(t, svc.DeleteResume(ctx, otherID))
+	assert.Empty(t, shares.shares)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// ResumeEventService errors
+var (
+	ErrInvalidEvent    = errors.New("invalid resume event")
+	ErrVersionNotFound = errors.New("resume version not found")
+)
+
+// ResumeEventServiceConfig contains configuration for the resume event service
+type ResumeEventServiceConfig struct {
+	// DefaultPageSize is the number of events returned when no limit is given
+	DefaultPageSize int
+	// MaxPageSize caps the number of events returned in one page
+	MaxPageSize int
+}
+
+// ResumeEventService records resume mutations and projects resume state from them
+type ResumeEventService struct {
+	eventRepo domain.ResumeEventRepository
+	config    ResumeEventServiceConfig
+}
+
+// NewResumeEventService creates a new resume event service
+func NewResumeEventService(eventRepo domain.ResumeEventRepository, config ResumeEventServiceConfig) *ResumeEventService {
+	// Set default values if not provided
+	if config.DefaultPageSize == 0 {
+		config.DefaultPageSize = 100
+	}
+	if config.MaxPageSize == 0 {
+		config.MaxPageSize = 500
+	}
+
+	return &ResumeEventService{
+		eventRepo: eventRepo,
+		config:    config,
+	}
+}
+
+// Record appends an event for a mutation; payload is the entity state after the change
+func (s *ResumeEventService) Record(ctx context.Context, resumeID, actorID uuid.UUID, entity string, entityID uuid.UUID, op string, payload any) (*domain.ResumeEvent, error) {
+	event := &domain.ResumeEvent{
+		ResumeID: resumeID,
+		Entity:   entity,
+		EntityID: entityID,
+		Op:       op,
+		ActorID:  actorID,
+	}
+
+	if op != domain.EventOpDelete && payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event payload: %w", err)
+		}
+		event.Payload = data
+	}
+
+	if err := s.eventRepo.AppendEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// EventsAfter returns a page of events newer than afterVersion, for delta sync and audit
+func (s *ResumeEventService) EventsAfter(ctx context.Context, resumeID uuid.UUID, afterVersion int64, limit int) ([]*domain.ResumeEvent, error) {
+	if limit <= 0 {
+		limit = s.config.DefaultPageSize
+	}
+	if limit > s.config.MaxPageSize {
+		limit = s.config.MaxPageSize
+	}
+
+	return s.eventRepo.GetEventsAfter(ctx, resumeID, afterVersion, limit)
+}
+
+// StateAt rebuilds the resume as it was at the given version
+func (s *ResumeEventService) StateAt(ctx context.Context, resumeID uuid.UUID, version int64) (*domain.Resume, error) {
+	events, err := s.eventRepo.GetEventsUpTo(ctx, resumeID, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 || events[len(events)-1].Version != version {
+		return nil, ErrVersionNotFound
+	}
+
+	return ProjectResume(events)
+}
+
+// ProjectResume replays events in version order into the resume state they describe.
+// It returns nil if the events end with the resume being deleted.
+func ProjectResume(events []*domain.ResumeEvent) (*domain.Resume, error) {
+	p := newResumeProjection()
+	for _, event := range events {
+		if err := p.apply(event); err != nil {
+			return nil, fmt.Errorf("version %d: %w", event.Version, err)
+		}
+	}
+
+	return p.resume(), nil
+}
+
+// resumeProjection accumulates section entries keyed by ID in insertion order
+type resumeProjection struct {
+	base           *domain.Resume
+	personalInfo   *domain.PersonalInfo
+	education      *projectedSection[domain.Education]
+	experience     *projectedSection[domain.Experience]
+	skills         *projectedSection[domain.Skill]
+	projects       *projectedSection[domain.Project]
+	certifications *projectedSection[domain.Certification]
+}
+
+func newResumeProjection() *resumeProjection {
+	return &resumeProjection{
+		education:      newProjectedSection[domain.Education](),
+		experience:     newProjectedSection[domain.Experience](),
+		skills:         newProjectedSection[domain.Skill](),
+		projects:       newProjectedSection[domain.Project](),
+		certifications: newProjectedSection[domain.Certification](),
+	}
+}
+
+// apply folds a single event into the projection
+func (p *resumeProjection) apply(event *domain.ResumeEvent) error {
+	switch event.Entity {
+	case domain.EventEntityResume:
+		if event.Op == domain.EventOpDelete {
+			p.base = nil
+			return nil
+		}
+		var resume domain.Resume
+		if err := json.Unmarshal(event.Payload, &resume); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		resume.UpdatedAt = event.CreatedAt
+		p.base = &resume
+		return nil
+	case domain.EventEntityPersonalInfo:
+		if event.Op == domain.EventOpDelete {
+			p.personalInfo = nil
+			return nil
+		}
+		var info domain.PersonalInfo
+		if err := json.Unmarshal(event.Payload, &info); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		p.personalInfo = &info
+	case domain.EventEntityEducation:
+		if err := p.education.apply(event); err != nil {
+			return err
+		}
+	case domain.EventEntityExperience:
+		if err := p.experience.apply(event); err != nil {
+			return err
+		}
+	case domain.EventEntitySkill:
+		if err := p.skills.apply(event); err != nil {
+			return err
+		}
+	case domain.EventEntityProject:
+		if err := p.projects.apply(event); err != nil {
+			return err
+		}
+	case domain.EventEntityCertification:
+		if err := p.certifications.apply(event); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown entity %q", ErrInvalidEvent, event.Entity)
+	}
+
+	if p.base != nil {
+		p.base.UpdatedAt = event.CreatedAt
+	}
+	return nil
+}
+
+// resume returns the projected resume
+func (p *resumeProjection) resume() *domain.Resume {
+	if p.base == nil {
+		return nil
+	}
+
+	resume := *p.base
+	resume.PersonalInfo = p.personalInfo
+	resume.Education = p.education.list()
+	resume.Experience = p.experience.list()
+	resume.Skills = p.skills.list()
+	resume.Projects = p.projects.list()
+	resume.Certifications = p.certifications.list()
+	return &resume
+}
+
+// projectedSection holds the entries of one resume section
+type projectedSection[T any] struct {
+	order   []uuid.UUID
+	entries map[uuid.UUID]*T
+}
+
+func newProjectedSection[T any]() *projectedSection[T] {
+	return &projectedSection[T]{entries: make(map[uuid.UUID]*T)}
+}
+
+// apply folds a section event into the entries
+func (s *projectedSection[T]) apply(event *domain.ResumeEvent) error {
+	if event.EntityID == uuid.Nil {
+		return fmt.Errorf("%w: missing entity ID", ErrInvalidEvent)
+	}
+
+	switch event.Op {
+	case domain.EventOpDelete:
+		delete(s.entries, event.EntityID)
+		return nil
+	case domain.EventOpCreate, domain.EventOpUpdate:
+		var entry T
+		if err := json.Unmarshal(event.Payload, &entry); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		if _, ok := s.entries[event.EntityID]; !ok {
+			s.order = append(s.order, event.EntityID)
+		}
+		s.entries[event.EntityID] = &entry
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown op %q", ErrInvalidEvent, event.Op)
+	}
+}
+
+// list returns the live entries in the order they were created
+func (s *projectedSection[T]) list() []*T {
+	list := make([]*T, 0, len(s.entries))
+	for _, id := range s.order {
+		if entry, ok := s.entries[id]; ok {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEvent builds an event with the given version and JSON payload
+func newEvent(t *testing.T, version int64, entity string, entityID uuid.UUID, op string, payload any) *domain.ResumeEvent {
+	event := &domain.ResumeEvent{
+		Version:   version,
+		Entity:    entity,
+		EntityID:  entityID,
+		Op:        op,
+		CreatedAt: time.Unix(version, 0).UTC(),
+	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		require.NoError(t, err)
+		event.Payload = data
+	}
+	return event
+}
+
+func TestProjectResume(t *testing.T) {
+	resumeID := uuid.New()
+	first := uuid.New()
+	second := uuid.New()
+
+	events := []*domain.ResumeEvent{
+		newEvent(t, 1, domain.EventEntityResume, uuid.Nil, domain.EventOpCreate, &domain.Resume{ID: resumeID}),
+		newEvent(t, 2, domain.EventEntitySkill, first, domain.EventOpCreate, &domain.Skill{Name: "Go"}),
+		newEvent(t, 3, domain.EventEntitySkill, second, domain.EventOpCreate, &domain.Skill{Name: "SQL"}),
+		newEvent(t, 4, domain.EventEntitySkill, first, domain.EventOpUpdate, &domain.Skill{Name: "Golang"}),
+		newEvent(t, 5, domain.EventEntitySkill, second, domain.EventOpDelete, nil),
+	}
+
+	resume, err := ProjectResume(events)
+	require.NoError(t, err)
+	require.NotNil(t, resume)
+	assert.Equal(t, resumeID, resume.ID)
+	assert.Equal(t, time.Unix(5, 0).UTC(), resume.UpdatedAt)
+	require.Len(t, resume.Skills, 1)
+	assert.Equal(t, "Golang", resume.Skills[0].Name)
+
+	// Replaying a prefix yields the earlier state
+	earlier, err := ProjectResume(events[:3])
+	require.NoError(t, err)
+	assert.Len(t, earlier.Skills, 2)
+}
+
+func TestProjectResumeDeleted(t *testing.T) {
+	events := []*domain.ResumeEvent{
+		newEvent(t, 1, domain.EventEntityResume, uuid.Nil, domain.EventOpCreate, &domain.Resume{ID: uuid.New()}),
+		newEvent(t, 2, domain.EventEntityResume, uuid.Nil, domain.EventOpDelete, nil),
+	}
+
+	resume, err := ProjectResume(events)
+	require.NoError(t, err)
+	assert.Nil(t, resume)
+}
+
+func TestProjectResumeInvalidEvent(t *testing.T) {
+	events := []*domain.ResumeEvent{
+		newEvent(t, 1, domain.EventEntitySkill, uuid.Nil, domain.EventOpCreate, &domain.Skill{Name: "Go"}),
+	}
+
+	_, err := ProjectResume(events)
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+}
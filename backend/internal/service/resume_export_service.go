@@ -0,0 +1,209 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/render"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// resumeExportPrefix is the storage folder rendered resume exports are kept in
+const resumeExportPrefix = "exports/resumes/"
+
+// resumeExportRenderVersion is part of every export's version, so exports
+// rendered before a change to the renderers aren't served after it
+const resumeExportRenderVersion = "1"
+
+// ResumeExportServiceConfig contains configuration for the resume export service
+type ResumeExportServiceConfig struct {
+	// URLExpiry is how long download URLs of exports stay valid
+	URLExpiry time.Duration
+}
+
+// ExportURL is a time-limited download URL of a rendered export
+type ExportURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Condensed is how a one-page export was condensed
+	Condensed *render.Condensed `json:"condensed,omitempty"`
+}
+
+// ResumeExportService keeps rendered resume exports in file storage, keyed
+// by a hash of the resume's content, and hands out signed URLs to download
+// them. A resume is only rendered again once it has changed; the export of
+// the version it replaces is deleted then.
+type ResumeExportService struct {
+	files  storage.Storage
+	cache  cache.Cache
+	config ResumeExportServiceConfig
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// NewResumeExportService creates a new resume export service
+func NewResumeExportService(files storage.Storage, store cache.Cache, config ResumeExportServiceConfig) *ResumeExportService {
+	// Set default values if not provided
+	if config.URLExpiry == 0 {
+		config.URLExpiry = 15 * time.Minute
+	}
+
+	return &ResumeExportService{
+		files:  files,
+		cache:  store,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// PDFURL returns a download URL of a complete resume as PDF
+func (s *ResumeExportService) PDFURL(ctx context.Context, resume *domain.Resume) (*ExportURL, error) {
+	version, err := resumeVersion(resume)
+	if err != nil {
+		return nil, err
+	}
+	return s.exportURL(ctx, resume, version, "pdf", "application/pdf", render.PDF)
+}
+
+// OnePagePDFURL returns a download URL of a complete resume condensed to
+// one page as PDF, with how it was condensed. Like the PDF, how a version
+// is condensed is only worked out once and kept with its export.
+func (s *ResumeExportService) OnePagePDFURL(ctx context.Context, resume *domain.Resume) (*ExportURL, error) {
+	version, err := resumeVersion(resume)
+	if err != nil {
+		return nil, err
+	}
+	condensed := s.condensed(ctx, resume, resumeExportKey(resume.ID, version, "one-page.pdf"))
+
+	exportURL, err := s.exportURL(ctx, resume, version, "one-page.pdf", "application/pdf", condensed.PDF)
+	if err != nil {
+		return nil, err
+	}
+	exportURL.Condensed = condensed
+	return exportURL, nil
+}
+
+// condensed returns how the resume version stored at key is condensed,
+// working it out if it isn't kept yet
+func (s *ResumeExportService) condensed(ctx context.Context, resume *domain.Resume, key string) *render.Condensed {
+	cacheKey := resumeExportCondensedKey(key)
+	data, err := s.cache.Get(ctx, cacheKey)
+	if err == nil {
+		var condensed render.Condensed
+		if err := json.Unmarshal(data, &condensed); err == nil {
+			return &condensed
+		}
+	} else if !errors.Is(err, cache.ErrMiss) {
+		log.Ctx(ctx).Warn().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to read condensed resume export")
+	}
+
+	condensed := render.CondensePDF(resume)
+	if data, err := json.Marshal(condensed); err == nil {
+		if err := s.cache.Set(ctx, cacheKey, data, 0); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to keep condensed resume export")
+		}
+	}
+	return condensed
+}
+
+// DeleteExports deletes the stored exports of a resume
+func (s *ResumeExportService) DeleteExports(ctx context.Context, resumeID uuid.UUID) {
+	for _, format := range []string{"pdf", "one-page.pdf"} {
+		s.replaceExport(ctx, resumeID, format, "")
+	}
+}
+
+// exportURL stores the resume rendered in a format, unless this version of
+// it is stored already, and signs a URL of it
+func (s *ResumeExportService) exportURL(ctx context.Context, resume *domain.Resume, version, format, contentType string, renderFn func(*domain.Resume) []byte) (*ExportURL, error) {
+	key := resumeExportKey(resume.ID, version, format)
+
+	object, err := s.files.Get(ctx, key)
+	switch {
+	case err == nil:
+		object.Body.Close()
+	case errors.Is(err, storage.ErrNotFound):
+		if err := s.files.Put(ctx, key, bytes.NewReader(renderFn(resume)), contentType); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to store resume export")
+			return nil, err
+		}
+		s.replaceExport(ctx, resume.ID, format, key)
+	default:
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to look up resume export")
+		return nil, err
+	}
+
+	expiresAt := s.now().Add(s.config.URLExpiry).UTC().Truncate(time.Second)
+	url, err := s.files.SignedURL(ctx, key, s.config.URLExpiry)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resume_id", resume.ID.String()).Msg("Failed to sign resume export URL")
+		return nil, err
+	}
+	return &ExportURL{URL: url, ExpiresAt: expiresAt}, nil
+}
+
+// replaceExport records key as the stored export of a resume in a format,
+// deleting the one it replaces and what is kept with it. An empty key only
+// deletes. Failures leave an unused file behind, so they are logged rather
+// than returned.
+func (s *ResumeExportService) replaceExport(ctx context.Context, resumeID uuid.UUID, format, key string) {
+	current := resumeExportCurrentKey(resumeID, format)
+	previous, err := s.cache.Get(ctx, current)
+	if err != nil && !errors.Is(err, cache.ErrMiss) {
+		log.Ctx(ctx).Warn().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to read current resume export")
+	}
+	if len(previous) > 0 && string(previous) != key {
+		if err := s.files.Delete(ctx, string(previous)); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("key", string(previous)).Msg("Failed to delete replaced resume export")
+		}
+		if err := s.cache.Del(ctx, resumeExportCondensedKey(string(previous))); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("key", string(previous)).Msg("Failed to delete condensed resume export")
+		}
+	}
+
+	if key == "" {
+		err = s.cache.Del(ctx, current)
+	} else {
+		err = s.cache.Set(ctx, current, []byte(key), 0)
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("resume_id", resumeID.String()).Msg("Failed to record current resume export")
+	}
+}
+
+// resumeVersion hashes everything an export of a resume is rendered from
+func resumeVersion(resume *domain.Resume) (string, error) {
+	data, err := json.Marshal(resume)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(resumeExportRenderVersion+"\n"), data...))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// resumeExportKey returns the storage key of an export of a resume version.
+// The file name is the one the download is saved as.
+func resumeExportKey(resumeID uuid.UUID, version, format string) string {
+	return resumeExportPrefix + resumeID.String() + "/" + version + "/resume-" + resumeID.String() + "." + format
+}
+
+// resumeExportCurrentKey returns the cache key recording the stored export
+// of a resume in a format
+func resumeExportCurrentKey(resumeID uuid.UUID, format string) string {
+	return "resume_export:" + format + ":" + resumeID.String()
+}
+
+// resumeExportCondensedKey returns the cache key keeping how the export
+// stored at key was condensed
+func resumeExportCondensedKey(key string) string {
+	return "resume_export:condensed:" + key
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/render"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeExportPDFURL(t *testing.T) {
+	ctx := context.Background()
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	files := storage.NewMemory()
+	signer := storage.NewURLSigner("/api/v1/files", []byte("secret"))
+	files.SetURLSigner(signer)
+	svc := NewResumeExportService(files, store, ResumeExportServiceConfig{URLExpiry: time.Minute})
+	now := time.Date(2025, 4, 11, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	resume := &domain.Resume{ID: uuid.New(), ResumeMetadata: domain.ResumeMetadata{Title: "Backend", Language: "en"}}
+	keyOf := func(exportURL *ExportURL) string {
+		key, _, _ := strings.Cut(strings.TrimPrefix(exportURL.URL, "/api/v1/files/"), "?")
+		return key
+	}
+
+	first, err := svc.PDFURL(ctx, resume)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), first.ExpiresAt)
+	key := keyOf(first)
+	assert.True(t, strings.HasPrefix(key, "exports/resumes/"+resume.ID.String()+"/"))
+	assert.True(t, strings.HasSuffix(key, "/resume-"+resume.ID.String()+".pdf"))
+	object, err := files.Get(ctx, key)
+	require.NoError(t, err)
+	object.Body.Close()
+	assert.Equal(t, "application/pdf", object.ContentType)
+
+	// An unchanged resume is served from storage, not rendered again
+	require.NoError(t, files.Put(ctx, key, strings.NewReader("%PDF-stored"), "application/pdf"))
+	second, err := svc.PDFURL(ctx, resume)
+	require.NoError(t, err)
+	assert.Equal(t, key, keyOf(second))
+	object, err = files.Get(ctx, key)
+	require.NoError(t, err)
+	object.Body.Close()
+	assert.Equal(t, int64(len("%PDF-stored")), object.Size)
+
+	// A changed resume gets a new export, replacing the old one
+	resume.Title = "Platform"
+	changed, err := svc.PDFURL(ctx, resume)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, keyOf(changed))
+	_, err = files.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	svc.DeleteExports(ctx, resume.ID)
+	_, err = files.Get(ctx, keyOf(changed))
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestResumeExportOnePagePDFURL(t *testing.T) {
+	ctx := context.Background()
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	files := storage.NewMemory()
+	files.SetURLSigner(storage.NewURLSigner("/api/v1/files", []byte("secret")))
+	svc := NewResumeExportService(files, store, ResumeExportServiceConfig{URLExpiry: time.Minute})
+
+	resume := &domain.Resume{ID: uuid.New(), ResumeMetadata: domain.ResumeMetadata{Title: "Backend", Language: "en"}}
+	first, err := svc.OnePagePDFURL(ctx, resume)
+	require.NoError(t, err)
+	require.NotNil(t, first.Condensed)
+	assert.True(t, first.Condensed.Fits)
+
+	// An unchanged resume reports how its export was condensed without
+	// condensing it again
+	version, err := resumeVersion(resume)
+	require.NoError(t, err)
+	key := resumeExportKey(resume.ID, version, "one-page.pdf")
+	kept := &render.Condensed{Fits: true, Margin: 36, FontScale: 0.85, Omitted: []render.OmittedEntry{}}
+	data, err := json.Marshal(kept)
+	require.NoError(t, err)
+	require.NoError(t, store.Set(ctx, resumeExportCondensedKey(key), data, 0))
+	second, err := svc.OnePagePDFURL(ctx, resume)
+	require.NoError(t, err)
+	assert.Equal(t, kept, second.Condensed)
+
+	// A changed resume is condensed again, and the old version's is dropped
+	resume.Title = "Platform"
+	changed, err := svc.OnePagePDFURL(ctx, resume)
+	require.NoError(t, err)
+	assert.Equal(t, first.Condensed, changed.Condensed)
+	_, err = store.Get(ctx, resumeExportCondensedKey(key))
+	assert.ErrorIs(t, err, cache.ErrMiss)
+
+	svc.DeleteExports(ctx, resume.ID)
+	version, err = resumeVersion(resume)
+	require.NoError(t, err)
+	_, err = store.Get(ctx, resumeExportCondensedKey(resumeExportKey(resume.ID, version, "one-page.pdf")))
+	assert.ErrorIs(t, err, cache.ErrMiss)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+)
+
+// ErrInvalidFeedCursor is returned for polling feed cursors the service didn't issue
+var ErrInvalidFeedCursor = errors.New("invalid feed cursor")
+
+// ResumeFeedServiceConfig contains configuration for the resume feed service
+type ResumeFeedServiceConfig struct {
+	// DefaultPageSize is the number of items returned when no limit is given
+	DefaultPageSize int
+	// MaxPageSize caps the number of items returned in one page
+	MaxPageSize int
+	// SettleDelay holds back events this recent. Events are numbered when
+	// recorded but become visible when their transaction commits, so a
+	// younger event could still be followed by one numbered before it.
+	SettleDelay time.Duration
+}
+
+// ResumeFeedService lists the changes to a user's resumes for clients that
+// poll for them, such as no-code automation platforms. Feeds are read oldest
+// first from a cursor that only moves forward, so a client that keeps the
+// last cursor it got sees every change once.
+type ResumeFeedService struct {
+	eventRepo  domain.ResumeEventRepository
+	resumeRepo domain.ResumeRepository
+	config     ResumeFeedServiceConfig
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// NewResumeFeedService creates a new resume feed service
+func NewResumeFeedService(eventRepo domain.ResumeEventRepository, resumeRepo domain.ResumeRepository, config ResumeFeedServiceConfig) *ResumeFeedService {
+	// Set default values if not provided
+	if config.DefaultPageSize <= 0 {
+		config.DefaultPageSize = 50
+	}
+	if config.MaxPageSize <= 0 {
+		config.MaxPageSize = 100
+	}
+	if config.SettleDelay <= 0 {
+		config.SettleDelay = 5 * time.Second
+	}
+
+	return &ResumeFeedService{
+		eventRepo:  eventRepo,
+		resumeRepo: resumeRepo,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+// ChangedResume is a resume that changed. Its ID is unique to the change, so
+// platforms that deduplicate items by ID see each change of a resume.
+type ChangedResume struct {
+	ID        string    `json:"id"`
+	ResumeID  uuid.UUID `json:"resume_id"`
+	ChangedAt time.Time `json:"changed_at"`
+	// Deleted is set for resumes deleted since, which have no Resume
+	Deleted bool           `json:"deleted"`
+	Resume  *domain.Resume `json:"resume,omitempty"`
+}
+
+// ChangedResumesPage is a page of changed resumes, oldest change first
+type ChangedResumesPage struct {
+	Data []*ChangedResume `json:"data"`
+	// NextCursor continues after this page; it is only empty when nothing
+	// has changed since the time polled from
+	NextCursor string `json:"next_cursor,omitempty"`
+	// HasMore is set when more changes can be fetched right away
+	HasMore bool `json:"has_more"`
+}
+
+// ResumeEventsPage is a page of events across a user's resumes, oldest first
+type ResumeEventsPage struct {
+	Data       []*domain.ResumeEvent `json:"data"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	HasMore    bool                  `json:"has_more"`
+}
+
+// ChangedResumes returns the user's resumes that changed after since, or
+// after the cursor of a previous page when one is given, each with its
+// current state. A resume that keeps changing is listed again with its
+// latest change.
+func (s *ResumeFeedService) ChangedResumes(ctx context.Context, userID uuid.UUID, since time.Time, cursor string, limit int) (*ChangedResumesPage, error) {
+	afterSeq, err := decodeFeedCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit = s.pageSize(limit)
+
+	changes, err := s.eventRepo.GetOwnerChangedResumes(ctx, userID, afterSeq, since, s.until(), limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ChangedResumesPage{Data: make([]*ChangedResume, 0, len(changes)), NextCursor: cursor}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		page.HasMore = true
+	}
+	for _, change := range changes {
+		item := &ChangedResume{
+			ID:        fmt.Sprintf("%s-%d", change.ResumeID, change.Seq),
+			ResumeID:  change.ResumeID,
+			ChangedAt: change.ChangedAt,
+		}
+		resume, err := s.resumeRepo.GetResumeByID(ctx, change.ResumeID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			item.Deleted = true
+		case err != nil:
+			return nil, err
+		default:
+			item.Resume = resume
+		}
+		page.Data = append(page.Data, item)
+		page.NextCursor = encodeFeedCursor(change.Seq)
+	}
+	return page, nil
+}
+
+// Events returns the events of all the user's resumes recorded after since,
+// or after the cursor of a previous page when one is given
+func (s *ResumeFeedService) Events(ctx context.Context, userID uuid.UUID, since time.Time, cursor string, limit int) (*ResumeEventsPage, error) {
+	afterSeq, err := decodeFeedCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit = s.pageSize(limit)
+
+	events, err := s.eventRepo.GetOwnerEventsAfter(ctx, userID, afterSeq, since, s.until(), limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ResumeEventsPage{Data: events, NextCursor: cursor}
+	if len(events) > limit {
+		page.Data = events[:limit]
+		page.HasMore = true
+	}
+	if len(page.Data) > 0 {
+		page.NextCursor = encodeFeedCursor(page.Data[len(page.Data)-1].Seq)
+	}
+	return page, nil
+}
+
+// pageSize bounds a requested page size
+func (s *ResumeFeedService) pageSize(limit int) int {
+	if limit <= 0 {
+		return s.config.DefaultPageSize
+	}
+	return min(limit, s.config.MaxPageSize)
+}
+
+// until is the time of the newest events that have settled
+func (s *ResumeFeedService) until() time.Time {
+	return s.now().Add(-s.config.SettleDelay)
+}
+
+// encodeFeedCursor encodes the position after the event numbered seq
+func encodeFeedCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+// decodeFeedCursor decodes a cursor made by encodeFeedCursor; the empty
+// cursor starts from the beginning
+func decodeFeedCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || seq < 1 {
+		return 0, ErrInvalidFeedCursor
+	}
+	return seq, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ownerEventRepository keeps the events of one owner's resumes in the order
+// they were recorded
+type ownerEventRepository struct {
+	domain.ResumeEventRepository
+	events []*domain.ResumeEvent
+}
+
+func (r *ownerEventRepository) record(resumeID uuid.UUID, op string, at time.Time) {
+	r.events = append(r.events, &domain.ResumeEvent{
+		ID:        uuid.New(),
+		ResumeID:  resumeID,
+		Entity:    domain.EventEntityResume,
+		Op:        op,
+		CreatedAt: at,
+		Seq:       int64(len(r.events) + 1),
+	})
+}
+
+func (r *ownerEventRepository) inRange(afterSeq int64, since, until time.Time) []*domain.ResumeEvent {
+	var events []*domain.ResumeEvent
+	for _, event := range r.events {
+		if event.Seq > afterSeq && event.CreatedAt.After(since) && !event.CreatedAt.After(until) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (r *ownerEventRepository) GetOwnerEventsAfter(ctx context.Context, ownerID uuid.UUID, afterSeq int64, since, until time.Time, limit int) ([]*domain.ResumeEvent, error) {
+	events := r.inRange(afterSeq, since, until)
+	return events[:min(limit, len(events))], nil
+}
+
+func (r *ownerEventRepository) GetOwnerChangedResumes(ctx context.Context, ownerID uuid.UUID, afterSeq int64, since, until time.Time, limit int) ([]*domain.ResumeChange, error) {
+	var changes []*domain.ResumeChange
+	latest := map[uuid.UUID]*domain.ResumeChange{}
+	for _, event := range r.inRange(afterSeq, since, until) {
+		if change, ok := latest[event.ResumeID]; ok {
+			change.Seq, change.ChangedAt = event.Seq, event.CreatedAt
+			continue
+		}
+		latest[event.ResumeID] = &domain.ResumeChange{ResumeID: event.ResumeID, Seq: event.Seq, ChangedAt: event.CreatedAt}
+		changes = append(changes, latest[event.ResumeID])
+	}
+	// Ordered by the latest event of each resume
+	slices.SortFunc(changes, func(a, b *domain.ResumeChange) int { return cmp.Compare(a.Seq, b.Seq) })
+	return changes[:min(limit, len(changes))], nil
+}
+
+// feedResumeRepository finds the resumes that still exist
+type feedResumeRepository struct {
+	domain.ResumeRepository
+	resumes map[uuid.UUID]*domain.Resume
+}
+
+func (r *feedResumeRepository) GetResumeByID(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	resume, ok := r.resumes[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return resume, nil
+}
+
+func TestResumeFeedChangedResumes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	kept := &domain.Resume{ID: uuid.New()}
+	deleted := uuid.New()
+	events := &ownerEventRepository{}
+	events.record(kept.ID, domain.EventOpCreate, now.Add(-3*time.Minute))
+	events.record(deleted, domain.EventOpCreate, now.Add(-2*time.Minute))
+	events.record(kept.ID, domain.EventOpUpdate, now.Add(-time.Minute))
+	events.record(deleted, domain.EventOpDelete, now.Add(-30*time.Second))
+	// Too recent to have settled
+	events.record(kept.ID, domain.EventOpUpdate, now.Add(-time.Second))
+
+	svc := NewResumeFeedService(events, &feedResumeRepository{resumes: map[uuid.UUID]*domain.Resume{kept.ID: kept}}, ResumeFeedServiceConfig{})
+	svc.now = func() time.Time { return now }
+
+	page, err := svc.ChangedResumes(ctx, uuid.New(), now.Add(-time.Hour), "", 1)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, kept.ID, page.Data[0].ResumeID)
+	assert.Equal(t, kept, page.Data[0].Resume)
+	assert.Equal(t, now.Add(-time.Minute), page.Data[0].ChangedAt)
+	assert.True(t, page.HasMore)
+
+	page, err = svc.ChangedResumes(ctx, uuid.New(), time.Time{}, page.NextCursor, 1)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, deleted, page.Data[0].ResumeID)
+	assert.True(t, page.Data[0].Deleted)
+	assert.Nil(t, page.Data[0].Resume)
+	assert.False(t, page.HasMore)
+
+	// Nothing new keeps the cursor where it was
+	cursor := page.NextCursor
+	page, err = svc.ChangedResumes(ctx, uuid.New(), time.Time{}, cursor, 1)
+	require.NoError(t, err)
+	assert.Empty(t, page.Data)
+	assert.Equal(t, cursor, page.NextCursor)
+
+	// The held back change shows up once it settles, under a new ID
+	svc.now = func() time.Time { return now.Add(time.Minute) }
+	page, err = svc.ChangedResumes(ctx, uuid.New(), time.Time{}, cursor, 10)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, kept.ID, page.Data[0].ResumeID)
+	assert.Equal(t, kept.ID.String()+"-5", page.Data[0].ID)
+}
+
+func TestResumeFeedEvents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	resumeID := uuid.New()
+	events := &ownerEventRepository{}
+	events.record(resumeID, domain.EventOpCreate, now.Add(-3*time.Minute))
+	events.record(resumeID, domain.EventOpUpdate, now.Add(-2*time.Minute))
+	events.record(resumeID, domain.EventOpDelete, now.Add(-time.Minute))
+
+	svc := NewResumeFeedService(events, &feedResumeRepository{}, ResumeFeedServiceConfig{})
+	svc.now = func() time.Time { return now }
+
+	page, err := svc.Events(ctx, uuid.New(), now.Add(-150*time.Second), "", 0)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 2)
+	assert.Equal(t, domain.EventOpUpdate, page.Data[0].Op)
+	assert.Equal(t, domain.EventOpDelete, page.Data[1].Op)
+	assert.Equal(t, "3", page.NextCursor)
+	assert.False(t, page.HasMore)
+
+	for _, cursor := range []string{"abc", "0", "-4"} {
+		_, err = svc.Events(ctx, uuid.New(), time.Time{}, cursor, 0)
+		assert.ErrorIs(t, err, ErrInvalidFeedCursor, cursor)
+	}
+}
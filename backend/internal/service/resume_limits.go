@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// ResumeLimitsConfig caps the number of entries a resume may hold per section
+type ResumeLimitsConfig struct {
+	MaxEducation      int
+	MaxExperience     int
+	MaxSkills         int
+	MaxProjects       int
+	MaxCertifications int
+	// MaxProjectTechnologies caps the technologies listed on a single project
+	MaxProjectTechnologies int
+}
+
+// LimitedResumeRepository enforces per-section entry caps in front of a resume
+// repository, protecting rendering and storage from pathological resumes.
+// Adds that would exceed a cap fail with a *domain.LimitExceededError.
+type LimitedResumeRepository struct {
+	domain.ResumeRepository
+	config ResumeLimitsConfig
+}
+
+// NewLimitedResumeRepository wraps a resume repository with entry caps
+func NewLimitedResumeRepository(repo domain.ResumeRepository, config ResumeLimitsConfig) *LimitedResumeRepository {
+	// Set default values if not provided
+	if config.MaxEducation == 0 {
+		config.MaxEducation = 20
+	}
+	if config.MaxExperience == 0 {
+		config.MaxExperience = 50
+	}
+	if config.MaxSkills == 0 {
+		config.MaxSkills = 200
+	}
+	if config.MaxProjects == 0 {
+		config.MaxProjects = 50
+	}
+	if config.MaxCertifications == 0 {
+		config.MaxCertifications = 50
+	}
+	if config.MaxProjectTechnologies == 0 {
+		config.MaxProjectTechnologies = 30
+	}
+
+	return &LimitedResumeRepository{
+		ResumeRepository: repo,
+		config:           config,
+	}
+}
+
+// checkCapacity fails when a section already holds limit entries
+func checkCapacity(section string, count, limit int) error {
+	if count >= limit {
+		return &domain.LimitExceededError{Section: section, Limit: limit}
+	}
+	return nil
+}
+
+// checkTechnologies fails when a project lists more technologies than allowed
+func (r *LimitedResumeRepository) checkTechnologies(project *domain.Project) error {
+	if len(project.Technologies) > r.config.MaxProjectTechnologies {
+		return &domain.LimitExceededError{Section: "project technologies", Limit: r.config.MaxProjectTechnologies}
+	}
+	return nil
+}
+
+// AddEducation adds an education entry unless the section is full
+func (r *LimitedResumeRepository) AddEducation(ctx context.Context, resumeID uuid.UUID, education *domain.Education) (uuid.UUID, error) {
+	entries, err := r.ResumeRepository.GetEducationByResume(ctx, resumeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkCapacity("education", len(entries), r.config.MaxEducation); err != nil {
+		return uuid.Nil, err
+	}
+
+	return r.ResumeRepository.AddEducation(ctx, resumeID, education)
+}
+
+// AddExperience adds an experience entry unless the section is full
+func (r *LimitedResumeRepository) AddExperience(ctx context.Context, resumeID uuid.UUID, experience *domain.Experience) (uuid.UUID, error) {
+	entries, err := r.ResumeRepository.GetExperienceByResume(ctx, resumeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkCapacity("experience", len(entries), r.config.MaxExperience); err != nil {
+		return uuid.Nil, err
+	}
+
+	return r.ResumeRepository.AddExperience(ctx, resumeID, experience)
+}
+
+// AddSkill adds a skill entry unless the section is full
+func (r *LimitedResumeRepository) AddSkill(ctx context.Context, resumeID uuid.UUID, skill *domain.Skill) (uuid.UUID, error) {
+	entries, err := r.ResumeRepository.GetSkillsByResume(ctx, resumeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkCapacity("skills", len(entries), r.config.MaxSkills); err != nil {
+		return uuid.Nil, err
+	}
+
+	return r.ResumeRepository.AddSkill(ctx, resumeID, skill)
+}
+
+// AddSkills adds several skill entries unless they don't all fit in the section
+func (r *LimitedResumeRepository) AddSkills(ctx context.Context, resumeID uuid.UUID, skills []*domain.Skill) ([]uuid.UUID, error) {
+	entries, err := r.ResumeRepository.GetSkillsByResume(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries)+len(skills) > r.config.MaxSkills {
+		return nil, &domain.LimitExceededError{Section: "skills", Limit: r.config.MaxSkills}
+	}
+
+	return r.ResumeRepository.AddSkills(ctx, resumeID, skills)
+}
+
+// AddProject adds a project entry unless the section is full or it lists too many technologies
+func (r *LimitedResumeRepository) AddProject(ctx context.Context, resumeID uuid.UUID, project *domain.Project) (uuid.UUID, error) {
+	if err := r.checkTechnologies(project); err != nil {
+		return uuid.Nil, err
+	}
+
+	entries, err := r.ResumeRepository.GetProjectsByResume(ctx, resumeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkCapacity("projects", len(entries), r.config.MaxProjects); err != nil {
+		return uuid.Nil, err
+	}
+
+	return r.ResumeRepository.AddProject(ctx, resumeID, project)
+}
+
+// UpdateProject updates a project entry unless it lists too many technologies
+func (r *LimitedResumeRepository) UpdateProject(ctx context.Context, resumeID, id uuid.UUID, project *domain.Project) error {
+	if err := r.checkTechnologies(project); err != nil {
+		return err
+	}
+
+	return r.ResumeRepository.UpdateProject(ctx, resumeID, id, project)
+}
+
+// AddProjectTechnology adds a technology unless the project already lists the maximum
+func (r *LimitedResumeRepository) AddProjectTechnology(ctx context.Context, resumeID, projectID uuid.UUID, technology string) error {
+	technologies, err := r.ResumeRepository.GetProjectTechnologies(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if err := checkCapacity("project technologies", len(technologies), r.config.MaxProjectTechnologies); err != nil {
+		return err
+	}
+
+	return r.ResumeRepository.AddProjectTechnology(ctx, resumeID, projectID, technology)
+}
+
+// AddCertification adds a certification entry unless the section is full
+func (r *LimitedResumeRepository) AddCertification(ctx context.Context, resumeID uuid.UUID, certification *domain.Certification) (uuid.UUID, error) {
+	entries, err := r.ResumeRepository.GetCertificationsByResume(ctx, resumeID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkCapacity("certifications", len(entries), r.config.MaxCertifications); err != nil {
+		return uuid.Nil, err
+	}
+
+	return r.ResumeRepository.AddCertification(ctx, resumeID, certification)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// skillListRepository keeps skills and projects in memory for limit checks
+type skillListRepository struct {
+	domain.ResumeRepository
+	skills   []*domain.Skill
+	projects []*domain.Project
+}
+
+func (r *skillListRepository) GetSkillsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Skill, error) {
+	return r.skills, nil
+}
+
+func (r *skillListRepository) AddSkill(ctx context.Context, resumeID uuid.UUID, skill *domain.Skill) (uuid.UUID, error) {
+	r.skills = append(r.skills, skill)
+	return uuid.New(), nil
+}
+
+func (r *skillListRepository) AddSkills(ctx context.Context, resumeID uuid.UUID, skills []*domain.Skill) ([]uuid.UUID, error) {
+	r.skills = append(r.skills, skills...)
+	return make([]uuid.UUID, len(skills)), nil
+}
+
+func (r *skillListRepository) GetProjectsByResume(ctx context.Context, resumeID uuid.UUID) ([]*domain.Project, error) {
+	return r.projects, nil
+}
+
+func (r *skillListRepository) AddProject(ctx context.Context, resumeID uuid.UUID, project *domain.Project) (uuid.UUID, error) {
+	r.projects = append(r.projects, project)
+	return uuid.New(), nil
+}
+
+func TestLimitedResumeRepository(t *testing.T) {
+	ctx := context.Background()
+	inner := &skillListRepository{}
+	repo := NewLimitedResumeRepository(inner, ResumeLimitsConfig{MaxSkills: 2, MaxProjectTechnologies: 1})
+	resumeID := uuid.New()
+
+	// A batch that doesn't fit is rejected whole
+	_, err := repo.AddSkills(ctx, resumeID, []*domain.Skill{{Name: "Go"}, {Name: "SQL"}, {Name: "Rust"}})
+	assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+	assert.Empty(t, inner.skills)
+
+	for range 2 {
+		_, err := repo.AddSkill(ctx, resumeID, &domain.Skill{Name: "Go"})
+		require.NoError(t, err)
+	}
+
+	_, err = repo.AddSkill(ctx, resumeID, &domain.Skill{Name: "SQL"})
+	var limitErr *domain.LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, "skills", limitErr.Section)
+	assert.Equal(t, 2, limitErr.Limit)
+	assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+	assert.Len(t, inner.skills, 2)
+
+	_, err = repo.AddProject(ctx, resumeID, &domain.Project{Name: "API", Technologies: []string{"Go", "SQL"}})
+	assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+	assert.Empty(t, inner.projects)
+
+	_, err = repo.AddProject(ctx, resumeID, &domain.Project{Name: "API", Technologies: []string{"Go"}})
+	require.NoError(t, err)
+}
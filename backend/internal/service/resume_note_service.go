@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// ResumeNoteService errors
+var (
+	ErrNoteNotFound        = errors.New("note not found")
+	ErrNoteVersionConflict = errors.New("note was changed since the base version")
+)
+
+// ResumeNoteServiceConfig contains configuration for the resume note service
+type ResumeNoteServiceConfig struct {
+	// VersionsKept is how many versions of a note are kept, the latest included
+	VersionsKept int
+}
+
+// ResumeNoteService stores the private notes on resumes. Notes arrive
+// encrypted and are never decrypted here. Every save names the version it
+// was based on, so a client can't overwrite a save it hasn't seen from
+// another device.
+type ResumeNoteService struct {
+	noteRepo domain.ResumeNoteRepository
+	config   ResumeNoteServiceConfig
+}
+
+// NewResumeNoteService creates a new resume note service
+func NewResumeNoteService(noteRepo domain.ResumeNoteRepository, config ResumeNoteServiceConfig) *ResumeNoteService {
+	// Set default values if not provided
+	if config.VersionsKept == 0 {
+		config.VersionsKept = 20
+	}
+
+	return &ResumeNoteService{
+		noteRepo: noteRepo,
+		config:   config,
+	}
+}
+
+// Note returns the latest version of a resume's note
+func (s *ResumeNoteService) Note(ctx context.Context, resumeID uuid.UUID) (*domain.ResumeNote, error) {
+	note, err := s.noteRepo.GetLatestNote(ctx, resumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNoteNotFound
+	}
+	return note, err
+}
+
+// NoteVersion returns a kept version of a resume's note
+func (s *ResumeNoteService) NoteVersion(ctx context.Context, resumeID uuid.UUID, version int) (*domain.ResumeNote, error) {
+	note, err := s.noteRepo.GetNoteVersion(ctx, resumeID, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNoteNotFound
+	}
+	return note, err
+}
+
+// Versions lists the kept versions of a resume's note, newest first
+func (s *ResumeNoteService) Versions(ctx context.Context, resumeID uuid.UUID) ([]*domain.ResumeNoteVersion, error) {
+	return s.noteRepo.GetNoteVersions(ctx, resumeID)
+}
+
+// SaveNote stores note as the version after baseVersion, the version the
+// client last read or 0 for a resume without a note. It fails with
+// ErrNoteVersionConflict when the note has been saved since.
+func (s *ResumeNoteService) SaveNote(ctx context.Context, note *domain.ResumeNote, baseVersion int) error {
+	if err := note.Validate(); err != nil {
+		return err
+	}
+
+	latest := 0
+	current, err := s.noteRepo.GetLatestNote(ctx, note.ResumeID)
+	if err == nil {
+		latest = current.Version
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if baseVersion != latest {
+		return ErrNoteVersionConflict
+	}
+
+	note.Version = latest + 1
+	// A concurrent save of the same base version takes the version first
+	if err := s.noteRepo.CreateNote(ctx, note); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrNoteVersionConflict
+		}
+		return err
+	}
+
+	// The save succeeded either way, so old versions are pruned on the next one
+	if oldest := note.Version - s.config.VersionsKept + 1; oldest > 1 {
+		if err := s.noteRepo.DeleteNoteVersionsBefore(ctx, note.ResumeID, oldest); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("resume_id", note.ResumeID.String()).Msg("Failed to prune note versions")
+		}
+	}
+
+	return nil
+}
+
+// DeleteNote deletes every version of a resume's note
+func (s *ResumeNoteService) DeleteNote(ctx context.Context, resumeID uuid.UUID) error {
+	err := s.noteRepo.DeleteNotes(ctx, resumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNoteNotFound
+	}
+	return err
+}
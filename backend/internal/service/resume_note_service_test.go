@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noteRepository keeps note versions in memory
+type noteRepository struct {
+	notes []*domain.ResumeNote
+}
+
+func (r *noteRepository) CreateNote(ctx context.Context, note *domain.ResumeNote) error {
+	for _, existing := range r.notes {
+		if existing.ResumeID == note.ResumeID && existing.Version == note.Version {
+			return repository.ErrConflict
+		}
+	}
+	r.notes = append(r.notes, note)
+	return nil
+}
+
+func (r *noteRepository) GetLatestNote(ctx context.Context, resumeID uuid.UUID) (*domain.ResumeNote, error) {
+	var latest *domain.ResumeNote
+	for _, note := range r.notes {
+		if note.ResumeID == resumeID && (latest == nil || note.Version > latest.Version) {
+			latest = note
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrNotFound
+	}
+	return latest, nil
+}
+
+func (r *noteRepository) GetNoteVersion(ctx context.Context, resumeID uuid.UUID, version int) (*domain.ResumeNote, error) {
+	for _, note := range r.notes {
+		if note.ResumeID == resumeID && note.Version == version {
+			return note, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *noteRepository) GetNoteVersions(ctx context.Context, resumeID uuid.UUID) ([]*domain.ResumeNoteVersion, error) {
+	var versions []*domain.ResumeNoteVersion
+	for i := len(r.notes) - 1; i >= 0; i-- {
+		if note := r.notes[i]; note.ResumeID == resumeID {
+			versions = append(versions, &domain.ResumeNoteVersion{Version: note.Version, Size: len(note.Ciphertext)})
+		}
+	}
+	return versions, nil
+}
+
+func (r *noteRepository) DeleteNoteVersionsBefore(ctx context.Context, resumeID uuid.UUID, version int) error {
+	kept := r.notes[:0]
+	for _, note := range r.notes {
+		if note.ResumeID != resumeID || note.Version >= version {
+			kept = append(kept, note)
+		}
+	}
+	r.notes = kept
+	return nil
+}
+
+func (r *noteRepository) DeleteNotes(ctx context.Context, resumeID uuid.UUID) error {
+	count := len(r.notes)
+	kept := r.notes[:0]
+	for _, note := range r.notes {
+		if note.ResumeID != resumeID {
+			kept = append(kept, note)
+		}
+	}
+	r.notes = kept
+	if len(kept) == count {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func TestSaveNoteVersions(t *testing.T) {
+	ctx := context.Background()
+	repo := &noteRepository{}
+	s := NewResumeNoteService(repo, ResumeNoteServiceConfig{VersionsKept: 3})
+	resumeID := uuid.New()
+
+	newNote := func(ciphertext string) *domain.ResumeNote {
+		return &domain.ResumeNote{ResumeID: resumeID, Algorithm: "AES-256-GCM", Nonce: []byte("nonce"), Ciphertext: []byte(ciphertext)}
+	}
+
+	_, err := s.Note(ctx, resumeID)
+	assert.ErrorIs(t, err, ErrNoteNotFound)
+
+	// The first save is based on version 0
+	first := newNote("first")
+	require.NoError(t, s.SaveNote(ctx, first, 0))
+	assert.Equal(t, 1, first.Version)
+
+	// A client that hasn't seen the latest version can't overwrite it
+	second := newNote("second")
+	require.NoError(t, s.SaveNote(ctx, second, 1))
+	assert.ErrorIs(t, s.SaveNote(ctx, newNote("stale"), 1), ErrNoteVersionConflict)
+	assert.ErrorIs(t, s.SaveNote(ctx, newNote("ahead"), 5), ErrNoteVersionConflict)
+
+	note, err := s.Note(ctx, resumeID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, note.Version)
+	assert.Equal(t, []byte("second"), note.Ciphertext)
+
+	// Only the latest versions are kept
+	for base := 2; base < 5; base++ {
+		require.NoError(t, s.SaveNote(ctx, newNote("more"), base))
+	}
+	versions, err := s.Versions(ctx, resumeID)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, 5, versions[0].Version)
+	assert.Equal(t, 3, versions[2].Version)
+
+	_, err = s.NoteVersion(ctx, resumeID, 1)
+	assert.ErrorIs(t, err, ErrNoteNotFound)
+
+	require.NoError(t, s.DeleteNote(ctx, resumeID))
+	assert.ErrorIs(t, s.DeleteNote(ctx, resumeID), ErrNoteNotFound)
+}
+
+func TestSaveNoteValidation(t *testing.T) {
+	s := NewResumeNoteService(&noteRepository{}, ResumeNoteServiceConfig{})
+	resumeID := uuid.New()
+
+	for name, note := range map[string]*domain.ResumeNote{
+		"empty ciphertext": {ResumeID: resumeID, Algorithm: "AES-256-GCM"},
+		"large ciphertext": {ResumeID: resumeID, Algorithm: "AES-256-GCM", Ciphertext: make([]byte, domain.MaxNoteCiphertextSize+1)},
+		"no algorithm":     {ResumeID: resumeID, Ciphertext: []byte("x")},
+		"long nonce":       {ResumeID: resumeID, Algorithm: "AES-256-GCM", Nonce: make([]byte, domain.MaxNoteNonceSize+1), Ciphertext: []byte("x")},
+	} {
+		var validationErr *domain.ValidationError
+		err := s.SaveNote(context.Background(), note, 0)
+		assert.True(t, errors.As(err, &validationErr), name)
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+)
+
+// ResumePublicationService errors
+var (
+	ErrPublicationNotFound = errors.New("resume publication not found")
+	ErrSlugTaken           = errors.New("slug is taken")
+)
+
+// maxSlugSuggestions is how many free slugs are suggested for a taken one
+const maxSlugSuggestions = 3
+
+// ResumePublicationService publishes resumes as public pages at vanity
+// slugs. Unlike share links, published pages are meant to be found, so they
+// carry no token and are open to search engines.
+type ResumePublicationService struct {
+	publicationRepo domain.ResumePublicationRepository
+	resumeRepo      domain.ResumeRepository
+}
+
+// NewResumePublicationService creates a new resume publication service
+func NewResumePublicationService(publicationRepo domain.ResumePublicationRepository, resumeRepo domain.ResumeRepository) *ResumePublicationService {
+	return &ResumePublicationService{
+		publicationRepo: publicationRepo,
+		resumeRepo:      resumeRepo,
+	}
+}
+
+// Publish publishes a resume at slug, or moves its page there when it is
+// already published. The page leaves out the hidden fields and sections.
+func (s *ResumePublicationService) Publish(ctx context.Context, resume *domain.Resume, slug string, hidden []string) (*domain.ResumePublication, error) {
+	slug, err := domain.NormalizeSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	hidden, err = domain.NormalizeShareHidden(hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	publication := &domain.ResumePublication{ResumeID: resume.ID, UserID: resume.UserID, Slug: slug, Hidden: hidden}
+	if err := s.publicationRepo.SavePublication(ctx, publication); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrSlugTaken
+		}
+		return nil, err
+	}
+
+	return publication, nil
+}
+
+// Publication returns the publication of a resume
+func (s *ResumePublicationService) Publication(ctx context.Context, resumeID uuid.UUID) (*domain.ResumePublication, error) {
+	publication, err := s.publicationRepo.GetPublication(ctx, resumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrPublicationNotFound
+	}
+	return publication, err
+}
+
+// Unpublish takes a resume's page down, freeing its slug
+func (s *ResumePublicationService) Unpublish(ctx context.Context, resumeID uuid.UUID) error {
+	err := s.publicationRepo.DeletePublication(ctx, resumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrPublicationNotFound
+	}
+	return err
+}
+
+// SuggestSlugs returns free slugs like a taken one, numbered from 2
+func (s *ResumePublicationService) SuggestSlugs(ctx context.Context, slug string) ([]string, error) {
+	slug, err := domain.NormalizeSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leave room for the number within the length limit
+	base := slug
+	if len(base) > domain.MaxSlugLength-3 {
+		base = base[:domain.MaxSlugLength-3]
+	}
+	candidates := make([]string, 0, 2*maxSlugSuggestions)
+	for n := 2; len(candidates) < cap(candidates); n++ {
+		candidates = append(candidates, base+"-"+strconv.Itoa(n))
+	}
+
+	taken, err := s.publicationRepo.SlugsTaken(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]string, 0, maxSlugSuggestions)
+	for _, candidate := range candidates {
+		if !slices.Contains(taken, candidate) && len(suggestions) < maxSlugSuggestions {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions, nil
+}
+
+// View returns the resume published at slug, without the fields and sections
+// its page hides
+func (s *ResumePublicationService) View(ctx context.Context, slug string) (*domain.Resume, *domain.ResumePublication, error) {
+	publication, err := s.publicationRepo.GetPublicationBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, ErrPublicationNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resume, err := s.resumeRepo.GetCompleteResume(ctx, publication.ResumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, ErrPublicationNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return publication.Redact(resume), publication, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/mail"
+)
+
+// DefaultResumeSendDailyLimit is how many resumes a user can email per UTC
+// day unless configured otherwise
+const DefaultResumeSendDailyLimit = 10
+
+// ErrSendLimitReached is returned when a user has emailed as many resumes
+// today as they are allowed
+var ErrSendLimitReached = errors.New("daily resume send limit reached")
+
+// ResumeSendServiceConfig contains configuration for the resume send service
+type ResumeSendServiceConfig struct {
+	// DailyLimit caps the resumes each user can email per UTC day
+	DailyLimit int
+}
+
+// ResumeSendService emails rendered resumes to addresses users give, such as
+// a recruiter's, capping how many each user sends a day so the app can't be
+// used to send bulk mail
+type ResumeSendService struct {
+	mailService *MailService
+	cache       cache.Cache
+	config      ResumeSendServiceConfig
+	now         func() time.Time
+}
+
+// NewResumeSendService creates a new resume send service
+func NewResumeSendService(mailService *MailService, store cache.Cache, config ResumeSendServiceConfig) *ResumeSendService {
+	// Set default values if not provided
+	if config.DailyLimit <= 0 {
+		config.DailyLimit = DefaultResumeSendDailyLimit
+	}
+
+	return &ResumeSendService{
+		mailService: mailService,
+		cache:       store,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// DailyLimit returns how many resumes each user can email per UTC day
+func (s *ResumeSendService) DailyLimit() int {
+	return s.config.DailyLimit
+}
+
+// Remaining returns how many more resumes the user can email today
+func (s *ResumeSendService) Remaining(ctx context.Context, userID uuid.UUID) (int, error) {
+	value, err := s.cache.Get(ctx, s.sentKey(userID))
+	if errors.Is(err, cache.ErrMiss) {
+		return s.config.DailyLimit, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	sent, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0, err
+	}
+	return max(s.config.DailyLimit-sent, 0), nil
+}
+
+// Send queues a message for delivery, counting it against the user's daily
+// limit, and returns how many more the user can send today
+func (s *ResumeSendService) Send(ctx context.Context, userID uuid.UUID, msg *mail.Message) (int, error) {
+	// Invalid messages don't use up the allowance
+	if err := msg.Validate(); err != nil {
+		return 0, err
+	}
+
+	// The counter expires when the day ends, so it resets at midnight UTC
+	sent, err := s.cache.Incr(ctx, s.sentKey(userID), max(s.ResetAt().Sub(s.now()), time.Second))
+	if err != nil {
+		return 0, err
+	}
+	if sent > int64(s.config.DailyLimit) {
+		return 0, ErrSendLimitReached
+	}
+
+	if err := s.mailService.Send(ctx, msg); err != nil {
+		return 0, err
+	}
+	return s.config.DailyLimit - int(sent), nil
+}
+
+// ResetAt returns when the daily limits next reset, at midnight UTC
+func (s *ResumeSendService) ResetAt() time.Time {
+	return s.now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// sentKey is the cache key counting the resumes a user has sent today
+func (s *ResumeSendService) sentKey(userID uuid.UUID) string {
+	return "resume_send:" + userID.String() + ":" + s.now().UTC().Format(time.DateOnly)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResumeSendService(t *testing.T, dailyLimit int) *ResumeSendService {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	mr := miniredis.RunT(t)
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})})
+	return NewResumeSendService(NewMailService(queue, mail.NewLogSender()), store, ResumeSendServiceConfig{DailyLimit: dailyLimit})
+}
+
+func TestResumeSendServiceDailyLimit(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestResumeSendService(t, 2)
+	now := time.Date(2025, 4, 11, 23, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	userID := uuid.New()
+	msg := &mail.Message{To: "jobs@example.com", Subject: "Resume"}
+
+	remaining, err := svc.Remaining(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, remaining)
+
+	// Invalid messages aren't counted
+	_, err = svc.Send(ctx, userID, &mail.Message{Subject: "Resume"})
+	assert.ErrorIs(t, err, mail.ErrInvalidMessage)
+
+	remaining, err = svc.Send(ctx, userID, msg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+	remaining, err = svc.Send(ctx, userID, msg)
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	_, err = svc.Send(ctx, userID, msg)
+	assert.ErrorIs(t, err, ErrSendLimitReached)
+
+	remaining, err = svc.Remaining(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+
+	// Other users have their own allowance
+	_, err = svc.Send(ctx, uuid.New(), msg)
+	assert.NoError(t, err)
+
+	// The allowance resets at midnight UTC
+	assert.Equal(t, time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC), svc.ResetAt())
+	now = now.Add(2 * time.Hour)
+	remaining, err = svc.Send(ctx, userID, msg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+}
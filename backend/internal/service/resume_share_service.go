@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/security"
+)
+
+// ResumeShareService errors
+var (
+	ErrShareNotFound      = errors.New("share link not found")
+	ErrPassphraseRequired = errors.New("share link requires a passphrase")
+	ErrWrongPassphrase    = errors.New("wrong passphrase")
+	ErrShareExpired       = errors.New("share link has expired")
+	ErrShareExhausted     = errors.New("share link has reached its view limit")
+)
+
+// shareTokenBytes is the entropy of share link and viewer tokens
+const shareTokenBytes = 24
+
+// ResumeShareServiceConfig contains configuration for the resume share service
+type ResumeShareServiceConfig struct {
+	// ViewerTTL is how long a correct passphrase unlocks a protected link
+	ViewerTTL time.Duration
+}
+
+// ResumeShareService manages links that share resumes with people who have no
+// account. Protected links require a passphrase; entering it issues a viewer
+// token, kept in the cache, that unlocks the link for a while. Links may be
+// limited to a number of views, counted atomically in the cache so concurrent
+// viewers can't exceed the limit, and may expire.
+type ResumeShareService struct {
+	shareRepo  domain.ResumeShareRepository
+	resumeRepo domain.ResumeRepository
+	cache      cache.Cache
+	config     ResumeShareServiceConfig
+}
+
+// NewResumeShareService creates a new resume share service
+func NewResumeShareService(shareRepo domain.ResumeShareRepository, resumeRepo domain.ResumeRepository, store cache.Cache, config ResumeShareServiceConfig) *ResumeShareService {
+	// Set default values if not provided
+	if config.ViewerTTL == 0 {
+		config.ViewerTTL = time.Hour
+	}
+
+	return &ResumeShareService{
+		shareRepo:  shareRepo,
+		resumeRepo: resumeRepo,
+		cache:      store,
+		config:     config,
+	}
+}
+
+// ViewerTTL returns how long a viewer token stays valid
+func (s *ResumeShareService) ViewerTTL() time.Duration {
+	return s.config.ViewerTTL
+}
+
+// CreateShare creates a share link for a resume, protected by passphrase unless
+// it is empty. Viewers of the link don't see the hidden fields and sections.
+func (s *ResumeShareService) CreateShare(ctx context.Context, resumeID uuid.UUID, passphrase string, limits domain.ShareLimits, hidden []string) (*domain.ResumeShare, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	hidden, err := domain.NormalizeShareHidden(hidden)
+	if err != nil {
+		return nil, err
+	}
+	share := &domain.ResumeShare{ResumeID: resumeID, ShareLimits: limits, Hidden: hidden}
+
+	if passphrase != "" {
+		if err := domain.ValidateSharePassphrase(passphrase); err != nil {
+			return nil, err
+		}
+		hash, err := security.HashPassword(passphrase, nil)
+		if err != nil {
+			return nil, err
+		}
+		share.PassphraseHash = hash
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	share.Token = token
+
+	if err := s.shareRepo.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+// Shares returns the share links of a resume
+func (s *ResumeShareService) Shares(ctx context.Context, resumeID uuid.UUID) ([]*domain.ResumeShare, error) {
+	return s.shareRepo.GetSharesByResumeID(ctx, resumeID)
+}
+
+// Share returns one of a resume's share links
+func (s *ResumeShareService) Share(ctx context.Context, resumeID, shareID uuid.UUID) (*domain.ResumeShare, error) {
+	share, err := s.shareRepo.GetShareByID(ctx, resumeID, shareID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrShareNotFound
+	}
+	return share, err
+}
+
+// UpdateShare replaces the view limit, expiry and hidden fields of a share
+// link. Views already counted still count against a new limit.
+func (s *ResumeShareService) UpdateShare(ctx context.Context, share *domain.ResumeShare, limits domain.ShareLimits, hidden []string) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	hidden, err := domain.NormalizeShareHidden(hidden)
+	if err != nil {
+		return err
+	}
+
+	previousLimits, previousHidden := share.ShareLimits, share.Hidden
+	share.ShareLimits, share.Hidden = limits, hidden
+	if err := s.shareRepo.UpdateShare(ctx, share); err != nil {
+		share.ShareLimits, share.Hidden = previousLimits, previousHidden
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrShareNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Views returns how many times a share link has been viewed. Only links with
+// a view limit are counted.
+func (s *ResumeShareService) Views(ctx context.Context, share *domain.ResumeShare) (int64, error) {
+	value, err := s.cache.Get(ctx, shareViewsKey(share.ID))
+	if errors.Is(err, cache.ErrMiss) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// RevokeShare deletes one of a resume's share links
+func (s *ResumeShareService) RevokeShare(ctx context.Context, resumeID, shareID uuid.UUID) error {
+	err := s.shareRepo.DeleteShare(ctx, resumeID, shareID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrShareNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Del(ctx, shareViewsKey(shareID))
+}
+
+// Unlock checks the passphrase of a protected link and returns a viewer token
+// that unlocks it until expiresAt
+func (s *ResumeShareService) Unlock(ctx context.Context, token, passphrase string) (string, time.Time, error) {
+	share, err := s.getShare(ctx, token)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if share.Expired(time.Now()) {
+		return "", time.Time{}, ErrShareExpired
+	}
+	if !share.Protected() {
+		return "", time.Time{}, nil
+	}
+
+	ok, err := security.VerifyPassword(passphrase, share.PassphraseHash)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !ok {
+		return "", time.Time{}, ErrWrongPassphrase
+	}
+
+	viewerToken, err := randomToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := s.cache.Set(ctx, shareViewerKey(viewerToken), []byte(share.ID.String()), s.config.ViewerTTL); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return viewerToken, time.Now().UTC().Add(s.config.ViewerTTL), nil
+}
+
+// View returns the resume behind a share link, without the fields and sections
+// the link hides, and counts the view. Protected links need a viewer token
+// issued by Unlock for the same link.
+func (s *ResumeShareService) View(ctx context.Context, token, viewerToken string) (*domain.Resume, error) {
+	share, err := s.getShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if share.Expired(time.Now()) {
+		return nil, ErrShareExpired
+	}
+
+	if share.Protected() {
+		if viewerToken == "" {
+			return nil, ErrPassphraseRequired
+		}
+		shareID, err := s.cache.Get(ctx, shareViewerKey(viewerToken))
+		if errors.Is(err, cache.ErrMiss) {
+			return nil, ErrPassphraseRequired
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Viewer tokens only unlock the link they were issued for
+		if string(shareID) != share.ID.String() {
+			return nil, ErrPassphraseRequired
+		}
+	}
+
+	if share.MaxViews > 0 {
+		// The counter doesn't expire, so extending a link's expiry can't reset
+		// it; it is removed with the link
+		views, err := s.cache.Incr(ctx, shareViewsKey(share.ID), 0)
+		if err != nil {
+			return nil, err
+		}
+		if views > int64(share.MaxViews) {
+			return nil, ErrShareExhausted
+		}
+	}
+
+	resume, err := s.resumeRepo.GetCompleteResume(ctx, share.ResumeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return share.Redact(resume), nil
+}
+
+// getShare looks up a share link by token
+func (s *ResumeShareService) getShare(ctx context.Context, token string) (*domain.ResumeShare, error) {
+	share, err := s.shareRepo.GetShareByToken(ctx, token)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrShareNotFound
+	}
+	return share, err
+}
+
+// randomToken returns a random URL-safe token
+func randomToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// shareViewsKey is the cache key of a share link's view counter
+func shareViewsKey(shareID uuid.UUID) string {
+	return "share_views:" + shareID.String()
+}
+
+// shareViewerKey is the cache key of a viewer token
+func shareViewerKey(viewerToken string) string {
+	return "share_viewer:" + viewerToken
+}
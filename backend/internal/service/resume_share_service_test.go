@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shareRepository keeps share links in memory
+type shareRepository struct {
+	shares []*domain.ResumeShare
+}
+
+func (r *shareRepository) CreateShare(ctx context.Context, share *domain.ResumeShare) error {
+	share.ID = uuid.New()
+	r.shares = append(r.shares, share)
+	return nil
+}
+
+func (r *shareRepository) GetShareByID(ctx context.Context, resumeID, id uuid.UUID) (*domain.ResumeShare, error) {
+	for _, share := range r.shares {
+		if share.ID == id && share.ResumeID == resumeID {
+			return share, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *shareRepository) UpdateShare(ctx context.Context, share *domain.ResumeShare) error {
+	return nil
+}
+
+func (r *shareRepository) GetShareByToken(ctx context.Context, token string) (*domain.ResumeShare, error) {
+	for _, share := range r.shares {
+		if share.Token == token {
+			return share, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *shareRepository) GetSharesByResumeID(ctx context.Context, resumeID uuid.UUID) ([]*domain.ResumeShare, error) {
+	var shares []*domain.ResumeShare
+	for _, share := range r.shares {
+		if share.ResumeID == resumeID {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+func (r *shareRepository) DeleteShare(ctx context.Context, resumeID, id uuid.UUID) error {
+	for i, share := range r.shares {
+		if share.ID == id && share.ResumeID == resumeID {
+			r.shares = append(r.shares[:i], r.shares[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *shareRepository) DeleteSharesByResumeID(ctx context.Context, resumeID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	kept := r.shares[:0]
+	for _, share := range r.shares {
+		if share.ResumeID == resumeID {
+			ids = append(ids, share.ID)
+		} else {
+			kept = append(kept, share)
+		}
+	}
+	r.shares = kept
+	return ids, nil
+}
+
+func TestResumeShareService(t *testing.T) {
+	ctx := context.Background()
+	resume := &domain.Resume{ID: uuid.New(), ResumeMetadata: domain.ResumeMetadata{Title: "Backend"}}
+	resumes := &translationRepository{resumes: map[uuid.UUID]*domain.Resume{resume.ID: resume}}
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	svc := NewResumeShareService(&shareRepository{}, resumes, store, ResumeShareServiceConfig{})
+
+	// Open links need no passphrase
+	open, err := svc.CreateShare(ctx, resume.ID, "", domain.ShareLimits{}, nil)
+	require.NoError(t, err)
+	assert.False(t, open.Protected())
+	viewed, err := svc.View(ctx, open.Token, "")
+	require.NoError(t, err)
+	assert.Equal(t, resume.ID, viewed.ID)
+
+	_, err = svc.CreateShare(ctx, resume.ID, "short", domain.ShareLimits{}, nil)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	protected, err := svc.CreateShare(ctx, resume.ID, "correct horse", domain.ShareLimits{}, nil)
+	require.NoError(t, err)
+	assert.True(t, protected.Protected())
+	assert.NotContains(t, protected.PassphraseHash, "correct horse")
+	assert.NotEqual(t, open.Token, protected.Token)
+
+	_, err = svc.View(ctx, protected.Token, "")
+	assert.ErrorIs(t, err, ErrPassphraseRequired)
+	_, _, err = svc.Unlock(ctx, protected.Token, "wrong horse")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	viewerToken, expiresAt, err := svc.Unlock(ctx, protected.Token, "correct horse")
+	require.NoError(t, err)
+	assert.NotEmpty(t, viewerToken)
+	assert.False(t, expiresAt.IsZero())
+
+	viewed, err = svc.View(ctx, protected.Token, viewerToken)
+	require.NoError(t, err)
+	assert.Equal(t, resume.ID, viewed.ID)
+	_, err = svc.View(ctx, protected.Token, "forged")
+	assert.ErrorIs(t, err, ErrPassphraseRequired)
+
+	// Viewer tokens only unlock the link they were issued for
+	other, err := svc.CreateShare(ctx, resume.ID, "another passphrase", domain.ShareLimits{}, nil)
+	require.NoError(t, err)
+	_, err = svc.View(ctx, other.Token, viewerToken)
+	assert.ErrorIs(t, err, ErrPassphraseRequired)
+
+	// Revoked links stop working
+	require.NoError(t, svc.RevokeShare(ctx, resume.ID, protected.ID))
+	_, err = svc.View(ctx, protected.Token, viewerToken)
+	assert.ErrorIs(t, err, ErrShareNotFound)
+	assert.ErrorIs(t, svc.RevokeShare(ctx, resume.ID, protected.ID), ErrShareNotFound)
+
+	shares, err := svc.Shares(ctx, resume.ID)
+	require.NoError(t, err)
+	assert.Len(t, shares, 2)
+}
+
+func TestResumeShareLimits(t *testing.T) {
+	ctx := context.Background()
+	resume := &domain.Resume{ID: uuid.New()}
+	resumes := &translationRepository{resumes: map[uuid.UUID]*domain.Resume{resume.ID: resume}}
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	svc := NewResumeShareService(&shareRepository{}, resumes, store, ResumeShareServiceConfig{})
+
+	for _, limits := range []domain.ShareLimits{{MaxViews: -1}, {ExpiresAt: time.Now().Add(-time.Minute)}} {
+		_, err := svc.CreateShare(ctx, resume.ID, "", limits, nil)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	}
+
+	share, err := svc.CreateShare(ctx, resume.ID, "", domain.ShareLimits{MaxViews: 2, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	require.NoError(t, err)
+
+	for range 2 {
+		_, err := svc.View(ctx, share.Token, "")
+		require.NoError(t, err)
+	}
+	_, err = svc.View(ctx, share.Token, "")
+	assert.ErrorIs(t, err, ErrShareExhausted)
+
+	// Raising the limit allows more views; views already counted still count
+	require.NoError(t, svc.UpdateShare(ctx, share, domain.ShareLimits{MaxViews: 4}, nil))
+	_, err = svc.View(ctx, share.Token, "")
+	require.NoError(t, err)
+	views, err := svc.Views(ctx, share)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), views)
+
+	// Expired links stop working before their view limit
+	share.ExpiresAt = time.Now().Add(-time.Second)
+	_, err = svc.View(ctx, share.Token, "")
+	assert.ErrorIs(t, err, ErrShareExpired)
+	_, _, err = svc.Unlock(ctx, share.Token, "anything")
+	assert.ErrorIs(t, err, ErrShareExpired)
+
+	// Removing the limits reopens the link
+	require.NoError(t, svc.UpdateShare(ctx, share, domain.ShareLimits{}, nil))
+	_, err = svc.View(ctx, share.Token, "")
+	assert.NoError(t, err)
+}
+
+func TestResumeShareVisibility(t *testing.T) {
+	ctx := context.Background()
+	resume := &domain.Resume{
+		ID:           uuid.New(),
+		PersonalInfo: &domain.PersonalInfo{FirstName: "Ana", Email: "ana@example.com", Phone: "+525512345678", PhoneDisplay: "55 1234 5678", Address: domain.Address{City: "Puebla"}},
+		Skills:       []*domain.Skill{{Name: "Go"}},
+		Projects:     []*domain.Project{{Name: "API"}},
+	}
+	resumes := &translationRepository{resumes: map[uuid.UUID]*domain.Resume{resume.ID: resume}}
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	defer store.Close()
+	svc := NewResumeShareService(&shareRepository{}, resumes, store, ResumeShareServiceConfig{})
+
+	_, err = svc.CreateShare(ctx, resume.ID, "", domain.ShareLimits{}, []string{"references"})
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	share, err := svc.CreateShare(ctx, resume.ID, "", domain.ShareLimits{}, []string{"skills", " Phone", "phone", "address"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"address", "phone", "skills"}, share.Hidden)
+
+	viewed, err := svc.View(ctx, share.Token, "")
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", viewed.PersonalInfo.Email)
+	assert.Empty(t, viewed.PersonalInfo.Phone)
+	assert.Empty(t, viewed.PersonalInfo.PhoneDisplay)
+	assert.True(t, viewed.PersonalInfo.Address.IsZero())
+	assert.Empty(t, viewed.Skills)
+	assert.Len(t, viewed.Projects, 1)
+
+	// The stored resume is untouched
+	assert.Equal(t, "+525512345678", resume.PersonalInfo.Phone)
+	assert.Len(t, resume.Skills, 1)
+
+	require.NoError(t, svc.UpdateShare(ctx, share, domain.ShareLimits{}, []string{}))
+	viewed, err = svc.View(ctx, share.Token, "")
+	require.NoError(t, err)
+	assert.Len(t, viewed.Skills, 1)
+}
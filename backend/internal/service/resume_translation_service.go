@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/locale"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrTranslationExists is returned when a resume already has a variant in the requested language
+var ErrTranslationExists = errors.New("a translation in this language already exists")
+
+// ResumeTranslationService maintains localized variants of a resume. A
+// translation starts as a copy of the original with every section, so it
+// shares the original's structure, and its text is then edited independently.
+// All variants point at the original, which keeps the group flat.
+type ResumeTranslationService struct {
+	resumeRepo domain.ResumeRepository
+	transactor domain.Transactor
+}
+
+// NewResumeTranslationService creates a new resume translation service
+func NewResumeTranslationService(resumeRepo domain.ResumeRepository) *ResumeTranslationService {
+	return &ResumeTranslationService{
+		resumeRepo: resumeRepo,
+	}
+}
+
+// SetTransactor copies a resume in one transaction. Without it, a copy that
+// fails part way is deleted afterwards.
+func (s *ResumeTranslationService) SetTransactor(transactor domain.Transactor) {
+	s.transactor = transactor
+}
+
+// CreateTranslation copies source and all of its sections into a new resume
+// written in language
+func (s *ResumeTranslationService) CreateTranslation(ctx context.Context, source *domain.Resume, language string) (*domain.Resume, error) {
+	language, err := locale.Normalize(language)
+	if err != nil {
+		return nil, domain.NewValidationError("language", "Language must be a language tag such as \"en\" or \"es-MX\"", domain.ErrInvalidField)
+	}
+
+	variants, err := s.Translations(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		if variant.Language == language {
+			return nil, ErrTranslationExists
+		}
+	}
+
+	complete, err := s.resumeRepo.GetCompleteResume(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	root := translationRoot(source)
+	metadata := complete.ResumeMetadata
+	metadata.Tags = slices.Clone(metadata.Tags)
+	metadata.Language = language
+	metadata.TranslationOf = &root
+
+	var translation *domain.Resume
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		translation, err = s.resumeRepo.CreateResume(ctx, source.UserID, &metadata)
+		if err != nil {
+			return err
+		}
+
+		// Don't leave a partial copy behind if a section fails to copy
+		if err := s.copySections(ctx, complete, translation.ID); err != nil {
+			if s.transactor != nil {
+				return err
+			}
+			if deleteErr := s.resumeRepo.DeleteResume(ctx, translation.ID); deleteErr != nil {
+				log.Ctx(ctx).Error().Err(deleteErr).Str("resume_id", translation.ID.String()).Msg("Failed to remove incomplete translation")
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resumeRepo.GetCompleteResume(ctx, translation.ID)
+}
+
+// Translations returns the original of resume's translation group and all of
+// its translations, resume included
+func (s *ResumeTranslationService) Translations(ctx context.Context, resume *domain.Resume) ([]*domain.Resume, error) {
+	resumes, err := s.resumeRepo.GetResumesByUserID(ctx, resume.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	root := translationRoot(resume)
+	group := make([]*domain.Resume, 0, 1)
+	for _, candidate := range resumes {
+		if candidate.ID == root || (candidate.TranslationOf != nil && *candidate.TranslationOf == root) {
+			group = append(group, candidate)
+		}
+	}
+
+	return group, nil
+}
+
+// copySections adds copies of every section of source to the resume with targetID
+func (s *ResumeTranslationService) copySections(ctx context.Context, source *domain.Resume, targetID uuid.UUID) error {
+	if source.PersonalInfo != nil {
+		info := *source.PersonalInfo
+		if err := s.resumeRepo.SavePersonalInfo(ctx, targetID, &info); err != nil {
+			return err
+		}
+	}
+	for _, education := range source.Education {
+		entry := *education
+		if _, err := s.resumeRepo.AddEducation(ctx, targetID, &entry); err != nil {
+			return err
+		}
+	}
+	for _, experience := range source.Experience {
+		entry := *experience
+		entry.Achievements = slices.Clone(entry.Achievements)
+		if _, err := s.resumeRepo.AddExperience(ctx, targetID, &entry); err != nil {
+			return err
+		}
+	}
+	for _, skill := range source.Skills {
+		entry := *skill
+		if _, err := s.resumeRepo.AddSkill(ctx, targetID, &entry); err != nil {
+			return err
+		}
+	}
+	for _, project := range source.Projects {
+		entry := *project
+		entry.Technologies = slices.Clone(entry.Technologies)
+		if _, err := s.resumeRepo.AddProject(ctx, targetID, &entry); err != nil {
+			return err
+		}
+	}
+	for _, certification := range source.Certifications {
+		entry := *certification
+		if _, err := s.resumeRepo.AddCertification(ctx, targetID, &entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// translationRoot returns the ID of the original resume of resume's translation group
+func translationRoot(resume *domain.Resume) uuid.UUID {
+	if resume.TranslationOf != nil {
+		return *resume.TranslationOf
+	}
+	return resume.ID
+}
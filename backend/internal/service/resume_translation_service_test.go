@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// translationRepository keeps whole resumes in memory
+type translationRepository struct {
+	domain.ResumeRepository
+	resumes map[uuid.UUID]*domain.Resume
+	// sectionErr fails copying sections when set
+	sectionErr error
+}
+
+func (r *translationRepository) CreateResume(ctx context.Context, userID uuid.UUID, metadata *domain.ResumeMetadata) (*domain.Resume, error) {
+	resume := &domain.Resume{ID: uuid.New(), UserID: userID, ResumeMetadata: *metadata}
+	r.resumes[resume.ID] = resume
+	return resume, nil
+}
+
+func (r *translationRepository) GetResumesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Resume, error) {
+	var resumes []*domain.Resume
+	for _, resume := range r.resumes {
+		if resume.UserID == userID {
+			resumes = append(resumes, resume)
+		}
+	}
+	return resumes, nil
+}
+
+func (r *translationRepository) GetCompleteResume(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
+	return r.resumes[id], nil
+}
+
+func (r *translationRepository) SavePersonalInfo(ctx context.Context, resumeID uuid.UUID, info *domain.PersonalInfo) error {
+	r.resumes[resumeID].PersonalInfo = info
+	return nil
+}
+
+func (r *translationRepository) AddExperience(ctx context.Context, resumeID uuid.UUID, experience *domain.Experience) (uuid.UUID, error) {
+	if r.sectionErr != nil {
+		return uuid.Nil, r.sectionErr
+	}
+	r.resumes[resumeID].Experience = append(r.resumes[resumeID].Experience, experience)
+	return uuid.New(), nil
+}
+
+func TestResumeTranslationService(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	original := &domain.Resume{
+		ID:             uuid.New(),
+		UserID:         userID,
+		ResumeMetadata: domain.ResumeMetadata{Title: "Backend", Language: "en"},
+		PersonalInfo:   &domain.PersonalInfo{FirstName: "Ana"},
+		Experience:     []*domain.Experience{{Employer: "Acme", Achievements: []string{"Shipped"}}},
+	}
+	repo := &translationRepository{resumes: map[uuid.UUID]*domain.Resume{original.ID: original}}
+	svc := NewResumeTranslationService(repo)
+
+	spanish, err := svc.CreateTranslation(ctx, original, "es_MX")
+	require.NoError(t, err)
+	assert.Equal(t, "es-MX", spanish.Language)
+	assert.Equal(t, original.ID, *spanish.TranslationOf)
+	assert.Equal(t, "Ana", spanish.PersonalInfo.FirstName)
+	require.Len(t, spanish.Experience, 1)
+
+	// Sections are copies, so editing the translation leaves the original alone
+	spanish.Experience[0].Achievements[0] = "Lanzó"
+	assert.Equal(t, "Shipped", original.Experience[0].Achievements[0])
+
+	// Translating a translation joins the original's group
+	german, err := svc.CreateTranslation(ctx, spanish, "de")
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, *german.TranslationOf)
+
+	group, err := svc.Translations(ctx, german)
+	require.NoError(t, err)
+	assert.Len(t, group, 3)
+
+	_, err = svc.CreateTranslation(ctx, original, "es-MX")
+	assert.ErrorIs(t, err, ErrTranslationExists)
+
+	_, err = svc.CreateTranslation(ctx, original, "???")
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+// snapshotTransactor restores the repository's resumes when a unit of work fails
+type snapshotTransactor struct {
+	repo  *translationRepository
+	calls int
+}
+
+func (t *snapshotTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.calls++
+	saved := maps.Clone(t.repo.resumes)
+	if err := fn(ctx); err != nil {
+		t.repo.resumes = saved
+		return err
+	}
+	return nil
+}
+
+func TestResumeTranslationServiceTransaction(t *testing.T) {
+	ctx := context.Background()
+	original := &domain.Resume{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		ResumeMetadata: domain.ResumeMetadata{Title: "Backend", Language: "en"},
+		Experience:     []*domain.Experience{{Employer: "Acme"}},
+	}
+	repo := &translationRepository{resumes: map[uuid.UUID]*domain.Resume{original.ID: original}}
+	transactor := &snapshotTransactor{repo: repo}
+	svc := NewResumeTranslationService(repo)
+	svc.SetTransactor(transactor)
+
+	// A failed copy is rolled back rather than deleted afterwards; the fake
+	// repository has no DeleteResume, so calling it would panic
+	repo.sectionErr = errors.New("connection lost")
+	_, err := svc.CreateTranslation(ctx, original, "es")
+	assert.ErrorIs(t, err, repo.sectionErr)
+	assert.Len(t, repo.resumes, 1)
+
+	repo.sectionErr = nil
+	spanish, err := svc.CreateTranslation(ctx, original, "es")
+	require.NoError(t, err)
+	assert.Len(t, spanish.Experience, 1)
+	assert.Equal(t, 2, transactor.calls)
+}
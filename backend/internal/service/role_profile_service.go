@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/analysis"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+)
+
+// ErrRoleNotFound is returned when no profile has the requested slug
+var ErrRoleNotFound = errors.New("role profile not found")
+
+// RoleProfileService serves the role profiles resumes are compared against:
+// the built-in ones, with the profiles admins saved replacing those with the
+// same slug and adding roles
+type RoleProfileService struct {
+	roleRepo domain.RoleProfileRepository
+}
+
+// NewRoleProfileService creates a new role profile service
+func NewRoleProfileService(roleRepo domain.RoleProfileRepository) *RoleProfileService {
+	return &RoleProfileService{
+		roleRepo: roleRepo,
+	}
+}
+
+// Profiles returns every role profile, by slug
+func (s *RoleProfileService) Profiles(ctx context.Context) ([]*domain.RoleProfile, error) {
+	overrides, err := s.roleRepo.ListRoleProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := analysis.BuiltinRoles()
+	for _, override := range overrides {
+		i := slices.IndexFunc(profiles, func(p *domain.RoleProfile) bool { return p.Slug == override.Slug })
+		if i >= 0 {
+			profiles[i] = override
+		} else {
+			profiles = append(profiles, override)
+		}
+	}
+	slices.SortFunc(profiles, func(a, b *domain.RoleProfile) int { return strings.Compare(a.Slug, b.Slug) })
+	return profiles, nil
+}
+
+// Profile returns the role profile with a slug
+func (s *RoleProfileService) Profile(ctx context.Context, slug string) (*domain.RoleProfile, error) {
+	profiles, err := s.Profiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		if p.Slug == slug {
+			return p, nil
+		}
+	}
+	return nil, ErrRoleNotFound
+}
+
+// SaveProfile validates and stores an admin's profile, replacing the
+// built-in or saved profile with its slug
+func (s *RoleProfileService) SaveProfile(ctx context.Context, profile *domain.RoleProfile, adminID uuid.UUID) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	profile.Source = domain.RoleSourceOverride
+	profile.UpdatedBy = adminID
+	profile.UpdatedAt = time.Now().UTC()
+	return s.roleRepo.SaveRoleProfile(ctx, profile)
+}
+
+// DeleteProfile deletes an admin's profile, restoring the built-in profile
+// with its slug if there is one
+func (s *RoleProfileService) DeleteProfile(ctx context.Context, slug string) error {
+	if err := s.roleRepo.DeleteRoleProfile(ctx, slug); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	return nil
+}
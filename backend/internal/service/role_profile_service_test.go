@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/analysis"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roleProfileRepository keeps saved role profiles in memory
+type roleProfileRepository struct {
+	profiles map[string]*domain.RoleProfile
+}
+
+func (r *roleProfileRepository) ListRoleProfiles(ctx context.Context) ([]*domain.RoleProfile, error) {
+	var profiles []*domain.RoleProfile
+	for _, p := range r.profiles {
+		copied := *p
+		profiles = append(profiles, &copied)
+	}
+	return profiles, nil
+}
+
+func (r *roleProfileRepository) SaveRoleProfile(ctx context.Context, profile *domain.RoleProfile) error {
+	r.profiles[profile.Slug] = profile
+	return nil
+}
+
+func (r *roleProfileRepository) DeleteRoleProfile(ctx context.Context, slug string) error {
+	if _, ok := r.profiles[slug]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.profiles, slug)
+	return nil
+}
+
+func TestRoleProfileServiceOverrides(t *testing.T) {
+	ctx := context.Background()
+	repo := &roleProfileRepository{profiles: map[string]*domain.RoleProfile{}}
+	svc := NewRoleProfileService(repo)
+
+	profiles, err := svc.Profiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, profiles, len(analysis.BuiltinRoles()))
+	assert.True(t, slices.IsSortedFunc(profiles, func(a, b *domain.RoleProfile) int { return strings.Compare(a.Slug, b.Slug) }))
+
+	// An invalid profile isn't saved
+	err = svc.SaveProfile(ctx, &domain.RoleProfile{Slug: "backend-mid", Name: "Backend"}, uuid.New())
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Empty(t, repo.profiles)
+
+	// Saving replaces the built-in profile with the slug, or adds a role
+	adminID := uuid.New()
+	require.NoError(t, svc.SaveProfile(ctx, &domain.RoleProfile{Slug: "backend-mid", Name: "Backend (ours)", Skills: []domain.RoleSkill{{Name: "Go"}}}, adminID))
+	require.NoError(t, svc.SaveProfile(ctx, &domain.RoleProfile{Slug: "sre-senior", Name: "SRE — Senior", Skills: []domain.RoleSkill{{Name: "Linux"}}}, adminID))
+
+	profiles, err = svc.Profiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, profiles, len(analysis.BuiltinRoles())+1)
+	backend, err := svc.Profile(ctx, "backend-mid")
+	require.NoError(t, err)
+	assert.Equal(t, "Backend (ours)", backend.Name)
+	assert.Equal(t, domain.RoleSourceOverride, backend.Source)
+	assert.Equal(t, adminID, backend.UpdatedBy)
+
+	// Deleting restores the built-in profile
+	require.NoError(t, svc.DeleteProfile(ctx, "backend-mid"))
+	backend, err = svc.Profile(ctx, "backend-mid")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleSourceBuiltin, backend.Source)
+	assert.ErrorIs(t, svc.DeleteProfile(ctx, "backend-mid"), ErrRoleNotFound)
+
+	_, err = svc.Profile(ctx, "astronaut")
+	assert.ErrorIs(t, err, ErrRoleNotFound)
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// ServiceAccountKeyPrefix starts every service account key, telling them
+// apart from personal API keys and access tokens
+const ServiceAccountKeyPrefix = "rgsa_"
+
+// serviceAccountDisplayLength is how much of a key is kept to tell keys apart
+const serviceAccountDisplayLength = len(ServiceAccountKeyPrefix) + 8
+
+// ServiceAccountService errors
+var (
+	ErrServiceAccountNotFound   = errors.New("service account not found")
+	ErrInvalidServiceAccountKey = errors.New("invalid service account key")
+)
+
+// ServiceAccountServiceConfig contains configuration for the service account service
+type ServiceAccountServiceConfig struct {
+	// TouchInterval is how stale the last use of an account may get before
+	// it is updated
+	TouchInterval time.Duration
+	// MaxAuditEntries caps the audit entries returned for an account
+	MaxAuditEntries int
+}
+
+// ServiceAccountService manages the service accounts integrations use to
+// call the admin API. Keys are hashed like API keys. Everything admins do to
+// an account and every request it makes is kept in its audit trail.
+type ServiceAccountService struct {
+	accountRepo domain.ServiceAccountRepository
+	transactor  domain.Transactor
+	config      ServiceAccountServiceConfig
+}
+
+// NewServiceAccountService creates a new service account service
+func NewServiceAccountService(accountRepo domain.ServiceAccountRepository, config ServiceAccountServiceConfig) *ServiceAccountService {
+	// Set default values if not provided
+	if config.TouchInterval == 0 {
+		config.TouchInterval = time.Minute
+	}
+	if config.MaxAuditEntries == 0 {
+		config.MaxAuditEntries = 500
+	}
+
+	return &ServiceAccountService{
+		accountRepo: accountRepo,
+		config:      config,
+	}
+}
+
+// SetTransactor makes changes to an account commit together with their
+// audit entries
+func (s *ServiceAccountService) SetTransactor(transactor domain.Transactor) {
+	s.transactor = transactor
+}
+
+// CreateAccount creates a service account on behalf of an admin, returning it
+// with its key, which can't be recovered later
+func (s *ServiceAccountService) CreateAccount(ctx context.Context, actorID uuid.UUID, name, description string, scopes []string, expiresAt time.Time) (*domain.ServiceAccount, string, error) {
+	account := &domain.ServiceAccount{
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		ExpiresAt:   expiresAt,
+		CreatedBy:   actorID,
+	}
+	if err := account.Validate(); err != nil {
+		return nil, "", err
+	}
+	scopes, err := domain.NormalizeServiceAccountScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	account.Scopes = scopes
+
+	secret, err := newServiceAccountKey()
+	if err != nil {
+		return nil, "", err
+	}
+	account.Prefix = secret[:serviceAccountDisplayLength]
+	account.KeyHash = hashAPIKey(secret)
+
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.accountRepo.CreateServiceAccount(ctx, account); err != nil {
+			return err
+		}
+		return s.audit(ctx, account.ID, actorID, domain.ServiceAccountActionCreate, map[string]any{
+			"name":   account.Name,
+			"scopes": account.Scopes,
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	log.Ctx(ctx).Info().Str("service_account_id", account.ID.String()).Str("actor_id", actorID.String()).Strs("scopes", account.Scopes).Msg("Service account created")
+	return account, secret, nil
+}
+
+// Accounts returns all service accounts
+func (s *ServiceAccountService) Accounts(ctx context.Context) ([]*domain.ServiceAccount, error) {
+	return s.accountRepo.ListServiceAccounts(ctx)
+}
+
+// RotateKey replaces the key of a service account on behalf of an admin. The
+// old key stops working at once.
+func (s *ServiceAccountService) RotateKey(ctx context.Context, actorID, id uuid.UUID) (*domain.ServiceAccount, string, error) {
+	secret, err := newServiceAccountKey()
+	if err != nil {
+		return nil, "", err
+	}
+	prefix := secret[:serviceAccountDisplayLength]
+
+	var account *domain.ServiceAccount
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.accountRepo.UpdateServiceAccountKey(ctx, id, prefix, hashAPIKey(secret)); err != nil {
+			return err
+		}
+		if err := s.audit(ctx, id, actorID, domain.ServiceAccountActionRotateKey, map[string]string{"prefix": prefix}); err != nil {
+			return err
+		}
+		account, err = s.accountRepo.GetServiceAccount(ctx, id)
+		return err
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, "", ErrServiceAccountNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	log.Ctx(ctx).Info().Str("service_account_id", id.String()).Str("actor_id", actorID.String()).Msg("Service account key rotated")
+	return account, secret, nil
+}
+
+// DeleteAccount deletes a service account on behalf of an admin. Its audit
+// trail is kept.
+func (s *ServiceAccountService) DeleteAccount(ctx context.Context, actorID, id uuid.UUID) error {
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.accountRepo.DeleteServiceAccount(ctx, id); err != nil {
+			return err
+		}
+		return s.audit(ctx, id, actorID, domain.ServiceAccountActionDelete, nil)
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrServiceAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Str("service_account_id", id.String()).Str("actor_id", actorID.String()).Msg("Service account deleted")
+	return nil
+}
+
+// AuditLog returns the most recent audit entries of a service account,
+// including those of deleted accounts
+func (s *ServiceAccountService) AuditLog(ctx context.Context, id uuid.UUID) ([]*domain.ServiceAccountAuditEntry, error) {
+	return s.accountRepo.GetServiceAccountAuditEntries(ctx, id, s.config.MaxAuditEntries)
+}
+
+// Authenticate resolves a service account key to its account
+func (s *ServiceAccountService) Authenticate(ctx context.Context, secret string) (*domain.ServiceAccount, error) {
+	if !IsServiceAccountKey(secret) {
+		return nil, ErrInvalidServiceAccountKey
+	}
+
+	account, err := s.accountRepo.GetServiceAccountByHash(ctx, hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidServiceAccountKey
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if account.Expired(now) {
+		return nil, ErrInvalidServiceAccountKey
+	}
+
+	if now.Sub(account.LastUsedAt) >= s.config.TouchInterval {
+		// A failed update only loses the usage time, so the request goes on
+		if err := s.accountRepo.TouchServiceAccount(ctx, account.ID, now); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("service_account_id", account.ID.String()).Msg("Failed to record service account use")
+		} else {
+			account.LastUsedAt = now
+		}
+	}
+
+	return account, nil
+}
+
+// RecordRequest adds a request a service account made to its audit trail
+func (s *ServiceAccountService) RecordRequest(ctx context.Context, accountID uuid.UUID, method, path string, status int) error {
+	return s.audit(ctx, accountID, uuid.Nil, domain.ServiceAccountActionRequest, map[string]any{
+		"method": method,
+		"path":   path,
+		"status": status,
+	})
+}
+
+// audit adds an entry to the audit trail of a service account
+func (s *ServiceAccountService) audit(ctx context.Context, accountID, actorID uuid.UUID, action string, details any) error {
+	entry := &domain.ServiceAccountAuditEntry{
+		ServiceAccountID: accountID,
+		ActorID:          actorID,
+		Action:           action,
+	}
+
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit details: %w", err)
+		}
+		entry.Details = data
+	}
+
+	return s.accountRepo.AddServiceAccountAuditEntry(ctx, entry)
+}
+
+// IsServiceAccountKey reports whether a bearer token is a service account key
+func IsServiceAccountKey(token string) bool {
+	return strings.HasPrefix(token, ServiceAccountKeyPrefix)
+}
+
+// newServiceAccountKey generates a service account key
+func newServiceAccountKey() (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return ServiceAccountKeyPrefix + token, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryServiceAccountRepository keeps service accounts and their audit
+// trails in memory
+type memoryServiceAccountRepository struct {
+	accounts []*domain.ServiceAccount
+	audit    []*domain.ServiceAccountAuditEntry
+}
+
+func (r *memoryServiceAccountRepository) CreateServiceAccount(ctx context.Context, account *domain.ServiceAccount) error {
+	account.ID = uuid.New()
+	r.accounts = append(r.accounts, account)
+	return nil
+}
+
+func (r *memoryServiceAccountRepository) GetServiceAccount(ctx context.Context, id uuid.UUID) (*domain.ServiceAccount, error) {
+	for _, account := range r.accounts {
+		if account.ID == id {
+			return account, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memoryServiceAccountRepository) GetServiceAccountByHash(ctx context.Context, keyHash string) (*domain.ServiceAccount, error) {
+	for _, account := range r.accounts {
+		if account.KeyHash == keyHash {
+			return account, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memoryServiceAccountRepository) ListServiceAccounts(ctx context.Context) ([]*domain.ServiceAccount, error) {
+	return r.accounts, nil
+}
+
+func (r *memoryServiceAccountRepository) UpdateServiceAccountKey(ctx context.Context, id uuid.UUID, prefix, keyHash string) error {
+	account, err := r.GetServiceAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+	account.Prefix, account.KeyHash = prefix, keyHash
+	return nil
+}
+
+func (r *memoryServiceAccountRepository) TouchServiceAccount(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return nil
+}
+
+func (r *memoryServiceAccountRepository) DeleteServiceAccount(ctx context.Context, id uuid.UUID) error {
+	n := len(r.accounts)
+	r.accounts = slices.DeleteFunc(r.accounts, func(account *domain.ServiceAccount) bool { return account.ID == id })
+	if len(r.accounts) == n {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *memoryServiceAccountRepository) AddServiceAccountAuditEntry(ctx context.Context, entry *domain.ServiceAccountAuditEntry) error {
+	r.audit = append(r.audit, entry)
+	return nil
+}
+
+func (r *memoryServiceAccountRepository) GetServiceAccountAuditEntries(ctx context.Context, serviceAccountID uuid.UUID, limit int) ([]*domain.ServiceAccountAuditEntry, error) {
+	var entries []*domain.ServiceAccountAuditEntry
+	for i := len(r.audit) - 1; i >= 0 && len(entries) < limit; i-- {
+		if r.audit[i].ServiceAccountID == serviceAccountID {
+			entries = append(entries, r.audit[i])
+		}
+	}
+	return entries, nil
+}
+
+func TestServiceAccountLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryServiceAccountRepository{}
+	svc := NewServiceAccountService(repo, ServiceAccountServiceConfig{})
+	adminID := uuid.New()
+
+	account, secret, err := svc.CreateAccount(ctx, adminID, " Grafana ", "Dashboards", []string{"metrics:read", "Users:Read", "metrics:read"}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "Grafana", account.Name)
+	assert.Equal(t, []string{domain.ScopeMetricsRead, domain.ScopeUsersRead}, account.Scopes)
+	assert.True(t, strings.HasPrefix(secret, ServiceAccountKeyPrefix))
+	assert.True(t, strings.HasPrefix(secret, account.Prefix))
+	// Service account keys are no personal API keys
+	assert.False(t, IsAPIKey(secret))
+
+	authenticated, err := svc.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, account.ID, authenticated.ID)
+	require.NoError(t, svc.RecordRequest(ctx, account.ID, "GET", "/metrics", 200))
+
+	// Rotating replaces the key at once
+	_, rotated, err := svc.RotateKey(ctx, adminID, account.ID)
+	require.NoError(t, err)
+	_, err = svc.Authenticate(ctx, secret)
+	assert.ErrorIs(t, err, ErrInvalidServiceAccountKey)
+	_, err = svc.Authenticate(ctx, rotated)
+	require.NoError(t, err)
+
+	// The audit trail outlives the account
+	require.NoError(t, svc.DeleteAccount(ctx, adminID, account.ID))
+	_, err = svc.Authenticate(ctx, rotated)
+	assert.ErrorIs(t, err, ErrInvalidServiceAccountKey)
+	assert.ErrorIs(t, svc.DeleteAccount(ctx, adminID, account.ID), ErrServiceAccountNotFound)
+
+	entries, err := svc.AuditLog(ctx, account.ID)
+	require.NoError(t, err)
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{
+		domain.ServiceAccountActionDelete,
+		domain.ServiceAccountActionRotateKey,
+		domain.ServiceAccountActionRequest,
+		domain.ServiceAccountActionCreate,
+	}, actions)
+	assert.Equal(t, adminID, entries[0].ActorID)
+	assert.Equal(t, uuid.Nil, entries[2].ActorID)
+	var request map[string]any
+	require.NoError(t, json.Unmarshal(entries[2].Details, &request))
+	assert.Equal(t, "/metrics", request["path"])
+}
+
+func TestServiceAccountValidation(t *testing.T) {
+	ctx := context.Background()
+	svc := NewServiceAccountService(&memoryServiceAccountRepository{}, ServiceAccountServiceConfig{})
+
+	// User scopes can't be granted to service accounts
+	_, _, err := svc.CreateAccount(ctx, uuid.New(), "Backup", "", []string{domain.ScopeResumesRead}, time.Time{})
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, _, err = svc.CreateAccount(ctx, uuid.New(), "", "", []string{domain.ScopeUsersRead}, time.Time{})
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, _, err = svc.CreateAccount(ctx, uuid.New(), "Backup", "", []string{domain.ScopeUsersRead}, time.Now().Add(-time.Hour))
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestServiceAccountExpired(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryServiceAccountRepository{}
+	svc := NewServiceAccountService(repo, ServiceAccountServiceConfig{})
+
+	account, secret, err := svc.CreateAccount(ctx, uuid.New(), "Backup", "", []string{domain.ScopeUsersRead}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	account.ExpiresAt = time.Now().Add(-time.Minute)
+
+	_, err = svc.Authenticate(ctx, secret)
+	assert.ErrorIs(t, err, ErrInvalidServiceAccountKey)
+	_, err = svc.Authenticate(ctx, "rg_notaserviceaccount")
+	assert.ErrorIs(t, err, ErrInvalidServiceAccountKey)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/rs/zerolog/log"
+)
+
+// JobTypeReloadSettings is the job type for applying settings changed on other instances
+const JobTypeReloadSettings = "settings.reload"
+
+// SettingsServiceConfig contains configuration for the settings service
+type SettingsServiceConfig struct {
+	// CSPReportURI is where browsers report Content-Security-Policy violations
+	CSPReportURI string
+}
+
+// SettingsService manages the runtime settings admins can change without a
+// deploy. Changes apply immediately on the instance that saved them and on
+// the others when they next reload.
+type SettingsService struct {
+	settingRepo domain.InstanceSettingRepository
+	headers     *security.HeadersSwitch
+	config      SettingsServiceConfig
+}
+
+// NewSettingsService creates a new settings service that applies security
+// header settings to headers
+func NewSettingsService(settingRepo domain.InstanceSettingRepository, headers *security.HeadersSwitch, config SettingsServiceConfig) *SettingsService {
+	return &SettingsService{
+		settingRepo: settingRepo,
+		headers:     headers,
+		config:      config,
+	}
+}
+
+// SecurityHeaders returns the stored security header settings, or the
+// defaults when none are stored
+func (s *SettingsService) SecurityHeaders(ctx context.Context) (security.HeadersSettings, error) {
+	settings := security.DefaultHeadersSettings()
+
+	setting, err := s.settingRepo.GetSetting(ctx, domain.SettingSecurityHeaders)
+	if errors.Is(err, repository.ErrNotFound) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	if err := json.Unmarshal(setting.Value, &settings); err != nil {
+		return security.DefaultHeadersSettings(), err
+	}
+	return settings, nil
+}
+
+// UpdateSecurityHeaders validates, stores and applies security header settings
+func (s *SettingsService) UpdateSecurityHeaders(ctx context.Context, settings security.HeadersSettings, adminID uuid.UUID) error {
+	if err := settings.Validate(); err != nil {
+		return domain.NewValidationError("security_headers", err.Error(), domain.ErrInvalidField)
+	}
+
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	if err := s.settingRepo.SaveSetting(ctx, &domain.InstanceSetting{
+		Key:       domain.SettingSecurityHeaders,
+		Value:     value,
+		UpdatedBy: adminID,
+	}); err != nil {
+		return err
+	}
+
+	s.headers.Set(s.HeadersConfig(settings))
+	return nil
+}
+
+// Reload applies the stored settings. Settings that no longer validate are
+// skipped and the current ones kept.
+func (s *SettingsService) Reload(ctx context.Context) error {
+	settings, err := s.SecurityHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	if err := settings.Validate(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Ignoring invalid stored security header settings")
+		return nil
+	}
+
+	s.headers.Set(s.HeadersConfig(settings))
+	return nil
+}
+
+// HandleReloadJob picks up settings saved by other instances
+func (s *SettingsService) HandleReloadJob(ctx context.Context, job *jobs.Job) error {
+	return s.Reload(ctx)
+}
+
+// HeadersConfig returns the header configuration settings produce
+func (s *SettingsService) HeadersConfig(settings security.HeadersSettings) security.HeadersConfig {
+	return settings.Config(s.config.CSPReportURI)
+}
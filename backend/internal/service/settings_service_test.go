@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settingRepository keeps instance settings in memory
+type settingRepository struct {
+	settings map[string]*domain.InstanceSetting
+}
+
+func (r *settingRepository) GetSetting(ctx context.Context, key string) (*domain.InstanceSetting, error) {
+	setting, ok := r.settings[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return setting, nil
+}
+
+func (r *settingRepository) SaveSetting(ctx context.Context, setting *domain.InstanceSetting) error {
+	r.settings[setting.Key] = setting
+	return nil
+}
+
+func TestSettingsServiceSecurityHeaders(t *testing.T) {
+	ctx := context.Background()
+	repo := &settingRepository{settings: map[string]*domain.InstanceSetting{}}
+	headers := security.NewHeadersSwitch(security.DefaultHeadersConfig())
+	svc := NewSettingsService(repo, headers, SettingsServiceConfig{CSPReportURI: "/api/v1/csp-report"})
+
+	settings, err := svc.SecurityHeaders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, security.HeadersProfileStrict, settings.Profile)
+
+	err = svc.UpdateSecurityHeaders(ctx, security.HeadersSettings{Profile: "open"}, uuid.New())
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Empty(t, repo.settings)
+
+	adminID := uuid.New()
+	embedded := security.HeadersSettings{Profile: security.HeadersProfileEmbedded, FrameAncestors: []string{"https://portal.example.com"}}
+	require.NoError(t, svc.UpdateSecurityHeaders(ctx, embedded, adminID))
+	assert.Equal(t, adminID, repo.settings[domain.SettingSecurityHeaders].UpdatedBy)
+	assert.Contains(t, headers.Config().ContentSecurityPolicy, "frame-ancestors 'self' https://portal.example.com")
+	assert.Contains(t, headers.Config().ContentSecurityPolicy, "report-uri /api/v1/csp-report")
+
+	// Another instance picks the stored settings up on reload
+	other := security.NewHeadersSwitch(security.DefaultHeadersConfig())
+	require.NoError(t, NewSettingsService(repo, other, SettingsServiceConfig{}).Reload(ctx))
+	assert.Empty(t, other.Config().XFrameOptions)
+
+	settings, err = svc.SecurityHeaders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, embedded, settings)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/pkg/sms"
+)
+
+// JobTypeSendSMS is the job type for delivering a text message
+const JobTypeSendSMS = "sms.send"
+
+// SMSService queues outgoing text messages for delivery by the job worker
+type SMSService struct {
+	queue  *jobs.Queue
+	sender sms.Sender
+}
+
+// NewSMSService creates a new SMS service
+func NewSMSService(queue *jobs.Queue, sender sms.Sender) *SMSService {
+	return &SMSService{
+		queue:  queue,
+		sender: sender,
+	}
+}
+
+// Send queues a message for delivery
+func (s *SMSService) Send(ctx context.Context, msg *sms.Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.queue.Enqueue(ctx, JobTypeSendSMS, msg)
+	return err
+}
+
+// HandleSendJob delivers a queued message
+func (s *SMSService) HandleSendJob(ctx context.Context, job *jobs.Job) error {
+	var msg sms.Message
+	if err := job.DecodePayload(&msg); err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, &msg)
+}
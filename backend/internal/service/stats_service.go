@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// StatsServiceConfig contains configuration for the stats service
+type StatsServiceConfig struct {
+	// Days is how many days of exports are counted, today included
+	Days int
+	// Weeks is how many weeks of signups are counted, this week included
+	Weeks int
+}
+
+// StatsService computes the aggregate counts of the admin statistics
+type StatsService struct {
+	statsRepo domain.StatsRepository
+	config    StatsServiceConfig
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(statsRepo domain.StatsRepository, config StatsServiceConfig) *StatsService {
+	// Set default values if not provided
+	if config.Days <= 0 {
+		config.Days = 30
+	}
+	if config.Weeks <= 0 {
+		config.Weeks = 12
+	}
+
+	return &StatsService{
+		statsRepo: statsRepo,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// GetStats computes the admin statistics. The per day and per week counts
+// cover the configured periods, with zeros for periods without any.
+func (s *StatsService) GetStats(ctx context.Context) (*domain.AdminStats, error) {
+	now := s.now().UTC()
+	stats := &domain.AdminStats{GeneratedAt: now}
+
+	var err error
+	stats.ResumesPerUser, err = s.statsRepo.GetResumesPerUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range stats.ResumesPerUser {
+		stats.Users += count.Users
+		stats.Resumes += count.Resumes * count.Users
+	}
+
+	stats.ActiveSessions, err = s.statsRepo.CountActiveSessions(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	today := now.Truncate(24 * time.Hour)
+	firstDay := today.AddDate(0, 0, 1-s.config.Days)
+	exports, err := s.statsRepo.GetExportsPerDay(ctx, firstDay)
+	if err != nil {
+		return nil, err
+	}
+	stats.ExportsPerDay = fillPeriods(exports, firstDay, s.config.Days, 1)
+
+	// Weeks start on Monday, as PostgreSQL's date_trunc has them
+	sinceMonday := (int(today.Weekday()) + 6) % 7
+	firstWeek := today.AddDate(0, 0, -sinceMonday-7*(s.config.Weeks-1))
+	signups, err := s.statsRepo.GetSignupsPerWeek(ctx, firstWeek)
+	if err != nil {
+		return nil, err
+	}
+	stats.SignupsPerWeek = fillPeriods(signups, firstWeek, s.config.Weeks, 7)
+
+	return stats, nil
+}
+
+// fillPeriods returns the counts of n periods of days each from start,
+// taking them from counts and using zero for periods counts leaves out
+func fillPeriods(counts []domain.PeriodCount, start time.Time, n, days int) []domain.PeriodCount {
+	byStart := make(map[time.Time]int64, len(counts))
+	for _, count := range counts {
+		byStart[count.Start] = count.Count
+	}
+
+	periods := make([]domain.PeriodCount, n)
+	for i := range periods {
+		periodStart := start.AddDate(0, 0, i*days)
+		periods[i] = domain.PeriodCount{Start: periodStart, Count: byStart[periodStart]}
+	}
+	return periods
+}
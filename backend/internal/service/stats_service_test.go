@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStatsRepository returns fixed counts, recording the times asked for
+type stubStatsRepository struct {
+	exportsSince time.Time
+	signupsSince time.Time
+}
+
+func (r *stubStatsRepository) CountActiveSessions(ctx context.Context, now time.Time) (int64, error) {
+	return 7, nil
+}
+
+func (r *stubStatsRepository) GetResumesPerUser(ctx context.Context) ([]domain.ResumeCount, error) {
+	return []domain.ResumeCount{{Resumes: 0, Users: 4}, {Resumes: 1, Users: 3}, {Resumes: 3, Users: 2}}, nil
+}
+
+func (r *stubStatsRepository) GetExportsPerDay(ctx context.Context, since time.Time) ([]domain.PeriodCount, error) {
+	r.exportsSince = since
+	return []domain.PeriodCount{{Start: since.AddDate(0, 0, 1), Count: 5}}, nil
+}
+
+func (r *stubStatsRepository) GetSignupsPerWeek(ctx context.Context, since time.Time) ([]domain.PeriodCount, error) {
+	r.signupsSince = since
+	return []domain.PeriodCount{{Start: since, Count: 2}}, nil
+}
+
+func TestGetStats(t *testing.T) {
+	repo := &stubStatsRepository{}
+	svc := NewStatsService(repo, StatsServiceConfig{Days: 3, Weeks: 2})
+	// A Wednesday
+	svc.now = func() time.Time { return time.Date(2025, 4, 16, 10, 30, 0, 0, time.UTC) }
+
+	stats, err := svc.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), stats.Users)
+	assert.Equal(t, int64(9), stats.Resumes)
+	assert.Equal(t, int64(7), stats.ActiveSessions)
+	assert.Len(t, stats.ResumesPerUser, 3)
+
+	day := func(d int) time.Time { return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC) }
+	assert.Equal(t, day(14), repo.exportsSince)
+	assert.Equal(t, []domain.PeriodCount{{Start: day(14)}, {Start: day(15), Count: 5}, {Start: day(16)}}, stats.ExportsPerDay)
+
+	// Weeks start on Monday
+	assert.Equal(t, day(7), repo.signupsSince)
+	assert.Equal(t, []domain.PeriodCount{{Start: day(7), Count: 2}, {Start: day(14)}}, stats.SignupsPerWeek)
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/pkg/health"
+	"github.com/redis/go-redis/v9"
+)
+
+// Overall service statuses shown on the status page
+const (
+	ServiceStatusOperational = "operational"
+	ServiceStatusDegraded    = "degraded"
+	ServiceStatusOutage      = "major_outage"
+)
+
+// JobTypeStatusProbe is the job type for sampling dependency health for uptime history
+const JobTypeStatusProbe = "status.probe"
+
+// statusDateLayout names the daily uptime buckets
+const statusDateLayout = "2006-01-02"
+
+// ComponentUptime describes the current state and uptime history of one dependency
+type ComponentUptime struct {
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	Uptime7d  *float64      `json:"uptime_7d"`
+	Uptime30d *float64      `json:"uptime_30d"`
+	History   []DailyUptime `json:"history"`
+}
+
+// DailyUptime is the share of successful probes on one day, nil when nothing was sampled
+type DailyUptime struct {
+	Date   string   `json:"date"`
+	Uptime *float64 `json:"uptime"`
+}
+
+// StatusPage is the public summary of service health
+type StatusPage struct {
+	Status     string             `json:"status"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	Components []ComponentUptime  `json:"components"`
+	Incidents  []*domain.Incident `json:"incidents"`
+}
+
+// StatusServiceConfig contains configuration for the status service
+type StatusServiceConfig struct {
+	// HistoryDays is the number of daily uptime buckets kept and reported
+	HistoryDays int
+	// IncidentWindow is how long resolved incidents stay on the status page
+	IncidentWindow time.Duration
+}
+
+// StatusService samples dependency health and builds the public status page
+type StatusService struct {
+	checker      *health.Checker
+	incidentRepo domain.IncidentRepository
+	redis        redis.UniversalClient
+	config       StatusServiceConfig
+}
+
+// NewStatusService creates a new status service
+func NewStatusService(checker *health.Checker, incidentRepo domain.IncidentRepository, redisClient redis.UniversalClient, config StatusServiceConfig) *StatusService {
+	// Set default values if not provided
+	if config.HistoryDays == 0 {
+		config.HistoryDays = 30
+	}
+	if config.IncidentWindow == 0 {
+		config.IncidentWindow = 7 * 24 * time.Hour
+	}
+
+	return &StatusService{
+		checker:      checker,
+		incidentRepo: incidentRepo,
+		redis:        redisClient,
+		config:       config,
+	}
+}
+
+// HandleProbeJob runs all health checks and records the results in the uptime history
+func (s *StatusService) HandleProbeJob(ctx context.Context, job *jobs.Job) error {
+	report := s.checker.Run(ctx)
+	return s.record(ctx, report, time.Now().UTC())
+}
+
+// record stores a probe result in the daily buckets and as the latest report
+func (s *StatusService) record(ctx context.Context, report health.Report, at time.Time) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(s.config.HistoryDays+1) * 24 * time.Hour
+	day := at.UTC().Format(statusDateLayout)
+
+	pipe := s.redis.TxPipeline()
+	for name, component := range report.Components {
+		key := statusUptimeKey(name, day)
+		pipe.HIncrBy(ctx, key, "total", 1)
+		if component.Status == health.StatusUp {
+			pipe.HIncrBy(ctx, key, "up", 1)
+		}
+		pipe.Expire(ctx, key, ttl)
+	}
+	pipe.Set(ctx, statusLatestKey, data, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// latest returns the most recent probe, running the checks if none has been recorded
+func (s *StatusService) latest(ctx context.Context) (health.Report, error) {
+	data, err := s.redis.Get(ctx, statusLatestKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		report := s.checker.Run(ctx)
+		return report, s.record(ctx, report, time.Now().UTC())
+	}
+	if err != nil {
+		return health.Report{}, err
+	}
+
+	var report health.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return health.Report{}, err
+	}
+	return report, nil
+}
+
+// Status builds the public status page
+func (s *StatusService) Status(ctx context.Context) (*StatusPage, error) {
+	report, err := s.latest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest probe: %w", err)
+	}
+
+	incidents, err := s.incidentRepo.GetIncidentsSince(ctx, time.Now().UTC().Add(-s.config.IncidentWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incidents: %w", err)
+	}
+
+	page := &StatusPage{
+		Status:     ServiceStatusOperational,
+		UpdatedAt:  time.Now().UTC(),
+		Components: make([]ComponentUptime, 0, len(report.Components)),
+		Incidents:  incidents,
+	}
+
+	for _, name := range s.checker.Components() {
+		component, ok := report.Components[name]
+		if !ok {
+			continue
+		}
+		if !component.CheckedAt.IsZero() && component.CheckedAt.Before(page.UpdatedAt) {
+			page.UpdatedAt = component.CheckedAt
+		}
+
+		uptime, err := s.uptime(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get uptime for %s: %w", name, err)
+		}
+		uptime.Status = component.Status
+		page.Components = append(page.Components, *uptime)
+	}
+
+	page.Status = overallStatus(report, incidents)
+	return page, nil
+}
+
+// uptime reads the daily buckets of a component, most recent day first
+func (s *StatusService) uptime(ctx context.Context, name string) (*ComponentUptime, error) {
+	today := time.Now().UTC()
+
+	pipe := s.redis.Pipeline()
+	cmds := make([]*redis.SliceCmd, s.config.HistoryDays)
+	for i := range cmds {
+		day := today.AddDate(0, 0, -i).Format(statusDateLayout)
+		cmds[i] = pipe.HMGet(ctx, statusUptimeKey(name, day), "up", "total")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	result := &ComponentUptime{
+		Name:    name,
+		History: make([]DailyUptime, len(cmds)),
+	}
+
+	var up7, total7, up30, total30 int64
+	for i, cmd := range cmds {
+		up, total := bucketCounts(cmd.Val())
+		result.History[i] = DailyUptime{
+			Date:   today.AddDate(0, 0, -i).Format(statusDateLayout),
+			Uptime: ratio(up, total),
+		}
+		if i < 7 {
+			up7 += up
+			total7 += total
+		}
+		up30 += up
+		total30 += total
+	}
+	result.Uptime7d = ratio(up7, total7)
+	result.Uptime30d = ratio(up30, total30)
+
+	return result, nil
+}
+
+// overallStatus combines the latest probe with open incidents
+func overallStatus(report health.Report, incidents []*domain.Incident) string {
+	status := ServiceStatusOperational
+	if report.Status == health.StatusDown {
+		return ServiceStatusOutage
+	}
+	if report.Status == health.StatusDegraded {
+		status = ServiceStatusDegraded
+	}
+
+	for _, incident := range incidents {
+		if incident.Status == domain.IncidentStatusResolved {
+			continue
+		}
+		if incident.Impact == domain.IncidentImpactCritical {
+			return ServiceStatusOutage
+		}
+		status = ServiceStatusDegraded
+	}
+	return status
+}
+
+// bucketCounts parses the up and total fields of a daily bucket
+func bucketCounts(values []any) (up, total int64) {
+	if len(values) != 2 {
+		return 0, 0
+	}
+	if v, ok := values[0].(string); ok {
+		up, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if v, ok := values[1].(string); ok {
+		total, _ = strconv.ParseInt(v, 10, 64)
+	}
+	return up, total
+}
+
+// ratio returns up/total as a percentage rounded to two decimals, or nil without samples
+func ratio(up, total int64) *float64 {
+	if total == 0 {
+		return nil
+	}
+	pct := float64(int64(float64(up)/float64(total)*10000+0.5)) / 100
+	return &pct
+}
+
+// Redis keys for status data
+const statusLatestKey = "status:latest"
+
+func statusUptimeKey(component, day string) string {
+	return "status:uptime:" + component + ":" + day
+}
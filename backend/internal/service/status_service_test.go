@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/pkg/health"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubIncidentRepository returns a fixed list of incidents
+type stubIncidentRepository struct {
+	incidents []*domain.Incident
+}
+
+func (r *stubIncidentRepository) CreateIncident(ctx context.Context, incident *domain.Incident) error {
+	return nil
+}
+func (r *stubIncidentRepository) GetIncidentByID(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
+	return nil, errors.New("not implemented")
+}
+func (r *stubIncidentRepository) UpdateIncident(ctx context.Context, incident *domain.Incident) error {
+	return nil
+}
+func (r *stubIncidentRepository) DeleteIncident(ctx context.Context, id uuid.UUID) error { return nil }
+func (r *stubIncidentRepository) GetIncidentsSince(ctx context.Context, since time.Time) ([]*domain.Incident, error) {
+	return r.incidents, nil
+}
+
+func setupStatusService(t *testing.T, check health.Check, incidents ...*domain.Incident) *StatusService {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to create miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	checker := health.NewChecker(health.Config{})
+	checker.Register("database", check)
+
+	return NewStatusService(checker, &stubIncidentRepository{incidents: incidents}, client, StatusServiceConfig{HistoryDays: 7})
+}
+
+func TestStatusUptime(t *testing.T) {
+	failing := false
+	service := setupStatusService(t, func(ctx context.Context) error {
+		if failing {
+			return errors.New("down")
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	// Three good probes and one failed probe today
+	for i := 0; i < 3; i++ {
+		require.NoError(t, service.HandleProbeJob(ctx, nil))
+	}
+	failing = true
+	require.NoError(t, service.HandleProbeJob(ctx, nil))
+
+	page, err := service.Status(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, ServiceStatusOutage, page.Status)
+	require.Len(t, page.Components, 1)
+	component := page.Components[0]
+	assert.Equal(t, "database", component.Name)
+	assert.Equal(t, health.StatusDown, component.Status)
+	require.NotNil(t, component.Uptime7d)
+	assert.Equal(t, 75.0, *component.Uptime7d)
+	require.Len(t, component.History, 7)
+	assert.Nil(t, component.History[1].Uptime)
+}
+
+func TestStatusIncidents(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+
+	minor := &domain.Incident{Status: domain.IncidentStatusInvestigating, Impact: domain.IncidentImpactMinor}
+	page, err := setupStatusService(t, ok, minor).Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ServiceStatusDegraded, page.Status)
+
+	resolved := &domain.Incident{Status: domain.IncidentStatusResolved, Impact: domain.IncidentImpactCritical}
+	page, err = setupStatusService(t, ok, resolved).Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ServiceStatusOperational, page.Status)
+	assert.Len(t, page.Incidents, 1)
+}
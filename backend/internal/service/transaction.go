@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lordaris/resume_generator/internal/domain"
+)
+
+// withinTx runs fn in a transaction when transactor is set, and directly
+// otherwise
+func withinTx(ctx context.Context, transactor domain.Transactor, fn func(ctx context.Context) error) error {
+	if transactor == nil {
+		return fn(ctx)
+	}
+	return transactor.WithinTx(ctx, fn)
+}
@@ -0,0 +1,493 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/mail"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// User import statuses
+const (
+	UserImportStatusPending   = "pending"
+	UserImportStatusCompleted = "completed"
+	UserImportStatusFailed    = "failed"
+)
+
+// User import row outcomes
+const (
+	UserImportRowCreated = "created"
+	UserImportRowFailed  = "failed"
+)
+
+// JobTypeImportUsers is the job type for creating the accounts of a roster
+const JobTypeImportUsers = "user_import.run"
+
+// UserImportColumns lists the roster columns read. Only email is required;
+// first_name and last_name fill in the resume's personal details, and a row
+// giving one needs the other.
+var UserImportColumns = []string{"email", "first_name", "last_name", "resume_title", "target_job_title"}
+
+// UserImportService errors
+var (
+	ErrUserImportNotFound = errors.New("user import not found")
+	ErrInvalidRoster      = errors.New("invalid roster")
+)
+
+// UserImport describes a bulk import of accounts and its per-row results
+type UserImport struct {
+	ID          uuid.UUID       `json:"id"`
+	Status      string          `json:"status"`
+	RequestedBy uuid.UUID       `json:"requested_by"`
+	Total       int             `json:"total"`
+	Created     int             `json:"created"`
+	Failed      int             `json:"failed"`
+	Rows        []UserImportRow `json:"rows"`
+	RequestedAt time.Time       `json:"requested_at"`
+	CompletedAt time.Time       `json:"completed_at,omitzero"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// UserImportRow is the outcome of one roster row
+type UserImportRow struct {
+	// Line is the line of the row in the roster, counting the header
+	Line   int       `json:"line"`
+	Email  string    `json:"email"`
+	Status string    `json:"status"`
+	UserID uuid.UUID `json:"user_id,omitzero"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// userImportEntry is a parsed roster row
+type userImportEntry struct {
+	Line           int    `json:"line"`
+	Email          string `json:"email"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	ResumeTitle    string `json:"resume_title"`
+	TargetJobTitle string `json:"target_job_title"`
+}
+
+// userImportJobPayload identifies the import a job runs
+type userImportJobPayload struct {
+	ImportID uuid.UUID `json:"import_id"`
+}
+
+// UserImportServiceConfig contains configuration for the user import service
+type UserImportServiceConfig struct {
+	// MaxRows caps the rows of a single roster
+	MaxRows int
+	// ResultTTL is how long an import and its results are kept
+	ResultTTL time.Duration
+}
+
+// UserImportService bulk-creates accounts with a skeleton resume each from a
+// CSV roster. Rows are imported by a background job; each row succeeds or
+// fails on its own, and new users are emailed an invitation to set their
+// password through the password reset flow. Imports and their results are
+// kept in the cache, and the rows waiting to be imported in file storage.
+type UserImportService struct {
+	userRepo    domain.UserRepository
+	resumeRepo  domain.ResumeRepository
+	cache       cache.Cache
+	files       storage.Storage
+	queue       *jobs.Queue
+	mailService *MailService
+	transactor  domain.Transactor
+	config      UserImportServiceConfig
+}
+
+// NewUserImportService creates a new user import service
+func NewUserImportService(userRepo domain.UserRepository, resumeRepo domain.ResumeRepository, store cache.Cache, files storage.Storage, queue *jobs.Queue, mailService *MailService, config UserImportServiceConfig) *UserImportService {
+	// Set default values if not provided
+	if config.MaxRows == 0 {
+		config.MaxRows = 1000
+	}
+	if config.ResultTTL == 0 {
+		config.ResultTTL = 7 * 24 * time.Hour
+	}
+
+	return &UserImportService{
+		userRepo:    userRepo,
+		resumeRepo:  resumeRepo,
+		cache:       store,
+		files:       files,
+		queue:       queue,
+		mailService: mailService,
+		config:      config,
+	}
+}
+
+// SetTransactor creates each row's account and resume in one transaction,
+// so a row that fails part way leaves nothing behind
+func (s *UserImportService) SetTransactor(transactor domain.Transactor) {
+	s.transactor = transactor
+}
+
+// StartImport checks a roster's layout and queues its rows for import.
+// Problems with individual rows are reported in the import's results.
+func (s *UserImportService) StartImport(ctx context.Context, requestedBy uuid.UUID, roster io.Reader) (*UserImport, error) {
+	entries, err := s.parseRoster(roster)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &UserImport{
+		ID:          uuid.New(),
+		Status:      UserImportStatusPending,
+		RequestedBy: requestedBy,
+		Total:       len(entries),
+		Rows:        []UserImportRow{},
+		RequestedAt: time.Now().UTC(),
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.files.Put(ctx, userImportEntriesKey(imp.ID), bytes.NewReader(data), "application/json"); err != nil {
+		return nil, err
+	}
+	if err := s.save(ctx, imp); err != nil {
+		s.deleteEntries(ctx, imp.ID)
+		return nil, err
+	}
+
+	if _, err := s.queue.Enqueue(ctx, JobTypeImportUsers, userImportJobPayload{ImportID: imp.ID},
+		jobs.WithMaxAttempts(3), jobs.WithPriority(jobs.PriorityLow), jobs.WithOwner(requestedBy.String())); err != nil {
+		s.cache.Del(ctx, userImportKey(imp.ID))
+		s.deleteEntries(ctx, imp.ID)
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("import_id", imp.ID.String()).Str("requested_by", requestedBy.String()).Int("rows", imp.Total).Msg("User import queued")
+	return imp, nil
+}
+
+// GetImport returns an import with the results of the rows imported so far
+func (s *UserImportService) GetImport(ctx context.Context, id uuid.UUID) (*UserImport, error) {
+	data, err := s.cache.Get(ctx, userImportKey(id))
+	if err != nil {
+		if errors.Is(err, cache.ErrMiss) {
+			return nil, ErrUserImportNotFound
+		}
+		return nil, err
+	}
+
+	var imp UserImport
+	if err := json.Unmarshal(data, &imp); err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// ErrorReport returns the failed rows of an import as CSV
+func (s *UserImportService) ErrorReport(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	imp, err := s.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"line", "email", "error"})
+	for _, row := range imp.Rows {
+		if row.Status == UserImportRowFailed {
+			w.Write([]string{strconv.Itoa(row.Line), row.Email, row.Error})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// HandleImportJob imports the rows of a roster. Results are saved after every
+// row, so a retried job picks up where the last attempt stopped instead of
+// reporting the accounts it created as duplicates.
+func (s *UserImportService) HandleImportJob(ctx context.Context, job *jobs.Job) error {
+	var payload userImportJobPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return err
+	}
+
+	imp, err := s.GetImport(ctx, payload.ImportID)
+	if err != nil {
+		if errors.Is(err, ErrUserImportNotFound) {
+			// The import expired before it ran; there is nothing to retry
+			log.Ctx(ctx).Warn().Str("import_id", payload.ImportID.String()).Msg("User import not found")
+			return nil
+		}
+		return err
+	}
+	if imp.Status != UserImportStatusPending {
+		return nil
+	}
+
+	entries, err := s.loadEntries(ctx, imp.ID)
+	if err == nil {
+		err = s.importEntries(ctx, imp, entries)
+	}
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("import_id", imp.ID.String()).Msg("Failed to import users")
+		// Leave the import pending while the job still has retries left
+		if job.Attempts+1 < job.MaxAttempts {
+			return err
+		}
+		imp.Status = UserImportStatusFailed
+		imp.Error = "The import stopped before every row was processed"
+	} else {
+		imp.Status = UserImportStatusCompleted
+	}
+
+	imp.CompletedAt = time.Now().UTC()
+	if saveErr := s.save(ctx, imp); saveErr != nil {
+		return saveErr
+	}
+	s.deleteEntries(ctx, imp.ID)
+
+	log.Ctx(ctx).Info().Str("import_id", imp.ID.String()).Int("created", imp.Created).Int("failed", imp.Failed).Msg("User import finished")
+	return err
+}
+
+// importEntries imports the entries that have no result yet
+func (s *UserImportService) importEntries(ctx context.Context, imp *UserImport, entries []userImportEntry) error {
+	for _, entry := range entries[len(imp.Rows):] {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row, err := s.importEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+
+		imp.Rows = append(imp.Rows, row)
+		if row.Status == UserImportRowCreated {
+			imp.Created++
+		} else {
+			imp.Failed++
+		}
+		if err := s.save(ctx, imp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importEntry creates the account and skeleton resume of one row. Invalid
+// rows and taken addresses fail the row; other errors fail the job, which is
+// retried.
+func (s *UserImportService) importEntry(ctx context.Context, entry userImportEntry) (UserImportRow, error) {
+	row := UserImportRow{Line: entry.Line, Email: entry.Email}
+	fail := func(reason string) (UserImportRow, error) {
+		row.Status = UserImportRowFailed
+		row.Error = reason
+		return row, nil
+	}
+
+	metadata := domain.ResumeMetadata{Title: entry.ResumeTitle, TargetJobTitle: entry.TargetJobTitle}
+	var info *domain.PersonalInfo
+	if entry.FirstName != "" || entry.LastName != "" {
+		info = &domain.PersonalInfo{FirstName: entry.FirstName, LastName: entry.LastName, Email: entry.Email}
+	}
+
+	switch {
+	case entry.Email == "":
+		return fail("Email is required")
+	case !domain.EmailRegex.MatchString(entry.Email):
+		return fail("Invalid email format")
+	}
+	if err := metadata.Validate(); err != nil {
+		return fail(err.Error())
+	}
+	if info != nil {
+		if err := info.Validate(); err != nil {
+			return fail(err.Error())
+		}
+	}
+
+	now := time.Now().UTC()
+	user := &domain.User{
+		ID:        uuid.New(),
+		Email:     domain.NormalizeEmail(entry.Email),
+		Role:      "user",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.userRepo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+
+		metadata.BeforeSave()
+		resume, err := s.resumeRepo.CreateResume(ctx, user.ID, &metadata)
+		if err != nil {
+			return err
+		}
+		if info != nil {
+			info.BeforeSave()
+			return s.resumeRepo.SavePersonalInfo(ctx, resume.ID, info)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			return fail("An account with this email already exists")
+		}
+		return row, err
+	}
+
+	s.invite(ctx, user)
+
+	row.Status = UserImportRowCreated
+	row.UserID = user.ID
+	return row, nil
+}
+
+// invite emails a new user that an account was created for them. Imported
+// accounts have no password, so the user sets one through the password reset
+// flow. A delivery failure doesn't fail the row since the mail job is retried.
+func (s *UserImportService) invite(ctx context.Context, user *domain.User) {
+	if s.mailService == nil {
+		return
+	}
+
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "Your Resume Generator account",
+		Body: fmt.Sprintf("An account with a resume to get you started was created for you on Resume Generator.\n\n"+
+			"To sign in, request a password reset for %s and choose your password.", user.Email),
+	}
+	if err := s.mailService.Send(ctx, msg); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to queue import invitation")
+	}
+}
+
+// parseRoster reads the rows of a CSV roster. The header names the columns,
+// which may come in any order; unknown columns are ignored.
+func (s *UserImportService) parseRoster(roster io.Reader) ([]userImportEntry, error) {
+	r := csv.NewReader(roster)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the roster is empty", ErrInvalidRoster)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRoster, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if slices.Contains(UserImportColumns, name) {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, fmt.Errorf("%w: the header has no email column", ErrInvalidRoster)
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []userImportEntry
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRoster, err)
+		}
+		if len(entries) == s.config.MaxRows {
+			return nil, fmt.Errorf("%w: a roster can have at most %d rows", ErrInvalidRoster, s.config.MaxRows)
+		}
+
+		line, _ := r.FieldPos(0)
+		entries = append(entries, userImportEntry{
+			Line:           line,
+			Email:          field(record, "email"),
+			FirstName:      field(record, "first_name"),
+			LastName:       field(record, "last_name"),
+			ResumeTitle:    field(record, "resume_title"),
+			TargetJobTitle: field(record, "target_job_title"),
+		})
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: the roster has no rows", ErrInvalidRoster)
+	}
+
+	return entries, nil
+}
+
+// save stores the import and its results
+func (s *UserImportService) save(ctx context.Context, imp *UserImport) error {
+	data, err := json.Marshal(imp)
+	if err != nil {
+		return err
+	}
+	if err := s.cache.Set(ctx, userImportKey(imp.ID), data, s.config.ResultTTL); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("import_id", imp.ID.String()).Msg("Failed to store user import")
+		return err
+	}
+	return nil
+}
+
+// loadEntries reads the rows of an import's roster
+func (s *UserImportService) loadEntries(ctx context.Context, id uuid.UUID) ([]userImportEntry, error) {
+	object, err := s.files.Get(ctx, userImportEntriesKey(id))
+	if err != nil {
+		return nil, err
+	}
+	defer object.Body.Close()
+
+	var entries []userImportEntry
+	if err := json.NewDecoder(object.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// deleteEntries deletes the rows of an import's roster once they are no
+// longer needed. A failure only leaves an orphaned file, so it is logged.
+func (s *UserImportService) deleteEntries(ctx context.Context, id uuid.UUID) {
+	if err := s.files.Delete(ctx, userImportEntriesKey(id)); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("import_id", id.String()).Msg("Failed to delete user import roster")
+	}
+}
+
+// userImportKey returns the key holding an import and its results
+func userImportKey(id uuid.UUID) string {
+	return "user_import:" + id.String()
+}
+
+// userImportEntriesKey returns the storage key holding the rows of an
+// import's roster
+func userImportEntriesKey(id uuid.UUID) string {
+	return "imports/" + id.String() + ".json"
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/lordaris/resume_generator/internal/domain"
+	"github.com/lordaris/resume_generator/internal/jobs"
+	"github.com/lordaris/resume_generator/internal/repository"
+	"github.com/lordaris/resume_generator/pkg/cache"
+	"github.com/lordaris/resume_generator/pkg/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importUserRepository keeps created users in memory, failing once after
+// failAfter users have been created
+type importUserRepository struct {
+	domain.UserRepository
+	users     []*domain.User
+	failAfter int
+}
+
+func (r *importUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	if r.failAfter > 0 && len(r.users) == r.failAfter {
+		r.failAfter = 0
+		return errors.New("connection reset")
+	}
+	for _, existing := range r.users {
+		if existing.Email == user.Email {
+			return repository.ErrDuplicateEmail
+		}
+	}
+	r.users = append(r.users, user)
+	return nil
+}
+
+func newTestUserImportService(t *testing.T, users *importUserRepository, resumes *translationRepository) *UserImportService {
+	store, err := cache.NewMemory(cache.MemoryConfig{})
+	require.NoError(t, err)
+	mr := miniredis.RunT(t)
+	queue := jobs.NewQueue(jobs.QueueConfig{Redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})})
+	return NewUserImportService(users, resumes, store, storage.NewMemory(), queue, nil, UserImportServiceConfig{MaxRows: 5})
+}
+
+// runImportJob runs the job of an import as the worker would
+func runImportJob(t *testing.T, svc *UserImportService, importID uuid.UUID, attempts int) error {
+	payload, err := json.Marshal(userImportJobPayload{ImportID: importID})
+	require.NoError(t, err)
+	return svc.HandleImportJob(context.Background(), &jobs.Job{Type: JobTypeImportUsers, Payload: payload, Attempts: attempts, MaxAttempts: 3})
+}
+
+func TestUserImport(t *testing.T) {
+	ctx := context.Background()
+	users := &importUserRepository{users: []*domain.User{{ID: uuid.New(), Email: "taken@example.com"}}}
+	resumes := &translationRepository{resumes: map[uuid.UUID]*domain.Resume{}}
+	svc := newTestUserImportService(t, users, resumes)
+
+	roster := "Last_Name,email,First_Name,target_job_title,notes\n" +
+		"Lovelace,ada@example.com,Ada,Analyst,ignored\n" +
+		"\"\",grace@example.com,,,\n" +
+		",not-an-email,,,\n" +
+		"Turing,taken@example.com,Alan,,\n" +
+		",,Solo,,\n"
+	imp, err := svc.StartImport(ctx, uuid.New(), strings.NewReader(roster))
+	require.NoError(t, err)
+	assert.Equal(t, UserImportStatusPending, imp.Status)
+	assert.Equal(t, 5, imp.Total)
+
+	require.NoError(t, runImportJob(t, svc, imp.ID, 0))
+
+	imp, err = svc.GetImport(ctx, imp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, UserImportStatusCompleted, imp.Status)
+	assert.Equal(t, 2, imp.Created)
+	assert.Equal(t, 3, imp.Failed)
+	require.Len(t, imp.Rows, 5)
+	assert.Equal(t, UserImportRowCreated, imp.Rows[0].Status)
+	assert.Equal(t, UserImportRowCreated, imp.Rows[1].Status)
+	assert.Equal(t, "Invalid email format", imp.Rows[2].Error)
+	assert.Equal(t, "An account with this email already exists", imp.Rows[3].Error)
+	assert.Equal(t, "Email is required", imp.Rows[4].Error)
+
+	// Each account gets a skeleton resume, with personal details when named
+	require.Len(t, resumes.resumes, 2)
+	for _, resume := range resumes.resumes {
+		if resume.UserID == imp.Rows[0].UserID {
+			assert.Equal(t, "Analyst", resume.TargetJobTitle)
+			require.NotNil(t, resume.PersonalInfo)
+			assert.Equal(t, "Ada", resume.PersonalInfo.FirstName)
+		} else {
+			assert.Nil(t, resume.PersonalInfo)
+		}
+	}
+	assert.Empty(t, users.users[1].PasswordHash)
+
+	report, err := svc.ErrorReport(ctx, imp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "line,email,error\n"+
+		"4,not-an-email,Invalid email format\n"+
+		"5,taken@example.com,An account with this email already exists\n"+
+		"6,,Email is required\n", string(report))
+}
+
+func TestUserImportResumesAfterRetry(t *testing.T) {
+	ctx := context.Background()
+	users := &importUserRepository{failAfter: 1}
+	svc := newTestUserImportService(t, users, &translationRepository{resumes: map[uuid.UUID]*domain.Resume{}})
+
+	imp, err := svc.StartImport(ctx, uuid.New(), strings.NewReader("email\na@example.com\nb@example.com\n"))
+	require.NoError(t, err)
+
+	// The first attempt stops at the second row and stays pending
+	require.Error(t, runImportJob(t, svc, imp.ID, 0))
+	imp, err = svc.GetImport(ctx, imp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, UserImportStatusPending, imp.Status)
+	assert.Len(t, imp.Rows, 1)
+
+	// The retry only imports the rows left
+	require.NoError(t, runImportJob(t, svc, imp.ID, 1))
+	imp, err = svc.GetImport(ctx, imp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, UserImportStatusCompleted, imp.Status)
+	assert.Equal(t, 2, imp.Created)
+	assert.Len(t, users.users, 2)
+}
+
+func TestUserImportInvalidRoster(t *testing.T) {
+	svc := newTestUserImportService(t, &importUserRepository{}, nil)
+
+	for name, roster := range map[string]string{
+		"empty":        "",
+		"no email":     "first_name,last_name\nAda,Lovelace\n",
+		"header only":  "email\n",
+		"too many":     "email\n" + strings.Repeat("a@example.com\n", 6),
+		"broken quote": "email\n\"a@example.com\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.StartImport(context.Background(), uuid.New(), strings.NewReader(roster))
+			assert.ErrorIs(t, err, ErrInvalidRoster)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Each resume has at most one personal info record; the upsert in the
+-- repository relies on this constraint for ON CONFLICT (resume_id). The most
+-- recently updated record is kept; rows written together tie on updated_at,
+-- so the ID breaks the tie.
+DELETE FROM personal_info p
+USING personal_info newer
+WHERE p.resume_id = newer.resume_id
+AND (p.updated_at, p.id) < (newer.updated_at, newer.id);
+
+DROP INDEX IF EXISTS idx_personal_info_resume_id;
+ALTER TABLE personal_info ADD CONSTRAINT uq_personal_info_resume_id UNIQUE (resume_id);
+
+COMMENT ON CONSTRAINT uq_personal_info_resume_id ON personal_info IS 'A resume has a single personal info record';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE personal_info DROP CONSTRAINT IF EXISTS uq_personal_info_resume_id;
+CREATE INDEX idx_personal_info_resume_id ON personal_info(resume_id);
@@ -0,0 +1,48 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Resume documents store each resume as a single JSONB document, used when
+-- the server runs with RESUME_STORAGE=document instead of the section tables
+CREATE TABLE resume_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    document JSONB NOT NULL DEFAULT '{"schema_version": 1}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    -- Foreign key to users table
+    CONSTRAINT fk_resume_documents_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Document schema: an object with a version and optional section arrays
+    CONSTRAINT chk_resume_documents_schema CHECK (
+        jsonb_typeof(document) = 'object'
+        AND jsonb_typeof(document->'schema_version') = 'number'
+        AND (document->'personal_info' IS NULL OR jsonb_typeof(document->'personal_info') IN ('object', 'null'))
+        AND (document->'education' IS NULL OR jsonb_typeof(document->'education') = 'array')
+        AND (document->'experience' IS NULL OR jsonb_typeof(document->'experience') = 'array')
+        AND (document->'skills' IS NULL OR jsonb_typeof(document->'skills') = 'array')
+        AND (document->'projects' IS NULL OR jsonb_typeof(document->'projects') = 'array')
+        AND (document->'certifications' IS NULL OR jsonb_typeof(document->'certifications') = 'array')
+    )
+);
+
+-- Add index on user_id for faster lookups of a user's resumes
+CREATE INDEX idx_resume_documents_user_id ON resume_documents(user_id);
+
+-- GIN index for containment searches, including locating a section entry by its ID
+CREATE INDEX idx_resume_documents_document ON resume_documents USING GIN (document jsonb_path_ops);
+
+-- Add comments on table and columns
+COMMENT ON TABLE resume_documents IS 'Stores resumes as single JSONB documents (document storage mode)';
+COMMENT ON COLUMN resume_documents.id IS 'Unique identifier for the resume';
+COMMENT ON COLUMN resume_documents.user_id IS 'Foreign key to the user who owns this resume';
+COMMENT ON COLUMN resume_documents.document IS 'Resume content with all sections; every section entry carries its own id';
+COMMENT ON COLUMN resume_documents.created_at IS 'Timestamp when the resume was created';
+COMMENT ON COLUMN resume_documents.updated_at IS 'Timestamp when the resume was last modified';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_documents_document;
+DROP INDEX IF EXISTS idx_resume_documents_user_id;
+DROP TABLE IF EXISTS resume_documents;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Resume events are an append-only log of every resume mutation. Replaying
+-- a resume's events in version order yields its state at any point in time.
+-- There is no foreign key to resumes so the history survives deletion and
+-- covers both storage modes.
+CREATE TABLE resume_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    resume_id UUID NOT NULL,
+    version BIGINT NOT NULL,
+    entity VARCHAR(50) NOT NULL,
+    entity_id UUID,
+    op VARCHAR(20) NOT NULL,
+    payload JSONB NOT NULL DEFAULT 'null',
+    actor_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    -- Versions are a gapless sequence per resume
+    CONSTRAINT uq_resume_events_version UNIQUE (resume_id, version),
+
+    -- Actors may be deleted without losing the history
+    CONSTRAINT fk_resume_events_actor FOREIGN KEY (actor_id)
+        REFERENCES users(id) ON DELETE SET NULL,
+
+    CONSTRAINT chk_resume_events_op CHECK (op IN ('create', 'update', 'delete'))
+);
+
+-- Add index on actor_id for audit lookups by user
+CREATE INDEX idx_resume_events_actor_id ON resume_events(actor_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE resume_events IS 'Append-only log of resume mutations';
+COMMENT ON COLUMN resume_events.version IS 'Per-resume sequence number starting at 1';
+COMMENT ON COLUMN resume_events.entity IS 'Mutated entity: resume, personal_info, education, experience, skill, project or certification';
+COMMENT ON COLUMN resume_events.entity_id IS 'ID of the mutated section entry, NULL for resume-level entities';
+COMMENT ON COLUMN resume_events.op IS 'Operation: create, update or delete';
+COMMENT ON COLUMN resume_events.payload IS 'Entity state after the operation, JSON null for deletes';
+COMMENT ON COLUMN resume_events.actor_id IS 'User who made the change';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS resume_events;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Create email_changes table for pending email address changes. The new
+-- address is only written to users once the confirmation token is used.
+CREATE TABLE email_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    token TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    confirmed_at TIMESTAMPTZ,
+
+    CONSTRAINT fk_email_changes_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create unique index on token for faster lookups and to ensure uniqueness
+CREATE UNIQUE INDEX idx_email_changes_token ON email_changes(token);
+
+-- Create index on user_id for faster lookups
+CREATE INDEX idx_email_changes_user_id ON email_changes(user_id);
+
+-- Create index on expires_at for cleanup tasks
+CREATE INDEX idx_email_changes_expires_at ON email_changes(expires_at);
+
+-- Add comments
+COMMENT ON TABLE email_changes IS 'Stores pending email address change requests';
+COMMENT ON COLUMN email_changes.id IS 'Unique identifier for the email change request';
+COMMENT ON COLUMN email_changes.user_id IS 'Reference to the user changing their email';
+COMMENT ON COLUMN email_changes.new_email IS 'Requested email address, applied on confirmation';
+COMMENT ON COLUMN email_changes.token IS 'JWT token sent to the new address to confirm the change';
+COMMENT ON COLUMN email_changes.expires_at IS 'Expiration time for the email change request';
+COMMENT ON COLUMN email_changes.created_at IS 'Time when the email change request was created';
+COMMENT ON COLUMN email_changes.confirmed_at IS 'Time when the change was confirmed (NULL if pending)';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_email_changes_expires_at;
+DROP INDEX IF EXISTS idx_email_changes_user_id;
+DROP INDEX IF EXISTS idx_email_changes_token;
+DROP TABLE IF EXISTS email_changes;
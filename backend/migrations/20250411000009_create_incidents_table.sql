@@ -0,0 +1,42 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Create incidents table for outages published on the public status page
+CREATE TABLE incidents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'investigating',
+    impact VARCHAR(20) NOT NULL DEFAULT 'minor',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_incidents_created_by FOREIGN KEY (created_by)
+        REFERENCES users(id) ON DELETE SET NULL,
+
+    CONSTRAINT chk_incidents_status CHECK (status IN ('investigating', 'identified', 'monitoring', 'resolved')),
+    CONSTRAINT chk_incidents_impact CHECK (impact IN ('minor', 'major', 'critical')),
+    CONSTRAINT chk_incidents_resolved CHECK ((status = 'resolved') = (resolved_at IS NOT NULL))
+);
+
+-- Create index on started_at for listing recent incidents
+CREATE INDEX idx_incidents_started_at ON incidents(started_at DESC);
+
+-- Create partial index for unresolved incidents
+CREATE INDEX idx_incidents_unresolved ON incidents(started_at) WHERE resolved_at IS NULL;
+
+-- Add comments
+COMMENT ON TABLE incidents IS 'Service incidents managed by admins and shown on the status page';
+COMMENT ON COLUMN incidents.status IS 'Incident status: investigating, identified, monitoring or resolved';
+COMMENT ON COLUMN incidents.impact IS 'Incident impact: minor, major or critical';
+COMMENT ON COLUMN incidents.resolved_at IS 'Time the incident was resolved (NULL while ongoing)';
+COMMENT ON COLUMN incidents.created_by IS 'Admin who opened the incident';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_incidents_unresolved;
+DROP INDEX IF EXISTS idx_incidents_started_at;
+DROP TABLE IF EXISTS incidents;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Resume metadata distinguishes a user's resumes from each other in listings
+ALTER TABLE resumes
+    ADD COLUMN title VARCHAR(200) NOT NULL DEFAULT '',
+    ADD COLUMN target_job_title VARCHAR(200) NOT NULL DEFAULT '',
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+ALTER TABLE resume_documents
+    ADD COLUMN title VARCHAR(200) NOT NULL DEFAULT '',
+    ADD COLUMN target_job_title VARCHAR(200) NOT NULL DEFAULT '',
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- GIN indexes for filtering a resume list by tag
+CREATE INDEX idx_resumes_tags ON resumes USING GIN (tags);
+CREATE INDEX idx_resume_documents_tags ON resume_documents USING GIN (tags);
+
+-- Add comments on columns
+COMMENT ON COLUMN resumes.title IS 'Name the user gives the resume, shown in resume lists';
+COMMENT ON COLUMN resumes.target_job_title IS 'Role the resume is tailored for';
+COMMENT ON COLUMN resumes.tags IS 'Free-form lowercase labels for organizing resumes';
+COMMENT ON COLUMN resumes.updated_at IS 'Timestamp when the resume metadata was last modified';
+COMMENT ON COLUMN resume_documents.title IS 'Name the user gives the resume, shown in resume lists';
+COMMENT ON COLUMN resume_documents.target_job_title IS 'Role the resume is tailored for';
+COMMENT ON COLUMN resume_documents.tags IS 'Free-form lowercase labels for organizing resumes';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_documents_tags;
+DROP INDEX IF EXISTS idx_resumes_tags;
+
+ALTER TABLE resume_documents
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS target_job_title,
+    DROP COLUMN IF EXISTS title;
+
+ALTER TABLE resumes
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS target_job_title,
+    DROP COLUMN IF EXISTS title;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Phone numbers are stored in E.164; the country is kept so numbers can be
+-- edited and displayed in the owner's national format
+ALTER TABLE personal_info ADD COLUMN phone_country VARCHAR(2);
+
+COMMENT ON COLUMN personal_info.phone_country IS 'ISO 3166-1 alpha-2 country phone numbers are entered and displayed for';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE personal_info DROP COLUMN IF EXISTS phone_country;
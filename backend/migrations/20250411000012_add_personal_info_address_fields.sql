@@ -0,0 +1,21 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- International addresses need a second line, a region and a postal code;
+-- addresses that don't fit the structured fields are kept free-form
+ALTER TABLE personal_info
+    ADD COLUMN address_line2 TEXT,
+    ADD COLUMN region TEXT,
+    ADD COLUMN postal_code VARCHAR(20),
+    ADD COLUMN address_free_form TEXT;
+
+COMMENT ON COLUMN personal_info.region IS 'State, province or prefecture';
+COMMENT ON COLUMN personal_info.address_free_form IS 'Multi-line address used instead of the structured fields when set';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE personal_info
+    DROP COLUMN IF EXISTS address_free_form,
+    DROP COLUMN IF EXISTS postal_code,
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS address_line2;
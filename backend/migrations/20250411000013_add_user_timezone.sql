@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Timestamps are stored in UTC; the user's IANA time zone is kept so times can
+-- be shown to them in local time
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+
+COMMENT ON COLUMN users.timezone IS 'IANA time zone name times are displayed in for the user, such as Europe/Berlin';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Each resume is written in one language; translations are separate resumes
+-- that point at the resume they were translated from
+ALTER TABLE resumes
+    ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT 'en',
+    ADD COLUMN translation_of UUID REFERENCES resumes(id) ON DELETE SET NULL;
+
+ALTER TABLE resume_documents
+    ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT 'en',
+    ADD COLUMN translation_of UUID REFERENCES resume_documents(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_resumes_translation_of ON resumes(translation_of);
+CREATE INDEX idx_resume_documents_translation_of ON resume_documents(translation_of);
+
+COMMENT ON COLUMN resumes.language IS 'BCP 47 language tag the resume is written in';
+COMMENT ON COLUMN resumes.translation_of IS 'Resume this one is a translated copy of';
+COMMENT ON COLUMN resume_documents.language IS 'BCP 47 language tag the resume is written in';
+COMMENT ON COLUMN resume_documents.translation_of IS 'Resume this one is a translated copy of';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_documents_translation_of;
+DROP INDEX IF EXISTS idx_resumes_translation_of;
+
+ALTER TABLE resume_documents
+    DROP COLUMN IF EXISTS translation_of,
+    DROP COLUMN IF EXISTS language;
+
+ALTER TABLE resumes
+    DROP COLUMN IF EXISTS translation_of,
+    DROP COLUMN IF EXISTS language;
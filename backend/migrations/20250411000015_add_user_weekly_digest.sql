@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Users opt in to a weekly email summarizing their resume activity.
+-- digest_sent_at spaces the digests a week apart however often the job runs.
+ALTER TABLE users ADD COLUMN weekly_digest BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN digest_sent_at TIMESTAMPTZ;
+
+-- Add partial index for finding users whose digest is due
+CREATE INDEX idx_users_weekly_digest ON users(digest_sent_at) WHERE weekly_digest;
+
+COMMENT ON COLUMN users.weekly_digest IS 'Whether the user receives the weekly activity digest';
+COMMENT ON COLUMN users.digest_sent_at IS 'When the last weekly digest was sent, NULL if never';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_users_weekly_digest;
+ALTER TABLE users DROP COLUMN IF EXISTS digest_sent_at;
+ALTER TABLE users DROP COLUMN IF EXISTS weekly_digest;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Share links give read access to a resume to anyone holding the token.
+-- There is no foreign key to resumes because resumes live in either of two
+-- tables depending on the storage mode; links to deleted resumes stop working.
+CREATE TABLE resume_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    resume_id UUID NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    passphrase_hash TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_resume_shares_token UNIQUE (token)
+);
+
+-- Add index on resume_id for listing a resume's links
+CREATE INDEX idx_resume_shares_resume_id ON resume_shares(resume_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE resume_shares IS 'Links sharing a resume with people who have no account';
+COMMENT ON COLUMN resume_shares.token IS 'Random URL-safe token identifying the link';
+COMMENT ON COLUMN resume_shares.passphrase_hash IS 'Argon2id hash of the passphrase viewers must enter, NULL for open links';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_shares_resume_id;
+DROP TABLE IF EXISTS resume_shares;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Share links can stop working after a number of views or at a set time.
+-- Views are counted in Redis so concurrent viewers can't exceed the limit.
+ALTER TABLE resume_shares ADD COLUMN max_views INTEGER;
+ALTER TABLE resume_shares ADD COLUMN expires_at TIMESTAMPTZ;
+
+ALTER TABLE resume_shares ADD CONSTRAINT chk_resume_shares_max_views CHECK (max_views IS NULL OR max_views > 0);
+
+COMMENT ON COLUMN resume_shares.max_views IS 'Number of views after which the link stops working, NULL for no limit';
+COMMENT ON COLUMN resume_shares.expires_at IS 'Time the link stops working, NULL if it never expires';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE resume_shares DROP CONSTRAINT IF EXISTS chk_resume_shares_max_views;
+ALTER TABLE resume_shares DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE resume_shares DROP COLUMN IF EXISTS max_views;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Share links can hide contact details and whole sections from viewers
+ALTER TABLE resume_shares ADD COLUMN hidden TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN resume_shares.hidden IS 'Fields and sections left out when the resume is viewed or exported through the link';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE resume_shares DROP COLUMN IF EXISTS hidden;
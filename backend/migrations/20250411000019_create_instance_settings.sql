@@ -0,0 +1,17 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Settings admins change at runtime, one JSON document per key
+CREATE TABLE IF NOT EXISTS instance_settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE instance_settings IS 'Runtime settings of this deployment, edited by admins';
+COMMENT ON COLUMN instance_settings.updated_by IS 'Admin who last changed the setting';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS instance_settings;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Content-Security-Policy violations reported by browsers, counted per page,
+-- directive, blocked source and disposition
+CREATE TABLE IF NOT EXISTS csp_violations (
+    id UUID PRIMARY KEY,
+    document_uri TEXT NOT NULL,
+    violated_directive VARCHAR(100) NOT NULL,
+    blocked_uri TEXT NOT NULL,
+    disposition VARCHAR(20) NOT NULL,
+    source_file TEXT NOT NULL DEFAULT '',
+    line_number INTEGER NOT NULL DEFAULT 0,
+    column_number INTEGER NOT NULL DEFAULT 0,
+    count BIGINT NOT NULL DEFAULT 1,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (document_uri, violated_directive, blocked_uri, disposition)
+);
+
+CREATE INDEX IF NOT EXISTS idx_csp_violations_last_seen_at ON csp_violations(last_seen_at);
+
+COMMENT ON TABLE csp_violations IS 'Content-Security-Policy violation reports, aggregated';
+COMMENT ON COLUMN csp_violations.document_uri IS 'Page the violation happened on, without query or fragment';
+COMMENT ON COLUMN csp_violations.blocked_uri IS 'Origin of the blocked resource, or a keyword such as inline or eval';
+COMMENT ON COLUMN csp_violations.source_file IS 'Script that caused the latest report, if known';
+COMMENT ON COLUMN csp_violations.count IS 'Number of reports received';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS csp_violations;
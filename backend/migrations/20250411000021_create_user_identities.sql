@@ -0,0 +1,24 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Accounts at OAuth providers that users sign in with
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+COMMENT ON TABLE user_identities IS 'OAuth provider accounts linked to users';
+COMMENT ON COLUMN user_identities.subject IS 'Stable ID of the account at the provider';
+COMMENT ON COLUMN user_identities.email IS 'Verified email of the provider account at the last sign-in';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS user_identities;
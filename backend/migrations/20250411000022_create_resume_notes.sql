@@ -0,0 +1,30 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Private notes are encrypted by the client before upload; the server only
+-- keeps the ciphertext and what the client needs to decrypt it with its own
+-- key. Each save adds a version. Like share links there is no foreign key to
+-- resumes, which live in either of two tables; notes of deleted resumes can't
+-- be reached and go with the account.
+CREATE TABLE IF NOT EXISTS resume_notes (
+    resume_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    algorithm VARCHAR(50) NOT NULL,
+    key_id VARCHAR(100) NOT NULL DEFAULT '',
+    nonce BYTEA NOT NULL,
+    ciphertext BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (resume_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resume_notes_user_id ON resume_notes(user_id);
+
+COMMENT ON TABLE resume_notes IS 'Versions of client-side encrypted private notes on resumes';
+COMMENT ON COLUMN resume_notes.algorithm IS 'Cipher the client encrypted with, such as AES-256-GCM';
+COMMENT ON COLUMN resume_notes.key_id IS 'Client reference to the key, such as a key derivation salt';
+COMMENT ON COLUMN resume_notes.nonce IS 'Nonce or IV the client encrypted with';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS resume_notes;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Personal API keys let scripts call the API as a user without signing in.
+-- Only a hash of each key is stored; the key itself is shown once.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+COMMENT ON TABLE api_keys IS 'Personal API keys for programmatic access';
+COMMENT ON COLUMN api_keys.prefix IS 'Start of the key, shown so users can tell their keys apart';
+COMMENT ON COLUMN api_keys.key_hash IS 'Hex SHA-256 hash of the key';
+COMMENT ON COLUMN api_keys.scopes IS 'What the key may do, such as resumes:read';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS api_keys;
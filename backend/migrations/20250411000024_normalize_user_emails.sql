@@ -0,0 +1,49 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Emails are compared case-insensitively. Addresses were never verified, so
+-- accounts whose addresses differ only in case or surrounding space may
+-- belong to different people and are not merged here: the oldest keeps the
+-- address, and the others keep their data but move to a placeholder address
+-- until their owner merges them into the oldest, proving they own both.
+CREATE TABLE IF NOT EXISTS user_email_conflicts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_email_conflicts_email ON user_email_conflicts (lower(email));
+
+COMMENT ON TABLE user_email_conflicts IS 'Accounts whose address collided with an older account''s when emails became case-insensitive';
+COMMENT ON COLUMN user_email_conflicts.email IS 'The address the account had before it moved to a placeholder';
+
+WITH duplicates AS (
+    SELECT id, email, first_value(id) OVER (PARTITION BY lower(trim(email)) ORDER BY created_at, id) AS keeper_id
+    FROM users
+)
+INSERT INTO user_email_conflicts (user_id, email)
+SELECT id, email FROM duplicates WHERE id <> keeper_id;
+
+-- The .invalid top-level domain never resolves, so nothing is sent there
+UPDATE users SET email = users.id::text || '@email-conflict.invalid'
+FROM user_email_conflicts c
+WHERE users.id = c.user_id;
+
+UPDATE users SET email = lower(trim(email)) WHERE email <> lower(trim(email));
+
+-- Lookups compare lower(email), which this index serves and keeps unique
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- Normalized addresses stay lowercase; accounts not yet merged get their
+-- address back.
+DROP INDEX IF EXISTS idx_users_email_lower;
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+
+UPDATE users SET email = c.email
+FROM user_email_conflicts c
+WHERE users.id = c.user_id;
+
+DROP TABLE IF EXISTS user_email_conflicts;
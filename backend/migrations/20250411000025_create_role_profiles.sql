@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Role profiles saved by admins. A row replaces the built-in profile with the
+-- same slug, or adds a role; deleting it restores the built-in profile.
+CREATE TABLE IF NOT EXISTS role_profiles (
+    slug VARCHAR(100) PRIMARY KEY,
+    profile JSONB NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE role_profiles IS 'Admin overrides of the role profiles resumes are compared against';
+COMMENT ON COLUMN role_profiles.profile IS 'Name, expected skills and sections, and minimum experience of the role';
+COMMENT ON COLUMN role_profiles.updated_by IS 'Admin who last saved the profile';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS role_profiles;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Where imported resume entries came from. Entries live in several tables, or
+-- in the resume document, so they are named by entity and ID rather than
+-- referenced; rows go with their resume.
+CREATE TABLE IF NOT EXISTS entry_provenance (
+    resume_id UUID NOT NULL REFERENCES resumes(id) ON DELETE CASCADE,
+    entity VARCHAR(30) NOT NULL,
+    entity_id UUID NOT NULL,
+    source VARCHAR(30) NOT NULL,
+    import_id UUID NOT NULL,
+    source_ref VARCHAR(500),
+    original JSONB NOT NULL,
+    imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    synced_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (resume_id, entity, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entry_provenance_import_id ON entry_provenance(import_id);
+
+COMMENT ON TABLE entry_provenance IS 'Source of each imported resume entry';
+COMMENT ON COLUMN entry_provenance.import_id IS 'Shared by the entries imported together';
+COMMENT ON COLUMN entry_provenance.source_ref IS 'Identifier of the entry at its source, such as a GitHub repository';
+COMMENT ON COLUMN entry_provenance.original IS 'The entry as imported or last re-synced, for reverting edits';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS entry_provenance;
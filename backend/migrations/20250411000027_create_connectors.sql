@@ -0,0 +1,25 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE IF NOT EXISTS connectors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    resume_id UUID NOT NULL REFERENCES resumes(id) ON DELETE CASCADE,
+    provider VARCHAR(30) NOT NULL,
+    account VARCHAR(255) NOT NULL,
+    last_synced_at TIMESTAMPTZ,
+    conflicts JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (resume_id, provider, account)
+);
+
+CREATE INDEX IF NOT EXISTS idx_connectors_user_id ON connectors(user_id);
+
+COMMENT ON TABLE connectors IS 'Accounts at external sources whose entries are synced into a resume';
+COMMENT ON COLUMN connectors.account IS 'The user''s name at the provider';
+COMMENT ON COLUMN connectors.conflicts IS 'Entries the last sync found changed both locally and upstream';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS connectors;
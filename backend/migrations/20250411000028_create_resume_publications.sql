@@ -0,0 +1,26 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Published resumes are public pages at a vanity slug. Like share links there
+-- is no foreign key to resumes, which live in either of two tables; pages of
+-- deleted resumes stop working and go with the account.
+CREATE TABLE IF NOT EXISTS resume_publications (
+    resume_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(50) NOT NULL,
+    hidden TEXT[] NOT NULL DEFAULT '{}',
+    published_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_resume_publications_slug UNIQUE (slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resume_publications_user_id ON resume_publications(user_id);
+
+COMMENT ON TABLE resume_publications IS 'Resumes published as public pages';
+COMMENT ON COLUMN resume_publications.slug IS 'Lowercase vanity name the page is served at, under /r/';
+COMMENT ON COLUMN resume_publications.hidden IS 'Fields and sections the public page leaves out';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS resume_publications;
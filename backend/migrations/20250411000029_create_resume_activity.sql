@@ -0,0 +1,29 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Resume activity records what happens to a resume besides edits, which are
+-- in resume_events: exports, share links and their views, publishing and
+-- notes. Like resume_events there is no foreign key to resumes, so the
+-- history covers both storage modes.
+CREATE TABLE IF NOT EXISTS resume_activity (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    resume_id UUID NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Activity feeds page through both tables newest first
+CREATE INDEX IF NOT EXISTS idx_resume_activity_feed ON resume_activity(resume_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_resume_events_feed ON resume_events(resume_id, created_at DESC, id DESC);
+
+COMMENT ON TABLE resume_activity IS 'What happened to resumes besides edits, for activity feeds';
+COMMENT ON COLUMN resume_activity.kind IS 'Activity: export, email, share_create, share_revoke, share_view, publish, unpublish, page_view or note';
+COMMENT ON COLUMN resume_activity.actor_id IS 'User responsible, NULL for anonymous viewers';
+COMMENT ON COLUMN resume_activity.details IS 'Details of the activity, such as the format of an export';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_events_feed;
+DROP TABLE IF EXISTS resume_activity;
@@ -0,0 +1,45 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Service accounts let integrations call the admin API within their scopes
+-- without acting as a user. Each has one key, of which only a hash is stored.
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_service_accounts_key_hash UNIQUE (key_hash)
+);
+
+-- The audit trail has no foreign key to service_accounts, so it outlives the
+-- accounts it describes
+CREATE TABLE IF NOT EXISTS service_account_audit (
+    id UUID PRIMARY KEY,
+    service_account_id UUID NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(30) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_account_audit_account ON service_account_audit(service_account_id, created_at DESC);
+
+COMMENT ON TABLE service_accounts IS 'Machine accounts for integrations, managed by admins';
+COMMENT ON COLUMN service_accounts.prefix IS 'Start of the key, shown so admins can tell keys apart';
+COMMENT ON COLUMN service_accounts.key_hash IS 'Hex SHA-256 hash of the key';
+COMMENT ON COLUMN service_accounts.scopes IS 'What the account may do, such as metrics:read';
+COMMENT ON TABLE service_account_audit IS 'What admins did to service accounts and the requests the accounts made';
+COMMENT ON COLUMN service_account_audit.actor_id IS 'Admin who acted, NULL for requests made by the account';
+COMMENT ON COLUMN service_account_audit.action IS 'create, rotate_key, delete or request';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS service_account_audit;
+DROP TABLE IF EXISTS service_accounts;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- A user may add one phone number. Once verified, it can receive password
+-- reset codes by SMS.
+CREATE TABLE IF NOT EXISTS user_phones (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    phone VARCHAR(16) NOT NULL,
+    verified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE user_phones IS 'Phone numbers users receive SMS codes at';
+COMMENT ON COLUMN user_phones.phone IS 'Number in E.164 format';
+COMMENT ON COLUMN user_phones.verified_at IS 'When the user confirmed a code sent to the number, NULL until then';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS user_phones;
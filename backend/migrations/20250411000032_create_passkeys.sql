@@ -0,0 +1,31 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Passkeys are WebAuthn credentials users sign in with instead of a
+-- password, or confirm password logins with
+CREATE TABLE IF NOT EXISTS passkeys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    credential_id BYTEA NOT NULL,
+    credential JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+
+    CONSTRAINT uq_passkeys_credential_id UNIQUE (credential_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_passkeys_user_id ON passkeys(user_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS passkey_required BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON TABLE passkeys IS 'WebAuthn credentials users sign in with';
+COMMENT ON COLUMN passkeys.name IS 'Name the user gave the passkey, such as the device it is on';
+COMMENT ON COLUMN passkeys.credential_id IS 'Credential ID the authenticator chose';
+COMMENT ON COLUMN passkeys.credential IS 'Public key, sign count and flags of the credential';
+COMMENT ON COLUMN users.passkey_required IS 'Whether password logins must be confirmed with a passkey';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE users DROP COLUMN IF EXISTS passkey_required;
+DROP TABLE IF EXISTS passkeys;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Automation platforms poll a user's changes across all their resumes, so
+-- each event records the resume's owner and a position in the log of every
+-- resume. The owner is kept after the resume is deleted, so deletes are
+-- polled too.
+ALTER TABLE resume_events ADD COLUMN owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE resume_events ADD COLUMN seq BIGINT;
+
+-- Events of resumes deleted before owners were recorded keep no owner
+UPDATE resume_events e SET owner_id = COALESCE(
+    (SELECT user_id FROM resumes WHERE id = e.resume_id),
+    (SELECT user_id FROM resume_documents WHERE id = e.resume_id)
+);
+
+-- Existing events are numbered in the order they were recorded
+UPDATE resume_events e SET seq = n.seq
+FROM (SELECT id, row_number() OVER (ORDER BY created_at, id) AS seq FROM resume_events) n
+WHERE e.id = n.id;
+
+ALTER TABLE resume_events ALTER COLUMN seq SET NOT NULL;
+ALTER TABLE resume_events ALTER COLUMN seq ADD GENERATED ALWAYS AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('resume_events', 'seq'), COALESCE(MAX(seq), 0) + 1, false) FROM resume_events;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resume_events_owner_seq ON resume_events(owner_id, seq);
+
+COMMENT ON COLUMN resume_events.owner_id IS 'Owner of the resume when the event was recorded';
+COMMENT ON COLUMN resume_events.seq IS 'Position in the log of all resumes, for polling feeds';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_events_owner_seq;
+ALTER TABLE resume_events DROP COLUMN IF EXISTS seq;
+ALTER TABLE resume_events DROP COLUMN IF EXISTS owner_id;
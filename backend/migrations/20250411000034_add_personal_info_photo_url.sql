@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- The photo is uploaded separately from the rest of the personal info, which
+-- leaves it unchanged when saved
+ALTER TABLE personal_info ADD COLUMN photo_url TEXT;
+
+COMMENT ON COLUMN personal_info.photo_url IS 'URL of the uploaded profile photo';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE personal_info DROP COLUMN IF EXISTS photo_url;
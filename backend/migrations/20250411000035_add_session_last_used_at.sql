@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Refreshing a session now rotates its token in place, so created_at keeps
+-- the time of the login and last_used_at tracks activity for idle timeouts
+ALTER TABLE sessions ADD COLUMN last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+UPDATE sessions SET last_used_at = created_at;
+
+COMMENT ON COLUMN sessions.last_used_at IS 'Time when the session was last refreshed, or created';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE sessions DROP COLUMN IF EXISTS last_used_at;
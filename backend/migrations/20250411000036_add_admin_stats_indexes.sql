@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- The admin statistics count signups per week and exports per day over
+-- recent weeks, scanning only the rows in range
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+CREATE INDEX IF NOT EXISTS idx_resume_activity_exports ON resume_activity(created_at) WHERE kind = 'export';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_activity_exports;
+DROP INDEX IF EXISTS idx_users_created_at;
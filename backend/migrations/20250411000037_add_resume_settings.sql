@@ -0,0 +1,21 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Formatting preferences of each resume, honored by every export: date
+-- style, phone display and paper size. Unset preferences follow the locale
+-- of the resume's language.
+ALTER TABLE resumes ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE resume_documents ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
+
+-- The locale new resumes take their formatting preferences from
+ALTER TABLE users ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN resumes.settings IS 'Formatting preferences of exports, such as {"paper_size": "letter"}';
+COMMENT ON COLUMN resume_documents.settings IS 'Formatting preferences of exports, such as {"paper_size": "letter"}';
+COMMENT ON COLUMN users.locale IS 'BCP 47 locale such as en-US new resumes are formatted for, empty if unset';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
+ALTER TABLE resume_documents DROP COLUMN IF EXISTS settings;
+ALTER TABLE resumes DROP COLUMN IF EXISTS settings;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Roles users are given and the permissions each grants beyond a user's own
+-- resumes and account. The admin role grants every permission whatever its
+-- row lists.
+CREATE TABLE IF NOT EXISTS user_roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE user_roles IS 'Roles users are given, with the permissions they grant';
+COMMENT ON COLUMN user_roles.permissions IS 'Permissions such as resumes:read:any and users:manage';
+
+-- The two roles users had before permissions keep what they could do, and
+-- support staff can look at users and resumes without changing them
+INSERT INTO user_roles (name, description, permissions) VALUES
+    ('user', 'Manages their own resumes', '{}'),
+    ('admin', 'Manages the instance', '{resumes:read:any,resumes:write:any,roles:manage,service_accounts:manage,settings:manage,stats:read,users:manage,users:read}'),
+    ('support', 'Views users and their resumes without changing them', '{resumes:read:any,users:read}')
+ON CONFLICT (name) DO NOTHING;
+
+-- Any other role a user has becomes a role without permissions, so users
+-- can only be given roles that exist
+INSERT INTO user_roles (name)
+SELECT DISTINCT role FROM users
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES user_roles(name);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+DROP TABLE IF EXISTS user_roles;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Requests made with each API key, counted per hour. Counts are kept in
+-- Redis while the hour lasts and flushed here by an hourly job.
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    hour TIMESTAMPTZ NOT NULL,
+    requests BIGINT NOT NULL,
+
+    PRIMARY KEY (api_key_id, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_usage_hour ON api_key_usage(hour);
+CREATE INDEX IF NOT EXISTS idx_api_keys_last_used_at ON api_keys(last_used_at);
+
+COMMENT ON TABLE api_key_usage IS 'Hourly request counts of API keys';
+COMMENT ON COLUMN api_key_usage.hour IS 'Start of the hour the requests were made in, UTC';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_api_keys_last_used_at;
+DROP TABLE IF EXISTS api_key_usage;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- The foreign key only covered resumes in the resumes table, so provenance of
+-- resumes stored as documents couldn't be saved. Provenance is deleted with
+-- its resume by the application instead.
+ALTER TABLE entry_provenance DROP CONSTRAINT IF EXISTS entry_provenance_resume_id_fkey;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- Rows of resumes stored as documents are kept, so existing rows aren't checked
+ALTER TABLE entry_provenance
+    ADD CONSTRAINT entry_provenance_resume_id_fkey FOREIGN KEY (resume_id) REFERENCES resumes(id) ON DELETE CASCADE NOT VALID;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- As with entry_provenance, the foreign key only covered resumes in the
+-- resumes table, so resumes stored as documents couldn't have connectors.
+-- Connectors are deleted with their resume by the application instead.
+ALTER TABLE connectors DROP CONSTRAINT IF EXISTS connectors_resume_id_fkey;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- Rows of resumes stored as documents are kept, so existing rows aren't checked
+ALTER TABLE connectors
+    ADD CONSTRAINT connectors_resume_id_fkey FOREIGN KEY (resume_id) REFERENCES resumes(id) ON DELETE CASCADE NOT VALID;
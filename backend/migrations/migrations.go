@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations so the schema ships inside the
+// binary and can be applied without the migrations directory on disk.
+package migrations
+
+import "embed"
+
+// FS holds the goose migration files
+//
+//go:embed *.sql
+var FS embed.FS
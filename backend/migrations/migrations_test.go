@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	goose.SetBaseFS(FS)
+	t.Cleanup(func() { goose.SetBaseFS(nil) })
+
+	collected, err := goose.CollectMigrations(".", 0, goose.MaxVersion)
+	require.NoError(t, err)
+	assert.NotEmpty(t, collected)
+
+	entries, err := FS.ReadDir(".")
+	require.NoError(t, err)
+	assert.Len(t, collected, len(entries), "every embedded file should be a migration")
+}
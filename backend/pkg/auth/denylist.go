@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Denylist records revoked access tokens in Redis, so they stop working
+// before they expire. Single tokens are revoked by their JWT ID; all of a
+// user's tokens are revoked by storing a cutoff, before which tokens issued
+// to the user are rejected. Entries expire once the tokens they cover would
+// have expired anyway. A user's entries share a hash tag, so they are read
+// together on Redis Cluster too.
+type Denylist struct {
+	redis redis.UniversalClient
+	// leeway is how long after expiry tokens are still accepted
+	leeway time.Duration
+	// available reports whether Redis is reachable
+	available func() bool
+}
+
+// NewDenylist creates a Redis-backed token denylist. leeway must match the
+// JWTConfig.Leeway tokens are validated with, so entries outlive the tokens
+// they cover.
+func NewDenylist(redisClient redis.UniversalClient, leeway time.Duration) *Denylist {
+	return &Denylist{redis: redisClient, leeway: leeway}
+}
+
+// SetAvailability sets a function reporting whether Redis is reachable.
+// While it reports false, Revoked accepts tokens without checking them, so
+// signed-in users keep working through a Redis outage; revocations made
+// before the outage take effect again once Redis is back.
+func (d *Denylist) SetAvailability(available func() bool) {
+	d.available = available
+}
+
+// RevokeToken revokes a single token until it expires
+func (d *Denylist) RevokeToken(ctx context.Context, claims *JWTClaims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return errors.New("token has no ID or expiry")
+	}
+
+	// Tokens are accepted for the leeway after they expire
+	ttl := time.Until(claims.ExpiresAt.Time) + d.leeway
+	if ttl <= 0 {
+		return nil
+	}
+	return d.redis.Set(ctx, denylistTokenKey(claims.UserID, claims.ID), 1, ttl).Err()
+}
+
+// RevokeUser revokes every token issued to a user before at. ttl must cover
+// the lifetime of the tokens, after which they expire on their own.
+func (d *Denylist) RevokeUser(ctx context.Context, userID string, at time.Time, ttl time.Duration) error {
+	return d.redis.Set(ctx, denylistUserKey(userID), at.Unix(), ttl).Err()
+}
+
+// Revoked reports whether a token has been revoked. The issued-at claim has
+// second precision, so tokens issued in the same second as a user's
+// revocation are kept; otherwise tokens issued right after it, like those of
+// a confirmed email change, would be rejected too.
+func (d *Denylist) Revoked(ctx context.Context, claims *JWTClaims) (bool, error) {
+	if d.available != nil && !d.available() {
+		return false, nil
+	}
+
+	keys := []string{denylistUserKey(claims.UserID)}
+	if claims.ID != "" {
+		keys = append(keys, denylistTokenKey(claims.UserID, claims.ID))
+	}
+
+	values, err := d.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if len(values) > 1 && values[1] != nil {
+		return true, nil
+	}
+	if cutoff, ok := values[0].(string); ok {
+		revokedAt, err := strconv.ParseInt(cutoff, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		if claims.IssuedAt == nil || claims.IssuedAt.Unix() < revokedAt {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func denylistTokenKey(userID, jti string) string {
+	return "auth:denylist:{" + userID + "}:token:" + jti
+}
+
+func denylistUserKey(userID string) string {
+	return "auth:denylist:{" + userID + "}:user"
+}
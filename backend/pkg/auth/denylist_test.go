@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDenylist(t *testing.T) (*Denylist, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	return NewDenylist(redis.NewClient(&redis.Options{Addr: mr.Addr()}), DefaultLeeway), mr
+}
+
+func TestDenylistRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	denylist, mr := newTestDenylist(t)
+	j := newTestJWT(JWTConfig{}, 0)
+
+	revokedToken, err := j.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	otherToken, err := j.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	revoked, err := j.ValidateAccessToken(revokedToken)
+	require.NoError(t, err)
+	other, err := j.ValidateAccessToken(otherToken)
+	require.NoError(t, err)
+	require.NotEqual(t, revoked.ID, other.ID)
+
+	require.NoError(t, denylist.RevokeToken(ctx, revoked))
+
+	isRevoked, err := denylist.Revoked(ctx, revoked)
+	require.NoError(t, err)
+	assert.True(t, isRevoked)
+	isRevoked, err = denylist.Revoked(ctx, other)
+	require.NoError(t, err)
+	assert.False(t, isRevoked)
+
+	// The entry goes once the token would have expired
+	mr.FastForward(15*time.Minute + DefaultLeeway)
+	isRevoked, err = denylist.Revoked(ctx, revoked)
+	require.NoError(t, err)
+	assert.False(t, isRevoked)
+}
+
+func TestDenylistRevokeTokenCoversConfiguredLeeway(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	denylist := NewDenylist(redis.NewClient(&redis.Options{Addr: mr.Addr()}), 5*time.Minute)
+	j := newTestJWT(JWTConfig{Leeway: 5 * time.Minute}, 0)
+
+	token, err := j.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	claims, err := j.ValidateAccessToken(token)
+	require.NoError(t, err)
+	require.NoError(t, denylist.RevokeToken(ctx, claims))
+
+	// The token is still accepted here, so it must still be revoked
+	mr.FastForward(15*time.Minute + 4*time.Minute)
+	isRevoked, err := denylist.Revoked(ctx, claims)
+	require.NoError(t, err)
+	assert.True(t, isRevoked)
+}
+
+func TestDenylistRevokeUser(t *testing.T) {
+	ctx := context.Background()
+	denylist, _ := newTestDenylist(t)
+	now := time.Now()
+
+	issued := func(userID string, at time.Time) *JWTClaims {
+		claims := &JWTClaims{UserID: userID}
+		claims.IssuedAt = jwt.NewNumericDate(at)
+		return claims
+	}
+
+	require.NoError(t, denylist.RevokeUser(ctx, "user-1", now, time.Hour))
+
+	for name, tc := range map[string]struct {
+		claims  *JWTClaims
+		revoked bool
+	}{
+		"issued before":      {issued("user-1", now.Add(-time.Minute)), true},
+		"issued same second": {issued("user-1", now), false},
+		"issued after":       {issued("user-1", now.Add(time.Minute)), false},
+		"other user":         {issued("user-2", now.Add(-time.Minute)), false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			revoked, err := denylist.Revoked(ctx, tc.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tc.revoked, revoked)
+		})
+	}
+}
+
+func TestDenylistKeysShareASlot(t *testing.T) {
+	ctx := context.Background()
+	denylist, mr := newTestDenylist(t)
+	j := newTestJWT(JWTConfig{}, 0)
+
+	token, err := j.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	claims, err := j.ValidateAccessToken(token)
+	require.NoError(t, err)
+	require.NoError(t, denylist.RevokeToken(ctx, claims))
+	require.NoError(t, denylist.RevokeUser(ctx, "user-1", time.Now(), time.Hour))
+
+	// Redis Cluster refuses an MGET of keys in different slots
+	keys := mr.Keys()
+	require.Len(t, keys, 2)
+	for _, key := range keys {
+		start, end := strings.IndexByte(key, '{'), strings.IndexByte(key, '}')
+		require.True(t, start >= 0 && end > start+1, key)
+		assert.Equal(t, "user-1", key[start+1:end], key)
+	}
+}
+
+func TestDenylistRedisDown(t *testing.T) {
+	denylist, mr := newTestDenylist(t)
+	mr.Close()
+
+	_, err := denylist.Revoked(context.Background(), &JWTClaims{UserID: "user-1"})
+	assert.Error(t, err)
+}
+
+func TestDenylistSkipsChecksWhileRedisIsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	denylist, _ := newTestDenylist(t)
+	j := newTestJWT(JWTConfig{}, 0)
+
+	token, err := j.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	claims, err := j.ValidateAccessToken(token)
+	require.NoError(t, err)
+	require.NoError(t, denylist.RevokeToken(ctx, claims))
+
+	available := false
+	denylist.SetAvailability(func() bool { return available })
+	isRevoked, err := denylist.Revoked(ctx, claims)
+	require.NoError(t, err)
+	assert.False(t, isRevoked)
+
+	// The revocation applies again once Redis is back
+	available = true
+	isRevoked, err = denylist.Revoked(ctx, claims)
+	require.NoError(t, err)
+	assert.True(t, isRevoked)
+}
@@ -3,9 +3,11 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
@@ -17,6 +19,16 @@ const (
 	TokenTypeRefresh = "refresh"
 	// TokenTypeReset is the token type for password reset tokens
 	TokenTypeReset = "reset"
+	// TokenTypeEmailChange is the token type for email change confirmation tokens
+	TokenTypeEmailChange = "email_change"
+	// TokenTypeMagicLink is the token type for emailed sign-in links
+	TokenTypeMagicLink = "magic_link"
+	// TokenTypeAPIKey marks the claims of requests made with an API key,
+	// which is not a JWT
+	TokenTypeAPIKey = "api_key"
+	// TokenTypeServiceAccount marks the claims of requests made with a
+	// service account key, whose subject is the account ID
+	TokenTypeServiceAccount = "service_account"
 )
 
 // JWT claim errors
@@ -27,53 +39,99 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 	// ErrWrongTokenType is returned when the token type is not as expected
 	ErrWrongTokenType = errors.New("wrong token type")
+	// ErrWrongAudience is returned when the token was issued for another audience
+	ErrWrongAudience = errors.New("wrong token audience")
+	// ErrWrongIssuer is returned when the token was issued by someone else
+	ErrWrongIssuer = errors.New("wrong token issuer")
 )
 
+// DefaultLeeway is the clock skew tolerated when checking token times
+const DefaultLeeway = 30 * time.Second
+
 // JWTClaims defines custom claims for JWT
 type JWTClaims struct {
-	UserID    string `json:"user_id"`
-	Email     string `json:"email"`
-	Role      string `json:"role"`
-	TokenType string `json:"token_type"`
+	UserID    string `json:"user_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Role      string `json:"role,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	// Scope is the token type of minimal tokens, which carry no other
+	// custom claims
+	Scope string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Minimal reports whether the claims come from a minimal token, whose
+// email and role aren't part of the token and must be looked up
+func (c *JWTClaims) Minimal() bool {
+	return c.Scope != ""
+}
+
 // JWTConfig contains JWT configuration
 type JWTConfig struct {
-	// Secret is the JWT signing key
+	// Secret is the HS256 signing key used when Keys is empty
 	Secret string
+	// Keys lists the keys tokens are signed and verified with. The first key
+	// signs new tokens; the others only verify tokens signed before a key
+	// rotation and can be dropped once those have expired.
+	Keys []SigningKey
 	// AccessTokenExpiry is the duration after which an access token expires
 	AccessTokenExpiry time.Duration
 	// RefreshTokenExpiry is the duration after which a refresh token expires
 	RefreshTokenExpiry time.Duration
 	// ResetTokenExpiry is the duration after which a password reset token expires
 	ResetTokenExpiry time.Duration
+	// EmailChangeTokenExpiry is the duration after which an email change confirmation token expires
+	EmailChangeTokenExpiry time.Duration
+	// MagicLinkTokenExpiry is the duration after which an emailed sign-in link expires
+	MagicLinkTokenExpiry time.Duration
 	// Issuer is the token issuer
 	Issuer string
 	// Audience is the token audience
 	Audience string
+	// MinimalClaims issues access and refresh tokens carrying only the
+	// subject, token ID and scope, keeping the user's email and role out of
+	// browser storage and logs. Both kinds of tokens are accepted either way.
+	MinimalClaims bool
+	// Leeway is the clock skew tolerated when checking the exp, nbf and iat
+	// claims, so tokens from a server whose clock runs slightly ahead or behind
+	// are still accepted. Negative values disable it.
+	Leeway time.Duration
 }
 
 // DefaultJWTConfig returns default JWT configuration
 func DefaultJWTConfig() JWTConfig {
 	return JWTConfig{
-		AccessTokenExpiry:  15 * time.Minute,
-		RefreshTokenExpiry: 7 * 24 * time.Hour, // 7 days
-		ResetTokenExpiry:   1 * time.Hour,
-		Issuer:             "resume_generator",
-		Audience:           "resume_generator_users",
+		AccessTokenExpiry:      15 * time.Minute,
+		RefreshTokenExpiry:     7 * 24 * time.Hour, // 7 days
+		ResetTokenExpiry:       1 * time.Hour,
+		EmailChangeTokenExpiry: 24 * time.Hour,
+		MagicLinkTokenExpiry:   15 * time.Minute,
+		Issuer:                 "resume_generator",
+		Audience:               "resume_generator_users",
+		Leeway:                 DefaultLeeway,
 	}
 }
 
 // JWT handles JWT token generation and validation
 type JWT struct {
 	config JWTConfig
+	// keysByID finds the key named by a token's kid header
+	keysByID map[string]SigningKey
+	// methods lists the algorithms of the configured keys, the only ones accepted
+	methods []string
+	now     func() time.Time
 }
 
 // NewJWT creates a new JWT handler
 func NewJWT(config JWTConfig) *JWT {
-	if config.Secret == "" {
-		panic("JWT secret is required")
+	if len(config.Keys) == 0 {
+		if config.Secret == "" {
+			panic("JWT secret is required")
+		}
+		config.Keys = []SigningKey{NewHMACKey([]byte(config.Secret))}
+	}
+	if !config.Keys[0].CanSign() {
+		panic("the first JWT key must be able to sign")
 	}
 
 	// Apply defaults for zero values
@@ -86,16 +144,81 @@ func NewJWT(config JWTConfig) *JWT {
 	if config.ResetTokenExpiry == 0 {
 		config.ResetTokenExpiry = DefaultJWTConfig().ResetTokenExpiry
 	}
+	if config.EmailChangeTokenExpiry == 0 {
+		config.EmailChangeTokenExpiry = DefaultJWTConfig().EmailChangeTokenExpiry
+	}
+	if config.MagicLinkTokenExpiry == 0 {
+		config.MagicLinkTokenExpiry = DefaultJWTConfig().MagicLinkTokenExpiry
+	}
 	if config.Issuer == "" {
 		config.Issuer = DefaultJWTConfig().Issuer
 	}
 	if config.Audience == "" {
 		config.Audience = DefaultJWTConfig().Audience
 	}
+	if config.Leeway == 0 {
+		config.Leeway = DefaultJWTConfig().Leeway
+	} else if config.Leeway < 0 {
+		config.Leeway = 0
+	}
+
+	keysByID := make(map[string]SigningKey, len(config.Keys))
+	var methods []string
+	for _, key := range config.Keys {
+		keysByID[key.ID] = key
+		if !slices.Contains(methods, key.Algorithm) {
+			methods = append(methods, key.Algorithm)
+		}
+	}
 
 	return &JWT{
-		config: config,
+		config:   config,
+		keysByID: keysByID,
+		methods:  methods,
+		now:      time.Now,
+	}
+}
+
+// Leeway returns how long after expiry tokens are still accepted
+func (j *JWT) Leeway() time.Duration {
+	return j.config.Leeway
+}
+
+// JWKS returns the public keys tokens are verified with, for services that
+// verify tokens themselves. HS256 keys are secret and never listed.
+func (j *JWT) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range j.config.Keys {
+		if jwk, ok := key.jsonWebKey(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// verificationKeys returns the keys that may have signed a token: the key
+// its kid names, or for tokens from before key IDs every key of its algorithm
+func (j *JWT) verificationKeys(token *jwt.Token) (any, error) {
+	alg := token.Method.Alg()
+
+	if kid, ok := token.Header["kid"].(string); ok {
+		key, found := j.keysByID[kid]
+		if !found || key.Algorithm != alg {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key.verificationKey(), nil
+	}
+
+	var set jwt.VerificationKeySet
+	for _, key := range j.config.Keys {
+		if key.Algorithm == alg {
+			set.Keys = append(set.Keys, key.verificationKey())
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("unexpected signing method: %v", alg)
 	}
+	return set, nil
 }
 
 // GenerateAccessToken generates a new access token
@@ -113,9 +236,19 @@ func (j *JWT) GenerateResetToken(userID, email string) (string, error) {
 	return j.generateToken(userID, email, "", TokenTypeReset, j.config.ResetTokenExpiry)
 }
 
+// GenerateEmailChangeToken generates a token confirming a change to newEmail
+func (j *JWT) GenerateEmailChangeToken(userID, newEmail string) (string, error) {
+	return j.generateToken(userID, newEmail, "", TokenTypeEmailChange, j.config.EmailChangeTokenExpiry)
+}
+
+// GenerateMagicLinkToken generates a token signing the user in from an emailed link
+func (j *JWT) GenerateMagicLinkToken(userID, email string) (string, error) {
+	return j.generateToken(userID, email, "", TokenTypeMagicLink, j.config.MagicLinkTokenExpiry)
+}
+
 // generateToken is a helper function to generate JWT tokens
 func (j *JWT) generateToken(userID, email, role, tokenType string, expiry time.Duration) (string, error) {
-	now := time.Now()
+	now := j.now()
 	expiresAt := now.Add(expiry)
 
 	claims := JWTClaims{
@@ -123,18 +256,27 @@ func (j *JWT) generateToken(userID, email, role, tokenType string, expiry time.D
 		Email:     email,
 		Role:      role,
 		TokenType: tokenType,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    j.config.Issuer,
-			Subject:   userID,
-			Audience:  []string{j.config.Audience},
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(j.config.Secret))
+	}
+	// Only session tokens are minimized; the others are single-use and need
+	// the email they were issued for
+	if j.config.MinimalClaims && (tokenType == TokenTypeAccess || tokenType == TokenTypeRefresh) {
+		claims = JWTClaims{Scope: tokenType}
+	}
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		// The token ID lets a single token be revoked
+		ID:        uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    j.config.Issuer,
+		Subject:   userID,
+		Audience:  []string{j.config.Audience},
+	}
+
+	key := j.config.Keys[0]
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	signedToken, err := token.SignedString(key.signingKey())
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to sign JWT token")
 		return "", fmt.Errorf("failed to sign token: %w", err)
@@ -143,18 +285,27 @@ func (j *JWT) generateToken(userID, email, role, tokenType string, expiry time.D
 	return signedToken, nil
 }
 
-// ParseToken parses and validates a JWT token
+// ParseToken parses and validates a JWT token. The signature must verify
+// with one of the configured keys, the issuer and audience must match, and
+// exp, nbf and iat are checked allowing for the configured leeway.
 func (j *JWT) ParseToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (any, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(j.config.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, j.verificationKeys,
+		jwt.WithValidMethods(j.methods),
+		jwt.WithIssuer(j.config.Issuer),
+		jwt.WithAudience(j.config.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(j.config.Leeway),
+		jwt.WithTimeFunc(j.now),
+	)
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
 			return nil, ErrTokenExpired
+		case errors.Is(err, jwt.ErrTokenInvalidAudience):
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, ErrWrongAudience)
+		case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, ErrWrongIssuer)
 		}
 		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
@@ -167,10 +318,38 @@ func (j *JWT) ParseToken(tokenString string) (*JWTClaims, error) {
 	if !ok {
 		return nil, ErrInvalidToken
 	}
+	// Minimal tokens name their user by the subject and their type by the scope
+	if claims.Minimal() {
+		claims.UserID = claims.Subject
+		claims.TokenType = claims.Scope
+	}
+
+	j.logClockSkew(claims)
 
 	return claims, nil
 }
 
+// logClockSkew warns when a token was only accepted thanks to the leeway, which
+// means the issuing clock is ahead of ours
+func (j *JWT) logClockSkew(claims *JWTClaims) {
+	now := j.now()
+	var skew time.Duration
+	if claims.IssuedAt != nil {
+		skew = max(skew, claims.IssuedAt.Sub(now))
+	}
+	if claims.NotBefore != nil {
+		skew = max(skew, claims.NotBefore.Sub(now))
+	}
+	// NumericDate has second precision, so ignore sub-second differences
+	if skew >= time.Second {
+		log.Warn().
+			Dur("skew", skew).
+			Dur("leeway", j.config.Leeway).
+			Str("issuer", claims.Issuer).
+			Msg("Accepted JWT issued in the future; clocks may be out of sync")
+	}
+}
+
 // ValidateAccessToken validates an access token
 func (j *JWT) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 	claims, err := j.ParseToken(tokenString)
@@ -212,3 +391,31 @@ func (j *JWT) ValidateResetToken(tokenString string) (*JWTClaims, error) {
 
 	return claims, nil
 }
+
+// ValidateEmailChangeToken validates an email change confirmation token
+func (j *JWT) ValidateEmailChangeToken(tokenString string) (*JWTClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != TokenTypeEmailChange {
+		return nil, ErrWrongTokenType
+	}
+
+	return claims, nil
+}
+
+// ValidateMagicLinkToken validates an emailed sign-in link token
+func (j *JWT) ValidateMagicLinkToken(tokenString string) (*JWTClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != TokenTypeMagicLink {
+		return nil, ErrWrongTokenType
+	}
+
+	return claims, nil
+}
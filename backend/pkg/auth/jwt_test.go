@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestJWT returns a JWT handler whose clock is offset from real time
+func newTestJWT(config JWTConfig, offset time.Duration) *JWT {
+	config.Secret = "test-secret"
+	j := NewJWT(config)
+	j.now = func() time.Time { return time.Now().Add(offset) }
+	return j
+}
+
+func TestParseTokenClockSkew(t *testing.T) {
+	// The issuing server's clock runs 20s ahead of the validating server
+	issuer := newTestJWT(JWTConfig{}, 20*time.Second)
+	token, err := issuer.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+
+	// Within the default leeway the token is accepted
+	claims, err := newTestJWT(JWTConfig{}, 0).ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	// Without leeway it is not valid yet
+	_, err = newTestJWT(JWTConfig{Leeway: -1}, 0).ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// A clock far behind rejects it even with leeway
+	_, err = newTestJWT(JWTConfig{}, -time.Minute).ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseTokenExpiryLeeway(t *testing.T) {
+	j := newTestJWT(JWTConfig{AccessTokenExpiry: time.Minute}, 0)
+	token, err := j.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+
+	// Just past expiry, within the leeway
+	_, err = newTestJWT(JWTConfig{}, time.Minute+10*time.Second).ValidateAccessToken(token)
+	assert.NoError(t, err)
+
+	// Past expiry and the leeway
+	_, err = newTestJWT(JWTConfig{}, time.Minute+time.Hour).ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestParseTokenAudienceAndIssuer(t *testing.T) {
+	token, err := newTestJWT(JWTConfig{Audience: "other_service"}, 0).GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	_, err = newTestJWT(JWTConfig{}, 0).ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.ErrorIs(t, err, ErrWrongAudience)
+
+	token, err = newTestJWT(JWTConfig{Issuer: "someone_else"}, 0).GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	_, err = newTestJWT(JWTConfig{}, 0).ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.ErrorIs(t, err, ErrWrongIssuer)
+}
+
+func TestMinimalClaims(t *testing.T) {
+	minimal := newTestJWT(JWTConfig{MinimalClaims: true}, 0)
+	token, err := minimal.GenerateAccessToken("user-1", "user@example.com", "admin")
+	require.NoError(t, err)
+
+	// Only the subject, token ID and scope are custom to the token
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(payload, &raw))
+	assert.Equal(t, "user-1", raw["sub"])
+	assert.Equal(t, "access", raw["scope"])
+	assert.NotEmpty(t, raw["jti"])
+	assert.NotContains(t, string(payload), "user@example.com")
+	assert.NotContains(t, raw, "role")
+	assert.NotContains(t, raw, "user_id")
+
+	// Both modes accept both kinds of tokens
+	claims, err := newTestJWT(JWTConfig{}, 0).ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.True(t, claims.Minimal())
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Empty(t, claims.Email)
+	full, err := newTestJWT(JWTConfig{}, 0).GenerateAccessToken("user-1", "user@example.com", "admin")
+	require.NoError(t, err)
+	claims, err = minimal.ValidateAccessToken(full)
+	require.NoError(t, err)
+	assert.False(t, claims.Minimal())
+	assert.Equal(t, "admin", claims.Role)
+
+	// The scope keeps token types apart
+	refresh, err := minimal.GenerateRefreshToken("user-1", "user@example.com", "admin")
+	require.NoError(t, err)
+	_, err = minimal.ValidateAccessToken(refresh)
+	assert.ErrorIs(t, err, ErrWrongTokenType)
+
+	// Single-use tokens keep the email they were issued for
+	link, err := minimal.GenerateMagicLinkToken("user-1", "user@example.com")
+	require.NoError(t, err)
+	claims, err = minimal.ValidateMagicLinkToken(link)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", claims.Email)
+}
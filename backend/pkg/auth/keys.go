@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// ErrUnsupportedKey is returned for PEM keys that are neither RSA nor Ed25519
+var ErrUnsupportedKey = errors.New("unsupported key type, use RSA or Ed25519")
+
+// SigningKey is a key tokens are signed or verified with. HS256 keys hold a
+// shared secret; RS256 and EdDSA keys hold a public key, and a private key
+// when they sign.
+type SigningKey struct {
+	// ID is sent as the kid header so verifiers can pick the key
+	ID        string
+	Algorithm string
+	Secret    []byte
+	// PrivateKey signs tokens; keys without one only verify
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// NewHMACKey creates an HS256 key. Its ID is derived from the secret, so the
+// same secret gets the same ID on every instance.
+func NewHMACKey(secret []byte) SigningKey {
+	sum := sha256.Sum256(secret)
+	return SigningKey{
+		ID:        "hs-" + hex.EncodeToString(sum[:8]),
+		Algorithm: AlgorithmHS256,
+		Secret:    secret,
+	}
+}
+
+// ParsePrivateKeyPEM parses a PKCS #8 or PKCS #1 private key; RSA keys sign
+// with RS256 and Ed25519 keys with EdDSA
+func ParsePrivateKeyPEM(data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("parse private key: %w", err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return SigningKey{}, ErrUnsupportedKey
+	}
+	key, err := newPublicKey(signer.Public())
+	if err != nil {
+		return SigningKey{}, err
+	}
+	key.PrivateKey = signer
+	return key, nil
+}
+
+// ParsePublicKeyPEM parses a PKIX public key, which verifies the tokens of a
+// retired private key
+func ParsePublicKeyPEM(data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, errors.New("no PEM block found")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("parse public key: %w", err)
+	}
+	return newPublicKey(parsed)
+}
+
+// newPublicKey creates an asymmetric key, identified by the hash of its
+// public key
+func newPublicKey(public crypto.PublicKey) (SigningKey, error) {
+	key := SigningKey{PublicKey: public}
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.Algorithm = AlgorithmRS256
+	case ed25519.PublicKey:
+		key.Algorithm = AlgorithmEdDSA
+	default:
+		return SigningKey{}, ErrUnsupportedKey
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	sum := sha256.Sum256(der)
+	key.ID = base64.RawURLEncoding.EncodeToString(sum[:12])
+	return key, nil
+}
+
+// CanSign reports whether the key holds what is needed to sign tokens
+func (k SigningKey) CanSign() bool {
+	if k.Algorithm == AlgorithmHS256 {
+		return len(k.Secret) > 0
+	}
+	return k.PrivateKey != nil
+}
+
+// method returns the JWT signing method of the key
+func (k SigningKey) method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// signingKey returns the key material the signing method signs with
+func (k SigningKey) signingKey() any {
+	if k.Algorithm == AlgorithmHS256 {
+		return k.Secret
+	}
+	return k.PrivateKey
+}
+
+// verificationKey returns the key material the signing method verifies with
+func (k SigningKey) verificationKey() any {
+	if k.Algorithm == AlgorithmHS256 {
+		return k.Secret
+	}
+	return k.PublicKey
+}
+
+// JSONWebKey is a public key in JWK form (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	// N and E are the modulus and exponent of RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Curve and X describe Ed25519 keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet lists the public keys tokens can be verified with
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// jsonWebKey returns the public JWK of an asymmetric key; HS256 keys have none
+func (k SigningKey) jsonWebKey() (JSONWebKey, bool) {
+	jwk := JSONWebKey{KeyID: k.ID, Algorithm: k.Algorithm, Use: "sig"}
+	switch public := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return JSONWebKey{}, false
+	}
+	return jwk, true
+}
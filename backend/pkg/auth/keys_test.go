@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// privateKeyPEM encodes a private key as PKCS #8 PEM
+func privateKeyPEM(t *testing.T, key any) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// publicKeyPEM encodes a public key as PKIX PEM
+func publicKeyPEM(t *testing.T, key any) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestSecretRotation(t *testing.T) {
+	old := NewJWT(JWTConfig{Secret: "old-secret"})
+	oldToken, err := old.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+
+	// After rotation the new secret signs and the old one still verifies
+	rotated := NewJWT(JWTConfig{Keys: []SigningKey{NewHMACKey([]byte("new-secret")), NewHMACKey([]byte("old-secret"))}})
+	_, err = rotated.ValidateAccessToken(oldToken)
+	require.NoError(t, err)
+
+	newToken, err := rotated.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	_, err = old.ValidateAccessToken(newToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Once the old secret is dropped its tokens stop working
+	_, err = NewJWT(JWTConfig{Secret: "new-secret"}).ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokensWithoutKeyID(t *testing.T) {
+	// Tokens issued before key IDs carry no kid header
+	claims := JWTClaims{
+		UserID:    "user-1",
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    DefaultJWTConfig().Issuer,
+			Audience:  []string{DefaultJWTConfig().Audience},
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("old-secret"))
+	require.NoError(t, err)
+
+	j := NewJWT(JWTConfig{Keys: []SigningKey{NewHMACKey([]byte("new-secret")), NewHMACKey([]byte("old-secret"))}})
+	parsed, err := j.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", parsed.UserID)
+}
+
+func TestAsymmetricSigning(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		private   any
+		public    any
+		algorithm string
+		keyType   string
+	}{
+		"RS256": {rsaKey, &rsaKey.PublicKey, AlgorithmRS256, "RSA"},
+		"EdDSA": {edKey, edKey.Public(), AlgorithmEdDSA, "OKP"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signing, err := ParsePrivateKeyPEM(privateKeyPEM(t, tc.private))
+			require.NoError(t, err)
+			assert.Equal(t, tc.algorithm, signing.Algorithm)
+
+			j := NewJWT(JWTConfig{Keys: []SigningKey{signing}})
+			token, err := j.GenerateAccessToken("user-1", "user@example.com", "user")
+			require.NoError(t, err)
+
+			// Services holding only the public key verify the token
+			verifying, err := ParsePublicKeyPEM(publicKeyPEM(t, tc.public))
+			require.NoError(t, err)
+			assert.Equal(t, signing.ID, verifying.ID)
+			assert.False(t, verifying.CanSign())
+
+			verifier := NewJWT(JWTConfig{Keys: []SigningKey{NewHMACKey([]byte("secret")), verifying}})
+			claims, err := verifier.ValidateAccessToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.UserID)
+
+			jwks := verifier.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, signing.ID, jwks.Keys[0].KeyID)
+			assert.Equal(t, tc.keyType, jwks.Keys[0].KeyType)
+			assert.Equal(t, tc.algorithm, jwks.Keys[0].Algorithm)
+		})
+	}
+}
+
+func TestRejectsUnknownKeysAndAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	verifying, err := ParsePublicKeyPEM(publicKeyPEM(t, &rsaKey.PublicKey))
+	require.NoError(t, err)
+	signing, err := ParsePrivateKeyPEM(privateKeyPEM(t, rsaKey))
+	require.NoError(t, err)
+	j := NewJWT(JWTConfig{Keys: []SigningKey{signing}})
+
+	claims := JWTClaims{
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    DefaultJWTConfig().Issuer,
+			Audience:  []string{DefaultJWTConfig().Audience},
+		},
+	}
+
+	// An HS256 token keyed with the published public key must not verify
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	forged.Header["kid"] = verifying.ID
+	token, err := forged.SignedString(publicKeyPEM(t, &rsaKey.PublicKey))
+	require.NoError(t, err)
+	_, err = j.ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Neither may a token naming a key that isn't configured
+	other := NewJWT(JWTConfig{Secret: "other-secret"})
+	token, err = other.GenerateAccessToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	_, err = NewJWT(JWTConfig{Secret: "secret"}).ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	assert.Panics(t, func() { NewJWT(JWTConfig{Keys: []SigningKey{verifying}}) })
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves the token endpoint and API of a GitHub-like provider
+func fakeGitHub(t *testing.T, challenge *string, emails []map[string]any) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		// Errors come with status 200, like GitHub sends them
+		if r.Form.Get("code") != "good-code" || PKCEChallenge(r.Form.Get("code_verifier")) != *challenge {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "gho_token", "token_type": "bearer"})
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer gho_token", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(map[string]any{"id": 42, "login": "octo", "name": ""})
+	})
+	mux.HandleFunc("GET /user/emails", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(emails)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitHubProviderExchange(t *testing.T) {
+	var challenge string
+	server := fakeGitHub(t, &challenge, []map[string]any{
+		{"email": "old@example.com", "primary": false, "verified": true},
+		{"email": "octo@example.com", "primary": true, "verified": true},
+	})
+	provider := NewGitHubProvider(OAuthConfig{
+		ClientID:    "client",
+		RedirectURL: "https://api.example.com/api/v1/auth/github/callback",
+		AuthURL:     server.URL + "/authorize",
+		TokenURL:    server.URL + "/token",
+		APIURL:      server.URL,
+	})
+
+	verifier, err := NewOAuthToken()
+	require.NoError(t, err)
+	authURL, err := url.Parse(provider.AuthCodeURL("state-1", verifier))
+	require.NoError(t, err)
+	query := authURL.Query()
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "https://api.example.com/api/v1/auth/github/callback", query.Get("redirect_uri"))
+	challenge = query.Get("code_challenge")
+
+	identity, err := provider.Exchange(context.Background(), "good-code", verifier)
+	require.NoError(t, err)
+	assert.Equal(t, ProviderGitHub, identity.Provider)
+	assert.Equal(t, "42", identity.Subject)
+	assert.Equal(t, "octo@example.com", identity.Email)
+	assert.Equal(t, "octo", identity.Name)
+
+	// The verifier must match the challenge sent with the consent request
+	_, err = provider.Exchange(context.Background(), "good-code", "other-verifier")
+	assert.ErrorIs(t, err, ErrOAuthExchange)
+}
+
+func TestGitHubProviderUnverifiedEmail(t *testing.T) {
+	challenge := PKCEChallenge("verifier")
+	server := fakeGitHub(t, &challenge, []map[string]any{{"email": "octo@example.com", "primary": true, "verified": false}})
+	provider := NewGitHubProvider(OAuthConfig{TokenURL: server.URL + "/token", APIURL: server.URL})
+
+	_, err := provider.Exchange(context.Background(), "good-code", "verifier")
+	assert.ErrorIs(t, err, ErrOAuthNoVerifiedEmail)
+}
+
+func TestGoogleProviderExchange(t *testing.T) {
+	verified := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "ya29"})
+	})
+	mux.HandleFunc("GET /userinfo", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"sub": "1090", "email": "ana@example.com", "email_verified": verified, "name": "Ana"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	provider := NewGoogleProvider(OAuthConfig{TokenURL: server.URL + "/token", APIURL: server.URL})
+
+	_, err := provider.Exchange(context.Background(), "code", "verifier")
+	assert.ErrorIs(t, err, ErrOAuthNoVerifiedEmail)
+
+	verified = true
+	identity, err := provider.Exchange(context.Background(), "code", "verifier")
+	require.NoError(t, err)
+	assert.Equal(t, ProviderGoogle, identity.Provider)
+	assert.Equal(t, "1090", identity.Subject)
+	assert.Equal(t, "ana@example.com", identity.Email)
+}
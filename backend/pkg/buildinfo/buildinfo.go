@@ -0,0 +1,78 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// Build metadata, set at link time:
+//
+//	go build -ldflags "-X github.com/lordaris/resume_generator/pkg/buildinfo.Version=v1.2.3 \
+//	  -X github.com/lordaris/resume_generator/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/lordaris/resume_generator/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+var (
+	once sync.Once
+	info Info
+)
+
+// Get returns the build info. Values not set via ldflags fall back to the VCS
+// stamp the Go toolchain embeds in binaries built from a git checkout.
+func Get() Info {
+	once.Do(func() {
+		info = Info{
+			Version:   Version,
+			Commit:    Commit,
+			BuildTime: BuildTime,
+			GoVersion: runtime.Version(),
+		}
+
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	})
+
+	return info
+}
+
+// ShortCommit returns the abbreviated commit hash, or "unknown" if it isn't known
+func ShortCommit() string {
+	commit := Get().Commit
+	if commit == "" {
+		return "unknown"
+	}
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
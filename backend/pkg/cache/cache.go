@@ -0,0 +1,33 @@
+// Package cache provides a small key/value cache abstraction with Redis and
+// in-memory drivers.
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Get when the key is absent or has expired
+var ErrMiss = errors.New("cache: miss")
+
+// Cache stores byte values under string keys. A ttl of zero means the value
+// never expires.
+type Cache interface {
+	// Get returns the value stored under key, or ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key, replacing any existing value
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX stores value only if key is absent, reporting whether it was stored
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Take returns the value stored under key and removes it in one step,
+	// or ErrMiss, so of several concurrent callers only one gets the value
+	Take(ctx context.Context, key string) ([]byte, error)
+	// Del removes the given keys; missing keys are ignored. On Redis
+	// Cluster the keys must share a hash slot.
+	Del(ctx context.Context, keys ...string) error
+	// Incr atomically adds one to the decimal counter under key and returns
+	// the new count. A missing key starts at zero and expires after ttl; the
+	// ttl of an existing counter is left unchanged.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drivers returns every Cache implementation so they are held to the same contract
+func drivers(t *testing.T) map[string]Cache {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	memory, err := NewMemory(MemoryConfig{})
+	require.NoError(t, err)
+	t.Cleanup(memory.Close)
+
+	evicting, err := NewRistretto(RistrettoConfig{})
+	require.NoError(t, err)
+	t.Cleanup(evicting.Close)
+
+	return map[string]Cache{
+		"redis":     NewRedis(client),
+		"memory":    memory,
+		"ristretto": evicting,
+	}
+}
+
+func TestCacheGetSetDel(t *testing.T) {
+	ctx := context.Background()
+
+	for name, c := range drivers(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.Get(ctx, "missing")
+			assert.ErrorIs(t, err, ErrMiss)
+
+			require.NoError(t, c.Set(ctx, "key", []byte("value"), time.Minute))
+			value, err := c.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("value"), value)
+
+			require.NoError(t, c.Set(ctx, "key", []byte("replaced"), 0))
+			value, err = c.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("replaced"), value)
+
+			require.NoError(t, c.Del(ctx, "key", "missing"))
+			_, err = c.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrMiss)
+		})
+	}
+}
+
+func TestCacheSetNX(t *testing.T) {
+	ctx := context.Background()
+
+	for name, c := range drivers(t) {
+		t.Run(name, func(t *testing.T) {
+			ok, err := c.SetNX(ctx, "claim", []byte("first"), time.Minute)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = c.SetNX(ctx, "claim", []byte("second"), time.Minute)
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			value, err := c.Get(ctx, "claim")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("first"), value)
+		})
+	}
+}
+
+func TestCacheTake(t *testing.T) {
+	ctx := context.Background()
+
+	for name, c := range drivers(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.Take(ctx, "missing")
+			assert.ErrorIs(t, err, ErrMiss)
+
+			require.NoError(t, c.Set(ctx, "once", []byte("value"), time.Minute))
+
+			// Of concurrent takers only one gets the value
+			var taken atomic.Int32
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					value, err := c.Take(ctx, "once")
+					if err == nil {
+						assert.Equal(t, []byte("value"), value)
+						taken.Add(1)
+					} else {
+						assert.ErrorIs(t, err, ErrMiss)
+					}
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, int32(1), taken.Load())
+
+			_, err = c.Get(ctx, "once")
+			assert.ErrorIs(t, err, ErrMiss)
+		})
+	}
+}
+
+func TestCacheIncr(t *testing.T) {
+	ctx := context.Background()
+
+	for name, c := range drivers(t) {
+		t.Run(name, func(t *testing.T) {
+			for want := int64(1); want <= 3; want++ {
+				n, err := c.Incr(ctx, "views", time.Minute)
+				require.NoError(t, err)
+				assert.Equal(t, want, n)
+			}
+
+			value, err := c.Get(ctx, "views")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("3"), value)
+
+			require.NoError(t, c.Set(ctx, "text", []byte("value"), time.Minute))
+			_, err = c.Incr(ctx, "text", time.Minute)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMemoryExpiry(t *testing.T) {
+	c, err := NewMemory(MemoryConfig{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "short", []byte("value"), 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = c.Get(ctx, "short")
+	assert.ErrorIs(t, err, ErrMiss)
+}
+
+func TestMemoryFull(t *testing.T) {
+	c, err := NewMemory(MemoryConfig{MaxBytes: 32})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	ctx := context.Background()
+
+	// Entries are never dropped to make room; the write fails instead
+	require.NoError(t, c.Set(ctx, "first", []byte("0123456789"), time.Minute))
+	err = c.Set(ctx, "second", []byte("0123456789abcdef"), time.Minute)
+	assert.ErrorIs(t, err, ErrFull)
+	value, err := c.Get(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0123456789"), value)
+
+	// Replacing a value only counts the difference, and deleted or expired
+	// entries free their space
+	require.NoError(t, c.Set(ctx, "first", []byte("01234567890123456789"), 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, c.Set(ctx, "second", []byte("0123456789abcdef"), time.Minute))
+	require.NoError(t, c.Del(ctx, "second"))
+	_, err = c.Incr(ctx, "views", 0)
+	require.NoError(t, err)
+}
+
+func TestRistrettoEvicts(t *testing.T) {
+	c, err := NewRistretto(RistrettoConfig{MaxBytes: 1 << 10})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	ctx := context.Background()
+
+	// Writes never fail for lack of room; older entries make way instead
+	value := make([]byte, 100)
+	for i := range 100 {
+		require.NoError(t, c.Set(ctx, "key-"+strconv.Itoa(i), value, time.Minute))
+	}
+
+	stored := 0
+	for i := range 100 {
+		if _, err := c.Get(ctx, "key-"+strconv.Itoa(i)); err == nil {
+			stored++
+		}
+	}
+	assert.Less(t, stored, 100)
+}
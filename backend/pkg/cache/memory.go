@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrFull is returned by the memory cache when storing a value would exceed
+// its MaxBytes
+var ErrFull = errors.New("cache: memory cache is full")
+
+// sweepInterval is how often expired entries are removed from the memory cache
+const sweepInterval = time.Minute
+
+// MemoryConfig contains configuration for the in-memory cache
+type MemoryConfig struct {
+	// MaxBytes bounds the total size of stored values
+	MaxBytes int64
+}
+
+// memoryEntry is a value held by the memory cache
+type memoryEntry struct {
+	value []byte
+	// expiresAt is zero for values that never expire
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Memory is a Cache held in process memory. Values are not shared between
+// instances, so it suits tests and single-instance deployments. Entries are
+// never evicted before their ttl, since counters and single-use values must
+// not be dropped silently; once MaxBytes is reached writes fail with ErrFull
+// instead. Cached copies of data that can be loaded again belong in
+// Ristretto, which makes room by evicting them.
+type Memory struct {
+	mu       sync.Mutex
+	entries  map[string]memoryEntry
+	size     int64
+	maxBytes int64
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMemory creates a new in-memory cache
+func NewMemory(config MemoryConfig) (*Memory, error) {
+	// Set default values if not provided
+	if config.MaxBytes == 0 {
+		config.MaxBytes = 64 << 20
+	}
+
+	c := &Memory{
+		entries:  make(map[string]memoryEntry),
+		maxBytes: config.MaxBytes,
+		done:     make(chan struct{}),
+	}
+	go c.sweepLoop()
+	return c, nil
+}
+
+// Get returns the value stored under key, or ErrMiss
+func (c *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.get(key, time.Now())
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	return entry.value, nil
+}
+
+// Set stores value under key, replacing any existing value
+func (c *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.set(key, value, expiry(time.Now(), ttl))
+}
+
+// SetNX stores value only if key is absent, reporting whether it was stored
+func (c *Memory) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.get(key, now); ok {
+		return false, nil
+	}
+
+	if err := c.set(key, value, expiry(now, ttl)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Take returns the value stored under key and removes it, or ErrMiss
+func (c *Memory) Take(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.get(key, time.Now())
+	if !ok {
+		return nil, ErrMiss
+	}
+	c.del(key)
+
+	return entry.value, nil
+}
+
+// Del removes the given keys; missing keys are ignored
+func (c *Memory) Del(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		c.del(key)
+	}
+	return nil
+}
+
+// Incr atomically adds one to the counter under key and returns the new count
+func (c *Memory) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	var n int64
+	expiresAt := expiry(now, ttl)
+	if entry, ok := c.get(key, now); ok {
+		current, err := strconv.ParseInt(string(entry.value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("cache: value of %q is not a counter", key)
+		}
+		n = current
+		// Keep the counter's original expiry
+		expiresAt = entry.expiresAt
+	}
+
+	n++
+	if err := c.set(key, []byte(strconv.FormatInt(n, 10)), expiresAt); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Close stops the cache's background sweep
+func (c *Memory) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// sweepLoop removes expired entries until the cache is closed, so values
+// that are never read again don't hold memory
+func (c *Memory) sweepLoop() {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.Lock()
+			c.sweep(time.Now())
+			c.mu.Unlock()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// get returns the unexpired entry under key, removing it if it has expired.
+// Callers must hold mu.
+func (c *Memory) get(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if entry.expired(now) {
+		c.del(key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// set stores a value, failing with ErrFull rather than evicting other
+// entries when it doesn't fit. Callers must hold mu.
+func (c *Memory) set(key string, value []byte, expiresAt time.Time) error {
+	if c.sizeWith(key, value) > c.maxBytes {
+		c.sweep(time.Now())
+		if c.sizeWith(key, value) > c.maxBytes {
+			return ErrFull
+		}
+	}
+
+	c.size = c.sizeWith(key, value)
+	c.entries[key] = memoryEntry{value: value, expiresAt: expiresAt}
+	return nil
+}
+
+// sizeWith returns the size of the cache once value is stored under key.
+// Callers must hold mu.
+func (c *Memory) sizeWith(key string, value []byte) int64 {
+	size := c.size + entrySize(key, value)
+	if existing, ok := c.entries[key]; ok {
+		size -= entrySize(key, existing.value)
+	}
+	return size
+}
+
+// del removes key. Callers must hold mu.
+func (c *Memory) del(key string) {
+	if entry, ok := c.entries[key]; ok {
+		c.size -= entrySize(key, entry.value)
+		delete(c.entries, key)
+	}
+}
+
+// sweep removes every expired entry. Callers must hold mu.
+func (c *Memory) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if entry.expired(now) {
+			c.del(key)
+		}
+	}
+}
+
+// expiry returns when a value stored at now with ttl expires; zero for none
+func expiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// entrySize is the number of bytes an entry counts against MaxBytes
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// incrScript increments a counter and sets its expiry when it is created, in
+// one step so a crash can't leave a counter without one
+var incrScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// Redis is a Cache backed by a Redis server, shared by every instance of the application
+type Redis struct {
+	client redis.UniversalClient
+}
+
+// NewRedis creates a new Redis cache
+func NewRedis(client redis.UniversalClient) *Redis {
+	return &Redis{
+		client: client,
+	}
+}
+
+// Get returns the value stored under key, or ErrMiss
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrMiss
+		}
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Set stores value under key, replacing any existing value
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX stores value only if key is absent, reporting whether it was stored
+func (c *Redis) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Take returns the value stored under key and removes it, or ErrMiss
+func (c *Redis) Take(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.GetDel(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrMiss
+		}
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Del removes the given keys; missing keys are ignored
+func (c *Redis) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// Incr atomically adds one to the counter under key and returns the new count
+func (c *Redis) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, c.client, []string{key}, ttl.Milliseconds()).Int64()
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+)
+
+// RistrettoConfig contains configuration for the evicting in-memory cache
+type RistrettoConfig struct {
+	// MaxBytes bounds the total size of stored values
+	MaxBytes int64
+}
+
+// Ristretto is a Cache held in process memory that evicts the least useful
+// entries once MaxBytes is reached, so it never fills up. Any entry may be
+// dropped before its ttl, which suits cached copies of data that can be
+// loaded again; state that must survive until it expires belongs in Memory.
+type Ristretto struct {
+	// mu serializes writes so SetNX, Take and Incr are atomic; ristretto
+	// handles concurrent reads
+	mu    sync.Mutex
+	store *ristretto.Cache[string, []byte]
+}
+
+// NewRistretto creates a new evicting in-memory cache
+func NewRistretto(config RistrettoConfig) (*Ristretto, error) {
+	// Set default values if not provided
+	if config.MaxBytes == 0 {
+		config.MaxBytes = 64 << 20
+	}
+
+	store, err := ristretto.NewCache(&ristretto.Config[string, []byte]{
+		// Ristretto recommends ten counters per expected entry; assume ~1KB values
+		NumCounters: max(config.MaxBytes/100, 100),
+		MaxCost:     config.MaxBytes,
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ristretto{
+		store: store,
+	}, nil
+}
+
+// Get returns the value stored under key, or ErrMiss
+func (c *Ristretto) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := c.store.Get(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	return value, nil
+}
+
+// Set stores value under key, replacing any existing value
+func (c *Ristretto) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, ttl)
+	return nil
+}
+
+// SetNX stores value only if key is absent, reporting whether it was stored
+func (c *Ristretto) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.store.Get(key); ok {
+		return false, nil
+	}
+
+	c.set(key, value, ttl)
+	return true, nil
+}
+
+// Take returns the value stored under key and removes it, or ErrMiss
+func (c *Ristretto) Take(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.store.Get(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+	c.del(key)
+
+	return value, nil
+}
+
+// Del removes the given keys; missing keys are ignored
+func (c *Ristretto) Del(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		c.del(key)
+	}
+	return nil
+}
+
+// Incr atomically adds one to the counter under key and returns the new
+// count. Counters may be evicted like any other entry.
+func (c *Ristretto) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var n int64
+	if value, ok := c.store.Get(key); ok {
+		current, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("cache: value of %q is not a counter", key)
+		}
+		n = current
+		// Keep the counter's original expiry
+		ttl, _ = c.store.GetTTL(key)
+	}
+
+	n++
+	c.set(key, []byte(strconv.FormatInt(n, 10)), ttl)
+	return n, nil
+}
+
+// Close releases the cache's background goroutines
+func (c *Ristretto) Close() {
+	c.store.Close()
+}
+
+// set stores a value and waits for ristretto's write buffer so the value is
+// visible to the next Get. Callers must hold mu.
+func (c *Ristretto) set(key string, value []byte, ttl time.Duration) {
+	c.store.SetWithTTL(key, value, entrySize(key, value), ttl)
+	c.store.Wait()
+}
+
+// del removes key, waiting for ristretto's buffers so a following Get
+// misses. Callers must hold mu.
+func (c *Ristretto) del(key string) {
+	c.store.Del(key)
+	c.store.Wait()
+}
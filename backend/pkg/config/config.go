@@ -1,12 +1,58 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Resume storage modes
+const (
+	ResumeStorageRelational = "relational"
+	ResumeStorageDocument   = "document"
+)
+
+// Redis topologies
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// AI providers
+const (
+	AIProviderOpenAI = "openai"
+)
+
+// Cache drivers
+const (
+	CacheDriverRedis  = "redis"
+	CacheDriverMemory = "memory"
+)
+
+// JWT claims modes
+const (
+	JWTClaimsFull    = "full"
+	JWTClaimsMinimal = "minimal"
+)
+
+// File storage backends
+const (
+	StorageBackendLocal = "local"
+	StorageBackendS3    = "s3"
 )
 
 // Config holds all application configuration
@@ -16,21 +62,366 @@ type Config struct {
 	RedisUrl  string
 	JWTSecret string
 	CSRFKey   string
+
+	// Database connection security. DBMinSSLMode refuses to start when the
+	// sslmode of DBUrl is weaker; DBSSLPins are base64 SHA-256 hashes of the
+	// server public keys accepted. DBIAMRegion authenticates with AWS RDS IAM
+	// tokens instead of a password, and DBPasswordFile is re-read for each
+	// new connection so a rotated password needs no restart.
+	DBMinSSLMode   string
+	DBSSLPins      []string
+	DBIAMRegion    string
+	DBPasswordFile string
+
+	// Database pool sizing; zero fields keep the pool defaults
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+	// DBPreparedStatements runs queries as prepared statements (default
+	// true). Turn it off behind a transaction-pooling proxy such as
+	// PgBouncer.
+	DBPreparedStatements bool
+
+	// MetricsToken enables GET /metrics for scrapers that send it as a
+	// bearer token
+	MetricsToken string
+
+	// HTTP server timeouts
+	ServerReadTimeout  time.Duration
+	ServerWriteTimeout time.Duration
+	ServerIdleTimeout  time.Duration
+
+	// JWTLeeway is the clock skew tolerated when validating tokens; zero uses
+	// the default and a negative value disables it
+	JWTLeeway time.Duration
+
+	// JWTClaimsMode selects what access and refresh tokens carry: "full"
+	// embeds the user's email and role, "minimal" only the subject, token ID
+	// and scope, leaving the server to look the rest up for each request
+	JWTClaimsMode string
+
+	// Token lifetimes
+	JWTAccessTokenExpiry      time.Duration
+	JWTRefreshTokenExpiry     time.Duration
+	JWTResetTokenExpiry       time.Duration
+	JWTEmailChangeTokenExpiry time.Duration
+	JWTMagicLinkTokenExpiry   time.Duration
+
+	// Session policies, off when zero. Refresh is denied once a session has
+	// been idle for SessionIdleTimeout or open for SessionMaxLifetime.
+	SessionIdleTimeout time.Duration
+	SessionMaxLifetime time.Duration
+
+	// JWT key rotation. Tokens are signed with the private key when
+	// JWTPrivateKeyFile is set and with JWTSecret otherwise. Retired secrets
+	// and public keys keep verifying the tokens they signed until removed.
+	JWTPreviousSecrets []string
+	JWTPrivateKeyFile  string
+	JWTPublicKeyFiles  []string
+
+	// Outgoing mail; email is logged instead of sent when SMTPHost is empty
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// Outgoing text messages through Twilio; texts are logged instead of
+	// sent when TwilioAccountSID is empty. TwilioFrom is a number or a
+	// messaging service SID.
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string
+
+	// Passkey sign-in; it is off when WebAuthnRPID, the domain passkeys are
+	// bound to, is empty. WebAuthnRPOrigins are the origins of the pages
+	// passkeys are used from, the CORS origins by default.
+	WebAuthnRPID      string
+	WebAuthnRPName    string
+	WebAuthnRPOrigins []string
+
+	// CORSAllowedOrigins are the browser origins allowed to call the API,
+	// such as "https://app.example.com", or "https://*.example.com" for every
+	// subdomain
+	CORSAllowedOrigins []string
+
+	// CookieSecure marks cookies Secure so browsers only send them over
+	// HTTPS (default true). Turn it off only for local development over
+	// plain HTTP.
+	CookieSecure bool
+
+	// EmailFoldPlusTags drops the "+tag" of email local parts, so
+	// user+jobs@example.com signs in to and can't register next to
+	// user@example.com
+	EmailFoldPlusTags bool
+
+	// MaxRequestBodyBytes caps the size of JSON request bodies
+	MaxRequestBodyBytes int64
+
+	// TrustedProxies are the addresses and CIDR ranges of the reverse
+	// proxies in front of the API, whose X-Forwarded-For and X-Real-IP
+	// headers are believed. When empty the headers are believed from any
+	// client.
+	TrustedProxies []string
+
+	// ResumeStorage selects the resume repository: "relational" or "document"
+	ResumeStorage string
+
+	// CacheDriver selects the cache backend: "redis" or "memory"
+	CacheDriver string
+
+	// StorageBackend selects where files such as profile photos, data
+	// exports and import rosters are kept: "local" (the default), in
+	// StorageLocalDir, or "s3", in an S3-compatible bucket
+	StorageBackend  string
+	StorageLocalDir string
+	// StorageURLSecret signs the download URLs of local files, which this
+	// API serves. A random one is used when empty, so URLs only work on the
+	// instance that signed them until it restarts.
+	StorageURLSecret string
+	// S3Endpoint is the store's URL, AWS S3 in S3Region when empty.
+	// S3ForcePathStyle puts the bucket in the path, as MinIO needs.
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3ForcePathStyle  bool
+
+	// ResumeLimits caps the entries per resume section; zero fields use the service defaults
+	ResumeLimits ResumeLimits
+
+	// RateLimits sets the requests per minute of each rate limit policy;
+	// zero fields keep the policy defaults
+	RateLimits RateLimits
+
+	// ResumeSendDailyLimit caps the resumes each user can email per UTC day
+	ResumeSendDailyLimit int
+
+	// PublicBaseURL is the public URL of this API that published resume
+	// pages are linked at, such as "https://resumes.example.com"; page links
+	// are relative and pages have no canonical URL when empty
+	PublicBaseURL string
+
+	// RedisMode selects the Redis topology: a single server at RedisUrl
+	// ("standalone", the default), or the Sentinel or cluster nodes in
+	// RedisAddrs ("sentinel" or "cluster")
+	RedisMode string
+	// RedisAddrs are the Sentinel or cluster nodes, as host:port
+	RedisAddrs []string
+	// RedisMasterName is the master the Sentinel nodes are asked for
+	RedisMasterName string
+	// Redis credentials, overriding any in RedisUrl
+	RedisUsername         string
+	RedisPassword         string
+	RedisSentinelPassword string
+	// RedisTLS connects to Redis over TLS, verifying server certificates
+	// with the CAs in RedisTLSCAFile when set, otherwise the system roots.
+	// Standalone servers also enable TLS with a rediss:// URL.
+	RedisTLS       bool
+	RedisTLSCAFile string
+
+	// RedisOptional starts the server even when Redis can't be reached,
+	// with the features that need it degraded until it comes back
+	RedisOptional bool
+
+	// RateLimitFailClosed rejects requests to the login, registration and
+	// password reset endpoints while Redis is down, instead of counting them
+	// per instance
+	RateLimitFailClosed bool
+
+	// LogBodySampleRate is the fraction of JSON request bodies logged, with
+	// passwords and tokens redacted; zero logs none
+	LogBodySampleRate float64
+	// SlowRequestThreshold logs requests that take longer as warnings
+	SlowRequestThreshold time.Duration
+
+	// Tracing exports OpenTelemetry spans to an OTLP/HTTP collector when
+	// TracingEndpoint is set
+	TracingEndpoint    string
+	TracingServiceName string
+	// TracingSampleRatio is the fraction of new traces recorded (default 1)
+	TracingSampleRatio float64
+
+	// OAuth sign-in; each provider is enabled when its client ID is set.
+	// OAuthRedirectBaseURL is the public URL of this API that provider
+	// callbacks are registered under, such as "https://api.example.com".
+	OAuthRedirectBaseURL string
+	GoogleClientID       string
+	GoogleClientSecret   string
+	GitHubClientID       string
+	GitHubClientSecret   string
+
+	// MagicLinkURL enables signing in with links sent by email. It is the
+	// page of the web app the links open, such as
+	// "https://app.example.com/login/magic", which posts the token query
+	// parameter to /api/v1/login/magic-link/verify.
+	MagicLinkURL string
+
+	// AIProvider enables AI-assisted suggestions with a language model;
+	// empty (the default) disables them. "openai" uses any
+	// OpenAI-compatible chat completions API at AIBaseURL, OpenAI's when
+	// empty.
+	AIProvider string
+	AIBaseURL  string
+	AIAPIKey   string
+	AIModel    string
+	// AITimeout bounds each request to the provider
+	AITimeout time.Duration
+
+	// LanguageToolURL is the LanguageTool server proofreading uses, such as
+	// "https://api.languagetool.org"; empty (the default) uses the offline
+	// basic checker. The username and API key are for LanguageTool Premium.
+	LanguageToolURL      string
+	LanguageToolUsername string
+	LanguageToolAPIKey   string
+
+	// ConnectorGitHubToken authenticates the GitHub connector's API calls,
+	// raising GitHub's rate limit; calls are anonymous when empty
+	ConnectorGitHubToken string
+}
+
+// ResumeLimits holds the per-section entry caps read from RESUME_MAX_* variables
+type ResumeLimits struct {
+	MaxEducation           int
+	MaxExperience          int
+	MaxSkills              int
+	MaxProjects            int
+	MaxCertifications      int
+	MaxProjectTechnologies int
 }
 
-// Load loads configuration from environment variables with validation
+// RateLimits holds the requests per minute read from RATE_LIMIT_* variables
+type RateLimits struct {
+	// Strict guards login, registration and password reset
+	Strict int
+	// Default applies to writes
+	Default int
+	// Lenient applies to reads
+	Lenient int
+}
+
+// Load loads configuration from environment variables with validation.
+// Variables can also be set in the YAML file named by CONFIG_FILE, keyed by
+// the variable name; variables set in the environment take precedence. Every
+// problem found is reported in the returned error, not just the first.
 func Load() (*Config, error) {
 	// Try to load .env file, continue if not found
 	if err := godotenv.Load(); err != nil {
 		log.Info().Msg("No .env file found, using environment variables")
 	}
 
+	src := &source{}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CONFIG_FILE: %w", err)
+		}
+		src.file = file
+		log.Info().Str("path", path).Msg("Loaded configuration file")
+	}
+
 	config := &Config{
-		Port:      os.Getenv("PORT"),
-		DBUrl:     os.Getenv("DB_URL"),
-		RedisUrl:  os.Getenv("REDIS_URL"),
-		JWTSecret: os.Getenv("JWT_SECRET"),
-		CSRFKey:   os.Getenv("CSRF_KEY"),
+		Port:      src.get("PORT"),
+		DBUrl:     src.get("DB_URL"),
+		RedisUrl:  src.get("REDIS_URL"),
+		JWTSecret: src.get("JWT_SECRET"),
+		CSRFKey:   src.get("CSRF_KEY"),
+
+		JWTClaimsMode: src.get("JWT_CLAIMS_MODE"),
+
+		DBMinSSLMode:   src.get("DB_MIN_SSLMODE"),
+		DBSSLPins:      splitList(src.get("DB_SSL_PINS")),
+		DBIAMRegion:    src.get("DB_IAM_REGION"),
+		DBPasswordFile: src.get("DB_PASSWORD_FILE"),
+
+		DBPreparedStatements: true,
+
+		MetricsToken: src.get("METRICS_TOKEN"),
+
+		ServerReadTimeout:  15 * time.Second,
+		ServerWriteTimeout: 15 * time.Second,
+		ServerIdleTimeout:  60 * time.Second,
+
+		JWTAccessTokenExpiry:      15 * time.Minute,
+		JWTRefreshTokenExpiry:     7 * 24 * time.Hour, // 7 days
+		JWTResetTokenExpiry:       time.Hour,
+		JWTEmailChangeTokenExpiry: 24 * time.Hour,
+		JWTMagicLinkTokenExpiry:   15 * time.Minute,
+
+		JWTPreviousSecrets: splitList(src.get("JWT_PREVIOUS_SECRETS")),
+		JWTPrivateKeyFile:  src.get("JWT_PRIVATE_KEY_FILE"),
+		JWTPublicKeyFiles:  splitList(src.get("JWT_PUBLIC_KEY_FILES")),
+
+		SMTPHost:     src.get("SMTP_HOST"),
+		SMTPPort:     src.get("SMTP_PORT"),
+		SMTPUsername: src.get("SMTP_USERNAME"),
+		SMTPPassword: src.get("SMTP_PASSWORD"),
+		MailFrom:     src.get("MAIL_FROM"),
+
+		TwilioAccountSID: src.get("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:  src.get("TWILIO_AUTH_TOKEN"),
+		TwilioFrom:       src.get("TWILIO_FROM"),
+
+		WebAuthnRPID:      src.get("WEBAUTHN_RP_ID"),
+		WebAuthnRPName:    src.get("WEBAUTHN_RP_NAME"),
+		WebAuthnRPOrigins: splitList(src.get("WEBAUTHN_RP_ORIGINS")),
+
+		CORSAllowedOrigins: []string{"http://localhost:5173"},
+		CookieSecure:       true,
+		// Matches the default JSON body limit of the handlers
+		MaxRequestBodyBytes: 1 << 20,
+		TrustedProxies:      splitList(src.get("TRUSTED_PROXIES")),
+
+		RedisMode:             src.get("REDIS_MODE"),
+		RedisAddrs:            splitList(src.get("REDIS_ADDRS")),
+		RedisMasterName:       src.get("REDIS_MASTER_NAME"),
+		RedisUsername:         src.get("REDIS_USERNAME"),
+		RedisPassword:         src.get("REDIS_PASSWORD"),
+		RedisSentinelPassword: src.get("REDIS_SENTINEL_PASSWORD"),
+		RedisTLSCAFile:        src.get("REDIS_TLS_CA_FILE"),
+
+		ResumeStorage: src.get("RESUME_STORAGE"),
+
+		CacheDriver: src.get("CACHE_DRIVER"),
+
+		StorageBackend:    src.get("STORAGE_BACKEND"),
+		StorageLocalDir:   src.get("STORAGE_LOCAL_DIR"),
+		StorageURLSecret:  src.get("STORAGE_URL_SECRET"),
+		S3Endpoint:        strings.TrimSuffix(src.get("S3_ENDPOINT"), "/"),
+		S3Region:          src.get("S3_REGION"),
+		S3Bucket:          src.get("S3_BUCKET"),
+		S3AccessKeyID:     src.get("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey: src.get("S3_SECRET_ACCESS_KEY"),
+
+		TracingEndpoint:    src.get("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		TracingServiceName: src.get("OTEL_SERVICE_NAME"),
+		TracingSampleRatio: 1,
+
+		OAuthRedirectBaseURL: strings.TrimSuffix(src.get("OAUTH_REDIRECT_BASE_URL"), "/"),
+		GoogleClientID:       src.get("GOOGLE_CLIENT_ID"),
+		GoogleClientSecret:   src.get("GOOGLE_CLIENT_SECRET"),
+		GitHubClientID:       src.get("GITHUB_CLIENT_ID"),
+		GitHubClientSecret:   src.get("GITHUB_CLIENT_SECRET"),
+
+		MagicLinkURL: src.get("MAGIC_LINK_URL"),
+
+		AIProvider: src.get("AI_PROVIDER"),
+		AIBaseURL:  strings.TrimSuffix(src.get("AI_BASE_URL"), "/"),
+		AIAPIKey:   src.get("AI_API_KEY"),
+		AIModel:    src.get("AI_MODEL"),
+		AITimeout:  30 * time.Second,
+
+		LanguageToolURL:      strings.TrimSuffix(src.get("LANGUAGETOOL_URL"), "/"),
+		LanguageToolUsername: src.get("LANGUAGETOOL_USERNAME"),
+		LanguageToolAPIKey:   src.get("LANGUAGETOOL_API_KEY"),
+
+		ConnectorGitHubToken: src.get("CONNECTOR_GITHUB_TOKEN"),
+
+		ResumeSendDailyLimit: 10,
+
+		PublicBaseURL: strings.TrimSuffix(src.get("PUBLIC_BASE_URL"), "/"),
 	}
 
 	// Validate configuration
@@ -45,11 +436,26 @@ func Load() (*Config, error) {
 		missingVars = append(missingVars, "DB_URL")
 	}
 
-	if config.RedisUrl == "" {
-		missingVars = append(missingVars, "REDIS_URL")
+	switch config.RedisMode {
+	case "", RedisModeStandalone:
+		config.RedisMode = RedisModeStandalone
+		if config.RedisUrl == "" {
+			missingVars = append(missingVars, "REDIS_URL")
+		}
+	case RedisModeSentinel:
+		if len(config.RedisAddrs) == 0 {
+			missingVars = append(missingVars, "REDIS_ADDRS")
+		}
+		if config.RedisMasterName == "" {
+			missingVars = append(missingVars, "REDIS_MASTER_NAME")
+		}
+	case RedisModeCluster:
+		if len(config.RedisAddrs) == 0 {
+			missingVars = append(missingVars, "REDIS_ADDRS")
+		}
 	}
 
-	if config.JWTSecret == "" {
+	if config.JWTSecret == "" && config.JWTPrivateKeyFile == "" {
 		missingVars = append(missingVars, "JWT_SECRET")
 	}
 
@@ -57,9 +463,429 @@ func Load() (*Config, error) {
 		missingVars = append(missingVars, "CSRF_KEY")
 	}
 
+	if config.SMTPHost != "" && config.MailFrom == "" {
+		missingVars = append(missingVars, "MAIL_FROM")
+	}
+
+	if config.TwilioAccountSID != "" {
+		if config.TwilioAuthToken == "" {
+			missingVars = append(missingVars, "TWILIO_AUTH_TOKEN")
+		}
+		if config.TwilioFrom == "" {
+			missingVars = append(missingVars, "TWILIO_FROM")
+		}
+	}
+
+	if config.GoogleClientID != "" && config.GoogleClientSecret == "" {
+		missingVars = append(missingVars, "GOOGLE_CLIENT_SECRET")
+	}
+
+	if config.GitHubClientID != "" && config.GitHubClientSecret == "" {
+		missingVars = append(missingVars, "GITHUB_CLIENT_SECRET")
+	}
+
+	if (config.GoogleClientID != "" || config.GitHubClientID != "") && config.OAuthRedirectBaseURL == "" {
+		missingVars = append(missingVars, "OAUTH_REDIRECT_BASE_URL")
+	}
+
+	if config.AIProvider != "" && config.AIModel == "" {
+		missingVars = append(missingVars, "AI_MODEL")
+	}
+
+	if config.StorageBackend == StorageBackendS3 {
+		if config.S3Bucket == "" {
+			missingVars = append(missingVars, "S3_BUCKET")
+		}
+		if config.S3AccessKeyID == "" {
+			missingVars = append(missingVars, "S3_ACCESS_KEY_ID")
+		}
+		if config.S3SecretAccessKey == "" {
+			missingVars = append(missingVars, "S3_SECRET_ACCESS_KEY")
+		}
+	}
+
 	if len(missingVars) > 0 {
-		return nil, errors.New("missing required environment variables: " + strings.Join(missingVars, ", "))
+		src.fail("missing required environment variables: " + strings.Join(missingVars, ", "))
+	}
+
+	if !validPort(config.Port) {
+		src.invalid("PORT", "a port number from 1 to 65535")
+	}
+
+	if config.SMTPPort != "" && !validPort(config.SMTPPort) {
+		src.invalid("SMTP_PORT", "a port number from 1 to 65535")
+	}
+
+	switch config.DBMinSSLMode {
+	case "", "disable", "require", "verify-ca", "verify-full":
+	default:
+		src.invalid("DB_MIN_SSLMODE", "\"disable\", \"require\", \"verify-ca\" or \"verify-full\"")
+	}
+
+	for _, pin := range config.DBSSLPins {
+		if hash, err := base64.StdEncoding.DecodeString(pin); err != nil || len(hash) != sha256.Size {
+			src.invalid("DB_SSL_PINS", "a list of base64 SHA-256 public key hashes: "+strconv.Quote(pin)+" is not one")
+			break
+		}
+	}
+
+	if config.DBIAMRegion != "" && config.DBPasswordFile != "" {
+		src.fail("DB_IAM_REGION and DB_PASSWORD_FILE can't both be set")
+	}
+
+	src.positiveInt("DB_MAX_OPEN_CONNS", &config.DBMaxOpenConns)
+	src.positiveInt("DB_MAX_IDLE_CONNS", &config.DBMaxIdleConns)
+	if config.DBMaxOpenConns > 0 && config.DBMaxIdleConns > config.DBMaxOpenConns {
+		src.invalid("DB_MAX_IDLE_CONNS", "at most DB_MAX_OPEN_CONNS")
+	}
+	src.positiveDuration("DB_CONN_MAX_LIFETIME", &config.DBConnMaxLifetime)
+	src.positiveDuration("DB_CONN_MAX_IDLE_TIME", &config.DBConnMaxIdleTime)
+	src.boolean("DB_PREPARED_STATEMENTS", &config.DBPreparedStatements)
+
+	switch config.RedisMode {
+	case RedisModeStandalone, RedisModeSentinel, RedisModeCluster:
+	default:
+		src.invalid("REDIS_MODE", "\""+RedisModeStandalone+"\", \""+RedisModeSentinel+"\" or \""+RedisModeCluster+"\"")
+	}
+
+	for _, addr := range config.RedisAddrs {
+		if _, port, err := net.SplitHostPort(addr); err != nil || !validPort(port) {
+			src.invalid("REDIS_ADDRS", "a list of host:port addresses: "+strconv.Quote(addr)+" is not one")
+			break
+		}
+	}
+
+	src.boolean("REDIS_TLS", &config.RedisTLS)
+
+	if config.MailFrom != "" {
+		if _, err := mail.ParseAddress(config.MailFrom); err != nil {
+			src.invalid("MAIL_FROM", "an email address such as \"Resumes <noreply@example.com>\"")
+		}
+	}
+
+	switch config.ResumeStorage {
+	case "":
+		// Default to one table per resume section
+		config.ResumeStorage = ResumeStorageRelational
+	case ResumeStorageRelational, ResumeStorageDocument:
+	default:
+		src.invalid("RESUME_STORAGE", "\""+ResumeStorageRelational+"\" or \""+ResumeStorageDocument+"\"")
+	}
+
+	src.positiveInt("RESUME_MAX_EDUCATION", &config.ResumeLimits.MaxEducation)
+	src.positiveInt("RESUME_MAX_EXPERIENCE", &config.ResumeLimits.MaxExperience)
+	src.positiveInt("RESUME_MAX_SKILLS", &config.ResumeLimits.MaxSkills)
+	src.positiveInt("RESUME_MAX_PROJECTS", &config.ResumeLimits.MaxProjects)
+	src.positiveInt("RESUME_MAX_CERTIFICATIONS", &config.ResumeLimits.MaxCertifications)
+	src.positiveInt("RESUME_MAX_PROJECT_TECHNOLOGIES", &config.ResumeLimits.MaxProjectTechnologies)
+	src.positiveInt("RESUME_SEND_DAILY_LIMIT", &config.ResumeSendDailyLimit)
+
+	src.positiveInt("RATE_LIMIT_STRICT", &config.RateLimits.Strict)
+	src.positiveInt("RATE_LIMIT_DEFAULT", &config.RateLimits.Default)
+	src.positiveInt("RATE_LIMIT_LENIENT", &config.RateLimits.Lenient)
+
+	src.positiveDuration("SERVER_READ_TIMEOUT", &config.ServerReadTimeout)
+	src.positiveDuration("SERVER_WRITE_TIMEOUT", &config.ServerWriteTimeout)
+	src.positiveDuration("SERVER_IDLE_TIMEOUT", &config.ServerIdleTimeout)
+
+	if raw := src.get("JWT_LEEWAY"); raw != "" {
+		leeway, err := time.ParseDuration(raw)
+		if err != nil {
+			src.invalid("JWT_LEEWAY", "a duration such as \"30s\"")
+		}
+		config.JWTLeeway = leeway
+	}
+
+	switch config.JWTClaimsMode {
+	case "":
+		// Default to the tokens issued before minimal ones existed
+		config.JWTClaimsMode = JWTClaimsFull
+	case JWTClaimsFull, JWTClaimsMinimal:
+	default:
+		src.invalid("JWT_CLAIMS_MODE", "\""+JWTClaimsFull+"\" or \""+JWTClaimsMinimal+"\"")
+	}
+
+	src.positiveDuration("JWT_ACCESS_TOKEN_EXPIRY", &config.JWTAccessTokenExpiry)
+	src.positiveDuration("JWT_REFRESH_TOKEN_EXPIRY", &config.JWTRefreshTokenExpiry)
+	src.positiveDuration("JWT_RESET_TOKEN_EXPIRY", &config.JWTResetTokenExpiry)
+	src.positiveDuration("JWT_EMAIL_CHANGE_TOKEN_EXPIRY", &config.JWTEmailChangeTokenExpiry)
+	src.positiveDuration("JWT_MAGIC_LINK_TOKEN_EXPIRY", &config.JWTMagicLinkTokenExpiry)
+	if config.JWTRefreshTokenExpiry <= config.JWTAccessTokenExpiry {
+		src.invalid("JWT_REFRESH_TOKEN_EXPIRY", "longer than JWT_ACCESS_TOKEN_EXPIRY")
+	}
+	src.positiveDuration("SESSION_IDLE_TIMEOUT", &config.SessionIdleTimeout)
+	src.positiveDuration("SESSION_MAX_LIFETIME", &config.SessionMaxLifetime)
+
+	if raw := src.get("CORS_ALLOWED_ORIGINS"); raw != "" {
+		config.CORSAllowedOrigins = splitList(raw)
+		for _, origin := range config.CORSAllowedOrigins {
+			if !validOrigin(origin) {
+				src.invalid("CORS_ALLOWED_ORIGINS", "a list of origins such as \"https://app.example.com\" or \"https://*.example.com\", without a path or trailing slash: "+strconv.Quote(origin)+" is not one")
+				break
+			}
+		}
+	}
+
+	if config.WebAuthnRPID != "" {
+		if len(config.WebAuthnRPOrigins) == 0 {
+			config.WebAuthnRPOrigins = config.CORSAllowedOrigins
+		}
+		for _, origin := range config.WebAuthnRPOrigins {
+			// Browsers report the exact origin, so wildcards can't match it
+			if !validOrigin(origin) || strings.Contains(origin, "*") {
+				src.invalid("WEBAUTHN_RP_ORIGINS", "a list of origins such as \"https://app.example.com\", without a wildcard, path or trailing slash: "+strconv.Quote(origin)+" is not one")
+				break
+			}
+		}
+	}
+
+	src.boolean("COOKIE_SECURE", &config.CookieSecure)
+	src.boolean("EMAIL_FOLD_PLUS_TAGS", &config.EmailFoldPlusTags)
+
+	if raw := src.get("MAX_REQUEST_BODY_BYTES"); raw != "" {
+		size, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || size < 1 {
+			src.invalid("MAX_REQUEST_BODY_BYTES", "a positive number of bytes")
+		}
+		config.MaxRequestBodyBytes = size
+	}
+
+	for _, proxy := range config.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			src.invalid("TRUSTED_PROXIES", "a list of IP addresses and CIDR ranges: "+strconv.Quote(proxy)+" is neither")
+			break
+		}
+	}
+
+	src.boolean("REDIS_OPTIONAL", &config.RedisOptional)
+	src.boolean("RATE_LIMIT_FAIL_CLOSED", &config.RateLimitFailClosed)
+
+	if raw := src.get("LOG_BODY_SAMPLE_RATE"); raw != "" {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			src.invalid("LOG_BODY_SAMPLE_RATE", "a number from 0 to 1")
+		}
+		config.LogBodySampleRate = rate
+	}
+
+	src.positiveDuration("SLOW_REQUEST_THRESHOLD", &config.SlowRequestThreshold)
+
+	if raw := src.get("OTEL_TRACES_SAMPLER_ARG"); raw != "" {
+		ratio, err := strconv.ParseFloat(raw, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			src.invalid("OTEL_TRACES_SAMPLER_ARG", "a number from 0 to 1")
+		}
+		config.TracingSampleRatio = ratio
+	}
+
+	switch config.CacheDriver {
+	case "":
+		// Default to the shared Redis instance
+		config.CacheDriver = CacheDriverRedis
+	case CacheDriverRedis, CacheDriverMemory:
+	default:
+		src.invalid("CACHE_DRIVER", "\""+CacheDriverRedis+"\" or \""+CacheDriverMemory+"\"")
+	}
+
+	switch config.StorageBackend {
+	case "":
+		config.StorageBackend = StorageBackendLocal
+	case StorageBackendLocal, StorageBackendS3:
+	default:
+		src.invalid("STORAGE_BACKEND", "\""+StorageBackendLocal+"\" or \""+StorageBackendS3+"\"")
+	}
+	if config.StorageLocalDir == "" {
+		config.StorageLocalDir = "data/uploads"
+	}
+	if config.S3Endpoint != "" {
+		if u, err := url.Parse(config.S3Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.invalid("S3_ENDPOINT", "an http or https URL such as \"https://s3.us-east-1.amazonaws.com\"")
+		}
+	}
+	src.boolean("S3_FORCE_PATH_STYLE", &config.S3ForcePathStyle)
+
+	switch config.AIProvider {
+	case "", AIProviderOpenAI:
+	default:
+		src.invalid("AI_PROVIDER", "\""+AIProviderOpenAI+"\" or empty")
+	}
+
+	if config.AIBaseURL != "" {
+		if u, err := url.Parse(config.AIBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.invalid("AI_BASE_URL", "an http or https URL such as \"https://api.openai.com/v1\"")
+		}
+	}
+
+	src.positiveDuration("AI_TIMEOUT", &config.AITimeout)
+
+	if config.LanguageToolURL != "" {
+		if u, err := url.Parse(config.LanguageToolURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.invalid("LANGUAGETOOL_URL", "an http or https URL such as \"https://api.languagetool.org\"")
+		}
+	}
+	if config.PublicBaseURL != "" {
+		if u, err := url.Parse(config.PublicBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.invalid("PUBLIC_BASE_URL", "an http or https URL such as \"https://resumes.example.com\"")
+		}
+	}
+	if config.MagicLinkURL != "" {
+		if u, err := url.Parse(config.MagicLinkURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.invalid("MAGIC_LINK_URL", "an http or https URL such as \"https://app.example.com/login/magic\"")
+		}
+	}
+
+	if len(src.problems) > 0 {
+		return nil, errors.New(strings.Join(src.problems, "; "))
+	}
+
+	if !config.CookieSecure {
+		log.Warn().Msg("COOKIE_SECURE is off, cookies will be sent over plain HTTP")
 	}
 
 	return config, nil
 }
+
+// source reads configuration values from the environment, falling back to
+// the configuration file, and collects the problems found with them
+type source struct {
+	file     map[string]string
+	problems []string
+}
+
+// get returns the value of a variable, or "" when it is unset
+func (s *source) get(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return s.file[key]
+}
+
+// fail records a problem with the configuration
+func (s *source) fail(problem string) {
+	s.problems = append(s.problems, problem)
+}
+
+// invalid records a variable whose value isn't what it must be
+func (s *source) invalid(key, must string) {
+	s.fail("invalid " + key + ": must be " + must)
+}
+
+// positiveInt parses a positive integer variable into dst when it is set
+func (s *source) positiveInt(key string, dst *int) {
+	raw := s.get(key)
+	if raw == "" {
+		return
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		s.invalid(key, "a positive integer")
+		return
+	}
+	*dst = value
+}
+
+// positiveDuration parses a positive duration variable into dst when it is set
+func (s *source) positiveDuration(key string, dst *time.Duration) {
+	raw := s.get(key)
+	if raw == "" {
+		return
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		s.invalid(key, "a positive duration such as \"1s\"")
+		return
+	}
+	*dst = value
+}
+
+// boolean parses a boolean variable into dst when it is set
+func (s *source) boolean(key string, dst *bool) {
+	raw := s.get(key)
+	if raw == "" {
+		return
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		s.invalid(key, "true or false")
+		return
+	}
+	*dst = value
+}
+
+// readFile reads a YAML configuration file of variables. Keys are variable
+// names, matched case-insensitively; values are scalars, or lists for the
+// comma-separated variables.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if _, ok := item.(map[string]any); ok {
+					return nil, fmt.Errorf("%s: %s must be a value or a list of values", path, key)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			values[strings.ToUpper(key)] = strings.Join(items, ",")
+		case map[string]any:
+			return nil, fmt.Errorf("%s: %s must be a value or a list of values", path, key)
+		default:
+			values[strings.ToUpper(key)] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
+
+// validPort reports whether a port is a number from 1 to 65535
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+// validOrigin reports whether an origin is a scheme and host with nothing
+// after. The host may start with a "*." wildcard for the subdomains of a
+// domain with at least two labels, so "https://*.com" is refused. A bare "*"
+// is refused too: the API allows credentials, so it would let any site make
+// requests as the signed-in user.
+func validOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return false
+	}
+	host := u.Hostname()
+	if domain, ok := strings.CutPrefix(host, "*."); ok {
+		host = domain
+		if !strings.Contains(domain, ".") {
+			return false
+		}
+	}
+	return !strings.Contains(host, "*")
+}
+
+// splitList splits a comma-separated variable, dropping empty entries
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
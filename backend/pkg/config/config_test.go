@@ -0,0 +1,335 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setRequired sets the variables Load requires
+func setRequired(t *testing.T) {
+	t.Setenv("DB_URL", "postgres://localhost/resume_generator")
+	t.Setenv("REDIS_URL", "redis://localhost:6379/0")
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("CSRF_KEY", "csrf")
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Port)
+	assert.Equal(t, 15*time.Minute, cfg.JWTAccessTokenExpiry)
+	assert.Equal(t, 7*24*time.Hour, cfg.JWTRefreshTokenExpiry)
+	assert.Equal(t, []string{"http://localhost:5173"}, cfg.CORSAllowedOrigins)
+	assert.True(t, cfg.CookieSecure)
+	assert.False(t, cfg.EmailFoldPlusTags)
+	assert.True(t, cfg.DBPreparedStatements)
+	assert.EqualValues(t, 1<<20, cfg.MaxRequestBodyBytes)
+	assert.Equal(t, 15*time.Second, cfg.ServerReadTimeout)
+	assert.Equal(t, 10, cfg.ResumeSendDailyLimit)
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	setRequired(t)
+	t.Setenv("CSRF_KEY", "")
+	t.Setenv("JWT_ACCESS_TOKEN_EXPIRY", "soon")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com,https://example.com/app")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,proxy.internal")
+	t.Setenv("RATE_LIMIT_STRICT", "0")
+
+	_, err := Load()
+	require.Error(t, err)
+	for _, want := range []string{
+		"missing required environment variables: CSRF_KEY",
+		"invalid JWT_ACCESS_TOKEN_EXPIRY",
+		`invalid CORS_ALLOWED_ORIGINS: must be a list of origins such as "https://app.example.com" or "https://*.example.com", without a path or trailing slash: "https://example.com/app" is not one`,
+		`"proxy.internal" is neither`,
+		"invalid RATE_LIMIT_STRICT: must be a positive integer",
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadRejectsRefreshShorterThanAccess(t *testing.T) {
+	setRequired(t)
+	t.Setenv("JWT_ACCESS_TOKEN_EXPIRY", "2h")
+	t.Setenv("JWT_REFRESH_TOKEN_EXPIRY", "1h")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid JWT_REFRESH_TOKEN_EXPIRY: must be longer than JWT_ACCESS_TOKEN_EXPIRY")
+}
+
+func TestLoadFromFile(t *testing.T) {
+	setRequired(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+port: 9090
+JWT_ACCESS_TOKEN_EXPIRY: 5m
+CORS_ALLOWED_ORIGINS:
+  - https://app.example.com
+  - https://admin.example.com
+COOKIE_SECURE: false
+RATE_LIMIT_LENIENT: 1200
+`), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+	// The environment takes precedence over the file
+	t.Setenv("JWT_ACCESS_TOKEN_EXPIRY", "10m")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.Port)
+	assert.Equal(t, 10*time.Minute, cfg.JWTAccessTokenExpiry)
+	assert.Equal(t, []string{"https://app.example.com", "https://admin.example.com"}, cfg.CORSAllowedOrigins)
+	assert.False(t, cfg.CookieSecure)
+	assert.Equal(t, 1200, cfg.RateLimits.Lenient)
+}
+
+func TestLoadRejectsNestedFileValues(t *testing.T) {
+	setRequired(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("smtp:\n  host: mail.example.com\n"), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "smtp must be a value or a list of values")
+}
+
+func TestLoadCORSAllowedOrigins(t *testing.T) {
+	setRequired(t)
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, https://*.example.com,http://localhost:5173")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com", "https://*.example.com", "http://localhost:5173"}, cfg.CORSAllowedOrigins)
+
+	for _, origin := range []string{"*", "https://*.com", "https://app.*.example.com", "https://app.example.com/", "app.example.com"} {
+		t.Setenv("CORS_ALLOWED_ORIGINS", origin)
+		_, err := Load()
+		assert.ErrorContains(t, err, "invalid CORS_ALLOWED_ORIGINS", origin)
+	}
+}
+
+func TestLoadRedisModes(t *testing.T) {
+	setRequired(t)
+	t.Setenv("REDIS_URL", "")
+	t.Setenv("REDIS_MODE", "sentinel")
+	t.Setenv("REDIS_ADDRS", "sentinel-1:26379, sentinel-2:26379")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "missing required environment variables: REDIS_MASTER_NAME")
+
+	t.Setenv("REDIS_MASTER_NAME", "mymaster")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sentinel-1:26379", "sentinel-2:26379"}, cfg.RedisAddrs)
+
+	t.Setenv("REDIS_MODE", "cluster")
+	t.Setenv("REDIS_ADDRS", "node-1:6379,node-2")
+	_, err = Load()
+	assert.ErrorContains(t, err, `invalid REDIS_ADDRS: must be a list of host:port addresses: "node-2" is not one`)
+
+	t.Setenv("REDIS_MODE", "replicated")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid REDIS_MODE")
+}
+
+func TestLoadDatabaseSecurity(t *testing.T) {
+	setRequired(t)
+	t.Setenv("DB_MIN_SSLMODE", "verify-full")
+	t.Setenv("DB_SSL_PINS", "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	t.Setenv("DB_IAM_REGION", "eu-west-1")
+	t.Setenv("DB_PREPARED_STATEMENTS", "false")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.DBPreparedStatements)
+	assert.Equal(t, "verify-full", cfg.DBMinSSLMode)
+	assert.Equal(t, []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}, cfg.DBSSLPins)
+
+	t.Setenv("DB_MAX_OPEN_CONNS", "10")
+	t.Setenv("DB_MAX_IDLE_CONNS", "20")
+	t.Setenv("DB_MIN_SSLMODE", "prefer")
+	t.Setenv("DB_SSL_PINS", "not-a-hash")
+	t.Setenv("DB_PASSWORD_FILE", "/run/secrets/db-password")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid DB_MIN_SSLMODE")
+	assert.ErrorContains(t, err, `invalid DB_SSL_PINS: must be a list of base64 SHA-256 public key hashes: "not-a-hash" is not one`)
+	assert.ErrorContains(t, err, "DB_IAM_REGION and DB_PASSWORD_FILE can't both be set")
+	assert.ErrorContains(t, err, "invalid DB_MAX_IDLE_CONNS: must be at most DB_MAX_OPEN_CONNS")
+}
+
+func TestLoadAIProvider(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.AIProvider, "AI suggestions are disabled by default")
+
+	t.Setenv("AI_PROVIDER", "openai")
+	_, err = Load()
+	assert.ErrorContains(t, err, "missing required environment variables: AI_MODEL")
+
+	t.Setenv("AI_MODEL", "gpt-4o-mini")
+	t.Setenv("AI_BASE_URL", "http://localhost:11434/v1/")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:11434/v1", cfg.AIBaseURL)
+	assert.Equal(t, 30*time.Second, cfg.AITimeout)
+
+	t.Setenv("AI_PROVIDER", "anthropic")
+	t.Setenv("AI_BASE_URL", "localhost:11434")
+	_, err = Load()
+	assert.ErrorContains(t, err, `invalid AI_PROVIDER: must be "openai" or empty`)
+	assert.ErrorContains(t, err, "invalid AI_BASE_URL")
+}
+
+func TestLoadLanguageToolURL(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.LanguageToolURL, "proofreading is offline by default")
+
+	t.Setenv("LANGUAGETOOL_URL", "http://localhost:8010/")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8010", cfg.LanguageToolURL)
+
+	t.Setenv("LANGUAGETOOL_URL", "localhost:8010")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid LANGUAGETOOL_URL")
+}
+
+func TestLoadPublicBaseURL(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.PublicBaseURL)
+
+	t.Setenv("PUBLIC_BASE_URL", "https://resumes.example.com/")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://resumes.example.com", cfg.PublicBaseURL)
+
+	t.Setenv("PUBLIC_BASE_URL", "resumes.example.com")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid PUBLIC_BASE_URL")
+}
+
+func TestLoadMagicLink(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.MagicLinkURL)
+	assert.Equal(t, 15*time.Minute, cfg.JWTMagicLinkTokenExpiry)
+
+	t.Setenv("MAGIC_LINK_URL", "https://app.example.com/login/magic")
+	t.Setenv("JWT_MAGIC_LINK_TOKEN_EXPIRY", "10m")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/login/magic", cfg.MagicLinkURL)
+	assert.Equal(t, 10*time.Minute, cfg.JWTMagicLinkTokenExpiry)
+
+	t.Setenv("MAGIC_LINK_URL", "app.example.com/login/magic")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid MAGIC_LINK_URL")
+}
+
+func TestLoadJWTClaimsMode(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, JWTClaimsFull, cfg.JWTClaimsMode)
+
+	t.Setenv("JWT_CLAIMS_MODE", "minimal")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, JWTClaimsMinimal, cfg.JWTClaimsMode)
+
+	t.Setenv("JWT_CLAIMS_MODE", "none")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid JWT_CLAIMS_MODE")
+}
+
+func TestLoadSessionPolicies(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.SessionIdleTimeout)
+	assert.Zero(t, cfg.SessionMaxLifetime)
+
+	t.Setenv("SESSION_IDLE_TIMEOUT", "336h")
+	t.Setenv("SESSION_MAX_LIFETIME", "720h")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 14*24*time.Hour, cfg.SessionIdleTimeout)
+	assert.Equal(t, 30*24*time.Hour, cfg.SessionMaxLifetime)
+
+	t.Setenv("SESSION_IDLE_TIMEOUT", "-1h")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid SESSION_IDLE_TIMEOUT")
+}
+
+func TestLoadWebAuthn(t *testing.T) {
+	setRequired(t)
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.WebAuthnRPID)
+	assert.Empty(t, cfg.WebAuthnRPOrigins)
+
+	t.Setenv("WEBAUTHN_RP_ID", "example.com")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com"}, cfg.WebAuthnRPOrigins)
+
+	t.Setenv("WEBAUTHN_RP_ORIGINS", "https://*.example.com")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid WEBAUTHN_RP_ORIGINS")
+}
+
+func TestLoadStorage(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, StorageBackendLocal, cfg.StorageBackend)
+	assert.Equal(t, "data/uploads", cfg.StorageLocalDir)
+	assert.Empty(t, cfg.StorageURLSecret)
+
+	t.Setenv("STORAGE_URL_SECRET", "signing-secret")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "signing-secret", cfg.StorageURLSecret)
+
+	t.Setenv("STORAGE_BACKEND", "s3")
+	_, err = Load()
+	assert.ErrorContains(t, err, "S3_BUCKET, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY")
+
+	t.Setenv("S3_BUCKET", "uploads")
+	t.Setenv("S3_ACCESS_KEY_ID", "key")
+	t.Setenv("S3_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("S3_ENDPOINT", "http://localhost:9000/")
+	t.Setenv("S3_FORCE_PATH_STYLE", "true")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000", cfg.S3Endpoint)
+	assert.True(t, cfg.S3ForcePathStyle)
+
+	t.Setenv("STORAGE_BACKEND", "ftp")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid STORAGE_BACKEND")
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lordaris/resume_generator/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// MigrationCommands lists the commands accepted by Migrate
+var MigrationCommands = []string{"up", "up-by-one", "up-to", "down", "down-to", "redo", "reset", "status", "version"}
+
+// Migrate runs a goose command against the embedded migrations. Commands that
+// take a version, such as up-to and down-to, read it from args.
+func Migrate(ctx context.Context, db *sql.DB, command string, args ...string) error {
+	valid := false
+	for _, c := range MigrationCommands {
+		if c == command {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("unknown migration command %q", command)
+	}
+
+	goose.SetBaseFS(migrations.FS)
+	goose.SetLogger(gooseLogger{})
+	if err := goose.SetDialect("postgres"); err != nil {
+		return err
+	}
+
+	if err := goose.RunContext(ctx, command, db, ".", args...); err != nil {
+		return fmt.Errorf("migrate %s: %w", command, err)
+	}
+	return nil
+}
+
+// gooseLogger sends goose output through zerolog
+type gooseLogger struct{}
+
+func (gooseLogger) Fatalf(format string, v ...any) {
+	log.Fatal().Msg(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
+
+func (gooseLogger) Printf(format string, v ...any) {
+	log.Info().Msg(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
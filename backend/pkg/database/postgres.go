@@ -2,25 +2,93 @@ package database
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
+// SSL modes, from weakest to strongest. An unset sslmode means "require".
+var SSLModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+// PostgresConfig describes how to connect to PostgreSQL and the security
+// the connection must have
+type PostgresConfig struct {
+	// URL is the connection string, as a postgres:// URL or key=value pairs
+	URL string
+	// SSLMode overrides the sslmode of URL
+	SSLMode string
+	// MinSSLMode fails startup when the connection's sslmode is weaker, so
+	// a connection string without TLS can't reach production
+	MinSSLMode string
+	// SSLPins are base64 SHA-256 hashes of server public keys (the
+	// SubjectPublicKeyInfo). When set, the server's certificate, or with a
+	// verify mode any certificate in its verified chain, must have one.
+	SSLPins []string
+	// IAMRegion enables AWS RDS IAM authentication: each connection
+	// authenticates with a token signed for the URL's user and host, valid
+	// for 15 minutes, instead of a password
+	IAMRegion string
+	// PasswordFile is read for the password of each new connection, so a
+	// rotated secret is picked up without a restart
+	PasswordFile string
+	// Password, when set, is called for the password of each new
+	// connection, overriding IAMRegion and PasswordFile
+	Password func(ctx context.Context) (string, error)
+
+	// Pool sizing. MaxOpenConns caps the connections of this instance, so
+	// it times the number of instances must stay under the server's
+	// max_connections. MaxIdleConns defaults to MaxOpenConns, so bursts
+	// reuse connections instead of closing and reopening them.
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnMaxLifetime replaces connections after a while, which also moves
+	// the pool onto rotated credentials; ConnMaxIdleTime closes connections
+	// left unused once a burst is over
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
 // NewPostgres creates a new PostgreSQL connection pool with proper configuration
-func NewPostgres(dbURL string) (*sqlx.DB, error) {
-	// Open database connection
-	db, err := sqlx.Open("postgres", dbURL)
+func NewPostgres(config PostgresConfig) (*sqlx.DB, error) {
+	// Set default values if not provided
+	if config.MaxOpenConns <= 0 {
+		config.MaxOpenConns = 25
+	}
+	if config.MaxIdleConns <= 0 {
+		config.MaxIdleConns = config.MaxOpenConns
+	}
+	if config.ConnMaxLifetime <= 0 {
+		config.ConnMaxLifetime = 5 * time.Minute
+	}
+	if config.ConnMaxIdleTime <= 0 {
+		config.ConnMaxIdleTime = time.Minute
+	}
+
+	connector, err := newPostgresConnector(config)
 	if err != nil {
 		return nil, err
 	}
 
+	// Open database connection; queries are traced when tracing is enabled
+	sqlDB := otelsql.OpenDB(connector, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
+	db := sqlx.NewDb(sqlDB, "postgres")
+
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
 
 	// Verify the connection is working
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
@@ -28,9 +96,199 @@ func NewPostgres(dbURL string) (*sqlx.DB, error) {
 
 	// Ping the database to check connectivity
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	log.Info().Msg("Successfully connected to PostgreSQL database")
+	log.Info().
+		Str("sslmode", connector.sslMode).
+		Bool("iam", config.IAMRegion != "").
+		Int("max_open_conns", config.MaxOpenConns).
+		Int("max_idle_conns", config.MaxIdleConns).
+		Msg("Successfully connected to PostgreSQL database")
 	return db, nil
 }
+
+// postgresConnector opens connections with the configured password source
+// and TLS
+type postgresConnector struct {
+	settings map[string]string
+	sslMode  string
+	password func(ctx context.Context) (string, error)
+	dialer   pq.Dialer
+}
+
+func newPostgresConnector(config PostgresConfig) (*postgresConnector, error) {
+	settings, err := parseDSN(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database URL: %w", err)
+	}
+	// Set the session time zone to UTC unless the connection string already
+	// sets one, so timestamps read back from the database are in UTC
+	// whatever the server's default
+	if settings["timezone"] == "" {
+		settings["timezone"] = "UTC"
+	}
+	if config.SSLMode != "" {
+		settings["sslmode"] = config.SSLMode
+	}
+
+	c := &postgresConnector{settings: settings, sslMode: settings["sslmode"], password: config.Password}
+	if c.sslMode == "" {
+		c.sslMode = "require"
+	}
+	strength := slices.Index(SSLModes, c.sslMode)
+	if strength < 0 {
+		return nil, fmt.Errorf("unsupported database sslmode %q: must be one of %s", c.sslMode, strings.Join(SSLModes, ", "))
+	}
+	if config.MinSSLMode != "" {
+		required := slices.Index(SSLModes, config.MinSSLMode)
+		if required < 0 {
+			return nil, fmt.Errorf("unsupported minimum database sslmode %q: must be one of %s", config.MinSSLMode, strings.Join(SSLModes, ", "))
+		}
+		if strength < required {
+			return nil, fmt.Errorf("database sslmode %q is weaker than the required %q: set sslmode=%s in the database URL", c.sslMode, config.MinSSLMode, config.MinSSLMode)
+		}
+	}
+
+	if len(config.SSLPins) > 0 {
+		if c.sslMode == "disable" {
+			return nil, errors.New("database certificate pins need TLS, but sslmode is \"disable\"")
+		}
+		c.dialer, err = newPinningDialer(settings, c.sslMode, config.SSLPins)
+		if err != nil {
+			return nil, err
+		}
+		// The dialer negotiates TLS itself and hands the driver the
+		// encrypted connection
+		settings["sslmode"] = "disable"
+	}
+
+	switch {
+	case c.password != nil:
+	case config.IAMRegion != "":
+		if config.PasswordFile != "" {
+			return nil, errors.New("database IAM authentication and a password file can't both be used")
+		}
+		if c.sslMode == "disable" {
+			return nil, errors.New("database IAM authentication needs TLS, but sslmode is \"disable\"")
+		}
+		c.password, err = rdsIAMPassword(settings, config.IAMRegion)
+		if err != nil {
+			return nil, err
+		}
+	case config.PasswordFile != "":
+		// Fail at startup rather than on the first connection
+		if _, err := readPasswordFile(config.PasswordFile); err != nil {
+			return nil, err
+		}
+		path := config.PasswordFile
+		c.password = func(context.Context) (string, error) {
+			return readPasswordFile(path)
+		}
+	}
+	return c, nil
+}
+
+// Connect opens a connection, asking for the password first when it comes
+// from a hook
+func (c *postgresConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	settings := c.settings
+	if c.password != nil {
+		password, err := c.password(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("database password: %w", err)
+		}
+		settings = maps.Clone(settings)
+		settings["password"] = password
+	}
+
+	connector, err := pq.NewConnector(formatDSN(settings))
+	if err != nil {
+		return nil, err
+	}
+	if c.dialer != nil {
+		connector.Dialer(c.dialer)
+	}
+	return connector.Connect(ctx)
+}
+
+// Driver returns the PostgreSQL driver
+func (c *postgresConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+// readPasswordFile reads a password, without the trailing newline secret
+// files usually end with
+func readPasswordFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("database password file: %w", err)
+	}
+	password := strings.TrimRight(string(content), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("database password file %s is empty", path)
+	}
+	return password, nil
+}
+
+// parseDSN parses a connection string, as a postgres:// URL or key=value
+// pairs, into its settings
+func parseDSN(dsn string) (map[string]string, error) {
+	dsn = strings.TrimSpace(dsn)
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		converted, err := pq.ParseURL(dsn)
+		if err != nil {
+			return nil, err
+		}
+		dsn = converted
+	}
+
+	settings := map[string]string{}
+	for dsn = strings.TrimLeft(dsn, " \t\n"); dsn != ""; dsn = strings.TrimLeft(dsn, " \t\n") {
+		key, rest, ok := strings.Cut(dsn, "=")
+		if !ok {
+			return nil, fmt.Errorf("missing \"=\" after %q", dsn)
+		}
+		key = strings.TrimSpace(key)
+		rest = strings.TrimLeft(rest, " \t\n")
+
+		var value strings.Builder
+		if strings.HasPrefix(rest, "'") {
+			// A quoted value, in which backslash escapes the next character
+			i := 1
+			for ; i < len(rest) && rest[i] != '\''; i++ {
+				if rest[i] == '\\' {
+					i++
+					if i == len(rest) {
+						break
+					}
+				}
+				value.WriteByte(rest[i])
+			}
+			if i >= len(rest) {
+				return nil, fmt.Errorf("unterminated quoted value for %q", key)
+			}
+			dsn = rest[i+1:]
+		} else {
+			end := strings.IndexAny(rest, " \t\n")
+			if end < 0 {
+				end = len(rest)
+			}
+			value.WriteString(rest[:end])
+			dsn = rest[end:]
+		}
+		settings[key] = value.String()
+	}
+	return settings, nil
+}
+
+// formatDSN formats settings as a key=value connection string
+func formatDSN(settings map[string]string) string {
+	pairs := make([]string, 0, len(settings))
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(settings[key])
+		pairs = append(pairs, key+"='"+value+"'")
+	}
+	return strings.Join(pairs, " ")
+}
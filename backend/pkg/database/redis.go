@@ -0,0 +1,259 @@
+package database
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// RedisConfig describes how to reach Redis: a single server at URL, the
+// master named SentinelMaster through the Sentinel nodes in Addrs, or a
+// cluster seeded from the nodes in Addrs
+type RedisConfig struct {
+	// URL is a single server, such as "redis://localhost:6379/0"; the
+	// rediss scheme enables TLS
+	URL string
+	// Addrs are the Sentinel or cluster nodes, as host:port
+	Addrs          []string
+	SentinelMaster string
+	Cluster        bool
+	// Username and Password authenticate with the servers, overriding any
+	// in URL; SentinelPassword authenticates with the Sentinel nodes
+	Username         string
+	Password         string
+	SentinelPassword string
+	// TLS enables TLS for Sentinel and cluster nodes. TLSCAFile is a PEM
+	// file of the CAs server certificates are verified with, instead of the
+	// system roots; it also enables TLS.
+	TLS       bool
+	TLSCAFile string
+}
+
+// NewRedisClient creates a client for the configured topology. It doesn't
+// connect: the first command does.
+func NewRedisClient(config RedisConfig) (redis.UniversalClient, error) {
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case config.SentinelMaster != "":
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       config.SentinelMaster,
+			SentinelAddrs:    config.Addrs,
+			SentinelPassword: config.SentinelPassword,
+			Username:         config.Username,
+			Password:         config.Password,
+			TLSConfig:        tlsConfig,
+		}), nil
+	case config.Cluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     config.Addrs,
+			Username:  config.Username,
+			Password:  config.Password,
+			TLSConfig: tlsConfig,
+		}), nil
+	}
+
+	options, err := redis.ParseURL(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if config.Username != "" {
+		options.Username = config.Username
+	}
+	if config.Password != "" {
+		options.Password = config.Password
+	}
+	if tlsConfig != nil {
+		if options.TLSConfig != nil {
+			// Keep the server name ParseURL took from a rediss URL
+			tlsConfig.ServerName = options.TLSConfig.ServerName
+		}
+		options.TLSConfig = tlsConfig
+	}
+	return redis.NewClient(options), nil
+}
+
+// Address names the server or nodes of the configuration, without credentials
+func (c RedisConfig) Address() string {
+	if len(c.Addrs) > 0 && (c.SentinelMaster != "" || c.Cluster) {
+		return strings.Join(c.Addrs, ",")
+	}
+	if u, err := url.Parse(c.URL); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+// tlsConfig returns the TLS configuration for the servers, or nil when TLS
+// is left to the URL
+func (c RedisConfig) tlsConfig() (*tls.Config, error) {
+	if !c.TLS && c.TLSCAFile == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLSCAFile != "" {
+		pem, err := os.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no PEM certificates found", c.TLSCAFile)
+		}
+	}
+	return config, nil
+}
+
+// ErrRedisUnavailable is returned for Redis commands while the monitor has
+// found Redis down
+var ErrRedisUnavailable = errors.New("redis is unavailable")
+
+// RedisMonitorConfig contains configuration options for the Redis monitor
+type RedisMonitorConfig struct {
+	// Interval is how often Redis is pinged
+	Interval time.Duration
+	// Timeout bounds a single ping
+	Timeout time.Duration
+}
+
+// RedisMonitor pings Redis in the background so the server can run without
+// it. While Redis is down, commands fail straight away with
+// ErrRedisUnavailable instead of each waiting out the dial timeout, and the
+// features that need Redis fall back or turn themselves off. Once a ping
+// succeeds again commands go through as before.
+type RedisMonitor struct {
+	client    redis.UniversalClient
+	config    RedisMonitorConfig
+	available atomic.Bool
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewRedisMonitor creates a monitor for the client and installs the hook that
+// fails commands fast while Redis is down. The client starts out available.
+func NewRedisMonitor(client redis.UniversalClient, config RedisMonitorConfig) *RedisMonitor {
+	// Set default values if not provided
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 2 * time.Second
+	}
+
+	m := &RedisMonitor{
+		client: client,
+		config: config,
+	}
+	m.available.Store(true)
+	client.AddHook(redisAvailabilityHook{monitor: m})
+	return m
+}
+
+// Available reports whether Redis answered the last ping
+func (m *RedisMonitor) Available() bool {
+	return m.available.Load()
+}
+
+// Check pings Redis, updating and logging its availability
+func (m *RedisMonitor) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(withMonitorPing(ctx), m.config.Timeout)
+	defer cancel()
+
+	err := m.client.Ping(ctx).Err()
+	if up := err == nil; m.available.Swap(up) != up {
+		if up {
+			log.Info().Msg("Redis is available again, re-enabling Redis features")
+		} else {
+			log.Warn().Err(err).Msg("Redis is unavailable, running in degraded mode: rate limits fall back to this instance, background jobs pause and revoked tokens are not checked")
+		}
+	}
+	return err
+}
+
+// Start pings Redis on the configured interval until Stop is called
+func (m *RedisMonitor) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the background pings
+func (m *RedisMonitor) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+}
+
+// monitorPingKey marks the monitor's own pings, which must reach Redis even
+// while it is marked down
+type monitorPingKey struct{}
+
+func withMonitorPing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, monitorPingKey{}, true)
+}
+
+func isMonitorPing(ctx context.Context) bool {
+	ping, _ := ctx.Value(monitorPingKey{}).(bool)
+	return ping
+}
+
+// redisAvailabilityHook fails commands fast while the monitor has Redis down
+type redisAvailabilityHook struct {
+	monitor *RedisMonitor
+}
+
+func (h redisAvailabilityHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h redisAvailabilityHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !h.monitor.Available() && !isMonitorPing(ctx) {
+			cmd.SetErr(ErrRedisUnavailable)
+			return ErrRedisUnavailable
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h redisAvailabilityHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !h.monitor.Available() {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrRedisUnavailable)
+			}
+			return ErrRedisUnavailable
+		}
+		return next(ctx, cmds)
+	}
+}
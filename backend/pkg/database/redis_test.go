@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisMonitor(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	monitor := NewRedisMonitor(client, RedisMonitorConfig{})
+
+	require.NoError(t, monitor.Check(ctx))
+	assert.True(t, monitor.Available())
+	require.NoError(t, client.Set(ctx, "key", "value", 0).Err())
+
+	// Once a ping fails, commands fail fast until Redis is back
+	mr.Close()
+	assert.Error(t, monitor.Check(ctx))
+	assert.False(t, monitor.Available())
+	assert.ErrorIs(t, client.Get(ctx, "key").Err(), ErrRedisUnavailable)
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Get(ctx, "key")
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrRedisUnavailable)
+
+	require.NoError(t, mr.Restart())
+	require.NoError(t, monitor.Check(ctx))
+	assert.True(t, monitor.Available())
+	assert.NoError(t, client.Get(ctx, "key").Err())
+}
+
+func TestNewRedisClient(t *testing.T) {
+	client, err := NewRedisClient(RedisConfig{URL: "redis://localhost:6379/2", Password: "secret", TLS: true})
+	require.NoError(t, err)
+	options := client.(*redis.Client).Options()
+	assert.Equal(t, 2, options.DB)
+	assert.Equal(t, "secret", options.Password)
+	assert.NotNil(t, options.TLSConfig)
+
+	cluster := RedisConfig{Addrs: []string{"node-1:6379", "node-2:6379"}, Cluster: true}
+	client, err = NewRedisClient(cluster)
+	require.NoError(t, err)
+	assert.IsType(t, &redis.ClusterClient{}, client)
+	assert.Equal(t, "node-1:6379,node-2:6379", cluster.Address())
+
+	_, err = NewRedisClient(RedisConfig{URL: "redis://localhost:6379", TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+}
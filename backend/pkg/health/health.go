@@ -0,0 +1,154 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Component and overall statuses. A report is degraded when only optional
+// components are down.
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// Check reports whether a dependency is usable, returning an error if it isn't
+type Check func(ctx context.Context) error
+
+// ComponentStatus is the result of running a single check
+type ComponentStatus struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the combined result of all registered checks
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Config contains configuration for the checker
+type Config struct {
+	// Timeout bounds the time a single check may take
+	Timeout time.Duration
+}
+
+// Checker runs named dependency checks concurrently
+type Checker struct {
+	mu       sync.RWMutex
+	checks   map[string]Check
+	optional map[string]bool
+	timeout  time.Duration
+}
+
+// NewChecker creates a new checker
+func NewChecker(config Config) *Checker {
+	// Set default values if not provided
+	if config.Timeout == 0 {
+		config.Timeout = 2 * time.Second
+	}
+
+	return &Checker{
+		checks:   make(map[string]Check),
+		optional: make(map[string]bool),
+		timeout:  config.Timeout,
+	}
+}
+
+// Register adds a named check, replacing any existing check with the same name
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+	delete(c.optional, name)
+}
+
+// RegisterOptional adds a named check for a dependency the server can run
+// without; when it fails the report is degraded rather than down
+func (c *Checker) RegisterOptional(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+	c.optional[name] = true
+}
+
+// Components returns the registered check names in sorted order
+func (c *Checker) Components() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run executes all checks; the report is down if any required component is
+// down, and degraded if only optional ones are
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	optional := make(map[string]bool, len(c.optional))
+	for name, check := range c.checks {
+		checks[name] = check
+		optional[name] = c.optional[name]
+	}
+	c.mu.RUnlock()
+
+	report := Report{
+		Status:     StatusUp,
+		Components: make(map[string]ComponentStatus, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = result
+			switch {
+			case result.Status != StatusDown:
+			case !optional[name]:
+				report.Status = StatusDown
+			case report.Status == StatusUp:
+				report.Status = StatusDegraded
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run executes a single check with the configured timeout
+func (c *Checker) run(ctx context.Context, check Check) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := ComponentStatus{
+		Status:    StatusUp,
+		LatencyMS: time.Since(start).Milliseconds(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckerRun(t *testing.T) {
+	checker := NewChecker(Config{Timeout: 50 * time.Millisecond})
+	checker.Register("ok", func(ctx context.Context) error { return nil })
+	checker.Register("failing", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusUp, report.Components["ok"].Status)
+	assert.Equal(t, StatusDown, report.Components["failing"].Status)
+	assert.Equal(t, "connection refused", report.Components["failing"].Error)
+	assert.Equal(t, StatusDown, report.Components["slow"].Status)
+	assert.Equal(t, []string{"failing", "ok", "slow"}, checker.Components())
+}
+
+func TestCheckerRunAllUp(t *testing.T) {
+	checker := NewChecker(Config{})
+	checker.Register("ok", func(ctx context.Context) error { return nil })
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusUp, report.Status)
+}
+
+func TestCheckerRunOptionalDown(t *testing.T) {
+	checker := NewChecker(Config{})
+	checker.Register("database", func(ctx context.Context) error { return nil })
+	checker.RegisterOptional("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	report := checker.Run(context.Background())
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, StatusDown, report.Components["redis"].Status)
+
+	// A required component going down still takes the report down
+	checker.Register("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	report = checker.Run(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+}
@@ -0,0 +1,241 @@
+// Package locale holds the language-dependent text and date formats used when
+// a resume is rendered: section headings, month names and the word for an
+// ongoing date range. Languages without a catalog fall back to English.
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the language resumes are written in unless the user picks another
+const Default = "en"
+
+// ErrInvalidLanguage is returned for strings that aren't BCP 47 language tags
+var ErrInvalidLanguage = errors.New("invalid language tag")
+
+// Date styles of the month and year dates of a rendered resume
+const (
+	// DateStyleLong spells out the month, e.g. "March 2024"
+	DateStyleLong = "long"
+	// DateStyleNumeric writes the month as a number, e.g. "03/2024"
+	DateStyleNumeric = "numeric"
+	// DateStyleISO writes the year first, e.g. "2024-03"
+	DateStyleISO = "iso"
+)
+
+// DateStyles are the supported date styles
+var DateStyles = []string{DateStyleLong, DateStyleNumeric, DateStyleISO}
+
+// Paper sizes of a rendered resume
+const (
+	PaperA4     = "a4"
+	PaperLetter = "letter"
+)
+
+// PaperSizes are the supported paper sizes
+var PaperSizes = []string{PaperA4, PaperLetter}
+
+// letterRegions are the regions that print on US Letter rather than A4
+var letterRegions = []string{"US", "CA", "MX", "PH", "CL", "CO", "VE", "CR", "DO", "GT", "PA", "PR", "SV"}
+
+// Headings are the section titles of a rendered resume
+type Headings struct {
+	Experience     string
+	Education      string
+	Skills         string
+	Projects       string
+	Certifications string
+}
+
+// Catalog is the text of one language
+type Catalog struct {
+	Headings Headings
+	// Months are the month names, January first
+	Months [12]string
+	// MonthYear formats a month name and year, e.g. "%s %d" for "March 2024"
+	MonthYear string
+	// NumericMonthYear formats a month number and year, e.g. "%02d/%d" for
+	// "03/2024"
+	NumericMonthYear string
+	// Present stands in for the end date of something ongoing
+	Present string
+	// Expires introduces a certification's expiry date
+	Expires string
+	// Proficiency names skill proficiency levels 1 to 5
+	Proficiency [5]string
+
+	// dateStyle is how Month writes dates, DateStyleLong when empty
+	dateStyle string
+}
+
+var catalogs = map[string]*Catalog{
+	"en": {
+		Headings:         Headings{Experience: "Experience", Education: "Education", Skills: "Skills", Projects: "Projects", Certifications: "Certifications"},
+		Months:           [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		MonthYear:        "%s %d",
+		NumericMonthYear: "%02d/%d",
+		Present:          "Present",
+		Expires:          "Expires",
+		Proficiency:      [5]string{"Beginner", "Elementary", "Intermediate", "Advanced", "Expert"},
+	},
+	"es": {
+		Headings:         Headings{Experience: "Experiencia", Education: "Educación", Skills: "Habilidades", Projects: "Proyectos", Certifications: "Certificaciones"},
+		Months:           [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		MonthYear:        "%s de %d",
+		NumericMonthYear: "%02d/%d",
+		Present:          "Actualidad",
+		Expires:          "Vence",
+		Proficiency:      [5]string{"Principiante", "Básico", "Intermedio", "Avanzado", "Experto"},
+	},
+	"pt": {
+		Headings:         Headings{Experience: "Experiência", Education: "Formação", Skills: "Competências", Projects: "Projetos", Certifications: "Certificações"},
+		Months:           [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		MonthYear:        "%s de %d",
+		NumericMonthYear: "%02d/%d",
+		Present:          "Atual",
+		Expires:          "Expira",
+		Proficiency:      [5]string{"Iniciante", "Básico", "Intermediário", "Avançado", "Especialista"},
+	},
+	"fr": {
+		Headings:         Headings{Experience: "Expérience", Education: "Formation", Skills: "Compétences", Projects: "Projets", Certifications: "Certifications"},
+		Months:           [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		MonthYear:        "%s %d",
+		NumericMonthYear: "%02d/%d",
+		Present:          "Aujourd'hui",
+		Expires:          "Expire",
+		Proficiency:      [5]string{"Débutant", "Élémentaire", "Intermédiaire", "Avancé", "Expert"},
+	},
+	"de": {
+		Headings:         Headings{Experience: "Berufserfahrung", Education: "Ausbildung", Skills: "Kenntnisse", Projects: "Projekte", Certifications: "Zertifikate"},
+		Months:           [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		MonthYear:        "%s %d",
+		NumericMonthYear: "%02d.%d",
+		Present:          "heute",
+		Expires:          "Gültig bis",
+		Proficiency:      [5]string{"Anfänger", "Grundkenntnisse", "Gute Kenntnisse", "Sehr gute Kenntnisse", "Experte"},
+	},
+	"it": {
+		Headings:         Headings{Experience: "Esperienza", Education: "Istruzione", Skills: "Competenze", Projects: "Progetti", Certifications: "Certificazioni"},
+		Months:           [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		MonthYear:        "%s %d",
+		NumericMonthYear: "%02d/%d",
+		Present:          "oggi",
+		Expires:          "Scade",
+		Proficiency:      [5]string{"Principiante", "Base", "Intermedio", "Avanzato", "Esperto"},
+	},
+}
+
+// Normalize parses a BCP 47 language tag such as "es", "pt_BR" or "en-gb" and
+// returns its canonical form ("es", "pt-BR", "en-GB")
+func Normalize(tag string) (string, error) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" {
+		return "", ErrInvalidLanguage
+	}
+	parsed, err := language.Parse(tag)
+	if err != nil || parsed == language.Und {
+		return "", ErrInvalidLanguage
+	}
+	return parsed.String(), nil
+}
+
+// Supported reports whether resumes in tag are rendered with translated text
+// rather than the English fallback
+func Supported(tag string) bool {
+	_, ok := catalogs[base(tag)]
+	return ok
+}
+
+// Languages returns the languages with a catalog, sorted
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// For returns the catalog for tag, matching on its base language so "es-MX"
+// uses the Spanish catalog, and falling back to English
+func For(tag string) *Catalog {
+	if c, ok := catalogs[base(tag)]; ok {
+		return c
+	}
+	return catalogs[Default]
+}
+
+// PaperSize returns the paper size documents are printed on in the region of
+// tag: US Letter for regions such as "en-US" or "es-MX" and A4 otherwise,
+// including for tags without an explicit region
+func PaperSize(tag string) string {
+	parsed, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if err != nil {
+		return PaperA4
+	}
+	region, confidence := parsed.Region()
+	if confidence == language.Exact && slices.Contains(letterRegions, region.String()) {
+		return PaperLetter
+	}
+	return PaperA4
+}
+
+// WithDateStyle returns a copy of the catalog that writes dates in style,
+// one of DateStyles. Unknown styles write dates in DateStyleLong.
+func (c *Catalog) WithDateStyle(style string) *Catalog {
+	styled := *c
+	styled.dateStyle = style
+	return &styled
+}
+
+// Month formats t as a month and year, e.g. "March 2024" or "marzo de 2024",
+// or in the catalog's date style
+func (c *Catalog) Month(t time.Time) string {
+	switch c.dateStyle {
+	case DateStyleNumeric:
+		return fmt.Sprintf(c.NumericMonthYear, int(t.Month()), t.Year())
+	case DateStyleISO:
+		return t.Format("2006-01")
+	default:
+		return fmt.Sprintf(c.MonthYear, c.Months[t.Month()-1], t.Year())
+	}
+}
+
+// Date formats a stored YYYY-MM-DD date as a month and year. Other values, such
+// as "Present", are returned unchanged except that "Present" is translated.
+func (c *Catalog) Date(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "present") {
+		return c.Present
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return value
+	}
+	return c.Month(t)
+}
+
+// DateRange formats start and end dates, writing an empty or "Present" end as
+// the catalog's word for ongoing
+func (c *Catalog) DateRange(start, end string) string {
+	if strings.TrimSpace(start) == "" {
+		return c.Date(end)
+	}
+	if strings.TrimSpace(end) == "" {
+		end = "present"
+	}
+	return c.Date(start) + " – " + c.Date(end)
+}
+
+// base returns the lowercase base language of tag
+func base(tag string) string {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	lang, _, _ := strings.Cut(tag, "-")
+	return lang
+}
@@ -0,0 +1,58 @@
+package locale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	for input, want := range map[string]string{"es": "es", "pt_BR": "pt-BR", "en-gb": "en-GB", " DE ": "de"} {
+		got, err := Normalize(input)
+		require.NoError(t, err, "tag %q", input)
+		assert.Equal(t, want, got)
+	}
+
+	for _, input := range []string{"", "not a language", "und"} {
+		_, err := Normalize(input)
+		assert.ErrorIs(t, err, ErrInvalidLanguage, "tag %q", input)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	assert.Equal(t, "Experiencia", For("es-MX").Headings.Experience)
+	assert.Equal(t, "Experience", For("ja").Headings.Experience)
+	assert.False(t, Supported("ja"))
+
+	march := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "March 2024", For("en").Month(march))
+	assert.Equal(t, "marzo de 2024", For("es").Month(march))
+	assert.Equal(t, "März 2024", For("de").Month(march))
+
+	assert.Equal(t, "enero de 2020 – Actualidad", For("es").DateRange("2020-01-15", "Present"))
+	assert.Equal(t, "June 2019 – Present", For("en").DateRange("2019-06-01", ""))
+	assert.Equal(t, "No Expiration", For("en").Date("No Expiration"))
+
+	assert.Equal(t, "Expert", For("en").Proficiency[4])
+	assert.Equal(t, "Principiante", For("es").Proficiency[0])
+}
+
+func TestDateStyles(t *testing.T) {
+	march := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "03/2024", For("en").WithDateStyle(DateStyleNumeric).Month(march))
+	assert.Equal(t, "03.2024", For("de").WithDateStyle(DateStyleNumeric).Month(march))
+	assert.Equal(t, "2024-03", For("es").WithDateStyle(DateStyleISO).Month(march))
+	assert.Equal(t, "marzo de 2024", For("es").WithDateStyle(DateStyleLong).Month(march))
+	assert.Equal(t, "2020-01 – Actualidad", For("es").WithDateStyle(DateStyleISO).DateRange("2020-01-15", ""))
+
+	// The shared catalog keeps its style
+	assert.Equal(t, "March 2024", For("en").Month(march))
+}
+
+func TestPaperSize(t *testing.T) {
+	for tag, want := range map[string]string{"en-US": PaperLetter, "es_MX": PaperLetter, "fr-CA": PaperLetter, "en-GB": PaperA4, "de": PaperA4, "en": PaperA4, "": PaperA4} {
+		assert.Equal(t, want, PaperSize(tag), "tag %q", tag)
+	}
+}
@@ -0,0 +1,197 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidMessage is returned when a message is missing required fields
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+// Message is a plain-text email, optionally with files attached
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	// ReplyTo is where replies go instead of the sender, when set
+	ReplyTo     string       `json:"reply_to,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// Validate checks that the message can be delivered
+func (m *Message) Validate() error {
+	if m.To == "" || m.Subject == "" {
+		return ErrInvalidMessage
+	}
+	// Reject header injection through the recipient or subject
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") || strings.ContainsAny(m.ReplyTo, "\r\n") {
+		return ErrInvalidMessage
+	}
+	// Attachment names and types are written into part headers too
+	for _, a := range m.Attachments {
+		if a.Filename == "" || a.ContentType == "" {
+			return ErrInvalidMessage
+		}
+		if strings.ContainsAny(a.Filename, "\r\n\"") || strings.ContainsAny(a.ContentType, "\r\n") {
+			return ErrInvalidMessage
+		}
+	}
+	return nil
+}
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPConfig contains configuration for the SMTP sender
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPSender delivers messages through an SMTP relay
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender creates a new SMTP sender
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	// Set default values if not provided
+	if config.Port == "" {
+		config.Port = "587"
+	}
+
+	return &SMTPSender{
+		config: config,
+	}
+}
+
+// Send delivers the message
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	// net/smtp has no context support, so run it in a goroutine and honour cancellation
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.config.Host, s.config.Port), auth, s.config.From, []string{msg.To}, s.format(msg))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// format builds the RFC 5322 message
+func (s *SMTPSender) format(msg *Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	if msg.ReplyTo != "" {
+		fmt.Fprintf(&b, "Reply-To: %s\r\n", msg.ReplyTo)
+	}
+	// Non-ASCII subjects, such as ones with the user's name, are encoded
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if len(msg.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+		return []byte(b.String())
+	}
+
+	// The body and each attachment are parts of a multipart/mixed message
+	boundary := newBoundary()
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n", boundary)
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	for _, a := range msg.Attachments {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s\r\n", a.ContentType)
+		b.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&b, "Content-Disposition: attachment; filename=%q\r\n", a.Filename)
+		b.WriteString("\r\n")
+		writeBase64Lines(&b, a.Data)
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String())
+}
+
+// newBoundary returns a random multipart boundary, which can't occur in the
+// base64 and plain-text parts it separates by chance
+func newBoundary() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return "boundary-" + hex.EncodeToString(buf)
+}
+
+// writeBase64Lines writes data in base64, broken into the 76-character lines
+// RFC 2045 requires
+func writeBase64Lines(b *strings.Builder, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+}
+
+// LogSender logs messages instead of delivering them, for development
+type LogSender struct{}
+
+// NewLogSender creates a new log sender
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+// Send logs the message
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	filenames := make([]string, len(msg.Attachments))
+	for i, a := range msg.Attachments {
+		filenames[i] = a.Filename
+	}
+	log.Info().Str("to", msg.To).Str("subject", msg.Subject).Str("body", msg.Body).Strs("attachments", filenames).Msg("Email (not sent, SMTP not configured)")
+	return nil
+}
@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageValidate(t *testing.T) {
+	valid := Message{To: "jane@example.com", Subject: "Hello"}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name string
+		msg  Message
+	}{
+		{"no recipient", Message{Subject: "Hello"}},
+		{"header in subject", Message{To: "jane@example.com", Subject: "Hello\r\nBcc: eve@example.com"}},
+		{"header in reply-to", Message{To: "jane@example.com", Subject: "Hello", ReplyTo: "a@example.com\nBcc: eve@example.com"}},
+		{"unnamed attachment", Message{To: "jane@example.com", Subject: "Hello", Attachments: []Attachment{{ContentType: "application/pdf"}}}},
+		{"quote in filename", Message{To: "jane@example.com", Subject: "Hello", Attachments: []Attachment{{Filename: `a".pdf`, ContentType: "application/pdf"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.msg.Validate(), ErrInvalidMessage)
+		})
+	}
+}
+
+func TestSMTPSenderFormatAttachments(t *testing.T) {
+	sender := NewSMTPSender(SMTPConfig{From: "noreply@example.com"})
+	data := bytes.Repeat([]byte("%PDF-1.4 "), 40)
+	raw := sender.format(&Message{
+		To:          "jobs@example.com",
+		Subject:     "Resume of José",
+		Body:        "Please find my resume attached.\nThanks",
+		ReplyTo:     "jose@example.com",
+		Attachments: []Attachment{{Filename: "resume.pdf", ContentType: "application/pdf", Data: data}},
+	})
+
+	msg, err := netmail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "jose@example.com", msg.Header.Get("Reply-To"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Resume of José", subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := parts.NextPart()
+	require.NoError(t, err)
+	text, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "Please find my resume attached.\r\nThanks", string(text))
+
+	attachment, err := parts.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "resume.pdf", attachment.FileName())
+	assert.Equal(t, "application/pdf", attachment.Header.Get("Content-Type"))
+	// The multipart reader decodes quoted-printable only, so base64 is decoded here
+	encoded, err := io.ReadAll(attachment)
+	require.NoError(t, err)
+	for _, line := range bytes.Split(bytes.TrimSpace(encoded), []byte("\r\n")) {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(encoded)))
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	_, err = parts.NextPart()
+	assert.ErrorIs(t, err, io.EOF)
+}
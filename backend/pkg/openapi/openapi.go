@@ -0,0 +1,375 @@
+// Package openapi builds an OpenAPI 3 document from a typed route registry.
+// Routes are registered on an http.ServeMux together with a description of
+// their parameters, bodies and errors, so the document cannot drift from the
+// routes that are actually served.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Version is the OpenAPI specification version documents are written against
+const Version = "3.0.3"
+
+// bearerScheme names the security scheme used by routes that require authentication
+const bearerScheme = "bearerAuth"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of one path, keyed by lowercase HTTP method
+type PathItem map[string]*Operation
+
+// Operation describes a single route
+type Operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter describes a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body a route accepts
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response status of a route
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType pairs a content type with the schema of its payload
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas and security schemes referenced by operations
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how clients authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Param documents a path or query parameter of a route
+type Param struct {
+	Name        string
+	Description string
+	// Type is the JSON schema type, defaulting to string
+	Type     string
+	Format   string
+	Required bool
+}
+
+// Result documents an additional success response of a route
+type Result struct {
+	Status      int
+	Description string
+	// Body is a value of the response type; nil documents an empty body
+	Body any
+	// ContentType defaults to application/json
+	ContentType string
+}
+
+// Route documents a registered handler
+type Route struct {
+	Summary     string
+	Description string
+	Tags        []string
+	// Auth marks routes that require a bearer access token
+	Auth bool
+	// PathParams overrides the parameters parsed from the pattern, matched by name
+	PathParams []Param
+	Query      []Param
+	// Request is a value of the JSON request body type; nil means no body
+	Request any
+	// RequestOptional marks the request body as optional
+	RequestOptional bool
+	// Status is the success status, defaulting to 200
+	Status int
+	// Response is a value of the success response type; nil documents an empty body
+	Response any
+	// ContentType is the success content type, defaulting to application/json
+	ContentType string
+	// Alternatives documents further success responses
+	Alternatives []Result
+	// Errors lists the error statuses the route may respond with
+	Errors []int
+}
+
+// Config contains configuration for the registry
+type Config struct {
+	Title       string
+	Version     string
+	Description string
+	// ErrorResponse is a value of the error envelope returned by every error status
+	ErrorResponse any
+	// ErrorContentType is the content type of error responses, defaulting to
+	// application/json
+	ErrorContentType string
+	// DefaultErrors lists error statuses any route may respond with
+	DefaultErrors []int
+}
+
+// Registry registers routes on a mux and records their documentation
+type Registry struct {
+	mux     *http.ServeMux
+	config  Config
+	schemas *schemaGenerator
+
+	mu    sync.Mutex
+	paths map[string]*PathItem
+}
+
+// NewRegistry creates a registry that registers routes on mux
+func NewRegistry(mux *http.ServeMux, config Config) *Registry {
+	// Set default values if not provided
+	if config.Title == "" {
+		config.Title = "API"
+	}
+	if config.Version == "" {
+		config.Version = "1.0.0"
+	}
+
+	return &Registry{
+		mux:     mux,
+		config:  config,
+		schemas: newSchemaGenerator(),
+		paths:   make(map[string]*PathItem),
+	}
+}
+
+// HandleFunc registers a handler function for pattern and documents it
+func (r *Registry) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request), route Route) {
+	r.Handle(pattern, http.HandlerFunc(handler), route)
+}
+
+// Handle registers a handler for pattern and documents it. Patterns without a
+// method are served but left out of the document.
+func (r *Registry) Handle(pattern string, handler http.Handler, route Route) {
+	r.mux.Handle(pattern, handler)
+
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		log.Warn().Str("pattern", pattern).Msg("Route without a method left out of the OpenAPI document")
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path, params := parsePath(path)
+	item, exists := r.paths[path]
+	if !exists {
+		item = &PathItem{}
+		r.paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = r.operation(route, params)
+}
+
+// operation builds the documentation for one route
+func (r *Registry) operation(route Route, pathParams []string) *Operation {
+	op := &Operation{
+		Summary:     route.Summary,
+		Description: route.Description,
+		Tags:        route.Tags,
+		Responses:   make(map[string]*Response),
+	}
+
+	overrides := make(map[string]Param, len(route.PathParams))
+	for _, param := range route.PathParams {
+		overrides[param.Name] = param
+	}
+	for _, name := range pathParams {
+		param, ok := overrides[name]
+		if !ok {
+			param = Param{Name: name}
+			if name == "id" || strings.HasSuffix(name, "Id") {
+				param.Format = "uuid"
+			}
+		}
+		param.Required = true
+		op.Parameters = append(op.Parameters, r.parameter("path", param))
+	}
+	for _, param := range route.Query {
+		op.Parameters = append(op.Parameters, r.parameter("query", param))
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: !route.RequestOptional,
+			Content:  map[string]MediaType{"application/json": {Schema: r.schemas.schemaFor(route.Request)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	results := append([]Result{{Status: status, Body: route.Response, ContentType: route.ContentType}}, route.Alternatives...)
+	for _, result := range results {
+		op.Responses[strconv.Itoa(result.Status)] = r.response(result)
+	}
+
+	var errorStatuses []int
+	if route.Auth {
+		op.Security = []map[string][]string{{bearerScheme: {}}}
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized)
+	}
+	errorStatuses = append(errorStatuses, route.Errors...)
+	errorStatuses = append(errorStatuses, r.config.DefaultErrors...)
+	for _, status := range errorStatuses {
+		op.Responses[strconv.Itoa(status)] = r.response(Result{Status: status, Body: r.config.ErrorResponse, ContentType: r.config.ErrorContentType})
+	}
+
+	return op
+}
+
+// parameter documents a single path or query parameter
+func (r *Registry) parameter(in string, param Param) *Parameter {
+	schema := &Schema{Type: param.Type, Format: param.Format}
+	if schema.Type == "" {
+		schema.Type = "string"
+	}
+	return &Parameter{
+		Name:        param.Name,
+		In:          in,
+		Description: param.Description,
+		Required:    param.Required,
+		Schema:      schema,
+	}
+}
+
+// response documents a single response status
+func (r *Registry) response(result Result) *Response {
+	response := &Response{Description: result.Description}
+	if response.Description == "" {
+		response.Description = http.StatusText(result.Status)
+	}
+
+	contentType := result.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	switch {
+	case result.Body != nil:
+		response.Content = map[string]MediaType{contentType: {Schema: r.schemas.schemaFor(result.Body)}}
+	case result.ContentType != "":
+		// Non-JSON payloads without a type are documented as raw bytes
+		response.Content = map[string]MediaType{contentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	}
+	return response
+}
+
+// parsePath strips wildcard modifiers from a mux path and returns the names of its parameters
+func parsePath(path string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimSuffix(segment[1:len(segment)-1], "..."), "$")
+		if name == "" {
+			// "{$}" only anchors the end of the path
+			segments[i] = ""
+			continue
+		}
+		segments[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// Document returns the OpenAPI document for all routes registered so far
+func (r *Registry) Document() *Document {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	components := Components{
+		Schemas: r.schemas.components(),
+		SecuritySchemes: map[string]*SecurityScheme{
+			bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+
+	paths := make(map[string]*PathItem, len(r.paths))
+	for path, item := range r.paths {
+		paths[path] = item
+	}
+
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       r.config.Title,
+			Version:     r.config.Version,
+			Description: r.config.Description,
+		},
+		Paths:      paths,
+		Components: components,
+	}
+}
+
+// SpecHandler serves the document as JSON. It is rendered on first request,
+// so all routes must be registered before the server starts.
+func (r *Registry) SpecHandler() http.Handler {
+	var (
+		once sync.Once
+		spec []byte
+		err  error
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		once.Do(func() {
+			spec, err = json.Marshal(r.Document())
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to marshal OpenAPI document")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(spec); err != nil {
+			log.Error().Err(err).Msg("Failed to write OpenAPI document")
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+type testAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testItem struct {
+	testAudit
+	ID       uuid.UUID         `json:"id"`
+	Name     string            `json:"name" validate:"required,max=50"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Parent   *testItem         `json:"parent,omitempty"`
+	Secret   string            `json:"-"`
+	internal string
+}
+
+func TestRegistryDocument(t *testing.T) {
+	mux := http.NewServeMux()
+	registry := NewRegistry(mux, Config{Title: "Test API", ErrorResponse: testErrorResponse{}, DefaultErrors: []int{http.StatusInternalServerError}})
+
+	registry.HandleFunc("POST /items/{id}/children/{childId...}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}, Route{
+		Summary:  "Add a child item",
+		Auth:     true,
+		Query:    []Param{{Name: "limit", Type: "integer"}},
+		Request:  testItem{},
+		Status:   http.StatusCreated,
+		Response: &testItem{},
+		Errors:   []int{http.StatusNotFound},
+	})
+
+	// Registered routes are still served by the mux
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items/1/children/2", nil))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	doc := registry.Document()
+	assert.Equal(t, "Test API", doc.Info.Title)
+
+	op := (*doc.Paths["/items/{id}/children/{childId}"])["post"]
+	require.NotNil(t, op)
+	require.Len(t, op.Parameters, 3)
+	assert.Equal(t, "path", op.Parameters[0].In)
+	assert.Equal(t, "uuid", op.Parameters[0].Schema.Format)
+	assert.True(t, op.Parameters[1].Required)
+	assert.Equal(t, "query", op.Parameters[2].In)
+	assert.Equal(t, "integer", op.Parameters[2].Schema.Type)
+	assert.Equal(t, []map[string][]string{{bearerScheme: {}}}, op.Security)
+	assert.Equal(t, "#/components/schemas/testItem", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/testItem", op.Responses["201"].Content["application/json"].Schema.Ref)
+	for _, status := range []string{"401", "404", "500"} {
+		require.Contains(t, op.Responses, status)
+		assert.Equal(t, "#/components/schemas/testErrorResponse", op.Responses[status].Content["application/json"].Schema.Ref)
+	}
+
+	item := doc.Components.Schemas["testItem"]
+	require.NotNil(t, item)
+	assert.ElementsMatch(t, []string{"created_at", "id", "name", "tags", "labels", "parent"}, keys(item.Properties))
+	assert.Equal(t, []string{"name"}, item.Required)
+	assert.Equal(t, "date-time", item.Properties["created_at"].Format)
+	assert.Equal(t, "uuid", item.Properties["id"].Format)
+	assert.Equal(t, 50, *item.Properties["name"].MaxLength)
+	assert.Equal(t, "string", item.Properties["tags"].Items.Type)
+	assert.Equal(t, "string", item.Properties["labels"].AdditionalProperties.Type)
+	assert.Equal(t, "#/components/schemas/testItem", item.Properties["parent"].Ref)
+}
+
+func TestSpecHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	registry := NewRegistry(mux, Config{})
+	registry.HandleFunc("GET /ping", func(w http.ResponseWriter, r *http.Request) {}, Route{Summary: "Ping"})
+	mux.Handle("GET /openapi.json", registry.SpecHandler())
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, Version, doc["openapi"])
+	assert.Contains(t, doc["paths"], "/ping")
+}
+
+func keys(m map[string]*Schema) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,194 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a subset of the OpenAPI schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+}
+
+// Types with a custom JSON encoding
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	uuidType       = reflect.TypeFor[uuid.UUID]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemaGenerator derives schemas from Go types via reflection. Named structs
+// become components referenced by name, which also terminates recursive types.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// newSchemaGenerator creates an empty generator
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// components returns the named schemas generated so far
+func (g *schemaGenerator) components() map[string]*Schema {
+	components := make(map[string]*Schema, len(g.schemas))
+	for name, schema := range g.schemas {
+		components[name] = schema
+	}
+	return components
+}
+
+// schemaFor returns the schema of v's type
+func (g *schemaGenerator) schemaFor(v any) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of t, registering named structs as components
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		// Arbitrary JSON
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return g.ref(t)
+	default:
+		// Interfaces and anything else may hold any JSON value
+		return &Schema{}
+	}
+}
+
+// ref registers a named struct as a component and returns a reference to it
+func (g *schemaGenerator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := g.schemas[name]; taken {
+			// Qualify clashing names with their package
+			name = strings.ToUpper(path.Base(t.PkgPath())[:1]) + path.Base(t.PkgPath())[1:] + name
+		}
+		g.names[t] = name
+		// Reserve the name before generating fields so recursive types resolve
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.object(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// object builds the schema of a struct's JSON encoding
+func (g *schemaGenerator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(schema, t)
+	return schema
+}
+
+// addFields adds t's exported fields to schema, flattening embedded structs
+// the way encoding/json does
+func (g *schemaGenerator) addFields(schema *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			g.addFields(schema, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schema(field.Type)
+		if strings.Contains(opts, "string") && property.Type != "" {
+			property = &Schema{Type: "string"}
+		}
+
+		validate := strings.Split(field.Tag.Get("validate"), ",")
+		for _, rule := range validate {
+			key, value, _ := strings.Cut(rule, "=")
+			switch key {
+			case "required":
+				schema.Required = append(schema.Required, name)
+			case "email":
+				if property.Type == "string" {
+					property.Format = "email"
+				}
+			case "oneof":
+				if property.Type == "string" {
+					property.Enum = strings.Fields(value)
+				}
+			case "min", "max":
+				if n, err := strconv.Atoi(value); err == nil && property.Type == "string" {
+					if key == "min" {
+						property.MinLength = &n
+					} else {
+						property.MaxLength = &n
+					}
+				}
+			}
+		}
+
+		schema.Properties[name] = property
+	}
+}
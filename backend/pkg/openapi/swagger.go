@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// swaggerUIVersion pins the Swagger UI release loaded from the CDN
+const swaggerUIVersion = "5.17.14"
+
+// swaggerUIPolicy is the Content-Security-Policy of the Swagger UI page, which
+// loads from the CDN and starts with an inline script. It replaces the policy
+// set for the rest of the API.
+const swaggerUIPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; img-src 'self' data: https://cdn.jsdelivr.net; connect-src 'self'; frame-ancestors 'none'; form-action 'self'; base-uri 'self'; object-src 'none'"
+
+// swaggerUITemplate renders a page that loads Swagger UI and points it at the spec
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`))
+
+// SwaggerUIHandler serves a Swagger UI page for the document at specURL
+func SwaggerUIHandler(title, specURL string) http.Handler {
+	data := struct {
+		Title   string
+		Version string
+		SpecURL string
+	}{title, swaggerUIVersion, specURL}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Del("Content-Security-Policy-Report-Only")
+		w.Header().Set("Content-Security-Policy", swaggerUIPolicy)
+		if err := swaggerUITemplate.Execute(w, data); err != nil {
+			log.Error().Err(err).Msg("Failed to render Swagger UI")
+		}
+	})
+}
@@ -0,0 +1,62 @@
+// Package phone parses telephone numbers as users type them, in national or
+// international format, and formats stored numbers for display.
+package phone
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ttacon/libphonenumber"
+)
+
+// Errors returned when a number can't be parsed
+var (
+	ErrInvalidNumber = errors.New("invalid phone number")
+	ErrInvalidRegion = errors.New("invalid phone region")
+)
+
+// ValidRegion reports whether region is a supported ISO 3166-1 alpha-2 country code
+func ValidRegion(region string) bool {
+	_, ok := libphonenumber.GetSupportedRegions()[strings.ToUpper(region)]
+	return ok
+}
+
+// Normalize parses number and returns it in E.164 format. Numbers without a
+// leading "+" or international dialing prefix are read as national numbers of
+// region, which may be empty when the number is international.
+func Normalize(number, region string) (string, error) {
+	parsed, err := parse(number, region)
+	if err != nil {
+		return "", err
+	}
+	return libphonenumber.Format(parsed, libphonenumber.E164), nil
+}
+
+// Display formats an E.164 number for people in region: national format for
+// numbers of that region and international format otherwise. Numbers that
+// can't be parsed are returned unchanged.
+func Display(number, region string) string {
+	parsed, err := parse(number, "")
+	if err != nil {
+		return number
+	}
+
+	if region != "" && libphonenumber.GetRegionCodeForNumber(parsed) == strings.ToUpper(region) {
+		return libphonenumber.Format(parsed, libphonenumber.NATIONAL)
+	}
+	return libphonenumber.Format(parsed, libphonenumber.INTERNATIONAL)
+}
+
+// parse parses and validates number, reading national numbers as belonging to region
+func parse(number, region string) (*libphonenumber.PhoneNumber, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region != "" && !ValidRegion(region) {
+		return nil, ErrInvalidRegion
+	}
+
+	parsed, err := libphonenumber.Parse(number, region)
+	if err != nil || !libphonenumber.IsValidNumber(parsed) {
+		return nil, ErrInvalidNumber
+	}
+	return parsed, nil
+}
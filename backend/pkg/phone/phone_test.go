@@ -0,0 +1,41 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		number, region, want string
+	}{
+		{"030 1234567", "DE", "+49301234567"},
+		{"(202) 555-0143", "us", "+12025550143"},
+		{"+44 20 7946 0958", "", "+442079460958"},
+		{"+44 20 7946 0958", "DE", "+442079460958"},
+		{"00 33 1 42 68 53 00", "DE", "+33142685300"},
+		{"+12025550143", "", "+12025550143"},
+	}
+
+	for _, tt := range tests {
+		got, err := Normalize(tt.number, tt.region)
+		require.NoError(t, err, "number %q", tt.number)
+		assert.Equal(t, tt.want, got, "number %q", tt.number)
+	}
+
+	_, err := Normalize("030 1234567", "")
+	assert.ErrorIs(t, err, ErrInvalidNumber)
+	_, err = Normalize("12", "DE")
+	assert.ErrorIs(t, err, ErrInvalidNumber)
+	_, err = Normalize("030 1234567", "XX")
+	assert.ErrorIs(t, err, ErrInvalidRegion)
+}
+
+func TestDisplay(t *testing.T) {
+	assert.Equal(t, "030 1234567", Display("+49301234567", "DE"))
+	assert.Equal(t, "+49 30 1234567", Display("+49301234567", "US"))
+	assert.Equal(t, "+49 30 1234567", Display("+49301234567", ""))
+	assert.Equal(t, "not a number", Display("not a number", "DE"))
+}
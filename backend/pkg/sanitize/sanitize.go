@@ -0,0 +1,93 @@
+// Package sanitize cleans user-supplied text before it is stored: it normalizes
+// to NFC, drops control and invisible formatting characters that confuse ATS
+// parsers, and can fold text to ASCII for generated file names.
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Text cleans multi-line text such as descriptions. Line breaks and tabs are
+// kept, Windows line endings become "\n" and surrounding whitespace is trimmed.
+func Text(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.TrimSpace(clean(s, func(r rune) bool { return r == '\n' || r == '\t' }))
+}
+
+// Line cleans single-line text such as names and titles. Any run of
+// whitespace, including line breaks, collapses to a single space.
+func Line(s string) string {
+	return strings.Join(strings.Fields(clean(s, unicode.IsSpace)), " ")
+}
+
+// clean normalizes s to NFC and removes control and format characters, other
+// than those keep allows
+func clean(s string, keep func(rune) bool) string {
+	s = norm.NFC.String(s)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case keep(r):
+			return r
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r), r == unicode.ReplacementChar:
+			// Control characters, zero-width spaces and joiners, byte order
+			// marks, soft hyphens and bidi overrides
+			return -1
+		case unicode.IsSpace(r):
+			// Non-breaking and other exotic spaces
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// asciiReplacements spells out letters that do not decompose into an ASCII
+// base letter plus combining marks
+var asciiReplacements = strings.NewReplacer(
+	"ß", "ss", "Æ", "AE", "æ", "ae", "Œ", "OE", "œ", "oe",
+	"Ø", "O", "ø", "o", "Đ", "D", "đ", "d", "Ł", "L", "ł", "l",
+	"Þ", "Th", "þ", "th", "Ð", "D", "ð", "d", "ı", "i",
+)
+
+// Transliterate folds s to ASCII by stripping diacritics and spelling out
+// ligatures. Characters with no ASCII equivalent are dropped.
+func Transliterate(s string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), Line(s))
+	if err != nil {
+		folded = s
+	}
+	folded = asciiReplacements.Replace(folded)
+
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return -1
+		}
+		return r
+	}, folded)
+}
+
+// Filename turns s into a lowercase, hyphen-separated ASCII slug suitable for
+// a download file name. It returns fallback when nothing usable remains.
+func Filename(s, fallback string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(Transliterate(s)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			hyphen = false
+		} else if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return fallback
+	}
+	return slug
+}
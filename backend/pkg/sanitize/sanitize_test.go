@@ -0,0 +1,39 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLine(t *testing.T) {
+	tests := map[string]string{
+		"  Senior Engineer  ":           "Senior Engineer",
+		"Jose\u0301":                    "Jos\u00e9",
+		"Go\u200bLang\ufeff":            "GoLang",
+		"Back\u00adend\tDev\r\nOps\x00": "Backend Dev Ops",
+		"\u202eevil\u202c name":         "evil name",
+		"multi\n\nline\n  title":        "multi line title",
+		"Zo\u00eb \U0001F680 \u00dcnal": "Zo\u00eb \U0001F680 \u00dcnal",
+		"":                              "",
+		"\u200b\u200c\u200d\u00a0\u2060\ufeff\ufffd": "",
+	}
+
+	for input, want := range tests {
+		assert.Equal(t, want, Line(input), "input %q", input)
+	}
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "Led a team.\n\n\t- Shipped v2", Text("  Led a\u200b team.\r\n\r\n\t- Shipped v2\x07  "))
+}
+
+func TestTransliterate(t *testing.T) {
+	assert.Equal(t, "Francois Muller-Strasse Lodz", Transliterate("Fran\u00e7ois M\u00fcller-Stra\u00dfe \u0141\u00f3d\u017a"))
+	assert.Equal(t, "Zoe  Unal", Transliterate("Zo\u00eb \u65e5\u672c \u00dcnal"))
+}
+
+func TestFilename(t *testing.T) {
+	assert.Equal(t, "jose-garcia-resume-2025", Filename("  Jos\u00e9 Garc\u00eda \u2014 R\u00e9sum\u00e9 (2025)!", "resume"))
+	assert.Equal(t, "resume", Filename("\u65e5\u672c\u8a9e", "resume"))
+}
@@ -0,0 +1,116 @@
+package security
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+)
+
+// TrustedProxies are the reverse proxies whose forwarding headers are believed
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// ParseTrustedProxies parses a list of IP addresses and CIDR ranges
+func ParseTrustedProxies(proxies []string) (*TrustedProxies, error) {
+	t := &TrustedProxies{}
+	for _, proxy := range proxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				return nil, err
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		t.prefixes = append(t.prefixes, prefix.Masked())
+	}
+	return t, nil
+}
+
+// trusted reports whether an address belongs to one of the proxies
+func (t *TrustedProxies) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range t.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client that sent the request. The
+// forwarding headers are only believed when the request comes from a trusted
+// proxy; X-Forwarded-For is read from the right, skipping the proxies, since
+// clients can put anything at its start.
+func (t *TrustedProxies) ClientIP(r *http.Request) string {
+	remote := remoteIP(r)
+	if !t.trusted(remote) {
+		return remote
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		ips := strings.Split(xff, ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if !t.trusted(ip) || i == 0 {
+				return ip
+			}
+		}
+	}
+
+	if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
+		return xrip
+	}
+
+	return remote
+}
+
+// trustedProxies holds the proxies ClientIP believes, or nil to believe the
+// headers from any client
+var trustedProxies atomic.Pointer[TrustedProxies]
+
+// SetTrustedProxies sets the proxies ClientIP believes the forwarding headers
+// of; nil believes them from any client
+func SetTrustedProxies(proxies *TrustedProxies) {
+	trustedProxies.Store(proxies)
+}
+
+// ClientIP returns the address of the client that sent the request, using
+// the proxies set with SetTrustedProxies. Until proxies are set, the first
+// X-Forwarded-For address or X-Real-IP is taken from any client.
+func ClientIP(r *http.Request) string {
+	if proxies := trustedProxies.Load(); proxies != nil {
+		return proxies.ClientIP(r)
+	}
+
+	// Check for X-Forwarded-For header first
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		// X-Forwarded-For can contain multiple IPs, we want the first one
+		ips := strings.Split(xff, ",")
+		return strings.TrimSpace(ips[0])
+	}
+
+	// Check for X-Real-IP header
+	if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
+		return xrip
+	}
+
+	return remoteIP(r)
+}
+
+// remoteIP returns the address the request came from, without the port
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// If there's an error, just use RemoteAddr as-is
+		return r.RemoteAddr
+	}
+	return ip
+}
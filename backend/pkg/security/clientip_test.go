@@ -0,0 +1,43 @@
+package security
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxiesClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.10"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    string
+		want   string
+	}{
+		{"direct client", "203.0.113.5:4000", "", "203.0.113.5"},
+		{"untrusted client spoofing the header", "203.0.113.5:4000", "198.51.100.1", "203.0.113.5"},
+		{"through a proxy", "10.1.2.3:4000", "203.0.113.5", "203.0.113.5"},
+		{"spoofed entry before the proxy's", "10.1.2.3:4000", "198.51.100.1, 203.0.113.5", "203.0.113.5"},
+		{"through two proxies", "192.0.2.10:4000", "203.0.113.5, 10.9.9.9", "203.0.113.5"},
+		{"only proxies", "10.1.2.3:4000", "10.4.4.4, 10.5.5.5", "10.4.4.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				r.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			assert.Equal(t, tt.want, proxies.ClientIP(r))
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsHostnames(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"proxy.internal"})
+	assert.Error(t, err)
+}
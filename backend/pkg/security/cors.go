@@ -3,42 +3,44 @@ package security
 import (
 	"net/http"
 	"strconv"
+	"strings"
 )
 
+// CORSConfig configures cross-origin requests. AllowedOrigins are exact
+// origins such as "https://app.example.com", or patterns such as
+// "https://*.example.com" that match any subdomain but not the domain itself.
 type CORSConfig struct {
 	AllowedOrigins   []string
 	AllowedMethods   []string
 	AllowedHeaders   []string
+	ExposedHeaders   []string
 	AllowCredentials bool
 	MaxAge           int
 }
 
+// DefaultCORSConfig allows the local frontend development server; deployments
+// set their origins through configuration
 func DefaultCORSConfig() CORSConfig {
 	return CORSConfig{
-		// Allow all origins temporarily for debugging
-		// TODO: Replace "*" with "FRONTEND_URL" for production
 		AllowedOrigins:   []string{"http://localhost:5173"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-CSRF-Token", "X-Requested-With"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-CSRF-Token", "X-Requested-With", "X-Request-ID"},
+		ExposedHeaders:   []string{"X-Request-ID"},
 		AllowCredentials: true,
 		MaxAge:           86400,
 	}
 }
 
+// CORSMiddleware answers preflight requests and sets the CORS headers,
+// echoing the request's origin when it is allowed
 func CORSMiddleware(config CORSConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 
-			allowed := false
-			for _, allowedOrigin := range config.AllowedOrigins {
-				if allowedOrigin == "*" || allowedOrigin == origin {
-					allowed = true
-					break
-				}
-			}
-
-			if allowed {
+			// The response depends on the origin, so caches must not share it
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && originAllowed(config.AllowedOrigins, origin) {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 			}
 
@@ -50,6 +52,10 @@ func CORSMiddleware(config CORSConfig) func(http.Handler) http.Handler {
 				w.Header().Set("Access-Control-Allow-Headers", joinStrings(config.AllowedHeaders, ", "))
 			}
 
+			if len(config.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", joinStrings(config.ExposedHeaders, ", "))
+			}
+
 			if len(config.AllowedMethods) > 0 {
 				w.Header().Set("Access-Control-Allow-Methods", joinStrings(config.AllowedMethods, ", "))
 			}
@@ -59,7 +65,6 @@ func CORSMiddleware(config CORSConfig) func(http.Handler) http.Handler {
 			}
 
 			if r.Method == http.MethodOptions {
-				w.Header().Add("Vary", "Origin")
 				w.Header().Add("Vary", "Access-Control-Request-Method")
 				w.Header().Add("Vary", "Access-Control-Request-Headers")
 				w.WriteHeader(http.StatusNoContent)
@@ -71,6 +76,35 @@ func CORSMiddleware(config CORSConfig) func(http.Handler) http.Handler {
 	}
 }
 
+// originAllowed reports whether an origin matches one of the allowed origins
+// or wildcard patterns
+func originAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || pattern == origin || matchOriginPattern(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOriginPattern matches an origin against a pattern such as
+// "https://*.example.com". The scheme and port must be the same, and the
+// wildcard stands for one or more subdomain labels.
+func matchOriginPattern(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	prefix := scheme + "://"
+	if !strings.HasPrefix(origin, prefix) {
+		return false
+	}
+	sub, found := strings.CutSuffix(origin[len(prefix):], "."+host)
+	// The subdomain is a host name, so it can't carry a port or path that
+	// would make the suffix match a different host
+	return found && sub != "" && !strings.ContainsAny(sub, ":/@")
+}
+
 func joinStrings(strings []string, separator string) string {
 	if len(strings) == 0 {
 		return ""
@@ -0,0 +1,45 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddlewareOrigins(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"https://app.example.com", "https://*.example.org"}
+	handler := CORSMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://other.example.com", false},
+		{"https://preview.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"http://preview.example.org", false},
+		{"https://preview.example.org:8443", false},
+		{"https://evilexample.org", false},
+		{"https://preview.example.org.evil.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if tt.allowed {
+				assert.Equal(t, tt.origin, w.Header().Get("Access-Control-Allow-Origin"))
+			} else {
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+			}
+			assert.Contains(t, w.Header().Values("Vary"), "Origin")
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CSP errors
+var (
+	ErrInvalidCSP       = errors.New("invalid content security policy")
+	ErrInvalidCSPReport = errors.New("invalid content security policy report")
+)
+
+// cspDirectives lists the directives a policy may use
+var cspDirectives = map[string]bool{
+	"default-src":               true,
+	"script-src":                true,
+	"script-src-elem":           true,
+	"script-src-attr":           true,
+	"style-src":                 true,
+	"style-src-elem":            true,
+	"style-src-attr":            true,
+	"img-src":                   true,
+	"font-src":                  true,
+	"connect-src":               true,
+	"media-src":                 true,
+	"object-src":                true,
+	"frame-src":                 true,
+	"child-src":                 true,
+	"worker-src":                true,
+	"manifest-src":              true,
+	"frame-ancestors":           true,
+	"form-action":               true,
+	"base-uri":                  true,
+	"sandbox":                   true,
+	"upgrade-insecure-requests": true,
+	"require-trusted-types-for": true,
+	"trusted-types":             true,
+	"report-uri":                true,
+	"report-to":                 true,
+}
+
+// cspKeywords lists the quoted source keywords; nonces and hashes are checked separately
+var cspKeywords = map[string]bool{
+	"'self'":             true,
+	"'none'":             true,
+	"'unsafe-inline'":    true,
+	"'unsafe-eval'":      true,
+	"'unsafe-hashes'":    true,
+	"'strict-dynamic'":   true,
+	"'report-sample'":    true,
+	"'wasm-unsafe-eval'": true,
+	"'script'":           true,
+}
+
+// ValidateCSP checks that a Content-Security-Policy only uses known
+// directives, names each at most once and quotes source keywords correctly
+func ValidateCSP(policy string) error {
+	if strings.ContainsAny(policy, "\r\n,") {
+		return fmt.Errorf("%w: policy must be a single policy on one line", ErrInvalidCSP)
+	}
+
+	seen := make(map[string]bool)
+	for directive := range strings.SplitSeq(policy, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := strings.ToLower(fields[0])
+		if !cspDirectives[name] {
+			return fmt.Errorf("%w: unknown directive %q", ErrInvalidCSP, fields[0])
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: directive %q is given more than once", ErrInvalidCSP, name)
+		}
+		seen[name] = true
+
+		for _, source := range fields[1:] {
+			if !strings.HasPrefix(source, "'") {
+				continue
+			}
+			if !validCSPKeyword(strings.ToLower(source)) {
+				return fmt.Errorf("%w: unknown keyword %s in %s", ErrInvalidCSP, source, name)
+			}
+			if strings.EqualFold(source, "'none'") && len(fields) > 2 {
+				return fmt.Errorf("%w: 'none' must be the only source of %s", ErrInvalidCSP, name)
+			}
+		}
+	}
+
+	if len(seen) == 0 {
+		return fmt.Errorf("%w: policy has no directives", ErrInvalidCSP)
+	}
+	return nil
+}
+
+// validCSPKeyword reports whether a quoted source is a keyword, nonce or hash
+func validCSPKeyword(source string) bool {
+	if cspKeywords[source] {
+		return true
+	}
+	if !strings.HasSuffix(source, "'") || len(source) < 3 {
+		return false
+	}
+	for _, prefix := range []string{"'nonce-", "'sha256-", "'sha384-", "'sha512-"} {
+		if strings.HasPrefix(source, prefix) && len(source) > len(prefix)+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// cspHasDirective reports whether a policy sets a directive
+func cspHasDirective(policy, name string) bool {
+	for directive := range strings.SplitSeq(policy, ";") {
+		if fields := strings.Fields(directive); len(fields) > 0 && strings.EqualFold(fields[0], name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CSPViolation is a Content-Security-Policy violation reported by a browser
+type CSPViolation struct {
+	DocumentURI       string `json:"document_uri"`
+	Referrer          string `json:"referrer,omitempty"`
+	ViolatedDirective string `json:"violated_directive"`
+	BlockedURI        string `json:"blocked_uri,omitempty"`
+	SourceFile        string `json:"source_file,omitempty"`
+	LineNumber        int    `json:"line_number,omitempty"`
+	ColumnNumber      int    `json:"column_number,omitempty"`
+	// Disposition is "enforce" for blocked content and "report" for
+	// violations of report-only policies
+	Disposition string `json:"disposition,omitempty"`
+}
+
+// legacyCSPReport is the body browsers send to report-uri endpoints
+type legacyCSPReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		Referrer           string `json:"referrer"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		BlockedURI         string `json:"blocked-uri"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+		ColumnNumber       int    `json:"column-number"`
+		Disposition        string `json:"disposition"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport is one report of a Reporting API batch sent to report-to endpoints
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string `json:"documentURL"`
+		Referrer           string `json:"referrer"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		BlockedURL         string `json:"blockedURL"`
+		SourceFile         string `json:"sourceFile"`
+		LineNumber         int    `json:"lineNumber"`
+		ColumnNumber       int    `json:"columnNumber"`
+		Disposition        string `json:"disposition"`
+	} `json:"body"`
+}
+
+// ParseCSPReports reads the violations in a report body, accepting both the
+// report-uri format and Reporting API batches. Reports of other types in a
+// batch are skipped.
+func ParseCSPReports(body []byte) ([]CSPViolation, error) {
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		var batch []reportingAPIReport
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSPReport, err)
+		}
+		violations := make([]CSPViolation, 0, len(batch))
+		for _, report := range batch {
+			if report.Type != "csp-violation" {
+				continue
+			}
+			violations = append(violations, CSPViolation{
+				DocumentURI:       report.Body.DocumentURL,
+				Referrer:          report.Body.Referrer,
+				ViolatedDirective: report.Body.EffectiveDirective,
+				BlockedURI:        report.Body.BlockedURL,
+				SourceFile:        report.Body.SourceFile,
+				LineNumber:        report.Body.LineNumber,
+				ColumnNumber:      report.Body.ColumnNumber,
+				Disposition:       report.Body.Disposition,
+			})
+		}
+		return violations, nil
+	}
+
+	var report legacyCSPReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSPReport, err)
+	}
+	directive := report.Report.EffectiveDirective
+	if directive == "" {
+		directive = report.Report.ViolatedDirective
+	}
+	if directive == "" {
+		return nil, fmt.Errorf("%w: no violated directive", ErrInvalidCSPReport)
+	}
+	return []CSPViolation{{
+		DocumentURI:       report.Report.DocumentURI,
+		Referrer:          report.Report.Referrer,
+		ViolatedDirective: directive,
+		BlockedURI:        report.Report.BlockedURI,
+		SourceFile:        report.Report.SourceFile,
+		LineNumber:        report.Report.LineNumber,
+		ColumnNumber:      report.Report.ColumnNumber,
+		Disposition:       report.Report.Disposition,
+	}}, nil
+}
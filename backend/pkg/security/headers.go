@@ -1,9 +1,30 @@
 package security
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
 )
 
+// Security header profiles
+const (
+	// HeadersProfileStrict enforces the default policy and forbids framing
+	HeadersProfileStrict = "strict"
+	// HeadersProfileEmbedded enforces the default policy but lets the listed
+	// origins embed the app in frames
+	HeadersProfileEmbedded = "embedded"
+	// HeadersProfileReportOnly reports violations of the default policy
+	// without blocking anything, for trying out a policy
+	HeadersProfileReportOnly = "report-only"
+)
+
+// ErrInvalidHeadersSettings is returned when security header settings fail validation
+var ErrInvalidHeadersSettings = errors.New("invalid security header settings")
+
 // HeadersConfig contains configuration options for security headers
 type HeadersConfig struct {
 	// HSTS determines if Strict-Transport-Security header should be set
@@ -16,6 +37,9 @@ type HeadersConfig struct {
 	HSTSPreload bool
 	// ContentSecurityPolicy is the Content-Security-Policy header value
 	ContentSecurityPolicy string
+	// CSPReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// so browsers report violations without blocking anything
+	CSPReportOnly bool
 	// XFrameOptions is the X-Frame-Options header value
 	XFrameOptions string
 	// XContentTypeOptions is the X-Content-Type-Options header value
@@ -35,51 +59,162 @@ func DefaultHeadersConfig() HeadersConfig {
 	}
 }
 
+// HeadersSettings choose a security header profile and adjust it. They are
+// edited by admins at runtime, so they are validated before use.
+type HeadersSettings struct {
+	// Profile is one of the HeadersProfile constants
+	Profile string `json:"profile"`
+	// FrameAncestors lists the origins allowed to embed the app under the
+	// embedded profile; the app's own origin is always allowed
+	FrameAncestors []string `json:"frame_ancestors,omitempty"`
+	// ContentSecurityPolicy replaces the profile's policy when set
+	ContentSecurityPolicy string `json:"content_security_policy,omitempty"`
+}
+
+// DefaultHeadersSettings returns the settings used until an admin changes them
+func DefaultHeadersSettings() HeadersSettings {
+	return HeadersSettings{Profile: HeadersProfileStrict}
+}
+
+// Validate validates the header settings
+func (s HeadersSettings) Validate() error {
+	switch s.Profile {
+	case HeadersProfileStrict, HeadersProfileEmbedded, HeadersProfileReportOnly:
+	default:
+		return fmt.Errorf("%w: profile must be one of %s, %s, %s", ErrInvalidHeadersSettings, HeadersProfileStrict, HeadersProfileEmbedded, HeadersProfileReportOnly)
+	}
+
+	if len(s.FrameAncestors) > 0 && s.Profile != HeadersProfileEmbedded {
+		return fmt.Errorf("%w: frame ancestors only apply to the %s profile", ErrInvalidHeadersSettings, HeadersProfileEmbedded)
+	}
+	for _, origin := range s.FrameAncestors {
+		if !validFrameAncestor(origin) {
+			return fmt.Errorf("%w: frame ancestor %q must be an http or https origin", ErrInvalidHeadersSettings, origin)
+		}
+	}
+
+	if s.ContentSecurityPolicy != "" {
+		if err := ValidateCSP(s.ContentSecurityPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Config builds the header configuration for the settings. Policies that
+// don't name a report endpoint report violations to reportURI when it is set.
+func (s HeadersSettings) Config(reportURI string) HeadersConfig {
+	config := DefaultHeadersConfig()
+
+	switch s.Profile {
+	case HeadersProfileEmbedded:
+		ancestors := append([]string{"'self'"}, s.FrameAncestors...)
+		config.ContentSecurityPolicy = strings.Replace(config.ContentSecurityPolicy, "frame-ancestors 'none'", "frame-ancestors "+strings.Join(ancestors, " "), 1)
+		// X-Frame-Options can't name other origins; frame-ancestors replaces it
+		config.XFrameOptions = ""
+	case HeadersProfileReportOnly:
+		config.CSPReportOnly = true
+	}
+
+	if s.ContentSecurityPolicy != "" {
+		config.ContentSecurityPolicy = s.ContentSecurityPolicy
+	}
+	if reportURI != "" && !cspHasDirective(config.ContentSecurityPolicy, "report-uri") && !cspHasDirective(config.ContentSecurityPolicy, "report-to") {
+		config.ContentSecurityPolicy += "; report-uri " + reportURI
+	}
+
+	return config
+}
+
+// validFrameAncestor reports whether origin is an http or https origin,
+// optionally with a wildcard subdomain
+func validFrameAncestor(origin string) bool {
+	u, err := url.Parse(strings.Replace(origin, "*.", "wildcard.", 1))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
+
 // SecurityHeaders provides middleware for setting security headers
 func SecurityHeaders(config HeadersConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Set Strict-Transport-Security header
-			if config.HSTS {
-				var value string
-				if config.HSTSMaxAge > 0 {
-					value = "max-age=" + http.StatusText(config.HSTSMaxAge)
-					if config.HSTSIncludeSubdomains {
-						value += "; includeSubDomains"
-					}
-					if config.HSTSPreload {
-						value += "; preload"
-					}
-				}
-				if value != "" {
-					w.Header().Set("Strict-Transport-Security", value)
-				}
-			}
-
-			// Set Content-Security-Policy header
-			if config.ContentSecurityPolicy != "" {
-				w.Header().Set("Content-Security-Policy", config.ContentSecurityPolicy)
-			}
-
-			// Set X-Frame-Options header
-			if config.XFrameOptions != "" {
-				w.Header().Set("X-Frame-Options", config.XFrameOptions)
-			}
-
-			// Set X-Content-Type-Options header
-			if config.XContentTypeOptions != "" {
-				w.Header().Set("X-Content-Type-Options", config.XContentTypeOptions)
-			}
-
-			// Set additional security headers
-			w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
-			w.Header().Set("X-XSS-Protection", "1; mode=block")
-			w.Header().Set("Permissions-Policy", "accelerometer=(), camera=(), geolocation=(), gyroscope=(), magnetometer=(), microphone=(), payment=(), usb=()")
-			w.Header().Set("Cache-Control", "no-store, max-age=0")
-			w.Header().Set("Pragma", "no-cache")
-			w.Header().Set("X-Permitted-Cross-Domain-Policies", "none")
-
+			setSecurityHeaders(w, &config)
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// HeadersSwitch sets security headers from a configuration that can be
+// replaced while the server runs
+type HeadersSwitch struct {
+	config atomic.Pointer[HeadersConfig]
+}
+
+// NewHeadersSwitch creates a headers switch starting with config
+func NewHeadersSwitch(config HeadersConfig) *HeadersSwitch {
+	s := &HeadersSwitch{}
+	s.Set(config)
+	return s
+}
+
+// Set replaces the configuration used for subsequent requests
+func (s *HeadersSwitch) Set(config HeadersConfig) {
+	s.config.Store(&config)
+}
+
+// Config returns the current configuration
+func (s *HeadersSwitch) Config() HeadersConfig {
+	return *s.config.Load()
+}
+
+// Middleware sets security headers from the current configuration
+func (s *HeadersSwitch) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setSecurityHeaders(w, s.config.Load())
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setSecurityHeaders writes the headers described by config
+func setSecurityHeaders(w http.ResponseWriter, config *HeadersConfig) {
+	// Set Strict-Transport-Security header
+	if config.HSTS && config.HSTSMaxAge > 0 {
+		value := "max-age=" + strconv.Itoa(config.HSTSMaxAge)
+		if config.HSTSIncludeSubdomains {
+			value += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			value += "; preload"
+		}
+		w.Header().Set("Strict-Transport-Security", value)
+	}
+
+	// Set Content-Security-Policy header
+	if config.ContentSecurityPolicy != "" {
+		if config.CSPReportOnly {
+			w.Header().Set("Content-Security-Policy-Report-Only", config.ContentSecurityPolicy)
+		} else {
+			w.Header().Set("Content-Security-Policy", config.ContentSecurityPolicy)
+		}
+	}
+
+	// Set X-Frame-Options header
+	if config.XFrameOptions != "" {
+		w.Header().Set("X-Frame-Options", config.XFrameOptions)
+	}
+
+	// Set X-Content-Type-Options header
+	if config.XContentTypeOptions != "" {
+		w.Header().Set("X-Content-Type-Options", config.XContentTypeOptions)
+	}
+
+	// Set additional security headers
+	w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+	w.Header().Set("X-XSS-Protection", "1; mode=block")
+	w.Header().Set("Permissions-Policy", "accelerometer=(), camera=(), geolocation=(), gyroscope=(), magnetometer=(), microphone=(), payment=(), usb=()")
+	w.Header().Set("Cache-Control", "no-store, max-age=0")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("X-Permitted-Cross-Domain-Policies", "none")
+}
@@ -0,0 +1,91 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadersSettings(t *testing.T) {
+	strict := DefaultHeadersSettings().Config("/csp-report")
+	assert.Contains(t, strict.ContentSecurityPolicy, "frame-ancestors 'none'")
+	assert.True(t, strings.HasSuffix(strict.ContentSecurityPolicy, "; report-uri /csp-report"))
+	assert.Equal(t, "DENY", strict.XFrameOptions)
+	assert.False(t, strict.CSPReportOnly)
+
+	embedded := HeadersSettings{Profile: HeadersProfileEmbedded, FrameAncestors: []string{"https://*.example.com"}}
+	require.NoError(t, embedded.Validate())
+	config := embedded.Config("")
+	assert.Contains(t, config.ContentSecurityPolicy, "frame-ancestors 'self' https://*.example.com")
+	assert.Empty(t, config.XFrameOptions)
+
+	assert.True(t, HeadersSettings{Profile: HeadersProfileReportOnly}.Config("").CSPReportOnly)
+
+	// Custom policies keep their own report endpoint
+	custom := HeadersSettings{Profile: HeadersProfileStrict, ContentSecurityPolicy: "default-src 'self'; report-to csp"}
+	require.NoError(t, custom.Validate())
+	assert.Equal(t, custom.ContentSecurityPolicy, custom.Config("/csp-report").ContentSecurityPolicy)
+
+	for _, invalid := range []HeadersSettings{
+		{Profile: "lenient"},
+		{Profile: HeadersProfileStrict, FrameAncestors: []string{"https://example.com"}},
+		{Profile: HeadersProfileEmbedded, FrameAncestors: []string{"example.com"}},
+		{Profile: HeadersProfileEmbedded, FrameAncestors: []string{"https://example.com/path"}},
+		{Profile: HeadersProfileStrict, ContentSecurityPolicy: "default-src 'self'; script-scr 'self'"},
+	} {
+		assert.Error(t, invalid.Validate(), "settings %+v", invalid)
+	}
+}
+
+func TestValidateCSP(t *testing.T) {
+	require.NoError(t, ValidateCSP(DefaultHeadersConfig().ContentSecurityPolicy))
+	require.NoError(t, ValidateCSP("script-src 'self' 'nonce-abc123' 'sha256-Zm9v'; upgrade-insecure-requests"))
+
+	for _, policy := range []string{
+		"",
+		"default-src 'self'; default-src 'none'",
+		"default-src 'slef'",
+		"default-src 'none' https://example.com",
+		"default-src 'self', script-src 'self'",
+		"default-src 'self'\nscript-src 'self'",
+	} {
+		assert.ErrorIs(t, ValidateCSP(policy), ErrInvalidCSP, "policy %q", policy)
+	}
+}
+
+func TestHeadersSwitch(t *testing.T) {
+	headers := NewHeadersSwitch(DefaultHeadersConfig())
+	handler := headers.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "max-age=31536000; includeSubDomains; preload", rr.Header().Get("Strict-Transport-Security"))
+	assert.NotEmpty(t, rr.Header().Get("Content-Security-Policy"))
+
+	headers.Set(HeadersSettings{Profile: HeadersProfileReportOnly}.Config(""))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, rr.Header().Get("Content-Security-Policy"))
+	assert.NotEmpty(t, rr.Header().Get("Content-Security-Policy-Report-Only"))
+}
+
+func TestParseCSPReports(t *testing.T) {
+	violations, err := ParseCSPReports([]byte(`{"csp-report": {"document-uri": "https://app.example.com/", "violated-directive": "script-src-elem", "blocked-uri": "https://evil.example.com/x.js", "disposition": "enforce"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []CSPViolation{{DocumentURI: "https://app.example.com/", ViolatedDirective: "script-src-elem", BlockedURI: "https://evil.example.com/x.js", Disposition: "enforce"}}, violations)
+
+	violations, err = ParseCSPReports([]byte(`[{"type": "deprecation", "body": {}}, {"type": "csp-violation", "body": {"documentURL": "https://app.example.com/", "effectiveDirective": "img-src", "blockedURL": "data", "lineNumber": 3, "disposition": "report"}}]`))
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "img-src", violations[0].ViolatedDirective)
+	assert.Equal(t, 3, violations[0].LineNumber)
+
+	for _, body := range []string{`not json`, `{"csp-report": {}}`, `[{"type": 1}]`} {
+		_, err := ParseCSPReports([]byte(body))
+		assert.ErrorIs(t, err, ErrInvalidCSPReport, "body %s", body)
+	}
+}
@@ -0,0 +1,177 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrUnknownKey is returned when ciphertext names a key that isn't in the keyring
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrDecryption is returned when ciphertext can't be decrypted with any key
+	ErrDecryption = errors.New("decryption failed")
+)
+
+// keyIDPattern limits key IDs to characters that can't appear in standard
+// base64, so ciphertext with a key ID can't be mistaken for legacy ciphertext
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// keyIDSeparator separates the key ID from the sealed data
+const keyIDSeparator = "."
+
+// Keyring encrypts data with AES-256-GCM under a primary key and decrypts data
+// written under any of its keys. Ciphertext is "<key id>.<base64 nonce+data>",
+// so keys can be rotated by adding a new primary key and keeping the old ones
+// until everything encrypted with them has expired or been re-encrypted.
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+	// order lists the key IDs with the primary first, for legacy ciphertext
+	order []string
+}
+
+// NewKeyring creates a keyring from 32-byte keys by ID, encrypting with primary
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q is not in the keyring", primary)
+	}
+
+	k := &Keyring{
+		primary: primary,
+		aeads:   make(map[string]cipher.AEAD, len(keys)),
+		order:   []string{primary},
+	}
+	for id, key := range keys {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		// AES-256 requires a 32-byte key
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aesGCM, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.aeads[id] = aesGCM
+		if id != primary {
+			k.order = append(k.order, id)
+		}
+	}
+
+	return k, nil
+}
+
+// ParseKeyring parses a keyring from "id:base64key,id:base64key". The first
+// key is the primary key; the others are only used to decrypt.
+func ParseKeyring(spec string) (*Keyring, error) {
+	var primary string
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid keyring entry %q, expected id:base64key", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 for key %q: %w", id, err)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+		keys[id] = key
+		if primary == "" {
+			primary = id
+		}
+	}
+
+	return NewKeyring(primary, keys)
+}
+
+// PrimaryKeyID returns the ID of the key used to encrypt
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// Encrypt encrypts plaintext with the primary key. The key ID is authenticated
+// along with the data so ciphertext can't be relabelled with another key.
+func (k *Keyring) Encrypt(plaintext []byte) (string, error) {
+	aesGCM := k.aeads[k.primary]
+
+	// Create a nonce
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := aesGCM.Seal(nonce, nonce, plaintext, []byte(k.primary))
+	return k.primary + keyIDSeparator + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts ciphertext produced by Encrypt with any key in the keyring.
+// Ciphertext without a key ID, written before keys had IDs, is tried against
+// every key.
+func (k *Keyring) Decrypt(encryptedData string) ([]byte, error) {
+	id, encoded, ok := strings.Cut(encryptedData, keyIDSeparator)
+	if !ok {
+		return k.decryptLegacy(encryptedData)
+	}
+
+	aesGCM, known := k.aeads[id]
+	if !known {
+		return nil, ErrUnknownKey
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return open(aesGCM, ciphertext, []byte(id))
+}
+
+// NeedsRotation reports whether encryptedData was not encrypted with the
+// primary key and should be re-encrypted
+func (k *Keyring) NeedsRotation(encryptedData string) bool {
+	id, _, ok := strings.Cut(encryptedData, keyIDSeparator)
+	return !ok || id != k.primary
+}
+
+// decryptLegacy decrypts base64 ciphertext that carries no key ID
+func (k *Keyring) decryptLegacy(encryptedData string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	for _, id := range k.order {
+		if plaintext, err := open(k.aeads[id], ciphertext, nil); err == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, ErrDecryption
+}
+
+// open splits the nonce from ciphertext and decrypts it
+func open(aesGCM cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	nonceSize := aesGCM.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, ErrDecryption
+	}
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
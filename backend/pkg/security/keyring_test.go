@@ -0,0 +1,77 @@
+package security
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	oldRing, err := NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	require.NoError(t, err)
+	encrypted, err := oldRing.Encrypt([]byte("jane@example.com"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "k1."))
+
+	// After rotation, data written under the old key still decrypts
+	ring, err := ParseKeyring("k2:" + base64.StdEncoding.EncodeToString(newKey) + ",k1:" + base64.StdEncoding.EncodeToString(oldKey))
+	require.NoError(t, err)
+	assert.Equal(t, "k2", ring.PrimaryKeyID())
+	plaintext, err := ring.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", string(plaintext))
+	assert.True(t, ring.NeedsRotation(encrypted))
+
+	reencrypted, err := ring.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.False(t, ring.NeedsRotation(reencrypted))
+
+	// Relabelling ciphertext with another key ID fails authentication
+	_, err = ring.Decrypt("k1" + strings.TrimPrefix(reencrypted, "k2"))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	// Once the old key is dropped its data can't be read
+	_, err = oldRing.Decrypt(reencrypted)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyringLegacyCiphertext(t *testing.T) {
+	key := bytes.Repeat([]byte{3}, 32)
+
+	// Ciphertext in the format used before keys had IDs
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	aesGCM, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := make([]byte, aesGCM.NonceSize())
+	legacy := base64.StdEncoding.EncodeToString(aesGCM.Seal(nonce, nonce, []byte("legacy"), nil))
+
+	ring, err := NewKeyring("new", map[string][]byte{"new": bytes.Repeat([]byte{4}, 32), "old": key})
+	require.NoError(t, err)
+	plaintext, err := ring.Decrypt(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", string(plaintext))
+	assert.True(t, ring.NeedsRotation(legacy))
+}
+
+func TestNewKeyringValidation(t *testing.T) {
+	key := bytes.Repeat([]byte{5}, 32)
+
+	_, err := NewKeyring("missing", map[string][]byte{"k1": key})
+	assert.Error(t, err)
+	_, err = NewKeyring("k1", map[string][]byte{"k1": key[:16]})
+	assert.Error(t, err)
+	_, err = NewKeyring("bad.id", map[string][]byte{"bad.id": key})
+	assert.Error(t, err)
+	_, err = ParseKeyring("k1")
+	assert.Error(t, err)
+}
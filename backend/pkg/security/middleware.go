@@ -57,7 +57,7 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 }
 
 // SecurityMiddleware combines all security middleware
-func SecurityMiddleware(csrfProtection *CSRFProtection, session *Session, rateLimiter *RateLimiter, validator *Validator) Middleware {
+func SecurityMiddleware(csrfProtection *CSRFProtection, session *Session, rateLimiter Limiter, validator *Validator) Middleware {
 	securityHeaders := SecurityHeaders(DefaultHeadersConfig())
 
 	return Chain(
@@ -4,10 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"strconv"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -20,27 +19,118 @@ const (
 	DefaultRateInterval = time.Minute
 )
 
-// ErrRateLimitExceeded is returned when the rate limit is exceeded
-var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+// Rate limiting errors
+var (
+	// ErrRateLimitExceeded is returned when the rate limit is exceeded
+	ErrRateLimitExceeded = errors.New("rate limit exceeded")
+	// ErrRateLimitUnavailable is returned by fail-closed limiters when requests
+	// can't be counted
+	ErrRateLimitUnavailable = errors.New("rate limiter unavailable")
+)
+
+// RateLimitPolicy is a named request limit. Requests are counted per policy,
+// so routes under different policies don't use up each other's allowance.
+type RateLimitPolicy struct {
+	Name     string
+	Limit    int
+	Interval time.Duration
+	// FailClosed rejects requests while Redis is unreachable instead of
+	// counting them in process. Each instance only sees its share of the
+	// traffic, so a process-local count is weaker than the shared one.
+	FailClosed bool
+}
+
+// Rate limit policies
+var (
+	// StrictRateLimit guards endpoints that check credentials, like login and
+	// password reset, against guessing
+	StrictRateLimit = RateLimitPolicy{Name: "strict", Limit: 10, Interval: time.Minute}
+	// DefaultRateLimitPolicy applies to writes
+	DefaultRateLimitPolicy = RateLimitPolicy{Name: "default", Limit: DefaultRateLimit, Interval: DefaultRateInterval}
+	// LenientRateLimit applies to reads, which clients make far more of
+	LenientRateLimit = RateLimitPolicy{Name: "lenient", Limit: 600, Interval: time.Minute}
+)
+
+// Config returns a limiter configuration enforcing the policy
+func (p RateLimitPolicy) Config(redisClient redis.UniversalClient) RateLimiterConfig {
+	return RateLimiterConfig{
+		Redis:      redisClient,
+		Name:       p.Name,
+		Limit:      p.Limit,
+		Interval:   p.Interval,
+		FailClosed: p.FailClosed,
+	}
+}
+
+// Limiter enforces a sliding-window request limit per client and path
+type Limiter interface {
+	// CheckRateLimit records the request and returns the number of requests in
+	// the current window, or ErrRateLimitExceeded once the limit is reached
+	CheckRateLimit(ctx context.Context, r *http.Request) (int, error)
+	// Policy returns the limit enforced
+	Policy() RateLimitPolicy
+	// Middleware rejects requests over the limit with 429 Too Many Requests
+	Middleware(next http.Handler) http.Handler
+}
+
+// NewLimiter creates a Redis-backed limiter, or an in-memory one when no Redis
+// client is configured (development, tests and single-instance deployments)
+func NewLimiter(config RateLimiterConfig) Limiter {
+	if config.Redis == nil {
+		log.Warn().Msg("No Redis client for rate limiting, counting requests in memory")
+		return NewMemoryRateLimiter(config)
+	}
+	return NewRateLimiter(config)
+}
 
 // RateLimiterConfig contains configuration options for rate limiting
 type RateLimiterConfig struct {
 	// Redis is the Redis client to use for rate limiting
-	Redis *redis.Client
+	Redis redis.UniversalClient
+	// Name is the policy name requests are counted under
+	Name string
 	// Limit is the maximum number of requests per interval
 	Limit int
 	// Interval is the time period for the limit
 	Interval time.Duration
+	// Identify returns who a request is counted against. It defaults to the
+	// client IP; authenticated routes can count per user instead, so users
+	// behind a shared address don't exhaust each other's allowance.
+	Identify func(r *http.Request) string
+	// FailClosed rejects requests with ErrRateLimitUnavailable while Redis is
+	// unreachable; otherwise they are counted in process until it is back
+	FailClosed bool
 	// SkipSuccessfulAuth determines if successful authentication requests should bypass rate limiting
 	SkipSuccessfulAuth bool
 }
 
-// RateLimiter provides rate limiting functionality
+// withDefaults fills in the policy name, limit, interval and identity
+func (c RateLimiterConfig) withDefaults() RateLimiterConfig {
+	if c.Name == "" {
+		c.Name = DefaultRateLimitPolicy.Name
+	}
+	if c.Limit <= 0 {
+		c.Limit = DefaultRateLimit
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultRateInterval
+	}
+	if c.Identify == nil {
+		c.Identify = IPIdentity
+	}
+	return c
+}
+
+// RateLimiter provides rate limiting backed by Redis, so limits are shared
+// between instances
 type RateLimiter struct {
-	redis    *redis.Client
-	limit    int
-	interval time.Duration
+	redis    redis.UniversalClient
+	policy   RateLimitPolicy
+	identify func(r *http.Request) string
 	skipAuth bool
+	// fallback counts requests while Redis is unreachable, unless the
+	// policy fails closed
+	fallback *TokenBucketLimiter
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -50,101 +140,86 @@ func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
 	}
 
 	// Set defaults if not provided
-	if config.Limit <= 0 {
-		config.Limit = DefaultRateLimit
-	}
-	if config.Interval <= 0 {
-		config.Interval = DefaultRateInterval
-	}
+	config = config.withDefaults()
 
 	return &RateLimiter{
 		redis:    config.Redis,
-		limit:    config.Limit,
-		interval: config.Interval,
+		policy:   RateLimitPolicy{Name: config.Name, Limit: config.Limit, Interval: config.Interval, FailClosed: config.FailClosed},
+		identify: config.Identify,
 		skipAuth: config.SkipSuccessfulAuth,
+		fallback: NewTokenBucketLimiter(config),
 	}
 }
 
-// getIPAddress extracts the real IP address from the request
-func getIPAddress(r *http.Request) string {
-	// Check for X-Forwarded-For header first
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// X-Forwarded-For can contain multiple IPs, we want the first one
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			clientIP := strings.TrimSpace(ips[0])
-			return clientIP
-		}
-	}
-
-	// Check for X-Real-IP header
-	if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
-		return xrip
-	}
+// Policy returns the limit enforced
+func (rl *RateLimiter) Policy() RateLimitPolicy {
+	return rl.policy
+}
 
-	// Extract IP from RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		// If there's an error, just use RemoteAddr as-is
-		return r.RemoteAddr
-	}
-	return ip
+// IPIdentity counts requests against the client IP
+func IPIdentity(r *http.Request) string {
+	return "ip:" + ClientIP(r)
 }
 
-// getLimitKey generates a Redis key for rate limiting
-func (rl *RateLimiter) getLimitKey(r *http.Request) string {
-	ip := getIPAddress(r)
-	path := r.URL.Path
-	return fmt.Sprintf("ratelimit:%s:%s", ip, path)
+// getLimitKey generates the key requests are counted under
+func getLimitKey(policy string, identity string, r *http.Request) string {
+	return fmt.Sprintf("ratelimit:%s:%s:%s", policy, identity, r.URL.Path)
 }
 
+// slidingWindowScript records a request in a sorted set of request times
+// unless the window is full, returning whether it was allowed and the count.
+// Running it as one script keeps concurrent requests from both seeing room
+// for the last slot.
+var slidingWindowScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1] - ARGV[2])
+local count = redis.call('ZCARD', KEYS[1])
+if count >= tonumber(ARGV[3]) then
+	return {0, count}
+end
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[4])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return {1, count + 1}
+`)
+
 // CheckRateLimit checks if the request is within the rate limit
 func (rl *RateLimiter) CheckRateLimit(ctx context.Context, r *http.Request) (int, error) {
-	key := rl.getLimitKey(r)
-	now := time.Now().Unix()
-	windowStart := now - int64(rl.interval.Seconds())
+	key := getLimitKey(rl.policy.Name, rl.identify(r), r)
+	now := time.Now()
 
-	// Remove old entries (outside the current window)
-	err := rl.redis.ZRemRangeByScore(ctx, key, "0", strconv.FormatInt(windowStart, 10)).Err()
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to remove old rate limit entries")
-		// Allow the request to proceed if we can't communicate with Redis
-		return 0, nil
-	}
+	// Each request needs its own member, or requests in the same
+	// millisecond would be counted once
+	member := strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.FormatUint(requestSeq.Add(1), 10)
 
-	// Count existing requests in the current window
-	count, err := rl.redis.ZCard(ctx, key).Result()
+	result, err := slidingWindowScript.Run(ctx, rl.redis, []string{key},
+		now.UnixMilli(), rl.policy.Interval.Milliseconds(), rl.policy.Limit, member).Int64Slice()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to count rate limit entries")
-		// Allow the request to proceed if we can't communicate with Redis
-		return 0, nil
-	}
-
-	// Check if the rate limit has been exceeded
-	if count >= int64(rl.limit) {
-		return int(count), ErrRateLimitExceeded
-	}
-
-	// Add the current request to the sorted set with the current timestamp as score
-	err = rl.redis.ZAdd(ctx, key, redis.Z{
-		Score:  float64(now),
-		Member: now,
-	}).Err()
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to add rate limit entry")
+		log.Ctx(ctx).Error().Err(err).Str("policy", rl.policy.Name).Msg("Failed to check rate limit in Redis")
+		if rl.policy.FailClosed {
+			return 0, fmt.Errorf("%w: %w", ErrRateLimitUnavailable, err)
+		}
+		// Keep limiting in process if we can't communicate with Redis
+		return rl.fallback.CheckRateLimit(ctx, r)
 	}
 
-	// Set expiration for the key to the rate limit interval + 1 minute
-	err = rl.redis.Expire(ctx, key, rl.interval+time.Minute).Err()
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to set rate limit key expiration")
+	allowed, count := result[0] == 1, int(result[1])
+	if !allowed {
+		return count, ErrRateLimitExceeded
 	}
-
-	return int(count) + 1, nil
+	return count, nil
 }
 
+// requestSeq tells apart requests recorded in the same nanosecond
+var requestSeq atomic.Uint64
+
 // Middleware provides rate limiting middleware
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return rateLimitMiddleware(rl, next)
+}
+
+// rateLimitMiddleware rejects requests the limiter refuses and reports the
+// limit in response headers
+func rateLimitMiddleware(rl Limiter, next http.Handler) http.Handler {
+	limit, interval := rl.Policy().Limit, rl.Policy().Interval
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip rate limiting for OPTIONS requests (for CORS preflight)
 		if r.Method == http.MethodOptions {
@@ -157,10 +232,10 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 		if err != nil {
 			if errors.Is(err, ErrRateLimitExceeded) {
 				// Set rate limit headers
-				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.limit))
+				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
 				w.Header().Set("X-RateLimit-Remaining", "0")
-				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(rl.interval).Unix(), 10))
-				w.Header().Set("Retry-After", strconv.Itoa(int(rl.interval.Seconds())))
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(interval).Unix(), 10))
+				w.Header().Set("Retry-After", strconv.Itoa(int(interval.Seconds())))
 
 				// Return 429 Too Many Requests
 				w.WriteHeader(http.StatusTooManyRequests)
@@ -168,15 +243,22 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 				w.Write([]byte(`{"error":"Rate limit exceeded","status":429}`))
 				return
 			}
+			if errors.Is(err, ErrRateLimitUnavailable) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(interval.Seconds())))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"error":"Rate limiter unavailable","status":503}`))
+				return
+			}
 
 			// For other errors, log and continue
 			log.Error().Err(err).Msg("Rate limiting error")
 		}
 
 		// Set rate limit headers on successful requests too
-		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.limit))
-		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(rl.limit-count))
-		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(rl.interval).Unix(), 10))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(limit-count))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(interval).Unix(), 10))
 
 		next.ServeHTTP(w, r)
 	})
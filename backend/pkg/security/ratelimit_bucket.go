@@ -0,0 +1,107 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenBucketLimiter provides token-bucket rate limiting within a single
+// process. Each key holds up to the policy limit of tokens, refilled evenly
+// over the interval, and every request takes one. It keeps one counter per
+// key rather than a window of timestamps, so it stays cheap when it suddenly
+// takes every request, as the Redis limiter's fallback does during an outage.
+type TokenBucketLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	policy    RateLimitPolicy
+	identify  func(r *http.Request) string
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// tokenBucket is the tokens left for a key as of updated
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewTokenBucketLimiter creates a new in-memory token-bucket limiter. The Redis
+// client in config is ignored.
+func NewTokenBucketLimiter(config RateLimiterConfig) *TokenBucketLimiter {
+	// Set defaults if not provided
+	config = config.withDefaults()
+
+	return &TokenBucketLimiter{
+		buckets:   make(map[string]*tokenBucket),
+		policy:    RateLimitPolicy{Name: config.Name, Limit: config.Limit, Interval: config.Interval, FailClosed: config.FailClosed},
+		identify:  config.Identify,
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Policy returns the limit enforced
+func (rl *TokenBucketLimiter) Policy() RateLimitPolicy {
+	return rl.policy
+}
+
+// CheckRateLimit takes a token for the request. The count returned is the
+// number of tokens in use, which matches the request count of a sliding
+// window when requests arrive in a burst.
+func (rl *TokenBucketLimiter) CheckRateLimit(ctx context.Context, r *http.Request) (int, error) {
+	key := getLimitKey(rl.policy.Name, rl.identify(r), r)
+	limit := float64(rl.policy.Limit)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+
+	// Drop buckets that have refilled, at most once per interval
+	if now.Sub(rl.lastSweep) >= rl.policy.Interval {
+		rl.sweep(now)
+		rl.lastSweep = now
+	}
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limit, updated: now}
+		rl.buckets[key] = bucket
+	}
+	bucket.tokens = rl.refill(bucket, now)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return rl.policy.Limit, ErrRateLimitExceeded
+	}
+	bucket.tokens--
+
+	return rl.policy.Limit - int(bucket.tokens), nil
+}
+
+// Middleware provides rate limiting middleware
+func (rl *TokenBucketLimiter) Middleware(next http.Handler) http.Handler {
+	return rateLimitMiddleware(rl, next)
+}
+
+// refill returns the tokens in a bucket at now, capped at the limit
+func (rl *TokenBucketLimiter) refill(bucket *tokenBucket, now time.Time) float64 {
+	limit := float64(rl.policy.Limit)
+	elapsed := now.Sub(bucket.updated)
+	if elapsed <= 0 {
+		return bucket.tokens
+	}
+	return min(bucket.tokens+limit*elapsed.Seconds()/rl.policy.Interval.Seconds(), limit)
+}
+
+// sweep removes buckets that are full again, which behave the same as
+// missing ones. Callers must hold mu.
+func (rl *TokenBucketLimiter) sweep(now time.Time) {
+	for key, bucket := range rl.buckets {
+		if rl.refill(bucket, now) >= float64(rl.policy.Limit) {
+			delete(rl.buckets, key)
+		}
+	}
+}
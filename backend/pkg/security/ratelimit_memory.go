@@ -0,0 +1,92 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MemoryRateLimiter provides sliding-window rate limiting within a single
+// process. Each key keeps the timestamps of its requests in the current window;
+// keys with no recent requests are evicted periodically.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	windows   map[string][]time.Time
+	policy    RateLimitPolicy
+	identify  func(r *http.Request) string
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimiter creates a new in-memory rate limiter. The Redis client in
+// config is ignored.
+func NewMemoryRateLimiter(config RateLimiterConfig) *MemoryRateLimiter {
+	// Set defaults if not provided
+	config = config.withDefaults()
+
+	return &MemoryRateLimiter{
+		windows:   make(map[string][]time.Time),
+		policy:    RateLimitPolicy{Name: config.Name, Limit: config.Limit, Interval: config.Interval},
+		identify:  config.Identify,
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Policy returns the limit enforced
+func (rl *MemoryRateLimiter) Policy() RateLimitPolicy {
+	return rl.policy
+}
+
+// CheckRateLimit checks if the request is within the rate limit
+func (rl *MemoryRateLimiter) CheckRateLimit(ctx context.Context, r *http.Request) (int, error) {
+	key := getLimitKey(rl.policy.Name, rl.identify(r), r)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	windowStart := now.Add(-rl.policy.Interval)
+
+	// Drop counters for clients that have gone quiet, at most once per interval
+	if now.Sub(rl.lastSweep) >= rl.policy.Interval {
+		rl.sweep(windowStart)
+		rl.lastSweep = now
+	}
+
+	hits := pruneBefore(rl.windows[key], windowStart)
+	if len(hits) >= rl.policy.Limit {
+		rl.windows[key] = hits
+		return len(hits), ErrRateLimitExceeded
+	}
+
+	hits = append(hits, now)
+	rl.windows[key] = hits
+
+	return len(hits), nil
+}
+
+// Middleware provides rate limiting middleware
+func (rl *MemoryRateLimiter) Middleware(next http.Handler) http.Handler {
+	return rateLimitMiddleware(rl, next)
+}
+
+// sweep removes keys whose requests all fall before windowStart. Callers must hold mu.
+func (rl *MemoryRateLimiter) sweep(windowStart time.Time) {
+	for key, hits := range rl.windows {
+		if len(hits) == 0 || !hits[len(hits)-1].After(windowStart) {
+			delete(rl.windows, key)
+		}
+	}
+}
+
+// pruneBefore drops the leading timestamps that are not after windowStart.
+// Timestamps are appended in order, so the remainder are all in the window.
+func pruneBefore(hits []time.Time, windowStart time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(windowStart) {
+		i++
+	}
+	return hits[i:]
+}
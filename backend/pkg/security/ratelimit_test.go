@@ -0,0 +1,240 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimiterSlidingWindow(t *testing.T) {
+	rl := NewMemoryRateLimiter(RateLimiterConfig{Limit: 2, Interval: time.Minute})
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+	ctx := context.Background()
+	req := httptest.NewRequest("POST", "/api/v1/login", nil)
+
+	count, err := rl.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	now = now.Add(30 * time.Second)
+	count, err = rl.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = rl.CheckRateLimit(ctx, req)
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+
+	// Other paths are counted separately
+	_, err = rl.CheckRateLimit(ctx, httptest.NewRequest("POST", "/api/v1/register", nil))
+	require.NoError(t, err)
+
+	// The first request leaves the window
+	now = now.Add(31 * time.Second)
+	count, err = rl.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestMemoryRateLimiterEvictsStaleCounters(t *testing.T) {
+	rl := NewMemoryRateLimiter(RateLimiterConfig{Limit: 5, Interval: time.Minute})
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, path := range []string{"/a", "/b", "/c"} {
+		_, err := rl.CheckRateLimit(ctx, httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+	}
+	assert.Len(t, rl.windows, 3)
+
+	now = now.Add(2 * time.Minute)
+	_, err := rl.CheckRateLimit(ctx, httptest.NewRequest("GET", "/d", nil))
+	require.NoError(t, err)
+	assert.Len(t, rl.windows, 1)
+}
+
+func TestMemoryRateLimiterConcurrent(t *testing.T) {
+	rl := NewMemoryRateLimiter(RateLimiterConfig{Limit: 50, Interval: time.Minute})
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := rl.CheckRateLimit(ctx, httptest.NewRequest("GET", "/", nil)); err == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, allowed)
+}
+
+func TestRateLimiterFallsBackWhenRedisUnavailable(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	rl := NewRateLimiter(RateLimiterConfig{Redis: client, Limit: 1, Interval: time.Minute})
+	mr.Close()
+
+	ctx := context.Background()
+	req := httptest.NewRequest("POST", "/api/v1/login", nil)
+
+	_, err = rl.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+	_, err = rl.CheckRateLimit(ctx, req)
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+}
+
+func TestRateLimiterFailsClosedWhenRedisUnavailable(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	policy := StrictRateLimit
+	policy.FailClosed = true
+	rl := NewRateLimiter(policy.Config(client))
+	mr.Close()
+
+	_, err = rl.CheckRateLimit(context.Background(), httptest.NewRequest("POST", "/api/v1/login", nil))
+	assert.ErrorIs(t, err, ErrRateLimitUnavailable)
+
+	rr := httptest.NewRecorder()
+	rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("request should have been rejected")
+	})).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/login", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+}
+
+func TestTokenBucketLimiterRefills(t *testing.T) {
+	rl := NewTokenBucketLimiter(RateLimiterConfig{Limit: 2, Interval: time.Minute})
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+	ctx := context.Background()
+	req := httptest.NewRequest("POST", "/api/v1/login", nil)
+
+	count, err := rl.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = rl.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = rl.CheckRateLimit(ctx, req)
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+
+	// A token comes back every half interval
+	now = now.Add(30 * time.Second)
+	_, err = rl.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+	_, err = rl.CheckRateLimit(ctx, req)
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+
+	// Full buckets are swept
+	now = now.Add(2 * time.Minute)
+	_, err = rl.CheckRateLimit(ctx, httptest.NewRequest("POST", "/api/v1/register", nil))
+	require.NoError(t, err)
+	assert.Len(t, rl.buckets, 1)
+}
+
+func TestNewLimiterWithoutRedis(t *testing.T) {
+	assert.IsType(t, &MemoryRateLimiter{}, NewLimiter(RateLimiterConfig{}))
+}
+
+func newTestRedisLimiter(t *testing.T, config RateLimiterConfig) (*RateLimiter, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	config.Redis = client
+	return NewRateLimiter(config), mr
+}
+
+func TestRateLimiterCountsEveryRequest(t *testing.T) {
+	// Requests in the same instant used to share one sorted set member
+	rl, _ := newTestRedisLimiter(t, RateLimiterConfig{Limit: 50, Interval: time.Minute})
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := rl.CheckRateLimit(ctx, httptest.NewRequest("GET", "/", nil)); err == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, allowed)
+}
+
+func TestRateLimiterSlidingWindow(t *testing.T) {
+	rl, mr := newTestRedisLimiter(t, StrictRateLimit.Config(nil))
+	ctx := context.Background()
+	req := httptest.NewRequest("POST", "/api/v1/login", nil)
+
+	for i := range StrictRateLimit.Limit {
+		count, err := rl.CheckRateLimit(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, i+1, count)
+	}
+	_, err := rl.CheckRateLimit(ctx, req)
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+
+	// Counters are kept per policy
+	lenient := NewRateLimiter(LenientRateLimit.Config(rl.redis))
+	_, err = lenient.CheckRateLimit(ctx, req)
+	require.NoError(t, err)
+
+	// Keys expire with the window
+	mr.FastForward(StrictRateLimit.Interval)
+	assert.False(t, mr.Exists(getLimitKey(StrictRateLimit.Name, IPIdentity(req), req)))
+}
+
+func TestRateLimiterIdentity(t *testing.T) {
+	identify := func(r *http.Request) string { return "user:" + r.Header.Get("X-User") }
+	rl, _ := newTestRedisLimiter(t, RateLimiterConfig{Limit: 1, Interval: time.Minute, Identify: identify})
+	ctx := context.Background()
+
+	// Users behind the same address are counted separately
+	for _, user := range []string{"a", "b"} {
+		req := httptest.NewRequest("GET", "/api/v1/resumes", nil)
+		req.Header.Set("X-User", user)
+		_, err := rl.CheckRateLimit(ctx, req)
+		require.NoError(t, err, user)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/resumes", nil)
+	req.Header.Set("X-User", "a")
+	_, err := rl.CheckRateLimit(ctx, req)
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+}
+
+func TestRateLimitPolicyDefaults(t *testing.T) {
+	rl := NewMemoryRateLimiter(RateLimiterConfig{})
+	assert.Equal(t, DefaultRateLimitPolicy, rl.Policy())
+}
@@ -1,13 +1,8 @@
 package security
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
 	"time"
 
@@ -28,8 +23,13 @@ var (
 
 // SessionConfig contains configuration options for session management
 type SessionConfig struct {
-	// Key is the secret key used to encrypt session data (must be 32 bytes for AES-256)
+	// Key is the secret key used to encrypt session data (must be 32 bytes for AES-256).
+	// It is ignored when Keyring is set.
 	Key []byte
+	// Keyring encrypts session data with its primary key and decrypts cookies
+	// written under any of its keys, so the key can be rotated without
+	// logging everyone out
+	Keyring *Keyring
 	// CookieSecure determines if the cookie should be sent only over HTTPS
 	CookieSecure bool
 	// CookiePath is the path for which the cookie is valid
@@ -56,14 +56,24 @@ type SessionData struct {
 
 // Session provides secure session management with encrypted cookies
 type Session struct {
-	config SessionConfig
+	config  SessionConfig
+	keyring *Keyring
 }
 
 // NewSession creates a new session manager
 func NewSession(config SessionConfig) (*Session, error) {
-	// AES-256 requires a 32-byte key
-	if len(config.Key) != 32 {
-		return nil, errors.New("session encryption key must be 32 bytes")
+	keyring := config.Keyring
+	if keyring == nil {
+		// AES-256 requires a 32-byte key
+		if len(config.Key) != 32 {
+			return nil, errors.New("session encryption key must be 32 bytes")
+		}
+
+		var err error
+		keyring, err = NewKeyring("default", map[string][]byte{"default": config.Key})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Set defaults if not provided
@@ -78,7 +88,8 @@ func NewSession(config SessionConfig) (*Session, error) {
 	}
 
 	return &Session{
-		config: config,
+		config:  config,
+		keyring: keyring,
 	}, nil
 }
 
@@ -96,7 +107,7 @@ func (s *Session) Create(w http.ResponseWriter, sessionData SessionData) error {
 	}
 
 	// Encrypt the session data
-	encryptedData, err := s.encrypt(jsonData)
+	encryptedData, err := s.keyring.Encrypt(jsonData)
 	if err != nil {
 		return err
 	}
@@ -127,7 +138,7 @@ func (s *Session) Get(r *http.Request) (SessionData, error) {
 	}
 
 	// Decrypt the session data
-	jsonData, err := s.decrypt(cookie.Value)
+	jsonData, err := s.keyring.Decrypt(cookie.Value)
 	if err != nil {
 		return sessionData, ErrSessionDecryption
 	}
@@ -159,69 +170,6 @@ func (s *Session) Clear(w http.ResponseWriter) {
 	})
 }
 
-// encrypt encrypts data using AES-GCM
-func (s *Session) encrypt(plaintext []byte) (string, error) {
-	// Create a new AES cipher block
-	block, err := aes.NewCipher(s.config.Key)
-	if err != nil {
-		return "", err
-	}
-
-	// Create a new GCM cipher mode
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
-	}
-
-	// Create a nonce
-	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
-	}
-
-	// Encrypt the plaintext
-	ciphertext := aesGCM.Seal(nonce, nonce, plaintext, nil)
-
-	// Base64 encode the ciphertext
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
-}
-
-// decrypt decrypts data using AES-GCM
-func (s *Session) decrypt(encryptedData string) ([]byte, error) {
-	// Base64 decode the ciphertext
-	ciphertext, err := base64.StdEncoding.DecodeString(encryptedData)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create a new AES cipher block
-	block, err := aes.NewCipher(s.config.Key)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create a new GCM cipher mode
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, err
-	}
-
-	// Extract the nonce
-	nonceSize := aesGCM.NonceSize()
-	if len(ciphertext) < nonceSize {
-		return nil, errors.New("ciphertext too short")
-	}
-	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-
-	// Decrypt the ciphertext
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	return plaintext, nil
-}
-
 // Middleware provides session middleware that adds session data to the request context
 func (s *Session) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -240,6 +188,13 @@ func (s *Session) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Re-issue cookies written under a retired key with the primary key
+		if cookie, err := r.Cookie(SessionCookieName); err == nil && s.keyring.NeedsRotation(cookie.Value) {
+			if err := s.Create(w, sessionData); err != nil {
+				log.Ctx(r.Context()).Warn().Err(err).Msg("Failed to re-encrypt session")
+			}
+		}
+
 		// Add session data to request context
 		ctx := SetSessionContext(r.Context(), sessionData)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,142 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidMessage is returned when a message is missing required fields
+var ErrInvalidMessage = errors.New("invalid sms message")
+
+// maxBodyLength is the longest body providers deliver, in characters, as
+// up to ten concatenated segments
+const maxBodyLength = 1600
+
+// e164Pattern matches phone numbers in E.164 format
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// Message is a text message to a phone number
+type Message struct {
+	// To is the recipient's number in E.164 format
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+// Validate checks that the message can be delivered
+func (m *Message) Validate() error {
+	if !e164Pattern.MatchString(m.To) || m.Body == "" {
+		return ErrInvalidMessage
+	}
+	if utf8.RuneCountInString(m.Body) > maxBodyLength {
+		return ErrInvalidMessage
+	}
+	return nil
+}
+
+// Sender delivers text messages
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// TwilioConfig contains configuration for the Twilio sender
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// From is the number or messaging service SID messages are sent from
+	From string
+	// BaseURL is the Twilio API, overridden in tests
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// TwilioSender delivers messages through the Twilio Messages API
+type TwilioSender struct {
+	config TwilioConfig
+}
+
+// NewTwilioSender creates a new Twilio sender
+func NewTwilioSender(config TwilioConfig) *TwilioSender {
+	// Set default values if not provided
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.twilio.com"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &TwilioSender{
+		config: config,
+	}
+}
+
+// twilioError is the body of a failed Twilio API response
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Send delivers the message
+func (s *TwilioSender) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	form := url.Values{"To": {msg.To}, "Body": {msg.Body}}
+	// Messaging service SIDs pick the sending number themselves
+	if strings.HasPrefix(s.config.From, "MG") {
+		form.Set("MessagingServiceSid", s.config.From)
+	} else {
+		form.Set("From", s.config.From)
+	}
+
+	endpoint := s.config.BaseURL + "/2010-04-01/Accounts/" + url.PathEscape(s.config.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.config.AccountSID, s.config.AuthToken)
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr twilioError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			return fmt.Errorf("twilio: unexpected status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("twilio: %s (code %d)", apiErr.Message, apiErr.Code)
+	}
+	return nil
+}
+
+// LogSender logs messages instead of delivering them, for development
+type LogSender struct{}
+
+// NewLogSender creates a new log sender
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+// Send logs the message
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	log.Info().Str("to", msg.To).Str("body", msg.Body).Msg("SMS (not sent, Twilio not configured)")
+	return nil
+}
@@ -0,0 +1,66 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageValidate(t *testing.T) {
+	valid := Message{To: "+14155552671", Body: "Your code is 123456"}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name string
+		msg  Message
+	}{
+		{"no recipient", Message{Body: "Hello"}},
+		{"not E.164", Message{To: "415-555-2671", Body: "Hello"}},
+		{"no body", Message{To: "+14155552671"}},
+		{"body too long", Message{To: "+14155552671", Body: strings.Repeat("a", maxBodyLength+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.msg.Validate(), ErrInvalidMessage)
+		})
+	}
+}
+
+func TestTwilioSenderSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", user)
+		assert.Equal(t, "secret", pass)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "+14155552671", r.PostForm.Get("To"))
+		assert.Equal(t, "+15005550006", r.PostForm.Get("From"))
+		assert.Equal(t, "Your code is 123456", r.PostForm.Get("Body"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(TwilioConfig{AccountSID: "AC123", AuthToken: "secret", From: "+15005550006", BaseURL: server.URL})
+	err := sender.Send(context.Background(), &Message{To: "+14155552671", Body: "Your code is 123456"})
+	assert.NoError(t, err)
+}
+
+func TestTwilioSenderSendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number."}`))
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(TwilioConfig{AccountSID: "AC123", AuthToken: "secret", From: "MG123", BaseURL: server.URL})
+	err := sender.Send(context.Background(), &Message{To: "+14155552671", Body: "Hello"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "21211")
+}
@@ -0,0 +1,319 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config contains configuration for the S3 storage. Any S3-compatible
+// store works, such as MinIO or Cloudflare R2.
+type S3Config struct {
+	// Endpoint is the store's URL, defaulting to AWS S3 in Region
+	Endpoint string
+	Region   string
+	Bucket   string
+	// AccessKeyID and SecretAccessKey sign requests
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle puts the bucket in the path rather than the host name, as
+	// most self-hosted stores need
+	PathStyle  bool
+	HTTPClient *http.Client
+}
+
+// S3 stores files in an S3 bucket, signing requests with AWS Signature
+// Version 4
+type S3 struct {
+	config S3Config
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// NewS3 creates a new S3 storage
+func NewS3(config S3Config) (*S3, error) {
+	// Set default values if not provided
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3: bucket is required")
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", config.Endpoint)
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+
+	return &S3{
+		config: config,
+		now:    time.Now,
+	}, nil
+}
+
+// s3Error is the body of a failed S3 API response
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// Put stores a file. The body is read into memory to sign it.
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get returns a stored file
+func (s *S3) Get(ctx context.Context, key string) (*Object, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Object{Body: resp.Body, ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}, nil
+}
+
+// Delete removes a file. S3 reports success for missing files too.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// maxPresignExpiry is the longest S3 accepts for presigned URLs
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// SignedURL returns a presigned URL of a file. S3 caps the expiry at seven
+// days, which longer ones are shortened to.
+func (s *S3) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return "", err
+	}
+	s.presign(req.URL, min(max(expiry, time.Second), maxPresignExpiry), s.now())
+	return req.URL.String(), nil
+}
+
+// newRequest creates a request for the object stored under key
+func (s *S3) newRequest(ctx context.Context, method, key string, data []byte) (*http.Request, error) {
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	objectPath := "/" + key
+	if s.config.PathStyle {
+		objectPath = "/" + s.config.Bucket + objectPath
+	} else {
+		endpoint.Host = s.config.Bucket + "." + endpoint.Host
+	}
+	endpoint.Path = strings.TrimRight(endpoint.Path, "/") + objectPath
+	endpoint.RawPath = uriEncode(endpoint.Path, false)
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	return http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+}
+
+// do signs and sends a request, turning failed responses into errors
+func (s *S3) do(req *http.Request, payload []byte) (*http.Response, error) {
+	s.sign(req, payload, s.now())
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	var apiErr s3Error
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr); err != nil || apiErr.Code == "" {
+		return nil, fmt.Errorf("s3: unexpected status %d", resp.StatusCode)
+	}
+	return nil, fmt.Errorf("s3: %s (%s)", apiErr.Message, apiErr.Code)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to the request,
+// covering the host and every header already set on it
+func (s *S3) sign(req *http.Request, payload []byte, at time.Time) {
+	at = at.UTC()
+	amzDate := at.Format("20060102T150405Z")
+	date := at.Format("20060102")
+	payloadHash := sha256.Sum256(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.config.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// presign adds the query parameters of a presigned GET request to u, which
+// only signs the host header and leaves the payload unsigned
+func (s *S3) presign(u *url.URL, expiry time.Duration, at time.Time) {
+	at = at.UTC()
+	amzDate := at.Format("20060102T150405Z")
+	date := at.Format("20060102")
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.config.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		uriEncode(u.Path, false),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	query.Set("X-Amz-Signature", hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign)))
+	u.RawQuery = canonicalQuery(query)
+}
+
+// signingKey derives the key requests made on date are signed with
+func (s *S3) signingKey(date string) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	return hmacSHA256(key, "aws4_request")
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters sorted by name, as signed
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and
+// slashes too unless encodeSlash is false, as signing requires
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,222 @@
+// Package storage keeps files, such as uploaded photos, data exports and
+// import rosters, on local disk or in an S3-compatible object store.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Storage errors
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Object is a stored file
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// Storage stores files under keys such as "photos/abc.jpg". Keys are
+// slash-separated relative paths without "." or ".." segments.
+type Storage interface {
+	// Put stores a file, replacing any stored under the key
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	// Get returns a stored file, or ErrNotFound. The caller closes its body.
+	Get(ctx context.Context, key string) (*Object, error)
+	// Delete removes a file; removing a missing file is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL the file stored under key can be downloaded
+	// from without credentials until expiry
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// ValidKey reports whether key can name a stored file
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// Local stores files in a directory on local disk. The content type of a
+// file is told from its extension.
+type Local struct {
+	dir    string
+	signer *URLSigner
+}
+
+// NewLocal creates a local storage in dir, creating it if needed
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &Local{dir: dir}, nil
+}
+
+// Put stores a file. It is written to a temporary file first, so readers
+// never see it half written.
+func (s *Local) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	name := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Get returns a stored file
+func (s *Local) Get(ctx context.Context, key string) (*Object, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Object{Body: f, ContentType: contentType, Size: info.Size()}, nil
+}
+
+// Delete removes a file
+func (s *Local) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// SetURLSigner signs the URLs of files with signer; the API serves them.
+// Without one SignedURL returns ErrSignedURLUnsupported.
+func (s *Local) SetURLSigner(signer *URLSigner) {
+	s.signer = signer
+}
+
+// SignedURL returns a signed URL of a file, which need not exist yet
+func (s *Local) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if s.signer == nil {
+		return "", ErrSignedURLUnsupported
+	}
+	return s.signer.Sign(key, expiry)
+}
+
+// path is the file a key is stored in
+func (s *Local) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+// Memory keeps files in memory, for tests and development
+type Memory struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+	signer  *URLSigner
+}
+
+// memoryObject is a file kept by Memory
+type memoryObject struct {
+	data        []byte
+	contentType string
+}
+
+// NewMemory creates a new memory storage
+func NewMemory() *Memory {
+	return &Memory{objects: map[string]memoryObject{}}
+}
+
+// Put stores a file
+func (s *Memory) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{data: data, contentType: contentType}
+	return nil
+}
+
+// Get returns a stored file
+func (s *Memory) Get(ctx context.Context, key string) (*Object, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &Object{Body: io.NopCloser(bytes.NewReader(object.data)), ContentType: object.contentType, Size: int64(len(object.data))}, nil
+}
+
+// Delete removes a file
+func (s *Memory) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+// SetURLSigner signs the URLs of files with signer
+func (s *Memory) SetURLSigner(signer *URLSigner) {
+	s.signer = signer
+}
+
+// SignedURL returns a signed URL of a file
+func (s *Memory) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if s.signer == nil {
+		return "", ErrSignedURLUnsupported
+	}
+	return s.signer.Sign(key, expiry)
+}
@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentRedis records a span for every command and pipeline the client
+// runs, with address naming the server or nodes. Spans name the command but
+// leave out its arguments, which hold keys and values such as session tokens.
+func InstrumentRedis(client redis.UniversalClient, address string) {
+	client.AddHook(redisTracingHook{
+		tracer: tracer,
+		attrs: []attribute.KeyValue{
+			semconv.DBSystemRedis,
+			semconv.ServerAddress(address),
+		},
+	})
+}
+
+// redisTracingHook starts a client span around each Redis call
+type redisTracingHook struct {
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+func (h redisTracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h redisTracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		name := strings.ToUpper(cmd.Name())
+		ctx, span := h.tracer.Start(ctx, "redis "+name, trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(h.attrs...), trace.WithAttributes(semconv.DBOperationName(name)))
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+func (h redisTracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := h.tracer.Start(ctx, "redis pipeline", trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(h.attrs...), trace.WithAttributes(attribute.Int("db.redis.num_cmd", len(cmds))))
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+// recordRedisError marks the span failed, except for redis.Nil, which only
+// means a key was missing
+func recordRedisError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,136 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/lordaris/resume_generator/pkg/buildinfo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultServiceName names the service in traces unless configured
+const DefaultServiceName = "resume_generator"
+
+// Config contains configuration options for tracing
+type Config struct {
+	// Endpoint is the URL of the OTLP/HTTP collector, such as
+	// "http://localhost:4318". Tracing is disabled when it is empty.
+	Endpoint string
+	// ServiceName names the service in traces
+	ServiceName string
+	// SampleRatio is the fraction of new traces recorded, from 0 to 1.
+	// Requests that arrive with a sampled trace are always recorded.
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider, exporting spans over OTLP, and
+// returns a function that flushes pending spans on shutdown. Without an
+// endpoint the no-op provider stays in place, so instrumented code costs
+// next to nothing.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Set defaults if not provided
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultServiceName
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(config.Endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(buildinfo.Get().Version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// tracer starts the spans this package records
+var tracer = otel.Tracer("github.com/lordaris/resume_generator/pkg/telemetry")
+
+// Middleware records a server span for each request, continuing the trace of
+// the caller when the request carries one. The span is named after the method
+// until NameSpansByRoute renames it after the matched route.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			))
+		defer span.End()
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(sw.status))
+		if sw.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(sw.status))
+		}
+	})
+}
+
+// statusWriter records the status code of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap returns the wrapped writer so http.ResponseController can flush it
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NameSpansByRoute renames the request span after the route pattern the mux
+// matched, such as "GET /api/v1/resumes/{id}", so requests to the same route
+// group together. It must wrap the mux directly, which records the pattern on
+// the request it is handed.
+func NameSpansByRoute(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+
+		if r.Pattern == "" {
+			return
+		}
+		// The route attribute holds the path template without the method
+		route := r.Pattern
+		if _, path, ok := strings.Cut(route, " "); ok {
+			route = path
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Pattern)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	})
+}
@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recorder collects the spans ended by the tests. The global provider can only
+// be installed once, so tests look at the spans ended since they started.
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+// endedSince returns the spans ended after the first n
+func endedSince(n int) []sdktrace.ReadOnlySpan {
+	return recorder.Ended()[n:]
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddlewareNamesSpansByRoute(t *testing.T) {
+	start := len(recorder.Ended())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/resumes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	// Middleware that copies the request, like the repo's own, sits between
+	// the server span and the mux
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NameSpansByRoute(mux).ServeHTTP(w, r.WithContext(r.Context()))
+	}))
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resumes/42", nil)
+	req.Header.Set("traceparent", parent)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := endedSince(start)
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /api/v1/resumes/{id}", span.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "/api/v1/resumes/{id}", spanAttribute(span, "http.route").AsString())
+	assert.EqualValues(t, http.StatusServiceUnavailable, spanAttribute(span, "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
+
+func TestInstrumentRedis(t *testing.T) {
+	start := len(recorder.Ended())
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	InstrumentRedis(client, mr.Addr())
+
+	ctx := context.Background()
+	require.NoError(t, client.Set(ctx, "session:secret", "value", 0).Err())
+	assert.ErrorIs(t, client.Get(ctx, "missing").Err(), redis.Nil)
+
+	spans := endedSince(start)
+	require.Len(t, spans, 2)
+	assert.Equal(t, "redis SET", spans[0].Name())
+	assert.Equal(t, "redis GET", spans[1].Name())
+	assert.Equal(t, mr.Addr(), spanAttribute(spans[0], "server.address").AsString())
+	// A missing key is not a failure
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	// Keys and values stay out of the spans
+	for _, attr := range spans[0].Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "session:secret")
+	}
+}
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}